@@ -13,6 +13,8 @@ func main() {
 	}
 
 	rootCmd.AddCommand(subcommands.CmdHealthCheck())
+	rootCmd.AddCommand(subcommands.CmdSchedule())
+	rootCmd.AddCommand(subcommands.CmdDigest())
 
 	err := rootCmd.Execute()
 	if err != nil {
@@ -0,0 +1,92 @@
+package subcommands
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/eser/acik.io/pkg/api/adapters/appcontext"
+	adapterdigest "github.com/eser/acik.io/pkg/api/adapters/digest"
+	"github.com/eser/acik.io/pkg/api/adapters/storage"
+	"github.com/eser/acik.io/pkg/api/business/digest"
+	"github.com/eser/acik.io/pkg/api/business/questions"
+	"github.com/spf13/cobra"
+)
+
+var ErrUserRequired = errors.New("--user is required")
+
+func CmdDigest() *cobra.Command {
+	digestCmd := &cobra.Command{ //nolint:exhaustruct
+		Use:   "digest",
+		Short: "Inspect the weekly digest",
+		Long:  `Inspect the weekly digest mailed to the users`,
+	}
+
+	var (
+		userId string
+		period string
+		html   bool
+	)
+
+	previewCmd := &cobra.Command{ //nolint:exhaustruct
+		Use:   "preview",
+		Short: "Preview the digest of a user",
+		Long:  `Render the digest of a user to the standard output without sending it`,
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if userId == "" {
+				return ErrUserRequired
+			}
+
+			return execDigestPreview(cmd.Context(), userId, period, html)
+		},
+	}
+
+	previewCmd.Flags().StringVar(&userId, "user", "", "id of the user to preview the digest of")
+	previewCmd.Flags().StringVar(&period, "period", "", "ISO week to preview, such as 2026-W42 (default: last week)")
+	previewCmd.Flags().BoolVar(&html, "html", false, "print the HTML part instead of the plain text one")
+
+	digestCmd.AddCommand(previewCmd)
+
+	return digestCmd
+}
+
+func execDigestPreview(ctx context.Context, userId string, period string, html bool) error {
+	appContext, err := appcontext.NewAppContext(ctx)
+	if err != nil {
+		return err //nolint:wrapcheck
+	}
+
+	store, err := storage.NewFromDefault(appContext.Data)
+	if err != nil {
+		return err //nolint:wrapcheck
+	}
+
+	renderer, err := adapterdigest.NewTemplateRenderer()
+	if err != nil {
+		return err //nolint:wrapcheck
+	}
+
+	service := digest.NewService(&appContext.Config.Digest, store, questions.NewService(store, nil, nil, nil, nil), renderer, nil, nil)
+
+	preview, rendered, err := service.Preview(ctx, userId, period)
+	if err != nil {
+		return err //nolint:wrapcheck
+	}
+
+	if html {
+		fmt.Print(rendered.Html) //nolint:forbidigo
+
+		return nil
+	}
+
+	fmt.Printf("Subject: %s\n", rendered.Subject) //nolint:forbidigo
+
+	if preview.IsEmpty() {
+		fmt.Println("Note: nothing new in the followed profiles, this digest would not be sent.") //nolint:forbidigo
+	}
+
+	fmt.Printf("\n%s", rendered.Text) //nolint:forbidigo
+
+	return nil
+}
@@ -0,0 +1,122 @@
+package subcommands
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/eser/acik.io/pkg/api/adapters/appcontext"
+	"github.com/eser/acik.io/pkg/api/adapters/jobs"
+	"github.com/spf13/cobra"
+)
+
+func CmdSchedule() *cobra.Command {
+	scheduleCmd := &cobra.Command{ //nolint:exhaustruct
+		Use:   "schedule",
+		Short: "Inspect and trigger the scheduled tasks",
+		Long:  `Inspect and trigger the scheduled tasks run by the background worker`,
+	}
+
+	scheduleCmd.AddCommand(
+		&cobra.Command{ //nolint:exhaustruct
+			Use:   "list",
+			Short: "List the scheduled tasks",
+			Long:  `List the scheduled tasks with their cron expressions, next runs and last runs`,
+			Args:  cobra.NoArgs,
+			RunE: func(cmd *cobra.Command, args []string) error {
+				return execScheduleList(cmd.Context())
+			},
+		},
+		&cobra.Command{ //nolint:exhaustruct
+			Use:   "run-now <task>",
+			Short: "Run a scheduled task right away",
+			Long:  `Run a scheduled task right away in this process, regardless of its schedule`,
+			Args:  cobra.ExactArgs(1),
+			RunE: func(cmd *cobra.Command, args []string) error {
+				return execScheduleRunNow(cmd.Context(), args[0])
+			},
+		},
+	)
+
+	return scheduleCmd
+}
+
+func execScheduleList(ctx context.Context) error {
+	appContext, err := appcontext.NewAppContext(ctx)
+	if err != nil {
+		return err //nolint:wrapcheck
+	}
+
+	scheduler, err := jobs.NewScheduler(appContext)
+	if err != nil {
+		return err //nolint:wrapcheck
+	}
+
+	tasks, err := scheduler.Tasks(ctx)
+	if err != nil {
+		return err //nolint:wrapcheck
+	}
+
+	writer := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0) //nolint:mnd
+
+	fmt.Fprintln(writer, "TASK\tCRON\tNEXT RUN\tLAST RUN\tSTATUS\tERROR") //nolint:errcheck
+
+	for _, task := range tasks {
+		expression, nextRun, lastRun, status, lastError := "-", "-", "-", "-", ""
+
+		if task.Expression != "" {
+			expression = task.Expression
+		}
+
+		if task.NextRunAt != nil {
+			nextRun = task.NextRunAt.Format(time.RFC3339)
+		}
+
+		if task.LastRun != nil {
+			lastRun = task.LastRun.StartedAt.Format(time.RFC3339)
+			status = task.LastRun.Status
+			lastError = task.LastRun.Error.String
+		}
+
+		fmt.Fprintf( //nolint:errcheck
+			writer,
+			"%s\t%s\t%s\t%s\t%s\t%s\n",
+			task.Name,
+			expression,
+			nextRun,
+			lastRun,
+			status,
+			lastError,
+		)
+	}
+
+	return writer.Flush() //nolint:wrapcheck
+}
+
+func execScheduleRunNow(ctx context.Context, task string) error {
+	appContext, err := appcontext.NewAppContext(ctx)
+	if err != nil {
+		return err //nolint:wrapcheck
+	}
+
+	scheduler, err := jobs.NewScheduler(appContext)
+	if err != nil {
+		return err //nolint:wrapcheck
+	}
+
+	run, err := scheduler.RunNow(ctx, task)
+	if err != nil {
+		return err //nolint:wrapcheck
+	}
+
+	fmt.Printf( //nolint:forbidigo
+		"Task %s finished (run: %s, took: %s)\n",
+		task,
+		run.Id,
+		run.FinishedAt.Time.Sub(run.StartedAt).Round(time.Millisecond),
+	)
+
+	return nil
+}
@@ -0,0 +1,45 @@
+package main
+
+import (
+	"context"
+	"log/slog"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/eser/acik.io/pkg/api/adapters/appcontext"
+	"github.com/eser/acik.io/pkg/api/adapters/queue"
+	"github.com/eser/acik.io/pkg/api/adapters/storage"
+	"github.com/eser/acik.io/pkg/api/business/outbox"
+)
+
+func main() {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	appContext, err := appcontext.NewAppContext(ctx)
+	if err != nil {
+		panic(err)
+	}
+
+	store, err := storage.NewFromDefault(appContext.Data)
+	if err != nil {
+		panic(err)
+	}
+
+	service := outbox.NewService(&appContext.Config.Outbox, store, queue.NewFromDefault(appContext.Queue))
+
+	appContext.Logger.InfoContext(
+		ctx,
+		"Starting outbox relay",
+		slog.String("name", appContext.Config.AppName),
+		slog.String("environment", appContext.Config.AppEnv),
+		slog.Duration("interval", appContext.Config.Outbox.RelayInterval),
+	)
+
+	service.Relay(ctx, func(err error) {
+		appContext.Logger.ErrorContext(ctx, "Outbox relay failed", slog.Any("error", err))
+	})
+
+	appContext.Logger.InfoContext(ctx, "Outbox relay stopped")
+}
@@ -0,0 +1,37 @@
+package main
+
+import (
+	"context"
+	"log/slog"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/eser/acik.io/pkg/api/adapters/appcontext"
+	"github.com/eser/acik.io/pkg/api/adapters/jobs"
+)
+
+func main() {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	appContext, err := appcontext.NewAppContext(ctx)
+	if err != nil {
+		panic(err)
+	}
+
+	appContext.Logger.InfoContext(
+		ctx,
+		"Starting worker",
+		slog.String("name", appContext.Config.AppName),
+		slog.String("environment", appContext.Config.AppEnv),
+		slog.String("metrics", appContext.Config.Work.MetricsAddr),
+	)
+
+	err = jobs.Run(ctx, appContext)
+	if err != nil {
+		panic(err)
+	}
+
+	appContext.Logger.InfoContext(ctx, "Worker stopped")
+}
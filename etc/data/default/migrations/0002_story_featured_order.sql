@@ -0,0 +1,9 @@
+-- +goose Up
+ALTER TABLE "story" ADD COLUMN IF NOT EXISTS "featured_order" INTEGER DEFAULT 0 NOT NULL;
+
+CREATE INDEX IF NOT EXISTS "story_featured_order_index" ON "story" ("featured_order") WHERE "is_featured" = TRUE AND "deleted_at" IS NULL;
+
+-- +goose Down
+DROP INDEX IF EXISTS "story_featured_order_index";
+
+ALTER TABLE "story" DROP COLUMN IF EXISTS "featured_order";
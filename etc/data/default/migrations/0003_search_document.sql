@@ -0,0 +1,118 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS "search_document" (
+  "entity_type" TEXT NOT NULL,
+  "entity_id" CHAR(26) NOT NULL,
+  "slug" TEXT NOT NULL,
+  "title" TEXT NOT NULL,
+  "summary" TEXT NOT NULL,
+  "content" TEXT NOT NULL,
+  "is_visible" BOOLEAN NOT NULL,
+  "visible_from" TIMESTAMP WITH TIME ZONE DEFAULT NOW() NOT NULL,
+  "updated_at" TIMESTAMP WITH TIME ZONE DEFAULT NOW() NOT NULL,
+  "search_vector_turkish" TSVECTOR GENERATED ALWAYS AS (
+    setweight(to_tsvector('turkish'::REGCONFIG, "title"), 'A') ||
+    setweight(to_tsvector('turkish'::REGCONFIG, "summary"), 'B') ||
+    setweight(to_tsvector('turkish'::REGCONFIG, "content"), 'C')
+  ) STORED,
+  "search_vector_english" TSVECTOR GENERATED ALWAYS AS (
+    setweight(to_tsvector('english'::REGCONFIG, "title"), 'A') ||
+    setweight(to_tsvector('english'::REGCONFIG, "summary"), 'B') ||
+    setweight(to_tsvector('english'::REGCONFIG, "content"), 'C')
+  ) STORED,
+  CONSTRAINT "search_document_pkey" PRIMARY KEY ("entity_type", "entity_id")
+);
+
+CREATE INDEX IF NOT EXISTS "search_document_search_vector_turkish_index" ON "search_document" USING GIN ("search_vector_turkish");
+
+CREATE INDEX IF NOT EXISTS "search_document_search_vector_english_index" ON "search_document" USING GIN ("search_vector_english");
+
+-- +goose StatementBegin
+CREATE OR REPLACE FUNCTION "search_document_upsert"(
+  p_entity_type TEXT,
+  p_entity_id CHAR(26),
+  p_slug TEXT,
+  p_title TEXT,
+  p_summary TEXT,
+  p_content TEXT,
+  p_is_visible BOOLEAN,
+  p_visible_from TIMESTAMP WITH TIME ZONE
+) RETURNS VOID AS $$
+BEGIN
+  INSERT INTO "search_document" ("entity_type", "entity_id", "slug", "title", "summary", "content", "is_visible", "visible_from", "updated_at")
+  VALUES (p_entity_type, p_entity_id, p_slug, p_title, COALESCE(p_summary, ''), COALESCE(p_content, ''), p_is_visible, COALESCE(p_visible_from, NOW()), NOW())
+  ON CONFLICT ("entity_type", "entity_id") DO UPDATE
+  SET "slug" = EXCLUDED."slug",
+    "title" = EXCLUDED."title",
+    "summary" = EXCLUDED."summary",
+    "content" = EXCLUDED."content",
+    "is_visible" = EXCLUDED."is_visible",
+    "visible_from" = EXCLUDED."visible_from",
+    "updated_at" = EXCLUDED."updated_at";
+END;
+$$ LANGUAGE plpgsql;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE OR REPLACE FUNCTION "search_document_sync"() RETURNS TRIGGER AS $$
+BEGIN
+  IF TG_OP = 'DELETE' THEN
+    DELETE FROM "search_document" WHERE "entity_type" = TG_ARGV[0] AND "entity_id" = OLD."id";
+
+    RETURN OLD;
+  END IF;
+
+  CASE TG_ARGV[0]
+    WHEN 'profile' THEN
+      PERFORM "search_document_upsert"('profile', NEW."id", NEW."slug", NEW."title", NEW."description", '',
+        NEW."deleted_at" IS NULL, NEW."created_at");
+    WHEN 'story' THEN
+      PERFORM "search_document_upsert"('story', NEW."id", NEW."slug", NEW."title", COALESCE(NULLIF(NEW."summary", ''), NEW."description"), NEW."content",
+        NEW."status" = 'published' AND NEW."deleted_at" IS NULL, NEW."published_at");
+    WHEN 'event' THEN
+      PERFORM "search_document_upsert"('event', NEW."id", NEW."slug", NEW."title", NEW."description", '',
+        NEW."status" = 'published' AND NEW."deleted_at" IS NULL, NEW."published_at");
+    WHEN 'question' THEN
+      PERFORM "search_document_upsert"('question', NEW."id", NEW."id", NEW."content", '', NEW."answer_content",
+        NOT NEW."is_hidden" AND NEW."deleted_at" IS NULL, NEW."created_at");
+  END CASE;
+
+  RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+-- +goose StatementEnd
+
+CREATE TRIGGER "profile_search_document_sync" AFTER INSERT OR UPDATE OR DELETE ON "profile"
+  FOR EACH ROW EXECUTE FUNCTION "search_document_sync"('profile');
+
+CREATE TRIGGER "story_search_document_sync" AFTER INSERT OR UPDATE OR DELETE ON "story"
+  FOR EACH ROW EXECUTE FUNCTION "search_document_sync"('story');
+
+CREATE TRIGGER "event_search_document_sync" AFTER INSERT OR UPDATE OR DELETE ON "event"
+  FOR EACH ROW EXECUTE FUNCTION "search_document_sync"('event');
+
+CREATE TRIGGER "question_search_document_sync" AFTER INSERT OR UPDATE OR DELETE ON "question"
+  FOR EACH ROW EXECUTE FUNCTION "search_document_sync"('question');
+
+-- backfill the documents of the existing records by touching them once.
+UPDATE "profile" SET "id" = "id";
+
+UPDATE "story" SET "id" = "id";
+
+UPDATE "event" SET "id" = "id";
+
+UPDATE "question" SET "id" = "id";
+
+-- +goose Down
+DROP TRIGGER IF EXISTS "question_search_document_sync" ON "question";
+
+DROP TRIGGER IF EXISTS "event_search_document_sync" ON "event";
+
+DROP TRIGGER IF EXISTS "story_search_document_sync" ON "story";
+
+DROP TRIGGER IF EXISTS "profile_search_document_sync" ON "profile";
+
+DROP FUNCTION IF EXISTS "search_document_sync"();
+
+DROP FUNCTION IF EXISTS "search_document_upsert"(TEXT, CHAR(26), TEXT, TEXT, TEXT, TEXT, BOOLEAN, TIMESTAMP WITH TIME ZONE);
+
+DROP TABLE IF EXISTS "search_document";
@@ -0,0 +1,21 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS "outbox" (
+  "id" CHAR(26) NOT NULL PRIMARY KEY,
+  "aggregate_type" TEXT NOT NULL,
+  "aggregate_id" CHAR(26) NOT NULL,
+  "event_type" TEXT NOT NULL,
+  "payload" JSONB NOT NULL,
+  "status" TEXT DEFAULT 'pending'::TEXT NOT NULL,
+  "attempts" INTEGER DEFAULT 0 NOT NULL,
+  "next_attempt_at" TIMESTAMP WITH TIME ZONE DEFAULT NOW() NOT NULL,
+  "last_error" TEXT,
+  "occurred_at" TIMESTAMP WITH TIME ZONE DEFAULT NOW() NOT NULL,
+  "delivered_at" TIMESTAMP WITH TIME ZONE
+);
+
+CREATE INDEX IF NOT EXISTS "outbox_pending_next_attempt_at_index" ON "outbox" ("next_attempt_at") WHERE "status" = 'pending';
+
+CREATE INDEX IF NOT EXISTS "outbox_pending_aggregate_index" ON "outbox" ("aggregate_type", "aggregate_id", "id") WHERE "status" = 'pending';
+
+-- +goose Down
+DROP TABLE IF EXISTS "outbox";
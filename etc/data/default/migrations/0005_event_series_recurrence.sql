@@ -0,0 +1,9 @@
+-- +goose Up
+ALTER TABLE "event_series" ADD COLUMN IF NOT EXISTS "recurrence_interval_days" INTEGER;
+
+ALTER TABLE "event_series" ADD COLUMN IF NOT EXISTS "recurrence_until" TIMESTAMP WITH TIME ZONE;
+
+-- +goose Down
+ALTER TABLE "event_series" DROP COLUMN IF EXISTS "recurrence_until";
+
+ALTER TABLE "event_series" DROP COLUMN IF EXISTS "recurrence_interval_days";
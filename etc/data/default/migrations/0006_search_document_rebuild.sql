@@ -0,0 +1,18 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE OR REPLACE FUNCTION "search_document_rebuild"(p_entity_type TEXT) RETURNS VOID AS $$
+BEGIN
+  IF p_entity_type NOT IN ('profile', 'story', 'event', 'question') THEN
+    RAISE EXCEPTION 'unknown search entity type: %', p_entity_type;
+  END IF;
+
+  DELETE FROM "search_document" WHERE "entity_type" = p_entity_type;
+
+  -- the sync triggers recreate the document of every touched row.
+  EXECUTE format('UPDATE %I SET "id" = "id"', p_entity_type);
+END;
+$$ LANGUAGE plpgsql;
+-- +goose StatementEnd
+
+-- +goose Down
+DROP FUNCTION IF EXISTS "search_document_rebuild"(TEXT);
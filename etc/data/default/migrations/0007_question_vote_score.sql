@@ -0,0 +1,19 @@
+-- +goose Up
+ALTER TABLE "question" ADD COLUMN IF NOT EXISTS "vote_score" INTEGER DEFAULT 0 NOT NULL;
+
+UPDATE "question" q
+SET "vote_score" = s."score"
+FROM (
+  SELECT "question_id", SUM("score")::INTEGER AS "score"
+  FROM "question_vote"
+  GROUP BY "question_id"
+) s
+WHERE s."question_id" = q."id";
+
+CREATE INDEX IF NOT EXISTS "question_unanswered_vote_score_index" ON "question" ("vote_score" DESC, "created_at" DESC)
+  WHERE "answered_at" IS NULL AND "is_hidden" = FALSE AND "deleted_at" IS NULL;
+
+-- +goose Down
+DROP INDEX IF EXISTS "question_unanswered_vote_score_index";
+
+ALTER TABLE "question" DROP COLUMN IF EXISTS "vote_score";
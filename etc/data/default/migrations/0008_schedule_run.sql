@@ -0,0 +1,18 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS "schedule_run" (
+  "id" CHAR(26) NOT NULL PRIMARY KEY,
+  "task" TEXT NOT NULL,
+  "trigger" TEXT NOT NULL,
+  "instance" TEXT NOT NULL,
+  "status" TEXT DEFAULT 'running'::TEXT NOT NULL,
+  "scheduled_at" TIMESTAMP WITH TIME ZONE NOT NULL,
+  "started_at" TIMESTAMP WITH TIME ZONE DEFAULT NOW() NOT NULL,
+  "finished_at" TIMESTAMP WITH TIME ZONE,
+  "error" TEXT,
+  CONSTRAINT "schedule_run_task_scheduled_at_unique" UNIQUE ("task", "scheduled_at")
+);
+
+CREATE INDEX IF NOT EXISTS "schedule_run_task_started_at_index" ON "schedule_run" ("task", "started_at" DESC);
+
+-- +goose Down
+DROP TABLE IF EXISTS "schedule_run";
@@ -0,0 +1,48 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS "webhook_endpoint" (
+  "id" CHAR(26) NOT NULL PRIMARY KEY,
+  "profile_id" CHAR(26) NOT NULL,
+  "url" TEXT NOT NULL,
+  "secret" TEXT NOT NULL,
+  "event_types" TEXT NOT NULL,
+  "description" TEXT DEFAULT ''::TEXT NOT NULL,
+  "is_active" BOOLEAN DEFAULT TRUE NOT NULL,
+  "consecutive_failures" INTEGER DEFAULT 0 NOT NULL,
+  "failing_since" TIMESTAMP WITH TIME ZONE,
+  "disabled_at" TIMESTAMP WITH TIME ZONE,
+  "disabled_reason" TEXT,
+  "created_at" TIMESTAMP WITH TIME ZONE DEFAULT NOW() NOT NULL,
+  "updated_at" TIMESTAMP WITH TIME ZONE,
+  "deleted_at" TIMESTAMP WITH TIME ZONE
+);
+
+CREATE INDEX IF NOT EXISTS "webhook_endpoint_profile_id_index" ON "webhook_endpoint" ("profile_id") WHERE "deleted_at" IS NULL;
+
+CREATE TABLE IF NOT EXISTS "webhook_delivery" (
+  "id" CHAR(26) NOT NULL PRIMARY KEY,
+  "endpoint_id" CHAR(26) NOT NULL,
+  "event_id" CHAR(26) NOT NULL,
+  "event_type" TEXT NOT NULL,
+  "payload" JSONB NOT NULL,
+  "replay_of" CHAR(26),
+  "status" TEXT DEFAULT 'pending'::TEXT NOT NULL,
+  "attempts" INTEGER DEFAULT 0 NOT NULL,
+  "next_attempt_at" TIMESTAMP WITH TIME ZONE DEFAULT NOW() NOT NULL,
+  "response_status" INTEGER,
+  "response_body" TEXT,
+  "error" TEXT,
+  "duration_ms" INTEGER,
+  "created_at" TIMESTAMP WITH TIME ZONE DEFAULT NOW() NOT NULL,
+  "delivered_at" TIMESTAMP WITH TIME ZONE
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS "webhook_delivery_endpoint_id_event_id_unique" ON "webhook_delivery" ("endpoint_id", "event_id") WHERE "replay_of" IS NULL;
+
+CREATE INDEX IF NOT EXISTS "webhook_delivery_pending_next_attempt_at_index" ON "webhook_delivery" ("next_attempt_at") WHERE "status" = 'pending';
+
+CREATE INDEX IF NOT EXISTS "webhook_delivery_endpoint_id_created_at_index" ON "webhook_delivery" ("endpoint_id", "created_at" DESC);
+
+-- +goose Down
+DROP TABLE IF EXISTS "webhook_delivery";
+
+DROP TABLE IF EXISTS "webhook_endpoint";
@@ -0,0 +1,33 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS "notification" (
+  "id" CHAR(26) NOT NULL PRIMARY KEY,
+  "user_id" CHAR(26) NOT NULL,
+  "type" TEXT NOT NULL,
+  "source_id" CHAR(26) NOT NULL,
+  "title" TEXT NOT NULL,
+  "body" TEXT NOT NULL,
+  "link_uri" TEXT,
+  "data" JSONB DEFAULT '{}'::JSONB NOT NULL,
+  "channels" TEXT NOT NULL,
+  "read_at" TIMESTAMP WITH TIME ZONE,
+  "created_at" TIMESTAMP WITH TIME ZONE DEFAULT NOW() NOT NULL,
+  CONSTRAINT "notification_user_id_type_source_id_unique" UNIQUE ("user_id", "type", "source_id")
+);
+
+CREATE INDEX IF NOT EXISTS "notification_user_id_created_at_index" ON "notification" ("user_id", "created_at" DESC, "id" DESC);
+
+CREATE INDEX IF NOT EXISTS "notification_user_id_unread_index" ON "notification" ("user_id") WHERE "read_at" IS NULL;
+
+CREATE TABLE IF NOT EXISTS "notification_preference" (
+  "user_id" CHAR(26) NOT NULL,
+  "type" TEXT NOT NULL,
+  "channels" TEXT NOT NULL,
+  "created_at" TIMESTAMP WITH TIME ZONE DEFAULT NOW() NOT NULL,
+  "updated_at" TIMESTAMP WITH TIME ZONE,
+  PRIMARY KEY ("user_id", "type")
+);
+
+-- +goose Down
+DROP TABLE IF EXISTS "notification_preference";
+
+DROP TABLE IF EXISTS "notification";
@@ -0,0 +1,8 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS "digest_unsubscribe" (
+  "user_id" CHAR(26) NOT NULL PRIMARY KEY,
+  "created_at" TIMESTAMP WITH TIME ZONE DEFAULT NOW() NOT NULL
+);
+
+-- +goose Down
+DROP TABLE IF EXISTS "digest_unsubscribe";
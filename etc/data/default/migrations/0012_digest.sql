@@ -0,0 +1,16 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS "digest_send" (
+  "id" CHAR(26) NOT NULL PRIMARY KEY,
+  "user_id" CHAR(26) NOT NULL,
+  "period" TEXT NOT NULL,
+  "status" TEXT DEFAULT 'sending'::TEXT NOT NULL,
+  "attempts" INTEGER DEFAULT 1 NOT NULL,
+  "error" TEXT,
+  "claimed_at" TIMESTAMP WITH TIME ZONE DEFAULT NOW() NOT NULL,
+  "finished_at" TIMESTAMP WITH TIME ZONE,
+  "created_at" TIMESTAMP WITH TIME ZONE DEFAULT NOW() NOT NULL,
+  CONSTRAINT "digest_send_user_id_period_unique" UNIQUE ("user_id", "period")
+);
+
+-- +goose Down
+DROP TABLE IF EXISTS "digest_send";
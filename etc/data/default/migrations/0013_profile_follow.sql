@@ -0,0 +1,14 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS "profile_follow" (
+  "id" CHAR(26) NOT NULL PRIMARY KEY,
+  "user_id" CHAR(26) NOT NULL,
+  "profile_id" CHAR(26) NOT NULL,
+  "created_at" TIMESTAMP WITH TIME ZONE DEFAULT NOW() NOT NULL,
+  CONSTRAINT "profile_follow_user_id_profile_id_unique" UNIQUE ("user_id", "profile_id")
+);
+
+CREATE INDEX IF NOT EXISTS "profile_follow_profile_id_created_at_index" ON "profile_follow" ("profile_id", "created_at" DESC);
+CREATE INDEX IF NOT EXISTS "profile_follow_user_id_created_at_index" ON "profile_follow" ("user_id", "created_at" DESC);
+
+-- +goose Down
+DROP TABLE IF EXISTS "profile_follow";
@@ -0,0 +1,26 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS "project" (
+  "id" CHAR(26) NOT NULL PRIMARY KEY,
+  "profile_id" CHAR(26) NOT NULL,
+  "slug" TEXT NOT NULL,
+  "name" TEXT NOT NULL,
+  "description" TEXT NOT NULL,
+  "repository_uri" TEXT,
+  "tags" TEXT DEFAULT ''::TEXT NOT NULL,
+  "status" TEXT DEFAULT 'active'::TEXT NOT NULL,
+  "stars" INTEGER,
+  "language" TEXT,
+  "last_commit_at" TIMESTAMP WITH TIME ZONE,
+  "metadata_fetched_at" TIMESTAMP WITH TIME ZONE,
+  "created_at" TIMESTAMP WITH TIME ZONE DEFAULT NOW() NOT NULL,
+  "updated_at" TIMESTAMP WITH TIME ZONE,
+  "deleted_at" TIMESTAMP WITH TIME ZONE
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS "project_profile_id_slug_unique" ON "project" ("profile_id", "slug") WHERE "deleted_at" IS NULL;
+
+CREATE INDEX IF NOT EXISTS "project_metadata_fetched_at_index" ON "project" ("metadata_fetched_at" NULLS FIRST)
+  WHERE "repository_uri" IS NOT NULL AND "deleted_at" IS NULL;
+
+-- +goose Down
+DROP TABLE IF EXISTS "project";
@@ -0,0 +1,81 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS "tag" (
+  "id" CHAR(26) NOT NULL PRIMARY KEY,
+  "slug" TEXT NOT NULL CONSTRAINT "tag_slug_unique" UNIQUE,
+  "name" TEXT NOT NULL,
+  "canonical_tag_id" CHAR(26),
+  "created_at" TIMESTAMP WITH TIME ZONE DEFAULT NOW() NOT NULL,
+  "updated_at" TIMESTAMP WITH TIME ZONE
+);
+
+CREATE INDEX IF NOT EXISTS "tag_canonical_tag_id_index" ON "tag" ("canonical_tag_id") WHERE "canonical_tag_id" IS NOT NULL;
+
+CREATE TABLE IF NOT EXISTS "story_tag" (
+  "story_id" CHAR(26) NOT NULL,
+  "tag_id" CHAR(26) NOT NULL,
+  "created_at" TIMESTAMP WITH TIME ZONE DEFAULT NOW() NOT NULL,
+  PRIMARY KEY ("story_id", "tag_id")
+);
+
+CREATE INDEX IF NOT EXISTS "story_tag_tag_id_index" ON "story_tag" ("tag_id", "story_id");
+
+CREATE TABLE IF NOT EXISTS "event_tag" (
+  "event_id" CHAR(26) NOT NULL,
+  "tag_id" CHAR(26) NOT NULL,
+  "created_at" TIMESTAMP WITH TIME ZONE DEFAULT NOW() NOT NULL,
+  PRIMARY KEY ("event_id", "tag_id")
+);
+
+CREATE INDEX IF NOT EXISTS "event_tag_tag_id_index" ON "event_tag" ("tag_id", "event_id");
+
+CREATE TABLE IF NOT EXISTS "project_tag" (
+  "project_id" CHAR(26) NOT NULL,
+  "tag_id" CHAR(26) NOT NULL,
+  "created_at" TIMESTAMP WITH TIME ZONE DEFAULT NOW() NOT NULL,
+  PRIMARY KEY ("project_id", "tag_id")
+);
+
+CREATE INDEX IF NOT EXISTS "project_tag_tag_id_index" ON "project_tag" ("tag_id", "project_id");
+
+-- project tags were kept as comma separated names. they are moved to the
+-- shared tags here; the ids are derived from the slugs, as ulids can't be
+-- generated in sql.
+CREATE TEMPORARY TABLE "project_tag_name" ON COMMIT DROP AS
+SELECT p.id AS project_id, t.name,
+  TRIM(BOTH '-' FROM REGEXP_REPLACE(TRANSLATE(LOWER(t.name), 'çğıöşüâîû', 'cgiosuaiu'), '[^a-z0-9]+', '-', 'g')) AS slug
+FROM "project" p, UNNEST(STRING_TO_ARRAY(p.tags, ',')) AS t(name)
+WHERE p.tags <> '';
+
+INSERT INTO "tag" (id, slug, name)
+SELECT DISTINCT ON (slug) UPPER(SUBSTRING(MD5('tag:' || slug) FOR 26)), slug, name
+FROM "project_tag_name"
+WHERE slug <> ''
+ORDER BY slug, name
+ON CONFLICT (slug) DO NOTHING;
+
+INSERT INTO "project_tag" (project_id, tag_id)
+SELECT ptn.project_id, t.id
+FROM "project_tag_name" ptn
+  INNER JOIN "tag" t ON t.slug = ptn.slug
+ON CONFLICT DO NOTHING;
+
+ALTER TABLE "project" DROP COLUMN IF EXISTS "tags";
+
+-- +goose Down
+ALTER TABLE "project" ADD COLUMN IF NOT EXISTS "tags" TEXT DEFAULT ''::TEXT NOT NULL;
+
+UPDATE "project" p
+SET tags = COALESCE((
+  SELECT STRING_AGG(t.slug, ',' ORDER BY t.slug)
+  FROM "project_tag" pt
+    INNER JOIN "tag" t ON t.id = pt.tag_id
+  WHERE pt.project_id = p.id
+), '');
+
+DROP TABLE IF EXISTS "project_tag";
+
+DROP TABLE IF EXISTS "event_tag";
+
+DROP TABLE IF EXISTS "story_tag";
+
+DROP TABLE IF EXISTS "tag";
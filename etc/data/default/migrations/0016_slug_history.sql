@@ -0,0 +1,13 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS "slug_history" (
+  "kind" TEXT NOT NULL,
+  "slug" TEXT NOT NULL,
+  "entity_id" CHAR(26) NOT NULL,
+  "created_at" TIMESTAMP WITH TIME ZONE DEFAULT NOW() NOT NULL,
+  PRIMARY KEY ("kind", "slug")
+);
+
+CREATE INDEX IF NOT EXISTS "slug_history_entity_id_index" ON "slug_history" ("kind", "entity_id");
+
+-- +goose Down
+DROP TABLE IF EXISTS "slug_history";
@@ -0,0 +1,46 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS "audit_log" (
+  "id" CHAR(26) NOT NULL PRIMARY KEY,
+  "actor_user_id" CHAR(26),
+  "session_id" CHAR(26),
+  "action" TEXT NOT NULL,
+  "entity_type" TEXT NOT NULL,
+  "entity_id" TEXT NOT NULL,
+  "profile_id" CHAR(26),
+  "before" JSONB DEFAULT 'null'::JSONB NOT NULL,
+  "after" JSONB DEFAULT 'null'::JSONB NOT NULL,
+  "diff" JSONB DEFAULT '{}'::JSONB NOT NULL,
+  "ip" TEXT,
+  "correlation_id" TEXT,
+  "created_at" TIMESTAMP WITH TIME ZONE DEFAULT NOW() NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS "audit_log_created_at_index" ON "audit_log" ("created_at" DESC, "id" DESC);
+
+CREATE INDEX IF NOT EXISTS "audit_log_entity_index" ON "audit_log" ("entity_type", "entity_id", "created_at" DESC);
+
+CREATE INDEX IF NOT EXISTS "audit_log_actor_user_id_index" ON "audit_log" ("actor_user_id", "created_at" DESC)
+  WHERE "actor_user_id" IS NOT NULL;
+
+CREATE INDEX IF NOT EXISTS "audit_log_profile_id_index" ON "audit_log" ("profile_id", "created_at" DESC)
+  WHERE "profile_id" IS NOT NULL;
+
+-- the log is append-only, entries are never changed nor removed.
+-- +goose StatementBegin
+CREATE OR REPLACE FUNCTION "audit_log_reject_change"() RETURNS TRIGGER AS $$
+BEGIN
+  RAISE EXCEPTION 'audit_log is append-only';
+END;
+$$ LANGUAGE plpgsql;
+-- +goose StatementEnd
+
+CREATE TRIGGER "audit_log_append_only"
+  BEFORE UPDATE OR DELETE ON "audit_log"
+  FOR EACH ROW EXECUTE FUNCTION "audit_log_reject_change"();
+
+-- +goose Down
+DROP TRIGGER IF EXISTS "audit_log_append_only" ON "audit_log";
+
+DROP FUNCTION IF EXISTS "audit_log_reject_change"();
+
+DROP TABLE IF EXISTS "audit_log";
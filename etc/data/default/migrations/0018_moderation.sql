@@ -0,0 +1,31 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS "report" (
+  "id" CHAR(26) NOT NULL PRIMARY KEY,
+  "reporter_user_id" CHAR(26) NOT NULL,
+  "entity_type" TEXT NOT NULL,
+  "entity_id" CHAR(26) NOT NULL,
+  "reason" TEXT NOT NULL,
+  "note" TEXT DEFAULT '' NOT NULL,
+  "status" TEXT DEFAULT 'open' NOT NULL,
+  "action" TEXT,
+  "resolved_by_user_id" CHAR(26),
+  "resolution_note" TEXT,
+  "resolved_at" TIMESTAMP WITH TIME ZONE,
+  "created_at" TIMESTAMP WITH TIME ZONE DEFAULT NOW() NOT NULL
+);
+
+-- a user can't pile up open reports on the same content.
+CREATE UNIQUE INDEX IF NOT EXISTS "report_open_reporter_entity_unique" ON "report" ("reporter_user_id", "entity_type", "entity_id")
+  WHERE "status" = 'open';
+
+CREATE INDEX IF NOT EXISTS "report_status_created_at_index" ON "report" ("status", "created_at");
+CREATE INDEX IF NOT EXISTS "report_entity_index" ON "report" ("entity_type", "entity_id");
+
+ALTER TABLE "user" ADD COLUMN IF NOT EXISTS "suspended_at" TIMESTAMP WITH TIME ZONE;
+ALTER TABLE "user" ADD COLUMN IF NOT EXISTS "suspended_reason" TEXT;
+
+-- +goose Down
+ALTER TABLE "user" DROP COLUMN IF EXISTS "suspended_reason";
+ALTER TABLE "user" DROP COLUMN IF EXISTS "suspended_at";
+
+DROP TABLE IF EXISTS "report";
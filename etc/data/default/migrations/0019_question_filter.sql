@@ -0,0 +1,12 @@
+-- +goose Up
+-- questions held back by the content filter are queued for review without a
+-- reporter.
+ALTER TABLE "report" ALTER COLUMN "reporter_user_id" DROP NOT NULL;
+
+CREATE INDEX IF NOT EXISTS "question_user_id_created_at_index" ON "question" ("user_id", "created_at" DESC);
+
+-- +goose Down
+DROP INDEX IF EXISTS "question_user_id_created_at_index";
+
+DELETE FROM "report" WHERE "reporter_user_id" IS NULL;
+ALTER TABLE "report" ALTER COLUMN "reporter_user_id" SET NOT NULL;
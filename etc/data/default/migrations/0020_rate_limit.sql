@@ -0,0 +1,13 @@
+-- +goose Up
+-- token buckets of the rate limiter, when instances share them.
+CREATE TABLE IF NOT EXISTS "rate_limit_bucket" (
+  "key" TEXT NOT NULL PRIMARY KEY,
+  "tokens" DOUBLE PRECISION NOT NULL,
+  "is_allowed" BOOLEAN NOT NULL,
+  "updated_at" TIMESTAMP WITH TIME ZONE DEFAULT NOW() NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS "rate_limit_bucket_updated_at_index" ON "rate_limit_bucket" ("updated_at");
+
+-- +goose Down
+DROP TABLE IF EXISTS "rate_limit_bucket";
@@ -0,0 +1,23 @@
+-- +goose Up
+-- responses of requests sent with an Idempotency-Key header, replayed to
+-- retries of the same request. Rows of requests still being handled lock
+-- their key until locked_until.
+CREATE TABLE IF NOT EXISTS "idempotency_key" (
+  "scope" TEXT NOT NULL,
+  "key" TEXT NOT NULL,
+  "fingerprint" TEXT NOT NULL,
+  "status" TEXT DEFAULT 'in_flight' NOT NULL,
+  "response_status" INTEGER,
+  "response_headers" JSONB DEFAULT '{}' NOT NULL,
+  "response_body" BYTEA,
+  "locked_until" TIMESTAMP WITH TIME ZONE NOT NULL,
+  "expires_at" TIMESTAMP WITH TIME ZONE NOT NULL,
+  "created_at" TIMESTAMP WITH TIME ZONE DEFAULT NOW() NOT NULL,
+  "completed_at" TIMESTAMP WITH TIME ZONE,
+  PRIMARY KEY ("scope", "key")
+);
+
+CREATE INDEX IF NOT EXISTS "idempotency_key_expires_at_index" ON "idempotency_key" ("expires_at");
+
+-- +goose Down
+DROP TABLE IF EXISTS "idempotency_key";
@@ -0,0 +1,17 @@
+-- +goose Up
+-- databases migrated before 0011 and 0013 were rearranged may lack the
+-- unsubscribe list, which moved from 0012 into 0011, and may still carry
+-- the follow index 0013 replaced. brings them to the current schema; on
+-- other databases this is a no-op.
+CREATE TABLE IF NOT EXISTS "digest_unsubscribe" (
+  "user_id" CHAR(26) NOT NULL PRIMARY KEY,
+  "created_at" TIMESTAMP WITH TIME ZONE DEFAULT NOW() NOT NULL
+);
+
+DROP INDEX IF EXISTS "profile_follow_profile_id_index";
+
+CREATE INDEX IF NOT EXISTS "profile_follow_profile_id_created_at_index" ON "profile_follow" ("profile_id", "created_at" DESC);
+CREATE INDEX IF NOT EXISTS "profile_follow_user_id_created_at_index" ON "profile_follow" ("user_id", "created_at" DESC);
+
+-- +goose Down
+-- the objects above belong to 0011 and 0013, which drop them.
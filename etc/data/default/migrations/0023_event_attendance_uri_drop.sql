@@ -0,0 +1,7 @@
+-- +goose Up
+-- attendance is tracked in event_attendance and checked in with signed codes;
+-- the link this column held was never read.
+ALTER TABLE "event" DROP COLUMN IF EXISTS "attendance_uri";
+
+-- +goose Down
+ALTER TABLE "event" ADD COLUMN IF NOT EXISTS "attendance_uri" TEXT;
@@ -0,0 +1,30 @@
+-- name: InsertAuditLog :exec
+INSERT INTO "audit_log" (id, actor_user_id, session_id, action, entity_type, entity_id, profile_id, before, after, diff, ip, correlation_id)
+VALUES (
+  sqlc.arg(id),
+  sqlc.narg(actor_user_id),
+  sqlc.narg(session_id),
+  sqlc.arg(action),
+  sqlc.arg(entity_type),
+  sqlc.arg(entity_id),
+  sqlc.narg(profile_id),
+  sqlc.arg(before),
+  sqlc.arg(after),
+  sqlc.arg(diff),
+  sqlc.narg(ip),
+  sqlc.narg(correlation_id)
+);
+
+-- name: ListAuditLogs :many
+SELECT *
+FROM "audit_log"
+WHERE (sqlc.narg(actor_user_id)::CHAR(26) IS NULL OR actor_user_id = sqlc.narg(actor_user_id))
+  AND (sqlc.narg(entity_type)::TEXT IS NULL OR entity_type = sqlc.narg(entity_type))
+  AND (sqlc.narg(entity_id)::TEXT IS NULL OR entity_id = sqlc.narg(entity_id))
+  AND (sqlc.narg(action)::TEXT IS NULL OR action = sqlc.narg(action))
+  AND (sqlc.narg(profile_id)::CHAR(26) IS NULL OR profile_id = sqlc.narg(profile_id))
+  AND (sqlc.narg(since)::TIMESTAMP WITH TIME ZONE IS NULL OR created_at >= sqlc.narg(since))
+  AND (sqlc.narg(until)::TIMESTAMP WITH TIME ZONE IS NULL OR created_at < sqlc.narg(until))
+ORDER BY created_at DESC, id DESC
+LIMIT sqlc.arg(limit_count)
+OFFSET sqlc.arg(offset_count);
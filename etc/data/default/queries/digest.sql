@@ -0,0 +1,70 @@
+-- name: ListDigestRecipientIds :many
+SELECT u.id FROM "user" u
+WHERE u.email IS NOT NULL
+  AND u.deleted_at IS NULL
+  AND EXISTS (SELECT 1 FROM "profile_follow" pf WHERE pf.user_id = u.id)
+  AND NOT EXISTS (SELECT 1 FROM "digest_unsubscribe" du WHERE du.user_id = u.id)
+ORDER BY u.id;
+
+-- name: GetDigestRecipient :one
+SELECT u.id, u.name, u.email,
+  EXISTS (SELECT 1 FROM "digest_unsubscribe" du WHERE du.user_id = u.id) AS is_unsubscribed
+FROM "user" u
+WHERE u.id = sqlc.arg(id)
+  AND u.deleted_at IS NULL
+LIMIT 1;
+
+-- name: ListDigestStories :many
+SELECT s.id, s.slug, s.title, s.summary, s.published_at, p.slug AS author_slug, p.title AS author_title
+FROM "story" s
+  INNER JOIN "profile_follow" pf ON pf.profile_id = s.author_profile_id
+  INNER JOIN "profile" p ON p.id = s.author_profile_id AND p.deleted_at IS NULL
+WHERE pf.user_id = sqlc.arg(user_id)
+  AND s.status = 'published'
+  AND s.published_at >= sqlc.arg(published_since)
+  AND s.published_at < sqlc.arg(published_until)
+  AND s.deleted_at IS NULL
+ORDER BY s.published_at DESC
+LIMIT sqlc.arg(limit_count);
+
+-- name: ListDigestEvents :many
+SELECT e.id, e.slug, e.title, e.time_start, e.time_end
+FROM "event" e
+WHERE e.status = 'published'
+  AND e.time_start >= sqlc.arg(starts_after)
+  AND e.time_start < sqlc.arg(starts_before)
+  AND e.deleted_at IS NULL
+  AND EXISTS (
+    SELECT 1 FROM "event_attendance" ea
+      INNER JOIN "profile_follow" pf ON pf.profile_id = ea.profile_id
+    WHERE ea.event_id = e.id
+      AND ea.kind = 'organizer'
+      AND ea.deleted_at IS NULL
+      AND pf.user_id = sqlc.arg(user_id)
+  )
+ORDER BY e.time_start
+LIMIT sqlc.arg(limit_count);
+
+-- name: ClaimDigestSend :one
+INSERT INTO "digest_send" (id, user_id, period)
+VALUES (sqlc.arg(id), sqlc.arg(user_id), sqlc.arg(period))
+ON CONFLICT (user_id, period) DO UPDATE
+SET status = 'sending',
+  attempts = "digest_send".attempts + 1,
+  error = NULL,
+  claimed_at = NOW()
+WHERE "digest_send".status = 'failed'
+  OR ("digest_send".status = 'sending' AND "digest_send".claimed_at < sqlc.arg(stale_before))
+RETURNING *;
+
+-- name: FinishDigestSend :exec
+UPDATE "digest_send"
+SET status = sqlc.arg(status),
+  error = sqlc.arg(error),
+  finished_at = NOW()
+WHERE id = sqlc.arg(id);
+
+-- name: UnsubscribeFromDigest :exec
+INSERT INTO "digest_unsubscribe" (user_id)
+VALUES (sqlc.arg(user_id))
+ON CONFLICT (user_id) DO NOTHING;
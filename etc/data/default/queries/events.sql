@@ -0,0 +1,127 @@
+-- name: GetEventById :one
+SELECT * FROM "event"
+WHERE id = $1
+  AND deleted_at IS NULL
+LIMIT 1;
+
+-- name: GetEventBySlug :one
+SELECT * FROM "event"
+WHERE slug = $1
+  AND deleted_at IS NULL
+LIMIT 1;
+
+-- name: ListUpcomingEvents :many
+SELECT * FROM "event"
+WHERE status = 'published'
+  AND time_end >= NOW()
+  AND deleted_at IS NULL
+  AND (sqlc.narg(tag_id)::TEXT IS NULL OR EXISTS (
+    SELECT 1 FROM "event_tag" et
+    WHERE et.event_id = "event".id
+      AND et.tag_id = sqlc.narg(tag_id)
+  ))
+ORDER BY time_start
+LIMIT sqlc.arg(limit_count);
+
+-- name: ListRecurringEventSeries :many
+SELECT * FROM "event_series"
+WHERE recurrence_interval_days IS NOT NULL
+  AND recurrence_interval_days > 0
+  AND (recurrence_until IS NULL OR recurrence_until > NOW())
+  AND deleted_at IS NULL;
+
+-- name: GetLatestEventOfSeries :one
+SELECT * FROM "event"
+WHERE series_id = sqlc.arg(series_id)
+  AND deleted_at IS NULL
+ORDER BY time_start DESC
+LIMIT 1;
+
+-- name: CreateEvent :one
+INSERT INTO "event" (id, kind, slug, event_picture_uri, title, description, time_start, time_end, series_id, status)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10) RETURNING *;
+
+-- name: PublishEvent :one
+UPDATE "event"
+SET status = 'published',
+  published_at = NOW(),
+  updated_at = NOW()
+WHERE id = sqlc.arg(id)
+  AND status = 'draft'
+  AND deleted_at IS NULL
+RETURNING *;
+
+-- name: RescheduleEvent :one
+UPDATE "event" e
+SET time_start = sqlc.arg(time_start),
+  time_end = sqlc.arg(time_end),
+  updated_at = NOW()
+FROM (SELECT id, time_start, time_end FROM "event" WHERE id = sqlc.arg(id) AND deleted_at IS NULL FOR UPDATE) previous
+WHERE e.id = previous.id
+RETURNING e.slug, e.title, previous.time_start AS previous_time_start, previous.time_end AS previous_time_end;
+
+-- name: SetEventPictureUri :execrows
+UPDATE "event"
+SET event_picture_uri = sqlc.arg(event_picture_uri),
+  updated_at = NOW()
+WHERE id = sqlc.arg(id)
+  AND deleted_at IS NULL;
+
+-- name: ListEventOrganizerProfileIds :many
+SELECT profile_id FROM "event_attendance"
+WHERE event_id = sqlc.arg(event_id)
+  AND kind = 'organizer'
+  AND deleted_at IS NULL;
+
+-- name: GetEventAttendance :one
+SELECT * FROM "event_attendance"
+WHERE event_id = sqlc.arg(event_id)
+  AND profile_id = sqlc.arg(profile_id)
+  AND deleted_at IS NULL
+LIMIT 1;
+
+-- name: CreateEventAttendance :one
+INSERT INTO "event_attendance" (id, kind, event_id, profile_id)
+VALUES (sqlc.arg(id), sqlc.arg(kind), sqlc.arg(event_id), sqlc.arg(profile_id))
+ON CONFLICT (event_id, profile_id) DO UPDATE
+SET kind = EXCLUDED.kind,
+  updated_at = NOW(),
+  deleted_at = NULL
+WHERE "event_attendance".deleted_at IS NOT NULL
+RETURNING *;
+
+-- name: UpdateEventAttendanceKind :execrows
+UPDATE "event_attendance"
+SET kind = sqlc.arg(new_kind),
+  updated_at = NOW()
+WHERE event_id = sqlc.arg(event_id)
+  AND profile_id = sqlc.arg(profile_id)
+  AND kind = sqlc.arg(current_kind)
+  AND deleted_at IS NULL;
+
+-- name: IsEventAttendeeOfKindForUser :one
+SELECT EXISTS (
+  SELECT 1 FROM "event_attendance" ea
+  WHERE ea.event_id = sqlc.arg(event_id)
+    AND ea.kind = sqlc.arg(kind)
+    AND ea.deleted_at IS NULL
+    AND (
+      ea.profile_id IN (
+        SELECT u.individual_profile_id FROM "user" u
+        WHERE u.id = sqlc.arg(user_id)
+      )
+      OR ea.profile_id IN (
+        SELECT pm.profile_id FROM "profile_membership" pm
+        WHERE pm.user_id = sqlc.arg(user_id)
+          AND pm.deleted_at IS NULL
+      )
+    )
+) AS "exists";
+
+-- name: ListEventAttendeeUserIds :many
+SELECT u.id FROM "event_attendance" ea
+  INNER JOIN "user" u ON u.individual_profile_id = ea.profile_id
+WHERE ea.event_id = sqlc.arg(event_id)
+  AND ea.kind IN ('rsvp', 'attended')
+  AND ea.deleted_at IS NULL
+  AND u.deleted_at IS NULL;
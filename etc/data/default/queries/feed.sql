@@ -0,0 +1,47 @@
+-- name: ListFeedItems :many
+SELECT f.kind, f.id, f.slug, f.title, f.summary, f.picture_uri, f.published_at, f.time_start,
+  f.profile_slug, f.profile_title
+FROM (
+  SELECT 'story' AS kind, s.id, s.slug, s.title, s.summary, s.story_picture_uri AS picture_uri,
+    s.published_at, NULL::TIMESTAMPTZ AS time_start, p.slug AS profile_slug, p.title AS profile_title
+  FROM "story" s
+    INNER JOIN "profile_follow" pf ON pf.profile_id = s.author_profile_id
+    INNER JOIN "profile" p ON p.id = s.author_profile_id AND p.deleted_at IS NULL
+  WHERE pf.user_id = sqlc.arg(user_id)
+    AND s.status = 'published'
+    AND s.published_at IS NOT NULL
+    AND s.deleted_at IS NULL
+    AND (sqlc.narg(tag_id)::TEXT IS NULL OR EXISTS (
+      SELECT 1 FROM "story_tag" st
+      WHERE st.story_id = s.id
+        AND st.tag_id = sqlc.narg(tag_id)
+    ))
+  UNION ALL
+  SELECT 'event' AS kind, e.id, e.slug, e.title, e.description AS summary, e.event_picture_uri AS picture_uri,
+    e.published_at, e.time_start, o.slug AS profile_slug, o.title AS profile_title
+  FROM "event" e
+    INNER JOIN LATERAL (
+      SELECT p.slug, p.title
+      FROM "event_attendance" ea
+        INNER JOIN "profile_follow" pf ON pf.profile_id = ea.profile_id
+        INNER JOIN "profile" p ON p.id = ea.profile_id AND p.deleted_at IS NULL
+      WHERE ea.event_id = e.id
+        AND ea.kind = 'organizer'
+        AND ea.deleted_at IS NULL
+        AND pf.user_id = sqlc.arg(user_id)
+      ORDER BY pf.created_at
+      LIMIT 1
+    ) o ON TRUE
+  WHERE e.status = 'published'
+    AND e.published_at IS NOT NULL
+    AND e.deleted_at IS NULL
+    AND (sqlc.narg(tag_id)::TEXT IS NULL OR EXISTS (
+      SELECT 1 FROM "event_tag" et
+      WHERE et.event_id = e.id
+        AND et.tag_id = sqlc.narg(tag_id)
+    ))
+) f
+WHERE sqlc.narg(before_published_at)::TIMESTAMPTZ IS NULL
+  OR (f.published_at, f.id) < (sqlc.narg(before_published_at)::TIMESTAMPTZ, sqlc.narg(before_id)::TEXT)
+ORDER BY f.published_at DESC, f.id DESC
+LIMIT sqlc.arg(limit_count);
@@ -0,0 +1,52 @@
+-- name: ClaimIdempotencyKey :one
+INSERT INTO "idempotency_key" AS k (scope, key, fingerprint, status, locked_until, expires_at)
+VALUES (
+  sqlc.arg(scope),
+  sqlc.arg(key),
+  sqlc.arg(fingerprint),
+  'in_flight',
+  NOW() + make_interval(secs => sqlc.arg(lock_seconds)::DOUBLE PRECISION),
+  NOW() + make_interval(secs => sqlc.arg(ttl_seconds)::DOUBLE PRECISION)
+)
+ON CONFLICT (scope, key) DO UPDATE
+SET fingerprint = EXCLUDED.fingerprint,
+  status = 'in_flight',
+  response_status = NULL,
+  response_headers = '{}',
+  response_body = NULL,
+  locked_until = EXCLUDED.locked_until,
+  expires_at = EXCLUDED.expires_at,
+  created_at = NOW(),
+  completed_at = NULL
+WHERE k.expires_at < NOW()
+  OR (k.status = 'in_flight' AND k.locked_until < NOW() AND k.fingerprint = EXCLUDED.fingerprint)
+RETURNING *;
+
+-- name: GetIdempotencyKey :one
+SELECT * FROM "idempotency_key"
+WHERE scope = sqlc.arg(scope)
+  AND key = sqlc.arg(key)
+LIMIT 1;
+
+-- name: CompleteIdempotencyKey :execrows
+UPDATE "idempotency_key"
+SET status = 'completed',
+  response_status = sqlc.arg(response_status),
+  response_headers = sqlc.arg(response_headers),
+  response_body = sqlc.arg(response_body),
+  completed_at = NOW()
+WHERE scope = sqlc.arg(scope)
+  AND key = sqlc.arg(key)
+  AND fingerprint = sqlc.arg(fingerprint)
+  AND status = 'in_flight';
+
+-- name: ReleaseIdempotencyKey :execrows
+DELETE FROM "idempotency_key"
+WHERE scope = sqlc.arg(scope)
+  AND key = sqlc.arg(key)
+  AND fingerprint = sqlc.arg(fingerprint)
+  AND status = 'in_flight';
+
+-- name: DeleteExpiredIdempotencyKeys :execrows
+DELETE FROM "idempotency_key"
+WHERE expires_at < NOW();
@@ -0,0 +1,101 @@
+-- name: CreateReport :one
+INSERT INTO "report" (id, reporter_user_id, entity_type, entity_id, reason, note)
+VALUES (sqlc.arg(id), sqlc.arg(reporter_user_id), sqlc.arg(entity_type), sqlc.arg(entity_id), sqlc.arg(reason), sqlc.arg(note))
+ON CONFLICT (reporter_user_id, entity_type, entity_id) WHERE status = 'open' DO NOTHING
+RETURNING *;
+
+-- name: GetReportById :one
+SELECT * FROM "report"
+WHERE id = $1
+LIMIT 1;
+
+-- name: ListReports :many
+SELECT * FROM "report"
+WHERE status = sqlc.arg(status)
+  AND (sqlc.narg(entity_type)::TEXT IS NULL OR entity_type = sqlc.narg(entity_type))
+ORDER BY created_at, id
+LIMIT sqlc.arg(limit_count)
+OFFSET sqlc.arg(offset_count);
+
+-- name: ResolveReport :one
+UPDATE "report"
+SET status = sqlc.arg(status),
+  action = sqlc.narg(action),
+  resolved_by_user_id = sqlc.arg(resolved_by_user_id),
+  resolution_note = sqlc.arg(resolution_note),
+  resolved_at = NOW()
+WHERE id = sqlc.arg(id)
+  AND status = 'open'
+RETURNING *;
+
+-- name: ResolveOpenReportsOfEntity :execrows
+UPDATE "report"
+SET status = sqlc.arg(status),
+  action = sqlc.narg(action),
+  resolved_by_user_id = sqlc.arg(resolved_by_user_id),
+  resolution_note = sqlc.arg(resolution_note),
+  resolved_at = NOW()
+WHERE entity_type = sqlc.arg(entity_type)
+  AND entity_id = sqlc.arg(entity_id)
+  AND status = 'open';
+
+-- name: GetReportableEntity :one
+SELECT q.id::TEXT AS entity_id, q.user_id::TEXT AS owner_user_id, ''::TEXT AS profile_id
+FROM "question" q
+WHERE sqlc.arg(entity_type)::TEXT = 'question'
+  AND q.id = sqlc.arg(entity_id)
+  AND q.deleted_at IS NULL
+UNION ALL
+SELECT s.id::TEXT, COALESCE(u.id, '')::TEXT, COALESCE(s.author_profile_id, '')::TEXT
+FROM "story" s
+  LEFT JOIN "user" u ON u.individual_profile_id = s.author_profile_id AND u.deleted_at IS NULL
+WHERE sqlc.arg(entity_type)::TEXT = 'story'
+  AND s.id = sqlc.arg(entity_id)
+  AND s.deleted_at IS NULL
+UNION ALL
+SELECT p.id::TEXT, COALESCE(u.id, '')::TEXT, p.id::TEXT
+FROM "profile" p
+  LEFT JOIN "user" u ON u.individual_profile_id = p.id AND u.deleted_at IS NULL
+WHERE sqlc.arg(entity_type)::TEXT = 'profile'
+  AND p.id = sqlc.arg(entity_id)
+  AND p.deleted_at IS NULL
+LIMIT 1;
+
+-- name: HideStory :execrows
+UPDATE "story"
+SET status = 'hidden',
+  updated_at = NOW()
+WHERE id = $1
+  AND deleted_at IS NULL;
+
+-- name: HideQuestion :execrows
+UPDATE "question"
+SET is_hidden = TRUE,
+  updated_at = NOW()
+WHERE id = $1
+  AND deleted_at IS NULL;
+
+-- name: SoftDeleteStory :execrows
+UPDATE "story"
+SET deleted_at = NOW()
+WHERE id = $1
+  AND deleted_at IS NULL;
+
+-- name: SoftDeleteQuestion :execrows
+UPDATE "question"
+SET deleted_at = NOW()
+WHERE id = $1
+  AND deleted_at IS NULL;
+
+-- name: SoftDeleteProfile :execrows
+UPDATE "profile"
+SET deleted_at = NOW()
+WHERE id = $1
+  AND deleted_at IS NULL;
+
+-- name: UnhideQuestion :execrows
+UPDATE "question"
+SET is_hidden = FALSE,
+  updated_at = NOW()
+WHERE id = $1
+  AND deleted_at IS NULL;
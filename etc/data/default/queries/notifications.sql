@@ -0,0 +1,56 @@
+-- name: InsertNotification :one
+INSERT INTO "notification" (id, user_id, type, source_id, title, body, link_uri, data, channels)
+VALUES (sqlc.arg(id), sqlc.arg(user_id), sqlc.arg(type), sqlc.arg(source_id), sqlc.arg(title), sqlc.arg(body), sqlc.arg(link_uri), sqlc.arg(data), sqlc.arg(channels))
+ON CONFLICT (user_id, type, source_id) DO NOTHING
+RETURNING *;
+
+-- name: GetNotificationById :one
+SELECT * FROM "notification"
+WHERE id = sqlc.arg(id)
+LIMIT 1;
+
+-- name: ListInboxNotifications :many
+SELECT * FROM "notification"
+WHERE user_id = sqlc.arg(user_id)
+  AND 'in_app' = ANY(string_to_array(channels, ','))
+  AND (NOT sqlc.arg(unread_only)::BOOLEAN OR read_at IS NULL)
+ORDER BY created_at DESC, id DESC
+LIMIT sqlc.arg(limit_count)
+OFFSET sqlc.arg(offset_count);
+
+-- name: CountUnreadInboxNotifications :one
+SELECT COUNT(*) FROM "notification"
+WHERE user_id = sqlc.arg(user_id)
+  AND 'in_app' = ANY(string_to_array(channels, ','))
+  AND read_at IS NULL;
+
+-- name: MarkNotificationRead :execrows
+UPDATE "notification"
+SET read_at = COALESCE(read_at, NOW())
+WHERE id = sqlc.arg(id)
+  AND user_id = sqlc.arg(user_id);
+
+-- name: MarkAllNotificationsRead :execrows
+UPDATE "notification"
+SET read_at = NOW()
+WHERE user_id = sqlc.arg(user_id)
+  AND read_at IS NULL;
+
+-- name: ListNotificationPreferences :many
+SELECT * FROM "notification_preference"
+WHERE user_id = sqlc.arg(user_id)
+ORDER BY type;
+
+-- name: GetNotificationPreference :one
+SELECT * FROM "notification_preference"
+WHERE user_id = sqlc.arg(user_id)
+  AND type = sqlc.arg(type)
+LIMIT 1;
+
+-- name: UpsertNotificationPreference :one
+INSERT INTO "notification_preference" (user_id, type, channels)
+VALUES (sqlc.arg(user_id), sqlc.arg(type), sqlc.arg(channels))
+ON CONFLICT (user_id, type) DO UPDATE
+SET channels = EXCLUDED.channels,
+  updated_at = NOW()
+RETURNING *;
@@ -0,0 +1,34 @@
+-- name: InsertOutboxEvent :exec
+INSERT INTO "outbox" (id, aggregate_type, aggregate_id, event_type, payload)
+VALUES (sqlc.arg(id), sqlc.arg(aggregate_type), sqlc.arg(aggregate_id), sqlc.arg(event_type), sqlc.arg(payload));
+
+-- name: ClaimOutboxEvents :many
+SELECT * FROM "outbox" o
+WHERE o.status = 'pending'
+  AND o.next_attempt_at <= NOW()
+  AND NOT EXISTS (
+    SELECT 1 FROM "outbox" p
+    WHERE p.aggregate_type = o.aggregate_type
+      AND p.aggregate_id = o.aggregate_id
+      AND p.status = 'pending'
+      AND p.id < o.id
+  )
+ORDER BY o.id
+LIMIT sqlc.arg(limit_count)
+FOR UPDATE SKIP LOCKED;
+
+-- name: MarkOutboxEventDelivered :exec
+UPDATE "outbox"
+SET status = 'delivered',
+  attempts = attempts + 1,
+  last_error = NULL,
+  delivered_at = NOW()
+WHERE id = sqlc.arg(id);
+
+-- name: MarkOutboxEventFailed :exec
+UPDATE "outbox"
+SET status = sqlc.arg(status),
+  attempts = attempts + 1,
+  last_error = sqlc.arg(last_error),
+  next_attempt_at = sqlc.arg(next_attempt_at)
+WHERE id = sqlc.arg(id);
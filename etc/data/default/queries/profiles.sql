@@ -9,18 +9,145 @@ WHERE slug = $1
 LIMIT 1;
 
 -- name: ListProfiles :many
-SELECT * FROM "profile";
+SELECT * FROM "profile"
+WHERE sqlc.narg(tag_id)::TEXT IS NULL
+  OR EXISTS (
+    SELECT 1 FROM "story" s
+      INNER JOIN "story_tag" st ON st.story_id = s.id
+    WHERE s.author_profile_id = "profile".id
+      AND s.status = 'published'
+      AND s.published_at <= NOW()
+      AND s.deleted_at IS NULL
+      AND st.tag_id = sqlc.narg(tag_id)
+  )
+  OR EXISTS (
+    SELECT 1 FROM "event_attendance" ea
+      INNER JOIN "event" e ON e.id = ea.event_id
+      INNER JOIN "event_tag" et ON et.event_id = e.id
+    WHERE ea.profile_id = "profile".id
+      AND ea.kind = 'organizer'
+      AND ea.deleted_at IS NULL
+      AND e.status = 'published'
+      AND e.deleted_at IS NULL
+      AND et.tag_id = sqlc.narg(tag_id)
+  );
+
+-- name: ListNewestProfiles :many
+SELECT * FROM "profile"
+WHERE deleted_at IS NULL
+ORDER BY created_at DESC
+LIMIT sqlc.arg(limit_count);
 
 -- name: CreateProfile :one
-INSERT INTO "profile" (id, slug)
-VALUES ($1, $2) RETURNING *;
+INSERT INTO "profile" (id, kind, slug, title, description)
+VALUES (sqlc.arg(id), sqlc.arg(kind), sqlc.arg(slug), sqlc.arg(title), sqlc.arg(description))
+RETURNING *;
+
+-- name: CreateProfileMembership :one
+INSERT INTO "profile_membership" (id, kind, profile_id, user_id)
+VALUES (sqlc.arg(id), sqlc.arg(kind), sqlc.arg(profile_id), sqlc.arg(user_id))
+ON CONFLICT (profile_id, user_id) DO UPDATE
+SET kind = EXCLUDED.kind,
+  updated_at = NOW(),
+  deleted_at = NULL
+WHERE "profile_membership".deleted_at IS NOT NULL
+RETURNING *;
 
 -- name: UpdateProfile :execrows
 UPDATE "profile"
 SET slug = $2
 WHERE id = $1;
 
+-- name: SetProfilePictureUri :execrows
+UPDATE "profile"
+SET profile_picture_uri = sqlc.arg(profile_picture_uri),
+  updated_at = NOW()
+WHERE id = sqlc.arg(id)
+  AND deleted_at IS NULL;
+
 -- name: DeleteProfile :execrows
 UPDATE "profile"
 SET deleted_at = NOW()
 WHERE id = $1;
+
+-- name: IsProfileMember :one
+SELECT EXISTS (
+  SELECT 1 FROM "user" u
+  WHERE u.id = sqlc.arg(user_id)
+    AND u.individual_profile_id = sqlc.arg(profile_id)
+  UNION ALL
+  SELECT 1 FROM "profile_membership" pm
+  WHERE pm.user_id = sqlc.arg(user_id)
+    AND pm.profile_id = sqlc.arg(profile_id)
+    AND pm.deleted_at IS NULL
+) AS "exists";
+
+-- name: IsProfileAdmin :one
+SELECT EXISTS (
+  SELECT 1 FROM "user" u
+  WHERE u.id = sqlc.arg(user_id)
+    AND u.individual_profile_id = sqlc.arg(profile_id)
+  UNION ALL
+  SELECT 1 FROM "profile_membership" pm
+  WHERE pm.user_id = sqlc.arg(user_id)
+    AND pm.profile_id = sqlc.arg(profile_id)
+    AND pm.kind IN ('owner', 'admin')
+    AND pm.deleted_at IS NULL
+) AS "exists";
+
+-- name: IsUserActive :one
+SELECT EXISTS (
+  SELECT 1 FROM "user"
+  WHERE id = sqlc.arg(id)
+    AND deleted_at IS NULL
+    AND suspended_at IS NULL
+) AS is_active;
+
+-- name: FollowProfile :execrows
+INSERT INTO "profile_follow" (id, user_id, profile_id)
+VALUES (sqlc.arg(id), sqlc.arg(user_id), sqlc.arg(profile_id))
+ON CONFLICT (user_id, profile_id) DO NOTHING;
+
+-- name: UnfollowProfile :execrows
+DELETE FROM "profile_follow"
+WHERE user_id = sqlc.arg(user_id)
+  AND profile_id = sqlc.arg(profile_id);
+
+-- name: IsFollowingProfile :one
+SELECT EXISTS (
+  SELECT 1 FROM "profile_follow" pf
+  WHERE pf.user_id = sqlc.arg(user_id)
+    AND pf.profile_id = sqlc.arg(profile_id)
+) AS "exists";
+
+-- name: CountProfileFollowers :one
+SELECT COUNT(*) AS "count"
+FROM "profile_follow" pf
+  INNER JOIN "user" u ON u.id = pf.user_id AND u.deleted_at IS NULL
+WHERE pf.profile_id = sqlc.arg(profile_id);
+
+-- name: CountFollowedProfiles :one
+SELECT COUNT(*) AS "count"
+FROM "profile_follow" pf
+  INNER JOIN "profile" p ON p.id = pf.profile_id AND p.deleted_at IS NULL
+WHERE pf.user_id = sqlc.arg(user_id);
+
+-- name: ListProfileFollowers :many
+SELECT u.id AS user_id, u.name, p.slug AS profile_slug, p.title AS profile_title,
+  p.profile_picture_uri, pf.created_at AS followed_at
+FROM "profile_follow" pf
+  INNER JOIN "user" u ON u.id = pf.user_id AND u.deleted_at IS NULL
+  LEFT JOIN "profile" p ON p.id = u.individual_profile_id AND p.deleted_at IS NULL
+WHERE pf.profile_id = sqlc.arg(profile_id)
+ORDER BY pf.created_at DESC, pf.id DESC
+LIMIT sqlc.arg(limit_count)
+OFFSET sqlc.arg(offset_count);
+
+-- name: ListFollowedProfiles :many
+SELECT p.id, p.kind, p.slug, p.profile_picture_uri, p.title, p.description, pf.created_at AS followed_at
+FROM "profile_follow" pf
+  INNER JOIN "profile" p ON p.id = pf.profile_id AND p.deleted_at IS NULL
+WHERE pf.user_id = sqlc.arg(user_id)
+ORDER BY pf.created_at DESC, pf.id DESC
+LIMIT sqlc.arg(limit_count)
+OFFSET sqlc.arg(offset_count);
@@ -0,0 +1,81 @@
+-- name: GetProjectById :one
+SELECT * FROM "project"
+WHERE id = sqlc.arg(id)
+  AND deleted_at IS NULL
+LIMIT 1;
+
+-- name: GetProjectBySlug :one
+SELECT * FROM "project"
+WHERE profile_id = sqlc.arg(profile_id)
+  AND slug = sqlc.arg(slug)
+  AND deleted_at IS NULL
+LIMIT 1;
+
+-- name: ListProjectsByProfileId :many
+SELECT * FROM "project"
+WHERE profile_id = sqlc.arg(profile_id)
+  AND deleted_at IS NULL
+  AND (sqlc.narg(tag_id)::TEXT IS NULL OR EXISTS (
+    SELECT 1 FROM "project_tag" pt
+    WHERE pt.project_id = "project".id
+      AND pt.tag_id = sqlc.narg(tag_id)
+  ))
+ORDER BY CASE status WHEN 'active' THEN 0 WHEN 'inactive' THEN 1 ELSE 2 END, created_at DESC
+LIMIT sqlc.arg(limit_count) OFFSET sqlc.arg(offset_count);
+
+-- name: CreateProject :one
+INSERT INTO "project" (id, profile_id, slug, name, description, repository_uri, status)
+VALUES (sqlc.arg(id), sqlc.arg(profile_id), sqlc.arg(slug), sqlc.arg(name), sqlc.arg(description), sqlc.arg(repository_uri), sqlc.arg(status))
+RETURNING *;
+
+-- name: UpdateProject :execrows
+UPDATE "project"
+SET name = sqlc.arg(name),
+  description = sqlc.arg(description),
+  repository_uri = sqlc.arg(repository_uri),
+  status = sqlc.arg(status),
+  updated_at = NOW()
+WHERE id = sqlc.arg(id)
+  AND deleted_at IS NULL
+  AND (sqlc.narg(expected_version)::TIMESTAMPTZ IS NULL OR COALESCE(updated_at, created_at) = sqlc.narg(expected_version));
+
+-- name: ClearProjectMetadata :execrows
+UPDATE "project"
+SET stars = NULL,
+  language = NULL,
+  last_commit_at = NULL,
+  metadata_fetched_at = NULL
+WHERE id = sqlc.arg(id);
+
+-- name: SetProjectMetadata :execrows
+UPDATE "project"
+SET stars = sqlc.arg(stars),
+  language = sqlc.arg(language),
+  last_commit_at = sqlc.arg(last_commit_at),
+  metadata_fetched_at = NOW()
+WHERE id = sqlc.arg(id)
+  AND deleted_at IS NULL;
+
+-- name: ListProjectIdsWithStaleMetadata :many
+SELECT id FROM "project"
+WHERE repository_uri IS NOT NULL
+  AND deleted_at IS NULL
+  AND (metadata_fetched_at IS NULL OR metadata_fetched_at < sqlc.arg(fetched_before))
+ORDER BY metadata_fetched_at NULLS FIRST
+LIMIT sqlc.arg(limit_count);
+
+-- name: ListVisibleProjectsByTag :many
+SELECT p.* FROM "project" p
+  INNER JOIN "project_tag" pt ON pt.project_id = p.id
+  INNER JOIN "profile" pr ON pr.id = p.profile_id AND pr.deleted_at IS NULL
+WHERE pt.tag_id = sqlc.arg(tag_id)
+  AND pr.show_projects = TRUE
+  AND p.deleted_at IS NULL
+ORDER BY p.created_at DESC
+LIMIT sqlc.arg(limit_count) OFFSET sqlc.arg(offset_count);
+
+-- name: DeleteProject :execrows
+UPDATE "project"
+SET deleted_at = NOW()
+WHERE id = sqlc.arg(id)
+  AND deleted_at IS NULL;
@@ -0,0 +1,75 @@
+-- name: ListTopUnansweredQuestions :many
+SELECT * FROM "question"
+WHERE answered_at IS NULL
+  AND is_hidden = FALSE
+  AND deleted_at IS NULL
+ORDER BY vote_score DESC, created_at DESC
+LIMIT sqlc.arg(limit_count);
+
+-- name: RefreshQuestionVoteScores :execrows
+UPDATE "question" q
+SET vote_score = s.score
+FROM (
+  SELECT q2.id, COALESCE(SUM(qv.score), 0)::INTEGER AS score
+  FROM "question" q2
+    LEFT JOIN "question_vote" qv ON qv.question_id = q2.id
+  GROUP BY q2.id
+) s
+WHERE s.id = q.id
+  AND q.vote_score <> s.score;
+
+-- name: CreateQuestion :one
+INSERT INTO "question" (id, user_id, content, is_anonymous, is_hidden)
+VALUES (sqlc.arg(id), sqlc.arg(user_id), sqlc.arg(content), sqlc.arg(is_anonymous), sqlc.arg(is_hidden))
+RETURNING *;
+
+-- name: ListRecentQuestionContentsOfUser :many
+SELECT content FROM "question"
+WHERE user_id = sqlc.arg(user_id)
+  AND created_at >= sqlc.arg(since)
+  AND deleted_at IS NULL
+ORDER BY created_at DESC;
+
+-- name: GetQuestionReputationOfUser :one
+SELECT
+  COUNT(*) FILTER (WHERE is_hidden = FALSE AND deleted_at IS NULL)::INTEGER AS accepted_count,
+  COUNT(*) FILTER (WHERE EXISTS (
+    SELECT 1 FROM "report" r
+    WHERE r.entity_type = 'question'
+      AND r.entity_id = "question".id
+      AND r.status = 'actioned'
+      AND r.action IN ('hide', 'delete')
+  ))::INTEGER AS rejected_count,
+  COALESCE(SUM(vote_score) FILTER (WHERE is_hidden = FALSE AND deleted_at IS NULL), 0)::INTEGER AS vote_total
+FROM "question"
+WHERE user_id = sqlc.arg(user_id);
+
+-- name: GetQuestionById :one
+SELECT * FROM "question"
+WHERE id = sqlc.arg(id)
+  AND deleted_at IS NULL
+LIMIT 1;
+
+-- name: AnswerQuestion :one
+UPDATE "question"
+SET answered_at = NOW(),
+  answer_kind = sqlc.arg(answer_kind),
+  answer_uri = sqlc.arg(answer_uri),
+  answer_content = sqlc.arg(answer_content),
+  updated_at = NOW()
+WHERE id = sqlc.arg(id)
+  AND answered_at IS NULL
+  AND is_hidden = FALSE
+  AND deleted_at IS NULL
+RETURNING *;
+
+-- name: UpsertQuestionVote :exec
+INSERT INTO "question_vote" (id, question_id, user_id, score)
+VALUES (sqlc.arg(id), sqlc.arg(question_id), sqlc.arg(user_id), sqlc.arg(score))
+ON CONFLICT (question_id, user_id) DO UPDATE
+SET score = EXCLUDED.score;
+
+-- name: DeleteQuestionVote :execrows
+DELETE FROM "question_vote"
+WHERE question_id = sqlc.arg(question_id)
+  AND user_id = sqlc.arg(user_id);
@@ -0,0 +1,13 @@
+-- name: TakeRateLimitToken :one
+INSERT INTO "rate_limit_bucket" AS b (key, tokens, is_allowed, updated_at)
+VALUES (sqlc.arg(key), sqlc.arg(capacity)::DOUBLE PRECISION - 1, TRUE, NOW())
+ON CONFLICT (key) DO UPDATE
+SET tokens = LEAST(sqlc.arg(capacity)::DOUBLE PRECISION, b.tokens + EXTRACT(EPOCH FROM NOW() - b.updated_at)::DOUBLE PRECISION * sqlc.arg(refill_rate)::DOUBLE PRECISION)
+    - CASE WHEN LEAST(sqlc.arg(capacity)::DOUBLE PRECISION, b.tokens + EXTRACT(EPOCH FROM NOW() - b.updated_at)::DOUBLE PRECISION * sqlc.arg(refill_rate)::DOUBLE PRECISION) >= 1 THEN 1 ELSE 0 END,
+  is_allowed = LEAST(sqlc.arg(capacity)::DOUBLE PRECISION, b.tokens + EXTRACT(EPOCH FROM NOW() - b.updated_at)::DOUBLE PRECISION * sqlc.arg(refill_rate)::DOUBLE PRECISION) >= 1,
+  updated_at = NOW()
+RETURNING tokens, is_allowed;
+
+-- name: DeleteIdleRateLimitBuckets :execrows
+DELETE FROM "rate_limit_bucket"
+WHERE updated_at < sqlc.arg(idle_before);
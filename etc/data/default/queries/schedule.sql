@@ -0,0 +1,22 @@
+-- name: StartScheduleRun :one
+INSERT INTO "schedule_run" (id, task, trigger, instance, scheduled_at)
+VALUES (sqlc.arg(id), sqlc.arg(task), sqlc.arg(trigger), sqlc.arg(instance), sqlc.arg(scheduled_at))
+ON CONFLICT (task, scheduled_at) DO NOTHING
+RETURNING *;
+
+-- name: FinishScheduleRun :exec
+UPDATE "schedule_run"
+SET status = sqlc.arg(status),
+  error = sqlc.arg(error),
+  finished_at = NOW()
+WHERE id = sqlc.arg(id);
+
+-- name: ListLatestScheduleRuns :many
+SELECT DISTINCT ON (task) * FROM "schedule_run"
+ORDER BY task, started_at DESC;
+
+-- name: TryScheduleLeaderLock :one
+SELECT pg_try_advisory_lock(sqlc.arg(lock_key)::BIGINT) AS acquired;
+
+-- name: ReleaseScheduleLeaderLock :one
+SELECT pg_advisory_unlock(sqlc.arg(lock_key)::BIGINT) AS released;
@@ -0,0 +1,54 @@
+-- name: SearchDocuments :many
+SELECT
+  r.entity_type,
+  r.entity_id,
+  r.slug,
+  r.title,
+  ts_headline(
+    'turkish',
+    concat_ws(' ', NULLIF(r.summary, ''), NULLIF(r.content, '')),
+    to_tsquery('turkish', sqlc.arg(query_text)),
+    sqlc.arg(headline_options)
+  ) AS snippet,
+  r.rank,
+  r.total_count
+FROM (
+  SELECT
+    d.entity_type,
+    d.entity_id,
+    d.slug,
+    d.title,
+    d.summary,
+    d.content,
+    d.updated_at,
+    GREATEST(
+      ts_rank(d.search_vector_turkish, to_tsquery('turkish', sqlc.arg(query_text))),
+      ts_rank(d.search_vector_english, to_tsquery('english', sqlc.arg(query_text)))
+    )::REAL AS rank,
+    COUNT(*) OVER () AS total_count
+  FROM "search_document" d
+  WHERE d.is_visible = TRUE
+    AND d.visible_from <= NOW()
+    AND d.entity_type = ANY(string_to_array(sqlc.arg(entity_types)::TEXT, ','))
+    AND (sqlc.narg(tag_id)::TEXT IS NULL
+      OR (d.entity_type = 'story' AND EXISTS (
+        SELECT 1 FROM "story_tag" st
+        WHERE st.story_id = d.entity_id
+          AND st.tag_id = sqlc.narg(tag_id)
+      ))
+      OR (d.entity_type = 'event' AND EXISTS (
+        SELECT 1 FROM "event_tag" et
+        WHERE et.event_id = d.entity_id
+          AND et.tag_id = sqlc.narg(tag_id)
+      )))
+    AND (
+      d.search_vector_turkish @@ to_tsquery('turkish', sqlc.arg(query_text))
+      OR d.search_vector_english @@ to_tsquery('english', sqlc.arg(query_text))
+    )
+  ORDER BY rank DESC, d.updated_at DESC
+  LIMIT sqlc.arg(limit_count) OFFSET sqlc.arg(offset_count)
+) r
+ORDER BY r.rank DESC, r.updated_at DESC;
+
+-- name: RebuildSearchDocuments :exec
+SELECT search_document_rebuild(sqlc.arg(entity_type));
@@ -0,0 +1,61 @@
+-- name: GetSlugRedirect :one
+SELECT COALESCE(p.slug, e.slug, es.slug, s.slug, '')::TEXT AS current_slug
+FROM "slug_history" h
+  LEFT JOIN "profile" p ON h.kind = 'profile' AND p.id = h.entity_id AND p.deleted_at IS NULL
+  LEFT JOIN "event" e ON h.kind = 'event' AND e.id = h.entity_id AND e.deleted_at IS NULL
+  LEFT JOIN "event_series" es ON h.kind = 'event_series' AND es.id = h.entity_id AND es.deleted_at IS NULL
+  LEFT JOIN "story" s ON h.kind = 'story' AND s.id = h.entity_id AND s.deleted_at IS NULL
+WHERE h.kind = sqlc.arg(kind)
+  AND h.slug = sqlc.arg(slug);
+
+-- name: IsSlugTaken :one
+SELECT (
+  (sqlc.arg(kind)::TEXT = 'profile' AND EXISTS (SELECT 1 FROM "profile" WHERE slug = sqlc.arg(slug)))
+  OR (sqlc.arg(kind)::TEXT = 'event' AND EXISTS (SELECT 1 FROM "event" WHERE slug = sqlc.arg(slug)))
+  OR (sqlc.arg(kind)::TEXT = 'event_series' AND EXISTS (SELECT 1 FROM "event_series" WHERE slug = sqlc.arg(slug)))
+  OR (sqlc.arg(kind)::TEXT = 'story' AND EXISTS (SELECT 1 FROM "story" WHERE slug = sqlc.arg(slug)))
+)::BOOLEAN AS is_taken;
+
+-- name: RecordSlugHistory :exec
+INSERT INTO "slug_history" (kind, slug, entity_id)
+VALUES (sqlc.arg(kind), sqlc.arg(slug), sqlc.arg(entity_id))
+ON CONFLICT (kind, slug) DO UPDATE
+SET entity_id = EXCLUDED.entity_id,
+  created_at = NOW();
+
+-- name: DeleteSlugHistory :exec
+DELETE FROM "slug_history"
+WHERE kind = sqlc.arg(kind)
+  AND slug = sqlc.arg(slug);
+
+-- name: RenameProfileSlug :one
+UPDATE "profile" p
+SET slug = sqlc.arg(slug),
+  updated_at = NOW()
+FROM (SELECT id, slug FROM "profile" WHERE id = sqlc.arg(id) AND deleted_at IS NULL FOR UPDATE) previous
+WHERE p.id = previous.id
+RETURNING previous.slug AS previous_slug;
+
+-- name: RenameEventSlug :one
+UPDATE "event" e
+SET slug = sqlc.arg(slug),
+  updated_at = NOW()
+FROM (SELECT id, slug FROM "event" WHERE id = sqlc.arg(id) AND deleted_at IS NULL FOR UPDATE) previous
+WHERE e.id = previous.id
+RETURNING previous.slug AS previous_slug;
+
+-- name: RenameEventSeriesSlug :one
+UPDATE "event_series" es
+SET slug = sqlc.arg(slug),
+  updated_at = NOW()
+FROM (SELECT id, slug FROM "event_series" WHERE id = sqlc.arg(id) AND deleted_at IS NULL FOR UPDATE) previous
+WHERE es.id = previous.id
+RETURNING previous.slug AS previous_slug;
+
+-- name: RenameStorySlug :one
+UPDATE "story" s
+SET slug = sqlc.arg(slug),
+  updated_at = NOW()
+FROM (SELECT id, slug FROM "story" WHERE id = sqlc.arg(id) AND deleted_at IS NULL FOR UPDATE) previous
+WHERE s.id = previous.id
+RETURNING previous.slug AS previous_slug;
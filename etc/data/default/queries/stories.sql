@@ -0,0 +1,117 @@
+-- name: GetStoryById :one
+SELECT * FROM "story"
+WHERE id = $1
+  AND deleted_at IS NULL
+LIMIT 1;
+
+-- name: GetStoryBySlug :one
+SELECT * FROM "story"
+WHERE slug = $1
+  AND deleted_at IS NULL
+LIMIT 1;
+
+-- name: ListPublishedStories :many
+SELECT * FROM "story"
+WHERE status = 'published'
+  AND published_at <= NOW()
+  AND deleted_at IS NULL
+  AND (sqlc.narg(tag_id)::TEXT IS NULL OR EXISTS (
+    SELECT 1 FROM "story_tag" st
+    WHERE st.story_id = "story".id
+      AND st.tag_id = sqlc.narg(tag_id)
+  ))
+ORDER BY published_at DESC
+LIMIT sqlc.arg(limit_count) OFFSET sqlc.arg(offset_count);
+
+-- name: ListPublishedStoriesByAuthorProfileId :many
+SELECT * FROM "story"
+WHERE author_profile_id = sqlc.arg(author_profile_id)
+  AND status = 'published'
+  AND published_at <= NOW()
+  AND deleted_at IS NULL
+  AND (sqlc.narg(tag_id)::TEXT IS NULL OR EXISTS (
+    SELECT 1 FROM "story_tag" st
+    WHERE st.story_id = "story".id
+      AND st.tag_id = sqlc.narg(tag_id)
+  ))
+ORDER BY published_at DESC
+LIMIT sqlc.arg(limit_count) OFFSET sqlc.arg(offset_count);
+
+-- name: ListFeaturedStories :many
+SELECT * FROM "story"
+WHERE is_featured = TRUE
+  AND status = 'published'
+  AND published_at <= NOW()
+  AND deleted_at IS NULL
+  AND (sqlc.narg(tag_id)::TEXT IS NULL OR EXISTS (
+    SELECT 1 FROM "story_tag" st
+    WHERE st.story_id = "story".id
+      AND st.tag_id = sqlc.narg(tag_id)
+  ))
+ORDER BY featured_order, published_at DESC
+LIMIT sqlc.arg(limit_count);
+
+-- name: GetPublishedStoriesFeedStamp :one
+SELECT
+  COUNT(*) AS story_count,
+  COALESCE(MAX(COALESCE(updated_at, published_at)), 'epoch'::TIMESTAMPTZ)::TIMESTAMPTZ AS last_modified_at
+FROM "story"
+WHERE status = 'published'
+  AND published_at <= NOW()
+  AND deleted_at IS NULL;
+
+-- name: GetPublishedStoriesFeedStampByAuthorProfileId :one
+SELECT
+  COUNT(*) AS story_count,
+  COALESCE(MAX(COALESCE(updated_at, published_at)), 'epoch'::TIMESTAMPTZ)::TIMESTAMPTZ AS last_modified_at
+FROM "story"
+WHERE author_profile_id = sqlc.arg(author_profile_id)
+  AND status = 'published'
+  AND published_at <= NOW()
+  AND deleted_at IS NULL;
+
+-- name: ListDueScheduledStoryIds :many
+SELECT id FROM "story"
+WHERE status = 'scheduled'
+  AND published_at <= NOW()
+  AND deleted_at IS NULL
+ORDER BY published_at;
+
+-- name: CreateStory :one
+INSERT INTO "story" (id, kind, status, slug, story_picture_uri, title, description, author_profile_id, content, summary)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10) RETURNING *;
+
+-- name: UpdateStory :execrows
+UPDATE "story"
+SET story_picture_uri = sqlc.arg(story_picture_uri),
+  title = sqlc.arg(title),
+  description = sqlc.arg(description),
+  content = sqlc.arg(content),
+  summary = sqlc.arg(summary),
+  updated_at = NOW()
+WHERE id = sqlc.arg(id)
+  AND deleted_at IS NULL
+  AND (sqlc.narg(expected_version)::TIMESTAMPTZ IS NULL OR COALESCE(updated_at, created_at) = sqlc.narg(expected_version));
+
+-- name: SetStoryPictureUri :execrows
+UPDATE "story"
+SET story_picture_uri = sqlc.arg(story_picture_uri),
+  updated_at = NOW()
+WHERE id = sqlc.arg(id)
+  AND deleted_at IS NULL;
+
+-- name: UpdateStoryStatus :execrows
+UPDATE "story"
+SET status = sqlc.arg(new_status),
+  published_at = sqlc.arg(published_at),
+  updated_at = NOW()
+WHERE id = sqlc.arg(id)
+  AND status = sqlc.arg(current_status)
+  AND deleted_at IS NULL;
+
+-- name: SetStoryFeatured :execrows
+UPDATE "story"
+SET is_featured = sqlc.arg(is_featured),
+  featured_order = sqlc.arg(featured_order)
+WHERE id = sqlc.arg(id)
+  AND deleted_at IS NULL;
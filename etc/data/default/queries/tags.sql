@@ -0,0 +1,118 @@
+-- name: GetTagById :one
+SELECT * FROM "tag"
+WHERE id = sqlc.arg(id)
+LIMIT 1;
+
+-- name: GetTagBySlug :one
+SELECT * FROM "tag"
+WHERE slug = sqlc.arg(slug)
+LIMIT 1;
+
+-- name: ListTagsBySlugs :many
+SELECT * FROM "tag"
+WHERE slug = ANY(sqlc.arg(slugs)::TEXT[]);
+
+-- name: UpsertTag :one
+INSERT INTO "tag" (id, slug, name)
+VALUES (sqlc.arg(id), sqlc.arg(slug), sqlc.arg(name))
+ON CONFLICT (slug) DO UPDATE
+SET slug = EXCLUDED.slug
+RETURNING *;
+
+-- name: ListTagSynonyms :many
+SELECT * FROM "tag"
+WHERE canonical_tag_id = sqlc.arg(canonical_tag_id)
+ORDER BY slug;
+
+-- name: SetTagCanonical :execrows
+UPDATE "tag"
+SET canonical_tag_id = sqlc.arg(canonical_tag_id),
+  updated_at = NOW()
+WHERE id = sqlc.arg(id);
+
+-- name: RepointTagSynonyms :execrows
+UPDATE "tag"
+SET canonical_tag_id = sqlc.arg(to_tag_id),
+  updated_at = NOW()
+WHERE canonical_tag_id = sqlc.arg(from_tag_id);
+
+-- name: MoveStoryTags :execrows
+WITH "moved" AS (
+  DELETE FROM "story_tag"
+  WHERE tag_id = sqlc.arg(from_tag_id)
+  RETURNING story_id
+)
+INSERT INTO "story_tag" (story_id, tag_id)
+SELECT story_id, sqlc.arg(to_tag_id) FROM "moved"
+ON CONFLICT DO NOTHING;
+
+-- name: MoveEventTags :execrows
+WITH "moved" AS (
+  DELETE FROM "event_tag"
+  WHERE tag_id = sqlc.arg(from_tag_id)
+  RETURNING event_id
+)
+INSERT INTO "event_tag" (event_id, tag_id)
+SELECT event_id, sqlc.arg(to_tag_id) FROM "moved"
+ON CONFLICT DO NOTHING;
+
+-- name: MoveProjectTags :execrows
+WITH "moved" AS (
+  DELETE FROM "project_tag"
+  WHERE tag_id = sqlc.arg(from_tag_id)
+  RETURNING project_id
+)
+INSERT INTO "project_tag" (project_id, tag_id)
+SELECT project_id, sqlc.arg(to_tag_id) FROM "moved"
+ON CONFLICT DO NOTHING;
+
+-- name: ReplaceStoryTags :exec
+WITH "removed" AS (
+  DELETE FROM "story_tag"
+  WHERE story_id = sqlc.arg(story_id)
+    AND tag_id <> ALL(sqlc.arg(tag_ids)::TEXT[])
+)
+INSERT INTO "story_tag" (story_id, tag_id)
+SELECT sqlc.arg(story_id), UNNEST(sqlc.arg(tag_ids)::TEXT[])
+ON CONFLICT DO NOTHING;
+
+-- name: ReplaceEventTags :exec
+WITH "removed" AS (
+  DELETE FROM "event_tag"
+  WHERE event_id = sqlc.arg(event_id)
+    AND tag_id <> ALL(sqlc.arg(tag_ids)::TEXT[])
+)
+INSERT INTO "event_tag" (event_id, tag_id)
+SELECT sqlc.arg(event_id), UNNEST(sqlc.arg(tag_ids)::TEXT[])
+ON CONFLICT DO NOTHING;
+
+-- name: ReplaceProjectTags :exec
+WITH "removed" AS (
+  DELETE FROM "project_tag"
+  WHERE project_id = sqlc.arg(project_id)
+    AND tag_id <> ALL(sqlc.arg(tag_ids)::TEXT[])
+)
+INSERT INTO "project_tag" (project_id, tag_id)
+SELECT sqlc.arg(project_id), UNNEST(sqlc.arg(tag_ids)::TEXT[])
+ON CONFLICT DO NOTHING;
+
+-- name: ListStoryTags :many
+SELECT st.story_id AS entity_id, t.slug, t.name
+FROM "story_tag" st
+  INNER JOIN "tag" t ON t.id = st.tag_id
+WHERE st.story_id = ANY(sqlc.arg(story_ids)::TEXT[])
+ORDER BY st.story_id, t.slug;
+
+-- name: ListEventTags :many
+SELECT et.event_id AS entity_id, t.slug, t.name
+FROM "event_tag" et
+  INNER JOIN "tag" t ON t.id = et.tag_id
+WHERE et.event_id = ANY(sqlc.arg(event_ids)::TEXT[])
+ORDER BY et.event_id, t.slug;
+
+-- name: ListProjectTags :many
+SELECT pt.project_id AS entity_id, t.slug, t.name
+FROM "project_tag" pt
+  INNER JOIN "tag" t ON t.id = pt.tag_id
+WHERE pt.project_id = ANY(sqlc.arg(project_ids)::TEXT[])
+ORDER BY pt.project_id, t.slug;
@@ -0,0 +1,42 @@
+-- name: GetUserById :one
+SELECT * FROM "user"
+WHERE id = $1
+  AND deleted_at IS NULL
+LIMIT 1;
+
+-- name: GetSessionById :one
+SELECT * FROM "session"
+WHERE id = $1
+LIMIT 1;
+
+-- name: DeleteStaleSessions :execrows
+DELETE FROM "session"
+WHERE expires_at < NOW()
+  OR (status <> 'logged_in' AND created_at < sqlc.arg(pending_before));
+
+-- name: IsUserSuspended :one
+SELECT EXISTS (
+  SELECT 1 FROM "user"
+  WHERE id = sqlc.arg(id)
+    AND suspended_at IS NOT NULL
+) AS is_suspended;
+
+-- name: SuspendUser :execrows
+UPDATE "user"
+SET suspended_at = NOW(),
+  suspended_reason = sqlc.arg(suspended_reason),
+  updated_at = NOW()
+WHERE id = sqlc.arg(id)
+  AND deleted_at IS NULL;
+
+-- name: LiftUserSuspension :execrows
+UPDATE "user"
+SET suspended_at = NULL,
+  suspended_reason = NULL,
+  updated_at = NOW()
+WHERE id = sqlc.arg(id)
+  AND suspended_at IS NOT NULL;
+
+-- name: DeleteSessionsOfUser :execrows
+DELETE FROM "session"
+WHERE logged_in_user_id = sqlc.arg(logged_in_user_id);
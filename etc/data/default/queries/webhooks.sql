@@ -0,0 +1,138 @@
+-- name: CreateWebhookEndpoint :one
+INSERT INTO "webhook_endpoint" (id, profile_id, url, secret, event_types, description)
+VALUES (sqlc.arg(id), sqlc.arg(profile_id), sqlc.arg(url), sqlc.arg(secret), sqlc.arg(event_types), sqlc.arg(description))
+RETURNING *;
+
+-- name: GetWebhookEndpointById :one
+SELECT * FROM "webhook_endpoint"
+WHERE id = sqlc.arg(id)
+  AND profile_id = sqlc.arg(profile_id)
+  AND deleted_at IS NULL
+LIMIT 1;
+
+-- name: ListWebhookEndpointsByProfileId :many
+SELECT * FROM "webhook_endpoint"
+WHERE profile_id = sqlc.arg(profile_id)
+  AND deleted_at IS NULL
+ORDER BY created_at;
+
+-- name: UpdateWebhookEndpoint :one
+UPDATE "webhook_endpoint"
+SET url = sqlc.arg(url),
+  event_types = sqlc.arg(event_types),
+  description = sqlc.arg(description),
+  is_active = sqlc.arg(is_active),
+  consecutive_failures = CASE WHEN sqlc.arg(is_active) AND NOT is_active THEN 0 ELSE consecutive_failures END,
+  failing_since = CASE WHEN sqlc.arg(is_active) AND NOT is_active THEN NULL ELSE failing_since END,
+  disabled_at = CASE WHEN sqlc.arg(is_active) THEN NULL WHEN is_active THEN NOW() ELSE disabled_at END,
+  disabled_reason = CASE WHEN sqlc.arg(is_active) THEN NULL WHEN is_active THEN 'disabled by the profile' ELSE disabled_reason END,
+  updated_at = NOW()
+WHERE id = sqlc.arg(id)
+  AND profile_id = sqlc.arg(profile_id)
+  AND deleted_at IS NULL
+RETURNING *;
+
+-- name: RotateWebhookEndpointSecret :one
+UPDATE "webhook_endpoint"
+SET secret = sqlc.arg(secret),
+  updated_at = NOW()
+WHERE id = sqlc.arg(id)
+  AND profile_id = sqlc.arg(profile_id)
+  AND deleted_at IS NULL
+RETURNING *;
+
+-- name: DeleteWebhookEndpoint :execrows
+UPDATE "webhook_endpoint"
+SET deleted_at = NOW()
+WHERE id = sqlc.arg(id)
+  AND profile_id = sqlc.arg(profile_id)
+  AND deleted_at IS NULL;
+
+-- name: ListActiveWebhookEndpointsForProfileEvent :many
+SELECT * FROM "webhook_endpoint"
+WHERE profile_id = sqlc.arg(profile_id)
+  AND sqlc.arg(event_type)::TEXT = ANY(string_to_array(event_types, ','))
+  AND is_active = TRUE
+  AND deleted_at IS NULL;
+
+-- name: ListActiveWebhookEndpointsForQuestionEvent :many
+SELECT we.* FROM "webhook_endpoint" we
+  INNER JOIN "user" u ON u.individual_profile_id = we.profile_id AND u.deleted_at IS NULL
+  INNER JOIN "question" q ON q.user_id = u.id
+WHERE q.id = sqlc.arg(question_id)
+  AND sqlc.arg(event_type)::TEXT = ANY(string_to_array(we.event_types, ','))
+  AND we.is_active = TRUE
+  AND we.deleted_at IS NULL;
+
+-- name: RecordWebhookEndpointSuccess :exec
+UPDATE "webhook_endpoint"
+SET consecutive_failures = 0,
+  failing_since = NULL
+WHERE id = sqlc.arg(id)
+  AND consecutive_failures > 0;
+
+-- name: RecordWebhookEndpointFailure :one
+UPDATE "webhook_endpoint"
+SET consecutive_failures = consecutive_failures + 1,
+  failing_since = COALESCE(failing_since, NOW())
+WHERE id = sqlc.arg(id)
+RETURNING *;
+
+-- name: DisableWebhookEndpoint :exec
+UPDATE "webhook_endpoint"
+SET is_active = FALSE,
+  disabled_at = NOW(),
+  disabled_reason = sqlc.arg(disabled_reason)
+WHERE id = sqlc.arg(id)
+  AND is_active = TRUE;
+
+-- name: InsertWebhookDelivery :exec
+INSERT INTO "webhook_delivery" (id, endpoint_id, event_id, event_type, payload, replay_of)
+VALUES (sqlc.arg(id), sqlc.arg(endpoint_id), sqlc.arg(event_id), sqlc.arg(event_type), sqlc.arg(payload), sqlc.arg(replay_of))
+ON CONFLICT (endpoint_id, event_id) WHERE replay_of IS NULL DO NOTHING;
+
+-- name: ClaimWebhookDeliveries :many
+UPDATE "webhook_delivery"
+SET next_attempt_at = sqlc.arg(lease_until)
+WHERE id IN (
+  SELECT d.id FROM "webhook_delivery" d
+    INNER JOIN "webhook_endpoint" e ON e.id = d.endpoint_id
+  WHERE d.status = 'pending'
+    AND d.next_attempt_at <= NOW()
+    AND e.is_active = TRUE
+    AND e.deleted_at IS NULL
+  ORDER BY d.next_attempt_at
+  LIMIT sqlc.arg(limit_count)
+  FOR UPDATE OF d SKIP LOCKED
+)
+RETURNING *;
+
+-- name: GetWebhookDeliveryById :one
+SELECT * FROM "webhook_delivery"
+WHERE id = sqlc.arg(id)
+  AND endpoint_id = sqlc.arg(endpoint_id)
+LIMIT 1;
+
+-- name: ListWebhookDeliveriesByEndpointId :many
+SELECT * FROM "webhook_delivery"
+WHERE endpoint_id = sqlc.arg(endpoint_id)
+ORDER BY created_at DESC, id DESC
+LIMIT sqlc.arg(limit_count)
+OFFSET sqlc.arg(offset_count);
+
+-- name: MarkWebhookDeliveryAttempt :exec
+UPDATE "webhook_delivery"
+SET status = sqlc.arg(status),
+  attempts = attempts + 1,
+  next_attempt_at = sqlc.arg(next_attempt_at),
+  response_status = sqlc.arg(response_status),
+  response_body = sqlc.arg(response_body),
+  error = sqlc.arg(error),
+  duration_ms = sqlc.arg(duration_ms),
+  delivered_at = CASE WHEN sqlc.arg(status) = 'succeeded' THEN NOW() ELSE delivered_at END
+WHERE id = sqlc.arg(id);
+
+-- name: GetWebhookEndpointForDelivery :one
+SELECT * FROM "webhook_endpoint"
+WHERE id = sqlc.arg(id)
+LIMIT 1;
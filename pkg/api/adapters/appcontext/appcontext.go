@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"os"
 
+	"github.com/eser/ajan/cachefx"
 	"github.com/eser/ajan/configfx"
 	"github.com/eser/ajan/datafx"
 	"github.com/eser/ajan/logfx"
@@ -21,6 +22,7 @@ type AppContext struct {
 	Metrics *metricsfx.MetricsProvider
 	Data    *datafx.Registry
 	Queue   *queuefx.Registry
+	Cache   *cachefx.Registry
 }
 
 func NewAppContext(ctx context.Context) (*AppContext, error) {
@@ -66,5 +68,13 @@ func NewAppContext(ctx context.Context) (*AppContext, error) {
 		return nil, fmt.Errorf("%w: %w", ErrInitFailed, err)
 	}
 
+	// cache
+	appContext.Cache = cachefx.NewRegistry(appContext.Logger)
+
+	err = appContext.Cache.LoadFromConfig(ctx, &appContext.Config.Cache)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInitFailed, err)
+	}
+
 	return appContext, nil
 }
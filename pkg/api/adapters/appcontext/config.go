@@ -1,6 +1,22 @@
 package appcontext
 
 import (
+	"github.com/eser/acik.io/pkg/api/adapters/mail"
+	"github.com/eser/acik.io/pkg/api/adapters/worker"
+	"github.com/eser/acik.io/pkg/api/business/digest"
+	"github.com/eser/acik.io/pkg/api/business/events"
+	"github.com/eser/acik.io/pkg/api/business/home"
+	"github.com/eser/acik.io/pkg/api/business/idempotency"
+	"github.com/eser/acik.io/pkg/api/business/media"
+	"github.com/eser/acik.io/pkg/api/business/notifications"
+	"github.com/eser/acik.io/pkg/api/business/outbox"
+	"github.com/eser/acik.io/pkg/api/business/projects"
+	"github.com/eser/acik.io/pkg/api/business/questions"
+	"github.com/eser/acik.io/pkg/api/business/ratelimit"
+	"github.com/eser/acik.io/pkg/api/business/readcache"
+	"github.com/eser/acik.io/pkg/api/business/schedule"
+	"github.com/eser/acik.io/pkg/api/business/stories"
+	"github.com/eser/acik.io/pkg/api/business/webhooks"
 	"github.com/eser/ajan"
 )
 
@@ -11,5 +27,21 @@ type FeatureFlags struct {
 type AppConfig struct {
 	ajan.BaseConfig
 
-	Features FeatureFlags `conf:"FEATURES"`
+	Features      FeatureFlags         `conf:"FEATURES"`
+	Events        events.Config        `conf:"EVENTS"`
+	Stories       stories.Config       `conf:"STORIES"`
+	Home          home.Config          `conf:"HOME"`
+	Outbox        outbox.Config        `conf:"OUTBOX"`
+	Work          worker.Config        `conf:"WORK"`
+	Schedule      schedule.Config      `conf:"SCHEDULE"`
+	Webhooks      webhooks.Config      `conf:"WEBHOOKS"`
+	Mail          mail.Config          `conf:"MAIL"`
+	Notifications notifications.Config `conf:"NOTIFICATIONS"`
+	Digest        digest.Config        `conf:"DIGEST"`
+	Projects      projects.Config      `conf:"PROJECTS"`
+	Media         media.Config         `conf:"MEDIA"`
+	Questions     questions.Config     `conf:"QUESTIONS"`
+	RateLimit     ratelimit.Config     `conf:"RATE_LIMIT"`
+	Idempotency   idempotency.Config   `conf:"IDEMPOTENCY"`
+	ReadCache     readcache.Config     `conf:"READ_CACHE"`
 }
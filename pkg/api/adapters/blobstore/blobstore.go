@@ -0,0 +1,24 @@
+package blobstore
+
+import (
+	"fmt"
+
+	"github.com/eser/acik.io/pkg/api/business/media"
+)
+
+const (
+	BackendLocal = "local"
+	BackendS3    = "s3"
+)
+
+// NewFromConfig returns the blob store the configuration selects.
+func NewFromConfig(config *media.Config) (media.BlobStore, error) { //nolint:ireturn
+	switch config.Backend {
+	case BackendLocal:
+		return NewLocalStore(config.LocalDir), nil
+	case BackendS3:
+		return NewS3Store(config), nil
+	default:
+		return nil, fmt.Errorf("%w: %s", media.ErrUnknownBlobStore, config.Backend)
+	}
+}
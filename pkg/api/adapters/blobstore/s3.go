@@ -0,0 +1,206 @@
+package blobstore
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/eser/acik.io/pkg/api/business/media"
+)
+
+const (
+	amzDateLayout  = "20060102T150405Z"
+	amzDayLayout   = "20060102"
+	signingService = "s3"
+
+	maxBlobSize = media.MaxUploadSize
+)
+
+var ErrUnexpectedStatus = errors.New("unexpected status")
+
+// S3Store keeps blobs in a bucket of an S3-compatible service such as MinIO.
+// Requests are signed with AWS Signature Version 4.
+type S3Store struct {
+	httpClient *http.Client
+	endpoint   *url.URL
+	region     string
+	bucket     string
+	accessKey  string
+	secretKey  string
+	pathStyle  bool
+}
+
+func NewS3Store(config *media.Config) *S3Store {
+	endpoint, err := url.Parse(strings.TrimRight(config.S3Endpoint, "/"))
+	if err != nil {
+		endpoint = &url.URL{} //nolint:exhaustruct
+	}
+
+	return &S3Store{
+		httpClient: &http.Client{Timeout: config.RequestTimeout}, //nolint:exhaustruct
+		endpoint:   endpoint,
+		region:     config.S3Region,
+		bucket:     config.S3Bucket,
+		accessKey:  config.S3AccessKey,
+		secretKey:  config.S3SecretKey,
+		pathStyle:  config.S3PathStyle,
+	}
+}
+
+func (s *S3Store) Put(ctx context.Context, key string, contentType string, data []byte) error {
+	resp, err := s.do(ctx, http.MethodPut, key, data, func(req *http.Request) {
+		req.Header.Set("Content-Type", contentType)
+		req.Header.Set("Cache-Control", "public, max-age=31536000, immutable")
+	})
+	if err != nil {
+		return err
+	}
+
+	defer resp.Body.Close() //nolint:errcheck
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("%w(key: %s, status: %d)", ErrUnexpectedStatus, key, resp.StatusCode)
+	}
+
+	return nil
+}
+
+func (s *S3Store) Get(ctx context.Context, key string) (*media.Blob, error) {
+	resp, err := s.do(ctx, http.MethodGet, key, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	defer resp.Body.Close() //nolint:errcheck
+
+	switch {
+	case resp.StatusCode == http.StatusNotFound:
+		return nil, fmt.Errorf("%w(key: %s)", media.ErrBlobNotFound, key)
+	case resp.StatusCode != http.StatusOK:
+		return nil, fmt.Errorf("%w(key: %s, status: %d)", ErrUnexpectedStatus, key, resp.StatusCode)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxBlobSize))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+
+	return &media.Blob{ContentType: resp.Header.Get("Content-Type"), Data: data}, nil
+}
+
+func (s *S3Store) Exists(ctx context.Context, key string) (bool, error) {
+	resp, err := s.do(ctx, http.MethodHead, key, nil, nil)
+	if err != nil {
+		return false, err
+	}
+
+	defer resp.Body.Close() //nolint:errcheck
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return true, nil
+	case http.StatusNotFound:
+		return false, nil
+	default:
+		return false, fmt.Errorf("%w(key: %s, status: %d)", ErrUnexpectedStatus, key, resp.StatusCode)
+	}
+}
+
+func (s *S3Store) do(
+	ctx context.Context,
+	method string,
+	key string,
+	body []byte,
+	prepare func(req *http.Request),
+) (*http.Response, error) {
+	target := *s.endpoint
+
+	if s.pathStyle {
+		target.Path = "/" + s.bucket + "/" + key
+	} else {
+		target.Host = s.bucket + "." + target.Host
+		target.Path = "/" + key
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, target.String(), bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	if prepare != nil {
+		prepare(req)
+	}
+
+	s.sign(req, body, time.Now().UTC())
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send request: %w", err)
+	}
+
+	return resp, nil
+}
+
+// sign adds the AWS Signature Version 4 headers to a request.
+func (s *S3Store) sign(req *http.Request, body []byte, now time.Time) {
+	payloadHash := sha256Hex(body)
+	amzDate := now.Format(amzDateLayout)
+	day := now.Format(amzDayLayout)
+
+	req.Header.Set("X-Amz-Date", amzDate)
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+
+	signedHeaders := "host;x-amz-content-sha256;x-amz-date"
+	canonicalHeaders := "host:" + req.URL.Host + "\n" +
+		"x-amz-content-sha256:" + payloadHash + "\n" +
+		"x-amz-date:" + amzDate + "\n"
+
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		req.URL.EscapedPath(),
+		req.URL.RawQuery,
+		canonicalHeaders,
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+
+	scope := day + "/" + s.region + "/" + signingService + "/aws4_request"
+	stringToSign := "AWS4-HMAC-SHA256\n" + amzDate + "\n" + scope + "\n" + sha256Hex([]byte(canonicalRequest))
+
+	signingKey := hmacSha256([]byte("AWS4"+s.secretKey), day)
+	signingKey = hmacSha256(signingKey, s.region)
+	signingKey = hmacSha256(signingKey, signingService)
+	signingKey = hmacSha256(signingKey, "aws4_request")
+
+	signature := hex.EncodeToString(hmacSha256(signingKey, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf(
+		"AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		s.accessKey,
+		scope,
+		signedHeaders,
+		signature,
+	))
+}
+
+func sha256Hex(data []byte) string {
+	sum := sha256.Sum256(data)
+
+	return hex.EncodeToString(sum[:])
+}
+
+func hmacSha256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+
+	return mac.Sum(nil)
+}
@@ -0,0 +1,31 @@
+package digest
+
+import (
+	"context"
+
+	"github.com/eser/acik.io/pkg/api/adapters/mail"
+	"github.com/eser/acik.io/pkg/api/business/digest"
+)
+
+type Mailer interface {
+	Send(ctx context.Context, message *mail.Message) error
+}
+
+// MailerAdapter sends digests through a mailer.
+type MailerAdapter struct {
+	mailer Mailer
+}
+
+func NewMailerAdapter(mailer Mailer) *MailerAdapter {
+	return &MailerAdapter{mailer: mailer}
+}
+
+func (a *MailerAdapter) Send(ctx context.Context, email *digest.Email) error {
+	return a.mailer.Send(ctx, &mail.Message{ //nolint:wrapcheck
+		Headers: email.Headers,
+		To:      email.To,
+		Subject: email.Subject,
+		Text:    email.Text,
+		Html:    email.Html,
+	})
+}
@@ -0,0 +1,70 @@
+package digest
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	htmltemplate "html/template"
+	"strings"
+	texttemplate "text/template"
+	"time"
+
+	"github.com/eser/acik.io/pkg/api/business/digest"
+)
+
+//go:embed templates/*.tmpl
+var templates embed.FS
+
+// TemplateRenderer renders digests with the embedded templates, as HTML and
+// as a plain text alternative.
+type TemplateRenderer struct {
+	html *htmltemplate.Template
+	text *texttemplate.Template
+}
+
+func NewTemplateRenderer() (*TemplateRenderer, error) {
+	funcs := map[string]any{
+		"link": link,
+		"date": date,
+	}
+
+	html, err := htmltemplate.New("digest.html.tmpl").Funcs(funcs).ParseFS(templates, "templates/digest.html.tmpl")
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse html template: %w", err)
+	}
+
+	text, err := texttemplate.New("digest.txt.tmpl").Funcs(funcs).ParseFS(templates, "templates/digest.txt.tmpl")
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse text template: %w", err)
+	}
+
+	return &TemplateRenderer{html: html, text: text}, nil
+}
+
+func (r *TemplateRenderer) Render(d *digest.Digest) (*digest.Rendered, error) {
+	var html, text bytes.Buffer
+
+	err := r.html.Execute(&html, d)
+	if err != nil {
+		return nil, fmt.Errorf("failed to render html: %w", err)
+	}
+
+	err = r.text.Execute(&text, d)
+	if err != nil {
+		return nil, fmt.Errorf("failed to render text: %w", err)
+	}
+
+	return &digest.Rendered{
+		Subject: "Your weekly digest: " + d.Period.Key,
+		Text:    text.String(),
+		Html:    html.String(),
+	}, nil
+}
+
+func link(siteUrl string, path ...string) string {
+	return strings.TrimRight(siteUrl, "/") + strings.Join(path, "")
+}
+
+func date(t time.Time) string {
+	return t.UTC().Format("2 Jan 2006 15:04 MST")
+}
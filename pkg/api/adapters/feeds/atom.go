@@ -0,0 +1,84 @@
+package feeds
+
+import (
+	"encoding/xml"
+	"time"
+
+	"github.com/eser/acik.io/pkg/api/business/stories"
+)
+
+type atomDocument struct {
+	XMLName  xml.Name    `xml:"http://www.w3.org/2005/Atom feed"`
+	Id       string      `xml:"id"`
+	Title    string      `xml:"title"`
+	Subtitle string      `xml:"subtitle,omitempty"`
+	Updated  string      `xml:"updated"`
+	Author   *atomAuthor `xml:"author,omitempty"`
+	Links    []atomLink  `xml:"link"`
+	Entries  []atomEntry `xml:"entry"`
+}
+
+type atomAuthor struct {
+	Name string `xml:"name"`
+}
+
+type atomLink struct {
+	Href string `xml:"href,attr"`
+	Rel  string `xml:"rel,attr,omitempty"`
+	Type string `xml:"type,attr,omitempty"`
+}
+
+type atomText struct {
+	Type  string `xml:"type,attr"`
+	Value string `xml:",chardata"`
+}
+
+type atomEntry struct {
+	Id        string     `xml:"id"`
+	Title     string     `xml:"title"`
+	Published string     `xml:"published"`
+	Updated   string     `xml:"updated"`
+	Links     []atomLink `xml:"link"`
+	Summary   atomText   `xml:"summary"`
+	Content   *atomText  `xml:"content,omitempty"`
+}
+
+func EncodeAtom(feed *stories.Feed) ([]byte, error) {
+	document := atomDocument{
+		XMLName:  xml.Name{Space: "http://www.w3.org/2005/Atom", Local: "feed"},
+		Id:       feed.Id,
+		Title:    feed.Title,
+		Subtitle: feed.Description,
+		Updated:  atomTime(feed.UpdatedAt),
+		Author:   &atomAuthor{Name: feed.AuthorName},
+		Links: []atomLink{
+			{Href: feed.Link, Rel: "alternate", Type: "text/html"},
+			{Href: feed.FeedUrl, Rel: "self", Type: "application/atom+xml"},
+		},
+		Entries: make([]atomEntry, 0, len(feed.Entries)),
+	}
+
+	for _, entry := range feed.Entries {
+		item := atomEntry{
+			Id:        entry.Id,
+			Title:     entry.Title,
+			Published: atomTime(entry.PublishedAt),
+			Updated:   atomTime(entry.UpdatedAt),
+			Links:     []atomLink{{Href: entry.Link, Rel: "alternate", Type: "text/html"}},
+			Summary:   atomText{Type: "text", Value: entry.Summary},
+			Content:   nil,
+		}
+
+		if entry.ContentHtml != "" {
+			item.Content = &atomText{Type: "html", Value: entry.ContentHtml}
+		}
+
+		document.Entries = append(document.Entries, item)
+	}
+
+	return marshalXml(document)
+}
+
+func atomTime(value time.Time) string {
+	return value.UTC().Format(time.RFC3339)
+}
@@ -0,0 +1,45 @@
+package feeds
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/eser/acik.io/pkg/api/business/stories"
+)
+
+const (
+	FormatRss  Format = "rss"
+	FormatAtom Format = "atom"
+	FormatJson Format = "json"
+)
+
+var ErrUnknownFormat = errors.New("unknown feed format")
+
+type Format string
+
+func (f Format) ContentType() string {
+	switch f {
+	case FormatRss:
+		return "application/rss+xml; charset=utf-8"
+	case FormatAtom:
+		return "application/atom+xml; charset=utf-8"
+	case FormatJson:
+		return "application/feed+json; charset=utf-8"
+	default:
+		return "application/octet-stream"
+	}
+}
+
+// Encode serializes the feed in the given syndication format.
+func Encode(format Format, feed *stories.Feed) ([]byte, error) {
+	switch format {
+	case FormatRss:
+		return EncodeRss(feed)
+	case FormatAtom:
+		return EncodeAtom(feed)
+	case FormatJson:
+		return EncodeJsonFeed(feed)
+	default:
+		return nil, fmt.Errorf("%w: %q", ErrUnknownFormat, format)
+	}
+}
@@ -0,0 +1,188 @@
+package feeds_test
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/eser/acik.io/pkg/api/adapters/feeds"
+	"github.com/eser/acik.io/pkg/api/business/stories"
+)
+
+func newFeed() *stories.Feed {
+	published := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	return &stories.Feed{
+		UpdatedAt:   published,
+		Id:          "https://acik.io/eser",
+		Title:       "Eser <& Co>",
+		Description: "Stories",
+		Link:        "https://acik.io/eser",
+		FeedUrl:     "https://acik.io/eser/feed",
+		AuthorName:  "Eser",
+		Entries: []*stories.FeedEntry{
+			{
+				PublishedAt:     published,
+				UpdatedAt:       published,
+				Id:              "story-1",
+				Title:           "First",
+				Link:            "https://acik.io/eser/stories/first",
+				Summary:         "first summary",
+				ContentHtml:     "<p>first ]]> body</p>",
+				StoryPictureUri: "",
+			},
+			{
+				PublishedAt:     published,
+				UpdatedAt:       published,
+				Id:              "story-2",
+				Title:           "Second",
+				Link:            "https://acik.io/eser/stories/second",
+				Summary:         "second summary",
+				ContentHtml:     "",
+				StoryPictureUri: "",
+			},
+		},
+	}
+}
+
+func TestEncodeRss(t *testing.T) {
+	t.Parallel()
+
+	encoded, err := feeds.Encode(feeds.FormatRss, newFeed())
+	if err != nil {
+		t.Fatalf("encoding: %v", err)
+	}
+
+	var document struct {
+		Channel struct {
+			Title         string `xml:"title"`
+			LastBuildDate string `xml:"lastBuildDate"`
+			Items         []struct {
+				Guid    string `xml:"guid"`
+				Content string `xml:"http://purl.org/rss/1.0/modules/content/ encoded"`
+			} `xml:"item"`
+		} `xml:"channel"`
+	}
+
+	err = xml.Unmarshal(encoded, &document)
+	if err != nil {
+		t.Fatalf("parsing %s: %v", encoded, err)
+	}
+
+	if document.Channel.Title != "Eser <& Co>" {
+		t.Errorf("got title %q, want it escaped and read back as is", document.Channel.Title)
+	}
+
+	if document.Channel.LastBuildDate != "Fri, 02 Jan 2026 03:04:05 GMT" {
+		t.Errorf("got lastBuildDate %q, want an RFC 1123 date", document.Channel.LastBuildDate)
+	}
+
+	if len(document.Channel.Items) != 2 { //nolint:mnd
+		t.Fatalf("got %d items, want 2", len(document.Channel.Items))
+	}
+
+	if got := document.Channel.Items[0].Content; got != "<p>first ]]> body</p>" {
+		t.Errorf("got content %q, want the HTML kept intact in CDATA", got)
+	}
+
+	if got := document.Channel.Items[1].Content; got != "" {
+		t.Errorf("got content %q for an entry without HTML, want none", got)
+	}
+}
+
+func TestEncodeAtom(t *testing.T) {
+	t.Parallel()
+
+	encoded, err := feeds.Encode(feeds.FormatAtom, newFeed())
+	if err != nil {
+		t.Fatalf("encoding: %v", err)
+	}
+
+	var document struct {
+		XMLName xml.Name `xml:"http://www.w3.org/2005/Atom feed"`
+		Updated string   `xml:"updated"`
+		Entries []struct {
+			Id      string `xml:"id"`
+			Content *struct {
+				Type  string `xml:"type,attr"`
+				Value string `xml:",chardata"`
+			} `xml:"content"`
+		} `xml:"entry"`
+	}
+
+	err = xml.Unmarshal(encoded, &document)
+	if err != nil {
+		t.Fatalf("parsing %s: %v", encoded, err)
+	}
+
+	if document.Updated != "2026-01-02T03:04:05Z" {
+		t.Errorf("got updated %q, want an RFC 3339 date", document.Updated)
+	}
+
+	if len(document.Entries) != 2 { //nolint:mnd
+		t.Fatalf("got %d entries, want 2", len(document.Entries))
+	}
+
+	if content := document.Entries[0].Content; content == nil || content.Type != "html" || content.Value != "<p>first ]]> body</p>" {
+		t.Errorf("got content %+v, want the escaped HTML", content)
+	}
+
+	if content := document.Entries[1].Content; content != nil {
+		t.Errorf("got content %+v for an entry without HTML, want none", content)
+	}
+}
+
+func TestEncodeJsonFeed(t *testing.T) {
+	t.Parallel()
+
+	encoded, err := feeds.Encode(feeds.FormatJson, newFeed())
+	if err != nil {
+		t.Fatalf("encoding: %v", err)
+	}
+
+	var document struct {
+		Version string `json:"version"`
+		Items   []struct {
+			ContentHtml   string `json:"content_html"`
+			ContentText   string `json:"content_text"`
+			DatePublished string `json:"date_published"`
+		} `json:"items"`
+	}
+
+	err = json.Unmarshal(encoded, &document)
+	if err != nil {
+		t.Fatalf("parsing %s: %v", encoded, err)
+	}
+
+	if document.Version != "https://jsonfeed.org/version/1.1" {
+		t.Errorf("got version %q, want JSON Feed 1.1", document.Version)
+	}
+
+	if len(document.Items) != 2 { //nolint:mnd
+		t.Fatalf("got %d items, want 2", len(document.Items))
+	}
+
+	if item := document.Items[0]; item.ContentHtml != "<p>first ]]> body</p>" || item.DatePublished != "2026-01-02T03:04:05Z" {
+		t.Errorf("got item %+v, want the HTML and an RFC 3339 date", item)
+	}
+
+	if item := document.Items[1]; item.ContentHtml != "" || item.ContentText != "second summary" {
+		t.Errorf("got item %+v, want the summary as content_text", item)
+	}
+}
+
+func TestEncodeUnknownFormat(t *testing.T) {
+	t.Parallel()
+
+	_, err := feeds.Encode(feeds.Format("csv"), newFeed())
+	if !errors.Is(err, feeds.ErrUnknownFormat) {
+		t.Errorf("got %v, want %v", err, feeds.ErrUnknownFormat)
+	}
+
+	if contentType := feeds.Format("csv").ContentType(); !strings.HasPrefix(contentType, "application/octet-stream") {
+		t.Errorf("got content type %q for an unknown format", contentType)
+	}
+}
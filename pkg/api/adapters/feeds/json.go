@@ -0,0 +1,77 @@
+package feeds
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/eser/acik.io/pkg/api/business/stories"
+)
+
+const jsonFeedVersion = "https://jsonfeed.org/version/1.1"
+
+type jsonFeedDocument struct {
+	Version     string           `json:"version"`
+	Title       string           `json:"title"`
+	HomePageUrl string           `json:"home_page_url"`
+	FeedUrl     string           `json:"feed_url"`
+	Description string           `json:"description,omitempty"`
+	Authors     []jsonFeedAuthor `json:"authors,omitempty"`
+	Items       []jsonFeedItem   `json:"items"`
+}
+
+type jsonFeedAuthor struct {
+	Name string `json:"name"`
+}
+
+type jsonFeedItem struct {
+	Id            string `json:"id"`
+	Url           string `json:"url"`
+	Title         string `json:"title"`
+	Summary       string `json:"summary,omitempty"`
+	ContentHtml   string `json:"content_html,omitempty"`
+	ContentText   string `json:"content_text,omitempty"`
+	Image         string `json:"image,omitempty"`
+	DatePublished string `json:"date_published"`
+	DateModified  string `json:"date_modified"`
+}
+
+func EncodeJsonFeed(feed *stories.Feed) ([]byte, error) {
+	document := jsonFeedDocument{
+		Version:     jsonFeedVersion,
+		Title:       feed.Title,
+		HomePageUrl: feed.Link,
+		FeedUrl:     feed.FeedUrl,
+		Description: feed.Description,
+		Authors:     []jsonFeedAuthor{{Name: feed.AuthorName}},
+		Items:       make([]jsonFeedItem, 0, len(feed.Entries)),
+	}
+
+	for _, entry := range feed.Entries {
+		item := jsonFeedItem{
+			Id:            entry.Id,
+			Url:           entry.Link,
+			Title:         entry.Title,
+			Summary:       entry.Summary,
+			ContentHtml:   entry.ContentHtml,
+			ContentText:   "",
+			Image:         entry.StoryPictureUri,
+			DatePublished: entry.PublishedAt.UTC().Format(time.RFC3339),
+			DateModified:  entry.UpdatedAt.UTC().Format(time.RFC3339),
+		}
+
+		// every item needs either content_html or content_text.
+		if item.ContentHtml == "" {
+			item.ContentText = entry.Summary
+		}
+
+		document.Items = append(document.Items, item)
+	}
+
+	encoded, err := json.Marshal(document)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode feed: %w", err)
+	}
+
+	return encoded, nil
+}
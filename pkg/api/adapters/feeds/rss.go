@@ -0,0 +1,95 @@
+package feeds
+
+import (
+	"encoding/xml"
+	"fmt"
+	"net/http"
+
+	"github.com/eser/acik.io/pkg/api/business/stories"
+)
+
+type rssDocument struct {
+	XMLName   xml.Name   `xml:"rss"`
+	Version   string     `xml:"version,attr"`
+	AtomNs    string     `xml:"xmlns:atom,attr"`
+	ContentNs string     `xml:"xmlns:content,attr"`
+	Channel   rssChannel `xml:"channel"`
+}
+
+type rssChannel struct {
+	Title         string    `xml:"title"`
+	Link          string    `xml:"link"`
+	Description   string    `xml:"description"`
+	LastBuildDate string    `xml:"lastBuildDate"`
+	AtomLink      rssLink   `xml:"atom:link"`
+	Items         []rssItem `xml:"item"`
+}
+
+type rssLink struct {
+	Href string `xml:"href,attr"`
+	Rel  string `xml:"rel,attr"`
+	Type string `xml:"type,attr"`
+}
+
+type rssGuid struct {
+	Value       string `xml:",chardata"`
+	IsPermaLink bool   `xml:"isPermaLink,attr"`
+}
+
+type rssItem struct {
+	Title       string    `xml:"title"`
+	Link        string    `xml:"link"`
+	Guid        rssGuid   `xml:"guid"`
+	PubDate     string    `xml:"pubDate"`
+	Description string    `xml:"description"`
+	Content     *rssCdata `xml:"content:encoded,omitempty"`
+}
+
+type rssCdata struct {
+	Value string `xml:",cdata"`
+}
+
+func EncodeRss(feed *stories.Feed) ([]byte, error) {
+	document := rssDocument{
+		XMLName:   xml.Name{Space: "", Local: "rss"},
+		Version:   "2.0",
+		AtomNs:    "http://www.w3.org/2005/Atom",
+		ContentNs: "http://purl.org/rss/1.0/modules/content/",
+		Channel: rssChannel{
+			Title:         feed.Title,
+			Link:          feed.Link,
+			Description:   feed.Description,
+			LastBuildDate: feed.UpdatedAt.UTC().Format(http.TimeFormat),
+			AtomLink:      rssLink{Href: feed.FeedUrl, Rel: "self", Type: "application/rss+xml"},
+			Items:         make([]rssItem, 0, len(feed.Entries)),
+		},
+	}
+
+	for _, entry := range feed.Entries {
+		item := rssItem{
+			Title:       entry.Title,
+			Link:        entry.Link,
+			Guid:        rssGuid{Value: entry.Id, IsPermaLink: false},
+			PubDate:     entry.PublishedAt.UTC().Format(http.TimeFormat),
+			Description: entry.Summary,
+			Content:     nil,
+		}
+
+		if entry.ContentHtml != "" {
+			item.Content = &rssCdata{Value: entry.ContentHtml}
+		}
+
+		document.Channel.Items = append(document.Channel.Items, item)
+	}
+
+	return marshalXml(document)
+}
+
+func marshalXml(document any) ([]byte, error) {
+	encoded, err := xml.MarshalIndent(document, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode feed: %w", err)
+	}
+
+	return append([]byte(xml.Header), encoded...), nil
+}
@@ -0,0 +1,123 @@
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/eser/acik.io/pkg/api/business/projects"
+)
+
+const maxResponseSize = 1 << 20
+
+var ErrUnexpectedStatus = errors.New("unexpected status")
+
+// Client fetches repository metadata from the GitHub REST API.
+type Client struct {
+	httpClient *http.Client
+	baseUrl    string
+	token      string
+}
+
+func NewClient(config *projects.Config) *Client {
+	return &Client{
+		httpClient: &http.Client{Timeout: config.RequestTimeout}, //nolint:exhaustruct
+		baseUrl:    strings.TrimRight(config.GithubApiUrl, "/"),
+		token:      config.GithubToken,
+	}
+}
+
+type repositoryResponse struct {
+	Language        string `json:"language"`
+	DefaultBranch   string `json:"default_branch"`
+	StargazersCount int32  `json:"stargazers_count"`
+}
+
+type commitResponse struct {
+	Commit struct {
+		Committer struct {
+			Date time.Time `json:"date"`
+		} `json:"committer"`
+	} `json:"commit"`
+}
+
+// FetchRepository returns the stars and the language of a repository, and the
+// time of the last commit on its default branch.
+func (c *Client) FetchRepository(
+	ctx context.Context,
+	repository *projects.RepositoryRef,
+) (*projects.RepositoryMetadata, error) {
+	path := "/repos/" + url.PathEscape(repository.Owner) + "/" + url.PathEscape(repository.Name)
+
+	var repo repositoryResponse
+
+	err := c.get(ctx, path, &repo)
+	if err != nil {
+		return nil, err
+	}
+
+	metadata := &projects.RepositoryMetadata{ //nolint:exhaustruct
+		Stars:    repo.StargazersCount,
+		Language: repo.Language,
+	}
+
+	// empty repositories have no default branch to read a commit of.
+	if repo.DefaultBranch == "" {
+		return metadata, nil
+	}
+
+	var commit commitResponse
+
+	err = c.get(ctx, path+"/commits/"+url.PathEscape(repo.DefaultBranch), &commit)
+	if err != nil && !errors.Is(err, projects.ErrRepositoryNotFound) {
+		return nil, err
+	}
+
+	metadata.LastCommitAt = commit.Commit.Committer.Date
+
+	return metadata, nil
+}
+
+func (c *Client) get(ctx context.Context, path string, target any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseUrl+path, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("Accept", "application/vnd.github+json")
+	req.Header.Set("X-Github-Api-Version", "2022-11-28")
+
+	if c.token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send request: %w", err)
+	}
+
+	defer resp.Body.Close() //nolint:errcheck
+
+	switch {
+	case resp.StatusCode == http.StatusNotFound,
+		resp.StatusCode == http.StatusGone,
+		// returned for empty repositories.
+		resp.StatusCode == http.StatusConflict:
+		return fmt.Errorf("%w(path: %s)", projects.ErrRepositoryNotFound, path)
+	case resp.StatusCode != http.StatusOK:
+		return fmt.Errorf("%w(path: %s, status: %d)", ErrUnexpectedStatus, path, resp.StatusCode)
+	}
+
+	err = json.NewDecoder(io.LimitReader(resp.Body, maxResponseSize)).Decode(target)
+	if err != nil {
+		return fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	return nil
+}
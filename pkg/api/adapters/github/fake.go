@@ -0,0 +1,78 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/eser/acik.io/pkg/api/business/projects"
+)
+
+// FakeClient serves repository metadata from memory, for tests and for
+// running without network access. Unknown repositories are not found.
+type FakeClient struct {
+	repositories map[string]*projects.RepositoryMetadata
+	failures     map[string]error
+	calls        []string
+	mu           sync.Mutex
+}
+
+func NewFakeClient() *FakeClient {
+	return &FakeClient{
+		repositories: make(map[string]*projects.RepositoryMetadata),
+		failures:     make(map[string]error),
+		calls:        make([]string, 0),
+		mu:           sync.Mutex{},
+	}
+}
+
+// Set registers the metadata of a repository, given as "owner/name".
+func (c *FakeClient) Set(fullName string, metadata *projects.RepositoryMetadata) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.repositories[strings.ToLower(fullName)] = metadata
+}
+
+// Fail makes fetching a repository fail with err, as the API does when the
+// rate limit is exceeded.
+func (c *FakeClient) Fail(fullName string, err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.failures[strings.ToLower(fullName)] = err
+}
+
+// Calls returns the repositories fetched so far, as "owner/name".
+func (c *FakeClient) Calls() []string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return append([]string(nil), c.calls...)
+}
+
+func (c *FakeClient) FetchRepository(
+	_ context.Context,
+	repository *projects.RepositoryRef,
+) (*projects.RepositoryMetadata, error) {
+	fullName := repository.Owner + "/" + repository.Name
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.calls = append(c.calls, fullName)
+
+	if err, failing := c.failures[strings.ToLower(fullName)]; failing {
+		return nil, err
+	}
+
+	metadata, ok := c.repositories[strings.ToLower(fullName)]
+	if !ok {
+		return nil, fmt.Errorf("%w(repository: %s)", projects.ErrRepositoryNotFound, fullName)
+	}
+
+	copied := *metadata
+
+	return &copied, nil
+}
@@ -0,0 +1,204 @@
+package http
+
+import (
+	"net"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/eser/acik.io/pkg/api/adapters/appcontext"
+	"github.com/eser/acik.io/pkg/api/adapters/storage"
+	"github.com/eser/acik.io/pkg/api/business/audit"
+	"github.com/eser/acik.io/pkg/api/business/slugs"
+	"github.com/eser/ajan/httpfx"
+	"github.com/eser/ajan/httpfx/middlewares"
+	"github.com/eser/ajan/lib"
+)
+
+func RegisterHttpRoutesForAudit(routes *httpfx.Router, appContext *appcontext.AppContext) { //nolint:funlen
+	routes.
+		Route("GET /admin/audit", func(ctx *httpfx.Context) httpfx.Result {
+			_, failure := requireAdmin(ctx)
+			if failure != nil {
+				return *failure
+			}
+
+			filter, err := getAuditFilter(ctx)
+			if err != nil {
+				return ctx.Results.Error(http.StatusBadRequest, []byte(err.Error()))
+			}
+
+			query := ctx.Request.URL.Query()
+			filter.ActorUserId = query.Get("actor")
+			filter.ProfileId = query.Get("profileId")
+			filter.EntityType = query.Get("entityType")
+			filter.EntityId = query.Get("entityId")
+			filter.Action = query.Get("action")
+
+			store, err := storage.NewFromDefault(appContext.Data)
+			if err != nil {
+				return ctx.Results.Error(http.StatusInternalServerError, []byte(err.Error()))
+			}
+
+			records, err := audit.NewService(store).List(ctx.Request.Context(), filter)
+			if err != nil {
+				return ctx.Results.Error(http.StatusInternalServerError, []byte(err.Error()))
+			}
+
+			return ctx.Results.Json(records)
+		}).
+		HasSummary("List audit log").
+		HasDescription("Lists the audit log, the latest change first. Admins only.").
+		HasQueryParameter("actor", "Only changes made by this user id").
+		HasQueryParameter("profileId", "Only changes to entities of this profile id").
+		HasQueryParameter("entityType", "Only changes to entities of this type, such as story or profile").
+		HasQueryParameter("entityId", "Only changes to the entity with this id").
+		HasQueryParameter("action", "Only changes of this action, such as story.updated").
+		HasQueryParameter("since", "Only changes made at or after this time (RFC 3339)").
+		HasQueryParameter("until", "Only changes made before this time (RFC 3339)").
+		HasQueryParameter("limit", "Maximum number of entries to return").
+		HasQueryParameter("offset", "Number of entries to skip").
+		HasResponse(http.StatusOK)
+
+	routes.
+		Route("GET /profiles/{slug}/audit", SlugRedirectMiddleware(appContext, slugs.KindProfile, "/profiles/"), func(ctx *httpfx.Context) httpfx.Result {
+			user, hasUser := GetSessionUser(ctx)
+			if !hasUser {
+				return ctx.Results.Unauthorized([]byte("Authentication required"))
+			}
+
+			filter, err := getAuditFilter(ctx)
+			if err != nil {
+				return ctx.Results.Error(http.StatusBadRequest, []byte(err.Error()))
+			}
+
+			store, err := storage.NewFromDefault(appContext.Data)
+			if err != nil {
+				return ctx.Results.Error(http.StatusInternalServerError, []byte(err.Error()))
+			}
+
+			profileService := newProfilesService(appContext, store)
+
+			profile, err := profileService.GetBySlug(ctx.Request.Context(), ctx.Request.PathValue("slug"))
+			if err != nil {
+				return ctx.Results.Error(http.StatusInternalServerError, []byte(err.Error()))
+			}
+
+			if profile == nil || profile.DeletedAt.Valid {
+				return ctx.Results.NotFound()
+			}
+
+			isAdmin, err := profileService.IsAdmin(ctx.Request.Context(), profile.Id, user.Id)
+			if err != nil {
+				return membershipErrorResult(ctx, err)
+			}
+
+			if !isAdmin {
+				return ctx.Results.Error(http.StatusForbidden, []byte("User is not an admin of the profile"))
+			}
+
+			filter.ProfileId = profile.Id
+
+			records, err := audit.NewService(store).List(ctx.Request.Context(), filter)
+			if err != nil {
+				return ctx.Results.Error(http.StatusInternalServerError, []byte(err.Error()))
+			}
+
+			return ctx.Results.Json(records)
+		}).
+		HasSummary("List profile history").
+		HasDescription("Lists the changes to a profile and to what belongs to it, the latest first. Profile admins only.").
+		HasPathParameter("slug", "The slug of the profile").
+		HasQueryParameter("since", "Only changes made at or after this time (RFC 3339)").
+		HasQueryParameter("until", "Only changes made before this time (RFC 3339)").
+		HasQueryParameter("limit", "Maximum number of entries to return").
+		HasQueryParameter("offset", "Number of entries to skip").
+		HasResponse(http.StatusOK)
+}
+
+// CorrelationIdHeaderMiddleware gives requests without a correlation id one,
+// so the id ajan's correlation middleware responds with is also known to the
+// audit log. It must run before that middleware.
+func CorrelationIdHeaderMiddleware() httpfx.Handler {
+	return func(ctx *httpfx.Context) httpfx.Result {
+		if ctx.Request.Header.Get(middlewares.CorrelationIdHeader) == "" {
+			ctx.Request.Header.Set(middlewares.CorrelationIdHeader, lib.IdsGenerateUnique())
+		}
+
+		return ctx.Next()
+	}
+}
+
+// AuditActorMiddleware attributes the changes made while handling a request
+// to its session user, see audit.WithActor. It must run after the session
+// middleware.
+func AuditActorMiddleware() httpfx.Handler {
+	return func(ctx *httpfx.Context) httpfx.Result {
+		actor := &audit.Actor{
+			UserId:        "",
+			SessionId:     "",
+			Ip:            clientIp(ctx.Request),
+			CorrelationId: ctx.Request.Header.Get(middlewares.CorrelationIdHeader),
+		}
+
+		if user, hasUser := GetSessionUser(ctx); hasUser {
+			actor.UserId = user.Id
+		}
+
+		if session, hasSession := GetSession(ctx); hasSession {
+			actor.SessionId = session.Id
+		}
+
+		ctx.UpdateContext(audit.WithActor(ctx.Request.Context(), actor))
+
+		return ctx.Next()
+	}
+}
+
+func getAuditFilter(ctx *httpfx.Context) (*audit.ListFilter, error) {
+	query := ctx.Request.URL.Query()
+	limit, offset := getPagination(ctx)
+
+	filter := &audit.ListFilter{ //nolint:exhaustruct
+		Limit:  limit,
+		Offset: offset,
+	}
+
+	for name, target := range map[string]**time.Time{"since": &filter.Since, "until": &filter.Until} {
+		if query.Get(name) == "" {
+			continue
+		}
+
+		parsed, err := time.Parse(time.RFC3339, query.Get(name))
+		if err != nil {
+			return nil, err //nolint:wrapcheck
+		}
+
+		*target = &parsed
+	}
+
+	return filter, nil
+}
+
+// requestIdentity tells whose request it is: the session user, or the
+// client address for guests.
+func requestIdentity(ctx *httpfx.Context) string {
+	if user, hasUser := GetSessionUser(ctx); hasUser {
+		return "user:" + user.Id
+	}
+
+	return "ip:" + clientIp(ctx.Request)
+}
+
+// clientIp returns the address of the client as resolved by ajan's address
+// middleware, without the port and the proxies it came through.
+func clientIp(req *http.Request) string {
+	addr, _ := req.Context().Value(middlewares.ClientAddr).(string)
+	addr = strings.TrimSpace(strings.SplitN(addr, ",", 2)[0]) //nolint:mnd
+
+	if host, _, err := net.SplitHostPort(addr); err == nil {
+		return host
+	}
+
+	return addr
+}
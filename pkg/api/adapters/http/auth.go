@@ -0,0 +1,152 @@
+package http
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/eser/acik.io/pkg/api/adapters/storage"
+	"github.com/eser/acik.io/pkg/api/business/users"
+	"github.com/eser/ajan/datafx"
+	"github.com/eser/ajan/httpfx"
+)
+
+const (
+	ContextKeySession     httpfx.ContextKey = "session"
+	ContextKeySessionUser httpfx.ContextKey = "session-user"
+
+	SessionCookieName = "session"
+)
+
+// SessionMiddleware resolves the session given either as a bearer token or as
+// a cookie, and attaches the session and its user to the request context.
+// Requests without any session, or with one that is no longer valid, pass
+// through anonymously; routes requiring a user respond with 401 themselves.
+func SessionMiddleware(dataRegistry *datafx.Registry) httpfx.Handler {
+	return func(ctx *httpfx.Context) httpfx.Result {
+		sessionId, hasSession := getSessionId(ctx.Request)
+		if !hasSession {
+			return ctx.Next()
+		}
+
+		store, err := storage.NewFromDefault(dataRegistry)
+		if err != nil {
+			return ctx.Results.Error(http.StatusInternalServerError, []byte(err.Error()))
+		}
+
+		service := users.NewService(store)
+
+		session, user, err := service.ResolveSession(ctx.Request.Context(), sessionId)
+		if err != nil {
+			if errors.Is(err, users.ErrSessionNotValid) {
+				clearSessionCookie(ctx, sessionId)
+
+				return ctx.Next()
+			}
+
+			return ctx.Results.Error(http.StatusInternalServerError, []byte(err.Error()))
+		}
+
+		newContext := context.WithValue(ctx.Request.Context(), ContextKeySession, session)
+		newContext = context.WithValue(newContext, ContextKeySessionUser, user)
+
+		ctx.UpdateContext(newContext)
+
+		return ctx.Next()
+	}
+}
+
+func GetSessionUser(ctx *httpfx.Context) (*users.User, bool) {
+	user, ok := ctx.Request.Context().Value(ContextKeySessionUser).(*users.User)
+
+	return user, ok
+}
+
+// requireModerator returns the session user if they are a moderator, or the
+// result to respond with otherwise.
+func requireModerator(ctx *httpfx.Context) (*users.User, *httpfx.Result) {
+	user, hasUser := GetSessionUser(ctx)
+	if !hasUser {
+		result := ctx.Results.Unauthorized([]byte("Authentication required"))
+
+		return nil, &result
+	}
+
+	if !user.IsModerator() {
+		result := ctx.Results.Error(http.StatusForbidden, []byte("Moderator role required"))
+
+		return nil, &result
+	}
+
+	return user, nil
+}
+
+// requireAdmin returns the session user if they are an admin, or the result
+// to respond with otherwise.
+func requireAdmin(ctx *httpfx.Context) (*users.User, *httpfx.Result) {
+	user, hasUser := GetSessionUser(ctx)
+	if !hasUser {
+		result := ctx.Results.Unauthorized([]byte("Authentication required"))
+
+		return nil, &result
+	}
+
+	if !user.IsAdmin() {
+		result := ctx.Results.Error(http.StatusForbidden, []byte("Admin role required"))
+
+		return nil, &result
+	}
+
+	return user, nil
+}
+
+func GetSession(ctx *httpfx.Context) (*users.Session, bool) {
+	session, ok := ctx.Request.Context().Value(ContextKeySession).(*users.Session)
+
+	return session, ok
+}
+
+// clearSessionCookie expires the session cookie if it carries the given
+// session, so browsers stop sending it.
+func clearSessionCookie(ctx *httpfx.Context, sessionId string) {
+	cookie, err := ctx.Request.Cookie(SessionCookieName)
+	if err != nil || cookie.Value != sessionId {
+		return
+	}
+
+	http.SetCookie(ctx.ResponseWriter, &http.Cookie{ //nolint:exhaustruct
+		Name:     SessionCookieName,
+		Value:    "",
+		Path:     "/",
+		MaxAge:   -1,
+		HttpOnly: true,
+		Secure:   ctx.Request.TLS != nil,
+		SameSite: http.SameSiteLaxMode,
+	})
+}
+
+func getSessionId(req *http.Request) (string, bool) {
+	for _, authHeader := range req.Header["Authorization"] {
+		if strings.HasPrefix(authHeader, "Bearer ") {
+			return strings.TrimPrefix(authHeader, "Bearer "), true
+		}
+	}
+
+	cookie, err := req.Cookie(SessionCookieName)
+	if err == nil && cookie.Value != "" {
+		return cookie.Value, true
+	}
+
+	return "", false
+}
+
+// membershipErrorResult responds to a failed profile membership check, which
+// fails for suspended users too.
+func membershipErrorResult(ctx *httpfx.Context, err error) httpfx.Result {
+	if errors.Is(err, users.ErrUserSuspended) {
+		return ctx.Results.Error(http.StatusForbidden, []byte(err.Error()))
+	}
+
+	return ctx.Results.Error(http.StatusInternalServerError, []byte(err.Error()))
+}
@@ -0,0 +1,167 @@
+package http
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/eser/ajan/httpfx"
+)
+
+const (
+	// DefaultCacheControl lets caches keep the responses of routes without a
+	// policy, as long as they check back with the ETag before every use.
+	DefaultCacheControl = "no-cache"
+	// PrivateCacheControl keeps the responses to signed in users out of
+	// shared caches, as they may differ per user.
+	PrivateCacheControl = "private, no-cache"
+)
+
+// cachePolicies are the Cache-Control of the public read routes, for
+// guests. Handlers setting a Cache-Control of their own, as the feeds and
+// the media do, keep it.
+var cachePolicies = map[string]string{ //nolint:gochecknoglobals
+	"GET /home":                                   "public, max-age=30",
+	"GET /search":                                 "public, max-age=30",
+	"GET /profiles":                               "public, max-age=60",
+	"GET /profiles/{slug}":                        "public, max-age=60",
+	"GET /profiles/{slug}/stories":                "public, max-age=60",
+	"GET /profiles/{slug}/followers":              "public, max-age=60",
+	"GET /profiles/{slug}/projects":               "public, max-age=300",
+	"GET /profiles/{slug}/projects/{projectSlug}": "public, max-age=300",
+	"GET /stories":                                "public, max-age=60",
+	"GET /stories/featured":                       "public, max-age=60",
+	"GET /stories/{slug}":                         "public, max-age=60",
+	"GET /tags/{slug}":                            "public, max-age=300",
+}
+
+// ConditionalGetMiddleware sets the Cache-Control of read routes, and gives
+// their successful responses a strong ETag hashed from the body unless the
+// handler set validators of its own with checkNotModified. Clients holding
+// the same body get 304 instead. It must run after the session middleware.
+func ConditionalGetMiddleware() httpfx.Handler {
+	return func(ctx *httpfx.Context) httpfx.Result {
+		if ctx.Request.Method != http.MethodGet && ctx.Request.Method != http.MethodHead {
+			return ctx.Next()
+		}
+
+		result := ctx.Next()
+		header := ctx.ResponseWriter.Header()
+
+		if header.Get("Cache-Control") == "" {
+			header.Set("Cache-Control", cacheControlOf(ctx))
+		}
+
+		// the same address answers guests and signed in users differently.
+		header.Add("Vary", "Authorization, Cookie")
+
+		if result.StatusCode() != http.StatusOK || header.Get("ETag") != "" {
+			return result
+		}
+
+		etag := contentETag(result.Body())
+		header.Set("ETag", etag)
+
+		if ifNoneMatch := ctx.Request.Header.Get("If-None-Match"); ifNoneMatch != "" && etagMatches(ifNoneMatch, etag) {
+			return notModifiedResult(ctx)
+		}
+
+		return result
+	}
+}
+
+func cacheControlOf(ctx *httpfx.Context) string {
+	if _, hasUser := GetSessionUser(ctx); hasUser {
+		return PrivateCacheControl
+	}
+
+	if policy, ok := cachePolicies[ctx.Request.Pattern]; ok {
+		return policy
+	}
+
+	return DefaultCacheControl
+}
+
+// contentETag returns a strong entity tag of a response body.
+func contentETag(body []byte) string {
+	sum := sha256.Sum256(body)
+
+	return `"` + hex.EncodeToString(sum[:16]) + `"`
+}
+
+// checkNotModified sets the validators of the current representation and
+// reports whether the client already holds it, in which case the handler can
+// answer with 304 without building the body. A zero lastModified, as of an
+// empty list, leaves the representation with its ETag only.
+func checkNotModified(ctx *httpfx.Context, etag string, lastModified time.Time) bool {
+	header := ctx.ResponseWriter.Header()
+	header.Set("ETag", etag)
+
+	if !lastModified.IsZero() {
+		header.Set("Last-Modified", lastModified.UTC().Format(http.TimeFormat))
+	}
+
+	// If-None-Match takes precedence over If-Modified-Since (RFC 9110 13.2.2).
+	if ifNoneMatch := ctx.Request.Header.Get("If-None-Match"); ifNoneMatch != "" {
+		return etagMatches(ifNoneMatch, etag)
+	}
+
+	ifModifiedSince := ctx.Request.Header.Get("If-Modified-Since")
+	if ifModifiedSince == "" || lastModified.IsZero() {
+		return false
+	}
+
+	since, err := http.ParseTime(ifModifiedSince)
+	if err != nil {
+		return false
+	}
+
+	return !lastModified.Truncate(time.Second).After(since)
+}
+
+// versioned is implemented by the records that tell their own validators.
+type versioned interface {
+	ETag() string
+	Version() time.Time
+}
+
+// listValidators returns the validators of a list of records: an entity tag
+// changing as any of them changes, comes or goes, and the time of the latest
+// change among them. An empty list gets a stable entity tag of its own and a
+// zero time.
+func listValidators[T versioned](records []T) (string, time.Time) {
+	hash := sha256.New()
+
+	var lastModified time.Time
+
+	for _, record := range records {
+		hash.Write([]byte(record.ETag()))
+
+		if version := record.Version(); version.After(lastModified) {
+			lastModified = version
+		}
+	}
+
+	return `"` + hex.EncodeToString(hash.Sum(nil)[:16]) + `"`, lastModified
+}
+
+func notModifiedResult(ctx *httpfx.Context) httpfx.Result {
+	return ctx.Results.Bytes(nil).WithStatusCode(http.StatusNotModified)
+}
+
+// etagMatches performs the weak comparison used by If-None-Match.
+func etagMatches(header string, etag string) bool {
+	target := strings.TrimPrefix(etag, "W/")
+
+	for candidate := range strings.SplitSeq(header, ",") {
+		candidate = strings.TrimSpace(candidate)
+
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == target {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,280 @@
+package http
+
+import (
+	"database/sql"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/eser/acik.io/pkg/api/business/profiles"
+	"github.com/eser/acik.io/pkg/api/business/projects"
+	"github.com/eser/acik.io/pkg/api/business/stories"
+	"github.com/eser/ajan/httpfx"
+)
+
+// profileFixture serves a single profile detail the way GET /profiles/{slug}
+// does, counting how many times it is rendered.
+type profileFixture struct {
+	detail  *profiles.ProfileDetail
+	handler http.Handler
+	renders int
+}
+
+func newProfileFixture() *profileFixture {
+	f := &profileFixture{ //nolint:exhaustruct
+		detail: &profiles.ProfileDetail{
+			Profile: &profiles.Profile{ //nolint:exhaustruct
+				Id:        "01HPROFILE0000000000000000",
+				Slug:      "acik",
+				Title:     "Açık",
+				CreatedAt: time.Date(2026, 9, 1, 10, 0, 0, 0, time.UTC),
+				UpdatedAt: sql.NullTime{Time: time.Date(2026, 10, 1, 12, 30, 15, 500, time.UTC), Valid: true},
+			},
+			FollowerCount: 3,
+			IsFollowing:   false,
+		},
+	}
+
+	routes := httpfx.NewRouter("/")
+	routes.Use(ConditionalGetMiddleware())
+	routes.Route("GET /profiles/{slug}", func(ctx *httpfx.Context) httpfx.Result {
+		if checkNotModified(ctx, f.detail.ETag(), f.detail.Version()) {
+			return notModifiedResult(ctx)
+		}
+
+		f.renders++
+
+		return ctx.Results.Json(f.detail)
+	})
+	routes.Route("GET /profiles", func(ctx *httpfx.Context) httpfx.Result {
+		etag, lastModified := listValidators([]*profiles.Profile{})
+		if checkNotModified(ctx, etag, lastModified) {
+			return notModifiedResult(ctx)
+		}
+
+		return ctx.Results.Json([]*profiles.Profile{})
+	})
+	routes.Route("GET /stories", func(ctx *httpfx.Context) httpfx.Result {
+		return ctx.Results.Json([]string{"first", "second"})
+	})
+	routes.Route("PATCH /stories/{slug}", func(ctx *httpfx.Context) httpfx.Result {
+		return storiesErrorResult(ctx, fmt.Errorf("%w(id: %s)", stories.ErrVersionMismatch, ctx.Request.PathValue("slug")))
+	})
+	routes.Route("PUT /profiles/{slug}/projects/{projectSlug}", func(ctx *httpfx.Context) httpfx.Result {
+		return projectsErrorResult(ctx, fmt.Errorf("%w(id: %s)", projects.ErrVersionMismatch, ctx.Request.PathValue("projectSlug")))
+	})
+
+	f.handler = routes.GetMux()
+
+	return f
+}
+
+func (f *profileFixture) get(t *testing.T, path string, headers map[string]string) *httptest.ResponseRecorder {
+	t.Helper()
+
+	req := httptest.NewRequest(http.MethodGet, path, nil)
+	for name, value := range headers {
+		req.Header.Set(name, value)
+	}
+
+	recorder := httptest.NewRecorder()
+	f.handler.ServeHTTP(recorder, req)
+
+	return recorder
+}
+
+func TestProfileValidators(t *testing.T) {
+	t.Parallel()
+
+	f := newProfileFixture()
+
+	resp := f.get(t, "/profiles/acik", nil)
+	if resp.Code != http.StatusOK {
+		t.Fatalf("got %d, want 200", resp.Code)
+	}
+
+	if got := resp.Header().Get("ETag"); got != f.detail.ETag() {
+		t.Errorf("got ETag %q, want the one of the profile %q", got, f.detail.ETag())
+	}
+
+	if got := resp.Header().Get("Last-Modified"); got != "Thu, 01 Oct 2026 12:30:15 GMT" {
+		t.Errorf("got Last-Modified %q, want the time of the last update", got)
+	}
+
+	if got := resp.Header().Get("Cache-Control"); got != "public, max-age=60" {
+		t.Errorf("got Cache-Control %q, want the policy of the route", got)
+	}
+}
+
+func TestProfileNotModified(t *testing.T) {
+	t.Parallel()
+
+	f := newProfileFixture()
+	etag := f.detail.ETag()
+
+	tests := []struct {
+		headers map[string]string
+		name    string
+		want    int
+	}{
+		{name: "matching etag", headers: map[string]string{"If-None-Match": etag}, want: http.StatusNotModified},
+		{name: "weak matching etag", headers: map[string]string{"If-None-Match": `"other", W/` + etag}, want: http.StatusNotModified},
+		{name: "other etag", headers: map[string]string{"If-None-Match": `"other"`}, want: http.StatusOK},
+		{
+			name:    "modified since",
+			headers: map[string]string{"If-Modified-Since": "Thu, 01 Oct 2026 12:30:14 GMT"},
+			want:    http.StatusOK,
+		},
+		{
+			name:    "not modified since",
+			headers: map[string]string{"If-Modified-Since": "Thu, 01 Oct 2026 12:30:15 GMT"},
+			want:    http.StatusNotModified,
+		},
+		{
+			name:    "etag takes precedence",
+			headers: map[string]string{"If-None-Match": `"other"`, "If-Modified-Since": "Thu, 01 Oct 2026 12:30:15 GMT"},
+			want:    http.StatusOK,
+		},
+		{name: "invalid date", headers: map[string]string{"If-Modified-Since": "yesterday"}, want: http.StatusOK},
+	}
+
+	for _, test := range tests {
+		renders := f.renders
+
+		resp := f.get(t, "/profiles/acik", test.headers)
+		if resp.Code != test.want {
+			t.Errorf("%s: got %d, want %d", test.name, resp.Code, test.want)
+		}
+
+		rendered := f.renders > renders
+		if rendered != (test.want == http.StatusOK) {
+			t.Errorf("%s: got rendered %t, want it only for 200", test.name, rendered)
+		}
+
+		if test.want == http.StatusNotModified && resp.Body.Len() != 0 {
+			t.Errorf("%s: got a body of %d bytes with 304", test.name, resp.Body.Len())
+		}
+	}
+}
+
+func TestProfileETagFollowsFollowers(t *testing.T) {
+	t.Parallel()
+
+	f := newProfileFixture()
+	etag := f.detail.ETag()
+
+	f.detail.FollowerCount++
+
+	if resp := f.get(t, "/profiles/acik", map[string]string{"If-None-Match": etag}); resp.Code != http.StatusOK {
+		t.Errorf("got %d after gaining a follower, want 200", resp.Code)
+	}
+
+	f.detail.FollowerCount--
+	f.detail.IsFollowing = true
+
+	if resp := f.get(t, "/profiles/acik", map[string]string{"If-None-Match": etag}); resp.Code != http.StatusOK {
+		t.Errorf("got %d once followed, want 200", resp.Code)
+	}
+}
+
+func TestListValidators(t *testing.T) {
+	t.Parallel()
+
+	older := &profiles.Profile{Id: "1", CreatedAt: time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)} //nolint:exhaustruct
+	newer := &profiles.Profile{Id: "2", CreatedAt: time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC)} //nolint:exhaustruct
+
+	etag, lastModified := listValidators([]*profiles.Profile{older, newer})
+	if !lastModified.Equal(newer.CreatedAt) {
+		t.Errorf("got Last-Modified %s, want the newest version %s", lastModified, newer.CreatedAt)
+	}
+
+	if again, _ := listValidators([]*profiles.Profile{older, newer}); again != etag {
+		t.Errorf("got ETag %s for the same list, want %s", again, etag)
+	}
+
+	if removed, _ := listValidators([]*profiles.Profile{newer}); removed == etag {
+		t.Errorf("got the same ETag after a profile is removed")
+	}
+
+	older.UpdatedAt = sql.NullTime{Time: time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC), Valid: true}
+
+	updated, lastModified := listValidators([]*profiles.Profile{older, newer})
+	if updated == etag || !lastModified.Equal(older.UpdatedAt.Time) {
+		t.Errorf("got ETag %s and Last-Modified %s after an update, want new ones", updated, lastModified)
+	}
+}
+
+func TestEmptyListValidators(t *testing.T) {
+	t.Parallel()
+
+	f := newProfileFixture()
+
+	resp := f.get(t, "/profiles", nil)
+
+	etag := resp.Header().Get("ETag")
+	if resp.Code != http.StatusOK || etag == "" {
+		t.Fatalf("got %d with ETag %q, want 200 with an ETag", resp.Code, etag)
+	}
+
+	if lastModified := resp.Header().Get("Last-Modified"); lastModified != "" {
+		t.Errorf("got Last-Modified %q for an empty list, want none", lastModified)
+	}
+
+	if resp := f.get(t, "/profiles", map[string]string{"If-None-Match": etag}); resp.Code != http.StatusNotModified {
+		t.Errorf("got %d for the same empty list, want 304", resp.Code)
+	}
+
+	since := map[string]string{"If-Modified-Since": time.Now().UTC().Format(http.TimeFormat)}
+	if resp := f.get(t, "/profiles", since); resp.Code != http.StatusOK {
+		t.Errorf("got %d with If-Modified-Since for an empty list, want 200", resp.Code)
+	}
+}
+
+func TestBodyETagNotModified(t *testing.T) {
+	t.Parallel()
+
+	f := newProfileFixture()
+
+	resp := f.get(t, "/stories", nil)
+
+	etag := resp.Header().Get("ETag")
+	if resp.Code != http.StatusOK || etag == "" {
+		t.Fatalf("got %d with ETag %q, want 200 with a body hash", resp.Code, etag)
+	}
+
+	if resp := f.get(t, "/stories", map[string]string{"If-None-Match": etag}); resp.Code != http.StatusNotModified {
+		t.Errorf("got %d for the same body, want 304", resp.Code)
+	}
+}
+
+func TestVersionMismatchIsPreconditionFailed(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		method string
+		path   string
+	}{
+		{method: http.MethodPatch, path: "/stories/hello"},
+		{method: http.MethodPut, path: "/profiles/acik/projects/hello"},
+	}
+
+	f := newProfileFixture()
+
+	for _, test := range tests {
+		t.Run(test.method+" "+test.path, func(t *testing.T) {
+			t.Parallel()
+
+			req := httptest.NewRequest(test.method, test.path, nil)
+			req.Header.Set("If-Match", `"stale"`)
+
+			resp := httptest.NewRecorder()
+			f.handler.ServeHTTP(resp, req)
+
+			if resp.Code != http.StatusPreconditionFailed {
+				t.Errorf("got %d, want 412", resp.Code)
+			}
+		})
+	}
+}
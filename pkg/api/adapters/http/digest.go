@@ -0,0 +1,90 @@
+package http
+
+import (
+	"errors"
+	"html/template"
+	"net/http"
+	"strings"
+
+	"github.com/eser/acik.io/pkg/api/adapters/appcontext"
+	"github.com/eser/acik.io/pkg/api/adapters/storage"
+	"github.com/eser/acik.io/pkg/api/business/digest"
+	"github.com/eser/ajan/httpfx"
+)
+
+var unsubscribeTemplate = template.Must(template.New("unsubscribe").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head><meta charset="utf-8"><title>Unsubscribe from the weekly digest</title></head>
+<body>
+{{if .Done}}<p>You will no longer receive the weekly digest.</p>
+{{else}}<form method="post"><p>Stop receiving the weekly digest?</p><button type="submit">Unsubscribe</button></form>
+{{end}}</body>
+</html>
+`)) //nolint:gochecknoglobals
+
+func RegisterHttpRoutesForDigest(routes *httpfx.Router, appContext *appcontext.AppContext) {
+	// the link in the digest opens a confirmation, so that link scanners
+	// visiting it don't unsubscribe anyone.
+	routes.
+		Route("GET /digest/unsubscribe/{token}", func(ctx *httpfx.Context) httpfx.Result {
+			return unsubscribePageResult(ctx, false)
+		}).
+		HasSummary("Unsubscribe from the digest").
+		HasDescription("Shows a confirmation form for unsubscribing from the weekly digest.").
+		HasPathParameter("token", "The unsubscribe token from the digest").
+		HasResponse(http.StatusOK)
+
+	routes.
+		Route("POST /digest/unsubscribe/{token}", func(ctx *httpfx.Context) httpfx.Result {
+			store, err := storage.NewFromDefault(appContext.Data)
+			if err != nil {
+				return ctx.Results.Error(http.StatusInternalServerError, []byte(err.Error()))
+			}
+
+			questionsService, err := newQuestionsService(appContext)
+			if err != nil {
+				return ctx.Results.Error(http.StatusInternalServerError, []byte(err.Error()))
+			}
+
+			service := digest.NewService(&appContext.Config.Digest, store, questionsService, nil, nil, nil)
+
+			err = service.Unsubscribe(ctx.Request.Context(), ctx.Request.PathValue("token"))
+			if err != nil {
+				return digestErrorResult(ctx, err)
+			}
+
+			// mail clients unsubscribing in one click (RFC 8058) don't need a page.
+			if strings.HasPrefix(ctx.Request.Header.Get("Content-Type"), "application/x-www-form-urlencoded") &&
+				ctx.Request.PostFormValue("List-Unsubscribe") == "One-Click" {
+				return ctx.Results.Ok()
+			}
+
+			return unsubscribePageResult(ctx, true)
+		}).
+		HasSummary("Confirm digest unsubscription").
+		HasDescription("Unsubscribes the user the token was issued for from the weekly digest. Supports one-click unsubscription.").
+		HasPathParameter("token", "The unsubscribe token from the digest").
+		HasResponse(http.StatusOK)
+}
+
+func unsubscribePageResult(ctx *httpfx.Context, done bool) httpfx.Result {
+	var page strings.Builder
+
+	err := unsubscribeTemplate.Execute(&page, map[string]bool{"Done": done})
+	if err != nil {
+		return ctx.Results.Error(http.StatusInternalServerError, []byte(err.Error()))
+	}
+
+	ctx.ResponseWriter.Header().Set("Content-Type", "text/html; charset=utf-8")
+
+	return ctx.Results.Bytes([]byte(page.String()))
+}
+
+func digestErrorResult(ctx *httpfx.Context, err error) httpfx.Result {
+	switch {
+	case errors.Is(err, digest.ErrInvalidUnsubscribeToken):
+		return ctx.Results.NotFound()
+	default:
+		return ctx.Results.Error(http.StatusInternalServerError, []byte(err.Error()))
+	}
+}
@@ -0,0 +1,344 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/eser/acik.io/pkg/api/adapters/appcontext"
+	"github.com/eser/acik.io/pkg/api/adapters/qrcode"
+	"github.com/eser/acik.io/pkg/api/adapters/storage"
+	"github.com/eser/acik.io/pkg/api/business/audit"
+	"github.com/eser/acik.io/pkg/api/business/events"
+	"github.com/eser/acik.io/pkg/api/business/slugs"
+	"github.com/eser/acik.io/pkg/api/business/users"
+	"github.com/eser/ajan/httpfx"
+)
+
+func RegisterHttpRoutesForEvents(routes *httpfx.Router, appContext *appcontext.AppContext) { //nolint:funlen
+	routes.
+		Route("GET /events", func(ctx *httpfx.Context) httpfx.Result {
+			store, err := storage.NewFromDefault(appContext.Data)
+			if err != nil {
+				return ctx.Results.Error(http.StatusInternalServerError, []byte(err.Error()))
+			}
+
+			tagId, hasTag, err := getTagFilter(ctx, appContext)
+			if err != nil {
+				return ctx.Results.Error(http.StatusInternalServerError, []byte(err.Error()))
+			}
+
+			if !hasTag {
+				return ctx.Results.Json([]*events.Event{})
+			}
+
+			limit, _ := getPagination(ctx)
+
+			records, err := newEventsService(appContext, store).ListUpcoming(ctx.Request.Context(), tagId, limit)
+			if err != nil {
+				return eventsErrorResult(ctx, err)
+			}
+
+			for _, record := range records {
+				proxyPictures(appContext, &record.EventPictureUri)
+			}
+
+			return ctx.Results.Json(records)
+		}).
+		HasSummary("List upcoming events").
+		HasDescription("List the published events that have not ended yet, the soonest first.").
+		HasQueryParameter("tag", "Only list events with this tag").
+		HasQueryParameter("limit", "Maximum number of events to return").
+		HasResponse(http.StatusOK)
+
+	routes.
+		Route("GET /events/{slug}", SlugRedirectMiddleware(appContext, slugs.KindEvent, "/events/"), func(ctx *httpfx.Context) httpfx.Result {
+			store, err := storage.NewFromDefault(appContext.Data)
+			if err != nil {
+				return ctx.Results.Error(http.StatusInternalServerError, []byte(err.Error()))
+			}
+
+			userId := ""
+			if user, hasUser := GetSessionUser(ctx); hasUser {
+				userId = user.Id
+			}
+
+			event, err := newEventsService(appContext, store).
+				GetVisibleBySlug(ctx.Request.Context(), ctx.Request.PathValue("slug"), userId)
+			if err != nil {
+				return eventsErrorResult(ctx, err)
+			}
+
+			proxyPictures(appContext, &event.EventPictureUri)
+
+			return ctx.Results.Json(event)
+		}).
+		HasSummary("Get event").
+		HasDescription("Get a published event by its slug. Organizers can also see their draft events. Previous slugs redirect to the current one.").
+		HasPathParameter("slug", "The slug of the event").
+		HasResponse(http.StatusOK)
+
+	routes.
+		Route("GET /events/{slug}/check-in-code", SlugRedirectMiddleware(appContext, slugs.KindEvent, "/events/"), func(ctx *httpfx.Context) httpfx.Result {
+			response, result, ok := issueCheckInCode(ctx, appContext)
+			if !ok {
+				return result
+			}
+
+			return ctx.Results.Json(response)
+		}).
+		HasSummary("Get check-in code").
+		HasDescription("Returns the signed check-in code of the current user for an event they RSVP'd to.").
+		HasPathParameter("slug", "The slug of the event").
+		HasResponse(http.StatusOK)
+
+	routes.
+		Route("GET /events/{slug}/check-in-code.png", SlugRedirectMiddleware(appContext, slugs.KindEvent, "/events/"), func(ctx *httpfx.Context) httpfx.Result {
+			response, result, ok := issueCheckInCode(ctx, appContext)
+			if !ok {
+				return result
+			}
+
+			image, err := qrcode.EncodePng(response.Code)
+			if err != nil {
+				return ctx.Results.Error(http.StatusInternalServerError, []byte(err.Error()))
+			}
+
+			ctx.ResponseWriter.Header().Set("Content-Type", "image/png")
+			ctx.ResponseWriter.Header().Set("Cache-Control", "private, no-store")
+
+			return ctx.Results.Bytes(image)
+		}).
+		HasSummary("Get check-in QR code").
+		HasDescription("Returns the check-in code of the current user as a PNG QR code.").
+		HasPathParameter("slug", "The slug of the event").
+		HasResponse(http.StatusOK)
+
+	routes.
+		Route("PUT /events/{slug}/rsvp", func(ctx *httpfx.Context) httpfx.Result {
+			return changeRsvp(ctx, appContext, (*events.Service).Rsvp)
+		}).
+		HasSummary("RSVP to event").
+		HasDescription("Registers the individual profile of the current user as attending a published event. Repeating it changes nothing.").
+		HasPathParameter("slug", "The slug of the event").
+		HasResponse(http.StatusOK)
+
+	routes.
+		Route("DELETE /events/{slug}/rsvp", func(ctx *httpfx.Context) httpfx.Result {
+			return changeRsvp(ctx, appContext, (*events.Service).CancelRsvp)
+		}).
+		HasSummary("Cancel RSVP").
+		HasDescription("Withdraws the RSVP of the current user to an event. Attendees who already checked in can't withdraw.").
+		HasPathParameter("slug", "The slug of the event").
+		HasResponse(http.StatusOK)
+
+	routes.
+		Route("POST /events/{slug}/check-ins", IdempotencyMiddleware(appContext), func(ctx *httpfx.Context) httpfx.Result {
+			user, hasUser := GetSessionUser(ctx)
+			if !hasUser {
+				return ctx.Results.Unauthorized([]byte("Authentication required"))
+			}
+
+			var body events.CheckInRequest
+
+			err := json.NewDecoder(ctx.Request.Body).Decode(&body)
+			if err != nil || body.Code == "" {
+				return ctx.Results.BadRequest()
+			}
+
+			store, err := storage.NewFromDefault(appContext.Data)
+			if err != nil {
+				return ctx.Results.Error(http.StatusInternalServerError, []byte(err.Error()))
+			}
+
+			service := newEventsService(appContext, store)
+
+			event, err := service.GetBySlug(ctx.Request.Context(), ctx.Request.PathValue("slug"))
+			if err != nil {
+				return eventsErrorResult(ctx, err)
+			}
+
+			attendance, err := service.CheckIn(ctx.Request.Context(), event.Id, user.Id, body.Code)
+			if err != nil {
+				return eventsErrorResult(ctx, err)
+			}
+
+			return ctx.Results.Json(attendance)
+		}).
+		HasSummary("Check in attendee").
+		HasDescription("Verifies an attendee's check-in code and marks them as attended. Organizers only. Retries sent with the same Idempotency-Key header get the response of the first request.").
+		HasPathParameter("slug", "The slug of the event").
+		HasRequestModel(events.CheckInRequest{}). //nolint:exhaustruct
+		HasResponse(http.StatusOK)
+
+	routes.
+		Route("POST /events/{slug}/publish", func(ctx *httpfx.Context) httpfx.Result {
+			user, hasUser := GetSessionUser(ctx)
+			if !hasUser {
+				return ctx.Results.Unauthorized([]byte("Authentication required"))
+			}
+
+			store, err := storage.NewFromDefault(appContext.Data)
+			if err != nil {
+				return ctx.Results.Error(http.StatusInternalServerError, []byte(err.Error()))
+			}
+
+			service := newEventsService(appContext, store)
+
+			event, err := service.GetBySlug(ctx.Request.Context(), ctx.Request.PathValue("slug"))
+			if err != nil {
+				return eventsErrorResult(ctx, err)
+			}
+
+			event, err = service.Publish(ctx.Request.Context(), event.Id, user.Id)
+			if err != nil {
+				return eventsErrorResult(ctx, err)
+			}
+
+			return ctx.Results.Json(event)
+		}).
+		HasSummary("Publish event").
+		HasDescription("Makes a draft event public. Organizers only.").
+		HasPathParameter("slug", "The slug of the event").
+		HasResponse(http.StatusOK)
+
+	routes.
+		Route("PUT /events/{slug}/schedule", func(ctx *httpfx.Context) httpfx.Result {
+			user, hasUser := GetSessionUser(ctx)
+			if !hasUser {
+				return ctx.Results.Unauthorized([]byte("Authentication required"))
+			}
+
+			var input events.RescheduleInput
+
+			err := json.NewDecoder(ctx.Request.Body).Decode(&input)
+			if err != nil {
+				return ctx.Results.BadRequest()
+			}
+
+			store, err := storage.NewFromDefault(appContext.Data)
+			if err != nil {
+				return ctx.Results.Error(http.StatusInternalServerError, []byte(err.Error()))
+			}
+
+			service := newEventsService(appContext, store)
+
+			event, err := service.GetBySlug(ctx.Request.Context(), ctx.Request.PathValue("slug"))
+			if err != nil {
+				return eventsErrorResult(ctx, err)
+			}
+
+			event, err = service.Reschedule(ctx.Request.Context(), event.Id, user.Id, &input)
+			if err != nil {
+				return eventsErrorResult(ctx, err)
+			}
+
+			return ctx.Results.Json(event)
+		}).
+		HasSummary("Reschedule event").
+		HasDescription("Moves an event to a new time and notifies its attendees. Organizers only.").
+		HasPathParameter("slug", "The slug of the event").
+		HasRequestModel(events.RescheduleInput{}). //nolint:exhaustruct
+		HasResponse(http.StatusOK)
+}
+
+// changeRsvp applies an RSVP change to the individual profile of the current
+// user.
+func changeRsvp(
+	ctx *httpfx.Context,
+	appContext *appcontext.AppContext,
+	change func(*events.Service, context.Context, string, string, string) (*events.EventAttendance, error),
+) httpfx.Result {
+	user, hasUser := GetSessionUser(ctx)
+	if !hasUser {
+		return ctx.Results.Unauthorized([]byte("Authentication required"))
+	}
+
+	if !user.IndividualProfileId.Valid {
+		return ctx.Results.Error(http.StatusForbidden, []byte("User has no individual profile"))
+	}
+
+	store, err := storage.NewFromDefault(appContext.Data)
+	if err != nil {
+		return ctx.Results.Error(http.StatusInternalServerError, []byte(err.Error()))
+	}
+
+	service := newEventsService(appContext, store)
+
+	event, err := service.GetBySlug(ctx.Request.Context(), ctx.Request.PathValue("slug"))
+	if err != nil {
+		return eventsErrorResult(ctx, err)
+	}
+
+	attendance, err := change(service, ctx.Request.Context(), event.Id, user.Id, user.IndividualProfileId.String)
+	if err != nil {
+		return eventsErrorResult(ctx, err)
+	}
+
+	return ctx.Results.Json(attendance)
+}
+
+func issueCheckInCode(ctx *httpfx.Context, appContext *appcontext.AppContext) (*events.CheckInCodeResponse, httpfx.Result, bool) { //nolint:lll
+	user, hasUser := GetSessionUser(ctx)
+	if !hasUser {
+		return nil, ctx.Results.Unauthorized([]byte("Authentication required")), false
+	}
+
+	if !user.IndividualProfileId.Valid {
+		return nil, ctx.Results.Error(http.StatusForbidden, []byte("User has no individual profile")), false
+	}
+
+	store, err := storage.NewFromDefault(appContext.Data)
+	if err != nil {
+		return nil, ctx.Results.Error(http.StatusInternalServerError, []byte(err.Error())), false
+	}
+
+	service := newEventsService(appContext, store)
+
+	event, err := service.GetBySlug(ctx.Request.Context(), ctx.Request.PathValue("slug"))
+	if err != nil {
+		return nil, eventsErrorResult(ctx, err), false
+	}
+
+	response, err := service.IssueCheckInCode(ctx.Request.Context(), event.Id, user.IndividualProfileId.String)
+	if err != nil {
+		return nil, eventsErrorResult(ctx, err), false
+	}
+
+	return response, httpfx.Result{}, true //nolint:exhaustruct
+}
+
+// eventChange describes a change to an event for the audit log, the event as
+// read after the change being its new state.
+func eventChange(action string) func(record *events.Event) *audit.Change {
+	return func(record *events.Event) *audit.Change {
+		return &audit.Change{
+			Before:     nil,
+			After:      record,
+			Action:     action,
+			EntityType: events.AggregateEvent,
+			EntityId:   record.Id,
+			ProfileId:  "",
+		}
+	}
+}
+
+func eventsErrorResult(ctx *httpfx.Context, err error) httpfx.Result {
+	switch {
+	case errors.Is(err, events.ErrRecordNotFound):
+		return ctx.Results.NotFound()
+	case errors.Is(err, events.ErrInvalidCheckInCode), errors.Is(err, events.ErrInvalidInput):
+		return ctx.Results.Error(http.StatusBadRequest, []byte(err.Error()))
+	case errors.Is(err, events.ErrNotOrganizer), errors.Is(err, events.ErrNotAttending):
+		return ctx.Results.Error(http.StatusForbidden, []byte(err.Error()))
+	case errors.Is(err, events.ErrCheckInCodeForAnotherEvent):
+		return ctx.Results.Error(http.StatusUnprocessableEntity, []byte(err.Error()))
+	case errors.Is(err, events.ErrAlreadyCheckedIn), errors.Is(err, events.ErrAlreadyPublished):
+		return ctx.Results.Error(http.StatusConflict, []byte(err.Error()))
+	case errors.Is(err, users.ErrUserSuspended):
+		return ctx.Results.Error(http.StatusForbidden, []byte(err.Error()))
+	default:
+		return ctx.Results.Error(http.StatusInternalServerError, []byte(err.Error()))
+	}
+}
@@ -0,0 +1,132 @@
+package http
+
+import (
+	"net/http"
+
+	"github.com/eser/acik.io/pkg/api/adapters/appcontext"
+	"github.com/eser/acik.io/pkg/api/adapters/feeds"
+	"github.com/eser/acik.io/pkg/api/adapters/storage"
+	"github.com/eser/acik.io/pkg/api/business/slugs"
+	"github.com/eser/acik.io/pkg/api/business/stories"
+	"github.com/eser/ajan/httpfx"
+)
+
+const (
+	SiteName            = "acik.io"
+	FeedCacheControl    = "public, max-age=300"
+	feedContentQueryKey = "content"
+)
+
+func RegisterHttpRoutesForFeeds( //nolint:funlen
+	routes *httpfx.Router,
+	appContext *appcontext.AppContext,
+	renderer stories.ContentRenderer,
+) {
+	for _, format := range []feeds.Format{feeds.FormatRss, feeds.FormatAtom, feeds.FormatJson} {
+		routes.
+			Route("GET /stories/feed."+string(format), func(ctx *httpfx.Context) httpfx.Result {
+				service, err := newStoriesService(appContext, renderer)
+				if err != nil {
+					return ctx.Results.Error(http.StatusInternalServerError, []byte(err.Error()))
+				}
+
+				return feedResult(ctx, service, format, &stories.FeedInput{
+					AuthorProfileId: "",
+					Title:           SiteName,
+					Description:     "Stories published on " + SiteName,
+					Link:            service.SiteUrl(),
+					FeedUrl:         service.SiteUrl() + ctx.Request.URL.Path,
+					AuthorName:      SiteName,
+					Content:         "",
+				})
+			}).
+			HasSummary("Stories feed ("+string(format)+")").
+			HasDescription("Syndication feed of the latest published stories.").
+			HasQueryParameter(feedContentQueryKey, "Entry content: full (default) or summary").
+			HasResponse(http.StatusOK).
+			HasResponse(http.StatusNotModified)
+
+		routes.
+			Route("GET /profiles/{slug}/feed."+string(format), SlugRedirectMiddleware(appContext, slugs.KindProfile, "/profiles/"), func(ctx *httpfx.Context) httpfx.Result {
+				store, err := storage.NewFromDefault(appContext.Data)
+				if err != nil {
+					return ctx.Results.Error(http.StatusInternalServerError, []byte(err.Error()))
+				}
+
+				profile, err := newProfilesService(appContext, store).GetBySlug(ctx.Request.Context(), ctx.Request.PathValue("slug"))
+				if err != nil {
+					return profilesErrorResult(ctx, err)
+				}
+
+				if profile == nil {
+					return ctx.Results.NotFound()
+				}
+
+				if !profile.ShowStories {
+					return ctx.Results.NotFound()
+				}
+
+				service, err := newStoriesService(appContext, renderer)
+				if err != nil {
+					return ctx.Results.Error(http.StatusInternalServerError, []byte(err.Error()))
+				}
+
+				return feedResult(ctx, service, format, &stories.FeedInput{
+					AuthorProfileId: profile.Id,
+					Title:           profile.Title + " - " + SiteName,
+					Description:     profile.Description,
+					Link:            service.ProfileUrl(profile.Slug),
+					FeedUrl:         service.SiteUrl() + ctx.Request.URL.Path,
+					AuthorName:      profile.Title,
+					Content:         "",
+				})
+			}).
+			HasSummary("Profile stories feed ("+string(format)+")").
+			HasDescription("Syndication feed of the latest stories of a profile, if the profile shows its stories.").
+			HasPathParameter("slug", "The slug of the profile").
+			HasQueryParameter(feedContentQueryKey, "Entry content: full (default) or summary").
+			HasResponse(http.StatusOK).
+			HasResponse(http.StatusNotModified)
+	}
+}
+
+// feedResult answers conditional requests from the feed stamp alone, and only
+// loads and renders the stories when the client's copy is stale.
+func feedResult(
+	ctx *httpfx.Context,
+	service *stories.Service,
+	format feeds.Format,
+	input *stories.FeedInput,
+) httpfx.Result {
+	content, err := stories.ParseFeedContent(ctx.Request.URL.Query().Get(feedContentQueryKey))
+	if err != nil {
+		return ctx.Results.Error(http.StatusBadRequest, []byte(err.Error()))
+	}
+
+	input.Content = content
+
+	stamp, err := service.GetFeedStamp(ctx.Request.Context(), input.AuthorProfileId)
+	if err != nil {
+		return storiesErrorResult(ctx, err)
+	}
+
+	ctx.ResponseWriter.Header().Set("Cache-Control", FeedCacheControl)
+
+	if checkNotModified(ctx, stamp.ETag(string(format)+"-"+string(content)), stamp.LastModifiedAt) {
+		return notModifiedResult(ctx)
+	}
+
+	feed, err := service.BuildFeed(ctx.Request.Context(), input)
+	if err != nil {
+		return storiesErrorResult(ctx, err)
+	}
+
+	encoded, err := feeds.Encode(format, feed)
+	if err != nil {
+		return ctx.Results.Error(http.StatusInternalServerError, []byte(err.Error()))
+	}
+
+	ctx.ResponseWriter.Header().Set("Content-Type", format.ContentType())
+
+	return ctx.Results.Bytes(encoded)
+}
@@ -0,0 +1,216 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/eser/acik.io/pkg/api/adapters/appcontext"
+	"github.com/eser/acik.io/pkg/api/adapters/storage"
+	"github.com/eser/acik.io/pkg/api/business/feed"
+	"github.com/eser/acik.io/pkg/api/business/profiles"
+	"github.com/eser/acik.io/pkg/api/business/ratelimit"
+	"github.com/eser/acik.io/pkg/api/business/readcache"
+	"github.com/eser/acik.io/pkg/api/business/slugs"
+	"github.com/eser/acik.io/pkg/api/business/users"
+	"github.com/eser/ajan/httpfx"
+)
+
+func RegisterHttpRoutesForFollows( //nolint:funlen,cyclop
+	routes *httpfx.Router,
+	appContext *appcontext.AppContext,
+	readCache *readcache.Cache,
+) {
+	routes.
+		Route("GET /profiles/{slug}", SlugRedirectMiddleware(appContext, slugs.KindProfile, "/profiles/"), func(ctx *httpfx.Context) httpfx.Result {
+			store, err := storage.NewFromDefault(appContext.Data)
+			if err != nil {
+				return ctx.Results.Error(http.StatusInternalServerError, []byte(err.Error()))
+			}
+
+			userId := ""
+			if user, hasUser := GetSessionUser(ctx); hasUser {
+				userId = user.Id
+			}
+
+			service := profiles.NewService(
+				readcache.NewProfileRepository(store, readCache),
+				newEventRecorder(appContext, store),
+				slugs.NewService(store),
+			)
+
+			detail, err := service.GetDetailBySlug(ctx.Request.Context(), ctx.Request.PathValue("slug"), userId)
+			if err != nil {
+				return followsErrorResult(ctx, err)
+			}
+
+			if checkNotModified(ctx, detail.ETag(), detail.Version()) {
+				return notModifiedResult(ctx)
+			}
+
+			proxyPictures(appContext, &detail.ProfilePictureUri)
+
+			return ctx.Results.Json(detail)
+		}).
+		HasSummary("Get profile").
+		HasDescription("Gets a profile with its follower count, and whether the current user follows it. Previous slugs redirect to the current one. Responses carry an ETag and a Last-Modified, and 304 is returned to clients that already hold the profile.").
+		HasPathParameter("slug", "The slug of the profile").
+		HasResponse(http.StatusOK)
+
+	routes.
+		Route("PUT /profiles/{slug}/follow", RateLimitPolicyMiddleware(ratelimit.PolicyFollows), func(ctx *httpfx.Context) httpfx.Result {
+			user, hasUser := GetSessionUser(ctx)
+			if !hasUser {
+				return ctx.Results.Unauthorized([]byte("Authentication required"))
+			}
+
+			store, err := storage.NewFromDefault(appContext.Data)
+			if err != nil {
+				return ctx.Results.Error(http.StatusInternalServerError, []byte(err.Error()))
+			}
+
+			detail, err := newProfilesService(appContext, store).Follow(ctx.Request.Context(), user.Id, ctx.Request.PathValue("slug"))
+			if err != nil {
+				return followsErrorResult(ctx, err)
+			}
+
+			return ctx.Results.Json(detail)
+		}).
+		HasSummary("Follow profile").
+		HasDescription("Makes the current user follow a profile.").
+		HasPathParameter("slug", "The slug of the profile").
+		HasResponse(http.StatusOK)
+
+	routes.
+		Route("DELETE /profiles/{slug}/follow", RateLimitPolicyMiddleware(ratelimit.PolicyFollows), func(ctx *httpfx.Context) httpfx.Result {
+			user, hasUser := GetSessionUser(ctx)
+			if !hasUser {
+				return ctx.Results.Unauthorized([]byte("Authentication required"))
+			}
+
+			store, err := storage.NewFromDefault(appContext.Data)
+			if err != nil {
+				return ctx.Results.Error(http.StatusInternalServerError, []byte(err.Error()))
+			}
+
+			detail, err := newProfilesService(appContext, store).Unfollow(ctx.Request.Context(), user.Id, ctx.Request.PathValue("slug"))
+			if err != nil {
+				return followsErrorResult(ctx, err)
+			}
+
+			return ctx.Results.Json(detail)
+		}).
+		HasSummary("Unfollow profile").
+		HasDescription("Makes the current user stop following a profile.").
+		HasPathParameter("slug", "The slug of the profile").
+		HasResponse(http.StatusOK)
+
+	routes.
+		Route("GET /profiles/{slug}/followers", SlugRedirectMiddleware(appContext, slugs.KindProfile, "/profiles/"), func(ctx *httpfx.Context) httpfx.Result {
+			store, err := storage.NewFromDefault(appContext.Data)
+			if err != nil {
+				return ctx.Results.Error(http.StatusInternalServerError, []byte(err.Error()))
+			}
+
+			limit, offset := getPagination(ctx)
+
+			followers, err := newProfilesService(appContext, store).
+				ListFollowers(ctx.Request.Context(), ctx.Request.PathValue("slug"), limit, offset)
+			if err != nil {
+				return followsErrorResult(ctx, err)
+			}
+
+			for _, follower := range followers.Items {
+				proxyPictures(appContext, &follower.ProfilePictureUri)
+			}
+
+			return ctx.Results.Json(followers)
+		}).
+		HasSummary("List profile followers").
+		HasDescription("Lists the users following a profile, the latest first. Followers are people rather than content, so the list takes no tag filter.").
+		HasPathParameter("slug", "The slug of the profile").
+		HasQueryParameter("limit", "Maximum number of followers to return").
+		HasQueryParameter("offset", "Number of followers to skip").
+		HasResponse(http.StatusOK)
+
+	routes.
+		Route("GET /me/following", func(ctx *httpfx.Context) httpfx.Result {
+			user, hasUser := GetSessionUser(ctx)
+			if !hasUser {
+				return ctx.Results.Unauthorized([]byte("Authentication required"))
+			}
+
+			store, err := storage.NewFromDefault(appContext.Data)
+			if err != nil {
+				return ctx.Results.Error(http.StatusInternalServerError, []byte(err.Error()))
+			}
+
+			limit, offset := getPagination(ctx)
+
+			following, err := newProfilesService(appContext, store).ListFollowing(ctx.Request.Context(), user.Id, limit, offset)
+			if err != nil {
+				return followsErrorResult(ctx, err)
+			}
+
+			for _, profile := range following.Items {
+				proxyPictures(appContext, &profile.ProfilePictureUri)
+			}
+
+			return ctx.Results.Json(following)
+		}).
+		HasSummary("List followed profiles").
+		HasDescription("Lists the profiles the current user follows, the latest followed first. The list is the follow relationships themselves and takes no tag filter; GET /me/feed narrows what they publish down to a tag.").
+		HasQueryParameter("limit", "Maximum number of profiles to return").
+		HasQueryParameter("offset", "Number of profiles to skip").
+		HasResponse(http.StatusOK)
+
+	routes.
+		Route("GET /me/feed", func(ctx *httpfx.Context) httpfx.Result {
+			user, hasUser := GetSessionUser(ctx)
+			if !hasUser {
+				return ctx.Results.Unauthorized([]byte("Authentication required"))
+			}
+
+			store, err := storage.NewFromDefault(appContext.Data)
+			if err != nil {
+				return ctx.Results.Error(http.StatusInternalServerError, []byte(err.Error()))
+			}
+
+			tagId, hasTag, err := getTagFilter(ctx, appContext)
+			if err != nil {
+				return ctx.Results.Error(http.StatusInternalServerError, []byte(err.Error()))
+			}
+
+			if !hasTag {
+				return ctx.Results.Json(&feed.Page{Items: []*feed.ListFeedItemsRow{}, NextCursor: ""})
+			}
+
+			limit, _ := getPagination(ctx)
+
+			page, err := feed.NewService(store).
+				ForUser(ctx.Request.Context(), user.Id, tagId, ctx.Request.URL.Query().Get("cursor"), limit)
+			if err != nil {
+				return followsErrorResult(ctx, err)
+			}
+
+			return ctx.Results.Json(page)
+		}).
+		HasSummary("Get personal feed").
+		HasDescription("Lists the stories and events published by the profiles the current user follows, the latest first.").
+		HasQueryParameter("tag", "Only list items with this tag").
+		HasQueryParameter("cursor", "The nextCursor of the previous page").
+		HasQueryParameter("limit", "Maximum number of items to return").
+		HasResponse(http.StatusOK)
+}
+
+func followsErrorResult(ctx *httpfx.Context, err error) httpfx.Result {
+	switch {
+	case errors.Is(err, profiles.ErrRecordNotFound):
+		return ctx.Results.NotFound()
+	case errors.Is(err, feed.ErrInvalidCursor):
+		return ctx.Results.Error(http.StatusBadRequest, []byte(err.Error()))
+	case errors.Is(err, users.ErrUserSuspended):
+		return ctx.Results.Error(http.StatusForbidden, []byte(err.Error()))
+	default:
+		return ctx.Results.Error(http.StatusInternalServerError, []byte(err.Error()))
+	}
+}
@@ -0,0 +1,54 @@
+package http
+
+import (
+	"net/http"
+
+	"github.com/eser/acik.io/pkg/api/adapters/appcontext"
+	"github.com/eser/acik.io/pkg/api/adapters/storage"
+	"github.com/eser/acik.io/pkg/api/business/home"
+	"github.com/eser/acik.io/pkg/api/business/readcache"
+	"github.com/eser/acik.io/pkg/api/business/stories"
+	"github.com/eser/ajan/httpfx"
+)
+
+func RegisterHttpRoutesForHome(
+	routes *httpfx.Router,
+	appContext *appcontext.AppContext,
+	renderer stories.ContentRenderer,
+	readCache *readcache.Cache,
+) {
+	routes.
+		Route("GET /home", func(ctx *httpfx.Context) httpfx.Result {
+			store, err := storage.NewFromDefault(appContext.Data)
+			if err != nil {
+				return ctx.Results.Error(http.StatusInternalServerError, []byte(err.Error()))
+			}
+
+			storiesService, err := newStoriesService(appContext, renderer)
+			if err != nil {
+				return ctx.Results.Error(http.StatusInternalServerError, []byte(err.Error()))
+			}
+
+			questionsService, err := newQuestionsService(appContext)
+			if err != nil {
+				return ctx.Results.Error(http.StatusInternalServerError, []byte(err.Error()))
+			}
+
+			service := readcache.NewHomePage(home.NewService(&appContext.Config.Home, &home.Sources{
+				FeaturedStories: storiesService,
+				UpcomingEvents:  newEventsService(appContext, store),
+				TopQuestions:    questionsService,
+				NewestProfiles:  newProfilesService(appContext, store),
+			}), readCache)
+
+			page, problems := service.GetPage(ctx.Request.Context())
+			for _, problem := range problems {
+				appContext.Logger.WarnContext(ctx.Request.Context(), "home section unavailable", "error", problem)
+			}
+
+			return ctx.Results.Json(page)
+		}).
+		HasSummary("Home page").
+		HasDescription("Featured stories, upcoming events, top unanswered questions and newest profiles in one response.").
+		HasResponse(http.StatusOK)
+}
@@ -2,54 +2,155 @@ package http
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"net/http"
 
+	"github.com/eser/acik.io/pkg/api/adapters/appcontext"
+	"github.com/eser/acik.io/pkg/api/adapters/markdown"
+	"github.com/eser/acik.io/pkg/api/adapters/queue"
+	"github.com/eser/acik.io/pkg/api/adapters/readcachestore"
 	"github.com/eser/acik.io/pkg/api/adapters/storage"
+	"github.com/eser/acik.io/pkg/api/business/audit"
+	"github.com/eser/acik.io/pkg/api/business/events"
+	"github.com/eser/acik.io/pkg/api/business/outbox"
 	"github.com/eser/acik.io/pkg/api/business/profiles"
-	"github.com/eser/ajan/datafx"
+	"github.com/eser/acik.io/pkg/api/business/readcache"
+	"github.com/eser/acik.io/pkg/api/business/slugs"
+	"github.com/eser/acik.io/pkg/api/business/tags"
+	"github.com/eser/acik.io/pkg/api/business/users"
 	"github.com/eser/ajan/httpfx"
 	"github.com/eser/ajan/httpfx/middlewares"
 	"github.com/eser/ajan/httpfx/modules/healthcheck"
 	"github.com/eser/ajan/httpfx/modules/openapi"
 	"github.com/eser/ajan/httpfx/modules/profiling"
 	"github.com/eser/ajan/lib"
-	"github.com/eser/ajan/logfx"
-	"github.com/eser/ajan/metricsfx"
 )
 
-func RegisterHttpRoutes(routes *httpfx.Router, logger *logfx.Logger, dataRegistry *datafx.Registry) {
+func RegisterHttpRoutes(routes *httpfx.Router, appContext *appcontext.AppContext, readCache *readcache.Cache) {
 	routes.
 		Route("GET /profiles", func(ctx *httpfx.Context) httpfx.Result {
-			store, err := storage.NewFromDefault(dataRegistry)
+			store, err := storage.NewFromDefault(appContext.Data)
 			if err != nil {
 				return ctx.Results.Error(http.StatusInternalServerError, []byte(err.Error()))
 			}
 
-			service := profiles.NewService(store)
+			tagId, hasTag, err := getTagFilter(ctx, appContext)
+			if err != nil {
+				return ctx.Results.Error(http.StatusInternalServerError, []byte(err.Error()))
+			}
+
+			if !hasTag {
+				return ctx.Results.Json([]*profiles.Profile{})
+			}
+
+			service := newProfilesService(appContext, store)
 
-			records, err := service.List(ctx.Request.Context())
+			records, err := service.List(ctx.Request.Context(), tagId)
 			if err != nil {
 				return ctx.Results.Error(http.StatusInternalServerError, []byte(err.Error()))
 			}
 
+			etag, lastModified := listValidators(records)
+			if checkNotModified(ctx, etag, lastModified) {
+				return notModifiedResult(ctx)
+			}
+
 			return ctx.Results.Json(records)
 		}).
 		HasSummary("List profiles").
-		HasDescription("List profiles.").
+		HasDescription("List profiles. Responses carry an ETag and a Last-Modified, and 304 is returned to clients that already hold the list.").
+		HasQueryParameter("tag", "Only list profiles that published a story or organize a published event with this tag").
 		HasResponse(http.StatusOK)
+
+	routes.
+		Route("POST /profiles", IdempotencyMiddleware(appContext), func(ctx *httpfx.Context) httpfx.Result {
+			user, hasUser := GetSessionUser(ctx)
+			if !hasUser {
+				return ctx.Results.Unauthorized([]byte("Authentication required"))
+			}
+
+			var input profiles.CreateInput
+
+			err := json.NewDecoder(ctx.Request.Body).Decode(&input)
+			if err != nil {
+				return ctx.Results.BadRequest()
+			}
+
+			store, err := storage.NewFromDefault(appContext.Data)
+			if err != nil {
+				return ctx.Results.Error(http.StatusInternalServerError, []byte(err.Error()))
+			}
+
+			record, err := newProfilesService(appContext, store).
+				Create(ctx.Request.Context(), user.Id, &input)
+			if err != nil {
+				return profilesErrorResult(ctx, err)
+			}
+
+			return ctx.Results.Json(record).WithStatusCode(http.StatusCreated)
+		}).
+		HasSummary("Create profile").
+		HasDescription("Creates an organization profile owned by the current user. Retries sent with the same Idempotency-Key header get the response of the first request.").
+		HasRequestModel(profiles.CreateInput{}). //nolint:exhaustruct
+		HasResponse(http.StatusCreated)
+
+	RegisterHttpRoutesForMembers(routes, appContext)
+	RegisterHttpRoutesForEvents(routes, appContext)
+	RegisterHttpRoutesForSearch(routes, appContext)
+	RegisterHttpRoutesForWebhooks(routes, appContext)
+	RegisterHttpRoutesForNotifications(routes, appContext)
+	RegisterHttpRoutesForDigest(routes, appContext)
+	RegisterHttpRoutesForFollows(routes, appContext, readCache)
+	RegisterHttpRoutesForProjects(routes, appContext)
+	RegisterHttpRoutesForAudit(routes, appContext)
+	RegisterHttpRoutesForModeration(routes, appContext)
+	RegisterHttpRoutesForQuestions(routes, appContext)
+
+	renderer := markdown.NewCachedRenderer(markdown.NewRenderer(), markdown.DefaultCacheSize)
+
+	RegisterHttpRoutesForStories(routes, appContext, renderer)
+	RegisterHttpRoutesForFeeds(routes, appContext, renderer)
+	RegisterHttpRoutesForHome(routes, appContext, renderer, readCache)
+	RegisterHttpRoutesForTags(routes, appContext, renderer)
+	RegisterHttpRoutesForMedia(routes, appContext, renderer)
+	RegisterHttpRoutesForSlugs(routes, appContext, renderer)
 }
 
-func Run(ctx context.Context, config *httpfx.Config, metricsProvider *metricsfx.MetricsProvider, logger *logfx.Logger, dataRegistry *datafx.Registry) error { //nolint:lll
+func Run(ctx context.Context, appContext *appcontext.AppContext) error {
+	config := &appContext.Config.Http
+
 	routes := httpfx.NewRouter("/")
-	httpService := httpfx.NewHttpService(config, routes, metricsProvider, logger)
+	httpService := httpfx.NewHttpService(config, routes, appContext.Metrics, appContext.Logger)
+
+	limiter, err := newRateLimiter(appContext)
+	if err != nil {
+		return err
+	}
+
+	readCache, err := newReadCache(appContext)
+	if err != nil {
+		return err
+	}
+
+	store, err := storage.NewFromDefault(appContext.Data)
+	if err != nil {
+		return err //nolint:wrapcheck
+	}
 
 	// http middlewares
 	routes.Use(middlewares.ErrorHandlerMiddleware())
 	routes.Use(middlewares.ResolveAddressMiddleware())
 	routes.Use(middlewares.ResponseTimeMiddleware())
+	routes.Use(CorrelationIdHeaderMiddleware())
 	routes.Use(middlewares.CorrelationIdMiddleware())
 	routes.Use(middlewares.CorsMiddleware())
 	routes.Use(middlewares.MetricsMiddleware(httpService.InnerMetrics))
+	routes.Use(SessionMiddleware(appContext.Data))
+	routes.Use(AuditActorMiddleware())
+	routes.Use(ReadCacheMiddleware(readcache.NewInvalidator(readCache, store), appContext.Logger))
+	routes.Use(RateLimitMiddleware(limiter, NewRateLimitMetrics(appContext.Metrics.GetRegistry()), appContext.Logger))
+	routes.Use(ConditionalGetMiddleware())
 
 	// http modules
 	healthcheck.RegisterHttpRoutes(routes, config)
@@ -57,7 +158,7 @@ func Run(ctx context.Context, config *httpfx.Config, metricsProvider *metricsfx.
 	profiling.RegisterHttpRoutes(routes, config)
 
 	// http routes
-	RegisterHttpRoutes(routes, logger, dataRegistry) //nolint:contextcheck
+	RegisterHttpRoutes(routes, appContext, readCache) //nolint:contextcheck
 
 	// run
 	cleanup, err := httpService.Start(ctx)
@@ -71,3 +172,74 @@ func Run(ctx context.Context, config *httpfx.Config, metricsProvider *metricsfx.
 
 	return nil
 }
+
+// newEventRecorder returns the recorder that writes the domain events of a
+// request into the outbox and the audit log, sharing the store of the
+// services using it.
+func newEventRecorder(appContext *appcontext.AppContext, store *storage.Queries) *audit.Recorder {
+	return audit.NewRecorder(newOutbox(appContext, store), audit.NewService(store))
+}
+
+// newProfilesService returns the profiles service of a request, recording
+// its changes through newEventRecorder.
+func newProfilesService(appContext *appcontext.AppContext, store *storage.Queries) *profiles.Service {
+	return profiles.NewService(store, newEventRecorder(appContext, store), slugs.NewService(store))
+}
+
+// newEventsService returns the events service of a request, recording its
+// changes through newEventRecorder.
+func newEventsService(appContext *appcontext.AppContext, store *storage.Queries) *events.Service {
+	recorder := newEventRecorder(appContext, store)
+
+	return events.NewService(
+		&appContext.Config.Events,
+		store,
+		recorder,
+		tags.NewService(store, recorder),
+		slugs.NewService(store),
+	)
+}
+
+// newTagsService returns the tags service of a request, recording its changes
+// through newEventRecorder.
+func newTagsService(appContext *appcontext.AppContext, store *storage.Queries) *tags.Service {
+	return tags.NewService(store, newEventRecorder(appContext, store))
+}
+
+// newOutbox returns the outbox alone, for the changes that are logged to the
+// audit log on their own. The events are noted for the read cache of the
+// instance, see ReadCacheMiddleware.
+func newOutbox(appContext *appcontext.AppContext, store *storage.Queries) *readcache.Recorder {
+	return readcache.NewRecorder(outbox.NewService(&appContext.Config.Outbox, store, queue.NewFromDefault(appContext.Queue)))
+}
+
+// newReadCache returns the cache the hot read paths go through, or none
+// when it is turned off.
+func newReadCache(appContext *appcontext.AppContext) (*readcache.Cache, error) {
+	config := &appContext.Config.ReadCache
+	if !config.Enabled {
+		return nil, nil //nolint:nilnil
+	}
+
+	store, err := readcachestore.NewFromConfig(config, appContext.Cache)
+	if err != nil {
+		return nil, err //nolint:wrapcheck
+	}
+
+	return readcache.NewCache(config, store, readcachestore.NewMetrics(appContext.Metrics)), nil
+}
+
+func profilesErrorResult(ctx *httpfx.Context, err error) httpfx.Result {
+	switch {
+	case errors.Is(err, profiles.ErrRecordNotFound):
+		return ctx.Results.NotFound()
+	case errors.Is(err, profiles.ErrInvalidInput), errors.Is(err, slugs.ErrInvalidSlug), errors.Is(err, slugs.ErrReservedSlug):
+		return ctx.Results.Error(http.StatusBadRequest, []byte(err.Error()))
+	case errors.Is(err, profiles.ErrSlugTaken):
+		return ctx.Results.Error(http.StatusConflict, []byte(err.Error()))
+	case errors.Is(err, users.ErrUserSuspended):
+		return ctx.Results.Error(http.StatusForbidden, []byte(err.Error()))
+	default:
+		return ctx.Results.Error(http.StatusInternalServerError, []byte(err.Error()))
+	}
+}
@@ -0,0 +1,120 @@
+package http
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/eser/acik.io/pkg/api/adapters/appcontext"
+	"github.com/eser/acik.io/pkg/api/adapters/storage"
+	"github.com/eser/acik.io/pkg/api/business/idempotency"
+	"github.com/eser/ajan/httpfx"
+)
+
+const (
+	IdempotencyKeyHeader      = "Idempotency-Key"
+	IdempotentReplayedHeader  = "Idempotent-Replayed"
+	idempotencyRetryAfterSecs = "1"
+)
+
+// replayedHeaders are the response headers stored along with the body.
+// Others are set anew on every request by the middlewares.
+var replayedHeaders = []string{"Content-Type", "Location", "ETag", "Last-Modified"} //nolint:gochecknoglobals
+
+// IdempotencyMiddleware replays the stored response to retries of a request
+// sent with an Idempotency-Key header, so they don't repeat its effects.
+// Keys belong to the session user, or to the client address for guests.
+// Server errors aren't stored, so those requests can be retried. Routes opt
+// in by listing it before their handler:
+//
+//	routes.Route("POST /things", IdempotencyMiddleware(appContext), handler)
+func IdempotencyMiddleware(appContext *appcontext.AppContext) httpfx.Handler {
+	return func(ctx *httpfx.Context) httpfx.Result {
+		key := ctx.Request.Header.Get(IdempotencyKeyHeader)
+		if key == "" {
+			return ctx.Next()
+		}
+
+		if !idempotency.IsValidKey(key) {
+			return ctx.Results.Error(http.StatusBadRequest, []byte("Idempotency-Key must be up to 255 printable characters"))
+		}
+
+		body, err := io.ReadAll(ctx.Request.Body)
+		if err != nil {
+			return ctx.Results.BadRequest()
+		}
+
+		ctx.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+		store, err := storage.NewFromDefault(appContext.Data)
+		if err != nil {
+			return ctx.Results.Error(http.StatusInternalServerError, []byte(err.Error()))
+		}
+
+		service := idempotency.NewService(&appContext.Config.Idempotency, store)
+		scope := requestIdentity(ctx)
+		fingerprint := idempotency.Fingerprint(ctx.Request, body)
+
+		stored, err := service.Begin(ctx.Request.Context(), scope, key, fingerprint)
+		if err != nil {
+			return idempotencyErrorResult(ctx, err)
+		}
+
+		if stored != nil {
+			header := ctx.ResponseWriter.Header()
+			for name, value := range stored.Headers {
+				header.Set(name, value)
+			}
+
+			header.Set(IdempotentReplayedHeader, "true")
+
+			return ctx.Results.Bytes(stored.Body).WithStatusCode(stored.StatusCode)
+		}
+
+		result := ctx.Next()
+
+		if result.StatusCode() >= http.StatusInternalServerError {
+			err = service.Release(ctx.Request.Context(), scope, key, fingerprint)
+		} else {
+			err = service.Complete(ctx.Request.Context(), scope, key, fingerprint, &idempotency.Response{
+				Headers:    responseHeaders(ctx.ResponseWriter.Header()),
+				Body:       result.Body(),
+				StatusCode: result.StatusCode(),
+			})
+		}
+
+		if err != nil {
+			// the request is handled already, the retries will wait for the
+			// lock to expire instead.
+			appContext.Logger.WarnContext(ctx.Request.Context(), "idempotency key not stored", "key", key, "error", err)
+		}
+
+		return result
+	}
+}
+
+func responseHeaders(header http.Header) map[string]string {
+	result := map[string]string{}
+
+	for _, name := range replayedHeaders {
+		if value := header.Get(name); value != "" {
+			result[name] = value
+		}
+	}
+
+	return result
+}
+
+func idempotencyErrorResult(ctx *httpfx.Context, err error) httpfx.Result {
+	switch {
+	case errors.Is(err, idempotency.ErrKeyReused):
+		return ctx.Results.Error(http.StatusUnprocessableEntity, []byte(err.Error()))
+	case errors.Is(err, idempotency.ErrInFlight):
+		ctx.ResponseWriter.Header().Set(RetryAfterHeader, idempotencyRetryAfterSecs)
+
+		return ctx.Results.Error(http.StatusConflict, []byte(err.Error()))
+	default:
+		return ctx.Results.Error(http.StatusInternalServerError, []byte(err.Error()))
+	}
+}
@@ -0,0 +1,276 @@
+package http
+
+import (
+	"database/sql"
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/eser/acik.io/pkg/api/adapters/appcontext"
+	"github.com/eser/acik.io/pkg/api/adapters/blobstore"
+	"github.com/eser/acik.io/pkg/api/adapters/imagefetch"
+	"github.com/eser/acik.io/pkg/api/adapters/storage"
+	"github.com/eser/acik.io/pkg/api/business/media"
+	"github.com/eser/acik.io/pkg/api/business/ratelimit"
+	"github.com/eser/acik.io/pkg/api/business/stories"
+	"github.com/eser/ajan/httpfx"
+)
+
+func RegisterHttpRoutesForMedia( //nolint:funlen,cyclop
+	routes *httpfx.Router,
+	appContext *appcontext.AppContext,
+	renderer stories.ContentRenderer,
+) {
+	routes.
+		Route("GET /media/{key...}", func(ctx *httpfx.Context) httpfx.Result {
+			service, err := newMediaService(appContext)
+			if err != nil {
+				return ctx.Results.Error(http.StatusInternalServerError, []byte(err.Error()))
+			}
+
+			blob, err := service.Get(ctx.Request.Context(), ctx.Request.PathValue("key"))
+			if err != nil {
+				return mediaErrorResult(ctx, err)
+			}
+
+			// keys are content-addressed, what they hold never changes.
+			ctx.ResponseWriter.Header().Set("Content-Type", blob.ContentType)
+			ctx.ResponseWriter.Header().Set("Cache-Control", "public, max-age=31536000, immutable")
+			ctx.ResponseWriter.Header().Set("X-Content-Type-Options", "nosniff")
+
+			return ctx.Results.Bytes(blob.Data)
+		}).
+		HasSummary("Get media").
+		HasDescription("Returns an uploaded picture by its key.").
+		HasPathParameter("key", "The key of the picture").
+		HasResponse(http.StatusOK)
+
+	routes.
+		Route("GET /img/{sig}/{params}", func(ctx *httpfx.Context) httpfx.Result {
+			proxy, err := newImageProxy(appContext)
+			if err != nil {
+				return ctx.Results.Error(http.StatusInternalServerError, []byte(err.Error()))
+			}
+
+			blob, err := proxy.Serve(ctx.Request.Context(), ctx.Request.PathValue("sig"), ctx.Request.PathValue("params"))
+			if err != nil {
+				return mediaErrorResult(ctx, err)
+			}
+
+			// signed addresses pin the image and its size, so they never change.
+			ctx.ResponseWriter.Header().Set("Content-Type", blob.ContentType)
+			ctx.ResponseWriter.Header().Set("Cache-Control", "public, max-age=31536000, immutable")
+			ctx.ResponseWriter.Header().Set("X-Content-Type-Options", "nosniff")
+
+			return ctx.Results.Bytes(blob.Data)
+		}).
+		HasSummary("Get proxied image").
+		HasDescription("Returns a remote image through the image proxy, resized to the size its signed address asks for.").
+		HasPathParameter("sig", "The signature of the parameters").
+		HasPathParameter("params", "The encoded address and size of the image").
+		HasResponse(http.StatusOK)
+
+	routes.
+		Route("PUT /profiles/{slug}/picture", RateLimitPolicyMiddleware(ratelimit.PolicyUploads), func(ctx *httpfx.Context) httpfx.Result {
+			user, hasUser := GetSessionUser(ctx)
+			if !hasUser {
+				return ctx.Results.Unauthorized([]byte("Authentication required"))
+			}
+
+			store, err := storage.NewFromDefault(appContext.Data)
+			if err != nil {
+				return ctx.Results.Error(http.StatusInternalServerError, []byte(err.Error()))
+			}
+
+			profileService := newProfilesService(appContext, store)
+
+			profile, err := profileService.GetBySlug(ctx.Request.Context(), ctx.Request.PathValue("slug"))
+			if err != nil {
+				return ctx.Results.Error(http.StatusInternalServerError, []byte(err.Error()))
+			}
+
+			if profile == nil || profile.DeletedAt.Valid {
+				return ctx.Results.NotFound()
+			}
+
+			isMember, err := profileService.IsMember(ctx.Request.Context(), profile.Id, user.Id)
+			if err != nil {
+				return membershipErrorResult(ctx, err)
+			}
+
+			if !isMember {
+				return ctx.Results.Error(http.StatusForbidden, []byte("User is not a member of the profile"))
+			}
+
+			image, failure := uploadPicture(ctx, appContext, media.PresetProfile)
+			if failure != nil {
+				return *failure
+			}
+
+			_, err = profileService.SetPicture(ctx.Request.Context(), profile.Id, image.Uri)
+			if err != nil {
+				return ctx.Results.Error(http.StatusInternalServerError, []byte(err.Error()))
+			}
+
+			return ctx.Results.Json(image)
+		}).
+		HasSummary("Upload profile picture").
+		HasDescription("Upload a JPEG, PNG, GIF or WebP image as the picture of a profile. Members only.").
+		HasPathParameter("slug", "The slug of the profile").
+		HasResponse(http.StatusOK)
+
+	routes.
+		Route("PUT /stories/{slug}/picture", RateLimitPolicyMiddleware(ratelimit.PolicyUploads), func(ctx *httpfx.Context) httpfx.Result {
+			user, hasUser := GetSessionUser(ctx)
+			if !hasUser {
+				return ctx.Results.Unauthorized([]byte("Authentication required"))
+			}
+
+			service, err := newStoriesService(appContext, renderer)
+			if err != nil {
+				return ctx.Results.Error(http.StatusInternalServerError, []byte(err.Error()))
+			}
+
+			record, err := service.GetEditableBySlug(ctx.Request.Context(), ctx.Request.PathValue("slug"), user.Id)
+			if err != nil {
+				return storiesErrorResult(ctx, err)
+			}
+
+			image, failure := uploadPicture(ctx, appContext, media.PresetStory)
+			if failure != nil {
+				return *failure
+			}
+
+			_, err = service.SetPicture(ctx.Request.Context(), record, image.Uri)
+			if err != nil {
+				return storiesErrorResult(ctx, err)
+			}
+
+			return ctx.Results.Json(image)
+		}).
+		HasSummary("Upload story picture").
+		HasDescription("Upload a JPEG, PNG, GIF or WebP image as the picture of a story. Authors only.").
+		HasPathParameter("slug", "The slug of the story").
+		HasResponse(http.StatusOK)
+
+	routes.
+		Route("PUT /events/{slug}/picture", RateLimitPolicyMiddleware(ratelimit.PolicyUploads), func(ctx *httpfx.Context) httpfx.Result {
+			user, hasUser := GetSessionUser(ctx)
+			if !hasUser {
+				return ctx.Results.Unauthorized([]byte("Authentication required"))
+			}
+
+			store, err := storage.NewFromDefault(appContext.Data)
+			if err != nil {
+				return ctx.Results.Error(http.StatusInternalServerError, []byte(err.Error()))
+			}
+
+			service := newEventsService(appContext, store)
+
+			event, err := service.GetBySlug(ctx.Request.Context(), ctx.Request.PathValue("slug"))
+			if err != nil {
+				return eventsErrorResult(ctx, err)
+			}
+
+			err = service.EnsureOrganizer(ctx.Request.Context(), event.Id, user.Id)
+			if err != nil {
+				return eventsErrorResult(ctx, err)
+			}
+
+			image, failure := uploadPicture(ctx, appContext, media.PresetEvent)
+			if failure != nil {
+				return *failure
+			}
+
+			_, err = service.SetPicture(ctx.Request.Context(), event.Id, image.Uri)
+			if err != nil {
+				return eventsErrorResult(ctx, err)
+			}
+
+			return ctx.Results.Json(image)
+		}).
+		HasSummary("Upload event picture").
+		HasDescription("Upload a JPEG, PNG, GIF or WebP image as the picture of an event. Organizers only.").
+		HasPathParameter("slug", "The slug of the event").
+		HasResponse(http.StatusOK)
+}
+
+func newMediaService(appContext *appcontext.AppContext) (*media.Service, error) {
+	store, err := blobstore.NewFromConfig(&appContext.Config.Media)
+	if err != nil {
+		return nil, err //nolint:wrapcheck
+	}
+
+	return media.NewService(&appContext.Config.Media, store), nil
+}
+
+func newImageProxy(appContext *appcontext.AppContext) (*media.Proxy, error) {
+	store, err := blobstore.NewFromConfig(&appContext.Config.Media)
+	if err != nil {
+		return nil, err //nolint:wrapcheck
+	}
+
+	return media.NewProxy(&appContext.Config.Media, store, imagefetch.NewFetcher(&appContext.Config.Media)), nil
+}
+
+// proxyPictures rewrites picture addresses on allowed remote hosts to go
+// through the image proxy, so readers never reach those hosts.
+func proxyPictures(appContext *appcontext.AppContext, uris ...*sql.NullString) {
+	proxy := media.NewProxy(&appContext.Config.Media, nil, nil)
+
+	for _, uri := range uris {
+		if uri.Valid {
+			uri.String = proxy.Rewrite(uri.String, 0, 0)
+		}
+	}
+}
+
+// uploadPicture stores the image in the request body with the variants of a
+// preset.
+func uploadPicture(ctx *httpfx.Context, appContext *appcontext.AppContext, preset string) (*media.Image, *httpfx.Result) {
+	// one byte more than allowed, so oversized uploads can be told apart.
+	data, err := io.ReadAll(io.LimitReader(ctx.Request.Body, media.MaxUploadSize+1))
+	if err != nil {
+		result := ctx.Results.BadRequest()
+
+		return nil, &result
+	}
+
+	service, err := newMediaService(appContext)
+	if err != nil {
+		result := ctx.Results.Error(http.StatusInternalServerError, []byte(err.Error()))
+
+		return nil, &result
+	}
+
+	image, err := service.Upload(ctx.Request.Context(), preset, data)
+	if err != nil {
+		result := mediaErrorResult(ctx, err)
+
+		return nil, &result
+	}
+
+	return image, nil
+}
+
+func mediaErrorResult(ctx *httpfx.Context, err error) httpfx.Result {
+	switch {
+	case errors.Is(err, media.ErrBlobNotFound):
+		return ctx.Results.NotFound()
+	case errors.Is(err, media.ErrUploadTooLarge), errors.Is(err, media.ErrImageTooLarge):
+		return ctx.Results.Error(http.StatusRequestEntityTooLarge, []byte(err.Error()))
+	case errors.Is(err, media.ErrUnsupportedType):
+		return ctx.Results.Error(http.StatusUnsupportedMediaType, []byte(err.Error()))
+	case errors.Is(err, media.ErrInvalidImage), errors.Is(err, media.ErrEmptyUpload),
+		errors.Is(err, media.ErrInvalidProxyParams):
+		return ctx.Results.Error(http.StatusBadRequest, []byte(err.Error()))
+	case errors.Is(err, media.ErrInvalidSignature), errors.Is(err, media.ErrHostNotAllowed):
+		return ctx.Results.Error(http.StatusForbidden, []byte(err.Error()))
+	case errors.Is(err, media.ErrRemoteNotFound), errors.Is(err, media.ErrProxySecretNotConfigured):
+		return ctx.Results.NotFound()
+	case errors.Is(err, media.ErrFailedToFetch):
+		return ctx.Results.Error(http.StatusBadGateway, []byte(err.Error()))
+	default:
+		return ctx.Results.Error(http.StatusInternalServerError, []byte(err.Error()))
+	}
+}
@@ -0,0 +1,74 @@
+package http
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/eser/acik.io/pkg/api/adapters/appcontext"
+	"github.com/eser/acik.io/pkg/api/adapters/storage"
+	"github.com/eser/acik.io/pkg/api/business/profiles"
+	"github.com/eser/ajan/httpfx"
+)
+
+func RegisterHttpRoutesForMembers(routes *httpfx.Router, appContext *appcontext.AppContext) {
+	routes.
+		Route("POST /profiles/{slug}/members", IdempotencyMiddleware(appContext), func(ctx *httpfx.Context) httpfx.Result {
+			user, hasUser := GetSessionUser(ctx)
+			if !hasUser {
+				return ctx.Results.Unauthorized([]byte("Authentication required"))
+			}
+
+			var input profiles.InviteInput
+
+			err := json.NewDecoder(ctx.Request.Body).Decode(&input)
+			if err != nil {
+				return ctx.Results.BadRequest()
+			}
+
+			store, err := storage.NewFromDefault(appContext.Data)
+			if err != nil {
+				return ctx.Results.Error(http.StatusInternalServerError, []byte(err.Error()))
+			}
+
+			service := newProfilesService(appContext, store)
+
+			profile, err := service.GetBySlug(ctx.Request.Context(), ctx.Request.PathValue("slug"))
+			if err != nil {
+				return ctx.Results.Error(http.StatusInternalServerError, []byte(err.Error()))
+			}
+
+			if profile == nil || profile.DeletedAt.Valid {
+				return ctx.Results.NotFound()
+			}
+
+			isAdmin, err := service.IsAdmin(ctx.Request.Context(), profile.Id, user.Id)
+			if err != nil {
+				return membershipErrorResult(ctx, err)
+			}
+
+			if !isAdmin {
+				return ctx.Results.Error(http.StatusForbidden, []byte("User is not an admin of the profile"))
+			}
+
+			membership, err := service.InviteMember(ctx.Request.Context(), profile.Id, user.Id, &input)
+			if err != nil {
+				return membersErrorResult(ctx, err)
+			}
+
+			return ctx.Results.Json(membership).WithStatusCode(http.StatusCreated)
+		}).
+		HasSummary("Invite profile member").
+		HasDescription("Adds a user to the members of a profile as an admin or a member, and notifies them. Profile admins only. Retries sent with the same Idempotency-Key header get the response of the first request.").
+		HasPathParameter("slug", "The slug of the profile").
+		HasRequestModel(profiles.InviteInput{}). //nolint:exhaustruct
+		HasResponse(http.StatusCreated)
+}
+
+func membersErrorResult(ctx *httpfx.Context, err error) httpfx.Result {
+	if errors.Is(err, profiles.ErrAlreadyMember) {
+		return ctx.Results.Error(http.StatusConflict, []byte(err.Error()))
+	}
+
+	return profilesErrorResult(ctx, err)
+}
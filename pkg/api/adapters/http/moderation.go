@@ -0,0 +1,224 @@
+package http
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/eser/acik.io/pkg/api/adapters/appcontext"
+	"github.com/eser/acik.io/pkg/api/adapters/storage"
+	"github.com/eser/acik.io/pkg/api/business/audit"
+	"github.com/eser/acik.io/pkg/api/business/moderation"
+	"github.com/eser/acik.io/pkg/api/business/ratelimit"
+	"github.com/eser/acik.io/pkg/api/business/users"
+	"github.com/eser/ajan/httpfx"
+)
+
+func RegisterHttpRoutesForModeration(routes *httpfx.Router, appContext *appcontext.AppContext) { //nolint:funlen
+	routes.
+		Route("POST /reports", RateLimitPolicyMiddleware(ratelimit.PolicyReports), IdempotencyMiddleware(appContext), func(ctx *httpfx.Context) httpfx.Result {
+			user, hasUser := GetSessionUser(ctx)
+			if !hasUser {
+				return ctx.Results.Unauthorized([]byte("Authentication required"))
+			}
+
+			var input moderation.ReportInput
+
+			err := json.NewDecoder(ctx.Request.Body).Decode(&input)
+			if err != nil {
+				return ctx.Results.BadRequest()
+			}
+
+			service, err := newModerationService(appContext)
+			if err != nil {
+				return ctx.Results.Error(http.StatusInternalServerError, []byte(err.Error()))
+			}
+
+			record, err := service.Report(ctx.Request.Context(), user.Id, &input)
+			if err != nil {
+				return moderationErrorResult(ctx, err)
+			}
+
+			return ctx.Results.Json(record).WithStatusCode(http.StatusCreated)
+		}).
+		HasSummary("Report content").
+		HasDescription("Reports a story, a question or a profile to the moderators, with a reason: spam, harassment, inappropriate, misinformation or other. Retries sent with the same Idempotency-Key header get the response of the first request.").
+		HasRequestModel(moderation.ReportInput{}). //nolint:exhaustruct
+		HasResponse(http.StatusCreated)
+
+	routes.
+		Route("GET /moderation/reports", func(ctx *httpfx.Context) httpfx.Result {
+			_, failure := requireModerator(ctx)
+			if failure != nil {
+				return *failure
+			}
+
+			service, err := newModerationService(appContext)
+			if err != nil {
+				return ctx.Results.Error(http.StatusInternalServerError, []byte(err.Error()))
+			}
+
+			query := ctx.Request.URL.Query()
+			limit, offset := getPagination(ctx)
+
+			records, err := service.List(ctx.Request.Context(), &moderation.ListFilter{
+				Status:     query.Get("status"),
+				EntityType: query.Get("entityType"),
+				Limit:      limit,
+				Offset:     offset,
+			})
+			if err != nil {
+				return moderationErrorResult(ctx, err)
+			}
+
+			return ctx.Results.Json(records)
+		}).
+		HasSummary("List reports").
+		HasDescription("Lists the moderation queue, the oldest report first. Moderators only.").
+		HasQueryParameter("status", "Status of the reports to return: open (default), actioned or dismissed").
+		HasQueryParameter("entityType", "Only reports on this kind of content: story, question or profile").
+		HasQueryParameter("limit", "Maximum number of reports to return").
+		HasQueryParameter("offset", "Number of reports to skip").
+		HasResponse(http.StatusOK)
+
+	routes.
+		Route("GET /moderation/reports/{id}", func(ctx *httpfx.Context) httpfx.Result {
+			_, failure := requireModerator(ctx)
+			if failure != nil {
+				return *failure
+			}
+
+			service, err := newModerationService(appContext)
+			if err != nil {
+				return ctx.Results.Error(http.StatusInternalServerError, []byte(err.Error()))
+			}
+
+			record, err := service.GetById(ctx.Request.Context(), ctx.Request.PathValue("id"))
+			if err != nil {
+				return moderationErrorResult(ctx, err)
+			}
+
+			return ctx.Results.Json(record)
+		}).
+		HasSummary("Get report").
+		HasDescription("Gets a report. Moderators only.").
+		HasPathParameter("id", "The id of the report").
+		HasResponse(http.StatusOK)
+
+	routes.
+		Route("POST /moderation/reports/{id}/resolve", func(ctx *httpfx.Context) httpfx.Result {
+			user, failure := requireModerator(ctx)
+			if failure != nil {
+				return *failure
+			}
+
+			var input moderation.ResolveInput
+
+			err := json.NewDecoder(ctx.Request.Body).Decode(&input)
+			if err != nil {
+				return ctx.Results.BadRequest()
+			}
+
+			service, err := newModerationService(appContext)
+			if err != nil {
+				return ctx.Results.Error(http.StatusInternalServerError, []byte(err.Error()))
+			}
+
+			record, err := service.Resolve(ctx.Request.Context(), user.Id, ctx.Request.PathValue("id"), &input)
+			if err != nil {
+				return moderationErrorResult(ctx, err)
+			}
+
+			return ctx.Results.Json(record)
+		}).
+		HasSummary("Resolve report").
+		HasDescription("Closes a report by hiding or deleting the reported content, suspending its owner, or dismissing the report. Moderators only.").
+		HasPathParameter("id", "The id of the report").
+		HasRequestModel(moderation.ResolveInput{}). //nolint:exhaustruct
+		HasResponse(http.StatusOK)
+
+	routes.
+		Route("PUT /moderation/users/{id}/suspension", func(ctx *httpfx.Context) httpfx.Result {
+			user, failure := requireModerator(ctx)
+			if failure != nil {
+				return *failure
+			}
+
+			var input moderation.SuspendInput
+
+			err := json.NewDecoder(ctx.Request.Body).Decode(&input)
+			if err != nil {
+				return ctx.Results.BadRequest()
+			}
+
+			service, err := newModerationService(appContext)
+			if err != nil {
+				return ctx.Results.Error(http.StatusInternalServerError, []byte(err.Error()))
+			}
+
+			err = service.Suspend(ctx.Request.Context(), user.Id, ctx.Request.PathValue("id"), &input)
+			if err != nil {
+				return moderationErrorResult(ctx, err)
+			}
+
+			return ctx.Results.Ok()
+		}).
+		HasSummary("Suspend user").
+		HasDescription("Suspends a user, logging them out everywhere and keeping them from writing. Moderators only.").
+		HasPathParameter("id", "The id of the user").
+		HasRequestModel(moderation.SuspendInput{}). //nolint:exhaustruct
+		HasResponse(http.StatusOK)
+
+	routes.
+		Route("DELETE /moderation/users/{id}/suspension", func(ctx *httpfx.Context) httpfx.Result {
+			user, failure := requireModerator(ctx)
+			if failure != nil {
+				return *failure
+			}
+
+			service, err := newModerationService(appContext)
+			if err != nil {
+				return ctx.Results.Error(http.StatusInternalServerError, []byte(err.Error()))
+			}
+
+			err = service.LiftSuspension(ctx.Request.Context(), user.Id, ctx.Request.PathValue("id"))
+			if err != nil {
+				return moderationErrorResult(ctx, err)
+			}
+
+			return ctx.Results.Ok()
+		}).
+		HasSummary("Lift user suspension").
+		HasDescription("Lets a suspended user log in again. Moderators only.").
+		HasPathParameter("id", "The id of the user").
+		HasResponse(http.StatusOK)
+}
+
+func newModerationService(appContext *appcontext.AppContext) (*moderation.Service, error) {
+	store, err := storage.NewFromDefault(appContext.Data)
+	if err != nil {
+		return nil, err //nolint:wrapcheck
+	}
+
+	return moderation.NewService(
+		store,
+		users.NewService(store),
+		audit.NewService(store),
+		newOutbox(appContext, store),
+	), nil
+}
+
+func moderationErrorResult(ctx *httpfx.Context, err error) httpfx.Result {
+	switch {
+	case errors.Is(err, moderation.ErrRecordNotFound), errors.Is(err, users.ErrRecordNotFound):
+		return ctx.Results.NotFound()
+	case errors.Is(err, moderation.ErrInvalidInput), errors.Is(err, moderation.ErrActionNotSupported):
+		return ctx.Results.Error(http.StatusBadRequest, []byte(err.Error()))
+	case errors.Is(err, moderation.ErrAlreadyReported), errors.Is(err, moderation.ErrReportClosed):
+		return ctx.Results.Error(http.StatusConflict, []byte(err.Error()))
+	case errors.Is(err, moderation.ErrCannotSuspendModerator), errors.Is(err, users.ErrUserSuspended):
+		return ctx.Results.Error(http.StatusForbidden, []byte(err.Error()))
+	default:
+		return ctx.Results.Error(http.StatusInternalServerError, []byte(err.Error()))
+	}
+}
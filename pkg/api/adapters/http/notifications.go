@@ -0,0 +1,176 @@
+package http
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/eser/acik.io/pkg/api/adapters/appcontext"
+	"github.com/eser/acik.io/pkg/api/adapters/storage"
+	"github.com/eser/acik.io/pkg/api/business/notifications"
+	"github.com/eser/acik.io/pkg/api/business/users"
+	"github.com/eser/ajan/httpfx"
+)
+
+func RegisterHttpRoutesForNotifications(routes *httpfx.Router, appContext *appcontext.AppContext) { //nolint:funlen
+	routes.
+		Route("GET /me/notifications", func(ctx *httpfx.Context) httpfx.Result {
+			user, hasUser := GetSessionUser(ctx)
+			if !hasUser {
+				return ctx.Results.Unauthorized([]byte("Authentication required"))
+			}
+
+			service, err := newNotificationsService(appContext)
+			if err != nil {
+				return ctx.Results.Error(http.StatusInternalServerError, []byte(err.Error()))
+			}
+
+			limit, offset := getPagination(ctx)
+			unreadOnly := ctx.Request.URL.Query().Get("unread") == "true"
+
+			inbox, err := service.Inbox(ctx.Request.Context(), user.Id, unreadOnly, limit, offset)
+			if err != nil {
+				return notificationsErrorResult(ctx, err)
+			}
+
+			return ctx.Results.Json(inbox)
+		}).
+		HasSummary("List notifications").
+		HasDescription("Lists the in-app notifications of the current user, the latest first.").
+		HasQueryParameter("unread", "Only list unread notifications when true").
+		HasQueryParameter("limit", "Maximum number of notifications to return").
+		HasQueryParameter("offset", "Number of notifications to skip").
+		HasResponse(http.StatusOK)
+
+	routes.
+		Route("POST /me/notifications/{id}/read", func(ctx *httpfx.Context) httpfx.Result {
+			user, hasUser := GetSessionUser(ctx)
+			if !hasUser {
+				return ctx.Results.Unauthorized([]byte("Authentication required"))
+			}
+
+			service, err := newNotificationsService(appContext)
+			if err != nil {
+				return ctx.Results.Error(http.StatusInternalServerError, []byte(err.Error()))
+			}
+
+			err = service.MarkRead(ctx.Request.Context(), user.Id, ctx.Request.PathValue("id"))
+			if err != nil {
+				return notificationsErrorResult(ctx, err)
+			}
+
+			return ctx.Results.Ok()
+		}).
+		HasSummary("Mark notification read").
+		HasDescription("Marks a notification of the current user as read.").
+		HasPathParameter("id", "The id of the notification").
+		HasResponse(http.StatusOK)
+
+	routes.
+		Route("POST /me/notifications/read-all", func(ctx *httpfx.Context) httpfx.Result {
+			user, hasUser := GetSessionUser(ctx)
+			if !hasUser {
+				return ctx.Results.Unauthorized([]byte("Authentication required"))
+			}
+
+			service, err := newNotificationsService(appContext)
+			if err != nil {
+				return ctx.Results.Error(http.StatusInternalServerError, []byte(err.Error()))
+			}
+
+			affected, err := service.MarkAllRead(ctx.Request.Context(), user.Id)
+			if err != nil {
+				return notificationsErrorResult(ctx, err)
+			}
+
+			return ctx.Results.Json(map[string]int64{"marked": affected})
+		}).
+		HasSummary("Mark all notifications read").
+		HasDescription("Marks every unread notification of the current user as read.").
+		HasResponse(http.StatusOK)
+
+	routes.
+		Route("GET /me/notifications/preferences", func(ctx *httpfx.Context) httpfx.Result {
+			user, hasUser := GetSessionUser(ctx)
+			if !hasUser {
+				return ctx.Results.Unauthorized([]byte("Authentication required"))
+			}
+
+			service, err := newNotificationsService(appContext)
+			if err != nil {
+				return ctx.Results.Error(http.StatusInternalServerError, []byte(err.Error()))
+			}
+
+			preferences, err := service.Preferences(ctx.Request.Context(), user.Id)
+			if err != nil {
+				return notificationsErrorResult(ctx, err)
+			}
+
+			return ctx.Results.Json(preferences)
+		}).
+		HasSummary("List notification preferences").
+		HasDescription("Lists the channels the current user is notified through, per notification type.").
+		HasResponse(http.StatusOK)
+
+	routes.
+		Route("PUT /me/notifications/preferences/{type}", func(ctx *httpfx.Context) httpfx.Result {
+			user, hasUser := GetSessionUser(ctx)
+			if !hasUser {
+				return ctx.Results.Unauthorized([]byte("Authentication required"))
+			}
+
+			var body notifications.PreferenceInput
+
+			err := json.NewDecoder(ctx.Request.Body).Decode(&body)
+			if err != nil {
+				return ctx.Results.BadRequest()
+			}
+
+			service, err := newNotificationsService(appContext)
+			if err != nil {
+				return ctx.Results.Error(http.StatusInternalServerError, []byte(err.Error()))
+			}
+
+			preference, err := service.SetPreference(ctx.Request.Context(), user.Id, ctx.Request.PathValue("type"), &body)
+			if err != nil {
+				return notificationsErrorResult(ctx, err)
+			}
+
+			return ctx.Results.Json(preference)
+		}).
+		HasSummary("Set notification preference").
+		HasDescription("Sets the channels the current user is notified through for a type. No channels mutes it.").
+		HasPathParameter("type", "The notification type").
+		HasRequestModel(notifications.PreferenceInput{}). //nolint:exhaustruct
+		HasResponse(http.StatusOK)
+}
+
+// newNotificationsService returns the notifications service for the inbox
+// and the preferences. Out of band channels are delivered by the background
+// worker, so none are set up here.
+func newNotificationsService(appContext *appcontext.AppContext) (*notifications.Service, error) {
+	store, err := storage.NewFromDefault(appContext.Data)
+	if err != nil {
+		return nil, err //nolint:wrapcheck
+	}
+
+	return notifications.NewService(
+		&appContext.Config.Notifications,
+		store,
+		users.NewService(store),
+		newEventsService(appContext, store),
+		newEventRecorder(appContext, store),
+		nil,
+	), nil
+}
+
+func notificationsErrorResult(ctx *httpfx.Context, err error) httpfx.Result {
+	switch {
+	case errors.Is(err, notifications.ErrRecordNotFound):
+		return ctx.Results.NotFound()
+	case errors.Is(err, notifications.ErrUnknownType), errors.Is(err, notifications.ErrUnknownChannel):
+		return ctx.Results.Error(http.StatusBadRequest, []byte(err.Error()))
+	default:
+		return ctx.Results.Error(http.StatusInternalServerError, []byte(err.Error()))
+	}
+}
@@ -0,0 +1,34 @@
+package http
+
+import (
+	"strconv"
+
+	"github.com/eser/ajan/httpfx"
+)
+
+const (
+	DefaultPageLimit = 20
+	MaxPageLimit     = 100
+)
+
+// getPagination reads the `limit` and `offset` query parameters, falling
+// back to sane defaults when they are missing or out of range.
+func getPagination(ctx *httpfx.Context) (int32, int32) {
+	query := ctx.Request.URL.Query()
+
+	limit, err := strconv.ParseInt(query.Get("limit"), 10, 32)
+	if err != nil || limit <= 0 {
+		limit = DefaultPageLimit
+	}
+
+	if limit > MaxPageLimit {
+		limit = MaxPageLimit
+	}
+
+	offset, err := strconv.ParseInt(query.Get("offset"), 10, 32)
+	if err != nil || offset < 0 {
+		offset = 0
+	}
+
+	return int32(limit), int32(offset)
+}
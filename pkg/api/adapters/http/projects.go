@@ -0,0 +1,236 @@
+package http
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/eser/acik.io/pkg/api/adapters/appcontext"
+	"github.com/eser/acik.io/pkg/api/adapters/github"
+	"github.com/eser/acik.io/pkg/api/adapters/queue"
+	"github.com/eser/acik.io/pkg/api/adapters/storage"
+	"github.com/eser/acik.io/pkg/api/business/projects"
+	"github.com/eser/acik.io/pkg/api/business/slugs"
+	"github.com/eser/acik.io/pkg/api/business/users"
+	"github.com/eser/ajan/httpfx"
+)
+
+func RegisterHttpRoutesForProjects(routes *httpfx.Router, appContext *appcontext.AppContext) { //nolint:funlen
+	routes.
+		Route("GET /profiles/{slug}/projects", SlugRedirectMiddleware(appContext, slugs.KindProfile, "/profiles/"), func(ctx *httpfx.Context) httpfx.Result {
+			service, err := newProjectsService(appContext)
+			if err != nil {
+				return ctx.Results.Error(http.StatusInternalServerError, []byte(err.Error()))
+			}
+
+			userId := ""
+			if user, hasUser := GetSessionUser(ctx); hasUser {
+				userId = user.Id
+			}
+
+			tagId, hasTag, err := getTagFilter(ctx, appContext)
+			if err != nil {
+				return ctx.Results.Error(http.StatusInternalServerError, []byte(err.Error()))
+			}
+
+			if !hasTag {
+				return ctx.Results.Json([]*projects.ProjectView{})
+			}
+
+			limit, offset := getPagination(ctx)
+
+			records, err := service.ListByProfile(
+				ctx.Request.Context(),
+				ctx.Request.PathValue("slug"),
+				userId,
+				tagId,
+				limit,
+				offset,
+			)
+			if err != nil {
+				return projectsErrorResult(ctx, err)
+			}
+
+			return ctx.Results.Json(records)
+		}).
+		HasSummary("List profile projects").
+		HasDescription("List projects of a profile, if the profile shows its projects or the user is a member.").
+		HasPathParameter("slug", "The slug of the profile").
+		HasQueryParameter("tag", "Only list projects with this tag").
+		HasQueryParameter("limit", "Maximum number of projects to return").
+		HasQueryParameter("offset", "Number of projects to skip").
+		HasResponse(http.StatusOK)
+
+	routes.
+		Route("GET /profiles/{slug}/projects/{projectSlug}", SlugRedirectMiddleware(appContext, slugs.KindProfile, "/profiles/"), func(ctx *httpfx.Context) httpfx.Result {
+			service, err := newProjectsService(appContext)
+			if err != nil {
+				return ctx.Results.Error(http.StatusInternalServerError, []byte(err.Error()))
+			}
+
+			userId := ""
+			if user, hasUser := GetSessionUser(ctx); hasUser {
+				userId = user.Id
+			}
+
+			record, err := service.Get(
+				ctx.Request.Context(),
+				ctx.Request.PathValue("slug"),
+				ctx.Request.PathValue("projectSlug"),
+				userId,
+			)
+			if err != nil {
+				return projectsErrorResult(ctx, err)
+			}
+
+			if checkNotModified(ctx, record.ETag(), record.Version()) {
+				return notModifiedResult(ctx)
+			}
+
+			return ctx.Results.Json(record)
+		}).
+		HasSummary("Get project").
+		HasDescription("Get a project of a profile, with the metadata of its repository when available.").
+		HasPathParameter("slug", "The slug of the profile").
+		HasPathParameter("projectSlug", "The slug of the project").
+		HasResponse(http.StatusOK)
+
+	routes.
+		Route("POST /profiles/{slug}/projects", IdempotencyMiddleware(appContext), func(ctx *httpfx.Context) httpfx.Result {
+			user, hasUser := GetSessionUser(ctx)
+			if !hasUser {
+				return ctx.Results.Unauthorized([]byte("Authentication required"))
+			}
+
+			var body projects.CreateProjectInput
+
+			err := json.NewDecoder(ctx.Request.Body).Decode(&body)
+			if err != nil {
+				return ctx.Results.BadRequest()
+			}
+
+			service, err := newProjectsService(appContext)
+			if err != nil {
+				return ctx.Results.Error(http.StatusInternalServerError, []byte(err.Error()))
+			}
+
+			record, err := service.Create(ctx.Request.Context(), user.Id, ctx.Request.PathValue("slug"), &body)
+			if err != nil {
+				return projectsErrorResult(ctx, err)
+			}
+
+			return ctx.Results.Json(record).WithStatusCode(http.StatusCreated)
+		}).
+		HasSummary("Create project").
+		HasDescription("Create a project on a profile. Members only. Retries sent with the same Idempotency-Key header get the response of the first request.").
+		HasPathParameter("slug", "The slug of the profile").
+		HasRequestModel(projects.CreateProjectInput{}). //nolint:exhaustruct
+		HasResponse(http.StatusCreated)
+
+	routes.
+		Route("PUT /profiles/{slug}/projects/{projectSlug}", func(ctx *httpfx.Context) httpfx.Result {
+			user, hasUser := GetSessionUser(ctx)
+			if !hasUser {
+				return ctx.Results.Unauthorized([]byte("Authentication required"))
+			}
+
+			var body projects.UpdateProjectInput
+
+			err := json.NewDecoder(ctx.Request.Body).Decode(&body)
+			if err != nil {
+				return ctx.Results.BadRequest()
+			}
+
+			service, err := newProjectsService(appContext)
+			if err != nil {
+				return ctx.Results.Error(http.StatusInternalServerError, []byte(err.Error()))
+			}
+
+			body.IfMatch = ctx.Request.Header.Get("If-Match")
+
+			record, err := service.Update(
+				ctx.Request.Context(),
+				user.Id,
+				ctx.Request.PathValue("slug"),
+				ctx.Request.PathValue("projectSlug"),
+				&body,
+			)
+			if err != nil {
+				return projectsErrorResult(ctx, err)
+			}
+
+			ctx.ResponseWriter.Header().Set("ETag", record.ETag())
+
+			return ctx.Results.Json(record)
+		}).
+		HasSummary("Update project").
+		HasDescription("Update a project of a profile. Members only. With an If-Match header, the project is only updated while it still has one of the given ETags, and 412 is returned otherwise.").
+		HasPathParameter("slug", "The slug of the profile").
+		HasPathParameter("projectSlug", "The slug of the project").
+		HasRequestModel(projects.UpdateProjectInput{}). //nolint:exhaustruct
+		HasResponse(http.StatusOK)
+
+	routes.
+		Route("DELETE /profiles/{slug}/projects/{projectSlug}", func(ctx *httpfx.Context) httpfx.Result {
+			user, hasUser := GetSessionUser(ctx)
+			if !hasUser {
+				return ctx.Results.Unauthorized([]byte("Authentication required"))
+			}
+
+			service, err := newProjectsService(appContext)
+			if err != nil {
+				return ctx.Results.Error(http.StatusInternalServerError, []byte(err.Error()))
+			}
+
+			err = service.Delete(
+				ctx.Request.Context(),
+				user.Id,
+				ctx.Request.PathValue("slug"),
+				ctx.Request.PathValue("projectSlug"),
+			)
+			if err != nil {
+				return projectsErrorResult(ctx, err)
+			}
+
+			return ctx.Results.Ok()
+		}).
+		HasSummary("Delete project").
+		HasDescription("Delete a project of a profile. Members only.").
+		HasPathParameter("slug", "The slug of the profile").
+		HasPathParameter("projectSlug", "The slug of the project").
+		HasResponse(http.StatusOK)
+}
+
+func newProjectsService(appContext *appcontext.AppContext) (*projects.Service, error) {
+	store, err := storage.NewFromDefault(appContext.Data)
+	if err != nil {
+		return nil, err //nolint:wrapcheck
+	}
+
+	return projects.NewService(
+		&appContext.Config.Projects,
+		store,
+		newProfilesService(appContext, store),
+		newTagsService(appContext, store),
+		github.NewClient(&appContext.Config.Projects),
+		queue.NewFromDefault(appContext.Queue),
+		newEventRecorder(appContext, store),
+	), nil
+}
+
+func projectsErrorResult(ctx *httpfx.Context, err error) httpfx.Result {
+	switch {
+	case errors.Is(err, projects.ErrRecordNotFound):
+		return ctx.Results.NotFound()
+	case errors.Is(err, projects.ErrInvalidInput):
+		return ctx.Results.Error(http.StatusBadRequest, []byte(err.Error()))
+	case errors.Is(err, projects.ErrNotMember):
+		return ctx.Results.Error(http.StatusForbidden, []byte(err.Error()))
+	case errors.Is(err, projects.ErrVersionMismatch):
+		return ctx.Results.Error(http.StatusPreconditionFailed, []byte(err.Error()))
+	case errors.Is(err, users.ErrUserSuspended):
+		return ctx.Results.Error(http.StatusForbidden, []byte(err.Error()))
+	default:
+		return ctx.Results.Error(http.StatusInternalServerError, []byte(err.Error()))
+	}
+}
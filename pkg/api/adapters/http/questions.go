@@ -0,0 +1,174 @@
+package http
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/eser/acik.io/pkg/api/adapters/appcontext"
+	"github.com/eser/acik.io/pkg/api/adapters/storage"
+	"github.com/eser/acik.io/pkg/api/business/audit"
+	"github.com/eser/acik.io/pkg/api/business/moderation"
+	"github.com/eser/acik.io/pkg/api/business/questions"
+	"github.com/eser/acik.io/pkg/api/business/ratelimit"
+	"github.com/eser/acik.io/pkg/api/business/users"
+	"github.com/eser/ajan/httpfx"
+)
+
+func RegisterHttpRoutesForQuestions(routes *httpfx.Router, appContext *appcontext.AppContext) {
+	routes.
+		Route("POST /questions", RateLimitPolicyMiddleware(ratelimit.PolicyQuestions), IdempotencyMiddleware(appContext), func(ctx *httpfx.Context) httpfx.Result {
+			user, hasUser := GetSessionUser(ctx)
+			if !hasUser {
+				return ctx.Results.Unauthorized([]byte("Authentication required"))
+			}
+
+			var input questions.CreateInput
+
+			err := json.NewDecoder(ctx.Request.Body).Decode(&input)
+			if err != nil {
+				return ctx.Results.BadRequest()
+			}
+
+			service, err := newQuestionsService(appContext)
+			if err != nil {
+				return ctx.Results.Error(http.StatusInternalServerError, []byte(err.Error()))
+			}
+
+			record, err := service.Create(ctx.Request.Context(), user.Id, &input)
+			if err != nil {
+				return questionsErrorResult(ctx, err)
+			}
+
+			return ctx.Results.Json(record).WithStatusCode(http.StatusCreated)
+		}).
+		HasSummary("Ask question").
+		HasDescription("Asks a question, optionally anonymously. Questions the content filter holds back are stored hidden, with isHidden set, until a moderator lets them through. Retries sent with the same Idempotency-Key header get the response of the first request.").
+		HasRequestModel(questions.CreateInput{}). //nolint:exhaustruct
+		HasResponse(http.StatusCreated)
+
+	routes.
+		Route("POST /questions/{id}/answer", func(ctx *httpfx.Context) httpfx.Result {
+			_, failure := requireAdmin(ctx)
+			if failure != nil {
+				return *failure
+			}
+
+			var input questions.AnswerInput
+
+			err := json.NewDecoder(ctx.Request.Body).Decode(&input)
+			if err != nil {
+				return ctx.Results.BadRequest()
+			}
+
+			service, err := newQuestionsService(appContext)
+			if err != nil {
+				return ctx.Results.Error(http.StatusInternalServerError, []byte(err.Error()))
+			}
+
+			record, err := service.Answer(ctx.Request.Context(), ctx.Request.PathValue("id"), &input)
+			if err != nil {
+				return questionsErrorResult(ctx, err)
+			}
+
+			return ctx.Results.Json(record)
+		}).
+		HasSummary("Answer question").
+		HasDescription("Answers a question in writing (kind text, with content) or with a link to its recording (kind video, with uri). The asker is notified. Admins only.").
+		HasPathParameter("id", "The id of the question").
+		HasRequestModel(questions.AnswerInput{}). //nolint:exhaustruct
+		HasResponse(http.StatusOK)
+
+	routes.
+		Route("PUT /questions/{id}/vote", RateLimitPolicyMiddleware(ratelimit.PolicyVotes), func(ctx *httpfx.Context) httpfx.Result {
+			user, hasUser := GetSessionUser(ctx)
+			if !hasUser {
+				return ctx.Results.Unauthorized([]byte("Authentication required"))
+			}
+
+			var input questions.VoteInput
+
+			err := json.NewDecoder(ctx.Request.Body).Decode(&input)
+			if err != nil {
+				return ctx.Results.BadRequest()
+			}
+
+			service, err := newQuestionsService(appContext)
+			if err != nil {
+				return ctx.Results.Error(http.StatusInternalServerError, []byte(err.Error()))
+			}
+
+			err = service.Vote(ctx.Request.Context(), user.Id, ctx.Request.PathValue("id"), &input)
+			if err != nil {
+				return questionsErrorResult(ctx, err)
+			}
+
+			return ctx.Results.Ok()
+		}).
+		HasSummary("Vote question").
+		HasDescription("Votes a question up with a score of 1 or down with -1, replacing the earlier vote of the current user. Questions are ranked by their votes as of the next vote score refresh.").
+		HasPathParameter("id", "The id of the question").
+		HasRequestModel(questions.VoteInput{}). //nolint:exhaustruct
+		HasResponse(http.StatusOK)
+
+	routes.
+		Route("DELETE /questions/{id}/vote", RateLimitPolicyMiddleware(ratelimit.PolicyVotes), func(ctx *httpfx.Context) httpfx.Result {
+			user, hasUser := GetSessionUser(ctx)
+			if !hasUser {
+				return ctx.Results.Unauthorized([]byte("Authentication required"))
+			}
+
+			service, err := newQuestionsService(appContext)
+			if err != nil {
+				return ctx.Results.Error(http.StatusInternalServerError, []byte(err.Error()))
+			}
+
+			err = service.Unvote(ctx.Request.Context(), user.Id, ctx.Request.PathValue("id"))
+			if err != nil {
+				return questionsErrorResult(ctx, err)
+			}
+
+			return ctx.Results.Ok()
+		}).
+		HasSummary("Withdraw question vote").
+		HasDescription("Withdraws the vote of the current user on a question.").
+		HasPathParameter("id", "The id of the question").
+		HasResponse(http.StatusOK)
+}
+
+func newQuestionsService(appContext *appcontext.AppContext) (*questions.Service, error) {
+	store, err := storage.NewFromDefault(appContext.Data)
+	if err != nil {
+		return nil, err //nolint:wrapcheck
+	}
+
+	filters, err := questions.NewPipelineFromConfig(&appContext.Config.Questions, store)
+	if err != nil {
+		return nil, err //nolint:wrapcheck
+	}
+
+	userService := users.NewService(store)
+
+	return questions.NewService(
+		store,
+		userService,
+		filters,
+		moderation.NewService(store, userService, audit.NewService(store), newOutbox(appContext, store)),
+		newEventRecorder(appContext, store),
+	), nil
+}
+
+func questionsErrorResult(ctx *httpfx.Context, err error) httpfx.Result {
+	switch {
+	case errors.Is(err, questions.ErrInvalidInput):
+		return ctx.Results.Error(http.StatusBadRequest, []byte(err.Error()))
+	case errors.Is(err, users.ErrUserSuspended):
+		return ctx.Results.Error(http.StatusForbidden, []byte(err.Error()))
+	case errors.Is(err, users.ErrRecordNotFound), errors.Is(err, questions.ErrRecordNotFound):
+		return ctx.Results.NotFound()
+	case errors.Is(err, questions.ErrAlreadyAnswered):
+		return ctx.Results.Error(http.StatusConflict, []byte(err.Error()))
+	default:
+		return ctx.Results.Error(http.StatusInternalServerError, []byte(err.Error()))
+	}
+}
@@ -0,0 +1,175 @@
+package http
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/eser/acik.io/pkg/api/adapters/appcontext"
+	"github.com/eser/acik.io/pkg/api/adapters/ratelimitstore"
+	"github.com/eser/acik.io/pkg/api/adapters/storage"
+	"github.com/eser/acik.io/pkg/api/business/ratelimit"
+	"github.com/eser/ajan/httpfx"
+	"github.com/eser/ajan/logfx"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const (
+	RateLimitLimitHeader     = "RateLimit-Limit"
+	RateLimitRemainingHeader = "RateLimit-Remaining"
+	RateLimitResetHeader     = "RateLimit-Reset"
+	RateLimitPolicyHeader    = "RateLimit-Policy"
+	RetryAfterHeader         = "Retry-After"
+
+	ContextKeyRateLimitGate httpfx.ContextKey = "rate-limit-gate"
+
+	rateLimitOutcomeAllowed = "allowed"
+	rateLimitOutcomeLimited = "limited"
+	rateLimitOutcomeError   = "error"
+)
+
+type RateLimitMetrics struct {
+	RequestsTotal *prometheus.CounterVec
+	TakeDuration  *prometheus.HistogramVec
+}
+
+func NewRateLimitMetrics(registry *prometheus.Registry) *RateLimitMetrics {
+	requestsTotal := prometheus.NewCounterVec(
+		prometheus.CounterOpts{ //nolint:exhaustruct
+			Name: "http_rate_limit_requests_total",
+			Help: "Total number of rate limited requests by policy and outcome",
+		},
+		[]string{"policy", "outcome"},
+	)
+
+	takeDuration := prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{ //nolint:exhaustruct
+			Name:    "http_rate_limit_take_duration_seconds",
+			Help:    "Duration of taking a token from the bucket store",
+			Buckets: prometheus.DefBuckets,
+		},
+		[]string{"policy"},
+	)
+
+	registry.MustRegister(requestsTotal, takeDuration)
+
+	return &RateLimitMetrics{
+		RequestsTotal: requestsTotal,
+		TakeDuration:  takeDuration,
+	}
+}
+
+func newRateLimiter(appContext *appcontext.AppContext) (*ratelimit.Limiter, error) {
+	store, err := storage.NewFromDefault(appContext.Data)
+	if err != nil {
+		return nil, err //nolint:wrapcheck
+	}
+
+	buckets, err := ratelimitstore.NewFromConfig(&appContext.Config.RateLimit, store)
+	if err != nil {
+		return nil, err //nolint:wrapcheck
+	}
+
+	return ratelimit.NewLimiter(&appContext.Config.RateLimit, buckets) //nolint:wrapcheck
+}
+
+// rateLimitGate takes tokens from the buckets of the policies a request is
+// held to.
+type rateLimitGate struct {
+	limiter *ratelimit.Limiter
+	metrics *RateLimitMetrics
+	logger  *logfx.Logger
+}
+
+// RateLimitMiddleware holds every request that changes state to the write
+// policy, with a bucket per user, or per client address for guests, and
+// lets RateLimitPolicyMiddleware hold routes to policies of their own on top
+// of it. Reads aren't limited. It must run after the session middleware.
+// The limiter fails open: requests are let through when the bucket store
+// can't be reached.
+func RateLimitMiddleware(
+	limiter *ratelimit.Limiter,
+	metrics *RateLimitMetrics,
+	logger *logfx.Logger,
+) httpfx.Handler {
+	gate := &rateLimitGate{limiter: limiter, metrics: metrics, logger: logger}
+
+	return func(ctx *httpfx.Context) httpfx.Result {
+		ctx.UpdateContext(context.WithValue(ctx.Request.Context(), ContextKeyRateLimitGate, gate))
+
+		switch ctx.Request.Method {
+		case http.MethodGet, http.MethodHead, http.MethodOptions:
+			return ctx.Next()
+		default:
+			return gate.take(ctx, ratelimit.PolicyWrite)
+		}
+	}
+}
+
+// RateLimitPolicyMiddleware holds a route to a policy of its own, besides the
+// write policy every change falls under. The RateLimit headers tell the
+// state of this policy. Routes opt in by listing it before their handler:
+//
+//	routes.Route("POST /things", RateLimitPolicyMiddleware(ratelimit.PolicyThings), handler)
+func RateLimitPolicyMiddleware(policyName string) httpfx.Handler {
+	return func(ctx *httpfx.Context) httpfx.Result {
+		gate, ok := ctx.Request.Context().Value(ContextKeyRateLimitGate).(*rateLimitGate)
+		if !ok {
+			return ctx.Next()
+		}
+
+		return gate.take(ctx, policyName)
+	}
+}
+
+// take lets the request through if the bucket of its identity under the
+// policy has a token, and answers 429 otherwise.
+func (g *rateLimitGate) take(ctx *httpfx.Context, policyName string) httpfx.Result {
+	if !g.limiter.Enabled() {
+		return ctx.Next()
+	}
+
+	identity := requestIdentity(ctx)
+
+	started := time.Now()
+	decision, err := g.limiter.Allow(ctx.Request.Context(), policyName, identity)
+	g.metrics.TakeDuration.WithLabelValues(policyName).Observe(time.Since(started).Seconds())
+
+	if err != nil {
+		g.metrics.RequestsTotal.WithLabelValues(policyName, rateLimitOutcomeError).Inc()
+		g.logger.WarnContext(ctx.Request.Context(), "rate limiter unavailable", "policy", policyName, "error", err)
+
+		return ctx.Next()
+	}
+
+	policy, err := g.limiter.Policy(policyName)
+	if err != nil {
+		return ctx.Results.Error(http.StatusInternalServerError, []byte(err.Error()))
+	}
+
+	header := ctx.ResponseWriter.Header()
+	header.Set(RateLimitLimitHeader, strconv.Itoa(decision.Limit))
+	header.Set(RateLimitRemainingHeader, strconv.Itoa(decision.Remaining))
+	header.Set(RateLimitResetHeader, seconds(decision.Reset))
+	header.Set(RateLimitPolicyHeader, fmt.Sprintf("%d;w=%s", policy.Limit, seconds(policy.Window)))
+
+	if !decision.Allowed {
+		g.metrics.RequestsTotal.WithLabelValues(policyName, rateLimitOutcomeLimited).Inc()
+		header.Set(RetryAfterHeader, seconds(decision.RetryAfter))
+
+		return ctx.Results.Error(http.StatusTooManyRequests, []byte("Too many requests"))
+	}
+
+	g.metrics.RequestsTotal.WithLabelValues(policyName, rateLimitOutcomeAllowed).Inc()
+
+	return ctx.Next()
+}
+
+// seconds formats a duration as whole seconds, rounded up so clients
+// waiting that long find a token.
+func seconds(d time.Duration) string {
+	return strconv.FormatInt(int64(math.Ceil(d.Seconds())), 10)
+}
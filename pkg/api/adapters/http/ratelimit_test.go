@@ -0,0 +1,99 @@
+package http
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/eser/acik.io/pkg/api/adapters/ratelimitstore"
+	"github.com/eser/acik.io/pkg/api/business/ratelimit"
+	"github.com/eser/ajan/httpfx"
+	"github.com/eser/ajan/logfx"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+func newRateLimitedRouter(t *testing.T, policies string) http.Handler {
+	t.Helper()
+
+	limiter, err := ratelimit.NewLimiter(
+		&ratelimit.Config{Enabled: true, Backend: "memory", Policies: policies, IdleTtl: time.Hour},
+		ratelimitstore.NewMemoryStore(time.Hour),
+	)
+	if err != nil {
+		t.Fatalf("creating limiter: %v", err)
+	}
+
+	logger, err := logfx.NewLogger(io.Discard, &logfx.Config{}) //nolint:exhaustruct
+	if err != nil {
+		t.Fatalf("creating logger: %v", err)
+	}
+
+	ok := func(ctx *httpfx.Context) httpfx.Result {
+		return ctx.Results.Json([]string{})
+	}
+
+	routes := httpfx.NewRouter("/")
+	routes.Use(RateLimitMiddleware(limiter, NewRateLimitMetrics(prometheus.NewRegistry()), logger))
+	routes.Route("GET /questions", ok)
+	routes.Route("POST /questions", ok)
+	routes.Route("PUT /questions/{id}/vote", RateLimitPolicyMiddleware(ratelimit.PolicyVotes), ok)
+
+	return routes.GetMux()
+}
+
+func send(handler http.Handler, method string, path string) *httptest.ResponseRecorder {
+	resp := httptest.NewRecorder()
+	handler.ServeHTTP(resp, httptest.NewRequest(method, path, nil))
+
+	return resp
+}
+
+func TestRateLimitWritePolicy(t *testing.T) {
+	t.Parallel()
+
+	handler := newRateLimitedRouter(t, "write=2/1h")
+
+	for range 2 {
+		if resp := send(handler, http.MethodPost, "/questions"); resp.Code != http.StatusOK {
+			t.Fatalf("got %d within the write policy, want 200", resp.Code)
+		}
+	}
+
+	resp := send(handler, http.MethodPost, "/questions")
+	if resp.Code != http.StatusTooManyRequests {
+		t.Fatalf("got %d past the write policy, want 429", resp.Code)
+	}
+
+	if resp.Header().Get(RetryAfterHeader) == "" {
+		t.Errorf("got no %s header on 429", RetryAfterHeader)
+	}
+
+	if resp := send(handler, http.MethodGet, "/questions"); resp.Code != http.StatusOK {
+		t.Errorf("got %d for a read, want reads unlimited", resp.Code)
+	}
+}
+
+func TestRateLimitRoutePolicy(t *testing.T) {
+	t.Parallel()
+
+	handler := newRateLimitedRouter(t, "write=10/1h,votes=1/1h")
+
+	resp := send(handler, http.MethodPut, "/questions/1/vote")
+	if resp.Code != http.StatusOK {
+		t.Fatalf("got %d within the votes policy, want 200", resp.Code)
+	}
+
+	if policy := resp.Header().Get(RateLimitPolicyHeader); policy != "1;w=3600" {
+		t.Errorf("got %s %q, want the one of the votes policy", RateLimitPolicyHeader, policy)
+	}
+
+	if resp := send(handler, http.MethodPut, "/questions/2/vote"); resp.Code != http.StatusTooManyRequests {
+		t.Errorf("got %d past the votes policy, want 429", resp.Code)
+	}
+
+	if resp := send(handler, http.MethodPost, "/questions"); resp.Code != http.StatusOK {
+		t.Errorf("got %d for a route without a policy of its own, want only the write policy to hold", resp.Code)
+	}
+}
@@ -0,0 +1,38 @@
+package http
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+
+	"github.com/eser/acik.io/pkg/api/business/readcache"
+	"github.com/eser/ajan/httpfx"
+	"github.com/eser/ajan/logfx"
+)
+
+// ReadCacheMiddleware invalidates the read cache entries made stale by the
+// changes a request makes, as soon as it is handled. Without it, the memory
+// entries of the instance would only run out with their ttl, as domain
+// events are handled by the worker.
+func ReadCacheMiddleware(invalidator *readcache.Invalidator, logger *logfx.Logger) httpfx.Handler {
+	return func(ctx *httpfx.Context) httpfx.Result {
+		switch ctx.Request.Method {
+		case http.MethodGet, http.MethodHead, http.MethodOptions:
+			return ctx.Next()
+		}
+
+		requestCtx, pending := readcache.WithPending(ctx.Request.Context())
+		ctx.UpdateContext(requestCtx)
+
+		result := ctx.Next()
+
+		// a failed change leaves nothing committed; invalidating anyway
+		// costs a miss at most.
+		err := invalidator.Flush(context.WithoutCancel(requestCtx), pending)
+		if err != nil {
+			logger.WarnContext(requestCtx, "Read cache invalidation failed", slog.Any("error", err))
+		}
+
+		return result
+	}
+}
@@ -0,0 +1,69 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/eser/acik.io/pkg/api/adapters/appcontext"
+	"github.com/eser/acik.io/pkg/api/adapters/storage"
+	"github.com/eser/acik.io/pkg/api/business/search"
+	"github.com/eser/ajan/httpfx"
+)
+
+func RegisterHttpRoutesForSearch(routes *httpfx.Router, appContext *appcontext.AppContext) {
+	routes.
+		Route("GET /search", func(ctx *httpfx.Context) httpfx.Result {
+			store, err := storage.NewFromDefault(appContext.Data)
+			if err != nil {
+				return ctx.Results.Error(http.StatusInternalServerError, []byte(err.Error()))
+			}
+
+			tagId, hasTag, err := getTagFilter(ctx, appContext)
+			if err != nil {
+				return ctx.Results.Error(http.StatusInternalServerError, []byte(err.Error()))
+			}
+
+			query := ctx.Request.URL.Query()
+			limit, offset := getPagination(ctx)
+
+			if !hasTag {
+				return ctx.Results.Json(&search.Page{Results: []*search.Result{}, Total: 0, Limit: limit, Offset: offset})
+			}
+
+			var types []string
+			if value := query.Get("types"); value != "" {
+				types = strings.Split(value, ",")
+			}
+
+			page, err := search.NewService(store).Search(ctx.Request.Context(), &search.Query{
+				Text:   query.Get("q"),
+				Types:  types,
+				TagId:  tagId,
+				Limit:  limit,
+				Offset: offset,
+			})
+			if err != nil {
+				return searchErrorResult(ctx, err)
+			}
+
+			return ctx.Results.Json(page)
+		}).
+		HasSummary("Search").
+		HasDescription("Search profiles, stories, events and questions. Words are matched as prefixes.").
+		HasQueryParameter("q", "The text to search for").
+		HasQueryParameter("types", "Comma separated entity types to include: profile, story, event, question").
+		HasQueryParameter("tag", "Only find stories and events with this tag").
+		HasQueryParameter("limit", "Maximum number of results to return").
+		HasQueryParameter("offset", "Number of results to skip").
+		HasResponse(http.StatusOK)
+}
+
+func searchErrorResult(ctx *httpfx.Context, err error) httpfx.Result {
+	switch {
+	case errors.Is(err, search.ErrInvalidInput), errors.Is(err, search.ErrUnknownType):
+		return ctx.Results.Error(http.StatusBadRequest, []byte(err.Error()))
+	default:
+		return ctx.Results.Error(http.StatusInternalServerError, []byte(err.Error()))
+	}
+}
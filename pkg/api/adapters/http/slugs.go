@@ -0,0 +1,220 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/eser/acik.io/pkg/api/adapters/appcontext"
+	"github.com/eser/acik.io/pkg/api/adapters/storage"
+	"github.com/eser/acik.io/pkg/api/business/slugs"
+	"github.com/eser/acik.io/pkg/api/business/stories"
+	"github.com/eser/ajan/httpfx"
+)
+
+func RegisterHttpRoutesForSlugs( //nolint:funlen,cyclop
+	routes *httpfx.Router,
+	appContext *appcontext.AppContext,
+	renderer stories.ContentRenderer,
+) {
+	routes.
+		Route("PUT /profiles/{slug}/slug", func(ctx *httpfx.Context) httpfx.Result {
+			user, hasUser := GetSessionUser(ctx)
+			if !hasUser {
+				return ctx.Results.Unauthorized([]byte("Authentication required"))
+			}
+
+			var input slugs.RenameInput
+
+			err := json.NewDecoder(ctx.Request.Body).Decode(&input)
+			if err != nil {
+				return ctx.Results.BadRequest()
+			}
+
+			store, err := storage.NewFromDefault(appContext.Data)
+			if err != nil {
+				return ctx.Results.Error(http.StatusInternalServerError, []byte(err.Error()))
+			}
+
+			profileService := newProfilesService(appContext, store)
+
+			profile, err := profileService.GetBySlug(ctx.Request.Context(), ctx.Request.PathValue("slug"))
+			if err != nil {
+				return ctx.Results.Error(http.StatusInternalServerError, []byte(err.Error()))
+			}
+
+			if profile == nil || profile.DeletedAt.Valid {
+				return ctx.Results.NotFound()
+			}
+
+			isAdmin, err := profileService.IsAdmin(ctx.Request.Context(), profile.Id, user.Id)
+			if err != nil {
+				return membershipErrorResult(ctx, err)
+			}
+
+			if !isAdmin {
+				return ctx.Results.Error(http.StatusForbidden, []byte("User is not an admin of the profile"))
+			}
+
+			_, err = profileService.Rename(ctx.Request.Context(), profile.Id, input.Slug)
+			if err != nil {
+				return slugsErrorResult(ctx, err)
+			}
+
+			detail, err := profileService.GetDetailBySlug(ctx.Request.Context(), input.Slug, user.Id)
+			if err != nil {
+				return followsErrorResult(ctx, err)
+			}
+
+			return ctx.Results.Json(detail)
+		}).
+		HasSummary("Rename profile").
+		HasDescription("Changes the slug of a profile. The previous slug redirects to the new one. Profile admins only.").
+		HasPathParameter("slug", "The slug of the profile").
+		HasRequestModel(slugs.RenameInput{}). //nolint:exhaustruct
+		HasResponse(http.StatusOK)
+
+	routes.
+		Route("PUT /stories/{slug}/slug", func(ctx *httpfx.Context) httpfx.Result {
+			user, hasUser := GetSessionUser(ctx)
+			if !hasUser {
+				return ctx.Results.Unauthorized([]byte("Authentication required"))
+			}
+
+			var input slugs.RenameInput
+
+			err := json.NewDecoder(ctx.Request.Body).Decode(&input)
+			if err != nil {
+				return ctx.Results.BadRequest()
+			}
+
+			service, err := newStoriesService(appContext, renderer)
+			if err != nil {
+				return ctx.Results.Error(http.StatusInternalServerError, []byte(err.Error()))
+			}
+
+			record, err := service.Rename(ctx.Request.Context(), user.Id, ctx.Request.PathValue("slug"), &input)
+			if err != nil {
+				return slugsErrorResult(ctx, err)
+			}
+
+			return ctx.Results.Json(record)
+		}).
+		HasSummary("Rename story").
+		HasDescription("Changes the slug of a story. The previous slug redirects to the new one. Authors only.").
+		HasPathParameter("slug", "The slug of the story").
+		HasRequestModel(slugs.RenameInput{}). //nolint:exhaustruct
+		HasResponse(http.StatusOK)
+
+	routes.
+		Route("PUT /events/{slug}/slug", func(ctx *httpfx.Context) httpfx.Result {
+			user, hasUser := GetSessionUser(ctx)
+			if !hasUser {
+				return ctx.Results.Unauthorized([]byte("Authentication required"))
+			}
+
+			var input slugs.RenameInput
+
+			err := json.NewDecoder(ctx.Request.Body).Decode(&input)
+			if err != nil {
+				return ctx.Results.BadRequest()
+			}
+
+			store, err := storage.NewFromDefault(appContext.Data)
+			if err != nil {
+				return ctx.Results.Error(http.StatusInternalServerError, []byte(err.Error()))
+			}
+
+			service := newEventsService(appContext, store)
+
+			event, err := service.GetBySlug(ctx.Request.Context(), ctx.Request.PathValue("slug"))
+			if err != nil {
+				return eventsErrorResult(ctx, err)
+			}
+
+			err = service.EnsureOrganizer(ctx.Request.Context(), event.Id, user.Id)
+			if err != nil {
+				return eventsErrorResult(ctx, err)
+			}
+
+			event, err = service.Rename(ctx.Request.Context(), event.Id, input.Slug)
+			if err != nil {
+				return slugsErrorResult(ctx, err)
+			}
+
+			return ctx.Results.Json(event)
+		}).
+		HasSummary("Rename event").
+		HasDescription("Changes the slug of an event. The previous slug redirects to the new one. Organizers only.").
+		HasPathParameter("slug", "The slug of the event").
+		HasRequestModel(slugs.RenameInput{}). //nolint:exhaustruct
+		HasResponse(http.StatusOK)
+}
+
+// SlugRedirectMiddleware answers GET and HEAD requests the route couldn't find
+// with a permanent redirect to the address under the current slug, when the
+// slug path value is a previous slug of a record of the kind. The slug is
+// expected to be the path segment right after prefix.
+func SlugRedirectMiddleware(appContext *appcontext.AppContext, kind string, prefix string) httpfx.Handler {
+	return slugRedirect(func(ctx context.Context, kind string, slug string) (string, error) {
+		store, err := storage.NewFromDefault(appContext.Data)
+		if err != nil {
+			return "", err //nolint:wrapcheck
+		}
+
+		return slugs.NewService(store).Resolve(ctx, kind, slug)
+	}, kind, prefix)
+}
+
+func slugRedirect(
+	resolve func(ctx context.Context, kind string, slug string) (string, error),
+	kind string,
+	prefix string,
+) httpfx.Handler {
+	return func(ctx *httpfx.Context) httpfx.Result {
+		result := ctx.Next()
+
+		if result.StatusCode() != http.StatusNotFound ||
+			(ctx.Request.Method != http.MethodGet && ctx.Request.Method != http.MethodHead) {
+			return result
+		}
+
+		slug := ctx.Request.PathValue("slug")
+
+		current, err := resolve(ctx.Request.Context(), kind, slug)
+		if errors.Is(err, slugs.ErrRecordNotFound) {
+			return result
+		}
+
+		if err != nil {
+			return ctx.Results.Error(http.StatusInternalServerError, []byte(err.Error()))
+		}
+
+		location := prefix + url.PathEscape(current) + strings.TrimPrefix(ctx.Request.URL.Path, prefix+slug)
+		if ctx.Request.URL.RawQuery != "" {
+			location += "?" + ctx.Request.URL.RawQuery
+		}
+
+		// the router leaves the redirect address of results out of the response.
+		ctx.ResponseWriter.Header().Set("Location", location)
+
+		return ctx.Results.Redirect(location).WithStatusCode(http.StatusMovedPermanently)
+	}
+}
+
+func slugsErrorResult(ctx *httpfx.Context, err error) httpfx.Result {
+	switch {
+	case errors.Is(err, slugs.ErrRecordNotFound):
+		return ctx.Results.NotFound()
+	case errors.Is(err, slugs.ErrInvalidSlug), errors.Is(err, slugs.ErrReservedSlug):
+		return ctx.Results.Error(http.StatusBadRequest, []byte(err.Error()))
+	case errors.Is(err, slugs.ErrSlugTaken):
+		return ctx.Results.Error(http.StatusConflict, []byte(err.Error()))
+	default:
+		// errors of stories.Service.Rename pass through as well.
+		return storiesErrorResult(ctx, err)
+	}
+}
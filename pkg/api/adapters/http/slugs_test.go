@@ -0,0 +1,97 @@
+package http
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/eser/acik.io/pkg/api/business/slugs"
+	"github.com/eser/ajan/httpfx"
+)
+
+// newSlugRedirectFixture serves the profile "current" on every route, the
+// profile once named "old" having been renamed to it.
+func newSlugRedirectFixture(resolves *int) http.Handler {
+	resolve := func(_ context.Context, kind string, slug string) (string, error) {
+		*resolves++
+
+		if kind == slugs.KindProfile && slug == "old" {
+			return "current", nil
+		}
+
+		return "", fmt.Errorf("%w(kind: %s, slug: %s)", slugs.ErrRecordNotFound, kind, slug)
+	}
+
+	profile := func(ctx *httpfx.Context) httpfx.Result {
+		if ctx.Request.PathValue("slug") != "current" {
+			return ctx.Results.NotFound()
+		}
+
+		return ctx.Results.Json(map[string]string{"slug": "current"})
+	}
+
+	routes := httpfx.NewRouter("/")
+	routes.Route("GET /profiles/{slug}/projects/{projectSlug}", slugRedirect(resolve, slugs.KindProfile, "/profiles/"), profile)
+	routes.Route("POST /profiles/{slug}/projects", slugRedirect(resolve, slugs.KindProfile, "/profiles/"), profile)
+
+	return routes.GetMux()
+}
+
+func TestSlugRedirect(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		method       string
+		path         string
+		name         string
+		wantLocation string
+		want         int
+		wantResolve  bool
+	}{
+		{
+			name:         "previous slug",
+			method:       http.MethodGet,
+			path:         "/profiles/old/projects/acik?tag=go",
+			want:         http.StatusMovedPermanently,
+			wantLocation: "/profiles/current/projects/acik?tag=go",
+			wantResolve:  true,
+		},
+		{
+			name:         "previous slug with head",
+			method:       http.MethodHead,
+			path:         "/profiles/old/projects/acik",
+			want:         http.StatusMovedPermanently,
+			wantLocation: "/profiles/current/projects/acik",
+			wantResolve:  true,
+		},
+		{name: "current slug", method: http.MethodGet, path: "/profiles/current/projects/acik", want: http.StatusOK},
+		{name: "unknown slug", method: http.MethodGet, path: "/profiles/unknown/projects/acik", want: http.StatusNotFound, wantResolve: true},
+		{name: "other methods", method: http.MethodPost, path: "/profiles/old/projects", want: http.StatusNotFound},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			resolves := 0
+			handler := newSlugRedirectFixture(&resolves)
+
+			resp := httptest.NewRecorder()
+			handler.ServeHTTP(resp, httptest.NewRequest(test.method, test.path, nil))
+
+			if resp.Code != test.want {
+				t.Fatalf("got %d, want %d", resp.Code, test.want)
+			}
+
+			if got := resp.Header().Get("Location"); got != test.wantLocation {
+				t.Errorf("got Location %q, want %q", got, test.wantLocation)
+			}
+
+			if (resolves > 0) != test.wantResolve {
+				t.Errorf("got %d slug lookups, want them only for GET and HEAD requests not found", resolves)
+			}
+		})
+	}
+}
@@ -0,0 +1,401 @@
+package http
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/eser/acik.io/pkg/api/adapters/appcontext"
+	"github.com/eser/acik.io/pkg/api/adapters/queue"
+	"github.com/eser/acik.io/pkg/api/adapters/storage"
+	"github.com/eser/acik.io/pkg/api/business/slugs"
+	"github.com/eser/acik.io/pkg/api/business/stories"
+	"github.com/eser/acik.io/pkg/api/business/users"
+	"github.com/eser/ajan/httpfx"
+)
+
+func RegisterHttpRoutesForStories( //nolint:funlen,cyclop,maintidx
+	routes *httpfx.Router,
+	appContext *appcontext.AppContext,
+	renderer stories.ContentRenderer,
+) {
+	routes.
+		Route("GET /stories", func(ctx *httpfx.Context) httpfx.Result {
+			format, err := stories.ParseContentFormat(ctx.Request.URL.Query().Get("format"))
+			if err != nil {
+				return ctx.Results.Error(http.StatusBadRequest, []byte(err.Error()))
+			}
+
+			service, err := newStoriesService(appContext, renderer)
+			if err != nil {
+				return ctx.Results.Error(http.StatusInternalServerError, []byte(err.Error()))
+			}
+
+			tagId, hasTag, err := getTagFilter(ctx, appContext)
+			if err != nil {
+				return ctx.Results.Error(http.StatusInternalServerError, []byte(err.Error()))
+			}
+
+			if !hasTag {
+				return ctx.Results.Json([]*stories.StoryView{})
+			}
+
+			limit, offset := getPagination(ctx)
+
+			records, err := service.ListPublished(ctx.Request.Context(), tagId, limit, offset)
+			if err != nil {
+				return storiesErrorResult(ctx, err)
+			}
+
+			views, err := service.PresentAll(ctx.Request.Context(), records, format)
+			if err != nil {
+				return storiesErrorResult(ctx, err)
+			}
+
+			return ctx.Results.Json(views)
+		}).
+		HasSummary("List stories").
+		HasDescription("List published stories, newest first.").
+		HasQueryParameter("tag", "Only list stories with this tag").
+		HasQueryParameter("limit", "Maximum number of stories to return").
+		HasQueryParameter("offset", "Number of stories to skip").
+		HasQueryParameter("format", "Content format to return: content (default), html or both").
+		HasResponse(http.StatusOK)
+
+	routes.
+		Route("GET /stories/{slug}", SlugRedirectMiddleware(appContext, slugs.KindStory, "/stories/"), func(ctx *httpfx.Context) httpfx.Result {
+			format, err := stories.ParseContentFormat(ctx.Request.URL.Query().Get("format"))
+			if err != nil {
+				return ctx.Results.Error(http.StatusBadRequest, []byte(err.Error()))
+			}
+
+			service, err := newStoriesService(appContext, renderer)
+			if err != nil {
+				return ctx.Results.Error(http.StatusInternalServerError, []byte(err.Error()))
+			}
+
+			userId := ""
+			if user, hasUser := GetSessionUser(ctx); hasUser {
+				userId = user.Id
+			}
+
+			record, err := service.GetVisibleBySlug(ctx.Request.Context(), ctx.Request.PathValue("slug"), userId)
+			if err != nil {
+				return storiesErrorResult(ctx, err)
+			}
+
+			if checkNotModified(ctx, record.ETag(), record.Version()) {
+				return notModifiedResult(ctx)
+			}
+
+			view, err := service.Present(ctx.Request.Context(), record, format)
+			if err != nil {
+				return storiesErrorResult(ctx, err)
+			}
+
+			return ctx.Results.Json(view)
+		}).
+		HasSummary("Get story").
+		HasDescription("Get a published story by its slug. Authors can also see their unpublished stories. Previous slugs redirect to the current one.").
+		HasPathParameter("slug", "The slug of the story").
+		HasQueryParameter("format", "Content format to return: content (default), html or both").
+		HasResponse(http.StatusOK)
+
+	routes.
+		Route("GET /stories/featured", func(ctx *httpfx.Context) httpfx.Result {
+			format, err := stories.ParseContentFormat(ctx.Request.URL.Query().Get("format"))
+			if err != nil {
+				return ctx.Results.Error(http.StatusBadRequest, []byte(err.Error()))
+			}
+
+			service, err := newStoriesService(appContext, renderer)
+			if err != nil {
+				return ctx.Results.Error(http.StatusInternalServerError, []byte(err.Error()))
+			}
+
+			tagId, hasTag, err := getTagFilter(ctx, appContext)
+			if err != nil {
+				return ctx.Results.Error(http.StatusInternalServerError, []byte(err.Error()))
+			}
+
+			if !hasTag {
+				return ctx.Results.Json([]*stories.StoryView{})
+			}
+
+			limit, _ := getPagination(ctx)
+
+			records, err := service.ListFeatured(ctx.Request.Context(), tagId, limit)
+			if err != nil {
+				return storiesErrorResult(ctx, err)
+			}
+
+			views, err := service.PresentAll(ctx.Request.Context(), records, format)
+			if err != nil {
+				return storiesErrorResult(ctx, err)
+			}
+
+			return ctx.Results.Json(views)
+		}).
+		HasSummary("List featured stories").
+		HasDescription("List the stories featured by moderators, in their curated order.").
+		HasQueryParameter("tag", "Only list stories with this tag").
+		HasQueryParameter("limit", "Maximum number of stories to return").
+		HasQueryParameter("format", "Content format to return: content (default), html or both").
+		HasResponse(http.StatusOK)
+
+	routes.
+		Route("GET /profiles/{slug}/stories", SlugRedirectMiddleware(appContext, slugs.KindProfile, "/profiles/"), func(ctx *httpfx.Context) httpfx.Result {
+			format, err := stories.ParseContentFormat(ctx.Request.URL.Query().Get("format"))
+			if err != nil {
+				return ctx.Results.Error(http.StatusBadRequest, []byte(err.Error()))
+			}
+
+			store, err := storage.NewFromDefault(appContext.Data)
+			if err != nil {
+				return ctx.Results.Error(http.StatusInternalServerError, []byte(err.Error()))
+			}
+
+			profile, err := newProfilesService(appContext, store).GetBySlug(ctx.Request.Context(), ctx.Request.PathValue("slug"))
+			if err != nil {
+				return ctx.Results.Error(http.StatusInternalServerError, []byte(err.Error()))
+			}
+
+			if profile == nil {
+				return ctx.Results.NotFound()
+			}
+
+			if !profile.ShowStories {
+				return ctx.Results.NotFound()
+			}
+
+			service, err := newStoriesService(appContext, renderer)
+			if err != nil {
+				return ctx.Results.Error(http.StatusInternalServerError, []byte(err.Error()))
+			}
+
+			tagId, hasTag, err := getTagFilter(ctx, appContext)
+			if err != nil {
+				return ctx.Results.Error(http.StatusInternalServerError, []byte(err.Error()))
+			}
+
+			if !hasTag {
+				return ctx.Results.Json([]*stories.StoryView{})
+			}
+
+			limit, offset := getPagination(ctx)
+
+			records, err := service.ListPublishedByAuthor(ctx.Request.Context(), profile.Id, tagId, limit, offset)
+			if err != nil {
+				return storiesErrorResult(ctx, err)
+			}
+
+			views, err := service.PresentAll(ctx.Request.Context(), records, format)
+			if err != nil {
+				return storiesErrorResult(ctx, err)
+			}
+
+			return ctx.Results.Json(views)
+		}).
+		HasSummary("List profile stories").
+		HasDescription("List published stories of a profile, if the profile shows its stories.").
+		HasPathParameter("slug", "The slug of the profile").
+		HasQueryParameter("tag", "Only list stories with this tag").
+		HasQueryParameter("limit", "Maximum number of stories to return").
+		HasQueryParameter("offset", "Number of stories to skip").
+		HasQueryParameter("format", "Content format to return: content (default), html or both").
+		HasResponse(http.StatusOK)
+
+	routes.
+		Route("POST /stories", IdempotencyMiddleware(appContext), func(ctx *httpfx.Context) httpfx.Result {
+			user, hasUser := GetSessionUser(ctx)
+			if !hasUser {
+				return ctx.Results.Unauthorized([]byte("Authentication required"))
+			}
+
+			var input stories.CreateStoryInput
+
+			err := json.NewDecoder(ctx.Request.Body).Decode(&input)
+			if err != nil {
+				return ctx.Results.BadRequest()
+			}
+
+			service, err := newStoriesService(appContext, renderer)
+			if err != nil {
+				return ctx.Results.Error(http.StatusInternalServerError, []byte(err.Error()))
+			}
+
+			record, err := service.Create(ctx.Request.Context(), user.Id, &input)
+			if err != nil {
+				return storiesErrorResult(ctx, err)
+			}
+
+			return ctx.Results.Json(record).WithStatusCode(http.StatusCreated)
+		}).
+		HasSummary("Create story").
+		HasDescription("Create a draft story on behalf of a profile the user is a member of. Retries sent with the same Idempotency-Key header get the response of the first request.").
+		HasRequestModel(stories.CreateStoryInput{}). //nolint:exhaustruct
+		HasResponse(http.StatusCreated)
+
+	routes.
+		Route("PATCH /stories/{slug}", func(ctx *httpfx.Context) httpfx.Result {
+			user, hasUser := GetSessionUser(ctx)
+			if !hasUser {
+				return ctx.Results.Unauthorized([]byte("Authentication required"))
+			}
+
+			var input stories.UpdateStoryInput
+
+			err := json.NewDecoder(ctx.Request.Body).Decode(&input)
+			if err != nil {
+				return ctx.Results.BadRequest()
+			}
+
+			service, err := newStoriesService(appContext, renderer)
+			if err != nil {
+				return ctx.Results.Error(http.StatusInternalServerError, []byte(err.Error()))
+			}
+
+			input.IfMatch = ctx.Request.Header.Get("If-Match")
+
+			record, err := service.Update(ctx.Request.Context(), user.Id, ctx.Request.PathValue("slug"), &input)
+			if err != nil {
+				return storiesErrorResult(ctx, err)
+			}
+
+			ctx.ResponseWriter.Header().Set("ETag", record.ETag())
+
+			return ctx.Results.Json(record)
+		}).
+		HasSummary("Update story").
+		HasDescription("Update the content of a story. With an If-Match header, the story is only updated while it still has one of the given ETags, and 412 is returned otherwise.").
+		HasPathParameter("slug", "The slug of the story").
+		HasRequestModel(stories.UpdateStoryInput{}). //nolint:exhaustruct
+		HasResponse(http.StatusOK)
+
+	routes.
+		Route("POST /stories/{slug}/status", func(ctx *httpfx.Context) httpfx.Result {
+			user, hasUser := GetSessionUser(ctx)
+			if !hasUser {
+				return ctx.Results.Unauthorized([]byte("Authentication required"))
+			}
+
+			var input stories.TransitionInput
+
+			err := json.NewDecoder(ctx.Request.Body).Decode(&input)
+			if err != nil {
+				return ctx.Results.BadRequest()
+			}
+
+			service, err := newStoriesService(appContext, renderer)
+			if err != nil {
+				return ctx.Results.Error(http.StatusInternalServerError, []byte(err.Error()))
+			}
+
+			record, err := service.Transition(ctx.Request.Context(), user.Id, ctx.Request.PathValue("slug"), &input)
+			if err != nil {
+				return storiesErrorResult(ctx, err)
+			}
+
+			return ctx.Results.Json(record)
+		}).
+		HasSummary("Change story status").
+		HasDescription("Move a story through draft, in review, published and archived. A future publishAt schedules it.").
+		HasPathParameter("slug", "The slug of the story").
+		HasRequestModel(stories.TransitionInput{}). //nolint:exhaustruct
+		HasResponse(http.StatusOK)
+
+	routes.
+		Route("PUT /stories/{slug}/featured", func(ctx *httpfx.Context) httpfx.Result {
+			if _, failure := requireModerator(ctx); failure != nil {
+				return *failure
+			}
+
+			var input stories.FeatureInput
+
+			err := json.NewDecoder(ctx.Request.Body).Decode(&input)
+			if err != nil {
+				return ctx.Results.BadRequest()
+			}
+
+			input.IsFeatured = true
+
+			return setStoryFeatured(ctx, appContext, renderer, &input)
+		}).
+		HasSummary("Feature story").
+		HasDescription("Feature a published story on the home page at the given order. Moderators only.").
+		HasPathParameter("slug", "The slug of the story").
+		HasRequestModel(stories.FeatureInput{}). //nolint:exhaustruct
+		HasResponse(http.StatusOK)
+
+	routes.
+		Route("DELETE /stories/{slug}/featured", func(ctx *httpfx.Context) httpfx.Result {
+			if _, failure := requireModerator(ctx); failure != nil {
+				return *failure
+			}
+
+			return setStoryFeatured(ctx, appContext, renderer, &stories.FeatureInput{IsFeatured: false, Order: 0})
+		}).
+		HasSummary("Unfeature story").
+		HasDescription("Remove a story from the featured stories. Moderators only.").
+		HasPathParameter("slug", "The slug of the story").
+		HasResponse(http.StatusOK)
+}
+
+func newStoriesService(
+	appContext *appcontext.AppContext,
+	renderer stories.ContentRenderer,
+) (*stories.Service, error) {
+	store, err := storage.NewFromDefault(appContext.Data)
+	if err != nil {
+		return nil, err //nolint:wrapcheck
+	}
+
+	return stories.NewService(
+		&appContext.Config.Stories,
+		store,
+		newProfilesService(appContext, store),
+		queue.NewFromDefault(appContext.Queue),
+		renderer,
+		newEventRecorder(appContext, store),
+		newTagsService(appContext, store),
+		slugs.NewService(store),
+	), nil
+}
+
+func setStoryFeatured(
+	ctx *httpfx.Context,
+	appContext *appcontext.AppContext,
+	renderer stories.ContentRenderer,
+	input *stories.FeatureInput,
+) httpfx.Result {
+	service, err := newStoriesService(appContext, renderer)
+	if err != nil {
+		return ctx.Results.Error(http.StatusInternalServerError, []byte(err.Error()))
+	}
+
+	record, err := service.SetFeatured(ctx.Request.Context(), ctx.Request.PathValue("slug"), input)
+	if err != nil {
+		return storiesErrorResult(ctx, err)
+	}
+
+	return ctx.Results.Json(record)
+}
+
+func storiesErrorResult(ctx *httpfx.Context, err error) httpfx.Result {
+	switch {
+	case errors.Is(err, stories.ErrRecordNotFound):
+		return ctx.Results.NotFound()
+	case errors.Is(err, stories.ErrInvalidInput):
+		return ctx.Results.Error(http.StatusBadRequest, []byte(err.Error()))
+	case errors.Is(err, stories.ErrNotAuthor):
+		return ctx.Results.Error(http.StatusForbidden, []byte(err.Error()))
+	case errors.Is(err, stories.ErrInvalidTransition), errors.Is(err, stories.ErrStatusChanged):
+		return ctx.Results.Error(http.StatusConflict, []byte(err.Error()))
+	case errors.Is(err, stories.ErrVersionMismatch):
+		return ctx.Results.Error(http.StatusPreconditionFailed, []byte(err.Error()))
+	case errors.Is(err, users.ErrUserSuspended):
+		return ctx.Results.Error(http.StatusForbidden, []byte(err.Error()))
+	default:
+		return ctx.Results.Error(http.StatusInternalServerError, []byte(err.Error()))
+	}
+}
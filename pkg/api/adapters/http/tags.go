@@ -0,0 +1,248 @@
+package http
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/url"
+
+	"github.com/eser/acik.io/pkg/api/adapters/appcontext"
+	"github.com/eser/acik.io/pkg/api/adapters/storage"
+	"github.com/eser/acik.io/pkg/api/business/events"
+	"github.com/eser/acik.io/pkg/api/business/projects"
+	"github.com/eser/acik.io/pkg/api/business/stories"
+	"github.com/eser/acik.io/pkg/api/business/tags"
+	"github.com/eser/ajan/httpfx"
+)
+
+type tagPage struct {
+	Tag      *tags.TagDetail         `json:"tag"`
+	Stories  []*stories.StoryView    `json:"stories"`
+	Events   []*events.Event         `json:"events"`
+	Projects []*projects.ProjectView `json:"projects"`
+}
+
+func RegisterHttpRoutesForTags( //nolint:funlen,cyclop
+	routes *httpfx.Router,
+	appContext *appcontext.AppContext,
+	renderer stories.ContentRenderer,
+) {
+	routes.
+		Route("GET /tags/{slug}", func(ctx *httpfx.Context) httpfx.Result {
+			format, err := stories.ParseContentFormat(ctx.Request.URL.Query().Get("format"))
+			if err != nil {
+				return ctx.Results.Error(http.StatusBadRequest, []byte(err.Error()))
+			}
+
+			store, err := storage.NewFromDefault(appContext.Data)
+			if err != nil {
+				return ctx.Results.Error(http.StatusInternalServerError, []byte(err.Error()))
+			}
+
+			slug := ctx.Request.PathValue("slug")
+
+			detail, err := newTagsService(appContext, store).Get(ctx.Request.Context(), slug)
+			if err != nil {
+				return tagsErrorResult(ctx, err)
+			}
+
+			// synonyms and unnormalized spellings live at the canonical address.
+			if detail.Slug != slug {
+				return ctx.Results.Redirect("/tags/" + url.PathEscape(detail.Slug)).
+					WithStatusCode(http.StatusMovedPermanently)
+			}
+
+			storyService, err := newStoriesService(appContext, renderer)
+			if err != nil {
+				return ctx.Results.Error(http.StatusInternalServerError, []byte(err.Error()))
+			}
+
+			projectService, err := newProjectsService(appContext)
+			if err != nil {
+				return ctx.Results.Error(http.StatusInternalServerError, []byte(err.Error()))
+			}
+
+			eventService := newEventsService(appContext, store)
+
+			limit, offset := getPagination(ctx)
+
+			records, err := storyService.ListPublished(ctx.Request.Context(), detail.Id, limit, offset)
+			if err != nil {
+				return storiesErrorResult(ctx, err)
+			}
+
+			storyViews, err := storyService.PresentAll(ctx.Request.Context(), records, format)
+			if err != nil {
+				return storiesErrorResult(ctx, err)
+			}
+
+			upcomingEvents, err := eventService.ListUpcoming(ctx.Request.Context(), detail.Id, limit)
+			if err != nil {
+				return eventsErrorResult(ctx, err)
+			}
+
+			projectViews, err := projectService.ListVisibleByTag(ctx.Request.Context(), detail.Id, limit, 0)
+			if err != nil {
+				return projectsErrorResult(ctx, err)
+			}
+
+			return ctx.Results.Json(&tagPage{
+				Tag:      detail,
+				Stories:  storyViews,
+				Events:   upcomingEvents,
+				Projects: projectViews,
+			})
+		}).
+		HasSummary("Get tag").
+		HasDescription("Get a tag with its synonyms and what is tagged with it. Synonyms redirect to the canonical tag.").
+		HasPathParameter("slug", "The slug of the tag").
+		HasQueryParameter("limit", "Maximum number of items of each kind to return").
+		HasQueryParameter("offset", "Number of stories to skip").
+		HasQueryParameter("format", "Content format of stories to return: content (default), html or both").
+		HasResponse(http.StatusOK)
+
+	routes.
+		Route("POST /tags/{slug}/merge", func(ctx *httpfx.Context) httpfx.Result {
+			_, failure := requireModerator(ctx)
+			if failure != nil {
+				return *failure
+			}
+
+			var input tags.MergeTagInput
+
+			err := json.NewDecoder(ctx.Request.Body).Decode(&input)
+			if err != nil || input.Into == "" {
+				return ctx.Results.BadRequest()
+			}
+
+			store, err := storage.NewFromDefault(appContext.Data)
+			if err != nil {
+				return ctx.Results.Error(http.StatusInternalServerError, []byte(err.Error()))
+			}
+
+			detail, err := newTagsService(appContext, store).Merge(ctx.Request.Context(), ctx.Request.PathValue("slug"), input.Into)
+			if err != nil {
+				return tagsErrorResult(ctx, err)
+			}
+
+			return ctx.Results.Json(detail)
+		}).
+		HasSummary("Merge tag").
+		HasDescription("Merge a tag into another one, which takes over everything tagged with it. Moderators only.").
+		HasPathParameter("slug", "The slug of the tag to merge").
+		HasRequestModel(tags.MergeTagInput{}). //nolint:exhaustruct
+		HasResponse(http.StatusOK)
+
+	routes.
+		Route("POST /tags/{slug}/synonyms", func(ctx *httpfx.Context) httpfx.Result {
+			_, failure := requireModerator(ctx)
+			if failure != nil {
+				return *failure
+			}
+
+			var input tags.AddSynonymInput
+
+			err := json.NewDecoder(ctx.Request.Body).Decode(&input)
+			if err != nil || input.Name == "" {
+				return ctx.Results.BadRequest()
+			}
+
+			store, err := storage.NewFromDefault(appContext.Data)
+			if err != nil {
+				return ctx.Results.Error(http.StatusInternalServerError, []byte(err.Error()))
+			}
+
+			detail, err := newTagsService(appContext, store).AddSynonym(ctx.Request.Context(), ctx.Request.PathValue("slug"), input.Name)
+			if err != nil {
+				return tagsErrorResult(ctx, err)
+			}
+
+			return ctx.Results.Json(detail)
+		}).
+		HasSummary("Add tag synonym").
+		HasDescription("Make a name a synonym of a tag. An existing tag with that name is merged. Moderators only.").
+		HasPathParameter("slug", "The slug of the tag").
+		HasRequestModel(tags.AddSynonymInput{}). //nolint:exhaustruct
+		HasResponse(http.StatusOK)
+
+	routes.
+		Route("PUT /events/{slug}/tags", func(ctx *httpfx.Context) httpfx.Result {
+			user, hasUser := GetSessionUser(ctx)
+			if !hasUser {
+				return ctx.Results.Unauthorized([]byte("Authentication required"))
+			}
+
+			var names []string
+
+			err := json.NewDecoder(ctx.Request.Body).Decode(&names)
+			if err != nil {
+				return ctx.Results.BadRequest()
+			}
+
+			store, err := storage.NewFromDefault(appContext.Data)
+			if err != nil {
+				return ctx.Results.Error(http.StatusInternalServerError, []byte(err.Error()))
+			}
+
+			eventService := newEventsService(appContext, store)
+
+			event, err := eventService.GetBySlug(ctx.Request.Context(), ctx.Request.PathValue("slug"))
+			if err != nil {
+				return eventsErrorResult(ctx, err)
+			}
+
+			err = eventService.EnsureOrganizer(ctx.Request.Context(), event.Id, user.Id)
+			if err != nil {
+				return eventsErrorResult(ctx, err)
+			}
+
+			refs, err := eventService.SetTags(ctx.Request.Context(), event.Id, names)
+			if err != nil {
+				return tagsErrorResult(ctx, err)
+			}
+
+			return ctx.Results.Json(refs)
+		}).
+		HasSummary("Set event tags").
+		HasDescription("Replace the tags of an event with a list of tag names. Organizers only.").
+		HasPathParameter("slug", "The slug of the event").
+		HasRequestModel([]string{}).
+		HasResponse(http.StatusOK)
+}
+
+// getTagFilter resolves the tag query parameter to the id of its canonical
+// tag. It reports false for tags that don't exist, so listings can come back
+// empty instead of unfiltered.
+func getTagFilter(ctx *httpfx.Context, appContext *appcontext.AppContext) (string, bool, error) {
+	slug := ctx.Request.URL.Query().Get("tag")
+	if slug == "" {
+		return "", true, nil
+	}
+
+	store, err := storage.NewFromDefault(appContext.Data)
+	if err != nil {
+		return "", false, err //nolint:wrapcheck
+	}
+
+	tag, err := newTagsService(appContext, store).Resolve(ctx.Request.Context(), slug)
+	if errors.Is(err, tags.ErrRecordNotFound) {
+		return "", false, nil
+	}
+
+	if err != nil {
+		return "", false, err //nolint:wrapcheck
+	}
+
+	return tag.Id, true, nil
+}
+
+func tagsErrorResult(ctx *httpfx.Context, err error) httpfx.Result {
+	switch {
+	case errors.Is(err, tags.ErrRecordNotFound):
+		return ctx.Results.NotFound()
+	case errors.Is(err, tags.ErrInvalidInput):
+		return ctx.Results.Error(http.StatusBadRequest, []byte(err.Error()))
+	default:
+		return ctx.Results.Error(http.StatusInternalServerError, []byte(err.Error()))
+	}
+}
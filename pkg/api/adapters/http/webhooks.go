@@ -0,0 +1,269 @@
+package http
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/eser/acik.io/pkg/api/adapters/appcontext"
+	"github.com/eser/acik.io/pkg/api/adapters/storage"
+	adapterwebhooks "github.com/eser/acik.io/pkg/api/adapters/webhooks"
+	"github.com/eser/acik.io/pkg/api/business/slugs"
+	"github.com/eser/acik.io/pkg/api/business/webhooks"
+	"github.com/eser/ajan/httpfx"
+)
+
+func RegisterHttpRoutesForWebhooks(routes *httpfx.Router, appContext *appcontext.AppContext) { //nolint:funlen
+	routes.
+		Route("GET /profiles/{slug}/webhooks", SlugRedirectMiddleware(appContext, slugs.KindProfile, "/profiles/"), func(ctx *httpfx.Context) httpfx.Result {
+			service, profileId, result, ok := authorizeWebhooks(ctx, appContext)
+			if !ok {
+				return result
+			}
+
+			records, err := service.List(ctx.Request.Context(), profileId)
+			if err != nil {
+				return webhooksErrorResult(ctx, err)
+			}
+
+			return ctx.Results.Json(records)
+		}).
+		HasSummary("List webhook endpoints").
+		HasDescription("Lists the webhook endpoints of a profile. Profile admins only.").
+		HasPathParameter("slug", "The slug of the profile").
+		HasResponse(http.StatusOK)
+
+	routes.
+		Route("POST /profiles/{slug}/webhooks", func(ctx *httpfx.Context) httpfx.Result {
+			var body webhooks.EndpointInput
+
+			err := json.NewDecoder(ctx.Request.Body).Decode(&body)
+			if err != nil {
+				return ctx.Results.BadRequest()
+			}
+
+			service, profileId, result, ok := authorizeWebhooks(ctx, appContext)
+			if !ok {
+				return result
+			}
+
+			record, err := service.Create(ctx.Request.Context(), profileId, &body)
+			if err != nil {
+				return webhooksErrorResult(ctx, err)
+			}
+
+			return ctx.Results.Json(record)
+		}).
+		HasSummary("Create webhook endpoint").
+		HasDescription("Registers a webhook endpoint. The signing secret is only returned in this response.").
+		HasPathParameter("slug", "The slug of the profile").
+		HasRequestModel(webhooks.EndpointInput{}). //nolint:exhaustruct
+		HasResponse(http.StatusOK)
+
+	routes.
+		Route("PUT /profiles/{slug}/webhooks/{id}", func(ctx *httpfx.Context) httpfx.Result {
+			var body webhooks.EndpointInput
+
+			err := json.NewDecoder(ctx.Request.Body).Decode(&body)
+			if err != nil {
+				return ctx.Results.BadRequest()
+			}
+
+			service, profileId, result, ok := authorizeWebhooks(ctx, appContext)
+			if !ok {
+				return result
+			}
+
+			record, err := service.Update(ctx.Request.Context(), profileId, ctx.Request.PathValue("id"), &body)
+			if err != nil {
+				return webhooksErrorResult(ctx, err)
+			}
+
+			return ctx.Results.Json(record)
+		}).
+		HasSummary("Update webhook endpoint").
+		HasDescription("Updates a webhook endpoint. Re-activating a disabled endpoint clears its failure streak.").
+		HasPathParameter("slug", "The slug of the profile").
+		HasPathParameter("id", "The id of the endpoint").
+		HasRequestModel(webhooks.EndpointInput{}). //nolint:exhaustruct
+		HasResponse(http.StatusOK)
+
+	routes.
+		Route("DELETE /profiles/{slug}/webhooks/{id}", func(ctx *httpfx.Context) httpfx.Result {
+			service, profileId, result, ok := authorizeWebhooks(ctx, appContext)
+			if !ok {
+				return result
+			}
+
+			err := service.Delete(ctx.Request.Context(), profileId, ctx.Request.PathValue("id"))
+			if err != nil {
+				return webhooksErrorResult(ctx, err)
+			}
+
+			return ctx.Results.Ok()
+		}).
+		HasSummary("Delete webhook endpoint").
+		HasDescription("Deletes a webhook endpoint. Pending deliveries to it are dropped.").
+		HasPathParameter("slug", "The slug of the profile").
+		HasPathParameter("id", "The id of the endpoint").
+		HasResponse(http.StatusOK)
+
+	routes.
+		Route("POST /profiles/{slug}/webhooks/{id}/rotate-secret", func(ctx *httpfx.Context) httpfx.Result {
+			service, profileId, result, ok := authorizeWebhooks(ctx, appContext)
+			if !ok {
+				return result
+			}
+
+			record, err := service.RotateSecret(ctx.Request.Context(), profileId, ctx.Request.PathValue("id"))
+			if err != nil {
+				return webhooksErrorResult(ctx, err)
+			}
+
+			return ctx.Results.Json(record)
+		}).
+		HasSummary("Rotate webhook secret").
+		HasDescription("Replaces the signing secret of a webhook endpoint and returns the new one.").
+		HasPathParameter("slug", "The slug of the profile").
+		HasPathParameter("id", "The id of the endpoint").
+		HasResponse(http.StatusOK)
+
+	routes.
+		Route("POST /profiles/{slug}/webhooks/{id}/ping", func(ctx *httpfx.Context) httpfx.Result {
+			service, profileId, result, ok := authorizeWebhooks(ctx, appContext)
+			if !ok {
+				return result
+			}
+
+			record, err := service.Ping(ctx.Request.Context(), profileId, ctx.Request.PathValue("id"))
+			if err != nil {
+				return webhooksErrorResult(ctx, err)
+			}
+
+			return ctx.Results.Json(record)
+		}).
+		HasSummary("Ping webhook endpoint").
+		HasDescription("Sends a signed webhook.ping message to the endpoint right away and returns the delivery.").
+		HasPathParameter("slug", "The slug of the profile").
+		HasPathParameter("id", "The id of the endpoint").
+		HasResponse(http.StatusOK)
+
+	routes.
+		Route("GET /profiles/{slug}/webhooks/{id}/deliveries", SlugRedirectMiddleware(appContext, slugs.KindProfile, "/profiles/"), func(ctx *httpfx.Context) httpfx.Result {
+			service, profileId, result, ok := authorizeWebhooks(ctx, appContext)
+			if !ok {
+				return result
+			}
+
+			limit, offset := getPagination(ctx)
+
+			records, err := service.ListDeliveries(
+				ctx.Request.Context(),
+				profileId,
+				ctx.Request.PathValue("id"),
+				limit,
+				offset,
+			)
+			if err != nil {
+				return webhooksErrorResult(ctx, err)
+			}
+
+			return ctx.Results.Json(records)
+		}).
+		HasSummary("List webhook deliveries").
+		HasDescription("Lists the delivery log of a webhook endpoint, the latest first.").
+		HasPathParameter("slug", "The slug of the profile").
+		HasPathParameter("id", "The id of the endpoint").
+		HasQueryParameter("limit", "Maximum number of deliveries to return").
+		HasQueryParameter("offset", "Number of deliveries to skip").
+		HasResponse(http.StatusOK)
+
+	routes.
+		Route("POST /profiles/{slug}/webhooks/{id}/deliveries/{deliveryId}/replay", func(ctx *httpfx.Context) httpfx.Result {
+			service, profileId, result, ok := authorizeWebhooks(ctx, appContext)
+			if !ok {
+				return result
+			}
+
+			record, err := service.Replay(
+				ctx.Request.Context(),
+				profileId,
+				ctx.Request.PathValue("id"),
+				ctx.Request.PathValue("deliveryId"),
+			)
+			if err != nil {
+				return webhooksErrorResult(ctx, err)
+			}
+
+			return ctx.Results.Json(record)
+		}).
+		HasSummary("Replay webhook delivery").
+		HasDescription("Queues a past delivery to be sent again with the same body.").
+		HasPathParameter("slug", "The slug of the profile").
+		HasPathParameter("id", "The id of the endpoint").
+		HasPathParameter("deliveryId", "The id of the delivery").
+		HasResponse(http.StatusOK)
+}
+
+// authorizeWebhooks resolves the profile of the request and checks the
+// current user administers it.
+func authorizeWebhooks(
+	ctx *httpfx.Context,
+	appContext *appcontext.AppContext,
+) (*webhooks.Service, string, httpfx.Result, bool) {
+	user, hasUser := GetSessionUser(ctx)
+	if !hasUser {
+		return nil, "", ctx.Results.Unauthorized([]byte("Authentication required")), false
+	}
+
+	store, err := storage.NewFromDefault(appContext.Data)
+	if err != nil {
+		return nil, "", ctx.Results.Error(http.StatusInternalServerError, []byte(err.Error())), false
+	}
+
+	profileService := newProfilesService(appContext, store)
+
+	profile, err := profileService.GetBySlug(ctx.Request.Context(), ctx.Request.PathValue("slug"))
+	if err != nil {
+		return nil, "", ctx.Results.Error(http.StatusInternalServerError, []byte(err.Error())), false
+	}
+
+	if profile == nil {
+		return nil, "", ctx.Results.NotFound(), false
+	}
+
+	isAdmin, err := profileService.IsAdmin(ctx.Request.Context(), profile.Id, user.Id)
+	if err != nil {
+		return nil, "", membershipErrorResult(ctx, err), false
+	}
+
+	if !isAdmin {
+		return nil, "", ctx.Results.Error(http.StatusForbidden, []byte("User is not an admin of the profile")), false
+	}
+
+	config := &appContext.Config.Webhooks
+
+	service := webhooks.NewService(
+		config,
+		store,
+		adapterwebhooks.NewSender(config),
+		newEventsService(appContext, store),
+		newEventRecorder(appContext, store),
+	)
+
+	return service, profile.Id, httpfx.Result{}, true //nolint:exhaustruct
+}
+
+func webhooksErrorResult(ctx *httpfx.Context, err error) httpfx.Result {
+	switch {
+	case errors.Is(err, webhooks.ErrRecordNotFound):
+		return ctx.Results.NotFound()
+	case errors.Is(err, webhooks.ErrInvalidUrl),
+		errors.Is(err, webhooks.ErrNoEventTypes),
+		errors.Is(err, webhooks.ErrUnknownEventType),
+		errors.Is(err, webhooks.ErrDescriptionTooLong):
+		return ctx.Results.Error(http.StatusBadRequest, []byte(err.Error()))
+	default:
+		return ctx.Results.Error(http.StatusInternalServerError, []byte(err.Error()))
+	}
+}
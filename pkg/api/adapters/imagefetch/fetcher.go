@@ -0,0 +1,105 @@
+package imagefetch
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/eser/acik.io/pkg/api/adapters/netguard"
+	"github.com/eser/acik.io/pkg/api/business/media"
+)
+
+const (
+	dialTimeout  = 5 * time.Second
+	maxRedirects = 3
+)
+
+var (
+	ErrTooManyRedirects     = errors.New("too many redirects")
+	ErrRedirectNotAllowed   = errors.New("redirect leaves the allowed hosts")
+	ErrFailedToBuildRequest = errors.New("failed to build request")
+	ErrUnexpectedStatus     = errors.New("unexpected status")
+)
+
+// Fetcher downloads remote images for the image proxy. Like the webhook
+// sender, it refuses to connect to addresses that are not publicly routable,
+// checking the resolved address of every connection. Redirects are followed
+// only a few times, and only to https addresses on the allowed hosts.
+type Fetcher struct {
+	client *http.Client
+}
+
+func NewFetcher(config *media.Config) *Fetcher {
+	dialer := &net.Dialer{ //nolint:exhaustruct
+		Timeout: dialTimeout,
+		Control: netguard.Control,
+	}
+
+	transport := &http.Transport{ //nolint:exhaustruct
+		DialContext:         dialer.DialContext,
+		Proxy:               nil,
+		TLSHandshakeTimeout: dialTimeout,
+		MaxIdleConnsPerHost: 2, //nolint:mnd
+	}
+
+	return &Fetcher{
+		client: &http.Client{ //nolint:exhaustruct
+			Transport: transport,
+			Timeout:   config.ProxyRequestTimeout,
+			CheckRedirect: func(req *http.Request, via []*http.Request) error {
+				if len(via) > maxRedirects {
+					return ErrTooManyRedirects
+				}
+
+				if req.URL.Scheme != "https" || !media.IsAllowedHost(config, req.URL.Hostname()) {
+					return fmt.Errorf("%w(url: %s)", ErrRedirectNotAllowed, req.URL.Redacted())
+				}
+
+				return nil
+			},
+		},
+	}
+}
+
+func (f *Fetcher) Fetch(ctx context.Context, uri string, maxSize int64) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, uri, nil)
+	if err != nil {
+		return nil, fmt.Errorf("%w(url: %s): %w", ErrFailedToBuildRequest, uri, err)
+	}
+
+	req.Header.Set("Accept", "image/*")
+
+	resp, err := f.client.Do(req)
+	if err != nil {
+		return nil, err //nolint:wrapcheck
+	}
+
+	defer resp.Body.Close() //nolint:errcheck
+
+	switch {
+	case resp.StatusCode == http.StatusNotFound || resp.StatusCode == http.StatusGone:
+		return nil, fmt.Errorf("%w(url: %s)", media.ErrRemoteNotFound, uri)
+	case resp.StatusCode != http.StatusOK:
+		return nil, fmt.Errorf("%w(url: %s, status: %d)", ErrUnexpectedStatus, uri, resp.StatusCode)
+	}
+
+	if resp.ContentLength > maxSize {
+		return nil, fmt.Errorf("%w: at most %d bytes are allowed", media.ErrUploadTooLarge, maxSize)
+	}
+
+	// one byte more than allowed, so oversized images can be told apart.
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+
+	if int64(len(data)) > maxSize {
+		return nil, fmt.Errorf("%w: at most %d bytes are allowed", media.ErrUploadTooLarge, maxSize)
+	}
+
+	return data, nil
+}
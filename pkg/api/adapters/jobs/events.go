@@ -0,0 +1,65 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+
+	"github.com/eser/acik.io/pkg/api/adapters/worker"
+	"github.com/eser/acik.io/pkg/api/business/notifications"
+	"github.com/eser/acik.io/pkg/api/business/outbox"
+	"github.com/eser/acik.io/pkg/api/business/readcache"
+	"github.com/eser/acik.io/pkg/api/business/webhooks"
+)
+
+// subscriber handles a domain event in process. The outbox delivers events
+// at least once and a failing subscriber causes every subscriber of the event
+// to run again, so subscribers must be idempotent.
+type subscriber func(ctx context.Context, envelope *outbox.Envelope) error
+
+// subscribers returns the in-process subscribers of each domain event type.
+// Each event type is consumed from its own queue once, and fanned out to its
+// subscribers here, as consumers sharing a queue would compete for messages
+// rather than each receive them.
+func (s *services) subscribers() map[string][]subscriber {
+	subscribers := make(map[string][]subscriber)
+
+	for _, eventType := range webhooks.EventTypes {
+		subscribers[eventType] = append(subscribers[eventType], s.webhooks.HandleDomainEvent)
+	}
+
+	for _, eventType := range notifications.SourceEventTypes {
+		subscribers[eventType] = append(subscribers[eventType], s.notifications.HandleDomainEvent)
+	}
+
+	for _, eventType := range readcache.EventTypes {
+		subscribers[eventType] = append(subscribers[eventType], s.readCache.HandleDomainEvent)
+	}
+
+	subscribers[notifications.EventNotificationCreated] = append(
+		subscribers[notifications.EventNotificationCreated],
+		s.notifications.Deliver,
+	)
+
+	return subscribers
+}
+
+func registerDomainEventHandlers(w *worker.Worker, services *services) error {
+	for eventType, subscribers := range services.subscribers() {
+		handler := worker.Typed(func(ctx context.Context, envelope *outbox.Envelope) error {
+			errs := make([]error, 0, len(subscribers))
+
+			for _, subscriber := range subscribers {
+				errs = append(errs, subscriber(ctx, envelope))
+			}
+
+			return errors.Join(errs...)
+		})
+
+		err := w.Register(outbox.QueueName(eventType), worker.DefaultConcurrency, handler)
+		if err != nil {
+			return err //nolint:wrapcheck
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,376 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/eser/acik.io/pkg/api/adapters/appcontext"
+	adapterdigest "github.com/eser/acik.io/pkg/api/adapters/digest"
+	"github.com/eser/acik.io/pkg/api/adapters/github"
+	"github.com/eser/acik.io/pkg/api/adapters/mail"
+	"github.com/eser/acik.io/pkg/api/adapters/markdown"
+	adapternotifications "github.com/eser/acik.io/pkg/api/adapters/notifications"
+	"github.com/eser/acik.io/pkg/api/adapters/queue"
+	"github.com/eser/acik.io/pkg/api/adapters/ratelimitstore"
+	"github.com/eser/acik.io/pkg/api/adapters/readcachestore"
+	"github.com/eser/acik.io/pkg/api/adapters/storage"
+	adapterwebhooks "github.com/eser/acik.io/pkg/api/adapters/webhooks"
+	"github.com/eser/acik.io/pkg/api/adapters/worker"
+	"github.com/eser/acik.io/pkg/api/business/audit"
+	"github.com/eser/acik.io/pkg/api/business/digest"
+	"github.com/eser/acik.io/pkg/api/business/events"
+	"github.com/eser/acik.io/pkg/api/business/idempotency"
+	"github.com/eser/acik.io/pkg/api/business/moderation"
+	"github.com/eser/acik.io/pkg/api/business/notifications"
+	"github.com/eser/acik.io/pkg/api/business/outbox"
+	"github.com/eser/acik.io/pkg/api/business/profiles"
+	"github.com/eser/acik.io/pkg/api/business/projects"
+	"github.com/eser/acik.io/pkg/api/business/questions"
+	"github.com/eser/acik.io/pkg/api/business/ratelimit"
+	"github.com/eser/acik.io/pkg/api/business/readcache"
+	"github.com/eser/acik.io/pkg/api/business/search"
+	"github.com/eser/acik.io/pkg/api/business/slugs"
+	"github.com/eser/acik.io/pkg/api/business/stories"
+	"github.com/eser/acik.io/pkg/api/business/tags"
+	"github.com/eser/acik.io/pkg/api/business/users"
+	"github.com/eser/acik.io/pkg/api/business/webhooks"
+	"github.com/eser/ajan/queuefx"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const metricsReadHeaderTimeout = 5 * time.Second
+
+// services are the business services the jobs and the scheduled tasks of the
+// background process work with.
+type services struct {
+	stories       *stories.Service
+	users         *users.Service
+	search        *search.Service
+	events        *events.Service
+	questions     *questions.Service
+	webhooks      *webhooks.Service
+	notifications *notifications.Service
+	digest        *digest.Service
+	projects      *projects.Service
+	rateLimits    *ratelimit.Limiter
+	idempotency   *idempotency.Service
+	readCache     *readcache.Invalidator
+}
+
+func newServices(appContext *appcontext.AppContext) (*services, error) {
+	store, err := storage.NewFromDefault(appContext.Data)
+	if err != nil {
+		return nil, err //nolint:wrapcheck
+	}
+
+	publisher := queue.NewFromDefault(appContext.Queue)
+	outboxService := outbox.NewService(&appContext.Config.Outbox, store, publisher)
+	auditService := audit.NewService(store)
+	// jobs act on their own, their changes are logged without an actor.
+	recorder := audit.NewRecorder(outboxService, auditService)
+	slugService := slugs.NewService(store)
+	tagService := tags.NewService(store, recorder)
+	eventService := events.NewService(&appContext.Config.Events, store, recorder, tagService, slugService)
+	userService := users.NewService(store)
+	profileService := profiles.NewService(store, recorder, slugService)
+	mailer := mail.NewSmtpMailer(&appContext.Config.Mail)
+
+	questionFilters, err := questions.NewPipelineFromConfig(&appContext.Config.Questions, store)
+	if err != nil {
+		return nil, err //nolint:wrapcheck
+	}
+
+	questionService := questions.NewService(
+		store,
+		userService,
+		questionFilters,
+		moderation.NewService(store, userService, auditService, outboxService),
+		recorder,
+	)
+
+	renderer, err := adapterdigest.NewTemplateRenderer()
+	if err != nil {
+		return nil, err //nolint:wrapcheck
+	}
+
+	buckets, err := ratelimitstore.NewFromConfig(&appContext.Config.RateLimit, store)
+	if err != nil {
+		return nil, err //nolint:wrapcheck
+	}
+
+	rateLimiter, err := ratelimit.NewLimiter(&appContext.Config.RateLimit, buckets)
+	if err != nil {
+		return nil, err //nolint:wrapcheck
+	}
+
+	readCache, err := newReadCache(appContext)
+	if err != nil {
+		return nil, err
+	}
+
+	webhookService := webhooks.NewService(
+		&appContext.Config.Webhooks,
+		store,
+		adapterwebhooks.NewSender(&appContext.Config.Webhooks),
+		eventService,
+		recorder,
+	)
+
+	return &services{
+		stories: stories.NewService(
+			&appContext.Config.Stories,
+			store,
+			profileService,
+			publisher,
+			markdown.NewRenderer(),
+			recorder,
+			tagService,
+			slugService,
+		),
+		users:     userService,
+		search:    search.NewService(store),
+		events:    eventService,
+		questions: questionService,
+		webhooks:  webhookService,
+		notifications: notifications.NewService(
+			&appContext.Config.Notifications,
+			store,
+			userService,
+			eventService,
+			recorder,
+			map[string]notifications.Channel{
+				notifications.ChannelEmail: adapternotifications.NewEmailChannel(
+					mailer,
+					appContext.Config.Notifications.SiteUrl,
+				),
+				notifications.ChannelWebhook: adapternotifications.NewWebhookChannel(webhookService),
+			},
+		),
+		digest: digest.NewService(
+			&appContext.Config.Digest,
+			store,
+			questionService,
+			renderer,
+			adapterdigest.NewMailerAdapter(mailer),
+			publisher,
+		),
+		projects: projects.NewService(
+			&appContext.Config.Projects,
+			store,
+			profileService,
+			tagService,
+			github.NewClient(&appContext.Config.Projects),
+			publisher,
+			recorder,
+		),
+		rateLimits:  rateLimiter,
+		idempotency: idempotency.NewService(&appContext.Config.Idempotency, store),
+		readCache:   readcache.NewInvalidator(readCache, store),
+	}, nil
+}
+
+// newReadCache returns the cache of the http instances for invalidating its
+// entries, or none when it is turned off or kept in their memory, out of the
+// reach of the worker.
+func newReadCache(appContext *appcontext.AppContext) (*readcache.Cache, error) {
+	config := &appContext.Config.ReadCache
+	if !config.Enabled || config.Backend == readcachestore.BackendMemory {
+		return nil, nil //nolint:nilnil
+	}
+
+	store, err := readcachestore.NewFromConfig(config, appContext.Cache)
+	if err != nil {
+		return nil, err //nolint:wrapcheck
+	}
+
+	return readcache.NewCache(config, store, readcachestore.NewMetrics(appContext.Metrics)), nil
+}
+
+func registerJobHandlers(w *worker.Worker, appContext *appcontext.AppContext, services *services) error {
+	config := &appContext.Config.Work
+
+	registrations := []struct {
+		handler     worker.Handler
+		queue       string
+		concurrency int
+	}{
+		{
+			queue:       stories.QueuePublishScheduled,
+			concurrency: 4, //nolint:mnd
+			handler: worker.Typed(func(ctx context.Context, job *stories.PublishScheduledJob) error {
+				return services.stories.PublishScheduled(ctx, job) //nolint:wrapcheck
+			}),
+		},
+		{
+			queue:       users.QueueSweepSessions,
+			concurrency: worker.DefaultConcurrency,
+			handler: worker.Typed(func(ctx context.Context, _ *users.SweepSessionsJob) error {
+				deleted, err := services.users.SweepSessions(ctx, config.SessionPendingTtl)
+				if err != nil {
+					return err //nolint:wrapcheck
+				}
+
+				appContext.Logger.InfoContext(ctx, "Swept sessions", slog.Int64("deleted", deleted))
+
+				return nil
+			}),
+		},
+		{
+			queue:       search.QueueReindex,
+			concurrency: worker.DefaultConcurrency,
+			handler: worker.Typed(func(ctx context.Context, job *search.ReindexJob) error {
+				if job.EntityType == "" {
+					return services.search.Rebuild(ctx) //nolint:wrapcheck
+				}
+
+				err := services.search.Rebuild(ctx, job.EntityType)
+				if errors.Is(err, search.ErrUnknownType) {
+					return worker.Permanent(err)
+				}
+
+				return err //nolint:wrapcheck
+			}),
+		},
+		{
+			queue:       events.QueueMaterializeRecurring,
+			concurrency: worker.DefaultConcurrency,
+			handler: worker.Typed(func(ctx context.Context, job *events.MaterializeRecurringJob) error {
+				horizon := job.Horizon
+				if horizon <= 0 {
+					horizon = config.RecurringHorizon
+				}
+
+				created, err := services.events.MaterializeRecurring(ctx, horizon)
+				if err != nil {
+					return err //nolint:wrapcheck
+				}
+
+				appContext.Logger.InfoContext(ctx, "Materialized recurring events", slog.Int("created", created))
+
+				return nil
+			}),
+		},
+		{
+			queue:       digest.QueueSendDigest,
+			concurrency: worker.DefaultConcurrency,
+			handler: worker.Typed(func(ctx context.Context, job *digest.SendDigestJob) error {
+				status, err := services.digest.Send(ctx, job.UserId, job.Period)
+				if errors.Is(err, digest.ErrInvalidPeriod) || errors.Is(err, digest.ErrRecordNotFound) {
+					return worker.Permanent(err)
+				}
+
+				if err != nil {
+					return err //nolint:wrapcheck
+				}
+
+				appContext.Logger.DebugContext(
+					ctx,
+					"Processed digest",
+					slog.String("user", job.UserId),
+					slog.String("period", job.Period),
+					slog.String("status", status),
+				)
+
+				return nil
+			}),
+		},
+		{
+			queue:       projects.QueueRefreshMetadata,
+			concurrency: worker.DefaultConcurrency,
+			handler: worker.Typed(func(ctx context.Context, job *projects.RefreshMetadataJob) error {
+				return services.projects.RefreshMetadata(ctx, job.ProjectId) //nolint:wrapcheck
+			}),
+		},
+	}
+
+	for _, registration := range registrations {
+		err := w.Register(registration.queue, registration.concurrency, registration.handler)
+		if err != nil {
+			return err //nolint:wrapcheck
+		}
+	}
+
+	return nil
+}
+
+func Run(ctx context.Context, appContext *appcontext.AppContext) error {
+	config := &appContext.Config.Work
+
+	w := worker.New(
+		config,
+		appContext.Queue.GetNamed(queuefx.DefaultBroker),
+		queue.NewFromDefault(appContext.Queue),
+		appContext.Logger,
+		worker.NewMetrics(appContext.Metrics),
+	)
+
+	services, err := newServices(appContext)
+	if err != nil {
+		return err
+	}
+
+	err = registerJobHandlers(w, appContext, services)
+	if err != nil {
+		return err
+	}
+
+	err = registerDomainEventHandlers(w, services)
+	if err != nil {
+		return err
+	}
+
+	scheduler, err := newScheduler(appContext, services)
+	if err != nil {
+		return err
+	}
+
+	if config.MetricsAddr != "" {
+		server := &http.Server{ //nolint:exhaustruct
+			Addr: config.MetricsAddr,
+			Handler: promhttp.HandlerFor(
+				appContext.Metrics.GetRegistry(),
+				promhttp.HandlerOpts{}, //nolint:exhaustruct
+			),
+			ReadHeaderTimeout: metricsReadHeaderTimeout,
+		}
+
+		go func() {
+			err := server.ListenAndServe()
+			if err != nil && !errors.Is(err, http.ErrServerClosed) {
+				appContext.Logger.ErrorContext(ctx, "Metrics endpoint failed", slog.Any("error", err))
+			}
+		}()
+
+		defer server.Close() //nolint:errcheck
+	}
+
+	// the scheduler and the webhook dispatcher stop along with the worker,
+	// should the worker fail.
+	backgroundCtx, stopBackground := context.WithCancel(ctx)
+	schedulerDone := make(chan error, 1)
+	dispatcherDone := make(chan struct{})
+
+	go func() {
+		schedulerDone <- scheduler.Run(backgroundCtx, func(err error) {
+			appContext.Logger.ErrorContext(backgroundCtx, "Scheduled task failed", slog.Any("error", err))
+		})
+	}()
+
+	go func() {
+		defer close(dispatcherDone)
+
+		services.webhooks.Dispatch(backgroundCtx, func(err error) {
+			appContext.Logger.ErrorContext(backgroundCtx, "Webhook dispatch failed", slog.Any("error", err))
+		})
+	}()
+
+	err = w.Run(ctx)
+
+	stopBackground()
+	<-dispatcherDone
+
+	return errors.Join(err, <-schedulerDone)
+}
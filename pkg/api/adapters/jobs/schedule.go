@@ -0,0 +1,166 @@
+package jobs
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"time"
+
+	"github.com/eser/acik.io/pkg/api/adapters/appcontext"
+	"github.com/eser/acik.io/pkg/api/adapters/storage"
+	"github.com/eser/acik.io/pkg/api/business/schedule"
+)
+
+const (
+	TaskSweepSessions        = "users.sweep-sessions"
+	TaskPublishDueStories    = "stories.publish-due"
+	TaskRefreshVoteScores    = "questions.refresh-vote-scores"
+	TaskMaterializeRecurring = "events.materialize-recurring"
+	TaskSendWeeklyDigest     = "digest.send-weekly"
+	TaskRefreshProjects      = "projects.refresh-metadata"
+	TaskSweepRateLimits      = "ratelimit.sweep-buckets"
+	TaskSweepIdempotencyKeys = "idempotency.sweep-keys"
+	scheduleLeaderLockKey    = int64(0x5343484544554c45) // "SCHEDULE" in ascii, shared by every instance
+)
+
+// NewScheduler returns the scheduler with every periodic task registered.
+func NewScheduler(appContext *appcontext.AppContext) (*schedule.Service, error) {
+	services, err := newServices(appContext)
+	if err != nil {
+		return nil, err
+	}
+
+	return newScheduler(appContext, services)
+}
+
+func newScheduler(appContext *appcontext.AppContext, services *services) (*schedule.Service, error) {
+	store, err := storage.NewFromDefault(appContext.Data)
+	if err != nil {
+		return nil, err //nolint:wrapcheck
+	}
+
+	leader, err := storage.NewAdvisoryLeaderFromDefault(appContext.Data, scheduleLeaderLockKey)
+	if err != nil {
+		return nil, err //nolint:wrapcheck
+	}
+
+	scheduler := schedule.NewService(&appContext.Config.Schedule, store, leader, instanceName())
+	config := &appContext.Config.Work
+
+	tasks := []struct {
+		run  schedule.TaskFunc
+		name string
+	}{
+		{
+			name: TaskSweepSessions,
+			run: func(ctx context.Context) error {
+				deleted, err := services.users.SweepSessions(ctx, config.SessionPendingTtl)
+				if err != nil {
+					return err //nolint:wrapcheck
+				}
+
+				appContext.Logger.InfoContext(ctx, "Swept sessions", slog.Int64("deleted", deleted))
+
+				return nil
+			},
+		},
+		{
+			name: TaskPublishDueStories,
+			run: func(ctx context.Context) error {
+				enqueued, err := services.stories.PublishDue(ctx)
+				if enqueued > 0 {
+					appContext.Logger.InfoContext(ctx, "Enqueued due stories", slog.Int("enqueued", enqueued))
+				}
+
+				return err //nolint:wrapcheck
+			},
+		},
+		{
+			name: TaskRefreshVoteScores,
+			run: func(ctx context.Context) error {
+				_, err := services.questions.RefreshVoteScores(ctx)
+
+				return err //nolint:wrapcheck
+			},
+		},
+		{
+			name: TaskMaterializeRecurring,
+			run: func(ctx context.Context) error {
+				created, err := services.events.MaterializeRecurring(ctx, config.RecurringHorizon)
+				if err != nil {
+					return err //nolint:wrapcheck
+				}
+
+				appContext.Logger.InfoContext(ctx, "Materialized recurring events", slog.Int("created", created))
+
+				return nil
+			},
+		},
+		{
+			name: TaskSendWeeklyDigest,
+			run: func(ctx context.Context) error {
+				enqueued, err := services.digest.EnqueueAll(ctx, time.Now())
+				if err != nil {
+					return err //nolint:wrapcheck
+				}
+
+				appContext.Logger.InfoContext(ctx, "Enqueued weekly digests", slog.Int("enqueued", enqueued))
+
+				return nil
+			},
+		},
+		{
+			name: TaskRefreshProjects,
+			run: func(ctx context.Context) error {
+				refreshed, err := services.projects.RefreshStaleMetadata(ctx)
+				if refreshed > 0 {
+					appContext.Logger.InfoContext(ctx, "Refreshed project metadata", slog.Int("refreshed", refreshed))
+				}
+
+				return err //nolint:wrapcheck
+			},
+		},
+		{
+			name: TaskSweepRateLimits,
+			run: func(ctx context.Context) error {
+				deleted, err := services.rateLimits.Sweep(ctx)
+				if deleted > 0 {
+					appContext.Logger.InfoContext(ctx, "Swept rate limit buckets", slog.Int64("deleted", deleted))
+				}
+
+				return err //nolint:wrapcheck
+			},
+		},
+		{
+			name: TaskSweepIdempotencyKeys,
+			run: func(ctx context.Context) error {
+				deleted, err := services.idempotency.Sweep(ctx)
+				if deleted > 0 {
+					appContext.Logger.InfoContext(ctx, "Swept idempotency keys", slog.Int64("deleted", deleted))
+				}
+
+				return err //nolint:wrapcheck
+			},
+		},
+	}
+
+	for _, task := range tasks {
+		err := scheduler.Register(task.name, task.run)
+		if err != nil {
+			return nil, err //nolint:wrapcheck
+		}
+	}
+
+	return scheduler, nil
+}
+
+// instanceName identifies this process in the run history.
+func instanceName() string {
+	hostname, err := os.Hostname()
+	if err != nil {
+		hostname = "unknown"
+	}
+
+	return fmt.Sprintf("%s:%d", hostname, os.Getpid())
+}
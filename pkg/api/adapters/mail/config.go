@@ -0,0 +1,11 @@
+package mail
+
+import "time"
+
+type Config struct {
+	SmtpAddr string        `conf:"SMTP_ADDR" default:"localhost:25"`         // host:port of the SMTP relay
+	Username string        `conf:"USERNAME"`                                 // SMTP username, authentication is skipped when empty
+	Password string        `conf:"PASSWORD"`                                 // SMTP password
+	From     string        `conf:"FROM" default:"acik.io <noreply@acik.io>"` // sender of outgoing mails
+	Timeout  time.Duration `conf:"TIMEOUT" default:"10s"`                    // upper bound for a single SMTP session
+}
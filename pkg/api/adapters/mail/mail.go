@@ -0,0 +1,256 @@
+package mail
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net"
+	netmail "net/mail"
+	"net/smtp"
+	"net/textproto"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/oklog/ulid/v2"
+)
+
+var (
+	ErrInvalidAddress     = errors.New("invalid mail address")
+	ErrFailedToCompose    = errors.New("failed to compose mail")
+	ErrFailedToSend       = errors.New("failed to send mail")
+	ErrHeaderNotSupported = errors.New("header is set by the mailer")
+)
+
+// reservedHeaders are composed by the mailer itself.
+var reservedHeaders = []string{ //nolint:gochecknoglobals
+	"From", "To", "Subject", "Date", "Message-Id", "Mime-Version", "Content-Type", "Content-Transfer-Encoding",
+}
+
+// Message is a mail to a single recipient. Html is optional; when set, the
+// mail is sent as multipart/alternative with Text as the fallback.
+type Message struct {
+	Headers map[string]string
+	To      string
+	Subject string
+	Text    string
+	Html    string
+}
+
+// SmtpMailer sends mails through an SMTP relay, upgrading the connection with
+// STARTTLS whenever the relay offers it.
+type SmtpMailer struct {
+	config *Config
+}
+
+func NewSmtpMailer(config *Config) *SmtpMailer {
+	return &SmtpMailer{config: config}
+}
+
+func (m *SmtpMailer) Send(ctx context.Context, message *Message) error {
+	from, err := netmail.ParseAddress(m.config.From)
+	if err != nil {
+		return fmt.Errorf("%w(from: %s): %w", ErrInvalidAddress, m.config.From, err)
+	}
+
+	to, err := netmail.ParseAddress(message.To)
+	if err != nil {
+		return fmt.Errorf("%w(to: %s): %w", ErrInvalidAddress, message.To, err)
+	}
+
+	body, err := Compose(from, to, message)
+	if err != nil {
+		return err
+	}
+
+	err = m.deliver(ctx, from.Address, to.Address, body)
+	if err != nil {
+		return fmt.Errorf("%w(to: %s): %w", ErrFailedToSend, to.Address, err)
+	}
+
+	return nil
+}
+
+func (m *SmtpMailer) deliver(ctx context.Context, from string, to string, body []byte) error {
+	host, _, err := net.SplitHostPort(m.config.SmtpAddr)
+	if err != nil {
+		return err //nolint:wrapcheck
+	}
+
+	dialer := &net.Dialer{Timeout: m.config.Timeout} //nolint:exhaustruct
+
+	conn, err := dialer.DialContext(ctx, "tcp", m.config.SmtpAddr)
+	if err != nil {
+		return err //nolint:wrapcheck
+	}
+
+	err = conn.SetDeadline(time.Now().Add(m.config.Timeout))
+	if err != nil {
+		conn.Close() //nolint:errcheck,gosec
+
+		return err //nolint:wrapcheck
+	}
+
+	client, err := smtp.NewClient(conn, host)
+	if err != nil {
+		conn.Close() //nolint:errcheck,gosec
+
+		return err //nolint:wrapcheck
+	}
+
+	defer client.Close() //nolint:errcheck
+
+	if ok, _ := client.Extension("STARTTLS"); ok {
+		err = client.StartTLS(&tls.Config{ServerName: host, MinVersion: tls.VersionTLS12}) //nolint:exhaustruct
+		if err != nil {
+			return err //nolint:wrapcheck
+		}
+	}
+
+	if m.config.Username != "" {
+		err = client.Auth(smtp.PlainAuth("", m.config.Username, m.config.Password, host))
+		if err != nil {
+			return err //nolint:wrapcheck
+		}
+	}
+
+	err = client.Mail(from)
+	if err != nil {
+		return err //nolint:wrapcheck
+	}
+
+	err = client.Rcpt(to)
+	if err != nil {
+		return err //nolint:wrapcheck
+	}
+
+	writer, err := client.Data()
+	if err != nil {
+		return err //nolint:wrapcheck
+	}
+
+	_, err = writer.Write(body)
+	if err != nil {
+		return err //nolint:wrapcheck
+	}
+
+	err = writer.Close()
+	if err != nil {
+		return err //nolint:wrapcheck
+	}
+
+	return client.Quit() //nolint:wrapcheck
+}
+
+// Compose renders a message as an RFC 5322 mail with quoted-printable parts.
+func Compose(from *netmail.Address, to *netmail.Address, message *Message) ([]byte, error) {
+	var buf bytes.Buffer
+
+	header := textproto.MIMEHeader{}
+	header.Set("From", from.String())
+	header.Set("To", to.String())
+	header.Set("Subject", mime.QEncoding.Encode("utf-8", message.Subject))
+	header.Set("Date", time.Now().Format(time.RFC1123Z))
+	header.Set("Message-Id", fmt.Sprintf("<%s@%s>", ulid.Make().String(), domainOf(from.Address)))
+	header.Set("Mime-Version", "1.0")
+
+	for name, value := range message.Headers {
+		for _, reserved := range reservedHeaders {
+			if strings.EqualFold(name, reserved) {
+				return nil, fmt.Errorf("%w(header: %s)", ErrHeaderNotSupported, name)
+			}
+		}
+
+		header.Set(name, value)
+	}
+
+	if message.Html == "" {
+		header.Set("Content-Type", "text/plain; charset=utf-8")
+		header.Set("Content-Transfer-Encoding", "quoted-printable")
+		writeHeader(&buf, header)
+
+		err := writeQuotedPrintable(&buf, message.Text)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrFailedToCompose, err)
+		}
+
+		return buf.Bytes(), nil
+	}
+
+	var parts bytes.Buffer
+
+	writer := multipart.NewWriter(&parts)
+
+	header.Set("Content-Type", "multipart/alternative; boundary="+writer.Boundary())
+	writeHeader(&buf, header)
+
+	for _, part := range []struct{ contentType, content string }{
+		{"text/plain; charset=utf-8", message.Text},
+		{"text/html; charset=utf-8", message.Html},
+	} {
+		partWriter, err := writer.CreatePart(textproto.MIMEHeader{
+			"Content-Type":              {part.contentType},
+			"Content-Transfer-Encoding": {"quoted-printable"},
+		})
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrFailedToCompose, err)
+		}
+
+		err = writeQuotedPrintable(partWriter, part.content)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrFailedToCompose, err)
+		}
+	}
+
+	err := writer.Close()
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrFailedToCompose, err)
+	}
+
+	buf.Write(parts.Bytes())
+
+	return buf.Bytes(), nil
+}
+
+func writeHeader(buf *bytes.Buffer, header textproto.MIMEHeader) {
+	names := make([]string, 0, len(header))
+	for name := range header {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	for _, name := range names {
+		for _, value := range header[name] {
+			buf.WriteString(name + ": " + value + "\r\n")
+		}
+	}
+
+	buf.WriteString("\r\n")
+}
+
+func writeQuotedPrintable(w io.Writer, content string) error {
+	writer := quotedprintable.NewWriter(w)
+
+	_, err := writer.Write([]byte(content))
+	if err != nil {
+		return err //nolint:wrapcheck
+	}
+
+	return writer.Close() //nolint:wrapcheck
+}
+
+func domainOf(address string) string {
+	_, domain, found := strings.Cut(address, "@")
+	if !found {
+		return "localhost"
+	}
+
+	return domain
+}
@@ -0,0 +1,96 @@
+package markdown
+
+import (
+	"container/list"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"sync"
+
+	"github.com/eser/acik.io/pkg/api/business/stories"
+)
+
+const DefaultCacheSize = 512
+
+type cacheEntry struct {
+	key   string
+	value *stories.RenderedContent
+}
+
+// CachedRenderer keeps the most recently rendered documents in memory, keyed
+// by the hash of their source, so unchanged stories are not rendered again.
+type CachedRenderer struct {
+	inner    stories.ContentRenderer
+	entries  map[string]*list.Element
+	order    *list.List
+	capacity int
+
+	mu sync.Mutex
+}
+
+var _ stories.ContentRenderer = (*CachedRenderer)(nil)
+
+func NewCachedRenderer(inner stories.ContentRenderer, capacity int) *CachedRenderer {
+	if capacity <= 0 {
+		capacity = DefaultCacheSize
+	}
+
+	return &CachedRenderer{
+		inner:    inner,
+		entries:  make(map[string]*list.Element, capacity),
+		order:    list.New(),
+		capacity: capacity,
+		mu:       sync.Mutex{},
+	}
+}
+
+func (c *CachedRenderer) Render(ctx context.Context, source string) (*stories.RenderedContent, error) {
+	sum := sha256.Sum256([]byte(source))
+	key := hex.EncodeToString(sum[:])
+
+	if cached, hit := c.get(key); hit {
+		return cached, nil
+	}
+
+	rendered, err := c.inner.Render(ctx, source)
+	if err != nil {
+		return nil, err //nolint:wrapcheck
+	}
+
+	c.put(key, rendered)
+
+	return rendered, nil
+}
+
+func (c *CachedRenderer) get(key string) (*stories.RenderedContent, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	element, hit := c.entries[key]
+	if !hit {
+		return nil, false
+	}
+
+	c.order.MoveToFront(element)
+
+	return element.Value.(*cacheEntry).value, true //nolint:forcetypeassert
+}
+
+func (c *CachedRenderer) put(key string, value *stories.RenderedContent) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if element, exists := c.entries[key]; exists {
+		c.order.MoveToFront(element)
+
+		return
+	}
+
+	c.entries[key] = c.order.PushFront(&cacheEntry{key: key, value: value})
+
+	if c.order.Len() > c.capacity {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*cacheEntry).key) //nolint:forcetypeassert
+	}
+}
@@ -0,0 +1,78 @@
+package markdown
+
+import (
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/yuin/goldmark/ast"
+)
+
+var turkishTransliterations = strings.NewReplacer( //nolint:gochecknoglobals
+	"ç", "c", "Ç", "c",
+	"ğ", "g", "Ğ", "g",
+	"ı", "i", "İ", "i",
+	"ö", "o", "Ö", "o",
+	"ş", "s", "Ş", "s",
+	"ü", "u", "Ü", "u",
+)
+
+// headingIDs generates ASCII heading ids, transliterating Turkish letters
+// instead of dropping them, and keeps them unique within a document.
+type headingIDs struct {
+	used map[string]struct{}
+}
+
+func newHeadingIDs() *headingIDs {
+	return &headingIDs{used: make(map[string]struct{})}
+}
+
+func (h *headingIDs) Generate(value []byte, _ ast.NodeKind) []byte {
+	base := slugify(string(value))
+	if base == "" {
+		base = "heading"
+	}
+
+	id := base
+	for i := 1; ; i++ {
+		if _, exists := h.used[id]; !exists {
+			break
+		}
+
+		id = base + "-" + strconv.Itoa(i)
+	}
+
+	h.used[id] = struct{}{}
+
+	return []byte(id)
+}
+
+func (h *headingIDs) Put(value []byte) {
+	h.used[string(value)] = struct{}{}
+}
+
+func slugify(value string) string {
+	value = strings.ToLower(turkishTransliterations.Replace(value))
+
+	var builder strings.Builder
+
+	pendingDash := false
+
+	for _, r := range value {
+		if r < unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r)) {
+			if pendingDash && builder.Len() > 0 {
+				builder.WriteByte('-')
+			}
+
+			builder.WriteRune(r)
+
+			pendingDash = false
+
+			continue
+		}
+
+		pendingDash = true
+	}
+
+	return builder.String()
+}
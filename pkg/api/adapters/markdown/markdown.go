@@ -0,0 +1,162 @@
+package markdown
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/eser/acik.io/pkg/api/business/stories"
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/extension"
+	"github.com/yuin/goldmark/parser"
+	"github.com/yuin/goldmark/text"
+)
+
+type Renderer struct {
+	engine goldmark.Markdown
+}
+
+var _ stories.ContentRenderer = (*Renderer)(nil)
+
+func NewRenderer() *Renderer {
+	engine := goldmark.New(
+		goldmark.WithExtensions(
+			extension.Linkify,
+			extension.NewTable(extension.WithTableCellAlignMethod(extension.TableCellAlignAttribute)),
+			extension.Strikethrough,
+			extension.TaskList,
+		),
+		goldmark.WithParserOptions(
+			parser.WithAutoHeadingID(),
+		),
+	)
+
+	return &Renderer{engine: engine}
+}
+
+func (r *Renderer) Render(ctx context.Context, source string) (*stories.RenderedContent, error) {
+	sourceBytes := []byte(source)
+
+	document := r.engine.Parser().Parse(
+		text.NewReader(sourceBytes),
+		parser.WithContext(parser.NewContext(parser.WithIDs(newHeadingIDs()))),
+	)
+
+	toc, plainText := collect(document, sourceBytes)
+	addHeadingAnchors(document)
+
+	var buf bytes.Buffer
+
+	err := r.engine.Renderer().Render(&buf, sourceBytes, document)
+	if err != nil {
+		return nil, fmt.Errorf("failed to render markdown: %w", err)
+	}
+
+	return &stories.RenderedContent{
+		Html:            Sanitize(buf.String()),
+		PlainText:       plainText,
+		TableOfContents: toc,
+	}, nil
+}
+
+// collect walks the document once, gathering the headings for the table of
+// contents and the visible text for summaries and reading time.
+func collect(document ast.Node, source []byte) ([]*stories.Heading, string) {
+	toc := make([]*stories.Heading, 0)
+
+	var plainText strings.Builder
+
+	_ = ast.Walk(document, func(node ast.Node, entering bool) (ast.WalkStatus, error) {
+		if !entering {
+			if node.Type() == ast.TypeBlock && plainText.Len() > 0 {
+				plainText.WriteByte('\n')
+			}
+
+			return ast.WalkContinue, nil
+		}
+
+		switch n := node.(type) {
+		case *ast.Heading:
+			id, _ := n.AttributeString("id")
+			idBytes, _ := id.([]byte)
+
+			toc = append(toc, &stories.Heading{
+				Id:    string(idBytes),
+				Text:  nodeText(n, source),
+				Level: n.Level,
+			})
+		case *ast.Text:
+			plainText.Write(n.Segment.Value(source))
+
+			if n.SoftLineBreak() || n.HardLineBreak() {
+				plainText.WriteByte(' ')
+			}
+		case *ast.String:
+			plainText.Write(n.Value)
+		case *ast.AutoLink:
+			plainText.Write(n.Label(source))
+		case *ast.CodeBlock, *ast.FencedCodeBlock:
+			lines := n.Lines()
+			for i := range lines.Len() {
+				segment := lines.At(i)
+				plainText.Write(segment.Value(source))
+			}
+		case *ast.HTMLBlock, *ast.RawHTML:
+			return ast.WalkSkipChildren, nil
+		}
+
+		return ast.WalkContinue, nil
+	})
+
+	return toc, strings.TrimSpace(plainText.String())
+}
+
+func nodeText(node ast.Node, source []byte) string {
+	var builder strings.Builder
+
+	_ = ast.Walk(node, func(child ast.Node, entering bool) (ast.WalkStatus, error) {
+		if !entering {
+			return ast.WalkContinue, nil
+		}
+
+		switch n := child.(type) {
+		case *ast.Text:
+			builder.Write(n.Segment.Value(source))
+		case *ast.String:
+			builder.Write(n.Value)
+		}
+
+		return ast.WalkContinue, nil
+	})
+
+	return builder.String()
+}
+
+// addHeadingAnchors appends a self-link to every heading so readers can share
+// links to sections.
+func addHeadingAnchors(document ast.Node) {
+	_ = ast.Walk(document, func(node ast.Node, entering bool) (ast.WalkStatus, error) {
+		heading, isHeading := node.(*ast.Heading)
+		if !entering || !isHeading {
+			return ast.WalkContinue, nil
+		}
+
+		id, hasId := heading.AttributeString("id")
+		idBytes, _ := id.([]byte)
+
+		if !hasId || len(idBytes) == 0 {
+			return ast.WalkSkipChildren, nil
+		}
+
+		link := ast.NewLink()
+		link.Destination = append([]byte("#"), idBytes...)
+		link.SetAttributeString("class", []byte("anchor"))
+		link.AppendChild(link, ast.NewString([]byte("#")))
+
+		heading.AppendChild(heading, link)
+
+		return ast.WalkSkipChildren, nil
+	})
+}
@@ -0,0 +1,107 @@
+package markdown_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/eser/acik.io/pkg/api/adapters/markdown"
+	"github.com/eser/acik.io/pkg/api/business/stories"
+)
+
+func TestRenderTableOfContents(t *testing.T) {
+	t.Parallel()
+
+	rendered, err := markdown.NewRenderer().Render(context.Background(), "# Çalışma Günü\n\ntext\n\n## Çalışma Günü\n\n### !!!\n")
+	if err != nil {
+		t.Fatalf("rendering: %v", err)
+	}
+
+	want := []stories.Heading{
+		{Id: "calisma-gunu", Text: "Çalışma Günü", Level: 1},
+		{Id: "calisma-gunu-1", Text: "Çalışma Günü", Level: 2},
+		{Id: "heading", Text: "!!!", Level: 3},
+	}
+
+	if len(rendered.TableOfContents) != len(want) {
+		t.Fatalf("got %d headings, want %d", len(rendered.TableOfContents), len(want))
+	}
+
+	for i, heading := range rendered.TableOfContents {
+		if *heading != want[i] {
+			t.Errorf("got heading %+v, want %+v", *heading, want[i])
+		}
+
+		if !strings.Contains(rendered.Html, `<a href="#`+want[i].Id+`" class="anchor">#</a>`) {
+			t.Errorf("got no anchor to %s in %q", want[i].Id, rendered.Html)
+		}
+	}
+}
+
+func TestRenderPlainText(t *testing.T) {
+	t.Parallel()
+
+	rendered, err := markdown.NewRenderer().Render(context.Background(), "Hello *world*\n\n<div>raw</div>\n\n```\ncode\n```\n")
+	if err != nil {
+		t.Fatalf("rendering: %v", err)
+	}
+
+	if words := strings.Join(strings.Fields(rendered.PlainText), " "); words != "Hello world code" {
+		t.Errorf("got plain text %q, want the visible text without markup or raw HTML", rendered.PlainText)
+	}
+}
+
+func TestRenderSanitizes(t *testing.T) {
+	t.Parallel()
+
+	rendered, err := markdown.NewRenderer().Render(context.Background(), "[x](javascript:alert(1)) <img src=x onerror=alert(1)>\n\nhttps://example.com\n")
+	if err != nil {
+		t.Fatalf("rendering: %v", err)
+	}
+
+	for _, unsafe := range []string{"javascript:", "onerror"} {
+		if strings.Contains(rendered.Html, unsafe) {
+			t.Errorf("got %q in %q", unsafe, rendered.Html)
+		}
+	}
+
+	if !strings.Contains(rendered.Html, `<a href="https://example.com" rel="nofollow noopener">`) {
+		t.Errorf("got %q, want the bare link linkified with rel nofollow", rendered.Html)
+	}
+}
+
+// countingRenderer renders every source as itself, counting the calls.
+type countingRenderer struct {
+	calls int
+}
+
+func (r *countingRenderer) Render(_ context.Context, source string) (*stories.RenderedContent, error) {
+	r.calls++
+
+	return &stories.RenderedContent{Html: source, PlainText: source, TableOfContents: nil}, nil
+}
+
+func TestCachedRenderer(t *testing.T) {
+	t.Parallel()
+
+	inner := &countingRenderer{}
+	cached := markdown.NewCachedRenderer(inner, 2) //nolint:mnd
+	ctx := context.Background()
+
+	for _, source := range []string{"a", "b", "a", "c", "a", "b"} {
+		rendered, err := cached.Render(ctx, source)
+		if err != nil {
+			t.Fatalf("rendering %q: %v", source, err)
+		}
+
+		if rendered.Html != source {
+			t.Errorf("got %q for %q", rendered.Html, source)
+		}
+	}
+
+	// a is kept as the most recently used one, so c evicts b, which is
+	// rendered again.
+	if inner.calls != 4 { //nolint:mnd
+		t.Errorf("got %d renders, want 4", inner.calls)
+	}
+}
@@ -0,0 +1,181 @@
+package markdown
+
+import (
+	"net/url"
+	"strings"
+
+	xhtml "golang.org/x/net/html"
+)
+
+var (
+	// allowedAttributes lists every tag that survives sanitization along with
+	// the attributes it may keep.
+	allowedAttributes = map[string]map[string]bool{ //nolint:gochecknoglobals
+		"p": {}, "br": {}, "hr": {},
+		"h1": {"id": true}, "h2": {"id": true}, "h3": {"id": true},
+		"h4": {"id": true}, "h5": {"id": true}, "h6": {"id": true},
+		"blockquote": {}, "pre": {}, "code": {"class": true},
+		"em": {}, "strong": {}, "del": {}, "s": {},
+		"a":   {"href": true, "title": true, "class": true},
+		"img": {"src": true, "alt": true, "title": true},
+		"ul":  {}, "ol": {"start": true}, "li": {},
+		"input": {"type": true, "checked": true, "disabled": true},
+		"table": {}, "thead": {}, "tbody": {}, "tr": {},
+		"th": {"align": true}, "td": {"align": true},
+		"span": {}, "sup": {}, "sub": {},
+	}
+
+	// droppedWithContent lists tags whose content is removed along with them.
+	droppedWithContent = map[string]bool{ //nolint:gochecknoglobals
+		"script": true, "style": true, "iframe": true, "object": true,
+		"embed": true, "noscript": true, "template": true, "textarea": true,
+	}
+
+	voidTags = map[string]bool{"br": true, "hr": true, "img": true, "input": true} //nolint:gochecknoglobals
+)
+
+// Sanitize removes everything from the given HTML that is not on the
+// allowlist. Unknown tags are unwrapped, keeping their text.
+func Sanitize(input string) string { //nolint:cyclop
+	tokenizer := xhtml.NewTokenizer(strings.NewReader(input))
+
+	var output strings.Builder
+
+	skipDepth := 0
+
+	for {
+		tokenType := tokenizer.Next()
+
+		switch tokenType { //nolint:exhaustive
+		case xhtml.ErrorToken:
+			return output.String()
+		case xhtml.TextToken:
+			if skipDepth == 0 {
+				output.WriteString(xhtml.EscapeString(string(tokenizer.Text())))
+			}
+		case xhtml.StartTagToken, xhtml.SelfClosingTagToken:
+			token := tokenizer.Token()
+
+			if droppedWithContent[token.Data] {
+				if tokenType == xhtml.StartTagToken {
+					skipDepth++
+				}
+
+				continue
+			}
+
+			if skipDepth > 0 {
+				continue
+			}
+
+			writeStartTag(&output, token)
+		case xhtml.EndTagToken:
+			token := tokenizer.Token()
+
+			if droppedWithContent[token.Data] {
+				if skipDepth > 0 {
+					skipDepth--
+				}
+
+				continue
+			}
+
+			if skipDepth > 0 || voidTags[token.Data] {
+				continue
+			}
+
+			if _, allowed := allowedAttributes[token.Data]; allowed {
+				output.WriteString("</" + token.Data + ">")
+			}
+		}
+	}
+}
+
+func writeStartTag(output *strings.Builder, token xhtml.Token) {
+	attributes, allowed := allowedAttributes[token.Data]
+	if !allowed {
+		return
+	}
+
+	if token.Data == "input" && !isCheckbox(token) {
+		return
+	}
+
+	output.WriteString("<" + token.Data)
+
+	external := false
+
+	for _, attr := range token.Attr {
+		if !attributes[attr.Key] || !isAllowedValue(token.Data, attr) {
+			continue
+		}
+
+		if attr.Key == "href" && isExternalUrl(attr.Val) {
+			external = true
+		}
+
+		output.WriteString(" " + attr.Key + `="` + xhtml.EscapeString(attr.Val) + `"`)
+	}
+
+	if external {
+		output.WriteString(` rel="nofollow noopener"`)
+	}
+
+	output.WriteString(">")
+}
+
+func isAllowedValue(tag string, attr xhtml.Attribute) bool {
+	switch attr.Key {
+	case "href", "src":
+		return isSafeUrl(attr.Val, attr.Key == "href")
+	case "class":
+		if tag == "a" {
+			return attr.Val == "anchor"
+		}
+
+		return strings.HasPrefix(attr.Val, "language-") && !strings.ContainsAny(attr.Val, " \t\n")
+	case "align":
+		return attr.Val == "left" || attr.Val == "center" || attr.Val == "right"
+	case "type":
+		return attr.Val == "checkbox"
+	}
+
+	return true
+}
+
+func isCheckbox(token xhtml.Token) bool {
+	for _, attr := range token.Attr {
+		if attr.Key == "type" {
+			return attr.Val == "checkbox"
+		}
+	}
+
+	return false
+}
+
+func isSafeUrl(value string, allowMailto bool) bool {
+	parsed, err := url.Parse(strings.TrimSpace(value))
+	if err != nil {
+		return false
+	}
+
+	switch strings.ToLower(parsed.Scheme) {
+	case "":
+		return true
+	case "http", "https":
+		return true
+	case "mailto":
+		return allowMailto
+	default:
+		return false
+	}
+}
+
+func isExternalUrl(value string) bool {
+	parsed, err := url.Parse(strings.TrimSpace(value))
+	if err != nil {
+		return false
+	}
+
+	return parsed.Host != ""
+}
@@ -0,0 +1,134 @@
+package markdown_test
+
+import (
+	"testing"
+
+	"github.com/eser/acik.io/pkg/api/adapters/markdown"
+)
+
+func TestSanitize(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{
+			name:  "javascript url",
+			input: `<a href="javascript:alert(1)">x</a>`,
+			want:  `<a>x</a>`,
+		},
+		{
+			name:  "mixed case javascript url with leading space",
+			input: `<a href=" JaVaScRiPt:alert(1)">x</a>`,
+			want:  `<a>x</a>`,
+		},
+		{
+			name:  "data url link",
+			input: `<a href="data:text/html,&lt;script&gt;alert(1)&lt;/script&gt;">x</a>`,
+			want:  `<a>x</a>`,
+		},
+		{
+			name:  "data url image",
+			input: `<img src="data:image/svg+xml;base64,PHN2Zz4=">`,
+			want:  `<img>`,
+		},
+		{
+			name:  "mailto image",
+			input: `<img src="mailto:someone@example.com">`,
+			want:  `<img>`,
+		},
+		{
+			name:  "mailto link",
+			input: `<a href="mailto:someone@example.com">mail</a>`,
+			want:  `<a href="mailto:someone@example.com">mail</a>`,
+		},
+		{
+			name:  "entity encoded scheme",
+			input: `<a href="&#106;avascript:alert(1)">x</a>`,
+			want:  `<a>x</a>`,
+		},
+		{
+			name:  "entity encoded colon",
+			input: `<a href="javascript&colon;alert(1)">x</a>`,
+			want:  `<a>x</a>`,
+		},
+		{
+			name:  "entity encoded tab in scheme",
+			input: `<a href="java&#x09;script:alert(1)">x</a>`,
+			want:  `<a>x</a>`,
+		},
+		{
+			name:  "event handler on image",
+			input: `<img src="/a.png" onerror="alert(1)">`,
+			want:  `<img src="/a.png">`,
+		},
+		{
+			name:  "event handler and style on paragraph",
+			input: `<p onclick="alert(1)" style="color: red">hi</p>`,
+			want:  `<p>hi</p>`,
+		},
+		{
+			name:  "event handler on external link",
+			input: `<a href="https://example.com" onmouseover="alert(1)">x</a>`,
+			want:  `<a href="https://example.com" rel="nofollow noopener">x</a>`,
+		},
+		{
+			name:  "nested unknown tags",
+			input: `<div><section><p>hi</p></section></div>`,
+			want:  `<p>hi</p>`,
+		},
+		{
+			name:  "script within unknown tag",
+			input: `<svg><script>alert(1)</script></svg>ok`,
+			want:  `ok`,
+		},
+		{
+			name:  "script within script",
+			input: `<script><script></script>alert(1)</script>after`,
+			want:  `alert(1)after`,
+		},
+		{
+			name:  "split script tag",
+			input: `<scr<script>ipt>alert(1)</script>`,
+			want:  `ipt&gt;alert(1)`,
+		},
+		{
+			name:  "unclosed script",
+			input: `<script>alert(1)`,
+			want:  ``,
+		},
+		{
+			name:  "unclosed unknown tags",
+			input: `<b><i>text`,
+			want:  `text`,
+		},
+		{
+			name:  "escaped markup stays escaped",
+			input: `&lt;script&gt;alert(1)&lt;/script&gt;`,
+			want:  `&lt;script&gt;alert(1)&lt;/script&gt;`,
+		},
+		{
+			name:  "double escaped text is not unescaped twice",
+			input: `&amp;lt;script&amp;gt;alert(1)&amp;lt;/script&amp;gt;`,
+			want:  `&amp;lt;script&amp;gt;alert(1)&amp;lt;/script&amp;gt;`,
+		},
+		{
+			name:  "double escaped attribute is not unescaped twice",
+			input: `<a title="&amp;quot; onclick=&amp;quot;x" href="/">y</a>`,
+			want:  `<a title="&amp;quot; onclick=&amp;quot;x" href="/">y</a>`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			got := markdown.Sanitize(test.input)
+			if got != test.want {
+				t.Errorf("Sanitize(%q) = %q, want %q", test.input, got, test.want)
+			}
+		})
+	}
+}
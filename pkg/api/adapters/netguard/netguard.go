@@ -0,0 +1,38 @@
+package netguard
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"syscall"
+)
+
+var ErrPrivateAddress = errors.New("refusing to connect to a private address")
+
+// IsPrivateAddress reports whether an address is not publicly routable.
+func IsPrivateAddress(ip net.IP) bool {
+	return ip.IsLoopback() ||
+		ip.IsPrivate() ||
+		ip.IsLinkLocalUnicast() ||
+		ip.IsLinkLocalMulticast() ||
+		ip.IsInterfaceLocalMulticast() ||
+		ip.IsMulticast() ||
+		ip.IsUnspecified()
+}
+
+// Control is a net.Dialer control function refusing connections to addresses
+// that are not publicly routable. It runs on the resolved address of every
+// connection, so host names resolving to internal services are caught as well.
+func Control(_ string, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err //nolint:wrapcheck
+	}
+
+	ip := net.ParseIP(host)
+	if ip == nil || IsPrivateAddress(ip) {
+		return fmt.Errorf("%w(address: %s)", ErrPrivateAddress, address)
+	}
+
+	return nil
+}
@@ -0,0 +1,48 @@
+package netguard_test
+
+import (
+	"errors"
+	"net"
+	"testing"
+
+	"github.com/eser/acik.io/pkg/api/adapters/netguard"
+)
+
+func TestIsPrivateAddress(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		address string
+		want    bool
+	}{
+		{address: "127.0.0.1", want: true},
+		{address: "10.1.2.3", want: true},
+		{address: "192.168.1.1", want: true},
+		{address: "169.254.169.254", want: true},
+		{address: "0.0.0.0", want: true},
+		{address: "::1", want: true},
+		{address: "fd00::1", want: true},
+		{address: "93.184.216.34", want: false},
+		{address: "2606:2800:220:1::", want: false},
+	}
+
+	for _, test := range tests {
+		if got := netguard.IsPrivateAddress(net.ParseIP(test.address)); got != test.want {
+			t.Errorf("got %t for %s, want %t", got, test.address, test.want)
+		}
+	}
+}
+
+func TestControl(t *testing.T) {
+	t.Parallel()
+
+	for _, address := range []string{"127.0.0.1:80", "[::1]:443", "localhost:80"} {
+		if err := netguard.Control("tcp", address, nil); !errors.Is(err, netguard.ErrPrivateAddress) {
+			t.Errorf("got %v for %s, want %v", err, address, netguard.ErrPrivateAddress)
+		}
+	}
+
+	if err := netguard.Control("tcp", "93.184.216.34:443", nil); err != nil {
+		t.Errorf("got %v for a public address, want none", err)
+	}
+}
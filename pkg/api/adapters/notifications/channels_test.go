@@ -0,0 +1,485 @@
+package notifications_test
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"io"
+	"mime"
+	"mime/quotedprintable"
+	"net"
+	netmail "net/mail"
+	"net/textproto"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/eser/acik.io/pkg/api/adapters/mail"
+	adapternotifications "github.com/eser/acik.io/pkg/api/adapters/notifications"
+	"github.com/eser/acik.io/pkg/api/business/notifications"
+	"github.com/eser/acik.io/pkg/api/business/notifications/notificationstest"
+	"github.com/eser/acik.io/pkg/api/business/outbox"
+	"github.com/eser/acik.io/pkg/api/business/outbox/outboxtest"
+	"github.com/eser/acik.io/pkg/api/business/profiles"
+	"github.com/eser/acik.io/pkg/api/business/users"
+	"github.com/eser/acik.io/pkg/api/business/webhooks"
+	"github.com/eser/acik.io/pkg/api/business/webhooks/webhookstest"
+)
+
+const (
+	siteUrl        = "https://acik.io"
+	userAyse       = "01HUSERAYSE000000000000000"
+	userMehmet     = "01HUSERMEHMET0000000000000"
+	profileAyse    = "01HPROFILEAYSE0000000000000"
+	endpointAyse   = "01HENDPOINTAYSE000000000000"
+	notificationId = "01HNOTIFICATION000000000000"
+)
+
+type received struct {
+	recipients []string
+	data       []byte
+}
+
+// smtpServer is a fake SMTP relay accepting every mail, without STARTTLS or
+// authentication.
+type smtpServer struct {
+	listener net.Listener
+	mails    []*received
+	mu       sync.Mutex
+}
+
+func newSmtpServer(t *testing.T) *smtpServer {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	server := &smtpServer{listener: listener} //nolint:exhaustruct
+
+	go server.serve()
+
+	t.Cleanup(func() { listener.Close() }) //nolint:errcheck,gosec
+
+	return server
+}
+
+func (s *smtpServer) serve() {
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			return
+		}
+
+		go s.handle(conn)
+	}
+}
+
+func (s *smtpServer) handle(conn net.Conn) {
+	defer conn.Close() //nolint:errcheck
+
+	text := textproto.NewConn(conn)
+	mail := &received{} //nolint:exhaustruct
+
+	_ = text.PrintfLine("220 localhost ESMTP")
+
+	for {
+		line, err := text.ReadLine()
+		if err != nil {
+			return
+		}
+
+		verb, arg, _ := strings.Cut(line, " ")
+
+		switch strings.ToUpper(verb) {
+		case "EHLO", "HELO":
+			_ = text.PrintfLine("250 localhost")
+		case "MAIL":
+			_ = text.PrintfLine("250 OK")
+		case "RCPT":
+			mail.recipients = append(mail.recipients, strings.Trim(strings.TrimPrefix(arg, "TO:"), "<>"))
+			_ = text.PrintfLine("250 OK")
+		case "DATA":
+			_ = text.PrintfLine("354 Go ahead")
+
+			mail.data, err = text.ReadDotBytes()
+			if err != nil {
+				return
+			}
+
+			s.mu.Lock()
+			s.mails = append(s.mails, mail)
+			s.mu.Unlock()
+
+			mail = &received{} //nolint:exhaustruct
+			_ = text.PrintfLine("250 OK")
+		case "QUIT":
+			_ = text.PrintfLine("221 Bye")
+
+			return
+		default:
+			_ = text.PrintfLine("250 OK")
+		}
+	}
+}
+
+func (s *smtpServer) received() []*received {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return append([]*received{}, s.mails...)
+}
+
+func (s *smtpServer) mailer() *mail.SmtpMailer {
+	return mail.NewSmtpMailer(&mail.Config{
+		SmtpAddr: s.listener.Addr().String(),
+		Username: "",
+		Password: "",
+		From:     "acik.io <noreply@acik.io>",
+		Timeout:  5 * time.Second,
+	})
+}
+
+type parsedMail struct {
+	to      string
+	subject string
+	body    string
+}
+
+func parse(t *testing.T, data []byte) *parsedMail {
+	t.Helper()
+
+	message, err := netmail.ReadMessage(bufio.NewReader(bytes.NewReader(data)))
+	if err != nil {
+		t.Fatalf("parsing mail: %v", err)
+	}
+
+	subject, err := new(mime.WordDecoder).DecodeHeader(message.Header.Get("Subject"))
+	if err != nil {
+		t.Fatalf("decoding subject: %v", err)
+	}
+
+	body, err := io.ReadAll(quotedprintable.NewReader(message.Body))
+	if err != nil {
+		t.Fatalf("decoding body: %v", err)
+	}
+
+	return &parsedMail{to: message.Header.Get("To"), subject: subject, body: strings.TrimRight(string(body), "\r\n")}
+}
+
+func notification(linkUri string) *notifications.Notification {
+	return &notifications.Notification{
+		Id:        notificationId,
+		UserId:    userAyse,
+		Type:      notifications.TypeQuestionAnswered,
+		SourceId:  "source-1",
+		Title:     "Sorunuz yanıtlandı",
+		Body:      "It depends.",
+		LinkUri:   sql.NullString{String: linkUri, Valid: linkUri != ""},
+		Data:      json.RawMessage("{}"),
+		Channels:  "email",
+		ReadAt:    sql.NullTime{}, //nolint:exhaustruct
+		CreatedAt: time.Now(),
+	}
+}
+
+func TestEmailChannelMailsNotification(t *testing.T) {
+	t.Parallel()
+
+	server := newSmtpServer(t)
+	channel := adapternotifications.NewEmailChannel(server.mailer(), siteUrl)
+
+	err := channel.Send(
+		context.Background(),
+		&notifications.Recipient{UserId: userAyse, Name: "Ayşe", Email: "ayse@example.com", IndividualProfileId: ""},
+		notification("/questions/1"),
+	)
+	if err != nil {
+		t.Fatalf("sending: %v", err)
+	}
+
+	mails := server.received()
+	if len(mails) != 1 {
+		t.Fatalf("got %d mails, want 1", len(mails))
+	}
+
+	if len(mails[0].recipients) != 1 || mails[0].recipients[0] != "ayse@example.com" {
+		t.Errorf("got recipients %v, want ayse@example.com", mails[0].recipients)
+	}
+
+	parsed := parse(t, mails[0].data)
+
+	if !strings.Contains(parsed.to, "ayse@example.com") {
+		t.Errorf("got To %q, want the address of the recipient", parsed.to)
+	}
+
+	if parsed.subject != "Sorunuz yanıtlandı" {
+		t.Errorf("got subject %q, want the notification title", parsed.subject)
+	}
+
+	if parsed.body != "It depends.\n\nhttps://acik.io/questions/1" {
+		t.Errorf("got body %q, want the notification body and its absolute link", parsed.body)
+	}
+}
+
+func TestEmailChannelSkipsRecipientsWithoutAddress(t *testing.T) {
+	t.Parallel()
+
+	server := newSmtpServer(t)
+	channel := adapternotifications.NewEmailChannel(server.mailer(), siteUrl)
+
+	err := channel.Send(
+		context.Background(),
+		&notifications.Recipient{UserId: userMehmet, Name: "Mehmet", Email: "", IndividualProfileId: ""},
+		notification(""),
+	)
+	if err != nil {
+		t.Fatalf("sending: %v", err)
+	}
+
+	if mails := server.received(); len(mails) != 0 {
+		t.Errorf("got %d mails, want none", len(mails))
+	}
+}
+
+func TestEmailChannelReportsRelayFailures(t *testing.T) {
+	t.Parallel()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	addr := listener.Addr().String()
+	listener.Close() //nolint:errcheck,gosec
+
+	channel := adapternotifications.NewEmailChannel(mail.NewSmtpMailer(&mail.Config{ //nolint:exhaustruct
+		SmtpAddr: addr,
+		From:     "acik.io <noreply@acik.io>",
+		Timeout:  time.Second,
+	}), siteUrl)
+
+	err = channel.Send(
+		context.Background(),
+		&notifications.Recipient{UserId: userAyse, Name: "Ayşe", Email: "ayse@example.com", IndividualProfileId: ""},
+		notification(""),
+	)
+	if !errors.Is(err, mail.ErrFailedToSend) {
+		t.Errorf("got %v, want %v", err, mail.ErrFailedToSend)
+	}
+}
+
+func newWebhookChannel(t *testing.T) (*adapternotifications.WebhookChannel, *webhookstest.Repository) {
+	t.Helper()
+
+	repo := webhookstest.NewRepository()
+
+	_, err := repo.CreateWebhookEndpoint(context.Background(), webhooks.CreateWebhookEndpointParams{
+		Id:          endpointAyse,
+		ProfileId:   profileAyse,
+		Url:         "https://example.com/hook",
+		Secret:      webhooks.GenerateSecret(),
+		EventTypes:  notifications.EventNotificationCreated,
+		Description: "",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	service := webhooks.NewService(&webhooks.Config{}, repo, nil, nil, outboxtest.Discard{}) //nolint:exhaustruct
+
+	return adapternotifications.NewWebhookChannel(service), repo
+}
+
+func TestWebhookChannelPublishesToIndividualProfile(t *testing.T) {
+	t.Parallel()
+
+	channel, repo := newWebhookChannel(t)
+
+	err := channel.Send(
+		context.Background(),
+		&notifications.Recipient{UserId: userAyse, Name: "Ayşe", Email: "", IndividualProfileId: profileAyse},
+		notification("/questions/1"),
+	)
+	if err != nil {
+		t.Fatalf("sending: %v", err)
+	}
+
+	deliveries := repo.Deliveries(endpointAyse)
+	if len(deliveries) != 1 {
+		t.Fatalf("got %d deliveries, want 1", len(deliveries))
+	}
+
+	if deliveries[0].EventId != notificationId || deliveries[0].EventType != notifications.EventNotificationCreated {
+		t.Errorf("got delivery of %s %s, want notification %s", deliveries[0].EventType, deliveries[0].EventId, notificationId)
+	}
+
+	var message struct {
+		Data notifications.Notification `json:"data"`
+	}
+
+	err = json.Unmarshal(deliveries[0].Payload, &message)
+	if err != nil {
+		t.Fatalf("decoding payload: %v", err)
+	}
+
+	if message.Data.Id != notificationId || message.Data.Title != "Sorunuz yanıtlandı" {
+		t.Errorf("got notification %+v in the payload", message.Data)
+	}
+}
+
+func TestWebhookChannelSkipsRecipientsWithoutProfile(t *testing.T) {
+	t.Parallel()
+
+	channel, repo := newWebhookChannel(t)
+
+	err := channel.Send(
+		context.Background(),
+		&notifications.Recipient{UserId: userMehmet, Name: "Mehmet", Email: "", IndividualProfileId: ""},
+		notification(""),
+	)
+	if err != nil {
+		t.Fatalf("sending: %v", err)
+	}
+
+	if deliveries := repo.Deliveries(endpointAyse); len(deliveries) != 0 {
+		t.Errorf("got %d deliveries, want none", len(deliveries))
+	}
+}
+
+// TestNotificationsFollowPreferences runs domain events through the
+// notifications service into the email and webhook channels, as the worker
+// does, and checks that opting out of a channel stops its deliveries.
+func TestNotificationsFollowPreferences(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	server := newSmtpServer(t)
+	webhookChannel, webhookRepo := newWebhookChannel(t)
+	repo := notificationstest.NewRepository()
+	recorder := outboxtest.NewRecorder()
+
+	service := notifications.NewService(
+		&notifications.Config{SiteUrl: siteUrl, DefaultChannels: "in_app,email"},
+		repo,
+		notificationstest.Users{
+			userAyse: &users.User{ //nolint:exhaustruct
+				Id:                  userAyse,
+				Name:                "Ayşe",
+				Email:               sql.NullString{String: "ayse@example.com", Valid: true},
+				IndividualProfileId: sql.NullString{String: profileAyse, Valid: true},
+			},
+		},
+		notificationstest.Attendees{},
+		recorder,
+		map[string]notifications.Channel{
+			notifications.ChannelEmail:   adapternotifications.NewEmailChannel(server.mailer(), siteUrl),
+			notifications.ChannelWebhook: webhookChannel,
+		},
+	)
+
+	invite := func(sourceId string, profileTitle string) {
+		t.Helper()
+
+		payload, err := json.Marshal(&profiles.MemberInvitedEvent{
+			ProfileId:       "01HPROFILEACIK0000000000000",
+			ProfileSlug:     "acik",
+			ProfileTitle:    profileTitle,
+			UserId:          userAyse,
+			Kind:            "member",
+			InvitedByUserId: userMehmet,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		err = service.HandleDomainEvent(ctx, &outbox.Envelope{
+			OccurredAt:    time.Now(),
+			Id:            sourceId,
+			AggregateType: "profile",
+			AggregateId:   "01HPROFILEACIK0000000000000",
+			EventType:     profiles.EventMemberInvited,
+			Payload:       payload,
+			Attempt:       0,
+		})
+		if err != nil {
+			t.Fatalf("handling event: %v", err)
+		}
+	}
+
+	delivered := 0
+	deliverNew := func() {
+		t.Helper()
+
+		envelopes := recorder.Envelopes(notifications.EventNotificationCreated)
+		defer func() { delivered = len(envelopes) }()
+
+		for _, envelope := range envelopes[delivered:] {
+			err := service.Deliver(ctx, envelope)
+			if err != nil {
+				t.Fatalf("delivering: %v", err)
+			}
+		}
+	}
+
+	// the defaults mail the invitation.
+	invite("01HSOURCE00000000000000001", "Açık")
+	deliverNew()
+
+	if mails := server.received(); len(mails) != 1 || parse(t, mails[0].data).subject != "You were invited to Açık" {
+		t.Fatalf("got %d mails, want the invitation", len(mails))
+	}
+
+	if deliveries := webhookRepo.Deliveries(endpointAyse); len(deliveries) != 0 {
+		t.Errorf("got %d webhook deliveries without opting in, want none", len(deliveries))
+	}
+
+	// opting out of email and into webhooks.
+	_, err := service.SetPreference(
+		ctx,
+		userAyse,
+		notifications.TypeProfileInvitation,
+		&notifications.PreferenceInput{Channels: []string{notifications.ChannelInApp, notifications.ChannelWebhook}},
+	)
+	if err != nil {
+		t.Fatalf("setting preference: %v", err)
+	}
+
+	invite("01HSOURCE00000000000000002", "Başka")
+	deliverNew()
+
+	if mails := server.received(); len(mails) != 1 {
+		t.Errorf("got %d mails after opting out of email, want 1", len(mails))
+	}
+
+	if deliveries := webhookRepo.Deliveries(endpointAyse); len(deliveries) != 1 {
+		t.Errorf("got %d webhook deliveries after opting in, want 1", len(deliveries))
+	}
+
+	// muting the type stops the notifications altogether.
+	_, err = service.SetPreference(
+		ctx,
+		userAyse,
+		notifications.TypeProfileInvitation,
+		&notifications.PreferenceInput{Channels: []string{}},
+	)
+	if err != nil {
+		t.Fatalf("setting preference: %v", err)
+	}
+
+	invite("01HSOURCE00000000000000003", "Üçüncü")
+	deliverNew()
+
+	if got := len(repo.Notifications(userAyse)); got != 2 {
+		t.Errorf("got %d notifications after muting, want 2", got)
+	}
+
+	if mails := server.received(); len(mails) != 1 {
+		t.Errorf("got %d mails after muting, want 1", len(mails))
+	}
+}
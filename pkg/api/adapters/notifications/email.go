@@ -0,0 +1,52 @@
+package notifications
+
+import (
+	"context"
+	"net/mail"
+	"strings"
+
+	adaptermail "github.com/eser/acik.io/pkg/api/adapters/mail"
+	"github.com/eser/acik.io/pkg/api/business/notifications"
+)
+
+type Mailer interface {
+	Send(ctx context.Context, message *adaptermail.Message) error
+}
+
+// EmailChannel mails notifications as plain text to the address of their
+// recipients. Recipients without an address are skipped.
+type EmailChannel struct {
+	mailer  Mailer
+	siteUrl string
+}
+
+func NewEmailChannel(mailer Mailer, siteUrl string) *EmailChannel {
+	return &EmailChannel{mailer: mailer, siteUrl: siteUrl}
+}
+
+func (c *EmailChannel) Send(
+	ctx context.Context,
+	recipient *notifications.Recipient,
+	notification *notifications.Notification,
+) error {
+	if recipient.Email == "" {
+		return nil
+	}
+
+	var text strings.Builder
+
+	text.WriteString(notification.Body)
+
+	if link := notifications.AbsoluteLink(c.siteUrl, notification); link != "" {
+		text.WriteString("\n\n")
+		text.WriteString(link)
+	}
+
+	to := &mail.Address{Name: recipient.Name, Address: recipient.Email}
+
+	return c.mailer.Send(ctx, &adaptermail.Message{ //nolint:wrapcheck,exhaustruct
+		To:      to.String(),
+		Subject: notification.Title,
+		Text:    text.String(),
+	})
+}
@@ -0,0 +1,43 @@
+package notifications
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/eser/acik.io/pkg/api/business/notifications"
+	"github.com/eser/acik.io/pkg/api/business/webhooks"
+)
+
+// WebhookChannel delivers notifications to the webhook endpoints of the
+// individual profile of their recipients that subscribe to
+// notification.created.
+type WebhookChannel struct {
+	webhooks *webhooks.Service
+}
+
+func NewWebhookChannel(webhooks *webhooks.Service) *WebhookChannel {
+	return &WebhookChannel{webhooks: webhooks}
+}
+
+func (c *WebhookChannel) Send(
+	ctx context.Context,
+	recipient *notifications.Recipient,
+	notification *notifications.Notification,
+) error {
+	if recipient.IndividualProfileId == "" {
+		return nil
+	}
+
+	data, err := json.Marshal(notification)
+	if err != nil {
+		return fmt.Errorf("%w(id: %s): %w", notifications.ErrFailedToDeliver, notification.Id, err)
+	}
+
+	return c.webhooks.Publish(ctx, recipient.IndividualProfileId, &webhooks.Message{ //nolint:wrapcheck
+		OccurredAt: notification.CreatedAt,
+		Id:         notification.Id,
+		Type:       notifications.EventNotificationCreated,
+		Data:       data,
+	})
+}
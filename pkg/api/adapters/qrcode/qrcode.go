@@ -0,0 +1,23 @@
+package qrcode
+
+import (
+	"errors"
+	"fmt"
+
+	"rsc.io/qr"
+)
+
+const DefaultScale = 8
+
+var ErrFailedToEncode = errors.New("failed to encode qr code")
+
+func EncodePng(content string) ([]byte, error) {
+	code, err := qr.Encode(content, qr.M)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrFailedToEncode, err)
+	}
+
+	code.Scale = DefaultScale
+
+	return code.PNG(), nil
+}
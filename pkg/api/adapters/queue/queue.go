@@ -0,0 +1,56 @@
+package queue
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/eser/ajan/queuefx"
+)
+
+var (
+	ErrBrokerNotFound        = errors.New("broker not found")
+	ErrFailedToEncodePayload = errors.New("failed to encode payload")
+	ErrFailedToEnqueue       = errors.New("failed to enqueue")
+)
+
+// Publisher enqueues JSON encoded jobs through a queuefx broker. The broker is
+// resolved on every call, so a missing broker only fails the callers that
+// actually enqueue something.
+type Publisher struct {
+	registry   *queuefx.Registry
+	brokerName string
+}
+
+func NewFromDefault(registry *queuefx.Registry) *Publisher {
+	return &Publisher{registry: registry, brokerName: queuefx.DefaultBroker}
+}
+
+func NewFromNamed(registry *queuefx.Registry, name string) *Publisher {
+	return &Publisher{registry: registry, brokerName: name}
+}
+
+func (p *Publisher) Enqueue(ctx context.Context, queueName string, payload any) error {
+	broker := p.registry.GetNamed(p.brokerName)
+	if broker == nil {
+		return fmt.Errorf("%w - %s", ErrBrokerNotFound, p.brokerName)
+	}
+
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("%w(queue: %s): %w", ErrFailedToEncodePayload, queueName, err)
+	}
+
+	_, err = broker.QueueDeclare(ctx, queueName)
+	if err != nil {
+		return fmt.Errorf("%w(queue: %s): %w", ErrFailedToEnqueue, queueName, err)
+	}
+
+	err = broker.Publish(ctx, queueName, body)
+	if err != nil {
+		return fmt.Errorf("%w(queue: %s): %w", ErrFailedToEnqueue, queueName, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,69 @@
+package ratelimitstore
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/eser/acik.io/pkg/api/business/ratelimit"
+)
+
+// MemoryStore keeps the buckets in process. Each instance limits on its
+// own, so the limits are multiplied by the number of instances.
+type MemoryStore struct {
+	buckets   map[string]*ratelimit.Bucket
+	idleTtl   time.Duration
+	lastSweep time.Time
+	now       func() time.Time
+	mu        sync.Mutex
+}
+
+func NewMemoryStore(idleTtl time.Duration) *MemoryStore {
+	return &MemoryStore{ //nolint:exhaustruct
+		buckets:   map[string]*ratelimit.Bucket{},
+		idleTtl:   idleTtl,
+		lastSweep: time.Now(),
+		now:       time.Now,
+	}
+}
+
+func (s *MemoryStore) Take(_ context.Context, key string, policy *ratelimit.Policy) (*ratelimit.Decision, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+
+	// nothing else sweeps the buckets of this process.
+	if s.idleTtl > 0 && now.Sub(s.lastSweep) >= s.idleTtl {
+		s.sweep(now.Add(-s.idleTtl))
+		s.lastSweep = now
+	}
+
+	bucket, ok := s.buckets[key]
+	if !ok {
+		bucket = ratelimit.NewBucket(policy, now)
+		s.buckets[key] = bucket
+	}
+
+	return bucket.Take(policy, now), nil
+}
+
+func (s *MemoryStore) Sweep(_ context.Context, idleBefore time.Time) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.sweep(idleBefore), nil
+}
+
+func (s *MemoryStore) sweep(idleBefore time.Time) int64 {
+	var deleted int64
+
+	for key, bucket := range s.buckets {
+		if bucket.UpdatedAt.Before(idleBefore) {
+			delete(s.buckets, key)
+			deleted++
+		}
+	}
+
+	return deleted
+}
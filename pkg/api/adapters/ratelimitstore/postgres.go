@@ -0,0 +1,41 @@
+package ratelimitstore
+
+import (
+	"context"
+	"time"
+
+	"github.com/eser/acik.io/pkg/api/business/ratelimit"
+)
+
+type Repository interface {
+	TakeRateLimitToken(ctx context.Context, arg ratelimit.TakeRateLimitTokenParams) (*ratelimit.TakeRateLimitTokenRow, error)
+	DeleteIdleRateLimitBuckets(ctx context.Context, idleBefore time.Time) (int64, error)
+}
+
+// PostgresStore keeps the buckets in the database, shared by every
+// instance. A take is a single upsert, so concurrent requests can't both
+// spend the last token, and the database clock is the only one used.
+type PostgresStore struct {
+	repo Repository
+}
+
+func NewPostgresStore(repo Repository) *PostgresStore {
+	return &PostgresStore{repo: repo}
+}
+
+func (s *PostgresStore) Take(ctx context.Context, key string, policy *ratelimit.Policy) (*ratelimit.Decision, error) {
+	row, err := s.repo.TakeRateLimitToken(ctx, ratelimit.TakeRateLimitTokenParams{
+		Key:        key,
+		Capacity:   float64(policy.Limit),
+		RefillRate: policy.Rate(),
+	})
+	if err != nil {
+		return nil, err //nolint:wrapcheck
+	}
+
+	return policy.Decide(row.Tokens, row.IsAllowed), nil
+}
+
+func (s *PostgresStore) Sweep(ctx context.Context, idleBefore time.Time) (int64, error) {
+	return s.repo.DeleteIdleRateLimitBuckets(ctx, idleBefore) //nolint:wrapcheck
+}
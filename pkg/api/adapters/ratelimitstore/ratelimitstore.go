@@ -0,0 +1,25 @@
+package ratelimitstore
+
+import (
+	"fmt"
+
+	"github.com/eser/acik.io/pkg/api/adapters/storage"
+	"github.com/eser/acik.io/pkg/api/business/ratelimit"
+)
+
+const (
+	BackendMemory   = "memory"
+	BackendPostgres = "postgres"
+)
+
+// NewFromConfig returns the bucket store the configuration selects.
+func NewFromConfig(config *ratelimit.Config, store *storage.Queries) (ratelimit.Store, error) { //nolint:ireturn
+	switch config.Backend {
+	case BackendMemory:
+		return NewMemoryStore(config.IdleTtl), nil
+	case BackendPostgres:
+		return NewPostgresStore(store), nil
+	default:
+		return nil, fmt.Errorf("%w: %s", ratelimit.ErrUnknownBackend, config.Backend)
+	}
+}
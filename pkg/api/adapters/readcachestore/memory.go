@@ -0,0 +1,97 @@
+package readcachestore
+
+import (
+	"container/list"
+	"context"
+	"sync"
+	"time"
+)
+
+type memoryEntry struct {
+	expiresAt time.Time
+	key       string
+	value     []byte
+}
+
+// MemoryStore keeps the entries in process, evicting the least recently
+// used once it holds size entries. Expired entries are dropped as they are
+// read or evicted.
+type MemoryStore struct {
+	entries map[string]*list.Element
+	order   *list.List
+	now     func() time.Time
+	size    int
+	mu      sync.Mutex
+}
+
+func NewMemoryStore(size int) *MemoryStore {
+	return &MemoryStore{ //nolint:exhaustruct
+		entries: make(map[string]*list.Element, size),
+		order:   list.New(),
+		now:     time.Now,
+		size:    size,
+	}
+}
+
+func (s *MemoryStore) Get(_ context.Context, key string) ([]byte, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	element, ok := s.entries[key]
+	if !ok {
+		return nil, false, nil
+	}
+
+	entry, _ := element.Value.(*memoryEntry)
+	if !s.now().Before(entry.expiresAt) {
+		s.remove(element)
+
+		return nil, false, nil
+	}
+
+	s.order.MoveToFront(element)
+
+	return entry.value, true, nil
+}
+
+func (s *MemoryStore) Set(_ context.Context, key string, value []byte, ttl time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entry := &memoryEntry{expiresAt: s.now().Add(ttl), key: key, value: value}
+
+	if element, ok := s.entries[key]; ok {
+		element.Value = entry
+		s.order.MoveToFront(element)
+
+		return nil
+	}
+
+	s.entries[key] = s.order.PushFront(entry)
+
+	for s.order.Len() > s.size {
+		s.remove(s.order.Back())
+	}
+
+	return nil
+}
+
+func (s *MemoryStore) Delete(_ context.Context, keys ...string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, key := range keys {
+		if element, ok := s.entries[key]; ok {
+			s.remove(element)
+		}
+	}
+
+	return nil
+}
+
+func (s *MemoryStore) remove(element *list.Element) {
+	entry, _ := element.Value.(*memoryEntry)
+
+	s.order.Remove(element)
+	delete(s.entries, entry.key)
+}
@@ -0,0 +1,52 @@
+package readcachestore
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+type MetricsProvider interface {
+	GetRegistry() *prometheus.Registry
+}
+
+// Metrics counts the lookups of each cache by outcome and times the loads
+// of its misses, see readcache.Metrics.
+type Metrics struct {
+	LookupsTotal *prometheus.CounterVec
+	LoadDuration *prometheus.HistogramVec
+}
+
+func NewMetrics(mp MetricsProvider) *Metrics { //nolint:varnamelen
+	lookupsTotal := prometheus.NewCounterVec(
+		prometheus.CounterOpts{ //nolint:exhaustruct
+			Name: "read_cache_lookups_total",
+			Help: "Total number of read cache lookups by cache and outcome",
+		},
+		[]string{"cache", "outcome"},
+	)
+
+	loadDuration := prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{ //nolint:exhaustruct
+			Name:    "read_cache_load_duration_seconds",
+			Help:    "Duration of loading the records missing from the read cache",
+			Buckets: prometheus.DefBuckets,
+		},
+		[]string{"cache"},
+	)
+
+	mp.GetRegistry().MustRegister(lookupsTotal, loadDuration)
+
+	return &Metrics{
+		LookupsTotal: lookupsTotal,
+		LoadDuration: loadDuration,
+	}
+}
+
+func (m *Metrics) CountLookup(name string, outcome string) {
+	m.LookupsTotal.WithLabelValues(name, outcome).Inc()
+}
+
+func (m *Metrics) ObserveLoad(name string, duration time.Duration) {
+	m.LoadDuration.WithLabelValues(name).Observe(duration.Seconds())
+}
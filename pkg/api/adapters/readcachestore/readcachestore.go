@@ -0,0 +1,48 @@
+package readcachestore
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/eser/acik.io/pkg/api/business/readcache"
+	"github.com/eser/ajan/cachefx"
+)
+
+const (
+	BackendMemory = "memory"
+	BackendRedis  = "redis"
+)
+
+// NewFromConfig returns the entry store the configuration selects. The redis
+// backend uses the default connection of ajan's cache registry. The memory
+// backend is only invalidated by the changes made through its own instance,
+// so it is refused when several instances serve the api.
+func NewFromConfig(config *readcache.Config, caches *cachefx.Registry) (readcache.Store, error) { //nolint:ireturn
+	switch config.Backend {
+	case BackendMemory:
+		if config.Instances > 1 {
+			return nil, fmt.Errorf(
+				"%w: the memory backend can't be kept by %d instances, use the redis backend",
+				readcache.ErrInvalidConfig,
+				config.Instances,
+			)
+		}
+
+		size, err := strconv.Atoi(strings.TrimSpace(config.Size))
+		if err != nil || size <= 0 {
+			return nil, fmt.Errorf("%w: size %q must be a positive number", readcache.ErrInvalidConfig, config.Size)
+		}
+
+		return NewMemoryStore(size), nil
+	case BackendRedis:
+		cache := caches.GetDefault()
+		if cache == nil {
+			return nil, fmt.Errorf("%w: the redis backend needs a default cache connection", readcache.ErrInvalidConfig)
+		}
+
+		return NewRedisStore(cache), nil
+	default:
+		return nil, fmt.Errorf("%w: %s", readcache.ErrUnknownBackend, config.Backend)
+	}
+}
@@ -0,0 +1,48 @@
+package readcachestore
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/eser/ajan/cachefx"
+)
+
+// RedisStore keeps the entries in a Redis compatible server through ajan's
+// cache registry, sharing them, and their invalidation, between instances.
+type RedisStore struct {
+	cache cachefx.Cache
+}
+
+func NewRedisStore(cache cachefx.Cache) *RedisStore {
+	return &RedisStore{cache: cache}
+}
+
+func (s *RedisStore) Get(ctx context.Context, key string) ([]byte, bool, error) {
+	value, err := s.cache.Get(ctx, key)
+	if err != nil {
+		return nil, false, err //nolint:wrapcheck
+	}
+
+	// the cache tells missing keys by an empty value; entries are never
+	// empty.
+	if value == "" {
+		return nil, false, nil
+	}
+
+	return []byte(value), true, nil
+}
+
+func (s *RedisStore) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	return s.cache.Set(ctx, key, value, ttl) //nolint:wrapcheck
+}
+
+func (s *RedisStore) Delete(ctx context.Context, keys ...string) error {
+	errs := make([]error, 0, len(keys))
+
+	for _, key := range keys {
+		errs = append(errs, s.cache.Delete(ctx, key))
+	}
+
+	return errors.Join(errs...)
+}
@@ -18,7 +18,7 @@ func NewFromDefault(dataRegistry *datafx.Registry) (*Queries, error) {
 
 	db := datasource.GetConnection()
 
-	return &Queries{db: db}, nil
+	return &Queries{db: &unitOfWorkDB{connection: db}}, nil
 }
 
 func NewFromNamed(dataRegistry *datafx.Registry, name string) (*Queries, error) {
@@ -30,5 +30,5 @@ func NewFromNamed(dataRegistry *datafx.Registry, name string) (*Queries, error)
 
 	db := datasource.GetConnection()
 
-	return &Queries{db: db}, nil
+	return &Queries{db: &unitOfWorkDB{connection: db}}, nil
 }
@@ -0,0 +1,109 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/eser/ajan/datafx"
+)
+
+var ErrDedicatedConnectionsNotSupported = errors.New("dedicated connections are not supported by the datasource")
+
+type connectionPool interface {
+	Conn(ctx context.Context) (*sql.Conn, error)
+}
+
+// AdvisoryLeader elects a leader among the instances sharing a database with
+// a session level advisory lock. The lock lives as long as the connection
+// holding it, so the leader keeps a dedicated connection out of the pool and
+// a crashed leader hands the leadership over as soon as its session ends.
+type AdvisoryLeader struct {
+	pool connectionPool
+	conn *sql.Conn
+	key  int64
+	mu   sync.Mutex
+}
+
+func NewAdvisoryLeaderFromDefault(dataRegistry *datafx.Registry, key int64) (*AdvisoryLeader, error) {
+	datasource := dataRegistry.GetDefault()
+
+	if datasource == nil {
+		return nil, fmt.Errorf("%w - default", ErrDatasourceNotFound)
+	}
+
+	pool, isPool := datasource.GetConnection().(connectionPool)
+	if !isPool {
+		return nil, ErrDedicatedConnectionsNotSupported
+	}
+
+	return &AdvisoryLeader{pool: pool, conn: nil, key: key, mu: sync.Mutex{}}, nil
+}
+
+func (l *AdvisoryLeader) TryLead(ctx context.Context) (bool, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.conn != nil {
+		err := l.conn.PingContext(ctx)
+		if err == nil {
+			return true, nil
+		}
+
+		// the session is gone, and the lock along with it.
+		discard(l.conn)
+		l.conn = nil
+	}
+
+	conn, err := l.pool.Conn(ctx)
+	if err != nil {
+		return false, err //nolint:wrapcheck
+	}
+
+	acquired, err := New(conn).TryScheduleLeaderLock(ctx, l.key)
+	if err != nil || !acquired {
+		conn.Close() //nolint:errcheck,gosec
+
+		return false, err
+	}
+
+	l.conn = conn
+
+	return true, nil
+}
+
+func (l *AdvisoryLeader) Resign(ctx context.Context) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.conn == nil {
+		return nil
+	}
+
+	conn := l.conn
+	l.conn = nil
+
+	released, err := New(conn).ReleaseScheduleLeaderLock(ctx, l.key)
+	if err != nil || !released {
+		// a connection that may still hold the lock must not go back to the
+		// pool, closing its session releases the lock instead.
+		discard(conn)
+
+		return err
+	}
+
+	return conn.Close() //nolint:wrapcheck
+}
+
+// discard closes the session of a dedicated connection rather than returning
+// it to the pool.
+func discard(conn *sql.Conn) {
+	_ = conn.Raw(func(any) error {
+		return driver.ErrBadConn
+	})
+
+	conn.Close() //nolint:errcheck,gosec
+}
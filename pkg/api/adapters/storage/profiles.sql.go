@@ -12,17 +12,65 @@ import (
 	"github.com/eser/acik.io/pkg/api/business/profiles"
 )
 
+const countFollowedProfiles = `-- name: CountFollowedProfiles :one
+SELECT COUNT(*) AS "count"
+FROM "profile_follow" pf
+  INNER JOIN "profile" p ON p.id = pf.profile_id AND p.deleted_at IS NULL
+WHERE pf.user_id = $1
+`
+
+// CountFollowedProfiles
+//
+//	SELECT COUNT(*) AS "count"
+//	FROM "profile_follow" pf
+//	  INNER JOIN "profile" p ON p.id = pf.profile_id AND p.deleted_at IS NULL
+//	WHERE pf.user_id = $1
+func (q *Queries) CountFollowedProfiles(ctx context.Context, userId string) (int64, error) {
+	row := q.db.QueryRowContext(ctx, countFollowedProfiles, userId)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const countProfileFollowers = `-- name: CountProfileFollowers :one
+SELECT COUNT(*) AS "count"
+FROM "profile_follow" pf
+  INNER JOIN "user" u ON u.id = pf.user_id AND u.deleted_at IS NULL
+WHERE pf.profile_id = $1
+`
+
+// CountProfileFollowers
+//
+//	SELECT COUNT(*) AS "count"
+//	FROM "profile_follow" pf
+//	  INNER JOIN "user" u ON u.id = pf.user_id AND u.deleted_at IS NULL
+//	WHERE pf.profile_id = $1
+func (q *Queries) CountProfileFollowers(ctx context.Context, profileId string) (int64, error) {
+	row := q.db.QueryRowContext(ctx, countProfileFollowers, profileId)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
 const createProfile = `-- name: CreateProfile :one
-INSERT INTO "profile" (id, slug)
-VALUES ($1, $2) RETURNING id, kind, slug, profile_picture_uri, title, description, show_stories, show_projects, created_at, updated_at, deleted_at
+INSERT INTO "profile" (id, kind, slug, title, description)
+VALUES ($1, $2, $3, $4, $5)
+RETURNING id, kind, slug, profile_picture_uri, title, description, show_stories, show_projects, created_at, updated_at, deleted_at
 `
 
 // CreateProfile
 //
-//	INSERT INTO "profile" (id, slug)
-//	VALUES ($1, $2) RETURNING id, kind, slug, profile_picture_uri, title, description, show_stories, show_projects, created_at, updated_at, deleted_at
+//	INSERT INTO "profile" (id, kind, slug, title, description)
+//	VALUES ($1, $2, $3, $4, $5)
+//	RETURNING id, kind, slug, profile_picture_uri, title, description, show_stories, show_projects, created_at, updated_at, deleted_at
 func (q *Queries) CreateProfile(ctx context.Context, arg profiles.CreateProfileParams) (*profiles.Profile, error) {
-	row := q.db.QueryRowContext(ctx, createProfile, arg.Id, arg.Slug)
+	row := q.db.QueryRowContext(ctx, createProfile,
+		arg.Id,
+		arg.Kind,
+		arg.Slug,
+		arg.Title,
+		arg.Description,
+	)
 	var i profiles.Profile
 	err := row.Scan(
 		&i.Id,
@@ -43,6 +91,50 @@ func (q *Queries) CreateProfile(ctx context.Context, arg profiles.CreateProfileP
 	return &i, err
 }
 
+const createProfileMembership = `-- name: CreateProfileMembership :one
+INSERT INTO "profile_membership" (id, kind, profile_id, user_id)
+VALUES ($1, $2, $3, $4)
+ON CONFLICT (profile_id, user_id) DO UPDATE
+SET kind = EXCLUDED.kind,
+  updated_at = NOW(),
+  deleted_at = NULL
+WHERE "profile_membership".deleted_at IS NOT NULL
+RETURNING id, kind, profile_id, user_id, created_at, updated_at, deleted_at
+`
+
+// CreateProfileMembership
+//
+//	INSERT INTO "profile_membership" (id, kind, profile_id, user_id)
+//	VALUES ($1, $2, $3, $4)
+//	ON CONFLICT (profile_id, user_id) DO UPDATE
+//	SET kind = EXCLUDED.kind,
+//	  updated_at = NOW(),
+//	  deleted_at = NULL
+//	WHERE "profile_membership".deleted_at IS NOT NULL
+//	RETURNING id, kind, profile_id, user_id, created_at, updated_at, deleted_at
+func (q *Queries) CreateProfileMembership(ctx context.Context, arg profiles.CreateProfileMembershipParams) (*profiles.ProfileMembership, error) {
+	row := q.db.QueryRowContext(ctx, createProfileMembership,
+		arg.Id,
+		arg.Kind,
+		arg.ProfileId,
+		arg.UserId,
+	)
+	var i profiles.ProfileMembership
+	err := row.Scan(
+		&i.Id,
+		&i.Kind,
+		&i.ProfileId,
+		&i.UserId,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.DeletedAt,
+	)
+	if err != nil && errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	return &i, err
+}
+
 const deleteProfile = `-- name: DeleteProfile :execrows
 UPDATE "profile"
 SET deleted_at = NOW()
@@ -62,6 +154,25 @@ func (q *Queries) DeleteProfile(ctx context.Context, id string) (int64, error) {
 	return result.RowsAffected()
 }
 
+const followProfile = `-- name: FollowProfile :execrows
+INSERT INTO "profile_follow" (id, user_id, profile_id)
+VALUES ($1, $2, $3)
+ON CONFLICT (user_id, profile_id) DO NOTHING
+`
+
+// FollowProfile
+//
+//	INSERT INTO "profile_follow" (id, user_id, profile_id)
+//	VALUES ($1, $2, $3)
+//	ON CONFLICT (user_id, profile_id) DO NOTHING
+func (q *Queries) FollowProfile(ctx context.Context, arg profiles.FollowProfileParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, followProfile, arg.Id, arg.UserId, arg.ProfileId)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
 const getProfileById = `-- name: GetProfileById :one
 SELECT id, kind, slug, profile_picture_uri, title, description, show_stories, show_projects, created_at, updated_at, deleted_at FROM "profile"
 WHERE id = $1
@@ -128,15 +239,320 @@ func (q *Queries) GetProfileBySlug(ctx context.Context, slug string) (*profiles.
 	return &i, err
 }
 
+const isFollowingProfile = `-- name: IsFollowingProfile :one
+SELECT EXISTS (
+  SELECT 1 FROM "profile_follow" pf
+  WHERE pf.user_id = $1
+    AND pf.profile_id = $2
+) AS "exists"
+`
+
+// IsFollowingProfile
+//
+//	SELECT EXISTS (
+//	  SELECT 1 FROM "profile_follow" pf
+//	  WHERE pf.user_id = $1
+//	    AND pf.profile_id = $2
+//	) AS "exists"
+func (q *Queries) IsFollowingProfile(ctx context.Context, arg profiles.IsFollowingProfileParams) (bool, error) {
+	row := q.db.QueryRowContext(ctx, isFollowingProfile, arg.UserId, arg.ProfileId)
+	var exists bool
+	err := row.Scan(&exists)
+	return exists, err
+}
+
+const isProfileAdmin = `-- name: IsProfileAdmin :one
+SELECT EXISTS (
+  SELECT 1 FROM "user" u
+  WHERE u.id = $1
+    AND u.individual_profile_id = $2
+  UNION ALL
+  SELECT 1 FROM "profile_membership" pm
+  WHERE pm.user_id = $1
+    AND pm.profile_id = $2
+    AND pm.kind IN ('owner', 'admin')
+    AND pm.deleted_at IS NULL
+) AS "exists"
+`
+
+// IsProfileAdmin
+//
+//	SELECT EXISTS (
+//	  SELECT 1 FROM "user" u
+//	  WHERE u.id = $1
+//	    AND u.individual_profile_id = $2
+//	  UNION ALL
+//	  SELECT 1 FROM "profile_membership" pm
+//	  WHERE pm.user_id = $1
+//	    AND pm.profile_id = $2
+//	    AND pm.kind IN ('owner', 'admin')
+//	    AND pm.deleted_at IS NULL
+//	) AS "exists"
+func (q *Queries) IsProfileAdmin(ctx context.Context, arg profiles.IsProfileAdminParams) (bool, error) {
+	row := q.db.QueryRowContext(ctx, isProfileAdmin, arg.UserId, arg.ProfileId)
+	var exists bool
+	err := row.Scan(&exists)
+	return exists, err
+}
+
+const isProfileMember = `-- name: IsProfileMember :one
+SELECT EXISTS (
+  SELECT 1 FROM "user" u
+  WHERE u.id = $1
+    AND u.individual_profile_id = $2
+  UNION ALL
+  SELECT 1 FROM "profile_membership" pm
+  WHERE pm.user_id = $1
+    AND pm.profile_id = $2
+    AND pm.deleted_at IS NULL
+) AS "exists"
+`
+
+// IsProfileMember
+//
+//	SELECT EXISTS (
+//	  SELECT 1 FROM "user" u
+//	  WHERE u.id = $1
+//	    AND u.individual_profile_id = $2
+//	  UNION ALL
+//	  SELECT 1 FROM "profile_membership" pm
+//	  WHERE pm.user_id = $1
+//	    AND pm.profile_id = $2
+//	    AND pm.deleted_at IS NULL
+//	) AS "exists"
+func (q *Queries) IsProfileMember(ctx context.Context, arg profiles.IsProfileMemberParams) (bool, error) {
+	row := q.db.QueryRowContext(ctx, isProfileMember, arg.UserId, arg.ProfileId)
+	var exists bool
+	err := row.Scan(&exists)
+	return exists, err
+}
+
+const isUserActive = `-- name: IsUserActive :one
+SELECT EXISTS (
+  SELECT 1 FROM "user"
+  WHERE id = $1
+    AND deleted_at IS NULL
+    AND suspended_at IS NULL
+) AS is_active
+`
+
+// IsUserActive
+//
+//	SELECT EXISTS (
+//	  SELECT 1 FROM "user"
+//	  WHERE id = $1
+//	    AND deleted_at IS NULL
+//	    AND suspended_at IS NULL
+//	) AS is_active
+func (q *Queries) IsUserActive(ctx context.Context, id string) (bool, error) {
+	row := q.db.QueryRowContext(ctx, isUserActive, id)
+	var is_active bool
+	err := row.Scan(&is_active)
+	return is_active, err
+}
+
+const listFollowedProfiles = `-- name: ListFollowedProfiles :many
+SELECT p.id, p.kind, p.slug, p.profile_picture_uri, p.title, p.description, pf.created_at AS followed_at
+FROM "profile_follow" pf
+  INNER JOIN "profile" p ON p.id = pf.profile_id AND p.deleted_at IS NULL
+WHERE pf.user_id = $1
+ORDER BY pf.created_at DESC, pf.id DESC
+LIMIT $2
+OFFSET $3
+`
+
+// ListFollowedProfiles
+//
+//	SELECT p.id, p.kind, p.slug, p.profile_picture_uri, p.title, p.description, pf.created_at AS followed_at
+//	FROM "profile_follow" pf
+//	  INNER JOIN "profile" p ON p.id = pf.profile_id AND p.deleted_at IS NULL
+//	WHERE pf.user_id = $1
+//	ORDER BY pf.created_at DESC, pf.id DESC
+//	LIMIT $2
+//	OFFSET $3
+func (q *Queries) ListFollowedProfiles(ctx context.Context, arg profiles.ListFollowedProfilesParams) ([]*profiles.ListFollowedProfilesRow, error) {
+	rows, err := q.db.QueryContext(ctx, listFollowedProfiles, arg.UserId, arg.LimitCount, arg.OffsetCount)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []*profiles.ListFollowedProfilesRow{}
+	for rows.Next() {
+		var i profiles.ListFollowedProfilesRow
+		if err := rows.Scan(
+			&i.Id,
+			&i.Kind,
+			&i.Slug,
+			&i.ProfilePictureUri,
+			&i.Title,
+			&i.Description,
+			&i.FollowedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, &i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listNewestProfiles = `-- name: ListNewestProfiles :many
+SELECT id, kind, slug, profile_picture_uri, title, description, show_stories, show_projects, created_at, updated_at, deleted_at FROM "profile"
+WHERE deleted_at IS NULL
+ORDER BY created_at DESC
+LIMIT $1
+`
+
+// ListNewestProfiles
+//
+//	SELECT id, kind, slug, profile_picture_uri, title, description, show_stories, show_projects, created_at, updated_at, deleted_at FROM "profile"
+//	WHERE deleted_at IS NULL
+//	ORDER BY created_at DESC
+//	LIMIT $1
+func (q *Queries) ListNewestProfiles(ctx context.Context, limitCount int32) ([]*profiles.Profile, error) {
+	rows, err := q.db.QueryContext(ctx, listNewestProfiles, limitCount)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []*profiles.Profile{}
+	for rows.Next() {
+		var i profiles.Profile
+		if err := rows.Scan(
+			&i.Id,
+			&i.Kind,
+			&i.Slug,
+			&i.ProfilePictureUri,
+			&i.Title,
+			&i.Description,
+			&i.ShowStories,
+			&i.ShowProjects,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.DeletedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, &i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listProfileFollowers = `-- name: ListProfileFollowers :many
+SELECT u.id AS user_id, u.name, p.slug AS profile_slug, p.title AS profile_title,
+  p.profile_picture_uri, pf.created_at AS followed_at
+FROM "profile_follow" pf
+  INNER JOIN "user" u ON u.id = pf.user_id AND u.deleted_at IS NULL
+  LEFT JOIN "profile" p ON p.id = u.individual_profile_id AND p.deleted_at IS NULL
+WHERE pf.profile_id = $1
+ORDER BY pf.created_at DESC, pf.id DESC
+LIMIT $2
+OFFSET $3
+`
+
+// ListProfileFollowers
+//
+//	SELECT u.id AS user_id, u.name, p.slug AS profile_slug, p.title AS profile_title,
+//	  p.profile_picture_uri, pf.created_at AS followed_at
+//	FROM "profile_follow" pf
+//	  INNER JOIN "user" u ON u.id = pf.user_id AND u.deleted_at IS NULL
+//	  LEFT JOIN "profile" p ON p.id = u.individual_profile_id AND p.deleted_at IS NULL
+//	WHERE pf.profile_id = $1
+//	ORDER BY pf.created_at DESC, pf.id DESC
+//	LIMIT $2
+//	OFFSET $3
+func (q *Queries) ListProfileFollowers(ctx context.Context, arg profiles.ListProfileFollowersParams) ([]*profiles.ListProfileFollowersRow, error) {
+	rows, err := q.db.QueryContext(ctx, listProfileFollowers, arg.ProfileId, arg.LimitCount, arg.OffsetCount)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []*profiles.ListProfileFollowersRow{}
+	for rows.Next() {
+		var i profiles.ListProfileFollowersRow
+		if err := rows.Scan(
+			&i.UserId,
+			&i.Name,
+			&i.ProfileSlug,
+			&i.ProfileTitle,
+			&i.ProfilePictureUri,
+			&i.FollowedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, &i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const listProfiles = `-- name: ListProfiles :many
 SELECT id, kind, slug, profile_picture_uri, title, description, show_stories, show_projects, created_at, updated_at, deleted_at FROM "profile"
+WHERE $1::TEXT IS NULL
+  OR EXISTS (
+    SELECT 1 FROM "story" s
+      INNER JOIN "story_tag" st ON st.story_id = s.id
+    WHERE s.author_profile_id = "profile".id
+      AND s.status = 'published'
+      AND s.published_at <= NOW()
+      AND s.deleted_at IS NULL
+      AND st.tag_id = $1
+  )
+  OR EXISTS (
+    SELECT 1 FROM "event_attendance" ea
+      INNER JOIN "event" e ON e.id = ea.event_id
+      INNER JOIN "event_tag" et ON et.event_id = e.id
+    WHERE ea.profile_id = "profile".id
+      AND ea.kind = 'organizer'
+      AND ea.deleted_at IS NULL
+      AND e.status = 'published'
+      AND e.deleted_at IS NULL
+      AND et.tag_id = $1
+  )
 `
 
 // ListProfiles
 //
 //	SELECT id, kind, slug, profile_picture_uri, title, description, show_stories, show_projects, created_at, updated_at, deleted_at FROM "profile"
-func (q *Queries) ListProfiles(ctx context.Context) ([]*profiles.Profile, error) {
-	rows, err := q.db.QueryContext(ctx, listProfiles)
+//	WHERE $1::TEXT IS NULL
+//	  OR EXISTS (
+//	    SELECT 1 FROM "story" s
+//	      INNER JOIN "story_tag" st ON st.story_id = s.id
+//	    WHERE s.author_profile_id = "profile".id
+//	      AND s.status = 'published'
+//	      AND s.published_at <= NOW()
+//	      AND s.deleted_at IS NULL
+//	      AND st.tag_id = $1
+//	  )
+//	  OR EXISTS (
+//	    SELECT 1 FROM "event_attendance" ea
+//	      INNER JOIN "event" e ON e.id = ea.event_id
+//	      INNER JOIN "event_tag" et ON et.event_id = e.id
+//	    WHERE ea.profile_id = "profile".id
+//	      AND ea.kind = 'organizer'
+//	      AND ea.deleted_at IS NULL
+//	      AND e.status = 'published'
+//	      AND e.deleted_at IS NULL
+//	      AND et.tag_id = $1
+//	  )
+func (q *Queries) ListProfiles(ctx context.Context, tagId sql.NullString) ([]*profiles.Profile, error) {
+	rows, err := q.db.QueryContext(ctx, listProfiles, tagId)
 	if err != nil {
 		return nil, err
 	}
@@ -170,6 +586,48 @@ func (q *Queries) ListProfiles(ctx context.Context) ([]*profiles.Profile, error)
 	return items, nil
 }
 
+const setProfilePictureUri = `-- name: SetProfilePictureUri :execrows
+UPDATE "profile"
+SET profile_picture_uri = $1,
+  updated_at = NOW()
+WHERE id = $2
+  AND deleted_at IS NULL
+`
+
+// SetProfilePictureUri
+//
+//	UPDATE "profile"
+//	SET profile_picture_uri = $1,
+//	  updated_at = NOW()
+//	WHERE id = $2
+//	  AND deleted_at IS NULL
+func (q *Queries) SetProfilePictureUri(ctx context.Context, arg profiles.SetProfilePictureUriParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, setProfilePictureUri, arg.ProfilePictureUri, arg.Id)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const unfollowProfile = `-- name: UnfollowProfile :execrows
+DELETE FROM "profile_follow"
+WHERE user_id = $1
+  AND profile_id = $2
+`
+
+// UnfollowProfile
+//
+//	DELETE FROM "profile_follow"
+//	WHERE user_id = $1
+//	  AND profile_id = $2
+func (q *Queries) UnfollowProfile(ctx context.Context, arg profiles.UnfollowProfileParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, unfollowProfile, arg.UserId, arg.ProfileId)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
 const updateProfile = `-- name: UpdateProfile :execrows
 UPDATE "profile"
 SET slug = $2
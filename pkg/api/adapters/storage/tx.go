@@ -0,0 +1,87 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/eser/ajan/datafx"
+)
+
+var (
+	ErrTransactionsNotSupported = errors.New("transactions are not supported by the connection")
+	ErrFailedToCommit           = errors.New("failed to commit transaction")
+)
+
+// unitOfWorkDB runs the queries inside the transaction of the unit of work
+// carried by the context, if there is one, and on the connection otherwise.
+// This lets business services group their repository calls into a single
+// transaction without knowing about it.
+type unitOfWorkDB struct {
+	connection datafx.SqlExecutor
+}
+
+func (db *unitOfWorkDB) executor(ctx context.Context) DBTX { //nolint:ireturn
+	uow, hasUow := ctx.Value(datafx.ContextKeyUnitOfWork).(*datafx.UnitOfWork)
+	if hasUow {
+		if tx, isTx := uow.TxScope().(*sql.Tx); isTx {
+			return tx
+		}
+	}
+
+	return db.connection
+}
+
+func (db *unitOfWorkDB) ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error) {
+	return db.executor(ctx).ExecContext(ctx, query, args...) //nolint:wrapcheck
+}
+
+func (db *unitOfWorkDB) PrepareContext(ctx context.Context, query string) (*sql.Stmt, error) {
+	return db.executor(ctx).PrepareContext(ctx, query) //nolint:wrapcheck
+}
+
+func (db *unitOfWorkDB) QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error) {
+	return db.executor(ctx).QueryContext(ctx, query, args...) //nolint:wrapcheck
+}
+
+func (db *unitOfWorkDB) QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row {
+	return db.executor(ctx).QueryRowContext(ctx, query, args...)
+}
+
+// Transact runs fn within a transaction, committing it if fn succeeds and
+// rolling it back otherwise. Calls nested into an ongoing transaction join it.
+func (q *Queries) Transact(ctx context.Context, fn func(ctx context.Context) error) error {
+	if _, inTx := ctx.Value(datafx.ContextKeyUnitOfWork).(*datafx.UnitOfWork); inTx {
+		return fn(ctx)
+	}
+
+	db, isUowDB := q.db.(*unitOfWorkDB)
+	if !isUowDB {
+		return ErrTransactionsNotSupported
+	}
+
+	starter, isStarter := db.connection.(datafx.TransactionStarter)
+	if !isStarter {
+		return ErrTransactionsNotSupported
+	}
+
+	uow, err := datafx.NewUnitOfWork(ctx, starter)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrTransactionsNotSupported, err)
+	}
+
+	defer uow.Close() //nolint:errcheck
+
+	err = fn(uow.Context())
+	if err != nil {
+		return err
+	}
+
+	err = uow.Commit()
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrFailedToCommit, err)
+	}
+
+	return nil
+}
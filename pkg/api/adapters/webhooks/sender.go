@@ -0,0 +1,89 @@
+package webhooks
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/eser/acik.io/pkg/api/adapters/netguard"
+	"github.com/eser/acik.io/pkg/api/business/webhooks"
+)
+
+const dialTimeout = 5 * time.Second
+
+var (
+	ErrFailedToBuildRequest = errors.New("failed to build request")
+	ErrFailedToSend         = errors.New("failed to send request")
+)
+
+// Sender posts webhook deliveries over HTTP. Unless private networks are
+// allowed, it refuses to connect to addresses that are not publicly routable;
+// the check runs on the resolved address of every connection, so host names
+// resolving to internal services are caught as well. Redirects are not
+// followed, as they could lead anywhere.
+type Sender struct {
+	client *http.Client
+}
+
+func NewSender(config *webhooks.Config) *Sender {
+	dialer := &net.Dialer{ //nolint:exhaustruct
+		Timeout: dialTimeout,
+	}
+
+	if !config.AllowPrivateNetworks {
+		dialer.Control = netguard.Control
+	}
+
+	transport := &http.Transport{ //nolint:exhaustruct
+		DialContext:         dialer.DialContext,
+		Proxy:               nil,
+		TLSHandshakeTimeout: dialTimeout,
+		MaxIdleConnsPerHost: 2, //nolint:mnd
+	}
+
+	return &Sender{
+		client: &http.Client{ //nolint:exhaustruct
+			Transport: transport,
+			Timeout:   config.RequestTimeout,
+			CheckRedirect: func(*http.Request, []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		},
+	}
+}
+
+func (s *Sender) Send(ctx context.Context, request *webhooks.OutgoingRequest) (*webhooks.SendResult, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, request.Url, bytes.NewReader(request.Body))
+	if err != nil {
+		return nil, fmt.Errorf("%w(url: %s): %w", ErrFailedToBuildRequest, request.Url, err)
+	}
+
+	for name, value := range request.Headers {
+		req.Header.Set(name, value)
+	}
+
+	startedAt := time.Now()
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("%w(url: %s): %w", ErrFailedToSend, request.Url, err)
+	}
+
+	defer resp.Body.Close()
+
+	// only the head of the body is kept, the rest is drained so the
+	// connection can be reused.
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, webhooks.MaxResponseBodyLength))
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, webhooks.MaxResponseBodyLength*8)) //nolint:mnd
+
+	return &webhooks.SendResult{
+		Body:       string(body),
+		StatusCode: resp.StatusCode,
+		Duration:   time.Since(startedAt),
+	}, nil
+}
@@ -0,0 +1,61 @@
+package webhooks_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/eser/acik.io/pkg/api/adapters/netguard"
+	adapterwebhooks "github.com/eser/acik.io/pkg/api/adapters/webhooks"
+	"github.com/eser/acik.io/pkg/api/business/webhooks"
+)
+
+func TestSenderRefusesPrivateAddresses(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	t.Cleanup(server.Close)
+
+	sender := adapterwebhooks.NewSender(&webhooks.Config{RequestTimeout: 5 * time.Second}) //nolint:exhaustruct
+
+	_, err := sender.Send(context.Background(), &webhooks.OutgoingRequest{
+		Headers: map[string]string{},
+		Url:     server.URL,
+		Body:    []byte("{}"),
+	})
+	if !errors.Is(err, netguard.ErrPrivateAddress) {
+		t.Errorf("got %v, want %v", err, netguard.ErrPrivateAddress)
+	}
+}
+
+func TestSenderDoesNotFollowRedirects(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		http.Redirect(w, req, "http://169.254.169.254/latest/meta-data", http.StatusFound)
+	}))
+	t.Cleanup(server.Close)
+
+	sender := adapterwebhooks.NewSender(&webhooks.Config{ //nolint:exhaustruct
+		RequestTimeout:       5 * time.Second,
+		AllowPrivateNetworks: true,
+	})
+
+	result, err := sender.Send(context.Background(), &webhooks.OutgoingRequest{
+		Headers: map[string]string{},
+		Url:     server.URL,
+		Body:    []byte("{}"),
+	})
+	if err != nil {
+		t.Fatalf("sending: %v", err)
+	}
+
+	if result.StatusCode != http.StatusFound || result.IsSuccess() {
+		t.Errorf("got status %d, want the redirect itself", result.StatusCode)
+	}
+}
@@ -0,0 +1,46 @@
+package worker
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var ErrInvalidConcurrency = errors.New("invalid concurrency")
+
+type Config struct {
+	Concurrency       string        `conf:"CONCURRENCY"`                      // per queue overrides of the number of concurrent jobs, as "queue=n,queue=n"
+	MetricsAddr       string        `conf:"METRICS_ADDR" default:":9091"`     // address of the prometheus endpoint, empty to disable it
+	DrainTimeout      time.Duration `conf:"DRAIN_TIMEOUT" default:"30s"`      // how long running jobs may take to finish after a shutdown signal
+	RetryBaseDelay    time.Duration `conf:"RETRY_BASE_DELAY" default:"1s"`    // delay before the first retry of a failed job, doubled on every further attempt
+	SessionPendingTtl time.Duration `conf:"SESSION_PENDING_TTL" default:"1h"` // age after which a session that never logged in is swept
+	RecurringHorizon  time.Duration `conf:"RECURRING_HORIZON" default:"720h"` // how far ahead recurring events are materialized
+}
+
+// ParseConcurrency parses the per queue concurrency overrides.
+func ParseConcurrency(value string) (map[string]int, error) {
+	result := map[string]int{}
+
+	for entry := range strings.SplitSeq(value, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		queue, count, found := strings.Cut(entry, "=")
+		if !found {
+			return nil, fmt.Errorf("%w: %q is not in queue=n form", ErrInvalidConcurrency, entry)
+		}
+
+		n, err := strconv.Atoi(strings.TrimSpace(count))
+		if err != nil || n < 1 {
+			return nil, fmt.Errorf("%w: %q must be a positive number", ErrInvalidConcurrency, entry)
+		}
+
+		result[strings.TrimSpace(queue)] = n
+	}
+
+	return result, nil
+}
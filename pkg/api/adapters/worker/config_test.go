@@ -0,0 +1,41 @@
+package worker_test
+
+import (
+	"errors"
+	"maps"
+	"testing"
+
+	"github.com/eser/acik.io/pkg/api/adapters/worker"
+)
+
+func TestParseConcurrency(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name  string
+		value string
+		want  map[string]int
+		err   error
+	}{
+		{name: "empty", value: "", want: map[string]int{}, err: nil},
+		{name: "overrides", value: " mail = 4, ,search.reindex=1", want: map[string]int{"mail": 4, "search.reindex": 1}, err: nil},
+		{name: "missing count", value: "mail", want: nil, err: worker.ErrInvalidConcurrency},
+		{name: "zero", value: "mail=0", want: nil, err: worker.ErrInvalidConcurrency},
+		{name: "not a number", value: "mail=many", want: nil, err: worker.ErrInvalidConcurrency},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := worker.ParseConcurrency(test.value)
+			if !errors.Is(err, test.err) {
+				t.Fatalf("got error %v, want %v", err, test.err)
+			}
+
+			if !maps.Equal(got, test.want) {
+				t.Errorf("got %v, want %v", got, test.want)
+			}
+		})
+	}
+}
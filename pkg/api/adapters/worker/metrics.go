@@ -0,0 +1,57 @@
+package worker
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const (
+	OutcomeSucceeded  = "succeeded"
+	OutcomeRetried    = "retried"
+	OutcomeDeadLetter = "dead_letter"
+	OutcomeRequeued   = "requeued"
+)
+
+type MetricsProvider interface {
+	GetRegistry() *prometheus.Registry
+}
+
+type Metrics struct {
+	JobsTotal    *prometheus.CounterVec
+	JobDuration  *prometheus.HistogramVec
+	JobsInFlight *prometheus.GaugeVec
+}
+
+func NewMetrics(mp MetricsProvider) *Metrics { //nolint:varnamelen
+	jobsTotal := prometheus.NewCounterVec(
+		prometheus.CounterOpts{ //nolint:exhaustruct
+			Name: "worker_jobs_total",
+			Help: "Total number of job attempts by outcome",
+		},
+		[]string{"queue", "outcome"},
+	)
+
+	jobDuration := prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{ //nolint:exhaustruct
+			Name:    "worker_job_duration_seconds",
+			Help:    "Duration of job attempts",
+			Buckets: prometheus.DefBuckets,
+		},
+		[]string{"queue"},
+	)
+
+	jobsInFlight := prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{ //nolint:exhaustruct
+			Name: "worker_jobs_in_flight",
+			Help: "Number of jobs being processed",
+		},
+		[]string{"queue"},
+	)
+
+	mp.GetRegistry().MustRegister(jobsTotal, jobDuration, jobsInFlight)
+
+	return &Metrics{
+		JobsTotal:    jobsTotal,
+		JobDuration:  jobDuration,
+		JobsInFlight: jobsInFlight,
+	}
+}
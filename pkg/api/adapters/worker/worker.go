@@ -0,0 +1,338 @@
+package worker
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/eser/ajan/logfx"
+	"github.com/eser/ajan/queuefx"
+)
+
+const (
+	DeadLetterSuffix   = ".dead-letter"
+	DefaultConcurrency = 1
+	MaxAttempts        = 5
+)
+
+var (
+	ErrBrokerNotFound         = errors.New("broker not found")
+	ErrConsumingNotSupported  = errors.New("broker does not support consuming")
+	ErrFailedToDeclareQueue   = errors.New("failed to declare queue")
+	ErrFailedToDecodeJob      = errors.New("failed to decode job")
+	ErrFailedToDeadLetter     = errors.New("failed to move job to the dead-letter queue")
+	ErrHandlerPanicked        = errors.New("job handler panicked")
+	ErrPermanent              = errors.New("permanent failure")
+	ErrQueueAlreadyRegistered = errors.New("queue is already registered")
+)
+
+// Handler processes the body of a single job. Returned errors are retried,
+// unless they wrap ErrPermanent.
+type Handler func(ctx context.Context, body []byte) error
+
+// Typed adapts a handler of a JSON encoded job payload. Payloads that can't be
+// decoded never succeed, so they go straight to the dead-letter queue.
+func Typed[T any](fn func(ctx context.Context, job *T) error) Handler {
+	return func(ctx context.Context, body []byte) error {
+		var job T
+
+		err := json.Unmarshal(body, &job)
+		if err != nil {
+			return Permanent(fmt.Errorf("%w: %w", ErrFailedToDecodeJob, err))
+		}
+
+		return fn(ctx, &job)
+	}
+}
+
+// Permanent marks an error as not worth retrying.
+func Permanent(err error) error {
+	return fmt.Errorf("%w: %w", ErrPermanent, err)
+}
+
+// Consumer is implemented by the queuefx brokers that can consume messages.
+type Consumer interface {
+	Consume(
+		ctx context.Context,
+		queueName string,
+		config queuefx.ConsumerConfig,
+	) (<-chan queuefx.Message, <-chan error)
+}
+
+// DeadLetterPublisher moves jobs that exhausted their attempts aside.
+type DeadLetterPublisher interface {
+	Enqueue(ctx context.Context, queueName string, payload any) error
+}
+
+// DeadLetter is published to the dead-letter queue of a queue, once a job of
+// it has failed permanently. Payload holds the original job when it is valid
+// JSON, RawPayload otherwise.
+type DeadLetter struct {
+	FailedAt   time.Time       `json:"failedAt"`
+	Queue      string          `json:"queue"`
+	Error      string          `json:"error"`
+	Payload    json.RawMessage `json:"payload,omitempty"`
+	RawPayload string          `json:"rawPayload,omitempty"`
+	Attempts   int             `json:"attempts"`
+}
+
+type registration struct {
+	handler     Handler
+	queue       string
+	concurrency int
+}
+
+type Worker struct {
+	config        *Config
+	broker        queuefx.Broker
+	deadLetters   DeadLetterPublisher
+	logger        *logfx.Logger
+	metrics       *Metrics
+	registrations []*registration
+}
+
+func New(
+	config *Config,
+	broker queuefx.Broker,
+	deadLetters DeadLetterPublisher,
+	logger *logfx.Logger,
+	metrics *Metrics,
+) *Worker {
+	return &Worker{
+		config:        config,
+		broker:        broker,
+		deadLetters:   deadLetters,
+		logger:        logger,
+		metrics:       metrics,
+		registrations: nil,
+	}
+}
+
+// Register adds the handler of a queue. The concurrency is the number of jobs
+// of the queue processed at once, unless overridden by the configuration.
+func (w *Worker) Register(queue string, concurrency int, handler Handler) error {
+	for _, existing := range w.registrations {
+		if existing.queue == queue {
+			return fmt.Errorf("%w: %s", ErrQueueAlreadyRegistered, queue)
+		}
+	}
+
+	w.registrations = append(w.registrations, &registration{
+		handler:     handler,
+		queue:       queue,
+		concurrency: concurrency,
+	})
+
+	return nil
+}
+
+// Run consumes the registered queues until the context is cancelled. Jobs
+// that are running by then get DrainTimeout to finish, the ones that were not
+// picked up yet stay on the queue.
+func (w *Worker) Run(ctx context.Context) error {
+	if w.broker == nil {
+		return ErrBrokerNotFound
+	}
+
+	consumer, ok := w.broker.(Consumer)
+	if !ok {
+		return fmt.Errorf("%w(dialect: %s)", ErrConsumingNotSupported, w.broker.GetDialect())
+	}
+
+	overrides, err := ParseConcurrency(w.config.Concurrency)
+	if err != nil {
+		return err
+	}
+
+	for _, reg := range w.registrations {
+		for _, name := range []string{reg.queue, reg.queue + DeadLetterSuffix} {
+			_, err := w.broker.QueueDeclare(ctx, name)
+			if err != nil {
+				return fmt.Errorf("%w(queue: %s): %w", ErrFailedToDeclareQueue, name, err)
+			}
+		}
+	}
+
+	// jobs keep running after the shutdown signal, until the drain timeout
+	// cuts them off.
+	jobCtx, cancelJobs := context.WithCancel(context.WithoutCancel(ctx))
+	defer cancelJobs()
+
+	go func() {
+		select {
+		case <-ctx.Done():
+		case <-jobCtx.Done():
+			return
+		}
+
+		timer := time.NewTimer(w.config.DrainTimeout)
+		defer timer.Stop()
+
+		select {
+		case <-timer.C:
+			cancelJobs()
+		case <-jobCtx.Done():
+		}
+	}()
+
+	var wg sync.WaitGroup
+
+	for _, reg := range w.registrations {
+		concurrency := reg.concurrency
+		if override, ok := overrides[reg.queue]; ok {
+			concurrency = override
+		}
+
+		messages, errs := consumer.Consume(ctx, reg.queue, queuefx.DefaultConsumerConfig())
+
+		go func() {
+			for err := range errs {
+				w.logger.ErrorContext(ctx, "Consuming queue failed", slog.String("queue", reg.queue), slog.Any("error", err))
+			}
+		}()
+
+		w.logger.InfoContext(ctx, "Consuming queue", slog.String("queue", reg.queue), slog.Int("concurrency", concurrency))
+
+		for range concurrency {
+			wg.Add(1)
+
+			go func() {
+				defer wg.Done()
+
+				for message := range messages {
+					w.process(ctx, jobCtx, reg, &message) //nolint:contextcheck
+				}
+			}()
+		}
+	}
+
+	wg.Wait()
+
+	return nil
+}
+
+// process runs a job until it succeeds, fails permanently or runs out of
+// attempts. Retries happen in place, so the order of the queue is kept.
+// Shutting down in between attempts puts the job back on the queue.
+func (w *Worker) process(ctx context.Context, jobCtx context.Context, reg *registration, message *queuefx.Message) {
+	inFlight := w.metrics.JobsInFlight.WithLabelValues(reg.queue)
+	inFlight.Inc()
+
+	defer inFlight.Dec()
+
+	var err error
+
+	attempt := 1
+
+	for ; ; attempt++ {
+		started := time.Now()
+		err = w.handle(jobCtx, reg.handler, message.Body)
+
+		w.metrics.JobDuration.WithLabelValues(reg.queue).Observe(time.Since(started).Seconds())
+
+		if err == nil {
+			w.metrics.JobsTotal.WithLabelValues(reg.queue, OutcomeSucceeded).Inc()
+			w.settle(jobCtx, reg.queue, message.Ack())
+
+			return
+		}
+
+		if errors.Is(err, ErrPermanent) || attempt >= MaxAttempts {
+			break
+		}
+
+		w.metrics.JobsTotal.WithLabelValues(reg.queue, OutcomeRetried).Inc()
+		w.logger.WarnContext(
+			jobCtx,
+			"Job failed, retrying",
+			slog.String("queue", reg.queue),
+			slog.Int("attempt", attempt),
+			slog.Any("error", err),
+		)
+
+		if !w.wait(ctx, attempt) {
+			w.metrics.JobsTotal.WithLabelValues(reg.queue, OutcomeRequeued).Inc()
+			w.settle(jobCtx, reg.queue, message.Nack(true))
+
+			return
+		}
+	}
+
+	w.logger.ErrorContext(
+		jobCtx,
+		"Job failed, moving it to the dead-letter queue",
+		slog.String("queue", reg.queue),
+		slog.Int("attempts", attempt),
+		slog.Any("error", err),
+	)
+
+	deadLetterErr := w.deadLetters.Enqueue(jobCtx, reg.queue+DeadLetterSuffix, newDeadLetter(reg.queue, message.Body, err, attempt))
+	if deadLetterErr != nil {
+		w.logger.ErrorContext(
+			jobCtx,
+			ErrFailedToDeadLetter.Error(),
+			slog.String("queue", reg.queue),
+			slog.Any("error", deadLetterErr),
+		)
+		w.metrics.JobsTotal.WithLabelValues(reg.queue, OutcomeRequeued).Inc()
+		w.settle(jobCtx, reg.queue, message.Nack(true))
+
+		return
+	}
+
+	w.metrics.JobsTotal.WithLabelValues(reg.queue, OutcomeDeadLetter).Inc()
+	w.settle(jobCtx, reg.queue, message.Ack())
+}
+
+func (w *Worker) handle(ctx context.Context, handler Handler, body []byte) (err error) {
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			err = Permanent(fmt.Errorf("%w: %v", ErrHandlerPanicked, recovered))
+		}
+	}()
+
+	return handler(ctx, body)
+}
+
+// wait sleeps before the next attempt. It returns false when the worker is
+// shutting down instead.
+func (w *Worker) wait(ctx context.Context, attempt int) bool {
+	timer := time.NewTimer(w.config.RetryBaseDelay << (attempt - 1))
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+func (w *Worker) settle(ctx context.Context, queue string, err error) {
+	if err != nil {
+		w.logger.ErrorContext(ctx, "Acknowledging job failed", slog.String("queue", queue), slog.Any("error", err))
+	}
+}
+
+func newDeadLetter(queue string, body []byte, err error, attempts int) *DeadLetter {
+	deadLetter := &DeadLetter{
+		FailedAt:   time.Now(),
+		Queue:      queue,
+		Error:      err.Error(),
+		Payload:    nil,
+		RawPayload: "",
+		Attempts:   attempts,
+	}
+
+	if json.Valid(body) {
+		deadLetter.Payload = body
+	} else {
+		deadLetter.RawPayload = string(body)
+	}
+
+	return deadLetter
+}
@@ -0,0 +1,66 @@
+package audit
+
+import "context"
+
+// EventRecorder records domain events, see outbox.Service.
+type EventRecorder interface {
+	Record(ctx context.Context, aggregateType string, aggregateId string, eventType string, payload any) error
+}
+
+// ProfileScoped is implemented by event payloads of entities that belong to a
+// profile.
+type ProfileScoped interface {
+	AuditProfileId() string
+}
+
+// Snapshotted is implemented by event payloads that carry the states of
+// their entity before and after the change, loaded by the service making it.
+// Payloads that don't are logged as they are, as the state after the change.
+type Snapshotted interface {
+	AuditStates() (before any, after any)
+}
+
+// Recorder is an EventRecorder that also logs every domain event it records
+// as a change of the event's aggregate. Since domain events are recorded in
+// the transaction of their state change, so are the log entries.
+type Recorder struct {
+	events EventRecorder
+	audit  *Service
+}
+
+func NewRecorder(events EventRecorder, audit *Service) *Recorder {
+	return &Recorder{events: events, audit: audit}
+}
+
+func (r *Recorder) Record(
+	ctx context.Context,
+	aggregateType string,
+	aggregateId string,
+	eventType string,
+	payload any,
+) error {
+	err := r.events.Record(ctx, aggregateType, aggregateId, eventType, payload)
+	if err != nil {
+		return err //nolint:wrapcheck
+	}
+
+	profileId := ""
+	if scoped, ok := payload.(ProfileScoped); ok {
+		profileId = scoped.AuditProfileId()
+	}
+
+	change := &Change{
+		Before:     nil,
+		After:      payload,
+		Action:     eventType,
+		EntityType: aggregateType,
+		EntityId:   aggregateId,
+		ProfileId:  profileId,
+	}
+
+	if snapshotted, ok := payload.(Snapshotted); ok {
+		change.Before, change.After = snapshotted.AuditStates()
+	}
+
+	return r.audit.Record(ctx, change)
+}
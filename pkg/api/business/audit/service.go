@@ -0,0 +1,159 @@
+package audit
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+)
+
+var (
+	ErrFailedToEncode      = errors.New("failed to encode state")
+	ErrFailedToRecord      = errors.New("failed to record audit log")
+	ErrFailedToListRecords = errors.New("failed to list records")
+)
+
+type Repository interface {
+	InsertAuditLog(ctx context.Context, arg InsertAuditLogParams) error
+	ListAuditLogs(ctx context.Context, arg ListAuditLogsParams) ([]*AuditLog, error)
+}
+
+// Service keeps the append-only log of who changed what. Record is meant to
+// be called within the transaction of the change, so a change is never
+// committed without its log entry.
+type Service struct {
+	repo Repository
+
+	idGenerator RecordIDGenerator
+}
+
+func NewService(repo Repository) *Service {
+	return &Service{repo: repo, idGenerator: DefaultIDGenerator}
+}
+
+// Record logs a change, attributed to the actor of the context.
+func (s *Service) Record(ctx context.Context, change *Change) error {
+	before, err := encode(change.Before)
+	if err != nil {
+		return err
+	}
+
+	after, err := encode(change.After)
+	if err != nil {
+		return err
+	}
+
+	return s.insert(ctx, change, before, after)
+}
+
+func (s *Service) List(ctx context.Context, filter *ListFilter) ([]*AuditLog, error) {
+	records, err := s.repo.ListAuditLogs(ctx, ListAuditLogsParams{
+		ActorUserId: nullString(filter.ActorUserId),
+		EntityType:  nullString(filter.EntityType),
+		EntityId:    nullString(filter.EntityId),
+		Action:      nullString(filter.Action),
+		ProfileId:   nullString(filter.ProfileId),
+		Since:       nullTime(filter.Since),
+		Until:       nullTime(filter.Until),
+		LimitCount:  filter.Limit,
+		OffsetCount: filter.Offset,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrFailedToListRecords, err)
+	}
+
+	return records, nil
+}
+
+func (s *Service) insert(ctx context.Context, change *Change, before json.RawMessage, after json.RawMessage) error {
+	diff, err := Diff(before, after)
+	if err != nil {
+		return err
+	}
+
+	actor, hasActor := ActorFrom(ctx)
+	if !hasActor {
+		actor = &Actor{} //nolint:exhaustruct
+	}
+
+	err = s.repo.InsertAuditLog(ctx, InsertAuditLogParams{
+		Id:            string(s.idGenerator()),
+		ActorUserId:   nullString(actor.UserId),
+		SessionId:     nullString(actor.SessionId),
+		Action:        change.Action,
+		EntityType:    change.EntityType,
+		EntityId:      change.EntityId,
+		ProfileId:     nullString(change.ProfileId),
+		Before:        before,
+		After:         after,
+		Diff:          diff,
+		Ip:            nullString(actor.Ip),
+		CorrelationId: nullString(actor.CorrelationId),
+	})
+	if err != nil {
+		return fmt.Errorf("%w(action: %s, entity: %s/%s): %w", ErrFailedToRecord, change.Action, change.EntityType, change.EntityId, err)
+	}
+
+	return nil
+}
+
+// Diff returns the fields that differ between two states, each with its value
+// before and after. States that aren't JSON objects are compared as a whole,
+// under the empty field name.
+func Diff(before json.RawMessage, after json.RawMessage) (json.RawMessage, error) {
+	var beforeFields, afterFields map[string]json.RawMessage
+
+	beforeErr := json.Unmarshal(before, &beforeFields)
+	afterErr := json.Unmarshal(after, &afterFields)
+
+	changes := map[string]*FieldChange{}
+
+	if beforeErr != nil || afterErr != nil {
+		if !bytes.Equal(before, after) {
+			changes[""] = &FieldChange{Before: before, After: after}
+		}
+	} else {
+		for name, value := range beforeFields {
+			if other, ok := afterFields[name]; !ok || !bytes.Equal(value, other) {
+				changes[name] = &FieldChange{Before: value, After: afterFields[name]}
+			}
+		}
+
+		for name, value := range afterFields {
+			if _, ok := beforeFields[name]; !ok {
+				changes[name] = &FieldChange{Before: nil, After: value}
+			}
+		}
+	}
+
+	encoded, err := json.Marshal(changes)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrFailedToEncode, err)
+	}
+
+	return encoded, nil
+}
+
+func encode(state any) (json.RawMessage, error) {
+	encoded, err := json.Marshal(state)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrFailedToEncode, err)
+	}
+
+	return encoded, nil
+}
+
+func nullString(value string) sql.NullString {
+	return sql.NullString{String: value, Valid: value != ""}
+}
+
+func nullTime(value *time.Time) sql.NullTime {
+	if value == nil {
+		return sql.NullTime{} //nolint:exhaustruct
+	}
+
+	return sql.NullTime{Time: *value, Valid: true}
+}
@@ -0,0 +1,113 @@
+package audit_test
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/eser/acik.io/pkg/api/business/audit"
+	"github.com/eser/acik.io/pkg/api/business/outbox/outboxtest"
+)
+
+// repository keeps the inserted log entries.
+type repository struct {
+	inserted []audit.InsertAuditLogParams
+}
+
+func (r *repository) InsertAuditLog(_ context.Context, arg audit.InsertAuditLogParams) error {
+	r.inserted = append(r.inserted, arg)
+
+	return nil
+}
+
+func (r *repository) ListAuditLogs(context.Context, audit.ListAuditLogsParams) ([]*audit.AuditLog, error) {
+	return nil, nil
+}
+
+// renamed is a payload carrying its states and the profile it belongs to.
+type renamed struct {
+	Before map[string]string
+	After  map[string]string
+}
+
+func (e *renamed) AuditStates() (any, any) {
+	return e.Before, e.After
+}
+
+func (e *renamed) AuditProfileId() string {
+	return "profile"
+}
+
+func TestDiff(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		before string
+		after  string
+		want   string
+	}{
+		{name: "unchanged", before: `{"a":1}`, after: `{"a":1}`, want: `{}`},
+		{
+			name:   "fields",
+			before: `{"a":1,"b":2,"c":3}`,
+			after:  `{"a":1,"b":4,"d":5}`,
+			want:   `{"b":{"before":2,"after":4},"c":{"before":3,"after":null},"d":{"before":null,"after":5}}`,
+		},
+		{name: "created", before: `null`, after: `{"a":1}`, want: `{"a":{"before":null,"after":1}}`},
+		{name: "not objects", before: `"x"`, after: `"y"`, want: `{"":{"before":"x","after":"y"}}`},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			diff, err := audit.Diff(json.RawMessage(test.before), json.RawMessage(test.after))
+			if err != nil {
+				t.Fatalf("diffing: %v", err)
+			}
+
+			if string(diff) != test.want {
+				t.Errorf("got %s, want %s", diff, test.want)
+			}
+		})
+	}
+}
+
+func TestRecorder(t *testing.T) {
+	t.Parallel()
+
+	repo := &repository{} //nolint:exhaustruct
+	events := outboxtest.NewRecorder()
+	recorder := audit.NewRecorder(events, audit.NewService(repo))
+
+	ctx := audit.WithActor(context.Background(), &audit.Actor{UserId: "user", SessionId: "", Ip: "192.0.2.1", CorrelationId: "request"})
+	payload := &renamed{Before: map[string]string{"slug": "eser"}, After: map[string]string{"slug": "ozsoy"}}
+
+	err := recorder.Record(ctx, "profile", "1", "profile.renamed", payload)
+	if err != nil {
+		t.Fatalf("recording: %v", err)
+	}
+
+	if len(events.Envelopes("profile.renamed")) != 1 {
+		t.Errorf("got %d events, want the domain event recorded too", len(events.Envelopes("profile.renamed")))
+	}
+
+	if len(repo.inserted) != 1 {
+		t.Fatalf("got %d log entries, want 1", len(repo.inserted))
+	}
+
+	entry := repo.inserted[0]
+
+	if entry.ActorUserId.String != "user" || entry.SessionId.Valid || entry.Ip.String != "192.0.2.1" || entry.ProfileId.String != "profile" {
+		t.Errorf("got entry %+v, want it attributed to the actor and the profile", entry)
+	}
+
+	if string(entry.Before) != `{"slug":"eser"}` || string(entry.After) != `{"slug":"ozsoy"}` {
+		t.Errorf("got %s before and %s after, want the states of the payload", entry.Before, entry.After)
+	}
+
+	if string(entry.Diff) != `{"slug":{"before":"eser","after":"ozsoy"}}` {
+		t.Errorf("got diff %s", entry.Diff)
+	}
+}
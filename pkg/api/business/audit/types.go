@@ -0,0 +1,70 @@
+package audit
+
+import (
+	"context"
+	"time"
+
+	"github.com/oklog/ulid/v2"
+)
+
+type RecordID string
+
+type RecordIDGenerator func() RecordID
+
+func DefaultIDGenerator() RecordID {
+	return RecordID(ulid.Make().String())
+}
+
+// Actor is who a change is made by. Changes made outside of requests, such
+// as by scheduled jobs, have an empty actor.
+type Actor struct {
+	UserId        string
+	SessionId     string
+	Ip            string
+	CorrelationId string
+}
+
+type actorContextKey struct{}
+
+// WithActor returns a context whose changes are attributed to the actor.
+func WithActor(ctx context.Context, actor *Actor) context.Context {
+	return context.WithValue(ctx, actorContextKey{}, actor)
+}
+
+// ActorFrom returns the actor of a context, if there is one.
+func ActorFrom(ctx context.Context) (*Actor, bool) {
+	actor, ok := ctx.Value(actorContextKey{}).(*Actor)
+
+	return actor, ok
+}
+
+// Change describes a mutation to be logged. Before and After are the states
+// of the entity around the change, encoded as JSON; either may be nil when the
+// entity is created or removed. ProfileId is the profile the entity belongs
+// to, if any, so the profile's admins can see its history.
+type Change struct {
+	Before     any
+	After      any
+	Action     string
+	EntityType string
+	EntityId   string
+	ProfileId  string
+}
+
+type ListFilter struct {
+	Since       *time.Time
+	Until       *time.Time
+	ActorUserId string
+	EntityType  string
+	EntityId    string
+	Action      string
+	ProfileId   string
+	Limit       int32
+	Offset      int32
+}
+
+// FieldChange is an entry of the diff of a change.
+type FieldChange struct {
+	Before any `json:"before"`
+	After  any `json:"after"`
+}
@@ -0,0 +1,10 @@
+package digest
+
+import "time"
+
+type Config struct {
+	SiteUrl            string        `conf:"SITE_URL" default:"https://acik.io"`                                 // public address digest links point to
+	UnsubscribeBaseUrl string        `conf:"UNSUBSCRIBE_BASE_URL" default:"https://acik.io/digest/unsubscribe/"` // address the unsubscribe tokens are appended to
+	UnsubscribeSecret  string        `conf:"UNSUBSCRIBE_SECRET"`                                                 // signs the unsubscribe tokens, digests are not sent without it
+	EventHorizon       time.Duration `conf:"EVENT_HORIZON" default:"336h"`                                       // how far ahead upcoming events are listed
+}
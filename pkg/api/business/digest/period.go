@@ -0,0 +1,60 @@
+package digest
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+const (
+	daysPerWeek = 7
+	hoursPerDay = 24
+
+	periodKeyFormat = "%04d-W%02d"
+)
+
+var ErrInvalidPeriod = errors.New("invalid digest period")
+
+// PeriodOf returns the ISO week, in UTC, containing the given time.
+func PeriodOf(t time.Time) *Period {
+	day := t.UTC().Truncate(hoursPerDay * time.Hour)
+	start := day.AddDate(0, 0, -((int(day.Weekday()) + daysPerWeek - 1) % daysPerWeek))
+
+	return newPeriod(start)
+}
+
+// PreviousPeriod returns the last full week before the given time, the one a
+// digest sent at that time covers.
+func PreviousPeriod(t time.Time) *Period {
+	return PeriodOf(PeriodOf(t).Start.AddDate(0, 0, -1))
+}
+
+// ParsePeriod parses an ISO week key like "2026-W42".
+func ParsePeriod(key string) (*Period, error) {
+	var year, week int
+
+	_, err := fmt.Sscanf(key, periodKeyFormat, &year, &week)
+	if err != nil {
+		return nil, fmt.Errorf("%w(period: %s): %w", ErrInvalidPeriod, key, err)
+	}
+
+	// January 4th always falls in the first ISO week of its year.
+	firstWeek := PeriodOf(time.Date(year, time.January, 4, 0, 0, 0, 0, time.UTC)) //nolint:mnd
+	period := newPeriod(firstWeek.Start.AddDate(0, 0, (week-1)*daysPerWeek))
+
+	if period.Key != key {
+		return nil, fmt.Errorf("%w(period: %s)", ErrInvalidPeriod, key)
+	}
+
+	return period, nil
+}
+
+func newPeriod(start time.Time) *Period {
+	year, week := start.ISOWeek()
+
+	return &Period{
+		Start: start,
+		End:   start.AddDate(0, 0, daysPerWeek),
+		Key:   fmt.Sprintf(periodKeyFormat, year, week),
+	}
+}
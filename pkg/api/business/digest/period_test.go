@@ -0,0 +1,73 @@
+package digest_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/eser/acik.io/pkg/api/business/digest"
+)
+
+func TestPeriodOf(t *testing.T) {
+	t.Parallel()
+
+	weekStart := time.Date(2025, time.December, 29, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name string
+		at   time.Time
+	}{
+		{name: "monday", at: weekStart},
+		{name: "thursday", at: time.Date(2026, time.January, 1, 15, 0, 0, 0, time.UTC)},
+		{name: "end of sunday", at: time.Date(2026, time.January, 4, 23, 59, 59, 0, time.UTC)},
+		{name: "monday in istanbul", at: time.Date(2026, time.January, 5, 1, 0, 0, 0, time.FixedZone("TRT", 3*60*60))},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			period := digest.PeriodOf(test.at)
+			if period.Key != "2026-W01" || !period.Start.Equal(weekStart) || !period.End.Equal(weekStart.AddDate(0, 0, 7)) {
+				t.Errorf("got %+v, want the first week of 2026", period)
+			}
+		})
+	}
+
+	if previous := digest.PreviousPeriod(time.Date(2026, time.January, 5, 6, 0, 0, 0, time.UTC)); previous.Key != "2026-W01" {
+		t.Errorf("got previous period %s, want 2026-W01", previous.Key)
+	}
+}
+
+func TestParsePeriod(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		key   string
+		start time.Time
+		err   error
+	}{
+		{key: "2026-W01", start: time.Date(2025, time.December, 29, 0, 0, 0, 0, time.UTC), err: nil},
+		{key: "2026-W43", start: time.Date(2026, time.October, 19, 0, 0, 0, 0, time.UTC), err: nil},
+		{key: "2020-W53", start: time.Date(2020, time.December, 28, 0, 0, 0, 0, time.UTC), err: nil},
+		{key: "2021-W53", start: time.Time{}, err: digest.ErrInvalidPeriod},
+		{key: "2026-W00", start: time.Time{}, err: digest.ErrInvalidPeriod},
+		{key: "2026-W1", start: time.Time{}, err: digest.ErrInvalidPeriod},
+		{key: "last week", start: time.Time{}, err: digest.ErrInvalidPeriod},
+	}
+
+	for _, test := range tests {
+		t.Run(test.key, func(t *testing.T) {
+			t.Parallel()
+
+			period, err := digest.ParsePeriod(test.key)
+			if !errors.Is(err, test.err) {
+				t.Fatalf("got %v, want %v", err, test.err)
+			}
+
+			if err == nil && (!period.Start.Equal(test.start) || period.Key != test.key) {
+				t.Errorf("got %+v, want the week starting %v", period, test.start)
+			}
+		})
+	}
+}
@@ -0,0 +1,316 @@
+package digest
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/mail"
+	"time"
+
+	"github.com/eser/acik.io/pkg/api/business/questions"
+)
+
+const (
+	MaxStories   = 10
+	MaxEvents    = 10
+	MaxQuestions = 5
+
+	// SendLease is how long a claimed send is left alone before it is
+	// considered abandoned by a crashed worker and claimed again.
+	SendLease = 30 * time.Minute
+)
+
+var (
+	ErrFailedToGetRecord              = errors.New("failed to get record")
+	ErrFailedToListRecords            = errors.New("failed to list records")
+	ErrFailedToUpdateRecord           = errors.New("failed to update record")
+	ErrFailedToRender                 = errors.New("failed to render digest")
+	ErrFailedToSend                   = errors.New("failed to send digest")
+	ErrFailedToEnqueue                = errors.New("failed to enqueue digest")
+	ErrRecordNotFound                 = errors.New("record not found")
+	ErrUnsubscribeSecretNotConfigured = errors.New("unsubscribe secret is not configured")
+)
+
+type Repository interface {
+	ListDigestRecipientIds(ctx context.Context) ([]string, error)
+	GetDigestRecipient(ctx context.Context, id string) (*GetDigestRecipientRow, error)
+	ListDigestStories(ctx context.Context, arg ListDigestStoriesParams) ([]*ListDigestStoriesRow, error)
+	ListDigestEvents(ctx context.Context, arg ListDigestEventsParams) ([]*ListDigestEventsRow, error)
+	ClaimDigestSend(ctx context.Context, arg ClaimDigestSendParams) (*DigestSend, error)
+	FinishDigestSend(ctx context.Context, arg FinishDigestSendParams) error
+	UnsubscribeFromDigest(ctx context.Context, userId string) error
+}
+
+type Questions interface {
+	ListTopUnanswered(ctx context.Context, limit int32) ([]*questions.Question, error)
+}
+
+type Renderer interface {
+	Render(digest *Digest) (*Rendered, error)
+}
+
+type Mailer interface {
+	Send(ctx context.Context, email *Email) error
+}
+
+type Publisher interface {
+	Enqueue(ctx context.Context, queueName string, payload any) error
+}
+
+type Service struct {
+	config    *Config
+	repo      Repository
+	questions Questions
+	renderer  Renderer
+	mailer    Mailer
+	publisher Publisher
+
+	idGenerator RecordIDGenerator
+}
+
+func NewService(
+	config *Config,
+	repo Repository,
+	questions Questions,
+	renderer Renderer,
+	mailer Mailer,
+	publisher Publisher,
+) *Service {
+	return &Service{
+		config:      config,
+		repo:        repo,
+		questions:   questions,
+		renderer:    renderer,
+		mailer:      mailer,
+		publisher:   publisher,
+		idGenerator: DefaultIDGenerator,
+	}
+}
+
+// EnqueueAll enqueues a send of the digest of the last full week for every
+// recipient and returns how many were enqueued. Sends are tracked per user and
+// period, so enqueueing a period again only retries the unsent ones.
+func (s *Service) EnqueueAll(ctx context.Context, now time.Time) (int, error) {
+	if s.config.UnsubscribeSecret == "" {
+		return 0, ErrUnsubscribeSecretNotConfigured
+	}
+
+	period := PreviousPeriod(now)
+
+	userIds, err := s.repo.ListDigestRecipientIds(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("%w: %w", ErrFailedToListRecords, err)
+	}
+
+	for i, userId := range userIds {
+		err := s.publisher.Enqueue(ctx, QueueSendDigest, &SendDigestJob{UserId: userId, Period: period.Key})
+		if err != nil {
+			return i, fmt.Errorf("%w(user: %s, period: %s): %w", ErrFailedToEnqueue, userId, period.Key, err)
+		}
+	}
+
+	return len(userIds), nil
+}
+
+// Send mails the digest of a period to a user, at most once. It returns the
+// resulting status: users who unsubscribed, have no address or had a quiet
+// week are skipped. A failed send is retried by sending again.
+func (s *Service) Send(ctx context.Context, userId string, periodKey string) (string, error) {
+	if s.config.UnsubscribeSecret == "" {
+		return "", ErrUnsubscribeSecretNotConfigured
+	}
+
+	period, err := ParsePeriod(periodKey)
+	if err != nil {
+		return "", err
+	}
+
+	recipient, err := s.getRecipient(ctx, userId)
+	if err != nil {
+		return "", err
+	}
+
+	if recipient.IsUnsubscribed || !recipient.Email.Valid {
+		return StatusSkipped, nil
+	}
+
+	claim, err := s.repo.ClaimDigestSend(ctx, ClaimDigestSendParams{
+		Id:          string(s.idGenerator()),
+		UserId:      userId,
+		Period:      period.Key,
+		StaleBefore: time.Now().Add(-SendLease),
+	})
+	if err != nil {
+		return "", fmt.Errorf("%w(user: %s, period: %s): %w", ErrFailedToUpdateRecord, userId, period.Key, err)
+	}
+
+	// sent, skipped or being sent by someone else.
+	if claim == nil {
+		return StatusSkipped, nil
+	}
+
+	status, sendErr := s.deliver(ctx, recipient, period)
+
+	errorMessage := sql.NullString{} //nolint:exhaustruct
+	if sendErr != nil {
+		errorMessage = sql.NullString{String: sendErr.Error(), Valid: true}
+	}
+
+	err = s.repo.FinishDigestSend(ctx, FinishDigestSendParams{Status: status, Error: errorMessage, Id: claim.Id})
+	if err != nil {
+		return "", errors.Join(sendErr, fmt.Errorf("%w(id: %s): %w", ErrFailedToUpdateRecord, claim.Id, err))
+	}
+
+	return status, sendErr
+}
+
+// Preview renders the digest of a period for a user without sending it or
+// recording anything. An empty period key previews the last full week.
+func (s *Service) Preview(ctx context.Context, userId string, periodKey string) (*Digest, *Rendered, error) {
+	now := time.Now()
+	period := PreviousPeriod(now)
+
+	if periodKey != "" {
+		var err error
+
+		period, err = ParsePeriod(periodKey)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	recipient, err := s.getRecipient(ctx, userId)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	digest, err := s.Compose(ctx, recipient, period, now)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	rendered, err := s.renderer.Render(digest)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w(user: %s): %w", ErrFailedToRender, userId, err)
+	}
+
+	return digest, rendered, nil
+}
+
+// Compose collects the content of a digest: the stories the followed profiles
+// published during the period, the events they organize in the near future
+// and the top unanswered questions.
+func (s *Service) Compose(
+	ctx context.Context,
+	recipient *GetDigestRecipientRow,
+	period *Period,
+	now time.Time,
+) (*Digest, error) {
+	stories, err := s.repo.ListDigestStories(ctx, ListDigestStoriesParams{
+		UserId:         recipient.Id,
+		PublishedSince: period.Start,
+		PublishedUntil: period.End,
+		LimitCount:     MaxStories,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%w(user: %s): %w", ErrFailedToListRecords, recipient.Id, err)
+	}
+
+	events, err := s.repo.ListDigestEvents(ctx, ListDigestEventsParams{
+		StartsAfter:  now,
+		StartsBefore: now.Add(s.config.EventHorizon),
+		UserId:       recipient.Id,
+		LimitCount:   MaxEvents,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%w(user: %s): %w", ErrFailedToListRecords, recipient.Id, err)
+	}
+
+	topQuestions, err := s.questions.ListTopUnanswered(ctx, MaxQuestions)
+	if err != nil {
+		return nil, err //nolint:wrapcheck
+	}
+
+	unsubscribeUrl := ""
+	if s.config.UnsubscribeSecret != "" {
+		unsubscribeUrl = s.config.UnsubscribeBaseUrl +
+			SignUnsubscribeToken([]byte(s.config.UnsubscribeSecret), recipient.Id)
+	}
+
+	return &Digest{
+		Recipient:      recipient,
+		Period:         period,
+		SiteUrl:        s.config.SiteUrl,
+		UnsubscribeUrl: unsubscribeUrl,
+		Stories:        stories,
+		Events:         events,
+		Questions:      topQuestions,
+	}, nil
+}
+
+// Unsubscribe stops the digests of the user the token was issued for.
+func (s *Service) Unsubscribe(ctx context.Context, token string) error {
+	if s.config.UnsubscribeSecret == "" {
+		return ErrUnsubscribeSecretNotConfigured
+	}
+
+	userId, err := VerifyUnsubscribeToken([]byte(s.config.UnsubscribeSecret), token)
+	if err != nil {
+		return err
+	}
+
+	err = s.repo.UnsubscribeFromDigest(ctx, userId)
+	if err != nil {
+		return fmt.Errorf("%w(user: %s): %w", ErrFailedToUpdateRecord, userId, err)
+	}
+
+	return nil
+}
+
+func (s *Service) deliver(ctx context.Context, recipient *GetDigestRecipientRow, period *Period) (string, error) {
+	digest, err := s.Compose(ctx, recipient, period, time.Now())
+	if err != nil {
+		return StatusFailed, err
+	}
+
+	if digest.IsEmpty() {
+		return StatusSkipped, nil
+	}
+
+	rendered, err := s.renderer.Render(digest)
+	if err != nil {
+		return StatusFailed, fmt.Errorf("%w(user: %s): %w", ErrFailedToRender, recipient.Id, err)
+	}
+
+	to := &mail.Address{Name: recipient.Name, Address: recipient.Email.String}
+
+	err = s.mailer.Send(ctx, &Email{
+		Headers: map[string]string{
+			"List-Unsubscribe":      "<" + digest.UnsubscribeUrl + ">",
+			"List-Unsubscribe-Post": "List-Unsubscribe=One-Click",
+		},
+		To:      to.String(),
+		Subject: rendered.Subject,
+		Text:    rendered.Text,
+		Html:    rendered.Html,
+	})
+	if err != nil {
+		return StatusFailed, fmt.Errorf("%w(user: %s, period: %s): %w", ErrFailedToSend, recipient.Id, period.Key, err)
+	}
+
+	return StatusSent, nil
+}
+
+func (s *Service) getRecipient(ctx context.Context, userId string) (*GetDigestRecipientRow, error) {
+	recipient, err := s.repo.GetDigestRecipient(ctx, userId)
+	if err != nil {
+		return nil, fmt.Errorf("%w(user: %s): %w", ErrFailedToGetRecord, userId, err)
+	}
+
+	if recipient == nil {
+		return nil, fmt.Errorf("%w(user: %s)", ErrRecordNotFound, userId)
+	}
+
+	return recipient, nil
+}
@@ -0,0 +1,71 @@
+package digest
+
+import (
+	"time"
+
+	"github.com/eser/acik.io/pkg/api/business/questions"
+	"github.com/oklog/ulid/v2"
+)
+
+const (
+	StatusSending = "sending"
+	StatusSent    = "sent"
+	StatusFailed  = "failed"
+	StatusSkipped = "skipped"
+
+	QueueSendDigest = "digest.send"
+)
+
+type RecordID string
+
+type RecordIDGenerator func() RecordID
+
+func DefaultIDGenerator() RecordID {
+	return RecordID(ulid.Make().String())
+}
+
+// SendDigestJob is enqueued for every recipient of a period's digest.
+type SendDigestJob struct {
+	UserId string `json:"userId"`
+	Period string `json:"period"`
+}
+
+// Digest is the content of a recipient's digest for a period.
+type Digest struct {
+	Recipient      *GetDigestRecipientRow
+	Period         *Period
+	SiteUrl        string
+	UnsubscribeUrl string
+	Stories        []*ListDigestStoriesRow
+	Events         []*ListDigestEventsRow
+	Questions      []*questions.Question
+}
+
+// IsEmpty reports whether nothing happened in the followed profiles during
+// the period. Top questions alone do not make a digest worth sending.
+func (d *Digest) IsEmpty() bool {
+	return len(d.Stories) == 0 && len(d.Events) == 0
+}
+
+// Rendered is a digest rendered as a mail.
+type Rendered struct {
+	Subject string
+	Text    string
+	Html    string
+}
+
+// Email is a rendered digest addressed to its recipient.
+type Email struct {
+	Headers map[string]string
+	To      string
+	Subject string
+	Text    string
+	Html    string
+}
+
+// Period is the week a digest covers, identified by its ISO week.
+type Period struct {
+	Start time.Time
+	End   time.Time
+	Key   string
+}
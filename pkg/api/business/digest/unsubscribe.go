@@ -0,0 +1,50 @@
+package digest
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+const (
+	unsubscribeTokenSeparator = "."
+	unsubscribeTokenVersion   = "digest-unsubscribe:v1:"
+	unsubscribeTokenParts     = 2
+)
+
+var ErrInvalidUnsubscribeToken = errors.New("invalid unsubscribe token")
+
+// SignUnsubscribeToken produces a token in the form of `<userId>.<signature>`
+// where the signature is an HMAC-SHA256 over the user id. Tokens do not
+// expire, so links in old digests keep working.
+func SignUnsubscribeToken(secret []byte, userId string) string {
+	return userId + unsubscribeTokenSeparator + signUnsubscribePayload(secret, userId)
+}
+
+// VerifyUnsubscribeToken checks the signature of the token and returns the
+// user id it was issued for.
+func VerifyUnsubscribeToken(secret []byte, token string) (string, error) {
+	parts := strings.Split(strings.TrimSpace(token), unsubscribeTokenSeparator)
+	if len(parts) != unsubscribeTokenParts || parts[0] == "" {
+		return "", fmt.Errorf("%w: malformed", ErrInvalidUnsubscribeToken)
+	}
+
+	userId, signature := parts[0], parts[1]
+
+	expected := signUnsubscribePayload(secret, userId)
+	if !hmac.Equal([]byte(signature), []byte(expected)) {
+		return "", fmt.Errorf("%w: signature mismatch", ErrInvalidUnsubscribeToken)
+	}
+
+	return userId, nil
+}
+
+func signUnsubscribePayload(secret []byte, userId string) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(unsubscribeTokenVersion + userId))
+
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
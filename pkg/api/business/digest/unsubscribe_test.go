@@ -0,0 +1,42 @@
+package digest_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/eser/acik.io/pkg/api/business/digest"
+)
+
+func TestUnsubscribeToken(t *testing.T) {
+	t.Parallel()
+
+	secret := []byte("secret")
+	token := digest.SignUnsubscribeToken(secret, "user")
+
+	userId, err := digest.VerifyUnsubscribeToken(secret, " "+token+"\n")
+	if err != nil || userId != "user" {
+		t.Fatalf("got %q, %v, want the user of the token", userId, err)
+	}
+
+	tests := []struct {
+		name  string
+		token string
+	}{
+		{name: "other secret", token: digest.SignUnsubscribeToken([]byte("other"), "user")},
+		{name: "other user", token: "admin" + token[len("user"):]},
+		{name: "no signature", token: "user"},
+		{name: "no user", token: token[len("user"):]},
+		{name: "extra part", token: token + ".x"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := digest.VerifyUnsubscribeToken(secret, test.token)
+			if !errors.Is(err, digest.ErrInvalidUnsubscribeToken) {
+				t.Errorf("got %v, want %v", err, digest.ErrInvalidUnsubscribeToken)
+			}
+		})
+	}
+}
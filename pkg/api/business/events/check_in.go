@@ -0,0 +1,91 @@
+package events
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	checkInCodeSeparator = "."
+	checkInCodeVersion   = "checkin:v2:"
+	checkInCodeParts     = 5
+)
+
+var (
+	ErrInvalidCheckInCode = errors.New("invalid check-in code")
+	ErrCheckInCodeExpired = errors.New("check-in code expired")
+)
+
+// CheckInClaims are what a check-in code was issued for.
+type CheckInClaims struct {
+	IssuedAt  time.Time
+	ExpiresAt time.Time
+	EventId   string
+	ProfileId string
+}
+
+// SignCheckInCode produces a code in the form of
+// `<eventId>.<profileId>.<issuedAt>.<expiresAt>.<signature>`, the times being
+// unix seconds, where the signature is an HMAC-SHA256 over the rest.
+func SignCheckInCode(secret []byte, claims *CheckInClaims) string {
+	payload := strings.Join([]string{
+		claims.EventId,
+		claims.ProfileId,
+		strconv.FormatInt(claims.IssuedAt.Unix(), 10),
+		strconv.FormatInt(claims.ExpiresAt.Unix(), 10),
+	}, checkInCodeSeparator)
+
+	return payload + checkInCodeSeparator + signCheckInPayload(secret, payload)
+}
+
+// VerifyCheckInCode checks the signature of the code and that it is not
+// expired at now, and returns what it was issued for.
+func VerifyCheckInCode(secret []byte, code string, now time.Time) (*CheckInClaims, error) {
+	parts := strings.Split(strings.TrimSpace(code), checkInCodeSeparator)
+	if len(parts) != checkInCodeParts || parts[0] == "" || parts[1] == "" {
+		return nil, fmt.Errorf("%w: malformed", ErrInvalidCheckInCode)
+	}
+
+	payload := strings.Join(parts[:checkInCodeParts-1], checkInCodeSeparator)
+
+	expected := signCheckInPayload(secret, payload)
+	if !hmac.Equal([]byte(parts[checkInCodeParts-1]), []byte(expected)) {
+		return nil, fmt.Errorf("%w: signature mismatch", ErrInvalidCheckInCode)
+	}
+
+	issuedAt, err := strconv.ParseInt(parts[2], 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("%w: malformed issue time", ErrInvalidCheckInCode)
+	}
+
+	expiresAt, err := strconv.ParseInt(parts[3], 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("%w: malformed expiry time", ErrInvalidCheckInCode)
+	}
+
+	claims := &CheckInClaims{
+		IssuedAt:  time.Unix(issuedAt, 0),
+		ExpiresAt: time.Unix(expiresAt, 0),
+		EventId:   parts[0],
+		ProfileId: parts[1],
+	}
+
+	if !now.Before(claims.ExpiresAt) {
+		return nil, fmt.Errorf("%w: %w at %s", ErrInvalidCheckInCode, ErrCheckInCodeExpired, claims.ExpiresAt.Format(time.RFC3339))
+	}
+
+	return claims, nil
+}
+
+func signCheckInPayload(secret []byte, payload string) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(checkInCodeVersion + payload))
+
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
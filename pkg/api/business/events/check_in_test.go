@@ -0,0 +1,96 @@
+package events_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/eser/acik.io/pkg/api/business/events"
+)
+
+const (
+	eventId   = "01HEVENT000000000000000000"
+	profileId = "01HPROFILE0000000000000000"
+)
+
+var (
+	secret = []byte("check-in secret")                      //nolint:gochecknoglobals
+	now    = time.Date(2026, 10, 19, 18, 0, 0, 0, time.UTC) //nolint:gochecknoglobals
+)
+
+func sign(expiresAt time.Time) string {
+	return events.SignCheckInCode(secret, &events.CheckInClaims{
+		IssuedAt:  now.Add(-time.Hour),
+		ExpiresAt: expiresAt,
+		EventId:   eventId,
+		ProfileId: profileId,
+	})
+}
+
+func TestVerifyCheckInCode(t *testing.T) {
+	t.Parallel()
+
+	claims, err := events.VerifyCheckInCode(secret, sign(now.Add(time.Hour)), now)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if claims.EventId != eventId || claims.ProfileId != profileId {
+		t.Errorf("got claims for %s/%s, want %s/%s", claims.EventId, claims.ProfileId, eventId, profileId)
+	}
+
+	if !claims.IssuedAt.Equal(now.Add(-time.Hour)) || !claims.ExpiresAt.Equal(now.Add(time.Hour)) {
+		t.Errorf("got claims issued at %s expiring at %s", claims.IssuedAt, claims.ExpiresAt)
+	}
+}
+
+func TestVerifyCheckInCodeRejects(t *testing.T) {
+	t.Parallel()
+
+	valid := sign(now.Add(time.Hour))
+	parts := strings.Split(valid, ".")
+
+	tamper := func(index int, value string) string {
+		tampered := append([]string{}, parts...)
+		tampered[index] = value
+
+		return strings.Join(tampered, ".")
+	}
+
+	tests := []struct {
+		want error
+		name string
+		code string
+	}{
+		{name: "empty", code: "", want: events.ErrInvalidCheckInCode},
+		{name: "code of the previous format", code: eventId + "." + profileId + "." + parts[4], want: events.ErrInvalidCheckInCode},
+		{name: "other event", code: tamper(0, "01HOTHER000000000000000000"), want: events.ErrInvalidCheckInCode},
+		{name: "other profile", code: tamper(1, "01HOTHER000000000000000000"), want: events.ErrInvalidCheckInCode},
+		{name: "extended expiry", code: tamper(3, "9999999999"), want: events.ErrInvalidCheckInCode},
+		{name: "forged signature", code: tamper(4, "c2lnbmF0dXJl"), want: events.ErrInvalidCheckInCode},
+		{
+			name: "other secret",
+			code: events.SignCheckInCode([]byte("other secret"), &events.CheckInClaims{
+				IssuedAt:  now,
+				ExpiresAt: now.Add(time.Hour),
+				EventId:   eventId,
+				ProfileId: profileId,
+			}),
+			want: events.ErrInvalidCheckInCode,
+		},
+		{name: "expired", code: sign(now.Add(-time.Second)), want: events.ErrCheckInCodeExpired},
+		{name: "expiring now", code: sign(now), want: events.ErrCheckInCodeExpired},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := events.VerifyCheckInCode(secret, test.code, now)
+			if !errors.Is(err, test.want) {
+				t.Errorf("got error %v, want %v", err, test.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,8 @@
+package events
+
+import "time"
+
+type Config struct {
+	CheckInSecret string        `conf:"CHECK_IN_SECRET"`              // signs the check-in codes handed out to attendees
+	CheckInGrace  time.Duration `conf:"CHECK_IN_GRACE"  default:"2h"` // how long after the end of an event its check-in codes are still accepted
+}
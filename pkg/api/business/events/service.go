@@ -0,0 +1,810 @@
+package events
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/eser/acik.io/pkg/api/business/slugs"
+	"github.com/eser/acik.io/pkg/api/business/tags"
+	"github.com/eser/acik.io/pkg/api/business/users"
+)
+
+const (
+	hoursPerDay = 24
+
+	occurrenceSlugDateLayout = "2006-01-02"
+)
+
+var (
+	ErrFailedToCreateRecord        = errors.New("failed to create record")
+	ErrFailedToGetRecord           = errors.New("failed to get record")
+	ErrFailedToListRecords         = errors.New("failed to list records")
+	ErrFailedToUpdateRecord        = errors.New("failed to update record")
+	ErrRecordNotFound              = errors.New("record not found")
+	ErrCheckInSecretNotConfigured  = errors.New("check-in secret is not configured")
+	ErrNotAttending                = errors.New("profile has not RSVP'd to the event")
+	ErrNotOrganizer                = errors.New("user is not an organizer of the event")
+	ErrCheckInCodeForAnotherEvent  = errors.New("check-in code belongs to another event")
+	ErrAlreadyCheckedIn            = errors.New("attendee has already checked in")
+	ErrAlreadyPublished            = errors.New("event is already published")
+	ErrInvalidInput                = errors.New("invalid input")
+	ErrFailedToCheckOrganizerState = errors.New("failed to check organizer state")
+)
+
+type Repository interface {
+	Transact(ctx context.Context, fn func(ctx context.Context) error) error
+	GetEventById(ctx context.Context, id string) (*Event, error)
+	GetEventBySlug(ctx context.Context, slug string) (*Event, error)
+	ListUpcomingEvents(ctx context.Context, arg ListUpcomingEventsParams) ([]*Event, error)
+	ListRecurringEventSeries(ctx context.Context) ([]*EventSeries, error)
+	GetLatestEventOfSeries(ctx context.Context, seriesId sql.NullString) (*Event, error)
+	CreateEvent(ctx context.Context, arg CreateEventParams) (*Event, error)
+	PublishEvent(ctx context.Context, id string) (*Event, error)
+	RescheduleEvent(ctx context.Context, arg RescheduleEventParams) (*RescheduleEventRow, error)
+	SetEventPictureUri(ctx context.Context, arg SetEventPictureUriParams) (int64, error)
+	ListEventOrganizerProfileIds(ctx context.Context, eventId string) ([]string, error)
+	ListEventAttendeeUserIds(ctx context.Context, eventId string) ([]string, error)
+	GetEventAttendance(ctx context.Context, arg GetEventAttendanceParams) (*EventAttendance, error)
+	CreateEventAttendance(ctx context.Context, arg CreateEventAttendanceParams) (*EventAttendance, error)
+	UpdateEventAttendanceKind(ctx context.Context, arg UpdateEventAttendanceKindParams) (int64, error)
+	IsEventAttendeeOfKindForUser(ctx context.Context, arg IsEventAttendeeOfKindForUserParams) (bool, error)
+	IsUserSuspended(ctx context.Context, id string) (bool, error)
+}
+
+// EventRecorder records domain events. It is called within the transaction
+// of the state change the event describes.
+type EventRecorder interface {
+	Record(ctx context.Context, aggregateType string, aggregateId string, eventType string, payload any) error
+}
+
+type Tagger interface {
+	SetTags(ctx context.Context, kind string, entityId string, names []string) ([]*tags.TagRef, error)
+	TagsOf(ctx context.Context, kind string, ids []string) (map[string][]*tags.TagRef, error)
+}
+
+type SlugRenamer interface {
+	Rename(ctx context.Context, kind string, id string, slug string) (string, error)
+}
+
+type Service struct {
+	config      *Config
+	repo        Repository
+	events      EventRecorder
+	tagger      Tagger
+	slugs       SlugRenamer
+	idGenerator RecordIDGenerator
+}
+
+func NewService(config *Config, repo Repository, events EventRecorder, tagger Tagger, slugs SlugRenamer) *Service {
+	return &Service{
+		config:      config,
+		repo:        repo,
+		events:      events,
+		tagger:      tagger,
+		slugs:       slugs,
+		idGenerator: DefaultIDGenerator,
+	}
+}
+
+func (s *Service) GetById(ctx context.Context, id string) (*Event, error) {
+	record, err := s.repo.GetEventById(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("%w(id: %s): %w", ErrFailedToGetRecord, id, err)
+	}
+
+	if record == nil {
+		return nil, fmt.Errorf("%w(id: %s)", ErrRecordNotFound, id)
+	}
+
+	return record, nil
+}
+
+func (s *Service) GetBySlug(ctx context.Context, slug string) (*Event, error) {
+	record, err := s.repo.GetEventBySlug(ctx, slug)
+	if err != nil {
+		return nil, fmt.Errorf("%w(slug: %s): %w", ErrFailedToGetRecord, slug, err)
+	}
+
+	if record == nil {
+		return nil, fmt.Errorf("%w(slug: %s)", ErrRecordNotFound, slug)
+	}
+
+	return record, nil
+}
+
+// GetVisibleBySlug returns an event if it is published, or if the given user
+// organizes it. userId may be empty for anonymous visitors.
+func (s *Service) GetVisibleBySlug(ctx context.Context, slug string, userId string) (*Event, error) {
+	record, err := s.GetBySlug(ctx, slug)
+	if err != nil {
+		return nil, err
+	}
+
+	if record.Status == StatusPublished {
+		return record, nil
+	}
+
+	if userId != "" {
+		isOrganizer, err := s.repo.IsEventAttendeeOfKindForUser(ctx, IsEventAttendeeOfKindForUserParams{
+			EventId: record.Id,
+			Kind:    AttendanceKindOrganizer,
+			UserId:  userId,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("%w(event: %s): %w", ErrFailedToCheckOrganizerState, record.Id, err)
+		}
+
+		if isOrganizer {
+			return record, nil
+		}
+	}
+
+	return nil, fmt.Errorf("%w(slug: %s)", ErrRecordNotFound, slug)
+}
+
+// ListUpcoming returns the published events that have not ended yet, the
+// soonest first. tagId narrows them down to the events with a tag when it
+// isn't empty.
+func (s *Service) ListUpcoming(ctx context.Context, tagId string, limit int32) ([]*Event, error) {
+	records, err := s.repo.ListUpcomingEvents(ctx, ListUpcomingEventsParams{
+		TagId:      sql.NullString{String: tagId, Valid: tagId != ""},
+		LimitCount: limit,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrFailedToListRecords, err)
+	}
+
+	return records, nil
+}
+
+// ListOrganizerProfileIds returns the profiles organizing the event.
+func (s *Service) ListOrganizerProfileIds(ctx context.Context, eventId string) ([]string, error) {
+	profileIds, err := s.repo.ListEventOrganizerProfileIds(ctx, eventId)
+	if err != nil {
+		return nil, fmt.Errorf("%w(event: %s): %w", ErrFailedToListRecords, eventId, err)
+	}
+
+	return profileIds, nil
+}
+
+// ListAttendeeUserIds returns the users who RSVP'd to or attended the event
+// with their individual profiles.
+func (s *Service) ListAttendeeUserIds(ctx context.Context, eventId string) ([]string, error) {
+	userIds, err := s.repo.ListEventAttendeeUserIds(ctx, eventId)
+	if err != nil {
+		return nil, fmt.Errorf("%w(event: %s): %w", ErrFailedToListRecords, eventId, err)
+	}
+
+	return userIds, nil
+}
+
+// Rsvp registers a profile of the user as attending a published event.
+// Profiles already attending are left as they are.
+func (s *Service) Rsvp(ctx context.Context, eventId string, userId string, profileId string) (*EventAttendance, error) {
+	err := s.ensureCanAttend(ctx, eventId, userId)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.repo.Transact(ctx, func(ctx context.Context) error {
+		previousKind := ""
+
+		current, err := s.repo.GetEventAttendance(ctx, GetEventAttendanceParams{EventId: eventId, ProfileId: profileId})
+		if err != nil {
+			return fmt.Errorf("%w(event: %s, profile: %s): %w", ErrFailedToGetRecord, eventId, profileId, err)
+		}
+
+		switch {
+		case current == nil:
+			created, err := s.repo.CreateEventAttendance(ctx, CreateEventAttendanceParams{
+				Id:        string(s.idGenerator()),
+				Kind:      AttendanceKindRsvp,
+				EventId:   eventId,
+				ProfileId: profileId,
+			})
+			if err != nil {
+				return fmt.Errorf("%w(event: %s, profile: %s): %w", ErrFailedToCreateRecord, eventId, profileId, err)
+			}
+
+			// a concurrent RSVP got there first.
+			if created == nil {
+				return nil
+			}
+		case current.Kind == AttendanceKindCancelled:
+			previousKind = AttendanceKindCancelled
+
+			affected, err := s.repo.UpdateEventAttendanceKind(ctx, UpdateEventAttendanceKindParams{
+				NewKind:     AttendanceKindRsvp,
+				EventId:     eventId,
+				ProfileId:   profileId,
+				CurrentKind: AttendanceKindCancelled,
+			})
+			if err != nil {
+				return fmt.Errorf("%w(event: %s, profile: %s): %w", ErrFailedToUpdateRecord, eventId, profileId, err)
+			}
+
+			if affected == 0 {
+				return nil
+			}
+		default:
+			return nil
+		}
+
+		return s.recordRsvpChange(ctx, current, eventId, profileId, previousKind)
+	})
+	if err != nil {
+		return nil, err //nolint:wrapcheck
+	}
+
+	return s.getAttendance(ctx, eventId, profileId)
+}
+
+// CancelRsvp withdraws the RSVP of a profile. Attendees who already checked
+// in and organizers can't withdraw.
+func (s *Service) CancelRsvp(ctx context.Context, eventId string, userId string, profileId string) (*EventAttendance, error) { //nolint:lll
+	err := s.ensureCanAttend(ctx, eventId, userId)
+	if err != nil {
+		return nil, err
+	}
+
+	var affected int64
+
+	err = s.repo.Transact(ctx, func(ctx context.Context) error {
+		before, err := s.repo.GetEventAttendance(ctx, GetEventAttendanceParams{EventId: eventId, ProfileId: profileId})
+		if err != nil {
+			return fmt.Errorf("%w(event: %s, profile: %s): %w", ErrFailedToGetRecord, eventId, profileId, err)
+		}
+
+		affected, err = s.repo.UpdateEventAttendanceKind(ctx, UpdateEventAttendanceKindParams{
+			NewKind:     AttendanceKindCancelled,
+			EventId:     eventId,
+			ProfileId:   profileId,
+			CurrentKind: AttendanceKindRsvp,
+		})
+		if err != nil {
+			return fmt.Errorf("%w(event: %s, profile: %s): %w", ErrFailedToUpdateRecord, eventId, profileId, err)
+		}
+
+		if affected == 0 {
+			return nil
+		}
+
+		return s.recordRsvpChange(ctx, before, eventId, profileId, AttendanceKindRsvp)
+	})
+	if err != nil {
+		return nil, err //nolint:wrapcheck
+	}
+
+	attendance, err := s.getAttendance(ctx, eventId, profileId)
+	if err != nil {
+		return nil, err
+	}
+
+	if affected == 0 && attendance.Kind != AttendanceKindCancelled {
+		return nil, fmt.Errorf("%w(event: %s, profile: %s): attendance is %s", ErrNotAttending, eventId, profileId, attendance.Kind)
+	}
+
+	return attendance, nil
+}
+
+// ensureCanAttend checks that the event is published and the user is not
+// suspended.
+func (s *Service) ensureCanAttend(ctx context.Context, eventId string, userId string) error {
+	record, err := s.GetById(ctx, eventId)
+	if err != nil {
+		return err
+	}
+
+	if record.Status != StatusPublished {
+		return fmt.Errorf("%w(id: %s)", ErrRecordNotFound, eventId)
+	}
+
+	isSuspended, err := s.repo.IsUserSuspended(ctx, userId)
+	if err != nil {
+		return fmt.Errorf("%w(user: %s): %w", ErrFailedToGetRecord, userId, err)
+	}
+
+	if isSuspended {
+		return fmt.Errorf("%w(id: %s)", users.ErrUserSuspended, userId)
+	}
+
+	return nil
+}
+
+// IssueCheckInCode returns the signed check-in code of an attendee who has
+// RSVP'd to the event.
+func (s *Service) IssueCheckInCode(ctx context.Context, eventId string, profileId string) (*CheckInCodeResponse, error) {
+	if s.config.CheckInSecret == "" {
+		return nil, ErrCheckInSecretNotConfigured
+	}
+
+	attendance, err := s.getAttendance(ctx, eventId, profileId)
+	if err != nil {
+		return nil, err
+	}
+
+	if attendance.Kind != AttendanceKindRsvp && attendance.Kind != AttendanceKindAttended {
+		return nil, fmt.Errorf("%w(event: %s, profile: %s)", ErrNotAttending, eventId, profileId)
+	}
+
+	event, err := s.GetById(ctx, eventId)
+	if err != nil {
+		return nil, err
+	}
+
+	// codes are handed out ahead of the event, and are good until a while
+	// after it ends.
+	claims := &CheckInClaims{
+		IssuedAt:  time.Now(),
+		ExpiresAt: event.TimeEnd.Add(s.config.CheckInGrace),
+		EventId:   eventId,
+		ProfileId: profileId,
+	}
+
+	return &CheckInCodeResponse{
+		ExpiresAt: claims.ExpiresAt,
+		EventId:   eventId,
+		ProfileId: profileId,
+		Code:      SignCheckInCode([]byte(s.config.CheckInSecret), claims),
+	}, nil
+}
+
+// CheckIn verifies a check-in code presented at the venue and marks the
+// attendee as attended. Codes issued for other events and codes that have
+// already been used are rejected.
+func (s *Service) CheckIn(ctx context.Context, eventId string, organizerUserId string, code string) (*EventAttendance, error) { //nolint:lll
+	if s.config.CheckInSecret == "" {
+		return nil, ErrCheckInSecretNotConfigured
+	}
+
+	err := s.EnsureOrganizer(ctx, eventId, organizerUserId)
+	if err != nil {
+		return nil, err
+	}
+
+	claims, err := VerifyCheckInCode([]byte(s.config.CheckInSecret), code, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	if claims.EventId != eventId {
+		return nil, fmt.Errorf("%w(event: %s, code event: %s)", ErrCheckInCodeForAnotherEvent, eventId, claims.EventId)
+	}
+
+	profileId := claims.ProfileId
+
+	// the update only succeeds while the attendance is still an RSVP, so
+	// concurrent or repeated scans of the same code can't check in twice.
+	var affected int64
+
+	err = s.repo.Transact(ctx, func(ctx context.Context) error {
+		before, err := s.repo.GetEventAttendance(ctx, GetEventAttendanceParams{EventId: eventId, ProfileId: profileId})
+		if err != nil {
+			return fmt.Errorf("%w(event: %s, profile: %s): %w", ErrFailedToGetRecord, eventId, profileId, err)
+		}
+
+		affected, err = s.repo.UpdateEventAttendanceKind(ctx, UpdateEventAttendanceKindParams{
+			NewKind:     AttendanceKindAttended,
+			EventId:     eventId,
+			ProfileId:   profileId,
+			CurrentKind: AttendanceKindRsvp,
+		})
+		if err != nil {
+			return fmt.Errorf("%w(event: %s, profile: %s): %w", ErrFailedToUpdateRecord, eventId, profileId, err)
+		}
+
+		if affected == 0 {
+			return nil
+		}
+
+		return s.recordRsvpChange(ctx, before, eventId, profileId, AttendanceKindRsvp)
+	})
+	if err != nil {
+		return nil, err //nolint:wrapcheck
+	}
+
+	attendance, err := s.getAttendance(ctx, eventId, profileId)
+	if err != nil {
+		return nil, err
+	}
+
+	if affected == 0 {
+		if attendance.Kind == AttendanceKindAttended {
+			return nil, fmt.Errorf("%w(event: %s, profile: %s)", ErrAlreadyCheckedIn, eventId, profileId)
+		}
+
+		return nil, fmt.Errorf("%w(event: %s, profile: %s)", ErrNotAttending, eventId, profileId)
+	}
+
+	return attendance, nil
+}
+
+// Publish makes a draft event public. Organizers only.
+func (s *Service) Publish(ctx context.Context, eventId string, userId string) (*Event, error) {
+	err := s.EnsureOrganizer(ctx, eventId, userId)
+	if err != nil {
+		return nil, err
+	}
+
+	var record *Event
+
+	err = s.repo.Transact(ctx, func(ctx context.Context) error {
+		before, err := s.GetById(ctx, eventId)
+		if err != nil {
+			return err
+		}
+
+		record, err = s.repo.PublishEvent(ctx, eventId)
+		if err != nil {
+			return fmt.Errorf("%w(id: %s): %w", ErrFailedToUpdateRecord, eventId, err)
+		}
+
+		if record == nil {
+			return fmt.Errorf("%w(id: %s)", ErrAlreadyPublished, eventId)
+		}
+
+		return s.events.Record(ctx, AggregateEvent, record.Id, EventEventPublished, &EventPublishedEvent{ //nolint:wrapcheck
+			TimeStart:   record.TimeStart,
+			TimeEnd:     record.TimeEnd,
+			PublishedAt: record.PublishedAt.Time,
+			EventId:     record.Id,
+			Slug:        record.Slug,
+			Title:       record.Title,
+			before:      before,
+			after:       record,
+		})
+	})
+	if err != nil {
+		return nil, err //nolint:wrapcheck
+	}
+
+	return record, nil
+}
+
+// Reschedule moves an event to a new time. Its attendees hear about it
+// through EventEventRescheduled, unless the time stays the same. Organizers
+// only.
+func (s *Service) Reschedule(ctx context.Context, eventId string, userId string, input *RescheduleInput) (*Event, error) { //nolint:lll
+	if input.TimeStart.IsZero() || !input.TimeEnd.After(input.TimeStart) {
+		return nil, fmt.Errorf("%w: timeEnd must be after timeStart", ErrInvalidInput)
+	}
+
+	err := s.EnsureOrganizer(ctx, eventId, userId)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.repo.Transact(ctx, func(ctx context.Context) error {
+		before, err := s.GetById(ctx, eventId)
+		if err != nil {
+			return err
+		}
+
+		previous, err := s.repo.RescheduleEvent(ctx, RescheduleEventParams{
+			TimeStart: input.TimeStart,
+			TimeEnd:   input.TimeEnd,
+			Id:        eventId,
+		})
+		if err != nil {
+			return fmt.Errorf("%w(id: %s): %w", ErrFailedToUpdateRecord, eventId, err)
+		}
+
+		if previous == nil {
+			return fmt.Errorf("%w(id: %s)", ErrRecordNotFound, eventId)
+		}
+
+		if previous.PreviousTimeStart.Equal(input.TimeStart) && previous.PreviousTimeEnd.Equal(input.TimeEnd) {
+			return nil
+		}
+
+		after, err := s.GetById(ctx, eventId)
+		if err != nil {
+			return err
+		}
+
+		return s.events.Record(ctx, AggregateEvent, eventId, EventEventRescheduled, &EventRescheduledEvent{ //nolint:wrapcheck
+			TimeStart:         input.TimeStart,
+			TimeEnd:           input.TimeEnd,
+			PreviousTimeStart: previous.PreviousTimeStart,
+			PreviousTimeEnd:   previous.PreviousTimeEnd,
+			EventId:           eventId,
+			Slug:              previous.Slug,
+			Title:             previous.Title,
+			before:            before,
+			after:             after,
+		})
+	})
+	if err != nil {
+		return nil, err //nolint:wrapcheck
+	}
+
+	return s.GetById(ctx, eventId)
+}
+
+// SetPicture replaces the picture of an event. Authorization is left to the
+// caller, see EnsureOrganizer.
+func (s *Service) SetPicture(ctx context.Context, eventId string, uri string) (*Event, error) {
+	return s.update(ctx, eventId, func(ctx context.Context, _ *Event) (bool, error) {
+		affected, err := s.repo.SetEventPictureUri(ctx, SetEventPictureUriParams{
+			EventPictureUri: sql.NullString{String: uri, Valid: uri != ""},
+			Id:              eventId,
+		})
+		if err != nil {
+			return false, fmt.Errorf("%w(id: %s): %w", ErrFailedToUpdateRecord, eventId, err)
+		}
+
+		return affected > 0, nil
+	})
+}
+
+// Rename gives an event a new slug. Links to the previous slug keep working,
+// see slugs.Service. Authorization is left to the caller, see
+// EnsureOrganizer.
+func (s *Service) Rename(ctx context.Context, eventId string, slug string) (*Event, error) {
+	return s.update(ctx, eventId, func(ctx context.Context, before *Event) (bool, error) {
+		if before.Slug == slug {
+			return false, nil
+		}
+
+		_, err := s.slugs.Rename(ctx, slugs.KindEvent, eventId, slug)
+		if err != nil {
+			return false, err //nolint:wrapcheck
+		}
+
+		return true, nil
+	})
+}
+
+// SetTags replaces the tags of an event with the given names. Authorization
+// is left to the caller, see EnsureOrganizer.
+func (s *Service) SetTags(ctx context.Context, eventId string, names []string) ([]*tags.TagRef, error) {
+	var after []*tags.TagRef
+
+	err := s.repo.Transact(ctx, func(ctx context.Context) error {
+		current, err := s.tagger.TagsOf(ctx, tags.KindEvent, []string{eventId})
+		if err != nil {
+			return err //nolint:wrapcheck
+		}
+
+		after, err = s.tagger.SetTags(ctx, tags.KindEvent, eventId, names)
+		if err != nil {
+			return err //nolint:wrapcheck
+		}
+
+		tagSlugs := make([]string, 0, len(after))
+		for _, ref := range after {
+			tagSlugs = append(tagSlugs, ref.Slug)
+		}
+
+		return s.events.Record(ctx, AggregateEvent, eventId, EventTagsChanged, &TagsChangedEvent{ //nolint:wrapcheck
+			EventId: eventId,
+			Tags:    tagSlugs,
+			before:  current[eventId],
+			after:   after,
+		})
+	})
+	if err != nil {
+		return nil, err //nolint:wrapcheck
+	}
+
+	return after, nil
+}
+
+// update applies a change to an event and records EventEventUpdated with its
+// states before and after, read within the transaction making it. change
+// reports whether it changed anything; when it didn't, nothing is recorded.
+func (s *Service) update(
+	ctx context.Context,
+	eventId string,
+	change func(ctx context.Context, before *Event) (bool, error),
+) (*Event, error) {
+	var after *Event
+
+	err := s.repo.Transact(ctx, func(ctx context.Context) error {
+		before, err := s.GetById(ctx, eventId)
+		if err != nil {
+			return err
+		}
+
+		changed, err := change(ctx, before)
+		if err != nil {
+			return err
+		}
+
+		after, err = s.GetById(ctx, eventId)
+		if err != nil || !changed {
+			return err
+		}
+
+		previousSlug := ""
+		if after.Slug != before.Slug {
+			previousSlug = before.Slug
+		}
+
+		return s.events.Record(ctx, AggregateEvent, eventId, EventEventUpdated, &EventUpdatedEvent{ //nolint:wrapcheck
+			EventId:      eventId,
+			Slug:         after.Slug,
+			PreviousSlug: previousSlug,
+			before:       before,
+			after:        after,
+		})
+	})
+	if err != nil {
+		return nil, err //nolint:wrapcheck
+	}
+
+	return after, nil
+}
+
+// EnsureOrganizer returns ErrNotOrganizer unless the user organizes the event
+// through one of their profiles, and users.ErrUserSuspended for suspended
+// users.
+func (s *Service) EnsureOrganizer(ctx context.Context, eventId string, userId string) error {
+	isSuspended, err := s.repo.IsUserSuspended(ctx, userId)
+	if err != nil {
+		return fmt.Errorf("%w(user: %s): %w", ErrFailedToGetRecord, userId, err)
+	}
+
+	if isSuspended {
+		return fmt.Errorf("%w(id: %s)", users.ErrUserSuspended, userId)
+	}
+
+	isOrganizer, err := s.repo.IsEventAttendeeOfKindForUser(ctx, IsEventAttendeeOfKindForUserParams{
+		EventId: eventId,
+		Kind:    AttendanceKindOrganizer,
+		UserId:  userId,
+	})
+	if err != nil {
+		return fmt.Errorf("%w(event: %s): %w", ErrFailedToCheckOrganizerState, eventId, err)
+	}
+
+	if !isOrganizer {
+		return fmt.Errorf("%w(event: %s, user: %s)", ErrNotOrganizer, eventId, userId)
+	}
+
+	return nil
+}
+
+// MaterializeRecurring creates the occurrences of the recurring event series
+// that start within the horizon. Each occurrence is a draft copy of the latest
+// event of its series, shifted by the recurrence interval, so organizers can
+// adjust the details before publishing it. Series without any event yet have
+// nothing to copy from and are skipped. It returns the number of created
+// events.
+func (s *Service) MaterializeRecurring(ctx context.Context, horizon time.Duration) (int, error) {
+	series, err := s.repo.ListRecurringEventSeries(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("%w: %w", ErrFailedToListRecords, err)
+	}
+
+	now := time.Now()
+	created := 0
+
+	for _, record := range series {
+		count, err := s.materializeSeries(ctx, record, now, now.Add(horizon))
+		created += count
+
+		if err != nil {
+			return created, err
+		}
+	}
+
+	return created, nil
+}
+
+func (s *Service) materializeSeries(
+	ctx context.Context,
+	series *EventSeries,
+	now time.Time,
+	until time.Time,
+) (int, error) {
+	seriesId := sql.NullString{String: series.Id, Valid: true}
+
+	latest, err := s.repo.GetLatestEventOfSeries(ctx, seriesId)
+	if err != nil {
+		return 0, fmt.Errorf("%w(series: %s): %w", ErrFailedToGetRecord, series.Id, err)
+	}
+
+	if latest == nil {
+		return 0, nil
+	}
+
+	if series.RecurrenceUntil.Valid && series.RecurrenceUntil.Time.Before(until) {
+		until = series.RecurrenceUntil.Time
+	}
+
+	interval := time.Duration(series.RecurrenceIntervalDays.Int32) * hoursPerDay * time.Hour
+	duration := latest.TimeEnd.Sub(latest.TimeStart)
+	created := 0
+
+	// every occurrence starts after the latest event, so running this again
+	// only picks up where the previous run stopped. occurrences missed while
+	// the series was dormant are not created retroactively.
+	start := latest.TimeStart.Add(interval)
+	for start.Before(now) {
+		start = start.Add(interval)
+	}
+
+	err = s.repo.Transact(ctx, func(ctx context.Context) error {
+		for ; !start.After(until); start = start.Add(interval) {
+			record, err := s.repo.CreateEvent(ctx, CreateEventParams{
+				Id:              string(s.idGenerator()),
+				Kind:            latest.Kind,
+				Slug:            series.Slug + "-" + start.Format(occurrenceSlugDateLayout),
+				EventPictureUri: latest.EventPictureUri,
+				Title:           latest.Title,
+				Description:     latest.Description,
+				TimeStart:       start,
+				TimeEnd:         start.Add(duration),
+				SeriesId:        seriesId,
+				Status:          StatusDraft,
+			})
+			if err != nil {
+				return fmt.Errorf("%w(series: %s): %w", ErrFailedToCreateRecord, series.Id, err)
+			}
+
+			err = s.events.Record(ctx, AggregateEvent, record.Id, EventEventCreated, &EventCreatedEvent{
+				EventId:  record.Id,
+				Slug:     record.Slug,
+				SeriesId: series.Id,
+				Status:   record.Status,
+				after:    record,
+			})
+			if err != nil {
+				return err //nolint:wrapcheck
+			}
+
+			created++
+		}
+
+		return nil
+	})
+	if err != nil {
+		return 0, err //nolint:wrapcheck
+	}
+
+	return created, nil
+}
+
+// recordRsvpChange records EventRsvpChanged for an attendance changed from
+// before, reading the state it left within the transaction making it.
+func (s *Service) recordRsvpChange(
+	ctx context.Context,
+	before *EventAttendance,
+	eventId string,
+	profileId string,
+	previousKind string,
+) error {
+	after, err := s.getAttendance(ctx, eventId, profileId)
+	if err != nil {
+		return err
+	}
+
+	return s.events.Record(ctx, AggregateEvent, eventId, EventRsvpChanged, &RsvpChangedEvent{ //nolint:wrapcheck
+		EventId:      eventId,
+		ProfileId:    profileId,
+		Kind:         after.Kind,
+		PreviousKind: previousKind,
+		before:       before,
+		after:        after,
+	})
+}
+
+func (s *Service) getAttendance(ctx context.Context, eventId string, profileId string) (*EventAttendance, error) {
+	attendance, err := s.repo.GetEventAttendance(ctx, GetEventAttendanceParams{
+		EventId:   eventId,
+		ProfileId: profileId,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%w(event: %s, profile: %s): %w", ErrFailedToGetRecord, eventId, profileId, err)
+	}
+
+	if attendance == nil {
+		return nil, fmt.Errorf("%w(event: %s, profile: %s)", ErrNotAttending, eventId, profileId)
+	}
+
+	return attendance, nil
+}
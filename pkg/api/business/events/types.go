@@ -0,0 +1,163 @@
+package events
+
+import (
+	"time"
+
+	"github.com/eser/acik.io/pkg/api/business/tags"
+	"github.com/oklog/ulid/v2"
+)
+
+const (
+	StatusDraft     = "draft"
+	StatusPublished = "published"
+
+	AttendanceKindOrganizer = "organizer"
+	AttendanceKindRsvp      = "rsvp"
+	AttendanceKindAttended  = "attended"
+	// AttendanceKindCancelled is left behind by attendees who withdrew their
+	// RSVP, so RSVP'ing again revives the same attendance.
+	AttendanceKindCancelled = "cancelled"
+
+	AggregateEvent = "event"
+
+	EventEventCreated     = "event.created"
+	EventEventUpdated     = "event.updated"
+	EventEventPublished   = "event.published"
+	EventRsvpChanged      = "event.rsvp-changed"
+	EventEventRescheduled = "event.rescheduled"
+	EventTagsChanged      = "event.tags-changed"
+
+	QueueMaterializeRecurring = "events.materialize-recurring"
+)
+
+type RecordID string
+
+type RecordIDGenerator func() RecordID
+
+func DefaultIDGenerator() RecordID {
+	return RecordID(ulid.Make().String())
+}
+
+type CheckInCodeResponse struct {
+	ExpiresAt time.Time `json:"expiresAt"`
+	EventId   string    `json:"eventId"`
+	ProfileId string    `json:"profileId"`
+	Code      string    `json:"code"`
+}
+
+type CheckInRequest struct {
+	Code string `json:"code"`
+}
+
+// RescheduleInput moves an event to a new time.
+type RescheduleInput struct {
+	TimeStart time.Time `json:"timeStart"`
+	TimeEnd   time.Time `json:"timeEnd"`
+}
+
+// RsvpChangedEvent is the payload of EventRsvpChanged, recorded whenever the
+// attendance of a profile to an event changes kind.
+type RsvpChangedEvent struct {
+	EventId      string `json:"eventId"`
+	ProfileId    string `json:"profileId"`
+	Kind         string `json:"kind"`
+	PreviousKind string `json:"previousKind"`
+
+	before *EventAttendance
+	after  *EventAttendance
+}
+
+// AuditStates logs the attendance before and after the change. before is nil
+// for first RSVPs.
+func (e *RsvpChangedEvent) AuditStates() (any, any) {
+	return e.before, e.after
+}
+
+// MaterializeRecurringJob is enqueued to create the upcoming occurrences of
+// the recurring event series, as drafts for the organizers to review.
+type MaterializeRecurringJob struct {
+	Horizon time.Duration `json:"horizon"`
+}
+
+// EventCreatedEvent is the payload of EventEventCreated.
+type EventCreatedEvent struct {
+	EventId  string `json:"eventId"`
+	Slug     string `json:"slug"`
+	SeriesId string `json:"seriesId,omitempty"`
+	Status   string `json:"status"`
+
+	after *Event
+}
+
+// AuditStates logs the event as created.
+func (e *EventCreatedEvent) AuditStates() (any, any) {
+	return nil, e.after
+}
+
+// EventUpdatedEvent is the payload of EventEventUpdated. PreviousSlug is set
+// when the event was renamed.
+type EventUpdatedEvent struct {
+	EventId      string `json:"eventId"`
+	Slug         string `json:"slug"`
+	PreviousSlug string `json:"previousSlug,omitempty"`
+
+	before *Event
+	after  *Event
+}
+
+// AuditStates logs the full event before and after the change.
+func (e *EventUpdatedEvent) AuditStates() (any, any) {
+	return e.before, e.after
+}
+
+// TagsChangedEvent is the payload of EventTagsChanged.
+type TagsChangedEvent struct {
+	EventId string   `json:"eventId"`
+	Tags    []string `json:"tags"`
+
+	before []*tags.TagRef
+	after  []*tags.TagRef
+}
+
+// AuditStates logs the tags of the event before and after the change.
+func (e *TagsChangedEvent) AuditStates() (any, any) {
+	return e.before, e.after
+}
+
+// EventPublishedEvent is the payload of EventEventPublished.
+type EventPublishedEvent struct {
+	TimeStart   time.Time `json:"timeStart"`
+	TimeEnd     time.Time `json:"timeEnd"`
+	PublishedAt time.Time `json:"publishedAt"`
+	EventId     string    `json:"eventId"`
+	Slug        string    `json:"slug"`
+	Title       string    `json:"title"`
+
+	before *Event
+	after  *Event
+}
+
+// AuditStates logs the full event before and after it was published.
+func (e *EventPublishedEvent) AuditStates() (any, any) {
+	return e.before, e.after
+}
+
+// EventRescheduledEvent is the payload of EventEventRescheduled, recorded
+// whenever the time of an event changes.
+type EventRescheduledEvent struct {
+	TimeStart         time.Time `json:"timeStart"`
+	TimeEnd           time.Time `json:"timeEnd"`
+	PreviousTimeStart time.Time `json:"previousTimeStart"`
+	PreviousTimeEnd   time.Time `json:"previousTimeEnd"`
+	EventId           string    `json:"eventId"`
+	Slug              string    `json:"slug"`
+	Title             string    `json:"title"`
+
+	before *Event
+	after  *Event
+}
+
+// AuditStates logs the full event before and after it was rescheduled.
+func (e *EventRescheduledEvent) AuditStates() (any, any) {
+	return e.before, e.after
+}
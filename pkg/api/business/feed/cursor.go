@@ -0,0 +1,42 @@
+package feed
+
+import (
+	"encoding/base64"
+	"errors"
+	"strings"
+	"time"
+)
+
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// Cursor points at the last item of a page; the next page starts right
+// after it. Items are ordered by their publication time, ties broken by id.
+type Cursor struct {
+	PublishedAt time.Time
+	Id          string
+}
+
+func (c *Cursor) Encode() string {
+	raw := c.PublishedAt.UTC().Format(time.RFC3339Nano) + "|" + c.Id
+
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+func DecodeCursor(encoded string) (*Cursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+
+	publishedAt, id, found := strings.Cut(string(raw), "|")
+	if !found || id == "" {
+		return nil, ErrInvalidCursor
+	}
+
+	timestamp, err := time.Parse(time.RFC3339Nano, publishedAt)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+
+	return &Cursor{PublishedAt: timestamp, Id: id}, nil
+}
@@ -0,0 +1,59 @@
+package feed
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+)
+
+var ErrFailedToListRecords = errors.New("failed to list records")
+
+type Repository interface {
+	ListFeedItems(ctx context.Context, arg ListFeedItemsParams) ([]*ListFeedItemsRow, error)
+}
+
+type Service struct {
+	repo Repository
+}
+
+func NewService(repo Repository) *Service {
+	return &Service{repo: repo}
+}
+
+// ForUser returns a page of the stories and events published by the profiles
+// the user follows, the latest first. An empty cursor returns the first page,
+// and a non-empty tagId narrows the feed down to the items with a tag.
+func (s *Service) ForUser(ctx context.Context, userId string, tagId string, cursor string, limit int32) (*Page, error) {
+	params := ListFeedItemsParams{ //nolint:exhaustruct
+		UserId: userId,
+		TagId:  sql.NullString{String: tagId, Valid: tagId != ""},
+		// one more than asked, to tell whether there's a next page.
+		LimitCount: limit + 1,
+	}
+
+	if cursor != "" {
+		decoded, err := DecodeCursor(cursor)
+		if err != nil {
+			return nil, err
+		}
+
+		params.BeforePublishedAt = sql.NullTime{Time: decoded.PublishedAt, Valid: true}
+		params.BeforeId = sql.NullString{String: decoded.Id, Valid: true}
+	}
+
+	items, err := s.repo.ListFeedItems(ctx, params)
+	if err != nil {
+		return nil, fmt.Errorf("%w(user: %s): %w", ErrFailedToListRecords, userId, err)
+	}
+
+	page := &Page{Items: items, NextCursor: ""}
+
+	if len(items) > int(limit) {
+		page.Items = items[:limit]
+		last := page.Items[len(page.Items)-1]
+		page.NextCursor = (&Cursor{PublishedAt: last.PublishedAt.Time, Id: last.Id}).Encode()
+	}
+
+	return page, nil
+}
@@ -0,0 +1,12 @@
+package feed
+
+const (
+	KindStory = "story"
+	KindEvent = "event"
+)
+
+// Page is a page of a feed. NextCursor is empty on the last page.
+type Page struct {
+	Items      []*ListFeedItemsRow `json:"items"`
+	NextCursor string              `json:"nextCursor,omitempty"`
+}
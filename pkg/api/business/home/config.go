@@ -0,0 +1,7 @@
+package home
+
+import "time"
+
+type Config struct {
+	SectionTimeout time.Duration `conf:"SECTION_TIMEOUT" default:"750ms"` // upper bound for assembling a single section of the home page
+}
@@ -0,0 +1,162 @@
+package home
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/eser/acik.io/pkg/api/business/events"
+	"github.com/eser/acik.io/pkg/api/business/profiles"
+	"github.com/eser/acik.io/pkg/api/business/questions"
+	"github.com/eser/acik.io/pkg/api/business/stories"
+)
+
+const SectionSize = 6
+
+var ErrSectionTimedOut = errors.New("section timed out")
+
+type FeaturedStoriesSource interface {
+	ListFeatured(ctx context.Context, tagId string, limit int32) ([]*stories.Story, error)
+}
+
+type UpcomingEventsSource interface {
+	ListUpcoming(ctx context.Context, tagId string, limit int32) ([]*events.Event, error)
+}
+
+type TopQuestionsSource interface {
+	ListTopUnanswered(ctx context.Context, limit int32) ([]*questions.Question, error)
+}
+
+type NewestProfilesSource interface {
+	ListNewest(ctx context.Context, limit int32) ([]*profiles.Profile, error)
+}
+
+type Sources struct {
+	FeaturedStories FeaturedStoriesSource
+	UpcomingEvents  UpcomingEventsSource
+	TopQuestions    TopQuestionsSource
+	NewestProfiles  NewestProfilesSource
+}
+
+type Service struct {
+	config  *Config
+	sources *Sources
+}
+
+func NewService(config *Config, sources *Sources) *Service {
+	return &Service{config: config, sources: sources}
+}
+
+// GetPage assembles every section concurrently. Each section gets its own
+// deadline; one that fails or is too slow is reported as unavailable instead
+// of holding up or failing the whole page. The returned errors describe the
+// unavailable sections and are meant for logging.
+func (s *Service) GetPage(ctx context.Context) (*Page, []error) {
+	page := &Page{
+		FeaturedStories:     []*stories.Story{},
+		UpcomingEvents:      []*events.Event{},
+		TopQuestions:        []*questions.Question{},
+		NewestProfiles:      []*profiles.Profile{},
+		UnavailableSections: []string{},
+	}
+
+	assembly := &assembly{
+		timeout:  s.config.SectionTimeout,
+		wg:       sync.WaitGroup{},
+		mu:       sync.Mutex{},
+		page:     page,
+		problems: nil,
+	}
+
+	runSection(ctx, assembly, SectionFeaturedStories, anyTag(s.sources.FeaturedStories.ListFeatured), &page.FeaturedStories)
+	runSection(ctx, assembly, SectionUpcomingEvents, anyTag(s.sources.UpcomingEvents.ListUpcoming), &page.UpcomingEvents)
+	runSection(ctx, assembly, SectionTopQuestions, s.sources.TopQuestions.ListTopUnanswered, &page.TopQuestions)
+	runSection(ctx, assembly, SectionNewestProfiles, s.sources.NewestProfiles.ListNewest, &page.NewestProfiles)
+
+	assembly.wg.Wait()
+
+	slices.Sort(page.UnavailableSections)
+
+	return page, assembly.problems
+}
+
+type assembly struct {
+	page     *Page
+	problems []error
+	timeout  time.Duration
+
+	wg sync.WaitGroup
+	mu sync.Mutex
+}
+
+// anyTag adapts a listing that can be narrowed down to a tag into one of the
+// records with any tag.
+func anyTag[T any](
+	fetch func(ctx context.Context, tagId string, limit int32) ([]T, error),
+) func(ctx context.Context, limit int32) ([]T, error) {
+	return func(ctx context.Context, limit int32) ([]T, error) {
+		return fetch(ctx, "", limit)
+	}
+}
+
+// runSection fetches a section in the background and stores it into target
+// once it arrives within the deadline. A fetch that overruns is abandoned;
+// its result is discarded rather than written into the page.
+func runSection[T any](
+	ctx context.Context,
+	assembly *assembly,
+	section string,
+	fetch func(ctx context.Context, limit int32) ([]T, error),
+	target *[]T,
+) {
+	assembly.wg.Add(1)
+
+	go func() {
+		defer assembly.wg.Done()
+
+		records, err := fetchWithTimeout(ctx, assembly.timeout, fetch)
+
+		assembly.mu.Lock()
+		defer assembly.mu.Unlock()
+
+		if err != nil {
+			assembly.page.UnavailableSections = append(assembly.page.UnavailableSections, section)
+			assembly.problems = append(assembly.problems, fmt.Errorf("%s: %w", section, err))
+
+			return
+		}
+
+		*target = records
+	}()
+}
+
+func fetchWithTimeout[T any](
+	ctx context.Context,
+	timeout time.Duration,
+	fetch func(ctx context.Context, limit int32) ([]T, error),
+) ([]T, error) {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	type result struct {
+		err     error
+		records []T
+	}
+
+	done := make(chan result, 1)
+
+	go func() {
+		records, err := fetch(ctx, SectionSize)
+		done <- result{err: err, records: records}
+	}()
+
+	select {
+	case res := <-done:
+		return res.records, res.err
+	case <-ctx.Done():
+		return nil, fmt.Errorf("%w: %w", ErrSectionTimedOut, ctx.Err())
+	}
+}
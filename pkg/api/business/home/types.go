@@ -0,0 +1,26 @@
+package home
+
+import (
+	"github.com/eser/acik.io/pkg/api/business/events"
+	"github.com/eser/acik.io/pkg/api/business/profiles"
+	"github.com/eser/acik.io/pkg/api/business/questions"
+	"github.com/eser/acik.io/pkg/api/business/stories"
+)
+
+const (
+	SectionFeaturedStories = "featuredStories"
+	SectionUpcomingEvents  = "upcomingEvents"
+	SectionTopQuestions    = "topQuestions"
+	SectionNewestProfiles  = "newestProfiles"
+)
+
+// Page is the aggregated home page. A section that failed or ran out of time
+// is left empty and listed in UnavailableSections, so clients can tell it
+// apart from a section with nothing to show.
+type Page struct {
+	FeaturedStories     []*stories.Story      `json:"featuredStories"`
+	UpcomingEvents      []*events.Event       `json:"upcomingEvents"`
+	TopQuestions        []*questions.Question `json:"topQuestions"`
+	NewestProfiles      []*profiles.Profile   `json:"newestProfiles"`
+	UnavailableSections []string              `json:"unavailableSections"`
+}
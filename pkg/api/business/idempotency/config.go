@@ -0,0 +1,8 @@
+package idempotency
+
+import "time"
+
+type Config struct {
+	Ttl     time.Duration `conf:"TTL" default:"24h"`     // how long a response is replayed to retries of its request
+	LockTtl time.Duration `conf:"LOCK_TTL" default:"1m"` // how long a request being handled keeps its key from retries, taken over after that
+}
@@ -0,0 +1,139 @@
+package idempotency
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+var (
+	ErrFailedToClaim        = errors.New("failed to claim idempotency key")
+	ErrFailedToUpdateRecord = errors.New("failed to update record")
+	ErrFailedToSweep        = errors.New("failed to sweep idempotency keys")
+	ErrKeyReused            = errors.New("idempotency key is already used for another request")
+	ErrInFlight             = errors.New("a request with the idempotency key is still being handled")
+)
+
+type Repository interface {
+	ClaimIdempotencyKey(ctx context.Context, arg ClaimIdempotencyKeyParams) (*IdempotencyKey, error)
+	GetIdempotencyKey(ctx context.Context, arg GetIdempotencyKeyParams) (*IdempotencyKey, error)
+	CompleteIdempotencyKey(ctx context.Context, arg CompleteIdempotencyKeyParams) (int64, error)
+	ReleaseIdempotencyKey(ctx context.Context, arg ReleaseIdempotencyKeyParams) (int64, error)
+	DeleteExpiredIdempotencyKeys(ctx context.Context) (int64, error)
+}
+
+type Service struct {
+	config *Config
+	repo   Repository
+}
+
+func NewService(config *Config, repo Repository) *Service {
+	return &Service{config: config, repo: repo}
+}
+
+// Begin claims a key of a scope for a request. It returns the stored
+// response when the request was handled before, and nothing when the
+// caller is to handle it and then Complete or Release the key. Keys still
+// being handled, or used for another request, are refused. A key whose
+// handler didn't finish within the lock ttl is taken over by the next
+// retry.
+func (s *Service) Begin(ctx context.Context, scope string, key string, fingerprint string) (*Response, error) {
+	record, err := s.repo.ClaimIdempotencyKey(ctx, ClaimIdempotencyKeyParams{
+		Scope:       scope,
+		Key:         key,
+		Fingerprint: fingerprint,
+		LockSeconds: s.config.LockTtl.Seconds(),
+		TtlSeconds:  s.config.Ttl.Seconds(),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%w(key: %s): %w", ErrFailedToClaim, key, err)
+	}
+
+	if record != nil {
+		return nil, nil //nolint:nilnil
+	}
+
+	record, err = s.repo.GetIdempotencyKey(ctx, GetIdempotencyKeyParams{Scope: scope, Key: key})
+	if err != nil {
+		return nil, fmt.Errorf("%w(key: %s): %w", ErrFailedToClaim, key, err)
+	}
+
+	switch {
+	case record == nil:
+		// released or swept in the meantime, the retry can claim it.
+		return nil, fmt.Errorf("%w(key: %s)", ErrInFlight, key)
+	case record.Fingerprint != fingerprint:
+		return nil, fmt.Errorf("%w(key: %s)", ErrKeyReused, key)
+	case record.Status != StatusCompleted:
+		return nil, fmt.Errorf("%w(key: %s)", ErrInFlight, key)
+	}
+
+	response := &Response{
+		Headers:    map[string]string{},
+		Body:       record.ResponseBody,
+		StatusCode: int(record.ResponseStatus.Int32),
+	}
+
+	err = json.Unmarshal(record.ResponseHeaders, &response.Headers)
+	if err != nil {
+		return nil, fmt.Errorf("%w(key: %s): %w", ErrFailedToClaim, key, err)
+	}
+
+	return response, nil
+}
+
+// Complete stores the response of a request claimed with Begin.
+func (s *Service) Complete(
+	ctx context.Context,
+	scope string,
+	key string,
+	fingerprint string,
+	response *Response,
+) error {
+	headers, err := json.Marshal(response.Headers)
+	if err != nil {
+		return fmt.Errorf("%w(key: %s): %w", ErrFailedToUpdateRecord, key, err)
+	}
+
+	_, err = s.repo.CompleteIdempotencyKey(ctx, CompleteIdempotencyKeyParams{
+		ResponseStatus:  sql.NullInt32{Int32: int32(response.StatusCode), Valid: true}, //nolint:gosec
+		ResponseHeaders: headers,
+		ResponseBody:    response.Body,
+		Scope:           scope,
+		Key:             key,
+		Fingerprint:     fingerprint,
+	})
+	if err != nil {
+		return fmt.Errorf("%w(key: %s): %w", ErrFailedToUpdateRecord, key, err)
+	}
+
+	return nil
+}
+
+// Release frees a key claimed with Begin without storing a response, so the
+// request can be retried, as after a server error.
+func (s *Service) Release(ctx context.Context, scope string, key string, fingerprint string) error {
+	_, err := s.repo.ReleaseIdempotencyKey(ctx, ReleaseIdempotencyKeyParams{
+		Scope:       scope,
+		Key:         key,
+		Fingerprint: fingerprint,
+	})
+	if err != nil {
+		return fmt.Errorf("%w(key: %s): %w", ErrFailedToUpdateRecord, key, err)
+	}
+
+	return nil
+}
+
+// Sweep deletes the keys past their ttl. It returns the number of keys
+// deleted.
+func (s *Service) Sweep(ctx context.Context) (int64, error) {
+	deleted, err := s.repo.DeleteExpiredIdempotencyKeys(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("%w: %w", ErrFailedToSweep, err)
+	}
+
+	return deleted, nil
+}
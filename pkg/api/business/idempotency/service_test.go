@@ -0,0 +1,191 @@
+package idempotency_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/eser/acik.io/pkg/api/business/idempotency"
+)
+
+// repository keeps the keys in memory. Locks never expire in it.
+type repository struct {
+	keys map[string]*idempotency.IdempotencyKey
+}
+
+func (r *repository) ClaimIdempotencyKey(
+	_ context.Context,
+	arg idempotency.ClaimIdempotencyKeyParams,
+) (*idempotency.IdempotencyKey, error) {
+	if _, ok := r.keys[arg.Scope+"/"+arg.Key]; ok {
+		return nil, nil //nolint:nilnil
+	}
+
+	record := &idempotency.IdempotencyKey{ //nolint:exhaustruct
+		Scope:       arg.Scope,
+		Key:         arg.Key,
+		Fingerprint: arg.Fingerprint,
+		Status:      idempotency.StatusInFlight,
+	}
+	r.keys[arg.Scope+"/"+arg.Key] = record
+
+	return record, nil
+}
+
+func (r *repository) GetIdempotencyKey(
+	_ context.Context,
+	arg idempotency.GetIdempotencyKeyParams,
+) (*idempotency.IdempotencyKey, error) {
+	return r.keys[arg.Scope+"/"+arg.Key], nil
+}
+
+func (r *repository) CompleteIdempotencyKey(_ context.Context, arg idempotency.CompleteIdempotencyKeyParams) (int64, error) {
+	record, ok := r.keys[arg.Scope+"/"+arg.Key]
+	if !ok || record.Fingerprint != arg.Fingerprint {
+		return 0, nil
+	}
+
+	record.Status = idempotency.StatusCompleted
+	record.ResponseStatus = arg.ResponseStatus
+	record.ResponseHeaders = arg.ResponseHeaders
+	record.ResponseBody = arg.ResponseBody
+
+	return 1, nil
+}
+
+func (r *repository) ReleaseIdempotencyKey(_ context.Context, arg idempotency.ReleaseIdempotencyKeyParams) (int64, error) {
+	record, ok := r.keys[arg.Scope+"/"+arg.Key]
+	if !ok || record.Fingerprint != arg.Fingerprint || record.Status != idempotency.StatusInFlight {
+		return 0, nil
+	}
+
+	delete(r.keys, arg.Scope+"/"+arg.Key)
+
+	return 1, nil
+}
+
+func (r *repository) DeleteExpiredIdempotencyKeys(context.Context) (int64, error) {
+	return 0, nil
+}
+
+func newService() *idempotency.Service {
+	return idempotency.NewService(
+		&idempotency.Config{Ttl: time.Hour, LockTtl: time.Minute},
+		&repository{keys: map[string]*idempotency.IdempotencyKey{}},
+	)
+}
+
+func TestBeginReplaysCompletedResponse(t *testing.T) {
+	t.Parallel()
+
+	service := newService()
+	ctx := context.Background()
+
+	response, err := service.Begin(ctx, "user", "key", "request")
+	if err != nil || response != nil {
+		t.Fatalf("got %+v, %v, want the key claimed", response, err)
+	}
+
+	_, err = service.Begin(ctx, "user", "key", "request")
+	if !errors.Is(err, idempotency.ErrInFlight) {
+		t.Errorf("got %v while handled, want %v", err, idempotency.ErrInFlight)
+	}
+
+	err = service.Complete(ctx, "user", "key", "request", &idempotency.Response{
+		Headers:    map[string]string{"Content-Type": "application/json"},
+		Body:       []byte(`{"id":"1"}`),
+		StatusCode: http.StatusCreated,
+	})
+	if err != nil {
+		t.Fatalf("completing: %v", err)
+	}
+
+	response, err = service.Begin(ctx, "user", "key", "request")
+	if err != nil {
+		t.Fatalf("retrying: %v", err)
+	}
+
+	if response == nil || response.StatusCode != http.StatusCreated || string(response.Body) != `{"id":"1"}` || response.Headers["Content-Type"] != "application/json" {
+		t.Errorf("got %+v, want the stored response", response)
+	}
+
+	_, err = service.Begin(ctx, "user", "key", "another request")
+	if !errors.Is(err, idempotency.ErrKeyReused) {
+		t.Errorf("got %v for another request, want %v", err, idempotency.ErrKeyReused)
+	}
+
+	response, err = service.Begin(ctx, "another user", "key", "another request")
+	if err != nil || response != nil {
+		t.Errorf("got %+v, %v, want keys kept apart per scope", response, err)
+	}
+}
+
+func TestReleaseAllowsRetry(t *testing.T) {
+	t.Parallel()
+
+	service := newService()
+	ctx := context.Background()
+
+	_, err := service.Begin(ctx, "user", "key", "request")
+	if err != nil {
+		t.Fatalf("claiming: %v", err)
+	}
+
+	err = service.Release(ctx, "user", "key", "request")
+	if err != nil {
+		t.Fatalf("releasing: %v", err)
+	}
+
+	response, err := service.Begin(ctx, "user", "key", "request")
+	if err != nil || response != nil {
+		t.Errorf("got %+v, %v, want the key claimed again", response, err)
+	}
+}
+
+func TestIsValidKey(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		key  string
+		want bool
+	}{
+		{key: "0b4f5c1e-7f39-4f0c-9a9b-3c4d2e1f0a6b", want: true},
+		{key: "", want: false},
+		{key: strings.Repeat("k", idempotency.MaxKeyLength), want: true},
+		{key: strings.Repeat("k", idempotency.MaxKeyLength+1), want: false},
+		{key: "key\n", want: false},
+		{key: "anahtar-ş", want: false},
+	}
+
+	for _, test := range tests {
+		if got := idempotency.IsValidKey(test.key); got != test.want {
+			t.Errorf("got %t for %q, want %t", got, test.key, test.want)
+		}
+	}
+}
+
+func TestFingerprint(t *testing.T) {
+	t.Parallel()
+
+	fingerprint := idempotency.Fingerprint(httptest.NewRequest(http.MethodPost, "/questions?x=1", nil), []byte("body"))
+
+	if got := idempotency.Fingerprint(httptest.NewRequest(http.MethodPost, "/questions?x=2", nil), []byte("body")); got != fingerprint {
+		t.Errorf("got another fingerprint for another query string, want only the path covered")
+	}
+
+	others := []string{
+		idempotency.Fingerprint(httptest.NewRequest(http.MethodPut, "/questions", nil), []byte("body")),
+		idempotency.Fingerprint(httptest.NewRequest(http.MethodPost, "/reports", nil), []byte("body")),
+		idempotency.Fingerprint(httptest.NewRequest(http.MethodPost, "/questions", nil), []byte("other body")),
+	}
+
+	for _, other := range others {
+		if other == fingerprint {
+			t.Errorf("got the same fingerprint for another request")
+		}
+	}
+}
@@ -0,0 +1,47 @@
+package idempotency
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+)
+
+const (
+	StatusInFlight  = "in_flight"
+	StatusCompleted = "completed"
+
+	MaxKeyLength = 255
+)
+
+// Response is what is replayed to the retries of a request.
+type Response struct {
+	Headers    map[string]string
+	Body       []byte
+	StatusCode int
+}
+
+// IsValidKey reports whether a client supplied key is usable: printable
+// ascii, up to MaxKeyLength bytes.
+func IsValidKey(key string) bool {
+	if key == "" || len(key) > MaxKeyLength {
+		return false
+	}
+
+	for i := range len(key) {
+		if key[i] < ' ' || key[i] > '~' {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Fingerprint identifies a request, so a key can't be reused for another
+// one. It covers the method, the path and the body.
+func Fingerprint(req *http.Request, body []byte) string {
+	hash := sha256.New()
+	hash.Write([]byte(req.Method + " " + req.URL.Path + "\n"))
+	hash.Write(body)
+
+	return hex.EncodeToString(hash.Sum(nil))
+}
@@ -0,0 +1,21 @@
+package media
+
+import "time"
+
+type Config struct {
+	Backend        string        `conf:"BACKEND" default:"local"`                           // where uploads are stored: local or s3
+	PublicUrl      string        `conf:"PUBLIC_URL" default:"http://localhost:8080/media/"` // base address stored keys are served from
+	LocalDir       string        `conf:"LOCAL_DIR" default:"./tmp/media"`                   // directory of the local backend
+	S3Endpoint     string        `conf:"S3_ENDPOINT" default:"http://localhost:9000"`       // address of the S3-compatible service
+	S3Region       string        `conf:"S3_REGION" default:"us-east-1"`
+	S3Bucket       string        `conf:"S3_BUCKET" default:"media"`
+	S3AccessKey    string        `conf:"S3_ACCESS_KEY"`
+	S3SecretKey    string        `conf:"S3_SECRET_KEY"`
+	S3PathStyle    bool          `conf:"S3_PATH_STYLE" default:"true"`  // address buckets by path rather than by host, as MinIO expects
+	RequestTimeout time.Duration `conf:"REQUEST_TIMEOUT" default:"30s"` // time limit of a request to the S3 backend
+
+	ProxyBaseUrl        string        `conf:"PROXY_BASE_URL" default:"http://localhost:8080/img/"`                                                         // base address of the image proxy
+	ProxySecret         string        `conf:"PROXY_SECRET"`                                                                                                // signs proxy addresses, the proxy is off without it
+	ProxyAllowedHosts   string        `conf:"PROXY_ALLOWED_HOSTS" default:"avatars.githubusercontent.com,pbs.twimg.com,abs.twimg.com,secure.gravatar.com"` // comma-separated hosts the proxy fetches from
+	ProxyRequestTimeout time.Duration `conf:"PROXY_REQUEST_TIMEOUT" default:"10s"`                                                                         // time limit of fetching a remote image
+}
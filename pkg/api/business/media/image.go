@@ -0,0 +1,346 @@
+package media
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"image"
+	"image/color"
+	"image/gif"
+	"image/jpeg"
+	"image/png"
+	"math"
+
+	"golang.org/x/image/draw"
+	"golang.org/x/image/webp"
+)
+
+const (
+	FormatJpeg = "jpeg"
+	FormatPng  = "png"
+	FormatGif  = "gif"
+	FormatWebp = "webp"
+
+	jpegQuality = 85
+
+	exifOrientationTag = 0x0112
+)
+
+// Sniff tells the format of an image by its magic bytes, regardless of what
+// the uploader claims it is.
+func Sniff(data []byte) (string, error) {
+	switch {
+	case bytes.HasPrefix(data, []byte{0xFF, 0xD8, 0xFF}):
+		return FormatJpeg, nil
+	case bytes.HasPrefix(data, []byte("\x89PNG\r\n\x1a\n")):
+		return FormatPng, nil
+	case bytes.HasPrefix(data, []byte("GIF87a")), bytes.HasPrefix(data, []byte("GIF89a")):
+		return FormatGif, nil
+	case len(data) >= 12 && string(data[:4]) == "RIFF" && string(data[8:12]) == "WEBP":
+		return FormatWebp, nil
+	default:
+		return "", ErrUnsupportedType
+	}
+}
+
+// OutputFormat is the format variants of an upload are encoded in. Photos stay
+// JPEG, while graphics keep their transparency as PNG. Only the first frame of
+// animated GIFs is kept.
+func OutputFormat(format string) string {
+	if format == FormatJpeg || format == FormatWebp {
+		return FormatJpeg
+	}
+
+	return FormatPng
+}
+
+func ContentTypeOf(format string) string {
+	return "image/" + format
+}
+
+// renderVariants decodes an upload and encodes each of the variants. Since
+// variants are encoded from pixels alone, EXIF and any other metadata of the
+// upload is left behind; the EXIF orientation of JPEGs is applied first so
+// the pictures still appear upright.
+func renderVariants(data []byte, format string, variants []*Variant) (map[string][]byte, error) {
+	decoded, err := decode(data, format)
+	if err != nil {
+		return nil, err
+	}
+
+	// bounding the image first keeps reorienting it cheap. The bounds are
+	// square so they hold either way round; cropped variants need the short
+	// side of the image to cover them, so they widen the bounds.
+	bounds := decoded.Bounds()
+	longSide, shortSide := max(bounds.Dx(), bounds.Dy()), min(bounds.Dx(), bounds.Dy())
+
+	maxSide := 0
+	for _, variant := range variants {
+		side := max(variant.Width, variant.Height)
+		if variant.Crop {
+			side = (side*longSide + shortSide - 1) / shortSide
+		}
+
+		maxSide = max(maxSide, side)
+	}
+
+	base := scale(decoded, &Variant{Name: "", Width: maxSide, Height: maxSide, Crop: false})
+
+	if format == FormatJpeg {
+		base = orient(base, jpegOrientation(data))
+	}
+
+	output := OutputFormat(format)
+	result := make(map[string][]byte, len(variants))
+
+	for _, variant := range variants {
+		encoded, err := encode(scale(base, variant), output)
+		if err != nil {
+			return nil, err
+		}
+
+		result[variant.Name] = encoded
+	}
+
+	return result, nil
+}
+
+func decode(data []byte, format string) (image.Image, error) {
+	var (
+		config image.Config
+		err    error
+	)
+
+	switch format {
+	case FormatJpeg:
+		config, err = jpeg.DecodeConfig(bytes.NewReader(data))
+	case FormatPng:
+		config, err = png.DecodeConfig(bytes.NewReader(data))
+	case FormatGif:
+		config, err = gif.DecodeConfig(bytes.NewReader(data))
+	case FormatWebp:
+		config, err = webp.DecodeConfig(bytes.NewReader(data))
+	default:
+		return nil, ErrUnsupportedType
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidImage, err)
+	}
+
+	if config.Width <= 0 || config.Height <= 0 || config.Width*config.Height > MaxPixels {
+		return nil, fmt.Errorf("%w: %dx%d", ErrImageTooLarge, config.Width, config.Height)
+	}
+
+	var decoded image.Image
+
+	switch format {
+	case FormatJpeg:
+		decoded, err = jpeg.Decode(bytes.NewReader(data))
+	case FormatPng:
+		decoded, err = png.Decode(bytes.NewReader(data))
+	case FormatGif:
+		decoded, err = gif.Decode(bytes.NewReader(data))
+	case FormatWebp:
+		decoded, err = webp.Decode(bytes.NewReader(data))
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidImage, err)
+	}
+
+	return decoded, nil
+}
+
+func encode(img image.Image, format string) ([]byte, error) {
+	var buffer bytes.Buffer
+
+	if format == FormatJpeg {
+		// JPEG has no transparency; transparent pixels turn white, not black.
+		flattened := image.NewRGBA(img.Bounds())
+		draw.Draw(flattened, flattened.Bounds(), image.NewUniform(color.White), image.Point{}, draw.Src)
+		draw.Draw(flattened, flattened.Bounds(), img, img.Bounds().Min, draw.Over)
+
+		err := jpeg.Encode(&buffer, flattened, &jpeg.Options{Quality: jpegQuality})
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrFailedToProcess, err)
+		}
+
+		return buffer.Bytes(), nil
+	}
+
+	err := png.Encode(&buffer, img)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrFailedToProcess, err)
+	}
+
+	return buffer.Bytes(), nil
+}
+
+// scale renders an image into the bounds of a variant.
+func scale(img image.Image, variant *Variant) *image.NRGBA {
+	bounds := img.Bounds()
+	source := bounds
+
+	var width, height int
+
+	if variant.Crop {
+		// the largest area of the variant's aspect ratio around the center.
+		if bounds.Dx()*variant.Height > bounds.Dy()*variant.Width {
+			cropWidth := bounds.Dy() * variant.Width / variant.Height
+			x0 := bounds.Min.X + (bounds.Dx()-cropWidth)/2 //nolint:mnd
+			source = image.Rect(x0, bounds.Min.Y, x0+cropWidth, bounds.Max.Y)
+		} else {
+			cropHeight := bounds.Dx() * variant.Height / variant.Width
+			y0 := bounds.Min.Y + (bounds.Dy()-cropHeight)/2 //nolint:mnd
+			source = image.Rect(bounds.Min.X, y0, bounds.Max.X, y0+cropHeight)
+		}
+
+		width, height = fit(source.Dx(), source.Dy(), variant.Width, variant.Height)
+	} else {
+		width, height = fit(bounds.Dx(), bounds.Dy(), variant.Width, variant.Height)
+	}
+
+	if nrgba, ok := img.(*image.NRGBA); ok && source == bounds && width == bounds.Dx() && height == bounds.Dy() {
+		return nrgba
+	}
+
+	result := image.NewNRGBA(image.Rect(0, 0, width, height))
+	draw.CatmullRom.Scale(result, result.Bounds(), img, source, draw.Src, nil)
+
+	return result
+}
+
+// fit returns the size of an image scaled down to fit within bounds, keeping
+// its aspect ratio.
+func fit(width int, height int, maxWidth int, maxHeight int) (int, int) {
+	if width <= maxWidth && height <= maxHeight {
+		return width, height
+	}
+
+	ratio := math.Min(float64(maxWidth)/float64(width), float64(maxHeight)/float64(height))
+
+	return max(1, int(math.Round(float64(width)*ratio))), max(1, int(math.Round(float64(height)*ratio)))
+}
+
+// orient applies an EXIF orientation to an image.
+func orient(img *image.NRGBA, orientation int) *image.NRGBA { //nolint:cyclop
+	if orientation < 2 || orientation > 8 { //nolint:mnd
+		return img
+	}
+
+	width, height := img.Bounds().Dx(), img.Bounds().Dy()
+
+	resultWidth, resultHeight := width, height
+	if orientation >= 5 { //nolint:mnd
+		resultWidth, resultHeight = height, width
+	}
+
+	result := image.NewNRGBA(image.Rect(0, 0, resultWidth, resultHeight))
+
+	for y := range resultHeight {
+		for x := range resultWidth {
+			var sourceX, sourceY int
+
+			switch orientation {
+			case 2: // mirrored horizontally
+				sourceX, sourceY = width-1-x, y
+			case 3: // rotated 180°
+				sourceX, sourceY = width-1-x, height-1-y
+			case 4: // mirrored vertically
+				sourceX, sourceY = x, height-1-y
+			case 5: // transposed
+				sourceX, sourceY = y, x
+			case 6: // rotated 90° clockwise
+				sourceX, sourceY = y, height-1-x
+			case 7: // transversed
+				sourceX, sourceY = width-1-y, height-1-x
+			case 8: // rotated 90° counterclockwise
+				sourceX, sourceY = width-1-y, x
+			}
+
+			from := img.PixOffset(img.Bounds().Min.X+sourceX, img.Bounds().Min.Y+sourceY)
+			to := result.PixOffset(x, y)
+			copy(result.Pix[to:to+4], img.Pix[from:from+4])
+		}
+	}
+
+	return result
+}
+
+// jpegOrientation returns the EXIF orientation of a JPEG, or 1 (upright) when
+// it has none.
+func jpegOrientation(data []byte) int {
+	offset := 2
+
+	for offset+4 <= len(data) {
+		if data[offset] != 0xFF {
+			return 1
+		}
+
+		marker := data[offset+1]
+
+		// markers without a payload.
+		if marker == 0x01 || (marker >= 0xD0 && marker <= 0xD8) {
+			offset += 2
+
+			continue
+		}
+
+		// the image data starts, metadata is over.
+		if marker == 0xDA || marker == 0xD9 {
+			return 1
+		}
+
+		size := int(binary.BigEndian.Uint16(data[offset+2:]))
+		if size < 2 || offset+2+size > len(data) {
+			return 1
+		}
+
+		segment := data[offset+4 : offset+2+size]
+		if marker == 0xE1 && bytes.HasPrefix(segment, []byte("Exif\x00\x00")) {
+			return tiffOrientation(segment[6:])
+		}
+
+		offset += 2 + size
+	}
+
+	return 1
+}
+
+func tiffOrientation(tiff []byte) int {
+	if len(tiff) < 8 { //nolint:mnd
+		return 1
+	}
+
+	var order binary.ByteOrder
+
+	switch string(tiff[:2]) {
+	case "II":
+		order = binary.LittleEndian
+	case "MM":
+		order = binary.BigEndian
+	default:
+		return 1
+	}
+
+	ifd := int(order.Uint32(tiff[4:]))
+	if ifd < 8 || ifd+2 > len(tiff) {
+		return 1
+	}
+
+	count := int(order.Uint16(tiff[ifd:]))
+
+	for i := range count {
+		entry := ifd + 2 + i*12
+		if entry+12 > len(tiff) {
+			return 1
+		}
+
+		if order.Uint16(tiff[entry:]) == exifOrientationTag {
+			return int(order.Uint16(tiff[entry+8:]))
+		}
+	}
+
+	return 1
+}
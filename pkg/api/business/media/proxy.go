@@ -0,0 +1,260 @@
+package media
+
+import (
+	"cmp"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// MaxProxySide bounds the width and the height of proxied images.
+const MaxProxySide = 1024
+
+var (
+	ErrProxySecretNotConfigured = errors.New("image proxy secret is not configured")
+	ErrInvalidSignature         = errors.New("invalid image proxy signature")
+	ErrInvalidProxyParams       = errors.New("invalid image proxy parameters")
+	ErrHostNotAllowed           = errors.New("host is not allowed by the image proxy")
+	ErrRemoteNotFound           = errors.New("remote image not found")
+	ErrFailedToFetch            = errors.New("failed to fetch remote image")
+)
+
+// RemoteFetcher downloads remote images. Implementations must refuse to
+// connect to private networks.
+type RemoteFetcher interface {
+	Fetch(ctx context.Context, uri string, maxSize int64) ([]byte, error)
+}
+
+// ProxyParams tells the proxy which image to serve and at which size. Width
+// and height are the bounds of the image; when both are given, the image is
+// cropped to fill them.
+type ProxyParams struct {
+	Url    string
+	Width  int
+	Height int
+}
+
+// Proxy serves remote images, such as avatars hosted by GitHub or X, through
+// signed addresses. Readers never reach the remote hosts, and since the first
+// fetch is kept in the blob store, images outlive renames at their origin.
+type Proxy struct {
+	config  *Config
+	store   BlobStore
+	fetcher RemoteFetcher
+}
+
+func NewProxy(config *Config, store BlobStore, fetcher RemoteFetcher) *Proxy {
+	return &Proxy{config: config, store: store, fetcher: fetcher}
+}
+
+// Rewrite returns the proxy address of an image when its host is allowed,
+// and the image address unchanged otherwise.
+func (p *Proxy) Rewrite(uri string, width int, height int) string {
+	if p.config.ProxySecret == "" || uri == "" {
+		return uri
+	}
+
+	signed, err := p.Sign(&ProxyParams{Url: uri, Width: width, Height: height})
+	if err != nil {
+		return uri
+	}
+
+	return signed
+}
+
+// Sign returns the signed proxy address of an image.
+func (p *Proxy) Sign(params *ProxyParams) (string, error) {
+	if p.config.ProxySecret == "" {
+		return "", ErrProxySecretNotConfigured
+	}
+
+	err := p.validate(params)
+	if err != nil {
+		return "", err
+	}
+
+	values := url.Values{}
+	values.Set("url", params.Url)
+
+	if params.Width > 0 {
+		values.Set("w", strconv.Itoa(params.Width))
+	}
+
+	if params.Height > 0 {
+		values.Set("h", strconv.Itoa(params.Height))
+	}
+
+	encoded := base64.RawURLEncoding.EncodeToString([]byte(values.Encode()))
+
+	return strings.TrimSuffix(p.config.ProxyBaseUrl, "/") + "/" + p.signature(encoded) + "/" + encoded, nil
+}
+
+// Serve verifies a signed proxy address and returns the image it stands for,
+// fetching and resizing it on the first request.
+func (p *Proxy) Serve(ctx context.Context, signature string, encoded string) (*Blob, error) {
+	if p.config.ProxySecret == "" {
+		return nil, ErrProxySecretNotConfigured
+	}
+
+	if !hmac.Equal([]byte(signature), []byte(p.signature(encoded))) {
+		return nil, ErrInvalidSignature
+	}
+
+	params, err := decodeProxyParams(encoded)
+	if err != nil {
+		return nil, err
+	}
+
+	// the allowlist may have shrunk since the address was signed.
+	err = p.validate(params)
+	if err != nil {
+		return nil, err
+	}
+
+	sum := sha256.Sum256([]byte(params.Url))
+	prefix := fmt.Sprintf("proxy/%x/%s", sum[:1], hex.EncodeToString(sum[:]))
+	variantKey := fmt.Sprintf("%s/%dx%d", prefix, params.Width, params.Height)
+
+	blob, err := p.store.Get(ctx, variantKey)
+	if err == nil {
+		return blob, nil
+	}
+
+	if !errors.Is(err, ErrBlobNotFound) {
+		return nil, fmt.Errorf("%w(key: %s): %w", ErrFailedToGet, variantKey, err)
+	}
+
+	source, err := p.source(ctx, prefix+"/source", params.Url)
+	if err != nil {
+		return nil, err
+	}
+
+	format, err := Sniff(source)
+	if err != nil {
+		return nil, err
+	}
+
+	variant := &Variant{
+		Name:   variantKey,
+		Width:  cmp.Or(params.Width, MaxProxySide),
+		Height: cmp.Or(params.Height, MaxProxySide),
+		Crop:   params.Width > 0 && params.Height > 0,
+	}
+
+	rendered, err := renderVariants(source, format, []*Variant{variant})
+	if err != nil {
+		return nil, err
+	}
+
+	contentType := ContentTypeOf(OutputFormat(format))
+
+	err = p.store.Put(ctx, variantKey, contentType, rendered[variantKey])
+	if err != nil {
+		return nil, fmt.Errorf("%w(key: %s): %w", ErrFailedToStore, variantKey, err)
+	}
+
+	return &Blob{ContentType: contentType, Data: rendered[variantKey]}, nil
+}
+
+// source returns the remote image as first fetched.
+func (p *Proxy) source(ctx context.Context, key string, uri string) ([]byte, error) {
+	blob, err := p.store.Get(ctx, key)
+	if err == nil {
+		return blob.Data, nil
+	}
+
+	if !errors.Is(err, ErrBlobNotFound) {
+		return nil, fmt.Errorf("%w(key: %s): %w", ErrFailedToGet, key, err)
+	}
+
+	data, err := p.fetcher.Fetch(ctx, uri, MaxUploadSize)
+	if err != nil {
+		if errors.Is(err, ErrRemoteNotFound) || errors.Is(err, ErrUploadTooLarge) {
+			return nil, err //nolint:wrapcheck
+		}
+
+		return nil, fmt.Errorf("%w(url: %s): %w", ErrFailedToFetch, uri, err)
+	}
+
+	// only images are kept, the proxy must not serve anything else.
+	format, err := Sniff(data)
+	if err != nil {
+		return nil, err
+	}
+
+	err = p.store.Put(ctx, key, ContentTypeOf(format), data)
+	if err != nil {
+		return nil, fmt.Errorf("%w(key: %s): %w", ErrFailedToStore, key, err)
+	}
+
+	return data, nil
+}
+
+func (p *Proxy) validate(params *ProxyParams) error {
+	if params.Width < 0 || params.Width > MaxProxySide || params.Height < 0 || params.Height > MaxProxySide {
+		return fmt.Errorf("%w: width and height must be between 0 and %d", ErrInvalidProxyParams, MaxProxySide)
+	}
+
+	parsed, err := url.Parse(params.Url)
+	if err != nil || parsed.Scheme != "https" || parsed.User != nil {
+		return fmt.Errorf("%w: url must be an https address", ErrInvalidProxyParams)
+	}
+
+	if !IsAllowedHost(p.config, parsed.Hostname()) {
+		return fmt.Errorf("%w(host: %s)", ErrHostNotAllowed, parsed.Hostname())
+	}
+
+	return nil
+}
+
+// IsAllowedHost tells whether the image proxy fetches images from a host.
+func IsAllowedHost(config *Config, host string) bool {
+	allowed := strings.Split(config.ProxyAllowedHosts, ",")
+	for i := range allowed {
+		allowed[i] = strings.ToLower(strings.TrimSpace(allowed[i]))
+	}
+
+	return host != "" && slices.Contains(allowed, strings.ToLower(host))
+}
+
+func (p *Proxy) signature(encoded string) string {
+	mac := hmac.New(sha256.New, []byte(p.config.ProxySecret))
+	mac.Write([]byte(encoded))
+
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+func decodeProxyParams(encoded string) (*ProxyParams, error) {
+	decoded, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidProxyParams, err)
+	}
+
+	values, err := url.ParseQuery(string(decoded))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidProxyParams, err)
+	}
+
+	params := &ProxyParams{Url: values.Get("url"), Width: 0, Height: 0}
+
+	for name, target := range map[string]*int{"w": &params.Width, "h": &params.Height} {
+		if values.Get(name) == "" {
+			continue
+		}
+
+		*target, err = strconv.Atoi(values.Get(name))
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s must be a number", ErrInvalidProxyParams, name)
+		}
+	}
+
+	return params, nil
+}
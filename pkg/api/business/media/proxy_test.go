@@ -0,0 +1,113 @@
+package media_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/eser/acik.io/pkg/api/business/media"
+)
+
+// fetcher serves one remote image, counting the fetches.
+type fetcher struct {
+	data    []byte
+	fetches int
+}
+
+func (f *fetcher) Fetch(context.Context, string, int64) ([]byte, error) {
+	f.fetches++
+
+	return f.data, nil
+}
+
+func newProxyConfig() *media.Config {
+	return &media.Config{ //nolint:exhaustruct
+		ProxyBaseUrl:      "https://acik.io/img/",
+		ProxySecret:       "secret",
+		ProxyAllowedHosts: "avatars.githubusercontent.com, pbs.twimg.com",
+	}
+}
+
+// splitProxyUrl returns the signature and the encoded parameters of a proxy
+// address.
+func splitProxyUrl(t *testing.T, signed string) (string, string) {
+	t.Helper()
+
+	signature, encoded, found := strings.Cut(strings.TrimPrefix(signed, "https://acik.io/img/"), "/")
+	if !found {
+		t.Fatalf("got %q, want a proxy address", signed)
+	}
+
+	return signature, encoded
+}
+
+func TestProxyServe(t *testing.T) {
+	t.Parallel()
+
+	remote := &fetcher{data: encodePng(t, 300, 200), fetches: 0}
+	proxy := media.NewProxy(newProxyConfig(), newStore(), remote)
+
+	signature, encoded := splitProxyUrl(t, proxy.Rewrite("https://avatars.githubusercontent.com/u/1", 64, 64)) //nolint:mnd
+
+	for range 2 {
+		blob, err := proxy.Serve(context.Background(), signature, encoded)
+		if err != nil {
+			t.Fatalf("serving: %v", err)
+		}
+
+		if got := decodeSize(t, blob.Data); got.X != 64 || got.Y != 64 || blob.ContentType != "image/png" {
+			t.Errorf("got a %s of %v, want a 64x64 PNG", blob.ContentType, got)
+		}
+	}
+
+	if remote.fetches != 1 {
+		t.Errorf("got %d fetches, want the remote image fetched once", remote.fetches)
+	}
+
+	_, err := proxy.Serve(context.Background(), signature+"x", encoded)
+	if !errors.Is(err, media.ErrInvalidSignature) {
+		t.Errorf("got %v for a tampered signature, want %v", err, media.ErrInvalidSignature)
+	}
+
+	// an address signed earlier stops working once its host is disallowed.
+	config := newProxyConfig()
+	config.ProxyAllowedHosts = "pbs.twimg.com"
+
+	_, err = media.NewProxy(config, newStore(), remote).Serve(context.Background(), signature, encoded)
+	if !errors.Is(err, media.ErrHostNotAllowed) {
+		t.Errorf("got %v after the host was disallowed, want %v", err, media.ErrHostNotAllowed)
+	}
+}
+
+func TestProxySignRejects(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		params media.ProxyParams
+		want   error
+	}{
+		{name: "other host", params: media.ProxyParams{Url: "https://example.com/a.png", Width: 0, Height: 0}, want: media.ErrHostNotAllowed},
+		{name: "http", params: media.ProxyParams{Url: "http://pbs.twimg.com/a.png", Width: 0, Height: 0}, want: media.ErrInvalidProxyParams},
+		{name: "credentials", params: media.ProxyParams{Url: "https://user@pbs.twimg.com/a.png", Width: 0, Height: 0}, want: media.ErrInvalidProxyParams},
+		{name: "too wide", params: media.ProxyParams{Url: "https://pbs.twimg.com/a.png", Width: media.MaxProxySide + 1, Height: 0}, want: media.ErrInvalidProxyParams},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			proxy := media.NewProxy(newProxyConfig(), newStore(), &fetcher{}) //nolint:exhaustruct
+
+			_, err := proxy.Sign(&test.params)
+			if !errors.Is(err, test.want) {
+				t.Errorf("got %v, want %v", err, test.want)
+			}
+
+			if got := proxy.Rewrite(test.params.Url, test.params.Width, test.params.Height); got != test.params.Url {
+				t.Errorf("got %q, want the address left as is", got)
+			}
+		})
+	}
+}
@@ -0,0 +1,136 @@
+package media
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+var (
+	ErrFailedToStore    = errors.New("failed to store upload")
+	ErrFailedToGet      = errors.New("failed to get upload")
+	ErrFailedToProcess  = errors.New("failed to process image")
+	ErrBlobNotFound     = errors.New("blob not found")
+	ErrUnknownPreset    = errors.New("unknown preset")
+	ErrUnsupportedType  = errors.New("unsupported image type, expected JPEG, PNG, GIF or WebP")
+	ErrInvalidImage     = errors.New("invalid image")
+	ErrUploadTooLarge   = errors.New("upload is too large")
+	ErrImageTooLarge    = errors.New("image dimensions are too large")
+	ErrEmptyUpload      = errors.New("upload is empty")
+	ErrUnknownBlobStore = errors.New("unknown blob store backend")
+)
+
+var keyPattern = regexp.MustCompile(`^images/[a-z]+/[0-9a-f]{2}/[0-9a-f]{64}/[a-z]+\.(jpeg|png)$`)
+
+// BlobStore keeps uploads under their keys. Keys are content-addressed, so a
+// key always holds the same bytes and putting it again is harmless.
+type BlobStore interface {
+	Put(ctx context.Context, key string, contentType string, data []byte) error
+	Get(ctx context.Context, key string) (*Blob, error)
+	Exists(ctx context.Context, key string) (bool, error)
+}
+
+type Service struct {
+	config *Config
+	store  BlobStore
+}
+
+func NewService(config *Config, store BlobStore) *Service {
+	return &Service{config: config, store: store}
+}
+
+// Upload validates an image, renders the variants of the preset and stores
+// them. Variants are keyed by the hash of the upload, so uploading the same
+// image again finds it already stored.
+func (s *Service) Upload(ctx context.Context, preset string, data []byte) (*Image, error) {
+	variants, ok := Presets[preset]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownPreset, preset)
+	}
+
+	if len(data) == 0 {
+		return nil, ErrEmptyUpload
+	}
+
+	if len(data) > MaxUploadSize {
+		return nil, fmt.Errorf("%w: at most %d bytes are allowed", ErrUploadTooLarge, MaxUploadSize)
+	}
+
+	format, err := Sniff(data)
+	if err != nil {
+		return nil, err
+	}
+
+	output := OutputFormat(format)
+	sum := sha256.Sum256(data)
+	prefix := fmt.Sprintf("images/%s/%x/%s", preset, sum[:1], hex.EncodeToString(sum[:]))
+
+	keys := make(map[string]string, len(variants))
+	for _, variant := range variants {
+		keys[variant.Name] = prefix + "/" + variant.Name + "." + output
+	}
+
+	originalKey := keys[variants[0].Name]
+
+	exists, err := s.store.Exists(ctx, originalKey)
+	if err != nil {
+		return nil, fmt.Errorf("%w(key: %s): %w", ErrFailedToGet, originalKey, err)
+	}
+
+	if !exists {
+		rendered, err := renderVariants(data, format, variants)
+		if err != nil {
+			return nil, err
+		}
+
+		// the first variant goes last, so its presence means all are stored.
+		for i := len(variants) - 1; i >= 0; i-- {
+			key := keys[variants[i].Name]
+
+			err := s.store.Put(ctx, key, ContentTypeOf(output), rendered[variants[i].Name])
+			if err != nil {
+				return nil, fmt.Errorf("%w(key: %s): %w", ErrFailedToStore, key, err)
+			}
+		}
+	}
+
+	image := &Image{
+		Key:      originalKey,
+		Uri:      s.Url(originalKey),
+		Variants: make(map[string]string, len(variants)),
+	}
+
+	for name, key := range keys {
+		image.Variants[name] = s.Url(key)
+	}
+
+	return image, nil
+}
+
+// Get returns a stored variant. Keys that this service couldn't have issued
+// are reported as not found without reaching the store.
+func (s *Service) Get(ctx context.Context, key string) (*Blob, error) {
+	if !keyPattern.MatchString(key) {
+		return nil, fmt.Errorf("%w(key: %s)", ErrBlobNotFound, key)
+	}
+
+	blob, err := s.store.Get(ctx, key)
+	if err != nil {
+		if errors.Is(err, ErrBlobNotFound) {
+			return nil, err
+		}
+
+		return nil, fmt.Errorf("%w(key: %s): %w", ErrFailedToGet, key, err)
+	}
+
+	return blob, nil
+}
+
+// Url returns the public address of a key.
+func (s *Service) Url(key string) string {
+	return strings.TrimSuffix(s.config.PublicUrl, "/") + "/" + key
+}
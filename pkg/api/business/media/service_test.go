@@ -0,0 +1,210 @@
+package media_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"image"
+	"image/color"
+	"image/png"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/eser/acik.io/pkg/api/business/media"
+)
+
+// store keeps blobs in memory, counting the puts.
+type store struct {
+	blobs map[string]*media.Blob
+	puts  int
+	mu    sync.Mutex
+}
+
+func newStore() *store {
+	return &store{blobs: map[string]*media.Blob{}} //nolint:exhaustruct
+}
+
+func (s *store) Put(_ context.Context, key string, contentType string, data []byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.blobs[key] = &media.Blob{ContentType: contentType, Data: data}
+	s.puts++
+
+	return nil
+}
+
+func (s *store) Get(_ context.Context, key string) (*media.Blob, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	blob, ok := s.blobs[key]
+	if !ok {
+		return nil, media.ErrBlobNotFound
+	}
+
+	return blob, nil
+}
+
+func (s *store) Exists(_ context.Context, key string) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	_, ok := s.blobs[key]
+
+	return ok, nil
+}
+
+func encodePng(t *testing.T, width int, height int) []byte {
+	t.Helper()
+
+	img := image.NewNRGBA(image.Rect(0, 0, width, height))
+	for x := range width {
+		img.Set(x, 0, color.NRGBA{R: 255, G: 0, B: 0, A: 128}) //nolint:mnd
+	}
+
+	var buf bytes.Buffer
+
+	err := png.Encode(&buf, img)
+	if err != nil {
+		t.Fatalf("encoding: %v", err)
+	}
+
+	return buf.Bytes()
+}
+
+func decodeSize(t *testing.T, data []byte) image.Point {
+	t.Helper()
+
+	config, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("decoding: %v", err)
+	}
+
+	return image.Point{X: config.Width, Y: config.Height}
+}
+
+func TestSniff(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		data []byte
+		want string
+		err  error
+	}{
+		{name: "jpeg", data: []byte{0xFF, 0xD8, 0xFF, 0xE0}, want: media.FormatJpeg, err: nil},
+		{name: "png", data: []byte("\x89PNG\r\n\x1a\n...."), want: media.FormatPng, err: nil},
+		{name: "gif", data: []byte("GIF89a..."), want: media.FormatGif, err: nil},
+		{name: "webp", data: []byte("RIFF\x00\x00\x00\x00WEBPVP8 "), want: media.FormatWebp, err: nil},
+		{name: "svg", data: []byte("<svg xmlns=\"http://www.w3.org/2000/svg\"/>"), want: "", err: media.ErrUnsupportedType},
+		{name: "short riff", data: []byte("RIFF"), want: "", err: media.ErrUnsupportedType},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := media.Sniff(test.data)
+			if got != test.want || !errors.Is(err, test.err) {
+				t.Errorf("got %q, %v, want %q, %v", got, err, test.want, test.err)
+			}
+		})
+	}
+}
+
+func TestUpload(t *testing.T) {
+	t.Parallel()
+
+	blobs := newStore()
+	service := media.NewService(&media.Config{PublicUrl: "https://cdn.acik.io/"}, blobs) //nolint:exhaustruct
+	data := encodePng(t, 300, 200)
+
+	uploaded, err := service.Upload(context.Background(), media.PresetProfile, data)
+	if err != nil {
+		t.Fatalf("uploading: %v", err)
+	}
+
+	if !strings.HasPrefix(uploaded.Uri, "https://cdn.acik.io/images/profile/") || !strings.HasSuffix(uploaded.Uri, "/original.png") {
+		t.Errorf("got uri %q, want the original variant kept as PNG", uploaded.Uri)
+	}
+
+	// the original fits its bounds without being scaled up, the others are
+	// cropped to fill theirs.
+	wants := map[string]image.Point{
+		media.VariantOriginal: {X: 300, Y: 200},
+		media.VariantMedium:   {X: 200, Y: 200},
+		media.VariantThumb:    {X: 64, Y: 64},
+	}
+
+	for name, want := range wants {
+		key := strings.TrimPrefix(uploaded.Variants[name], "https://cdn.acik.io/")
+
+		blob, err := service.Get(context.Background(), key)
+		if err != nil {
+			t.Fatalf("getting %s: %v", name, err)
+		}
+
+		if got := decodeSize(t, blob.Data); got != want {
+			t.Errorf("got %s of %v, want %v", name, got, want)
+		}
+	}
+
+	again, err := service.Upload(context.Background(), media.PresetProfile, data)
+	if err != nil {
+		t.Fatalf("uploading again: %v", err)
+	}
+
+	if again.Key != uploaded.Key || blobs.puts != len(wants) {
+		t.Errorf("got key %q after %d puts, want the stored upload found by its hash", again.Key, blobs.puts)
+	}
+}
+
+func TestUploadRejects(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		preset string
+		data   []byte
+		want   error
+	}{
+		{name: "unknown preset", preset: "banner", data: []byte("GIF89a"), want: media.ErrUnknownPreset},
+		{name: "empty", preset: media.PresetStory, data: nil, want: media.ErrEmptyUpload},
+		{name: "too large", preset: media.PresetStory, data: make([]byte, media.MaxUploadSize+1), want: media.ErrUploadTooLarge},
+		{name: "not an image", preset: media.PresetStory, data: []byte("hello"), want: media.ErrUnsupportedType},
+		{name: "broken image", preset: media.PresetStory, data: []byte("\x89PNG\r\n\x1a\nbroken"), want: media.ErrInvalidImage},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			blobs := newStore()
+
+			_, err := media.NewService(&media.Config{}, blobs).Upload(context.Background(), test.preset, test.data) //nolint:exhaustruct
+			if !errors.Is(err, test.want) {
+				t.Errorf("got %v, want %v", err, test.want)
+			}
+
+			if blobs.puts != 0 {
+				t.Errorf("got %d puts, want none", blobs.puts)
+			}
+		})
+	}
+}
+
+func TestGetRejectsForeignKeys(t *testing.T) {
+	t.Parallel()
+
+	blobs := newStore()
+	blobs.blobs["proxy/secret"] = &media.Blob{ContentType: "image/png", Data: nil}
+
+	for _, key := range []string{"proxy/secret", "images/profile/../../proxy/secret", ""} {
+		_, err := media.NewService(&media.Config{}, blobs).Get(context.Background(), key) //nolint:exhaustruct
+		if !errors.Is(err, media.ErrBlobNotFound) {
+			t.Errorf("got %v for %q, want %v", err, key, media.ErrBlobNotFound)
+		}
+	}
+}
@@ -0,0 +1,61 @@
+package media
+
+const (
+	// MaxUploadSize is the largest upload accepted, in bytes.
+	MaxUploadSize = 10 << 20
+	// MaxPixels bounds the dimensions of uploads, so small files that decode
+	// into huge images are rejected before they are decoded.
+	MaxPixels = 25_000_000
+
+	PresetProfile = "profile"
+	PresetStory   = "story"
+	PresetEvent   = "event"
+
+	VariantOriginal = "original"
+	VariantMedium   = "medium"
+	VariantThumb    = "thumb"
+)
+
+// Variant is a rendition of an upload. Variants fit within their bounds, or
+// are cropped to fill them exactly when Crop is set. Images are never scaled
+// up.
+type Variant struct {
+	Name   string
+	Width  int
+	Height int
+	Crop   bool
+}
+
+// Presets lists the variants generated for each kind of picture. The first
+// variant of each preset is the one written back to the owning record.
+var Presets = map[string][]*Variant{ //nolint:gochecknoglobals
+	PresetProfile: {
+		{Name: VariantOriginal, Width: 1024, Height: 1024, Crop: false},
+		{Name: VariantMedium, Width: 256, Height: 256, Crop: true},
+		{Name: VariantThumb, Width: 64, Height: 64, Crop: true},
+	},
+	PresetStory: {
+		{Name: VariantOriginal, Width: 2048, Height: 2048, Crop: false},
+		{Name: VariantMedium, Width: 1200, Height: 1200, Crop: false},
+		{Name: VariantThumb, Width: 400, Height: 225, Crop: true},
+	},
+	PresetEvent: {
+		{Name: VariantOriginal, Width: 2048, Height: 2048, Crop: false},
+		{Name: VariantMedium, Width: 1200, Height: 1200, Crop: false},
+		{Name: VariantThumb, Width: 400, Height: 225, Crop: true},
+	},
+}
+
+// Blob is a stored object.
+type Blob struct {
+	ContentType string
+	Data        []byte
+}
+
+// Image is a stored upload: the address of its original variant and the
+// addresses of all its variants by name.
+type Image struct {
+	Key      string            `json:"key"`
+	Uri      string            `json:"uri"`
+	Variants map[string]string `json:"variants"`
+}
@@ -0,0 +1,531 @@
+package moderation
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/eser/acik.io/pkg/api/business/audit"
+	"github.com/eser/acik.io/pkg/api/business/questions"
+	"github.com/eser/acik.io/pkg/api/business/stories"
+	"github.com/eser/acik.io/pkg/api/business/users"
+)
+
+var (
+	ErrFailedToCreateRecord   = errors.New("failed to create record")
+	ErrFailedToGetRecord      = errors.New("failed to get record")
+	ErrFailedToListRecords    = errors.New("failed to list records")
+	ErrFailedToUpdateRecord   = errors.New("failed to update record")
+	ErrRecordNotFound         = errors.New("record not found")
+	ErrInvalidInput           = errors.New("invalid input")
+	ErrAlreadyReported        = errors.New("content is already reported by the user")
+	ErrReportClosed           = errors.New("report is already closed")
+	ErrActionNotSupported     = errors.New("action is not supported for the content")
+	ErrCannotSuspendModerator = errors.New("moderators can't be suspended")
+)
+
+type Repository interface {
+	Transact(ctx context.Context, fn func(ctx context.Context) error) error
+	CreateReport(ctx context.Context, arg CreateReportParams) (*Report, error)
+	GetReportById(ctx context.Context, id string) (*Report, error)
+	ListReports(ctx context.Context, arg ListReportsParams) ([]*Report, error)
+	ResolveReport(ctx context.Context, arg ResolveReportParams) (*Report, error)
+	ResolveOpenReportsOfEntity(ctx context.Context, arg ResolveOpenReportsOfEntityParams) (int64, error)
+	GetReportableEntity(ctx context.Context, arg GetReportableEntityParams) (*GetReportableEntityRow, error)
+	HideStory(ctx context.Context, id string) (int64, error)
+	HideQuestion(ctx context.Context, id string) (int64, error)
+	SoftDeleteStory(ctx context.Context, id string) (int64, error)
+	SoftDeleteQuestion(ctx context.Context, id string) (int64, error)
+	SoftDeleteProfile(ctx context.Context, id string) (int64, error)
+	UnhideQuestion(ctx context.Context, id string) (int64, error)
+	GetQuestionById(ctx context.Context, id string) (*questions.Question, error)
+}
+
+type Users interface {
+	GetById(ctx context.Context, id string) (*users.User, error)
+	EnsureActive(ctx context.Context, userId string) error
+	Suspend(ctx context.Context, userId string, reason string) error
+	LiftSuspension(ctx context.Context, userId string) error
+}
+
+// AuditLog records what moderators do, see audit.Service.
+type AuditLog interface {
+	Record(ctx context.Context, change *audit.Change) error
+}
+
+// EventRecorder records domain events, see outbox.Service. The audit log is
+// written separately, so it must not be an audit.Recorder.
+type EventRecorder interface {
+	Record(ctx context.Context, aggregateType string, aggregateId string, eventType string, payload any) error
+}
+
+type Service struct {
+	repo   Repository
+	users  Users
+	audit  AuditLog
+	events EventRecorder
+
+	idGenerator RecordIDGenerator
+}
+
+func NewService(repo Repository, users Users, audit AuditLog, events EventRecorder) *Service {
+	return &Service{repo: repo, users: users, audit: audit, events: events, idGenerator: DefaultIDGenerator}
+}
+
+// Report puts content into the moderation queue. A user can have a single
+// open report per content.
+func (s *Service) Report(ctx context.Context, reporterUserId string, input *ReportInput) (*Report, error) {
+	note := strings.TrimSpace(input.Note)
+
+	switch {
+	case !IsReportable(input.EntityType):
+		return nil, fmt.Errorf("%w: entityType must be one of story, question or profile", ErrInvalidInput)
+	case !IsReason(input.Reason):
+		return nil, fmt.Errorf("%w: unknown reason %q", ErrInvalidInput, input.Reason)
+	case len(note) > MaxNoteLength:
+		return nil, fmt.Errorf("%w: note can't be longer than %d bytes", ErrInvalidInput, MaxNoteLength)
+	}
+
+	err := s.users.EnsureActive(ctx, reporterUserId)
+	if err != nil {
+		return nil, err //nolint:wrapcheck
+	}
+
+	entity, err := s.getEntity(ctx, input.EntityType, input.EntityId)
+	if err != nil {
+		return nil, err
+	}
+
+	var record *Report
+
+	err = s.repo.Transact(ctx, func(ctx context.Context) error {
+		var err error
+
+		record, err = s.repo.CreateReport(ctx, CreateReportParams{
+			Id:             string(s.idGenerator()),
+			ReporterUserId: sql.NullString{String: reporterUserId, Valid: true},
+			EntityType:     input.EntityType,
+			EntityId:       entity.EntityId,
+			Reason:         input.Reason,
+			Note:           note,
+		})
+		if err != nil {
+			return fmt.Errorf("%w: %w", ErrFailedToCreateRecord, err)
+		}
+
+		if record == nil {
+			return fmt.Errorf("%w(entity: %s/%s)", ErrAlreadyReported, input.EntityType, input.EntityId)
+		}
+
+		return s.audit.Record(ctx, &audit.Change{ //nolint:wrapcheck
+			Before:     nil,
+			After:      record,
+			Action:     "report.created",
+			EntityType: "report",
+			EntityId:   record.Id,
+			ProfileId:  "",
+		})
+	})
+	if err != nil {
+		return nil, err //nolint:wrapcheck
+	}
+
+	return record, nil
+}
+
+// Flag queues content held back by the automated content filter for review.
+// The report has no reporter, and the note tells what the filter found.
+// Dismissing it makes the content visible again.
+func (s *Service) Flag(ctx context.Context, entityType string, entityId string, note string) error {
+	if !IsReportable(entityType) {
+		return fmt.Errorf("%w: entityType must be one of story, question or profile", ErrInvalidInput)
+	}
+
+	return s.repo.Transact(ctx, func(ctx context.Context) error { //nolint:wrapcheck
+		record, err := s.repo.CreateReport(ctx, CreateReportParams{
+			Id:             string(s.idGenerator()),
+			ReporterUserId: sql.NullString{}, //nolint:exhaustruct
+			EntityType:     entityType,
+			EntityId:       entityId,
+			Reason:         ReasonAutomated,
+			Note:           truncate(note, MaxNoteLength),
+		})
+		if err != nil {
+			return fmt.Errorf("%w: %w", ErrFailedToCreateRecord, err)
+		}
+
+		return s.audit.Record(ctx, &audit.Change{ //nolint:wrapcheck
+			Before:     nil,
+			After:      record,
+			Action:     "report.flagged",
+			EntityType: "report",
+			EntityId:   record.Id,
+			ProfileId:  "",
+		})
+	})
+}
+
+func (s *Service) GetById(ctx context.Context, id string) (*Report, error) {
+	record, err := s.repo.GetReportById(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("%w(id: %s): %w", ErrFailedToGetRecord, id, err)
+	}
+
+	if record == nil {
+		return nil, fmt.Errorf("%w(id: %s)", ErrRecordNotFound, id)
+	}
+
+	return record, nil
+}
+
+// List returns the reports of a status, the oldest first so the queue is
+// worked through in order.
+func (s *Service) List(ctx context.Context, filter *ListFilter) ([]*Report, error) {
+	status := filter.Status
+	if status == "" {
+		status = StatusOpen
+	}
+
+	records, err := s.repo.ListReports(ctx, ListReportsParams{
+		Status:      status,
+		EntityType:  sql.NullString{String: filter.EntityType, Valid: filter.EntityType != ""},
+		LimitCount:  filter.Limit,
+		OffsetCount: filter.Offset,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrFailedToListRecords, err)
+	}
+
+	return records, nil
+}
+
+// Resolve closes an open report, taking the action on the reported content
+// in the same transaction.
+func (s *Service) Resolve(
+	ctx context.Context,
+	moderatorUserId string,
+	id string,
+	input *ResolveInput,
+) (*Report, error) {
+	err := s.users.EnsureActive(ctx, moderatorUserId)
+	if err != nil {
+		return nil, err //nolint:wrapcheck
+	}
+
+	report, err := s.GetById(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if report.Status != StatusOpen {
+		return nil, fmt.Errorf("%w(id: %s, status: %s)", ErrReportClosed, id, report.Status)
+	}
+
+	if !CanApply(report.EntityType, input.Action) {
+		return nil, fmt.Errorf("%w(entity: %s, action: %s)", ErrActionNotSupported, report.EntityType, input.Action)
+	}
+
+	note := sql.NullString{String: strings.TrimSpace(input.Note), Valid: strings.TrimSpace(input.Note) != ""}
+	moderator := sql.NullString{String: moderatorUserId, Valid: true}
+
+	if input.Action == ActionDismiss {
+		return s.dismiss(ctx, report, moderator, note)
+	}
+
+	entity, err := s.getEntity(ctx, report.EntityType, report.EntityId)
+	if err != nil {
+		return nil, err
+	}
+
+	var record *Report
+
+	err = s.repo.Transact(ctx, func(ctx context.Context) error {
+		err := s.apply(ctx, input.Action, report, entity)
+		if err != nil {
+			return err
+		}
+
+		record, err = s.repo.ResolveReport(ctx, ResolveReportParams{
+			Status:           StatusActioned,
+			Action:           sql.NullString{String: input.Action, Valid: true},
+			ResolvedByUserId: moderator,
+			ResolutionNote:   note,
+			Id:               report.Id,
+		})
+		if err != nil {
+			return fmt.Errorf("%w(id: %s): %w", ErrFailedToUpdateRecord, report.Id, err)
+		}
+
+		if record == nil {
+			return fmt.Errorf("%w(id: %s)", ErrReportClosed, report.Id)
+		}
+
+		// the content is dealt with, so are the other reports on it.
+		_, err = s.repo.ResolveOpenReportsOfEntity(ctx, ResolveOpenReportsOfEntityParams{
+			Status:           StatusActioned,
+			Action:           sql.NullString{String: input.Action, Valid: true},
+			ResolvedByUserId: moderator,
+			ResolutionNote:   note,
+			EntityType:       report.EntityType,
+			EntityId:         report.EntityId,
+		})
+		if err != nil {
+			return fmt.Errorf("%w(entity: %s/%s): %w", ErrFailedToUpdateRecord, report.EntityType, report.EntityId, err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err //nolint:wrapcheck
+	}
+
+	return record, nil
+}
+
+// Suspend suspends a user outside of any report. Moderators can't suspend
+// each other.
+func (s *Service) Suspend(ctx context.Context, moderatorUserId string, userId string, input *SuspendInput) error {
+	err := s.users.EnsureActive(ctx, moderatorUserId)
+	if err != nil {
+		return err //nolint:wrapcheck
+	}
+
+	return s.repo.Transact(ctx, func(ctx context.Context) error { //nolint:wrapcheck
+		return s.suspend(ctx, userId, strings.TrimSpace(input.Reason), "")
+	})
+}
+
+func (s *Service) LiftSuspension(ctx context.Context, moderatorUserId string, userId string) error {
+	err := s.users.EnsureActive(ctx, moderatorUserId)
+	if err != nil {
+		return err //nolint:wrapcheck
+	}
+
+	return s.repo.Transact(ctx, func(ctx context.Context) error { //nolint:wrapcheck
+		err := s.users.LiftSuspension(ctx, userId)
+		if err != nil {
+			return err //nolint:wrapcheck
+		}
+
+		return s.audit.Record(ctx, &audit.Change{ //nolint:wrapcheck
+			Before:     map[string]any{"isSuspended": true},
+			After:      map[string]any{"isSuspended": false},
+			Action:     "moderation.suspension_lifted",
+			EntityType: "user",
+			EntityId:   userId,
+			ProfileId:  "",
+		})
+	})
+}
+
+func (s *Service) dismiss(
+	ctx context.Context,
+	report *Report,
+	moderator sql.NullString,
+	note sql.NullString,
+) (*Report, error) {
+	var record *Report
+
+	err := s.repo.Transact(ctx, func(ctx context.Context) error {
+		var err error
+
+		record, err = s.repo.ResolveReport(ctx, ResolveReportParams{
+			Status:           StatusDismissed,
+			Action:           sql.NullString{}, //nolint:exhaustruct
+			ResolvedByUserId: moderator,
+			ResolutionNote:   note,
+			Id:               report.Id,
+		})
+		if err != nil {
+			return fmt.Errorf("%w(id: %s): %w", ErrFailedToUpdateRecord, report.Id, err)
+		}
+
+		if record == nil {
+			return fmt.Errorf("%w(id: %s)", ErrReportClosed, report.Id)
+		}
+
+		// the filter was wrong, so the question it held back is let through.
+		if report.Reason == ReasonAutomated && report.EntityType == EntityQuestion {
+			err = s.letThrough(ctx, report)
+			if err != nil {
+				return err
+			}
+		}
+
+		return s.audit.Record(ctx, &audit.Change{ //nolint:wrapcheck
+			Before:     report,
+			After:      record,
+			Action:     "moderation.report_dismissed",
+			EntityType: "report",
+			EntityId:   report.Id,
+			ProfileId:  "",
+		})
+	})
+	if err != nil {
+		return nil, err //nolint:wrapcheck
+	}
+
+	return record, nil
+}
+
+// letThrough makes a question held back by the content filter visible, and
+// announces it the way questions are announced when asked.
+func (s *Service) letThrough(ctx context.Context, report *Report) error {
+	_, err := s.repo.UnhideQuestion(ctx, report.EntityId)
+	if err != nil {
+		return fmt.Errorf("%w(entity: %s/%s): %w", ErrFailedToUpdateRecord, report.EntityType, report.EntityId, err)
+	}
+
+	err = s.recordModerated(ctx, ActionDismiss, report)
+	if err != nil {
+		return err
+	}
+
+	question, err := s.repo.GetQuestionById(ctx, report.EntityId)
+	if err != nil {
+		return fmt.Errorf("%w(entity: %s/%s): %w", ErrFailedToGetRecord, report.EntityType, report.EntityId, err)
+	}
+
+	// the question is gone since it was flagged.
+	if question == nil {
+		return nil
+	}
+
+	return s.events.Record( //nolint:wrapcheck
+		ctx,
+		questions.AggregateQuestion,
+		question.Id,
+		questions.EventQuestionCreated,
+		questions.NewQuestionCreatedEvent(question),
+	)
+}
+
+// apply takes a moderation action on reported content and logs it.
+func (s *Service) apply(ctx context.Context, action string, report *Report, entity *GetReportableEntityRow) error {
+	if action == ActionSuspend {
+		if entity.OwnerUserId == "" {
+			return fmt.Errorf(
+				"%w(entity: %s/%s): content has no single owner",
+				ErrActionNotSupported,
+				report.EntityType,
+				report.EntityId,
+			)
+		}
+
+		return s.suspend(ctx, entity.OwnerUserId, report.Reason, report.Id)
+	}
+
+	var (
+		affected int64
+		err      error
+		after    map[string]any
+	)
+
+	switch {
+	case action == ActionHide && report.EntityType == EntityStory:
+		affected, err = s.repo.HideStory(ctx, report.EntityId)
+		after = map[string]any{"status": stories.StatusHidden}
+	case action == ActionHide && report.EntityType == EntityQuestion:
+		affected, err = s.repo.HideQuestion(ctx, report.EntityId)
+		after = map[string]any{"isHidden": true}
+	case action == ActionDelete && report.EntityType == EntityStory:
+		affected, err = s.repo.SoftDeleteStory(ctx, report.EntityId)
+		after = map[string]any{"isDeleted": true}
+	case action == ActionDelete && report.EntityType == EntityQuestion:
+		affected, err = s.repo.SoftDeleteQuestion(ctx, report.EntityId)
+		after = map[string]any{"isDeleted": true}
+	case action == ActionDelete && report.EntityType == EntityProfile:
+		affected, err = s.repo.SoftDeleteProfile(ctx, report.EntityId)
+		after = map[string]any{"isDeleted": true}
+	default:
+		return fmt.Errorf("%w(entity: %s, action: %s)", ErrActionNotSupported, report.EntityType, action)
+	}
+
+	if err != nil {
+		return fmt.Errorf("%w(entity: %s/%s): %w", ErrFailedToUpdateRecord, report.EntityType, report.EntityId, err)
+	}
+
+	if affected == 0 {
+		return fmt.Errorf("%w(entity: %s/%s)", ErrRecordNotFound, report.EntityType, report.EntityId)
+	}
+
+	after["reportId"] = report.Id
+
+	err = s.recordModerated(ctx, action, report)
+	if err != nil {
+		return err
+	}
+
+	return s.audit.Record(ctx, &audit.Change{ //nolint:wrapcheck
+		Before:     nil,
+		After:      after,
+		Action:     "moderation." + action,
+		EntityType: report.EntityType,
+		EntityId:   report.EntityId,
+		ProfileId:  entity.ProfileId,
+	})
+}
+
+func (s *Service) recordModerated(ctx context.Context, action string, report *Report) error {
+	return s.events.Record(ctx, report.EntityType, report.EntityId, EventContentModerated, &ContentModeratedEvent{ //nolint:wrapcheck
+		EntityType: report.EntityType,
+		EntityId:   report.EntityId,
+		Action:     action,
+		ReportId:   report.Id,
+	})
+}
+
+// suspend suspends a user and logs it, along with the report that led to it
+// if any.
+func (s *Service) suspend(ctx context.Context, userId string, reason string, reportId string) error {
+	user, err := s.users.GetById(ctx, userId)
+	if err != nil {
+		return err //nolint:wrapcheck
+	}
+
+	if user.IsModerator() {
+		return fmt.Errorf("%w(id: %s)", ErrCannotSuspendModerator, userId)
+	}
+
+	err = s.users.Suspend(ctx, userId, reason)
+	if err != nil {
+		return err //nolint:wrapcheck
+	}
+
+	after := map[string]any{"isSuspended": true, "reason": reason}
+	if reportId != "" {
+		after["reportId"] = reportId
+	}
+
+	return s.audit.Record(ctx, &audit.Change{ //nolint:wrapcheck
+		Before:     map[string]any{"isSuspended": user.IsSuspended()},
+		After:      after,
+		Action:     "moderation.user_suspended",
+		EntityType: "user",
+		EntityId:   userId,
+		ProfileId:  "",
+	})
+}
+
+func (s *Service) getEntity(ctx context.Context, entityType string, id string) (*GetReportableEntityRow, error) {
+	entity, err := s.repo.GetReportableEntity(ctx, GetReportableEntityParams{EntityType: entityType, EntityId: id})
+	if err != nil {
+		return nil, fmt.Errorf("%w(entity: %s/%s): %w", ErrFailedToGetRecord, entityType, id, err)
+	}
+
+	if entity == nil {
+		return nil, fmt.Errorf("%w(entity: %s/%s)", ErrRecordNotFound, entityType, id)
+	}
+
+	return entity, nil
+}
+
+func truncate(value string, length int) string {
+	if len(value) <= length {
+		return value
+	}
+
+	return strings.ToValidUTF8(value[:length], "")
+}
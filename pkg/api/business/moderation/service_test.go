@@ -0,0 +1,231 @@
+package moderation_test
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"sync"
+	"testing"
+
+	"github.com/eser/acik.io/pkg/api/business/audit"
+	"github.com/eser/acik.io/pkg/api/business/moderation"
+	"github.com/eser/acik.io/pkg/api/business/outbox/outboxtest"
+	"github.com/eser/acik.io/pkg/api/business/questions"
+)
+
+const (
+	moderatorId = "01HMODERATOR00000000000000"
+	askerId     = "01HASKER000000000000000000"
+)
+
+// repository holds reports and questions, following moderation.sql.
+type repository struct {
+	moderation.Repository
+
+	reports   map[string]*moderation.Report
+	questions map[string]*questions.Question
+	mu        sync.Mutex
+}
+
+func (r *repository) Transact(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
+
+func (r *repository) GetReportById(_ context.Context, id string) (*moderation.Report, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	report, ok := r.reports[id]
+	if !ok {
+		return nil, nil //nolint:nilnil
+	}
+
+	clone := *report
+
+	return &clone, nil
+}
+
+func (r *repository) ResolveReport(_ context.Context, arg moderation.ResolveReportParams) (*moderation.Report, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	report, ok := r.reports[arg.Id]
+	if !ok || report.Status != moderation.StatusOpen {
+		return nil, nil //nolint:nilnil
+	}
+
+	report.Status = arg.Status
+	report.Action = arg.Action
+	report.ResolvedByUserId = arg.ResolvedByUserId
+	report.ResolutionNote = arg.ResolutionNote
+	clone := *report
+
+	return &clone, nil
+}
+
+func (r *repository) UnhideQuestion(_ context.Context, id string) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	question, ok := r.questions[id]
+	if !ok || question.DeletedAt.Valid {
+		return 0, nil
+	}
+
+	question.IsHidden = false
+
+	return 1, nil
+}
+
+func (r *repository) GetQuestionById(_ context.Context, id string) (*questions.Question, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	question, ok := r.questions[id]
+	if !ok || question.DeletedAt.Valid {
+		return nil, nil //nolint:nilnil
+	}
+
+	clone := *question
+
+	return &clone, nil
+}
+
+type activeUsers struct {
+	moderation.Users
+}
+
+func (activeUsers) EnsureActive(context.Context, string) error {
+	return nil
+}
+
+type discardAudit struct{}
+
+func (discardAudit) Record(context.Context, *audit.Change) error {
+	return nil
+}
+
+type fixture struct {
+	service  *moderation.Service
+	repo     *repository
+	recorder *outboxtest.Recorder
+}
+
+// newFixture holds back a question of the asker, flagged by the filter or
+// reported by a user.
+func newFixture(reason string, isAnonymous bool) *fixture {
+	repo := &repository{ //nolint:exhaustruct
+		reports: map[string]*moderation.Report{
+			"r1": {
+				Id:         "r1",
+				EntityType: moderation.EntityQuestion,
+				EntityId:   "q1",
+				Reason:     reason,
+				Status:     moderation.StatusOpen,
+			},
+		},
+		questions: map[string]*questions.Question{
+			"q1": {
+				Id:          "q1",
+				UserId:      askerId,
+				Content:     "When is the next meetup?",
+				IsHidden:    reason == moderation.ReasonAutomated,
+				IsAnonymous: isAnonymous,
+			},
+		},
+	}
+	recorder := outboxtest.NewRecorder()
+
+	return &fixture{
+		service:  moderation.NewService(repo, activeUsers{}, discardAudit{}, recorder),
+		repo:     repo,
+		recorder: recorder,
+	}
+}
+
+func (f *fixture) dismiss(t *testing.T) {
+	t.Helper()
+
+	_, err := f.service.Resolve(context.Background(), moderatorId, "r1", &moderation.ResolveInput{
+		Action: moderation.ActionDismiss,
+		Note:   "",
+	})
+	if err != nil {
+		t.Fatalf("dismissing: %v", err)
+	}
+}
+
+func (f *fixture) created(t *testing.T) []*questions.QuestionCreatedEvent {
+	t.Helper()
+
+	result := []*questions.QuestionCreatedEvent{}
+
+	for _, envelope := range f.recorder.Envelopes(questions.EventQuestionCreated) {
+		var payload questions.QuestionCreatedEvent
+
+		err := json.Unmarshal(envelope.Payload, &payload)
+		if err != nil {
+			t.Fatalf("decoding %s: %v", envelope.EventType, err)
+		}
+
+		result = append(result, &payload)
+	}
+
+	return result
+}
+
+func TestDismissingAutomatedReportAnnouncesQuestion(t *testing.T) {
+	t.Parallel()
+
+	f := newFixture(moderation.ReasonAutomated, false)
+	f.dismiss(t)
+
+	if f.repo.questions["q1"].IsHidden {
+		t.Errorf("got the question hidden, want it let through")
+	}
+
+	created := f.created(t)
+	if len(created) != 1 || created[0].QuestionId != "q1" || created[0].UserId != askerId {
+		t.Fatalf("got created events %+v, want one of q1 by the asker", created)
+	}
+
+	if got := len(f.recorder.Envelopes(moderation.EventContentModerated)); got != 1 {
+		t.Errorf("got %d moderated events, want 1", got)
+	}
+}
+
+func TestDismissingAutomatedReportKeepsAskerAnonymous(t *testing.T) {
+	t.Parallel()
+
+	f := newFixture(moderation.ReasonAutomated, true)
+	f.dismiss(t)
+
+	created := f.created(t)
+	if len(created) != 1 || created[0].UserId != "" {
+		t.Errorf("got created events %+v, want one without the asker", created)
+	}
+}
+
+func TestDismissingUserReportAnnouncesNothing(t *testing.T) {
+	t.Parallel()
+
+	f := newFixture(moderation.ReasonSpam, false)
+	f.dismiss(t)
+
+	if created := f.created(t); len(created) != 0 {
+		t.Errorf("got created events %+v for a question that was never held back", created)
+	}
+}
+
+func TestDismissingAutomatedReportOfDeletedQuestion(t *testing.T) {
+	t.Parallel()
+
+	f := newFixture(moderation.ReasonAutomated, false)
+	f.repo.questions["q1"].DeletedAt = sql.NullTime{Valid: true} //nolint:exhaustruct
+
+	f.dismiss(t)
+
+	if created := f.created(t); len(created) != 0 {
+		t.Errorf("got created events %+v for a deleted question", created)
+	}
+}
@@ -0,0 +1,106 @@
+package moderation
+
+import (
+	"slices"
+
+	"github.com/oklog/ulid/v2"
+)
+
+const (
+	EntityStory    = "story"
+	EntityQuestion = "question"
+	EntityProfile  = "profile"
+
+	StatusOpen      = "open"
+	StatusActioned  = "actioned"
+	StatusDismissed = "dismissed"
+
+	ActionHide    = "hide"
+	ActionDelete  = "delete"
+	ActionSuspend = "suspend"
+	ActionDismiss = "dismiss"
+
+	ReasonSpam           = "spam"
+	ReasonHarassment     = "harassment"
+	ReasonInappropriate  = "inappropriate"
+	ReasonMisinformation = "misinformation"
+	ReasonOther          = "other"
+	ReasonAutomated      = "automated" // set by the content filter, not open to users
+
+	MaxNoteLength = 1000
+
+	EventContentModerated = "moderation.content-moderated"
+)
+
+var reasons = []string{ //nolint:gochecknoglobals
+	ReasonSpam, ReasonHarassment, ReasonInappropriate, ReasonMisinformation, ReasonOther,
+}
+
+// actions lists what moderators can do to each kind of content. Profiles
+// can't be hidden, only deleted.
+var actions = map[string][]string{ //nolint:gochecknoglobals
+	EntityStory:    {ActionHide, ActionDelete, ActionSuspend},
+	EntityQuestion: {ActionHide, ActionDelete, ActionSuspend},
+	EntityProfile:  {ActionDelete, ActionSuspend},
+}
+
+type RecordID string
+
+type RecordIDGenerator func() RecordID
+
+func DefaultIDGenerator() RecordID {
+	return RecordID(ulid.Make().String())
+}
+
+func IsReason(reason string) bool {
+	return slices.Contains(reasons, reason)
+}
+
+// IsReportable reports whether users can report the kind of content.
+func IsReportable(entityType string) bool {
+	_, ok := actions[entityType]
+
+	return ok
+}
+
+// CanApply reports whether the action can be taken on the kind of content.
+// Dismissing applies to all.
+func CanApply(entityType string, action string) bool {
+	return action == ActionDismiss || slices.Contains(actions[entityType], action)
+}
+
+type ReportInput struct {
+	EntityType string `json:"entityType"`
+	EntityId   string `json:"entityId"`
+	Reason     string `json:"reason"`
+	Note       string `json:"note"`
+}
+
+// ResolveInput closes a report. Actions other than dismiss are taken on the
+// reported content, or on its owner for suspend, and close all the open
+// reports of that content.
+type ResolveInput struct {
+	Action string `json:"action"`
+	Note   string `json:"note"`
+}
+
+// ContentModeratedEvent is the payload of EventContentModerated, recorded
+// when moderation hides or deletes content, or lets content held back by the
+// filter through, in which case Action is dismiss.
+type ContentModeratedEvent struct {
+	EntityType string `json:"entityType"`
+	EntityId   string `json:"entityId"`
+	Action     string `json:"action"`
+	ReportId   string `json:"reportId"`
+}
+
+type SuspendInput struct {
+	Reason string `json:"reason"`
+}
+
+type ListFilter struct {
+	Status     string
+	EntityType string
+	Limit      int32
+	Offset     int32
+}
@@ -0,0 +1,6 @@
+package notifications
+
+type Config struct {
+	SiteUrl         string `conf:"SITE_URL" default:"https://acik.io"`      // public address notification links point to
+	DefaultChannels string `conf:"DEFAULT_CHANNELS" default:"in_app,email"` // channels of the types a user has set no preference for
+}
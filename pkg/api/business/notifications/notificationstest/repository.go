@@ -0,0 +1,264 @@
+// Package notificationstest provides in-memory dependencies of the
+// notifications service for tests.
+package notificationstest
+
+import (
+	"context"
+	"database/sql"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/eser/acik.io/pkg/api/business/notifications"
+	"github.com/eser/acik.io/pkg/api/business/users"
+)
+
+// Repository keeps notifications and preferences in memory, following the
+// semantics of the queries in notifications.sql. Transactions are not
+// isolated; the function is run as is.
+type Repository struct {
+	notifications []*notifications.Notification
+	preferences   []*notifications.NotificationPreference
+	mu            sync.Mutex
+}
+
+var _ notifications.Repository = (*Repository)(nil)
+
+func NewRepository() *Repository {
+	return &Repository{} //nolint:exhaustruct
+}
+
+// Notifications returns copies of the notifications of a user, in the order
+// they were created.
+func (r *Repository) Notifications(userId string) []*notifications.Notification {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	result := []*notifications.Notification{}
+
+	for _, notification := range r.notifications {
+		if notification.UserId == userId {
+			clone := *notification
+			result = append(result, &clone)
+		}
+	}
+
+	return result
+}
+
+func (r *Repository) Transact(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
+
+func (r *Repository) InsertNotification(
+	_ context.Context,
+	arg notifications.InsertNotificationParams,
+) (*notifications.Notification, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, notification := range r.notifications {
+		if notification.UserId == arg.UserId && notification.Type == arg.Type && notification.SourceId == arg.SourceId {
+			return nil, nil //nolint:nilnil
+		}
+	}
+
+	notification := &notifications.Notification{
+		Id:        arg.Id,
+		UserId:    arg.UserId,
+		Type:      arg.Type,
+		SourceId:  arg.SourceId,
+		Title:     arg.Title,
+		Body:      arg.Body,
+		LinkUri:   arg.LinkUri,
+		Data:      arg.Data,
+		Channels:  arg.Channels,
+		ReadAt:    sql.NullTime{}, //nolint:exhaustruct
+		CreatedAt: time.Now(),
+	}
+
+	r.notifications = append(r.notifications, notification)
+	clone := *notification
+
+	return &clone, nil
+}
+
+func (r *Repository) GetNotificationById(_ context.Context, id string) (*notifications.Notification, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, notification := range r.notifications {
+		if notification.Id == id {
+			clone := *notification
+
+			return &clone, nil
+		}
+	}
+
+	return nil, nil //nolint:nilnil
+}
+
+func (r *Repository) ListInboxNotifications(
+	_ context.Context,
+	arg notifications.ListInboxNotificationsParams,
+) ([]*notifications.Notification, error) {
+	inbox := r.inbox(arg.UserId, arg.UnreadOnly)
+	slices.Reverse(inbox)
+
+	start := min(int(arg.OffsetCount), len(inbox))
+	end := min(start+int(arg.LimitCount), len(inbox))
+
+	return inbox[start:end], nil
+}
+
+func (r *Repository) CountUnreadInboxNotifications(_ context.Context, userId string) (int64, error) {
+	return int64(len(r.inbox(userId, true))), nil
+}
+
+func (r *Repository) MarkNotificationRead(
+	_ context.Context,
+	arg notifications.MarkNotificationReadParams,
+) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, notification := range r.notifications {
+		if notification.Id == arg.Id && notification.UserId == arg.UserId {
+			if !notification.ReadAt.Valid {
+				notification.ReadAt = sql.NullTime{Time: time.Now(), Valid: true}
+			}
+
+			return 1, nil
+		}
+	}
+
+	return 0, nil
+}
+
+func (r *Repository) MarkAllNotificationsRead(_ context.Context, userId string) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var affected int64
+
+	for _, notification := range r.notifications {
+		if notification.UserId == userId && !notification.ReadAt.Valid {
+			notification.ReadAt = sql.NullTime{Time: time.Now(), Valid: true}
+			affected++
+		}
+	}
+
+	return affected, nil
+}
+
+func (r *Repository) ListNotificationPreferences(
+	_ context.Context,
+	userId string,
+) ([]*notifications.NotificationPreference, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	result := []*notifications.NotificationPreference{}
+
+	for _, preference := range r.preferences {
+		if preference.UserId == userId {
+			clone := *preference
+			result = append(result, &clone)
+		}
+	}
+
+	slices.SortFunc(result, func(a, b *notifications.NotificationPreference) int {
+		return strings.Compare(a.Type, b.Type)
+	})
+
+	return result, nil
+}
+
+func (r *Repository) GetNotificationPreference(
+	_ context.Context,
+	arg notifications.GetNotificationPreferenceParams,
+) (*notifications.NotificationPreference, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, preference := range r.preferences {
+		if preference.UserId == arg.UserId && preference.Type == arg.Type {
+			clone := *preference
+
+			return &clone, nil
+		}
+	}
+
+	return nil, nil //nolint:nilnil
+}
+
+func (r *Repository) UpsertNotificationPreference(
+	_ context.Context,
+	arg notifications.UpsertNotificationPreferenceParams,
+) (*notifications.NotificationPreference, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, preference := range r.preferences {
+		if preference.UserId == arg.UserId && preference.Type == arg.Type {
+			preference.Channels = arg.Channels
+			preference.UpdatedAt = sql.NullTime{Time: time.Now(), Valid: true}
+			clone := *preference
+
+			return &clone, nil
+		}
+	}
+
+	preference := &notifications.NotificationPreference{
+		UserId:    arg.UserId,
+		Type:      arg.Type,
+		Channels:  arg.Channels,
+		CreatedAt: time.Now(),
+		UpdatedAt: sql.NullTime{}, //nolint:exhaustruct
+	}
+
+	r.preferences = append(r.preferences, preference)
+	clone := *preference
+
+	return &clone, nil
+}
+
+func (r *Repository) inbox(userId string, unreadOnly bool) []*notifications.Notification {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	result := []*notifications.Notification{}
+
+	for _, notification := range r.notifications {
+		if notification.UserId != userId ||
+			!slices.Contains(strings.Split(notification.Channels, ","), notifications.ChannelInApp) ||
+			(unreadOnly && notification.ReadAt.Valid) {
+			continue
+		}
+
+		clone := *notification
+		result = append(result, &clone)
+	}
+
+	return result
+}
+
+// Users is a user directory over a fixed set of users.
+type Users map[string]*users.User
+
+func (u Users) GetById(_ context.Context, id string) (*users.User, error) {
+	user, ok := u[id]
+	if !ok {
+		return nil, users.ErrRecordNotFound
+	}
+
+	return user, nil
+}
+
+// Attendees lists the attendees of events from a fixed set.
+type Attendees map[string][]string
+
+func (a Attendees) ListAttendeeUserIds(_ context.Context, eventId string) ([]string, error) {
+	return a[eventId], nil
+}
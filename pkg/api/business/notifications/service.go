@@ -0,0 +1,500 @@
+package notifications
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/eser/acik.io/pkg/api/business/events"
+	"github.com/eser/acik.io/pkg/api/business/outbox"
+	"github.com/eser/acik.io/pkg/api/business/profiles"
+	"github.com/eser/acik.io/pkg/api/business/questions"
+	"github.com/eser/acik.io/pkg/api/business/users"
+)
+
+const MaxInboxPageSize = 100
+
+var (
+	ErrFailedToCreateRecord  = errors.New("failed to create record")
+	ErrFailedToGetRecord     = errors.New("failed to get record")
+	ErrFailedToListRecords   = errors.New("failed to list records")
+	ErrFailedToUpdateRecord  = errors.New("failed to update record")
+	ErrFailedToDecodePayload = errors.New("failed to decode payload")
+	ErrFailedToDeliver       = errors.New("failed to deliver notification")
+	ErrRecordNotFound        = errors.New("record not found")
+	ErrUnknownType           = errors.New("unknown notification type")
+	ErrUnknownChannel        = errors.New("unknown notification channel")
+	ErrChannelNotAvailable   = errors.New("notification channel is not available")
+)
+
+type Repository interface {
+	Transact(ctx context.Context, fn func(ctx context.Context) error) error
+	InsertNotification(ctx context.Context, arg InsertNotificationParams) (*Notification, error)
+	GetNotificationById(ctx context.Context, id string) (*Notification, error)
+	ListInboxNotifications(ctx context.Context, arg ListInboxNotificationsParams) ([]*Notification, error)
+	CountUnreadInboxNotifications(ctx context.Context, userId string) (int64, error)
+	MarkNotificationRead(ctx context.Context, arg MarkNotificationReadParams) (int64, error)
+	MarkAllNotificationsRead(ctx context.Context, userId string) (int64, error)
+	ListNotificationPreferences(ctx context.Context, userId string) ([]*NotificationPreference, error)
+	GetNotificationPreference(ctx context.Context, arg GetNotificationPreferenceParams) (*NotificationPreference, error)
+	UpsertNotificationPreference(
+		ctx context.Context,
+		arg UpsertNotificationPreferenceParams,
+	) (*NotificationPreference, error)
+}
+
+// Channel delivers notifications out of band, like email or webhooks.
+// Delivery is at least once, so a channel may be asked to deliver the same
+// notification again after a failure.
+type Channel interface {
+	Send(ctx context.Context, recipient *Recipient, notification *Notification) error
+}
+
+// UserDirectory resolves the users notifications are delivered to.
+type UserDirectory interface {
+	GetById(ctx context.Context, id string) (*users.User, error)
+}
+
+// EventAttendees resolves the users attending an event.
+type EventAttendees interface {
+	ListAttendeeUserIds(ctx context.Context, eventId string) ([]string, error)
+}
+
+// EventRecorder records domain events. It is called within the transaction
+// of the state change the event describes.
+type EventRecorder interface {
+	Record(ctx context.Context, aggregateType string, aggregateId string, eventType string, payload any) error
+}
+
+type Service struct {
+	config    *Config
+	repo      Repository
+	users     UserDirectory
+	attendees EventAttendees
+	events    EventRecorder
+	channels  map[string]Channel
+
+	idGenerator RecordIDGenerator
+}
+
+func NewService(
+	config *Config,
+	repo Repository,
+	users UserDirectory,
+	attendees EventAttendees,
+	events EventRecorder,
+	channels map[string]Channel,
+) *Service {
+	return &Service{
+		config:      config,
+		repo:        repo,
+		users:       users,
+		attendees:   attendees,
+		events:      events,
+		channels:    channels,
+		idGenerator: DefaultIDGenerator,
+	}
+}
+
+// Notify creates a notification through the channels the user prefers for its
+// type. It returns nil without an error when the user muted the type or was
+// already notified of the same source. Out of band channels are delivered
+// asynchronously, through the notification.created domain event.
+func (s *Service) Notify(ctx context.Context, input *Input) (*Notification, error) {
+	if !slices.Contains(Types, input.Type) {
+		return nil, fmt.Errorf("%w(type: %s)", ErrUnknownType, input.Type)
+	}
+
+	channels, err := s.channelsFor(ctx, input.UserId, input.Type)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(channels) == 0 {
+		return nil, nil //nolint:nilnil
+	}
+
+	data, err := json.Marshal(input.Data)
+	if err != nil {
+		return nil, fmt.Errorf("%w(type: %s): %w", ErrFailedToCreateRecord, input.Type, err)
+	}
+
+	var record *Notification
+
+	err = s.repo.Transact(ctx, func(ctx context.Context) error {
+		record, err = s.repo.InsertNotification(ctx, InsertNotificationParams{
+			Id:       string(s.idGenerator()),
+			UserId:   input.UserId,
+			Type:     input.Type,
+			SourceId: input.SourceId,
+			Title:    input.Title,
+			Body:     input.Body,
+			LinkUri:  sql.NullString{String: input.LinkUri, Valid: input.LinkUri != ""},
+			Data:     data,
+			Channels: strings.Join(channels, ","),
+		})
+		if err != nil {
+			return fmt.Errorf("%w(user: %s, type: %s): %w", ErrFailedToCreateRecord, input.UserId, input.Type, err)
+		}
+
+		outOfBand := slices.DeleteFunc(slices.Clone(channels), func(channel string) bool {
+			return channel == ChannelInApp
+		})
+
+		if record == nil || len(outOfBand) == 0 {
+			return nil
+		}
+
+		return s.events.Record( //nolint:wrapcheck
+			ctx,
+			AggregateNotification,
+			record.Id,
+			EventNotificationCreated,
+			&NotificationCreatedEvent{
+				CreatedAt:      record.CreatedAt,
+				NotificationId: record.Id,
+				UserId:         record.UserId,
+				Type:           record.Type,
+				Channels:       outOfBand,
+				after:          record,
+			},
+		)
+	})
+	if err != nil {
+		return nil, err //nolint:wrapcheck
+	}
+
+	return record, nil
+}
+
+// Inbox returns the in-app notifications of a user, the latest first, along
+// with the number of unread ones.
+func (s *Service) Inbox(
+	ctx context.Context,
+	userId string,
+	unreadOnly bool,
+	limit int32,
+	offset int32,
+) (*Inbox, error) {
+	if limit <= 0 || limit > MaxInboxPageSize {
+		limit = MaxInboxPageSize
+	}
+
+	records, err := s.repo.ListInboxNotifications(ctx, ListInboxNotificationsParams{
+		UserId:      userId,
+		UnreadOnly:  unreadOnly,
+		LimitCount:  limit,
+		OffsetCount: max(offset, 0),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%w(user: %s): %w", ErrFailedToListRecords, userId, err)
+	}
+
+	unread, err := s.repo.CountUnreadInboxNotifications(ctx, userId)
+	if err != nil {
+		return nil, fmt.Errorf("%w(user: %s): %w", ErrFailedToListRecords, userId, err)
+	}
+
+	return &Inbox{Items: records, UnreadCount: unread}, nil
+}
+
+func (s *Service) MarkRead(ctx context.Context, userId string, id string) error {
+	affected, err := s.repo.MarkNotificationRead(ctx, MarkNotificationReadParams{Id: id, UserId: userId})
+	if err != nil {
+		return fmt.Errorf("%w(id: %s): %w", ErrFailedToUpdateRecord, id, err)
+	}
+
+	if affected == 0 {
+		return fmt.Errorf("%w(id: %s)", ErrRecordNotFound, id)
+	}
+
+	return nil
+}
+
+// MarkAllRead marks every unread notification of a user as read and returns
+// how many there were.
+func (s *Service) MarkAllRead(ctx context.Context, userId string) (int64, error) {
+	affected, err := s.repo.MarkAllNotificationsRead(ctx, userId)
+	if err != nil {
+		return 0, fmt.Errorf("%w(user: %s): %w", ErrFailedToUpdateRecord, userId, err)
+	}
+
+	return affected, nil
+}
+
+// Preferences returns the channels of every notification type for a user,
+// falling back to the default channels for the types they did not set.
+func (s *Service) Preferences(ctx context.Context, userId string) ([]*Preference, error) {
+	records, err := s.repo.ListNotificationPreferences(ctx, userId)
+	if err != nil {
+		return nil, fmt.Errorf("%w(user: %s): %w", ErrFailedToListRecords, userId, err)
+	}
+
+	preferences := make([]*Preference, 0, len(Types))
+
+	for _, notificationType := range Types {
+		preference := &Preference{
+			Type:      notificationType,
+			Channels:  s.defaultChannels(),
+			IsDefault: true,
+		}
+
+		for _, record := range records {
+			if record.Type == notificationType {
+				preference.Channels = splitChannels(record.Channels)
+				preference.IsDefault = false
+			}
+		}
+
+		preferences = append(preferences, preference)
+	}
+
+	return preferences, nil
+}
+
+// SetPreference sets the channels a user is notified through for a type. No
+// channels mutes the type.
+func (s *Service) SetPreference(
+	ctx context.Context,
+	userId string,
+	notificationType string,
+	input *PreferenceInput,
+) (*Preference, error) {
+	if !slices.Contains(Types, notificationType) {
+		return nil, fmt.Errorf("%w(type: %s)", ErrUnknownType, notificationType)
+	}
+
+	channels := make([]string, 0, len(input.Channels))
+
+	for _, channel := range input.Channels {
+		if !slices.Contains(Channels, channel) {
+			return nil, fmt.Errorf("%w(channel: %s)", ErrUnknownChannel, channel)
+		}
+
+		channels = append(channels, channel)
+	}
+
+	slices.Sort(channels)
+	channels = slices.Compact(channels)
+
+	var after *Preference
+
+	err := s.repo.Transact(ctx, func(ctx context.Context) error {
+		current, err := s.repo.GetNotificationPreference(ctx, GetNotificationPreferenceParams{
+			UserId: userId,
+			Type:   notificationType,
+		})
+		if err != nil {
+			return fmt.Errorf("%w(user: %s, type: %s): %w", ErrFailedToGetRecord, userId, notificationType, err)
+		}
+
+		before := &Preference{Type: notificationType, Channels: s.defaultChannels(), IsDefault: true}
+		if current != nil {
+			before = &Preference{Type: current.Type, Channels: splitChannels(current.Channels), IsDefault: false}
+		}
+
+		record, err := s.repo.UpsertNotificationPreference(ctx, UpsertNotificationPreferenceParams{
+			UserId:   userId,
+			Type:     notificationType,
+			Channels: strings.Join(channels, ","),
+		})
+		if err != nil {
+			return fmt.Errorf("%w(user: %s, type: %s): %w", ErrFailedToUpdateRecord, userId, notificationType, err)
+		}
+
+		after = &Preference{Type: record.Type, Channels: splitChannels(record.Channels), IsDefault: false}
+
+		return s.events.Record(ctx, AggregatePreferences, userId, EventPreferenceChanged, &PreferenceChangedEvent{ //nolint:wrapcheck
+			UserId:   userId,
+			Type:     record.Type,
+			Channels: after.Channels,
+			before:   before,
+			after:    after,
+		})
+	})
+	if err != nil {
+		return nil, err //nolint:wrapcheck
+	}
+
+	return after, nil
+}
+
+// HandleDomainEvent turns the domain events users should hear about into
+// notifications. Notifications are unique per user and source event, so
+// handling an event again is harmless.
+func (s *Service) HandleDomainEvent(ctx context.Context, envelope *outbox.Envelope) error {
+	switch envelope.EventType {
+	case questions.EventQuestionAnswered:
+		var payload questions.QuestionAnsweredEvent
+
+		err := decode(envelope, &payload)
+		if err != nil {
+			return err
+		}
+
+		_, err = s.Notify(ctx, &Input{
+			Data:     &payload,
+			UserId:   payload.UserId,
+			Type:     TypeQuestionAnswered,
+			SourceId: envelope.Id,
+			Title:    "Your question was answered",
+			Body:     payload.Content,
+			LinkUri:  payload.AnswerUri,
+		})
+
+		return err
+	case events.EventEventRescheduled:
+		var payload events.EventRescheduledEvent
+
+		err := decode(envelope, &payload)
+		if err != nil {
+			return err
+		}
+
+		userIds, err := s.attendees.ListAttendeeUserIds(ctx, payload.EventId)
+		if err != nil {
+			return err //nolint:wrapcheck
+		}
+
+		errs := make([]error, 0, len(userIds))
+
+		for _, userId := range userIds {
+			_, err := s.Notify(ctx, &Input{
+				Data:     &payload,
+				UserId:   userId,
+				Type:     TypeEventRescheduled,
+				SourceId: envelope.Id,
+				Title:    fmt.Sprintf("%s has been rescheduled", payload.Title),
+				Body: fmt.Sprintf(
+					"The event now starts at %s.",
+					payload.TimeStart.UTC().Format("2 Jan 2006 15:04 MST"),
+				),
+				LinkUri: "/events/" + payload.Slug,
+			})
+
+			errs = append(errs, err)
+		}
+
+		return errors.Join(errs...)
+	case profiles.EventMemberInvited:
+		var payload profiles.MemberInvitedEvent
+
+		err := decode(envelope, &payload)
+		if err != nil {
+			return err
+		}
+
+		_, err = s.Notify(ctx, &Input{
+			Data:     &payload,
+			UserId:   payload.UserId,
+			Type:     TypeProfileInvitation,
+			SourceId: envelope.Id,
+			Title:    fmt.Sprintf("You were invited to %s", payload.ProfileTitle),
+			Body:     fmt.Sprintf("You were invited to join %s as %s.", payload.ProfileTitle, payload.Kind),
+			LinkUri:  "/profiles/" + payload.ProfileSlug,
+		})
+
+		return err
+	default:
+		return nil
+	}
+}
+
+// Deliver sends a created notification through its out of band channels.
+func (s *Service) Deliver(ctx context.Context, envelope *outbox.Envelope) error {
+	var payload NotificationCreatedEvent
+
+	err := decode(envelope, &payload)
+	if err != nil {
+		return err
+	}
+
+	notification, err := s.repo.GetNotificationById(ctx, payload.NotificationId)
+	if err != nil {
+		return fmt.Errorf("%w(id: %s): %w", ErrFailedToGetRecord, payload.NotificationId, err)
+	}
+
+	if notification == nil {
+		return nil
+	}
+
+	user, err := s.users.GetById(ctx, notification.UserId)
+	if errors.Is(err, users.ErrRecordNotFound) {
+		return nil
+	}
+
+	if err != nil {
+		return err //nolint:wrapcheck
+	}
+
+	recipient := &Recipient{
+		UserId:              user.Id,
+		Name:                user.Name,
+		Email:               user.Email.String,
+		IndividualProfileId: user.IndividualProfileId.String,
+	}
+
+	errs := make([]error, 0, len(payload.Channels))
+
+	for _, name := range payload.Channels {
+		channel, ok := s.channels[name]
+		if !ok {
+			errs = append(errs, fmt.Errorf("%w(channel: %s)", ErrChannelNotAvailable, name))
+
+			continue
+		}
+
+		err := channel.Send(ctx, recipient, notification)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%w(id: %s, channel: %s): %w", ErrFailedToDeliver, notification.Id, name, err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+func (s *Service) channelsFor(ctx context.Context, userId string, notificationType string) ([]string, error) {
+	record, err := s.repo.GetNotificationPreference(ctx, GetNotificationPreferenceParams{
+		UserId: userId,
+		Type:   notificationType,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%w(user: %s, type: %s): %w", ErrFailedToGetRecord, userId, notificationType, err)
+	}
+
+	if record == nil {
+		return s.defaultChannels(), nil
+	}
+
+	return splitChannels(record.Channels), nil
+}
+
+func (s *Service) defaultChannels() []string {
+	return splitChannels(s.config.DefaultChannels)
+}
+
+func splitChannels(channels string) []string {
+	result := make([]string, 0, len(Channels))
+
+	for channel := range strings.SplitSeq(channels, ",") {
+		channel = strings.TrimSpace(channel)
+		if channel != "" {
+			result = append(result, channel)
+		}
+	}
+
+	return result
+}
+
+func decode(envelope *outbox.Envelope, payload any) error {
+	err := json.Unmarshal(envelope.Payload, payload)
+	if err != nil {
+		return fmt.Errorf("%w(event: %s): %w", ErrFailedToDecodePayload, envelope.Id, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,378 @@
+package notifications_test
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/eser/acik.io/pkg/api/business/events"
+	"github.com/eser/acik.io/pkg/api/business/notifications"
+	"github.com/eser/acik.io/pkg/api/business/notifications/notificationstest"
+	"github.com/eser/acik.io/pkg/api/business/outbox"
+	"github.com/eser/acik.io/pkg/api/business/outbox/outboxtest"
+	"github.com/eser/acik.io/pkg/api/business/profiles"
+	"github.com/eser/acik.io/pkg/api/business/questions"
+	"github.com/eser/acik.io/pkg/api/business/users"
+)
+
+const (
+	userAyse   = "01HUSERAYSE000000000000000"
+	userMehmet = "01HUSERMEHMET0000000000000"
+	eventId    = "01HEVENT0000000000000000000"
+)
+
+// channel records the notifications it is asked to send.
+type channel struct {
+	sent []*notifications.Recipient
+	mu   sync.Mutex
+}
+
+func (c *channel) Send(_ context.Context, recipient *notifications.Recipient, _ *notifications.Notification) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.sent = append(c.sent, recipient)
+
+	return nil
+}
+
+type fixture struct {
+	service  *notifications.Service
+	repo     *notificationstest.Repository
+	recorder *outboxtest.Recorder
+	email    *channel
+}
+
+func newFixture() *fixture {
+	repo := notificationstest.NewRepository()
+	recorder := outboxtest.NewRecorder()
+	email := &channel{} //nolint:exhaustruct
+
+	service := notifications.NewService(
+		&notifications.Config{SiteUrl: "https://acik.io", DefaultChannels: "in_app,email"},
+		repo,
+		notificationstest.Users{
+			userAyse: &users.User{ //nolint:exhaustruct
+				Id:    userAyse,
+				Name:  "Ayşe",
+				Email: sql.NullString{String: "ayse@example.com", Valid: true},
+			},
+		},
+		notificationstest.Attendees{eventId: {userAyse, userMehmet}},
+		recorder,
+		map[string]notifications.Channel{notifications.ChannelEmail: email},
+	)
+
+	return &fixture{service: service, repo: repo, recorder: recorder, email: email}
+}
+
+func input(userId string, sourceId string) *notifications.Input {
+	return &notifications.Input{
+		Data:     nil,
+		UserId:   userId,
+		Type:     notifications.TypeQuestionAnswered,
+		SourceId: sourceId,
+		Title:    "Your question was answered",
+		Body:     "42",
+		LinkUri:  "/questions/1",
+	}
+}
+
+func createdEvents(t *testing.T, recorder *outboxtest.Recorder) []*notifications.NotificationCreatedEvent {
+	t.Helper()
+
+	envelopes := recorder.Envelopes(notifications.EventNotificationCreated)
+	result := make([]*notifications.NotificationCreatedEvent, len(envelopes))
+
+	for i, envelope := range envelopes {
+		var payload notifications.NotificationCreatedEvent
+
+		err := json.Unmarshal(envelope.Payload, &payload)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		result[i] = &payload
+	}
+
+	return result
+}
+
+func TestNotifyUsesDefaultChannels(t *testing.T) {
+	t.Parallel()
+
+	f := newFixture()
+
+	record, err := f.service.Notify(context.Background(), input(userAyse, "source-1"))
+	if err != nil {
+		t.Fatalf("notifying: %v", err)
+	}
+
+	if record == nil || record.Channels != "in_app,email" {
+		t.Fatalf("got notification %+v, want one through in_app and email", record)
+	}
+
+	created := createdEvents(t, f.recorder)
+	if len(created) != 1 || !slices.Equal(created[0].Channels, []string{notifications.ChannelEmail}) {
+		t.Errorf("got created events %+v, want one for the email channel", created)
+	}
+}
+
+func TestNotifyHonorsPreferences(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name          string
+		channels      []string
+		wantRecord    bool
+		wantInbox     bool
+		wantOutOfBand []string
+	}{
+		{name: "muted", channels: []string{}, wantRecord: false, wantInbox: false, wantOutOfBand: nil},
+		{name: "inbox only", channels: []string{notifications.ChannelInApp}, wantRecord: true, wantInbox: true, wantOutOfBand: nil},
+		{
+			name:          "email opted out",
+			channels:      []string{notifications.ChannelWebhook, notifications.ChannelInApp},
+			wantRecord:    true,
+			wantInbox:     true,
+			wantOutOfBand: []string{notifications.ChannelWebhook},
+		},
+		{
+			name:          "email only",
+			channels:      []string{notifications.ChannelEmail},
+			wantRecord:    true,
+			wantInbox:     false,
+			wantOutOfBand: []string{notifications.ChannelEmail},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			f := newFixture()
+			ctx := context.Background()
+
+			_, err := f.service.SetPreference(
+				ctx,
+				userAyse,
+				notifications.TypeQuestionAnswered,
+				&notifications.PreferenceInput{Channels: test.channels},
+			)
+			if err != nil {
+				t.Fatalf("setting preference: %v", err)
+			}
+
+			record, err := f.service.Notify(ctx, input(userAyse, "source-1"))
+			if err != nil {
+				t.Fatalf("notifying: %v", err)
+			}
+
+			if (record != nil) != test.wantRecord {
+				t.Errorf("got notification %+v, want one: %t", record, test.wantRecord)
+			}
+
+			inbox, err := f.service.Inbox(ctx, userAyse, false, 0, 0)
+			if err != nil {
+				t.Fatalf("listing inbox: %v", err)
+			}
+
+			if (len(inbox.Items) == 1) != test.wantInbox {
+				t.Errorf("got %d notifications in the inbox, want one: %t", len(inbox.Items), test.wantInbox)
+			}
+
+			created := createdEvents(t, f.recorder)
+
+			switch {
+			case test.wantOutOfBand == nil && len(created) != 0:
+				t.Errorf("got created events %+v, want none", created)
+			case test.wantOutOfBand != nil && (len(created) != 1 || !slices.Equal(created[0].Channels, test.wantOutOfBand)):
+				t.Errorf("got created events %+v, want one for %v", created, test.wantOutOfBand)
+			}
+
+			// other types keep the defaults.
+			other := input(userAyse, "source-2")
+			other.Type = notifications.TypeProfileInvitation
+
+			record, err = f.service.Notify(ctx, other)
+			if err != nil || record == nil || record.Channels != "in_app,email" {
+				t.Errorf("got notification %+v (%v) of another type, want the default channels", record, err)
+			}
+		})
+	}
+}
+
+func TestSetPreferenceRejectsUnknownChannels(t *testing.T) {
+	t.Parallel()
+
+	_, err := newFixture().service.SetPreference(
+		context.Background(),
+		userAyse,
+		notifications.TypeQuestionAnswered,
+		&notifications.PreferenceInput{Channels: []string{"pigeon"}},
+	)
+	if !errors.Is(err, notifications.ErrUnknownChannel) {
+		t.Errorf("got %v, want %v", err, notifications.ErrUnknownChannel)
+	}
+}
+
+func TestNotifyOncePerSource(t *testing.T) {
+	t.Parallel()
+
+	f := newFixture()
+
+	for range 2 {
+		_, err := f.service.Notify(context.Background(), input(userAyse, "source-1"))
+		if err != nil {
+			t.Fatalf("notifying: %v", err)
+		}
+	}
+
+	if got := len(f.repo.Notifications(userAyse)); got != 1 {
+		t.Errorf("got %d notifications, want 1", got)
+	}
+
+	if got := len(createdEvents(t, f.recorder)); got != 1 {
+		t.Errorf("got %d created events, want 1", got)
+	}
+}
+
+func TestHandleDomainEvent(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		envelope  func(t *testing.T) *outbox.Envelope
+		name      string
+		wantType  string
+		wantLink  string
+		wantUsers []string
+	}{
+		{
+			name: "question answered",
+			envelope: func(t *testing.T) *outbox.Envelope {
+				t.Helper()
+
+				return outboxtest.Envelope(t, questions.EventQuestionAnswered, &questions.QuestionAnsweredEvent{
+					QuestionId: "q1",
+					UserId:     userAyse,
+					Content:    "It depends.",
+					AnswerUri:  "https://youtu.be/answer",
+				})
+			},
+			wantType:  notifications.TypeQuestionAnswered,
+			wantLink:  "https://youtu.be/answer",
+			wantUsers: []string{userAyse},
+		},
+		{
+			name: "event rescheduled",
+			envelope: func(t *testing.T) *outbox.Envelope {
+				t.Helper()
+
+				return outboxtest.Envelope(t, events.EventEventRescheduled, &events.EventRescheduledEvent{ //nolint:exhaustruct
+					TimeStart: time.Date(2026, 11, 2, 18, 0, 0, 0, time.UTC),
+					TimeEnd:   time.Date(2026, 11, 2, 20, 0, 0, 0, time.UTC),
+					EventId:   eventId,
+					Slug:      "meetup",
+					Title:     "Meetup",
+				})
+			},
+			wantType:  notifications.TypeEventRescheduled,
+			wantLink:  "/events/meetup",
+			wantUsers: []string{userAyse, userMehmet},
+		},
+		{
+			name: "member invited",
+			envelope: func(t *testing.T) *outbox.Envelope {
+				t.Helper()
+
+				return outboxtest.Envelope(t, profiles.EventMemberInvited, &profiles.MemberInvitedEvent{
+					ProfileId:       "p1",
+					ProfileSlug:     "acik",
+					ProfileTitle:    "Açık",
+					UserId:          userMehmet,
+					Kind:            "member",
+					InvitedByUserId: userAyse,
+				})
+			},
+			wantType:  notifications.TypeProfileInvitation,
+			wantLink:  "/profiles/acik",
+			wantUsers: []string{userMehmet},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			f := newFixture()
+
+			err := f.service.HandleDomainEvent(context.Background(), test.envelope(t))
+			if err != nil {
+				t.Fatalf("handling event: %v", err)
+			}
+
+			for _, userId := range []string{userAyse, userMehmet} {
+				records := f.repo.Notifications(userId)
+
+				if !slices.Contains(test.wantUsers, userId) {
+					if len(records) != 0 {
+						t.Errorf("got %d notifications for %s, want none", len(records), userId)
+					}
+
+					continue
+				}
+
+				if len(records) != 1 {
+					t.Fatalf("got %d notifications for %s, want 1", len(records), userId)
+				}
+
+				if records[0].Type != test.wantType || records[0].LinkUri.String != test.wantLink {
+					t.Errorf("got %s notification linking to %q, want %s linking to %q",
+						records[0].Type, records[0].LinkUri.String, test.wantType, test.wantLink)
+				}
+			}
+		})
+	}
+}
+
+func TestDeliver(t *testing.T) {
+	t.Parallel()
+
+	f := newFixture()
+	ctx := context.Background()
+
+	_, err := f.service.SetPreference(
+		ctx,
+		userAyse,
+		notifications.TypeQuestionAnswered,
+		&notifications.PreferenceInput{Channels: []string{notifications.ChannelEmail, notifications.ChannelWebhook}},
+	)
+	if err != nil {
+		t.Fatalf("setting preference: %v", err)
+	}
+
+	_, err = f.service.Notify(ctx, input(userAyse, "source-1"))
+	if err != nil {
+		t.Fatalf("notifying: %v", err)
+	}
+
+	envelopes := f.recorder.Envelopes(notifications.EventNotificationCreated)
+	if len(envelopes) != 1 {
+		t.Fatalf("got %d created events, want 1", len(envelopes))
+	}
+
+	// the fixture has no webhook channel.
+	err = f.service.Deliver(ctx, envelopes[0])
+	if !errors.Is(err, notifications.ErrChannelNotAvailable) {
+		t.Errorf("got %v, want %v", err, notifications.ErrChannelNotAvailable)
+	}
+
+	if len(f.email.sent) != 1 || f.email.sent[0].Email != "ayse@example.com" {
+		t.Errorf("got email recipients %+v, want ayse@example.com", f.email.sent)
+	}
+}
@@ -0,0 +1,128 @@
+package notifications
+
+import (
+	"strings"
+	"time"
+
+	"github.com/eser/acik.io/pkg/api/business/events"
+	"github.com/eser/acik.io/pkg/api/business/profiles"
+	"github.com/eser/acik.io/pkg/api/business/questions"
+	"github.com/oklog/ulid/v2"
+)
+
+const (
+	ChannelInApp   = "in_app"
+	ChannelEmail   = "email"
+	ChannelWebhook = "webhook"
+
+	TypeQuestionAnswered  = "question-answered"
+	TypeEventRescheduled  = "event-rescheduled"
+	TypeProfileInvitation = "profile-invitation"
+
+	AggregateNotification = "notification"
+	// AggregatePreferences is the notification preferences of a user, whose
+	// id identifies them.
+	AggregatePreferences = "notification_preferences"
+
+	EventNotificationCreated = "notification.created"
+	EventPreferenceChanged   = "notification.preference-changed"
+)
+
+// Channels lists the channels a notification can be delivered through. The
+// in-app channel is the inbox itself; the others are delivered out of band.
+var Channels = []string{ChannelInApp, ChannelEmail, ChannelWebhook} //nolint:gochecknoglobals
+
+// Types lists the notification types users can set preferences for.
+var Types = []string{TypeQuestionAnswered, TypeEventRescheduled, TypeProfileInvitation} //nolint:gochecknoglobals
+
+// SourceEventTypes lists the domain events notifications are created for.
+var SourceEventTypes = []string{ //nolint:gochecknoglobals
+	questions.EventQuestionAnswered,
+	events.EventEventRescheduled,
+	profiles.EventMemberInvited,
+}
+
+type RecordID string
+
+type RecordIDGenerator func() RecordID
+
+func DefaultIDGenerator() RecordID {
+	return RecordID(ulid.Make().String())
+}
+
+// Input describes a notification to a user. SourceId identifies what caused
+// it, usually a domain event, so the same cause never notifies twice.
+type Input struct {
+	Data     any
+	UserId   string
+	Type     string
+	SourceId string
+	Title    string
+	Body     string
+	LinkUri  string
+}
+
+// Recipient is the user a notification is delivered to.
+type Recipient struct {
+	UserId              string
+	Name                string
+	Email               string
+	IndividualProfileId string
+}
+
+type Inbox struct {
+	Items       []*Notification `json:"items"`
+	UnreadCount int64           `json:"unreadCount"`
+}
+
+type Preference struct {
+	Type      string   `json:"type"`
+	Channels  []string `json:"channels"`
+	IsDefault bool     `json:"isDefault"`
+}
+
+type PreferenceInput struct {
+	Channels []string `json:"channels"`
+}
+
+// NotificationCreatedEvent is the payload of EventNotificationCreated. It is
+// only recorded for notifications with out of band channels to deliver to.
+type NotificationCreatedEvent struct {
+	CreatedAt      time.Time `json:"createdAt"`
+	NotificationId string    `json:"notificationId"`
+	UserId         string    `json:"userId"`
+	Type           string    `json:"type"`
+	Channels       []string  `json:"channels"`
+
+	after *Notification
+}
+
+// AuditStates logs the notification as created.
+func (e *NotificationCreatedEvent) AuditStates() (any, any) {
+	return nil, e.after
+}
+
+// PreferenceChangedEvent is the payload of EventPreferenceChanged.
+type PreferenceChangedEvent struct {
+	UserId   string   `json:"userId"`
+	Type     string   `json:"type"`
+	Channels []string `json:"channels"`
+
+	before *Preference
+	after  *Preference
+}
+
+// AuditStates logs the preference before and after the change.
+func (e *PreferenceChangedEvent) AuditStates() (any, any) {
+	return e.before, e.after
+}
+
+// AbsoluteLink returns the address the link of a notification points to,
+// resolving site relative links against the site address.
+func AbsoluteLink(siteUrl string, notification *Notification) string {
+	if !notification.LinkUri.Valid || strings.Contains(notification.LinkUri.String, "://") {
+		return notification.LinkUri.String
+	}
+
+	return strings.TrimRight(siteUrl, "/") + notification.LinkUri.String
+}
@@ -0,0 +1,9 @@
+package outbox
+
+import "time"
+
+type Config struct {
+	RelayInterval  time.Duration `conf:"RELAY_INTERVAL" default:"1s"`   // how often the relay polls for pending events
+	RetryBaseDelay time.Duration `conf:"RETRY_BASE_DELAY" default:"2s"` // delay before the first redelivery, doubled on every further attempt
+	RetryMaxDelay  time.Duration `conf:"RETRY_MAX_DELAY" default:"10m"` // upper bound for the delay between redeliveries
+}
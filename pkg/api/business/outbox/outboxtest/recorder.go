@@ -0,0 +1,97 @@
+// Package outboxtest provides in-memory stand-ins for the outbox in tests.
+package outboxtest
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/eser/acik.io/pkg/api/business/outbox"
+	"github.com/oklog/ulid/v2"
+)
+
+// Recorder keeps the domain events recorded through it as the envelopes the
+// outbox would publish, so they can be handed to subscribers.
+type Recorder struct {
+	envelopes []*outbox.Envelope
+	mu        sync.Mutex
+}
+
+func NewRecorder() *Recorder {
+	return &Recorder{} //nolint:exhaustruct
+}
+
+func (r *Recorder) Record(
+	_ context.Context,
+	aggregateType string,
+	aggregateId string,
+	eventType string,
+	payload any,
+) error {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return err //nolint:wrapcheck
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.envelopes = append(r.envelopes, &outbox.Envelope{
+		OccurredAt:    time.Now(),
+		Id:            ulid.Make().String(),
+		AggregateType: aggregateType,
+		AggregateId:   aggregateId,
+		EventType:     eventType,
+		Payload:       data,
+		Attempt:       0,
+	})
+
+	return nil
+}
+
+// Envelopes returns the recorded events of a type, in the order they were
+// recorded.
+func (r *Recorder) Envelopes(eventType string) []*outbox.Envelope {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	result := []*outbox.Envelope{}
+
+	for _, envelope := range r.envelopes {
+		if envelope.EventType == eventType {
+			result = append(result, envelope)
+		}
+	}
+
+	return result
+}
+
+// Discard is an event recorder dropping every event.
+type Discard struct{}
+
+func (Discard) Record(context.Context, string, string, string, any) error {
+	return nil
+}
+
+// Envelope wraps a payload the way the outbox publishes it, for handing to
+// subscribers directly.
+func Envelope(t *testing.T, eventType string, payload any) *outbox.Envelope {
+	t.Helper()
+
+	data, err := json.Marshal(payload)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return &outbox.Envelope{
+		OccurredAt:    time.Now(),
+		Id:            ulid.Make().String(),
+		AggregateType: "test",
+		AggregateId:   "1",
+		EventType:     eventType,
+		Payload:       data,
+		Attempt:       0,
+	}
+}
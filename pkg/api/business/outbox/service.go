@@ -0,0 +1,190 @@
+package outbox
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"time"
+)
+
+const (
+	RelayBatchSize = 100
+	MaxAttempts    = 15
+)
+
+var (
+	ErrFailedToEncodePayload = errors.New("failed to encode payload")
+	ErrFailedToRecord        = errors.New("failed to record event")
+	ErrFailedToClaim         = errors.New("failed to claim events")
+	ErrFailedToMark          = errors.New("failed to mark event")
+)
+
+type Repository interface {
+	Transact(ctx context.Context, fn func(ctx context.Context) error) error
+	InsertOutboxEvent(ctx context.Context, arg InsertOutboxEventParams) error
+	ClaimOutboxEvents(ctx context.Context, limitCount int32) ([]*Outbox, error)
+	MarkOutboxEventDelivered(ctx context.Context, id string) error
+	MarkOutboxEventFailed(ctx context.Context, arg MarkOutboxEventFailedParams) error
+}
+
+type Publisher interface {
+	Enqueue(ctx context.Context, queueName string, payload any) error
+}
+
+type Service struct {
+	config    *Config
+	repo      Repository
+	publisher Publisher
+
+	idGenerator RecordIDGenerator
+}
+
+func NewService(config *Config, repo Repository, publisher Publisher) *Service {
+	return &Service{config: config, repo: repo, publisher: publisher, idGenerator: DefaultIDGenerator}
+}
+
+// Record stores a domain event in the outbox. Called within a transaction,
+// the event is committed or discarded together with the state change that
+// caused it.
+func (s *Service) Record(
+	ctx context.Context,
+	aggregateType string,
+	aggregateId string,
+	eventType string,
+	payload any,
+) error {
+	encoded, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("%w(event: %s): %w", ErrFailedToEncodePayload, eventType, err)
+	}
+
+	err = s.repo.InsertOutboxEvent(ctx, InsertOutboxEventParams{
+		Id:            string(s.idGenerator()),
+		AggregateType: aggregateType,
+		AggregateId:   aggregateId,
+		EventType:     eventType,
+		Payload:       encoded,
+	})
+	if err != nil {
+		return fmt.Errorf("%w(event: %s): %w", ErrFailedToRecord, eventType, err)
+	}
+
+	return nil
+}
+
+// RelayBatch delivers the pending events that are due to the queue and
+// returns how many were delivered.
+//
+// Only the oldest pending event of each aggregate is claimed, so events of
+// the same aggregate are delivered in order: a failing event holds back the
+// ones after it until it is delivered or given up on. Claimed rows stay
+// locked until the batch completes, which keeps concurrent relays from
+// delivering the same event. An event may still be delivered more than once
+// if the batch fails to commit, so consumers must be idempotent.
+func (s *Service) RelayBatch(ctx context.Context) (int, error) {
+	delivered := 0
+
+	err := s.repo.Transact(ctx, func(ctx context.Context) error {
+		records, err := s.repo.ClaimOutboxEvents(ctx, RelayBatchSize)
+		if err != nil {
+			return fmt.Errorf("%w: %w", ErrFailedToClaim, err)
+		}
+
+		for _, record := range records {
+			publishErr := s.publisher.Enqueue(ctx, QueueName(record.EventType), &Envelope{
+				OccurredAt:    record.OccurredAt,
+				Id:            record.Id,
+				AggregateType: record.AggregateType,
+				AggregateId:   record.AggregateId,
+				EventType:     record.EventType,
+				Payload:       record.Payload,
+				Attempt:       record.Attempts + 1,
+			})
+			if publishErr != nil {
+				err = s.markFailed(ctx, record, publishErr)
+				if err != nil {
+					return err
+				}
+
+				continue
+			}
+
+			err = s.repo.MarkOutboxEventDelivered(ctx, record.Id)
+			if err != nil {
+				return fmt.Errorf("%w(id: %s): %w", ErrFailedToMark, record.Id, err)
+			}
+
+			delivered++
+		}
+
+		return nil
+	})
+	if err != nil {
+		return 0, err //nolint:wrapcheck
+	}
+
+	return delivered, nil
+}
+
+// Relay delivers pending events until the context is cancelled. A full batch
+// is followed by the next one right away; otherwise the relay waits for the
+// configured interval. Errors are passed to onError and do not stop it.
+func (s *Service) Relay(ctx context.Context, onError func(err error)) {
+	for {
+		delivered, err := s.RelayBatch(ctx)
+		if err != nil && ctx.Err() == nil {
+			onError(err)
+		}
+
+		if delivered == RelayBatchSize {
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(s.config.RelayInterval):
+		}
+	}
+}
+
+func (s *Service) markFailed(ctx context.Context, record *Outbox, cause error) error {
+	attempt := record.Attempts + 1
+
+	status := StatusPending
+	if attempt >= MaxAttempts {
+		status = StatusDead
+	}
+
+	err := s.repo.MarkOutboxEventFailed(ctx, MarkOutboxEventFailedParams{
+		Status:        status,
+		LastError:     sql.NullString{String: cause.Error(), Valid: true},
+		NextAttemptAt: time.Now().Add(s.Backoff(attempt)),
+		Id:            record.Id,
+	})
+	if err != nil {
+		return fmt.Errorf("%w(id: %s): %w", ErrFailedToMark, record.Id, err)
+	}
+
+	return nil
+}
+
+// Backoff returns the delay before the given redelivery attempt: the base
+// delay doubled for every previous attempt, capped, with up to 20% jitter so
+// retries of many events do not line up.
+func (s *Service) Backoff(attempt int32) time.Duration {
+	delay := s.config.RetryBaseDelay
+
+	for i := int32(1); i < attempt && delay < s.config.RetryMaxDelay; i++ {
+		delay *= 2
+	}
+
+	delay = min(delay, s.config.RetryMaxDelay)
+
+	jitter := time.Duration(rand.Int64N(int64(delay)/5 + 1)) //nolint:gosec,mnd
+
+	return delay - jitter
+}
@@ -0,0 +1,156 @@
+package outbox_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/eser/acik.io/pkg/api/business/outbox"
+)
+
+var errUnavailable = errors.New("queue unavailable")
+
+// repository keeps the outbox rows in memory, claiming all pending ones.
+type repository struct {
+	pending   []*outbox.Outbox
+	inserted  []outbox.InsertOutboxEventParams
+	delivered []string
+	failed    []outbox.MarkOutboxEventFailedParams
+}
+
+func (r *repository) Transact(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
+
+func (r *repository) InsertOutboxEvent(_ context.Context, arg outbox.InsertOutboxEventParams) error {
+	r.inserted = append(r.inserted, arg)
+
+	return nil
+}
+
+func (r *repository) ClaimOutboxEvents(context.Context, int32) ([]*outbox.Outbox, error) {
+	return r.pending, nil
+}
+
+func (r *repository) MarkOutboxEventDelivered(_ context.Context, id string) error {
+	r.delivered = append(r.delivered, id)
+
+	return nil
+}
+
+func (r *repository) MarkOutboxEventFailed(_ context.Context, arg outbox.MarkOutboxEventFailedParams) error {
+	r.failed = append(r.failed, arg)
+
+	return nil
+}
+
+// publisher fails every event of the failing type.
+type publisher struct {
+	failing   string
+	envelopes map[string]*outbox.Envelope
+}
+
+func (p *publisher) Enqueue(_ context.Context, queueName string, payload any) error {
+	envelope, _ := payload.(*outbox.Envelope)
+	if envelope.EventType == p.failing {
+		return errUnavailable
+	}
+
+	p.envelopes[queueName] = envelope
+
+	return nil
+}
+
+func newConfig() *outbox.Config {
+	return &outbox.Config{RelayInterval: time.Second, RetryBaseDelay: 2 * time.Second, RetryMaxDelay: time.Minute}
+}
+
+func TestRecord(t *testing.T) {
+	t.Parallel()
+
+	repo := &repository{} //nolint:exhaustruct
+	service := outbox.NewService(newConfig(), repo, nil)
+
+	err := service.Record(context.Background(), "story", "1", "story.published", map[string]string{"slug": "go"})
+	if err != nil {
+		t.Fatalf("recording: %v", err)
+	}
+
+	if len(repo.inserted) != 1 {
+		t.Fatalf("got %d rows, want 1", len(repo.inserted))
+	}
+
+	if row := repo.inserted[0]; row.Id == "" || row.AggregateId != "1" || string(row.Payload) != `{"slug":"go"}` {
+		t.Errorf("got row %+v, want an id and the encoded payload", row)
+	}
+
+	err = service.Record(context.Background(), "story", "1", "story.published", func() {})
+	if !errors.Is(err, outbox.ErrFailedToEncodePayload) {
+		t.Errorf("got %v for a payload that can not be encoded, want %v", err, outbox.ErrFailedToEncodePayload)
+	}
+}
+
+func TestRelayBatch(t *testing.T) {
+	t.Parallel()
+
+	repo := &repository{ //nolint:exhaustruct
+		pending: []*outbox.Outbox{
+			{Id: "1", EventType: "story.published", Payload: json.RawMessage(`{}`), Attempts: 2},                     //nolint:exhaustruct
+			{Id: "2", EventType: "user.suspended", Payload: json.RawMessage(`{}`), Attempts: 0},                      //nolint:exhaustruct
+			{Id: "3", EventType: "user.suspended", Payload: json.RawMessage(`{}`), Attempts: outbox.MaxAttempts - 1}, //nolint:exhaustruct
+		},
+	}
+	queue := &publisher{failing: "user.suspended", envelopes: map[string]*outbox.Envelope{}}
+
+	delivered, err := outbox.NewService(newConfig(), repo, queue).RelayBatch(context.Background())
+	if err != nil {
+		t.Fatalf("relaying: %v", err)
+	}
+
+	if delivered != 1 || len(repo.delivered) != 1 || repo.delivered[0] != "1" {
+		t.Errorf("got %d delivered as %v, want only the first event", delivered, repo.delivered)
+	}
+
+	if envelope := queue.envelopes[outbox.QueueName("story.published")]; envelope == nil || envelope.Attempt != 3 {
+		t.Errorf("got envelope %+v, want it published as the third attempt", envelope)
+	}
+
+	if len(repo.failed) != 2 { //nolint:mnd
+		t.Fatalf("got %d failures, want 2", len(repo.failed))
+	}
+
+	if failure := repo.failed[0]; failure.Status != outbox.StatusPending || failure.LastError.String != errUnavailable.Error() {
+		t.Errorf("got failure %+v, want the event kept pending with the error", failure)
+	}
+
+	if failure := repo.failed[1]; failure.Status != outbox.StatusDead {
+		t.Errorf("got status %q on the last attempt, want %q", failure.Status, outbox.StatusDead)
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	t.Parallel()
+
+	service := outbox.NewService(newConfig(), nil, nil)
+
+	tests := []struct {
+		attempt int32
+		want    time.Duration
+	}{
+		{attempt: 1, want: 2 * time.Second},
+		{attempt: 2, want: 4 * time.Second},
+		{attempt: 4, want: 16 * time.Second},
+		{attempt: outbox.MaxAttempts, want: time.Minute},
+	}
+
+	for _, test := range tests {
+		// The delay is shortened by up to a fifth of itself as jitter.
+		for range 20 {
+			if got := service.Backoff(test.attempt); got > test.want || got < test.want*4/5 {
+				t.Fatalf("got %v for attempt %d, want %v less up to 20%%", got, test.attempt, test.want)
+			}
+		}
+	}
+}
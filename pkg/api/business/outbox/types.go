@@ -0,0 +1,41 @@
+package outbox
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/oklog/ulid/v2"
+)
+
+const (
+	StatusPending   = "pending"
+	StatusDelivered = "delivered"
+	StatusDead      = "dead"
+
+	// QueuePrefix is prepended to the event type to name the queue an event
+	// is delivered to.
+	QueuePrefix = "domain-events."
+)
+
+type RecordID string
+
+type RecordIDGenerator func() RecordID
+
+func DefaultIDGenerator() RecordID {
+	return RecordID(ulid.Make().String())
+}
+
+// Envelope is the message delivered to the queue for each domain event.
+type Envelope struct {
+	OccurredAt    time.Time       `json:"occurredAt"`
+	Id            string          `json:"id"`
+	AggregateType string          `json:"aggregateType"`
+	AggregateId   string          `json:"aggregateId"`
+	EventType     string          `json:"eventType"`
+	Payload       json.RawMessage `json:"payload"`
+	Attempt       int32           `json:"attempt"`
+}
+
+func QueueName(eventType string) string {
+	return QueuePrefix + eventType
+}
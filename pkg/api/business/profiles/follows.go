@@ -0,0 +1,163 @@
+package profiles
+
+import (
+	"context"
+	"fmt"
+)
+
+// GetDetailBySlug returns a profile along with its follower count and, when a
+// user is given, whether that user follows it.
+func (s *Service) GetDetailBySlug(ctx context.Context, slug string, userId string) (*ProfileDetail, error) {
+	profile, err := s.getExistingBySlug(ctx, slug)
+	if err != nil {
+		return nil, err
+	}
+
+	followerCount, err := s.repo.CountProfileFollowers(ctx, profile.Id)
+	if err != nil {
+		return nil, fmt.Errorf("%w(profile: %s): %w", ErrFailedToGetRecord, profile.Id, err)
+	}
+
+	isFollowing := false
+
+	if userId != "" {
+		isFollowing, err = s.repo.IsFollowingProfile(ctx, IsFollowingProfileParams{
+			UserId:    userId,
+			ProfileId: profile.Id,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("%w(profile: %s, user: %s): %w", ErrFailedToGetRecord, profile.Id, userId, err)
+		}
+	}
+
+	return &ProfileDetail{
+		Profile:       profile,
+		FollowerCount: followerCount,
+		IsFollowing:   isFollowing,
+	}, nil
+}
+
+// Follow makes the user follow the profile. Following a profile twice is a
+// no-op.
+func (s *Service) Follow(ctx context.Context, userId string, slug string) (*ProfileDetail, error) {
+	return s.setFollowing(ctx, userId, slug, true)
+}
+
+// Unfollow makes the user stop following the profile. Unfollowing a profile
+// that isn't followed is a no-op.
+func (s *Service) Unfollow(ctx context.Context, userId string, slug string) (*ProfileDetail, error) {
+	return s.setFollowing(ctx, userId, slug, false)
+}
+
+// setFollowing follows or unfollows the profile, recording an event only
+// when it changes whether the user follows it.
+func (s *Service) setFollowing(ctx context.Context, userId string, slug string, isFollowing bool) (*ProfileDetail, error) {
+	err := s.ensureActive(ctx, userId)
+	if err != nil {
+		return nil, err
+	}
+
+	profile, err := s.getExistingBySlug(ctx, slug)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.repo.Transact(ctx, func(ctx context.Context) error {
+		var (
+			affected  int64
+			eventType string
+			err       error
+		)
+
+		if isFollowing {
+			eventType = EventProfileFollowed
+			affected, err = s.repo.FollowProfile(ctx, FollowProfileParams{
+				Id:        string(s.idGenerator()),
+				UserId:    userId,
+				ProfileId: profile.Id,
+			})
+		} else {
+			eventType = EventProfileUnfollowed
+			affected, err = s.repo.UnfollowProfile(ctx, UnfollowProfileParams{
+				UserId:    userId,
+				ProfileId: profile.Id,
+			})
+		}
+
+		if err != nil {
+			return fmt.Errorf("%w(profile: %s, user: %s): %w", ErrFailedToUpdateRecord, profile.Id, userId, err)
+		}
+
+		if affected == 0 {
+			return nil
+		}
+
+		return s.events.Record(ctx, AggregateProfile, profile.Id, eventType, &FollowEvent{ //nolint:wrapcheck
+			ProfileId:   profile.Id,
+			UserId:      userId,
+			isFollowing: isFollowing,
+		})
+	})
+	if err != nil {
+		return nil, err //nolint:wrapcheck
+	}
+
+	return s.GetDetailBySlug(ctx, slug, userId)
+}
+
+// ListFollowers lists the users following the profile, the latest first.
+func (s *Service) ListFollowers(ctx context.Context, slug string, limit int32, offset int32) (*Followers, error) {
+	profile, err := s.getExistingBySlug(ctx, slug)
+	if err != nil {
+		return nil, err
+	}
+
+	total, err := s.repo.CountProfileFollowers(ctx, profile.Id)
+	if err != nil {
+		return nil, fmt.Errorf("%w(profile: %s): %w", ErrFailedToListRecords, profile.Id, err)
+	}
+
+	items, err := s.repo.ListProfileFollowers(ctx, ListProfileFollowersParams{
+		ProfileId:   profile.Id,
+		LimitCount:  limit,
+		OffsetCount: offset,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%w(profile: %s): %w", ErrFailedToListRecords, profile.Id, err)
+	}
+
+	return &Followers{Items: items, Total: total}, nil
+}
+
+// ListFollowing lists the profiles the user follows, the latest followed
+// first.
+func (s *Service) ListFollowing(ctx context.Context, userId string, limit int32, offset int32) (*Following, error) {
+	total, err := s.repo.CountFollowedProfiles(ctx, userId)
+	if err != nil {
+		return nil, fmt.Errorf("%w(user: %s): %w", ErrFailedToListRecords, userId, err)
+	}
+
+	items, err := s.repo.ListFollowedProfiles(ctx, ListFollowedProfilesParams{
+		UserId:      userId,
+		LimitCount:  limit,
+		OffsetCount: offset,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%w(user: %s): %w", ErrFailedToListRecords, userId, err)
+	}
+
+	return &Following{Items: items, Total: total}, nil
+}
+
+func (s *Service) getExistingBySlug(ctx context.Context, slug string) (*Profile, error) {
+	profile, err := s.GetBySlug(ctx, slug)
+	if err != nil {
+		return nil, err
+	}
+
+	if profile == nil || profile.DeletedAt.Valid {
+		return nil, fmt.Errorf("%w(slug: %s)", ErrRecordNotFound, slug)
+	}
+
+	return profile, nil
+}
@@ -0,0 +1,81 @@
+package profiles
+
+import (
+	"context"
+	"fmt"
+)
+
+// InviteMember adds a user to the members of a profile, and lets them know
+// through EventMemberInvited. Authorization is left to the caller, see
+// IsAdmin.
+func (s *Service) InviteMember(
+	ctx context.Context,
+	profileId string,
+	invitedByUserId string,
+	input *InviteInput,
+) (*ProfileMembership, error) {
+	if input.Kind != MembershipKindAdmin && input.Kind != MembershipKindMember {
+		return nil, fmt.Errorf("%w: kind must be one of admin or member", ErrInvalidInput)
+	}
+
+	profile, err := s.GetById(ctx, profileId)
+	if err != nil {
+		return nil, err
+	}
+
+	if profile == nil || profile.DeletedAt.Valid {
+		return nil, fmt.Errorf("%w(id: %s)", ErrRecordNotFound, profileId)
+	}
+
+	isActive, err := s.repo.IsUserActive(ctx, input.UserId)
+	if err != nil {
+		return nil, fmt.Errorf("%w(user: %s): %w", ErrFailedToGetRecord, input.UserId, err)
+	}
+
+	if !isActive {
+		return nil, fmt.Errorf("%w(user: %s)", ErrRecordNotFound, input.UserId)
+	}
+
+	isMember, err := s.IsMember(ctx, profile.Id, input.UserId)
+	if err != nil {
+		return nil, err
+	}
+
+	if isMember {
+		return nil, fmt.Errorf("%w(profile: %s, user: %s)", ErrAlreadyMember, profile.Id, input.UserId)
+	}
+
+	var record *ProfileMembership
+
+	err = s.repo.Transact(ctx, func(ctx context.Context) error {
+		record, err = s.repo.CreateProfileMembership(ctx, CreateProfileMembershipParams{
+			Id:        string(s.idGenerator()),
+			Kind:      input.Kind,
+			ProfileId: profile.Id,
+			UserId:    input.UserId,
+		})
+		if err != nil {
+			return fmt.Errorf("%w(profile: %s, user: %s): %w", ErrFailedToCreateRecord, profile.Id, input.UserId, err)
+		}
+
+		// a concurrent invitation got there first.
+		if record == nil {
+			return fmt.Errorf("%w(profile: %s, user: %s)", ErrAlreadyMember, profile.Id, input.UserId)
+		}
+
+		return s.events.Record(ctx, AggregateProfile, profile.Id, EventMemberInvited, &MemberInvitedEvent{ //nolint:wrapcheck
+			ProfileId:       profile.Id,
+			ProfileSlug:     profile.Slug,
+			ProfileTitle:    profile.Title,
+			UserId:          input.UserId,
+			Kind:            record.Kind,
+			InvitedByUserId: invitedByUserId,
+			after:           record,
+		})
+	})
+	if err != nil {
+		return nil, err //nolint:wrapcheck
+	}
+
+	return record, nil
+}
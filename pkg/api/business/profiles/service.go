@@ -2,33 +2,69 @@ package profiles
 
 import (
 	"context"
+	"database/sql"
 	"errors"
 	"fmt"
+	"strings"
+
+	"github.com/eser/acik.io/pkg/api/business/slugs"
+	"github.com/eser/acik.io/pkg/api/business/users"
 )
 
 var (
-	ErrFailedToGetRecord   = errors.New("failed to get record")
-	ErrFailedToListRecords = errors.New("failed to list records")
-	// ErrFailedToCreateRecord = errors.New("failed to create record").
+	ErrFailedToGetRecord       = errors.New("failed to get record")
+	ErrFailedToListRecords     = errors.New("failed to list records")
+	ErrFailedToCheckMembership = errors.New("failed to check membership")
+	ErrFailedToUpdateRecord    = errors.New("failed to update record")
+	ErrFailedToCreateRecord    = errors.New("failed to create record")
+	ErrRecordNotFound          = errors.New("record not found")
+	ErrInvalidInput            = errors.New("invalid input")
+	ErrSlugTaken               = errors.New("slug is already taken")
+	ErrAlreadyMember           = errors.New("user is already a member of the profile")
 )
 
 type Repository interface {
+	Transact(ctx context.Context, fn func(ctx context.Context) error) error
 	GetProfileById(ctx context.Context, id string) (*Profile, error)
 	GetProfileBySlug(ctx context.Context, slug string) (*Profile, error)
-	ListProfiles(ctx context.Context) ([]*Profile, error)
-	// CreateProfile(ctx context.Context, arg CreateProfileParams) (*Profile, error)
-	// UpdateProfile(ctx context.Context, arg UpdateProfileParams) (int64, error)
-	// DeleteProfile(ctx context.Context, id string) (int64, error)
+	ListProfiles(ctx context.Context, tagId sql.NullString) ([]*Profile, error)
+	ListNewestProfiles(ctx context.Context, limitCount int32) ([]*Profile, error)
+	IsProfileMember(ctx context.Context, arg IsProfileMemberParams) (bool, error)
+	IsProfileAdmin(ctx context.Context, arg IsProfileAdminParams) (bool, error)
+	FollowProfile(ctx context.Context, arg FollowProfileParams) (int64, error)
+	UnfollowProfile(ctx context.Context, arg UnfollowProfileParams) (int64, error)
+	IsFollowingProfile(ctx context.Context, arg IsFollowingProfileParams) (bool, error)
+	CountProfileFollowers(ctx context.Context, profileId string) (int64, error)
+	CountFollowedProfiles(ctx context.Context, userId string) (int64, error)
+	ListProfileFollowers(ctx context.Context, arg ListProfileFollowersParams) ([]*ListProfileFollowersRow, error)
+	ListFollowedProfiles(ctx context.Context, arg ListFollowedProfilesParams) ([]*ListFollowedProfilesRow, error)
+	SetProfilePictureUri(ctx context.Context, arg SetProfilePictureUriParams) (int64, error)
+	IsUserSuspended(ctx context.Context, id string) (bool, error)
+	IsUserActive(ctx context.Context, id string) (bool, error)
+	CreateProfile(ctx context.Context, arg CreateProfileParams) (*Profile, error)
+	CreateProfileMembership(ctx context.Context, arg CreateProfileMembershipParams) (*ProfileMembership, error)
+}
+
+// EventRecorder records domain events. It is called within the transaction
+// of the state change the event describes.
+type EventRecorder interface {
+	Record(ctx context.Context, aggregateType string, aggregateId string, eventType string, payload any) error
+}
+
+type SlugRenamer interface {
+	Rename(ctx context.Context, kind string, id string, slug string) (string, error)
 }
 
 type Service struct {
-	repo Repository
+	repo   Repository
+	events EventRecorder
+	slugs  SlugRenamer
 
 	idGenerator RecordIDGenerator
 }
 
-func NewService(repo Repository) *Service {
-	return &Service{repo: repo, idGenerator: DefaultIDGenerator}
+func NewService(repo Repository, events EventRecorder, slugs SlugRenamer) *Service {
+	return &Service{repo: repo, events: events, slugs: slugs, idGenerator: DefaultIDGenerator}
 }
 
 func (s *Service) GetById(ctx context.Context, id string) (*Profile, error) {
@@ -49,8 +85,20 @@ func (s *Service) GetBySlug(ctx context.Context, slug string) (*Profile, error)
 	return record, nil
 }
 
-func (s *Service) List(ctx context.Context) ([]*Profile, error) {
-	records, err := s.repo.ListProfiles(ctx)
+// List returns the profiles. tagId narrows them down to the profiles that
+// published a story or organize a published event with the tag when it
+// isn't empty.
+func (s *Service) List(ctx context.Context, tagId string) ([]*Profile, error) {
+	records, err := s.repo.ListProfiles(ctx, sql.NullString{String: tagId, Valid: tagId != ""})
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrFailedToListRecords, err)
+	}
+
+	return records, nil
+}
+
+func (s *Service) ListNewest(ctx context.Context, limit int32) ([]*Profile, error) {
+	records, err := s.repo.ListNewestProfiles(ctx, limit)
 	if err != nil {
 		return nil, fmt.Errorf("%w: %w", ErrFailedToListRecords, err)
 	}
@@ -58,11 +106,222 @@ func (s *Service) List(ctx context.Context) ([]*Profile, error) {
 	return records, nil
 }
 
-// func (s *Service) Create(ctx context.Context, input *Profile) (*Profile, error) {
-// 	record, err := s.repo.CreateProfile(ctx, input)
-// 	if err != nil {
-// 		return nil, fmt.Errorf("%w: %w", ErrFailedToCreateRecord, err)
-// 	}
+// IsMember reports whether the user can act on behalf of the profile, either
+// as its individual owner or through a membership. Suspended users can't act
+// on behalf of any profile, which is reported as users.ErrUserSuspended.
+func (s *Service) IsMember(ctx context.Context, profileId string, userId string) (bool, error) {
+	err := s.ensureActive(ctx, userId)
+	if err != nil {
+		return false, err
+	}
+
+	isMember, err := s.repo.IsProfileMember(ctx, IsProfileMemberParams{
+		UserId:    userId,
+		ProfileId: profileId,
+	})
+	if err != nil {
+		return false, fmt.Errorf("%w(profile: %s, user: %s): %w", ErrFailedToCheckMembership, profileId, userId, err)
+	}
+
+	return isMember, nil
+}
+
+// SetPicture replaces the picture of a profile. Authorization is left to the
+// caller.
+func (s *Service) SetPicture(ctx context.Context, profileId string, uri string) (*Profile, error) {
+	return s.update(ctx, profileId, func(ctx context.Context, _ *Profile) (bool, error) {
+		affected, err := s.repo.SetProfilePictureUri(ctx, SetProfilePictureUriParams{
+			ProfilePictureUri: sql.NullString{String: uri, Valid: uri != ""},
+			Id:                profileId,
+		})
+		if err != nil {
+			return false, fmt.Errorf("%w(id: %s): %w", ErrFailedToUpdateRecord, profileId, err)
+		}
+
+		return affected > 0, nil
+	})
+}
+
+// Rename gives a profile a new slug. Links to the previous slug keep working,
+// see slugs.Service. Authorization is left to the caller.
+func (s *Service) Rename(ctx context.Context, profileId string, slug string) (*Profile, error) {
+	return s.update(ctx, profileId, func(ctx context.Context, before *Profile) (bool, error) {
+		if before.Slug == slug {
+			return false, nil
+		}
+
+		_, err := s.slugs.Rename(ctx, slugs.KindProfile, profileId, slug)
+		if err != nil {
+			return false, err //nolint:wrapcheck
+		}
+
+		return true, nil
+	})
+}
+
+// update applies a change to an existing profile and records
+// EventProfileUpdated with its states before and after, read within the
+// transaction making it. change reports whether it changed anything; when it
+// didn't, nothing is recorded.
+func (s *Service) update(
+	ctx context.Context,
+	profileId string,
+	change func(ctx context.Context, before *Profile) (bool, error),
+) (*Profile, error) {
+	var after *Profile
+
+	err := s.repo.Transact(ctx, func(ctx context.Context) error {
+		before, err := s.getExistingById(ctx, profileId)
+		if err != nil {
+			return err
+		}
+
+		changed, err := change(ctx, before)
+		if err != nil {
+			return err
+		}
+
+		after, err = s.getExistingById(ctx, profileId)
+		if err != nil || !changed {
+			return err
+		}
+
+		previousSlug := ""
+		if after.Slug != before.Slug {
+			previousSlug = before.Slug
+		}
+
+		return s.events.Record(ctx, AggregateProfile, profileId, EventProfileUpdated, &ProfileUpdatedEvent{ //nolint:wrapcheck
+			ProfileId:    profileId,
+			PreviousSlug: previousSlug,
+			before:       before,
+			after:        after,
+		})
+	})
+	if err != nil {
+		return nil, err //nolint:wrapcheck
+	}
+
+	return after, nil
+}
+
+// Create creates an organization profile owned by the user.
+func (s *Service) Create(ctx context.Context, userId string, input *CreateInput) (*Profile, error) {
+	title := strings.TrimSpace(input.Title)
+	description := strings.TrimSpace(input.Description)
+
+	switch {
+	case title == "":
+		return nil, fmt.Errorf("%w: title is required", ErrInvalidInput)
+	case len(title) > MaxTitleLength:
+		return nil, fmt.Errorf("%w: title can't be longer than %d bytes", ErrInvalidInput, MaxTitleLength)
+	case len(description) > MaxDescriptionLength:
+		return nil, fmt.Errorf("%w: description can't be longer than %d bytes", ErrInvalidInput, MaxDescriptionLength)
+	}
 
-// 	return record, nil
-// }
+	err := slugs.Validate(input.Slug)
+	if err != nil {
+		return nil, err //nolint:wrapcheck
+	}
+
+	err = s.ensureActive(ctx, userId)
+	if err != nil {
+		return nil, err
+	}
+
+	var record *Profile
+
+	err = s.repo.Transact(ctx, func(ctx context.Context) error {
+		existing, err := s.repo.GetProfileBySlug(ctx, input.Slug)
+		if err != nil {
+			return fmt.Errorf("%w(slug: %s): %w", ErrFailedToGetRecord, input.Slug, err)
+		}
+
+		if existing != nil {
+			return fmt.Errorf("%w(slug: %s)", ErrSlugTaken, input.Slug)
+		}
+
+		record, err = s.repo.CreateProfile(ctx, CreateProfileParams{
+			Id:          string(s.idGenerator()),
+			Kind:        KindOrganization,
+			Slug:        input.Slug,
+			Title:       title,
+			Description: description,
+		})
+		if err != nil {
+			return fmt.Errorf("%w(slug: %s): %w", ErrFailedToCreateRecord, input.Slug, err)
+		}
+
+		_, err = s.repo.CreateProfileMembership(ctx, CreateProfileMembershipParams{
+			Id:        string(s.idGenerator()),
+			Kind:      MembershipKindOwner,
+			ProfileId: record.Id,
+			UserId:    userId,
+		})
+		if err != nil {
+			return fmt.Errorf("%w(profile: %s, user: %s): %w", ErrFailedToCreateRecord, record.Id, userId, err)
+		}
+
+		return s.events.Record(ctx, AggregateProfile, record.Id, EventProfileCreated, &ProfileCreatedEvent{ //nolint:wrapcheck
+			ProfileId:       record.Id,
+			Kind:            record.Kind,
+			Slug:            record.Slug,
+			Title:           record.Title,
+			CreatedByUserId: userId,
+			after:           record,
+		})
+	})
+	if err != nil {
+		return nil, err //nolint:wrapcheck
+	}
+
+	return record, nil
+}
+
+// IsAdmin reports whether the user can manage the settings of the profile,
+// either as its individual owner or through an owner or admin membership.
+// Suspended users are reported as users.ErrUserSuspended.
+func (s *Service) IsAdmin(ctx context.Context, profileId string, userId string) (bool, error) {
+	err := s.ensureActive(ctx, userId)
+	if err != nil {
+		return false, err
+	}
+
+	isAdmin, err := s.repo.IsProfileAdmin(ctx, IsProfileAdminParams{
+		UserId:    userId,
+		ProfileId: profileId,
+	})
+	if err != nil {
+		return false, fmt.Errorf("%w(profile: %s, user: %s): %w", ErrFailedToCheckMembership, profileId, userId, err)
+	}
+
+	return isAdmin, nil
+}
+
+func (s *Service) getExistingById(ctx context.Context, id string) (*Profile, error) {
+	profile, err := s.GetById(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if profile == nil || profile.DeletedAt.Valid {
+		return nil, fmt.Errorf("%w(id: %s)", ErrRecordNotFound, id)
+	}
+
+	return profile, nil
+}
+
+// ensureActive returns users.ErrUserSuspended for suspended users, who are
+// kept from writing.
+func (s *Service) ensureActive(ctx context.Context, userId string) error {
+	isSuspended, err := s.repo.IsUserSuspended(ctx, userId)
+	if err != nil {
+		return fmt.Errorf("%w(user: %s): %w", ErrFailedToGetRecord, userId, err)
+	}
+
+	if isSuspended {
+		return fmt.Errorf("%w(id: %s)", users.ErrUserSuspended, userId)
+	}
+
+	return nil
+}
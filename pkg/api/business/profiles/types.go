@@ -1,6 +1,11 @@
 package profiles
 
-import "github.com/oklog/ulid/v2"
+import (
+	"strconv"
+	"time"
+
+	"github.com/oklog/ulid/v2"
+)
 
 type RecordID string
 
@@ -9,3 +14,172 @@ type RecordIDGenerator func() RecordID
 func DefaultIDGenerator() RecordID {
 	return RecordID(ulid.Make().String())
 }
+
+const (
+	KindIndividual   = "individual"
+	KindOrganization = "organization"
+
+	MaxTitleLength       = 200
+	MaxDescriptionLength = 2000
+
+	MembershipKindOwner  = "owner"
+	MembershipKindAdmin  = "admin"
+	MembershipKindMember = "member"
+
+	AggregateProfile = "profile"
+
+	EventProfileCreated    = "profile.created"
+	EventProfileUpdated    = "profile.updated"
+	EventMemberInvited     = "profile.member-invited"
+	EventProfileFollowed   = "profile.followed"
+	EventProfileUnfollowed = "profile.unfollowed"
+)
+
+// CreateInput describes a new organization profile. Individual profiles come
+// with their users.
+type CreateInput struct {
+	Slug        string `json:"slug"`
+	Title       string `json:"title"`
+	Description string `json:"description"`
+}
+
+// InviteInput adds a user to the members of a profile as an admin or a
+// member. Owners can't be invited.
+type InviteInput struct {
+	UserId string `json:"userId"`
+	Kind   string `json:"kind"`
+}
+
+// ProfileCreatedEvent is the payload of EventProfileCreated.
+type ProfileCreatedEvent struct {
+	ProfileId       string `json:"profileId"`
+	Kind            string `json:"kind"`
+	Slug            string `json:"slug"`
+	Title           string `json:"title"`
+	CreatedByUserId string `json:"createdByUserId"`
+
+	after *Profile
+}
+
+// AuditProfileId attributes the creation of a profile to the profile itself.
+func (e *ProfileCreatedEvent) AuditProfileId() string {
+	return e.ProfileId
+}
+
+// AuditStates logs the profile as created.
+func (e *ProfileCreatedEvent) AuditStates() (any, any) {
+	return nil, e.after
+}
+
+// ProfileUpdatedEvent is the payload of EventProfileUpdated. PreviousSlug is
+// set when the profile was renamed.
+type ProfileUpdatedEvent struct {
+	ProfileId    string `json:"profileId"`
+	PreviousSlug string `json:"previousSlug,omitempty"`
+
+	before *Profile
+	after  *Profile
+}
+
+// AuditProfileId attributes the changes of a profile to the profile itself.
+func (e *ProfileUpdatedEvent) AuditProfileId() string {
+	return e.ProfileId
+}
+
+// AuditStates logs the full profile before and after the change.
+func (e *ProfileUpdatedEvent) AuditStates() (any, any) {
+	return e.before, e.after
+}
+
+// MemberInvitedEvent is the payload of EventMemberInvited.
+type MemberInvitedEvent struct {
+	ProfileId       string `json:"profileId"`
+	ProfileSlug     string `json:"profileSlug"`
+	ProfileTitle    string `json:"profileTitle"`
+	UserId          string `json:"userId"`
+	Kind            string `json:"kind"`
+	InvitedByUserId string `json:"invitedByUserId"`
+
+	after *ProfileMembership
+}
+
+// AuditProfileId attributes the invitation to the profile.
+func (e *MemberInvitedEvent) AuditProfileId() string {
+	return e.ProfileId
+}
+
+// AuditStates logs the membership the invitation created.
+func (e *MemberInvitedEvent) AuditStates() (any, any) {
+	return nil, e.after
+}
+
+// FollowEvent is the payload of EventProfileFollowed and
+// EventProfileUnfollowed.
+type FollowEvent struct {
+	ProfileId string `json:"profileId"`
+	UserId    string `json:"userId"`
+
+	isFollowing bool
+}
+
+// AuditProfileId attributes the follow to the profile followed.
+func (e *FollowEvent) AuditProfileId() string {
+	return e.ProfileId
+}
+
+// followState is the state of a follow in the audit log.
+type followState struct {
+	UserId      string `json:"userId"`
+	IsFollowing bool   `json:"isFollowing"`
+}
+
+// AuditStates logs whether the user followed the profile before and after
+// the change, which are only recorded when they differ.
+func (e *FollowEvent) AuditStates() (any, any) {
+	return &followState{UserId: e.UserId, IsFollowing: !e.isFollowing},
+		&followState{UserId: e.UserId, IsFollowing: e.isFollowing}
+}
+
+// Version is the time of the last change to the profile.
+func (p *Profile) Version() time.Time {
+	if p.UpdatedAt.Valid {
+		return p.UpdatedAt.Time
+	}
+
+	return p.CreatedAt
+}
+
+// ETag returns a strong entity tag of the profile, changing with every update.
+func (p *Profile) ETag() string {
+	return `"` + p.Id + "-" + strconv.FormatInt(p.Version().UnixNano(), 36) + `"`
+}
+
+// ProfileDetail is a profile as shown on its own page.
+type ProfileDetail struct {
+	*Profile
+
+	FollowerCount int64 `json:"followerCount"`
+	IsFollowing   bool  `json:"isFollowing"`
+}
+
+// ETag returns a strong entity tag of the profile detail, which also changes
+// as the profile gains or loses followers, unlike its version.
+func (d *ProfileDetail) ETag() string {
+	following := "0"
+	if d.IsFollowing {
+		following = "1"
+	}
+
+	return `"` + d.Id + "-" + strconv.FormatInt(d.Version().UnixNano(), 36) +
+		"-" + strconv.FormatInt(d.FollowerCount, 36) + "-" + following + `"`
+}
+
+type Followers struct {
+	Items []*ListProfileFollowersRow `json:"items"`
+	Total int64                      `json:"total"`
+}
+
+type Following struct {
+	Items []*ListFollowedProfilesRow `json:"items"`
+	Total int64                      `json:"total"`
+}
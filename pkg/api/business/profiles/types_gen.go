@@ -23,7 +23,6 @@ type Event struct {
 	DeletedAt       sql.NullTime   `json:"deletedAt"`
 	SeriesId        sql.NullString `json:"seriesId"`
 	Status          string         `json:"status"`
-	AttendanceUri   sql.NullString `json:"attendanceUri"`
 	PublishedAt     sql.NullTime   `json:"publishedAt"`
 }
 
@@ -38,14 +37,16 @@ type EventAttendance struct {
 }
 
 type EventSeries struct {
-	Id              string         `json:"id"`
-	Slug            string         `json:"slug"`
-	EventPictureUri sql.NullString `json:"eventPictureUri"`
-	Title           string         `json:"title"`
-	Description     string         `json:"description"`
-	CreatedAt       time.Time      `json:"createdAt"`
-	UpdatedAt       sql.NullTime   `json:"updatedAt"`
-	DeletedAt       sql.NullTime   `json:"deletedAt"`
+	Id                     string         `json:"id"`
+	Slug                   string         `json:"slug"`
+	EventPictureUri        sql.NullString `json:"eventPictureUri"`
+	Title                  string         `json:"title"`
+	Description            string         `json:"description"`
+	CreatedAt              time.Time      `json:"createdAt"`
+	UpdatedAt              sql.NullTime   `json:"updatedAt"`
+	DeletedAt              sql.NullTime   `json:"deletedAt"`
+	RecurrenceIntervalDays sql.NullInt32  `json:"recurrenceIntervalDays"`
+	RecurrenceUntil        sql.NullTime   `json:"recurrenceUntil"`
 }
 
 type Profile struct {
@@ -62,6 +63,13 @@ type Profile struct {
 	DeletedAt         sql.NullTime   `json:"deletedAt"`
 }
 
+type ProfileFollow struct {
+	Id        string    `json:"id"`
+	UserId    string    `json:"userId"`
+	ProfileId string    `json:"profileId"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
 type ProfileMembership struct {
 	Id        string       `json:"id"`
 	Kind      string       `json:"kind"`
@@ -85,6 +93,7 @@ type Question struct {
 	IsAnonymous   bool           `json:"isAnonymous"`
 	AnswerKind    sql.NullString `json:"answerKind"`
 	AnswerContent sql.NullString `json:"answerContent"`
+	VoteScore     int32          `json:"voteScore"`
 }
 
 type QuestionVote struct {
@@ -124,6 +133,7 @@ type Story struct {
 	UpdatedAt       sql.NullTime   `json:"updatedAt"`
 	DeletedAt       sql.NullTime   `json:"deletedAt"`
 	Summary         string         `json:"summary"`
+	FeaturedOrder   int32          `json:"featuredOrder"`
 }
 
 type User struct {
@@ -142,9 +152,81 @@ type User struct {
 	IndividualProfileId sql.NullString `json:"individualProfileId"`
 }
 
+type CreateProfileMembershipParams struct {
+	Id        string `json:"id"`
+	Kind      string `json:"kind"`
+	ProfileId string `json:"profileId"`
+	UserId    string `json:"userId"`
+}
+
 type CreateProfileParams struct {
-	Id   string `json:"id"`
-	Slug string `json:"slug"`
+	Id          string `json:"id"`
+	Kind        string `json:"kind"`
+	Slug        string `json:"slug"`
+	Title       string `json:"title"`
+	Description string `json:"description"`
+}
+
+type FollowProfileParams struct {
+	Id        string `json:"id"`
+	UserId    string `json:"userId"`
+	ProfileId string `json:"profileId"`
+}
+
+type IsFollowingProfileParams struct {
+	UserId    string `json:"userId"`
+	ProfileId string `json:"profileId"`
+}
+
+type IsProfileAdminParams struct {
+	UserId    string `json:"userId"`
+	ProfileId string `json:"profileId"`
+}
+
+type IsProfileMemberParams struct {
+	UserId    string `json:"userId"`
+	ProfileId string `json:"profileId"`
+}
+
+type ListFollowedProfilesParams struct {
+	UserId      string `json:"userId"`
+	LimitCount  int32  `json:"limitCount"`
+	OffsetCount int32  `json:"offsetCount"`
+}
+
+type ListFollowedProfilesRow struct {
+	Id                string         `json:"id"`
+	Kind              string         `json:"kind"`
+	Slug              string         `json:"slug"`
+	ProfilePictureUri sql.NullString `json:"profilePictureUri"`
+	Title             string         `json:"title"`
+	Description       string         `json:"description"`
+	FollowedAt        time.Time      `json:"followedAt"`
+}
+
+type ListProfileFollowersParams struct {
+	ProfileId   string `json:"profileId"`
+	LimitCount  int32  `json:"limitCount"`
+	OffsetCount int32  `json:"offsetCount"`
+}
+
+type ListProfileFollowersRow struct {
+	UserId            string         `json:"userId"`
+	Name              string         `json:"name"`
+	ProfileSlug       sql.NullString `json:"profileSlug"`
+	ProfileTitle      sql.NullString `json:"profileTitle"`
+	ProfilePictureUri sql.NullString `json:"profilePictureUri"`
+	FollowedAt        time.Time      `json:"followedAt"`
+}
+
+type SetProfilePictureUriParams struct {
+	ProfilePictureUri sql.NullString `json:"profilePictureUri"`
+	Id                string         `json:"id"`
+}
+
+type UnfollowProfileParams struct {
+	UserId    string `json:"userId"`
+	ProfileId string `json:"profileId"`
 }
 
 type UpdateProfileParams struct {
@@ -0,0 +1,10 @@
+package projects
+
+import "time"
+
+type Config struct {
+	GithubApiUrl   string        `conf:"GITHUB_API_URL" default:"https://api.github.com"` // base address of the GitHub REST API
+	GithubToken    string        `conf:"GITHUB_TOKEN"`                                    // raises the GitHub rate limit when set, optional
+	RequestTimeout time.Duration `conf:"REQUEST_TIMEOUT" default:"10s"`                   // time limit of a repository metadata request
+	MetadataTtl    time.Duration `conf:"METADATA_TTL" default:"24h"`                      // how long repository metadata is kept before refreshing it
+}
@@ -0,0 +1,611 @@
+package projects
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/url"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/eser/acik.io/pkg/api/business/profiles"
+	"github.com/eser/acik.io/pkg/api/business/tags"
+)
+
+const staleMetadataBatchSize = 100
+
+var (
+	ErrFailedToGetRecord     = errors.New("failed to get record")
+	ErrFailedToListRecords   = errors.New("failed to list records")
+	ErrFailedToCreateRecord  = errors.New("failed to create record")
+	ErrFailedToUpdateRecord  = errors.New("failed to update record")
+	ErrFailedToDeleteRecord  = errors.New("failed to delete record")
+	ErrFailedToFetchMetadata = errors.New("failed to fetch repository metadata")
+	ErrRecordNotFound        = errors.New("record not found")
+	ErrInvalidInput          = errors.New("invalid input")
+	ErrNotMember             = errors.New("user is not a member of the profile")
+	ErrVersionMismatch       = errors.New("project has changed since the given version")
+	// ErrRepositoryNotFound is returned by repository hosts for repositories
+	// that don't exist or aren't public.
+	ErrRepositoryNotFound = errors.New("repository not found")
+)
+
+var slugPattern = regexp.MustCompile(`^[a-z0-9]+(?:-[a-z0-9]+)*$`)
+
+type Repository interface {
+	Transact(ctx context.Context, fn func(ctx context.Context) error) error
+	GetProjectById(ctx context.Context, id string) (*Project, error)
+	GetProjectBySlug(ctx context.Context, arg GetProjectBySlugParams) (*Project, error)
+	ListProjectsByProfileId(ctx context.Context, arg ListProjectsByProfileIdParams) ([]*Project, error)
+	ListVisibleProjectsByTag(ctx context.Context, arg ListVisibleProjectsByTagParams) ([]*Project, error)
+	CreateProject(ctx context.Context, arg CreateProjectParams) (*Project, error)
+	UpdateProject(ctx context.Context, arg UpdateProjectParams) (int64, error)
+	ClearProjectMetadata(ctx context.Context, id string) (int64, error)
+	SetProjectMetadata(ctx context.Context, arg SetProjectMetadataParams) (int64, error)
+	ListProjectIdsWithStaleMetadata(ctx context.Context, arg ListProjectIdsWithStaleMetadataParams) ([]string, error)
+	DeleteProject(ctx context.Context, id string) (int64, error)
+}
+
+type Profiles interface {
+	GetBySlug(ctx context.Context, slug string) (*profiles.Profile, error)
+	IsMember(ctx context.Context, profileId string, userId string) (bool, error)
+}
+
+type Tagger interface {
+	SetTags(ctx context.Context, kind string, entityId string, names []string) ([]*tags.TagRef, error)
+	TagsOf(ctx context.Context, kind string, ids []string) (map[string][]*tags.TagRef, error)
+}
+
+// RepositoryHost fetches the metadata of the repositories projects link to.
+type RepositoryHost interface {
+	FetchRepository(ctx context.Context, repository *RepositoryRef) (*RepositoryMetadata, error)
+}
+
+type JobQueue interface {
+	Enqueue(ctx context.Context, queueName string, payload any) error
+}
+
+// EventRecorder records domain events. It is called within the transaction
+// of the state change the event describes.
+type EventRecorder interface {
+	Record(ctx context.Context, aggregateType string, aggregateId string, eventType string, payload any) error
+}
+
+type Service struct {
+	config   *Config
+	repo     Repository
+	profiles Profiles
+	tagger   Tagger
+	host     RepositoryHost
+	jobs     JobQueue
+	events   EventRecorder
+
+	idGenerator RecordIDGenerator
+}
+
+func NewService(
+	config *Config,
+	repo Repository,
+	profiles Profiles,
+	tagger Tagger,
+	host RepositoryHost,
+	jobs JobQueue,
+	events EventRecorder,
+) *Service {
+	return &Service{
+		config:      config,
+		repo:        repo,
+		profiles:    profiles,
+		tagger:      tagger,
+		host:        host,
+		jobs:        jobs,
+		events:      events,
+		idGenerator: DefaultIDGenerator,
+	}
+}
+
+// ListByProfile lists the projects of a profile, active ones first. Profiles
+// that don't show their projects only list them to their members. userId may
+// be empty for anonymous visitors, and tagId empty for projects with any tag.
+func (s *Service) ListByProfile(
+	ctx context.Context,
+	profileSlug string,
+	userId string,
+	tagId string,
+	limit int32,
+	offset int32,
+) ([]*ProjectView, error) {
+	profile, err := s.getVisibleProfile(ctx, profileSlug, userId)
+	if err != nil {
+		return nil, err
+	}
+
+	records, err := s.repo.ListProjectsByProfileId(ctx, ListProjectsByProfileIdParams{
+		ProfileId:   profile.Id,
+		TagId:       sql.NullString{String: tagId, Valid: tagId != ""},
+		LimitCount:  limit,
+		OffsetCount: offset,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%w(profile: %s): %w", ErrFailedToListRecords, profile.Id, err)
+	}
+
+	return s.presentAll(ctx, records)
+}
+
+// ListVisibleByTag lists the projects with a tag across the profiles that show
+// their projects, newest first.
+func (s *Service) ListVisibleByTag(ctx context.Context, tagId string, limit int32, offset int32) ([]*ProjectView, error) {
+	records, err := s.repo.ListVisibleProjectsByTag(ctx, ListVisibleProjectsByTagParams{
+		TagId:       tagId,
+		LimitCount:  limit,
+		OffsetCount: offset,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%w(tag: %s): %w", ErrFailedToListRecords, tagId, err)
+	}
+
+	return s.presentAll(ctx, records)
+}
+
+// Get returns a project of a profile, with the same visibility rules as
+// ListByProfile.
+func (s *Service) Get(ctx context.Context, profileSlug string, slug string, userId string) (*ProjectView, error) {
+	profile, err := s.getVisibleProfile(ctx, profileSlug, userId)
+	if err != nil {
+		return nil, err
+	}
+
+	record, err := s.getBySlug(ctx, profile.Id, slug)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.present(ctx, record)
+}
+
+func (s *Service) Create(
+	ctx context.Context,
+	userId string,
+	profileSlug string,
+	input *CreateProjectInput,
+) (*ProjectView, error) {
+	if !slugPattern.MatchString(input.Slug) {
+		return nil, fmt.Errorf("%w: slug must consist of lowercase letters, digits and dashes", ErrInvalidInput)
+	}
+
+	status, repositoryUri, err := validate(input.Name, input.Status, input.RepositoryUri)
+	if err != nil {
+		return nil, err
+	}
+
+	profile, err := s.getMemberProfile(ctx, profileSlug, userId)
+	if err != nil {
+		return nil, err
+	}
+
+	existing, err := s.repo.GetProjectBySlug(ctx, GetProjectBySlugParams{ProfileId: profile.Id, Slug: input.Slug})
+	if err != nil {
+		return nil, fmt.Errorf("%w(slug: %s): %w", ErrFailedToGetRecord, input.Slug, err)
+	}
+
+	if existing != nil {
+		return nil, fmt.Errorf("%w: slug %q is already taken", ErrInvalidInput, input.Slug)
+	}
+
+	var record *Project
+
+	err = s.repo.Transact(ctx, func(ctx context.Context) error {
+		var err error
+
+		record, err = s.repo.CreateProject(ctx, CreateProjectParams{
+			Id:            string(s.idGenerator()),
+			ProfileId:     profile.Id,
+			Slug:          input.Slug,
+			Name:          strings.TrimSpace(input.Name),
+			Description:   input.Description,
+			RepositoryUri: repositoryUri,
+			Status:        status,
+		})
+		if err != nil {
+			return fmt.Errorf("%w: %w", ErrFailedToCreateRecord, err)
+		}
+
+		err = s.setTags(ctx, record.Id, input.Tags)
+		if err != nil {
+			return err
+		}
+
+		return s.recordChange(ctx, EventProjectCreated, nil, record.Id)
+	})
+	if err != nil {
+		return nil, err //nolint:wrapcheck
+	}
+
+	if repositoryUri.Valid {
+		s.enqueueRefresh(ctx, record.Id)
+	}
+
+	return s.present(ctx, record)
+}
+
+func (s *Service) Update(
+	ctx context.Context,
+	userId string,
+	profileSlug string,
+	slug string,
+	input *UpdateProjectInput,
+) (*ProjectView, error) {
+	status, repositoryUri, err := validate(input.Name, input.Status, input.RepositoryUri)
+	if err != nil {
+		return nil, err
+	}
+
+	profile, err := s.getMemberProfile(ctx, profileSlug, userId)
+	if err != nil {
+		return nil, err
+	}
+
+	record, err := s.getBySlug(ctx, profile.Id, slug)
+	if err != nil {
+		return nil, err
+	}
+
+	expectedVersion, err := expectedVersionOf(record, input.IfMatch)
+	if err != nil {
+		return nil, err
+	}
+
+	repositoryChanged := repositoryUri != record.RepositoryUri
+
+	err = s.repo.Transact(ctx, func(ctx context.Context) error {
+		before, err := s.getById(ctx, record.Id)
+		if err != nil {
+			return err
+		}
+
+		affected, err := s.repo.UpdateProject(ctx, UpdateProjectParams{
+			Name:            strings.TrimSpace(input.Name),
+			Description:     input.Description,
+			RepositoryUri:   repositoryUri,
+			Status:          status,
+			Id:              record.Id,
+			ExpectedVersion: expectedVersion,
+		})
+		if err != nil {
+			return fmt.Errorf("%w(id: %s): %w", ErrFailedToUpdateRecord, record.Id, err)
+		}
+
+		// changed between reading the project and updating it.
+		if affected == 0 && expectedVersion.Valid {
+			return fmt.Errorf("%w(id: %s)", ErrVersionMismatch, record.Id)
+		}
+
+		if repositoryChanged {
+			// metadata of the previous repository must not linger on.
+			_, err = s.repo.ClearProjectMetadata(ctx, record.Id)
+			if err != nil {
+				return fmt.Errorf("%w(id: %s): %w", ErrFailedToUpdateRecord, record.Id, err)
+			}
+		}
+
+		if input.Tags != nil {
+			err = s.setTags(ctx, record.Id, input.Tags)
+			if err != nil {
+				return err
+			}
+		}
+
+		return s.recordChange(ctx, EventProjectUpdated, before, record.Id)
+	})
+	if err != nil {
+		return nil, err //nolint:wrapcheck
+	}
+
+	if repositoryChanged && repositoryUri.Valid {
+		s.enqueueRefresh(ctx, record.Id)
+	}
+
+	return s.getById(ctx, record.Id)
+}
+
+// expectedVersionOf checks an If-Match precondition against the project, and
+// returns the version the update must still find to go through. "*" only
+// asks for the project to exist.
+func expectedVersionOf(record *Project, ifMatch string) (sql.NullTime, error) {
+	if ifMatch == "" || strings.TrimSpace(ifMatch) == "*" {
+		return sql.NullTime{}, nil //nolint:exhaustruct
+	}
+
+	etag := record.ETag()
+
+	for candidate := range strings.SplitSeq(ifMatch, ",") {
+		// If-Match uses the strong comparison, weak tags never match.
+		if strings.TrimSpace(candidate) == etag {
+			return sql.NullTime{Time: record.Version(), Valid: true}, nil
+		}
+	}
+
+	return sql.NullTime{}, fmt.Errorf("%w(id: %s)", ErrVersionMismatch, record.Id) //nolint:exhaustruct
+}
+
+func (s *Service) Delete(ctx context.Context, userId string, profileSlug string, slug string) error {
+	profile, err := s.getMemberProfile(ctx, profileSlug, userId)
+	if err != nil {
+		return err
+	}
+
+	record, err := s.getBySlug(ctx, profile.Id, slug)
+	if err != nil {
+		return err
+	}
+
+	return s.repo.Transact(ctx, func(ctx context.Context) error { //nolint:wrapcheck
+		before, err := s.present(ctx, record)
+		if err != nil {
+			return err
+		}
+
+		_, err = s.repo.DeleteProject(ctx, record.Id)
+		if err != nil {
+			return fmt.Errorf("%w(id: %s): %w", ErrFailedToDeleteRecord, record.Id, err)
+		}
+
+		return s.events.Record(ctx, AggregateProject, record.Id, EventProjectDeleted, &ProjectEvent{ //nolint:wrapcheck
+			ProjectId: record.Id,
+			ProfileId: record.ProfileId,
+			Slug:      record.Slug,
+			before:    before,
+			after:     nil,
+		})
+	})
+}
+
+// RefreshMetadata fetches the stars, the language and the last commit of the
+// repository of a project. Metadata of repositories that are gone or not on a
+// supported host is cleared.
+func (s *Service) RefreshMetadata(ctx context.Context, projectId string) error {
+	record, err := s.repo.GetProjectById(ctx, projectId)
+	if err != nil {
+		return fmt.Errorf("%w(id: %s): %w", ErrFailedToGetRecord, projectId, err)
+	}
+
+	if record == nil {
+		return nil
+	}
+
+	repository, isGithub := ParseGithubRepository(record.RepositoryUri.String)
+	if !isGithub {
+		return s.clearMetadata(ctx, record.Id)
+	}
+
+	metadata, err := s.host.FetchRepository(ctx, repository)
+	if errors.Is(err, ErrRepositoryNotFound) {
+		return s.clearMetadata(ctx, record.Id)
+	}
+
+	if err != nil {
+		return fmt.Errorf("%w(id: %s): %w", ErrFailedToFetchMetadata, record.Id, err)
+	}
+
+	_, err = s.repo.SetProjectMetadata(ctx, SetProjectMetadataParams{
+		Stars:        sql.NullInt32{Int32: metadata.Stars, Valid: true},
+		Language:     sql.NullString{String: metadata.Language, Valid: metadata.Language != ""},
+		LastCommitAt: sql.NullTime{Time: metadata.LastCommitAt, Valid: !metadata.LastCommitAt.IsZero()},
+		Id:           record.Id,
+	})
+	if err != nil {
+		return fmt.Errorf("%w(id: %s): %w", ErrFailedToUpdateRecord, record.Id, err)
+	}
+
+	return nil
+}
+
+// RefreshStaleMetadata refreshes the metadata of a batch of projects whose
+// metadata is older than the configured TTL, and returns how many it did.
+func (s *Service) RefreshStaleMetadata(ctx context.Context) (int, error) {
+	ids, err := s.repo.ListProjectIdsWithStaleMetadata(ctx, ListProjectIdsWithStaleMetadataParams{
+		FetchedBefore: time.Now().Add(-s.config.MetadataTtl),
+		LimitCount:    staleMetadataBatchSize,
+	})
+	if err != nil {
+		return 0, fmt.Errorf("%w: %w", ErrFailedToListRecords, err)
+	}
+
+	refreshed := 0
+	errs := make([]error, 0)
+
+	for _, id := range ids {
+		err := s.RefreshMetadata(ctx, id)
+		if err != nil {
+			errs = append(errs, err)
+
+			continue
+		}
+
+		refreshed++
+	}
+
+	return refreshed, errors.Join(errs...)
+}
+
+func (s *Service) enqueueRefresh(ctx context.Context, projectId string) {
+	// best effort, the periodic refresh picks up projects without metadata.
+	_ = s.jobs.Enqueue(ctx, QueueRefreshMetadata, &RefreshMetadataJob{ProjectId: projectId})
+}
+
+func (s *Service) clearMetadata(ctx context.Context, projectId string) error {
+	_, err := s.repo.ClearProjectMetadata(ctx, projectId)
+	if err != nil {
+		return fmt.Errorf("%w(id: %s): %w", ErrFailedToUpdateRecord, projectId, err)
+	}
+
+	return nil
+}
+
+func (s *Service) getVisibleProfile(ctx context.Context, slug string, userId string) (*profiles.Profile, error) {
+	profile, err := s.getProfile(ctx, slug)
+	if err != nil {
+		return nil, err
+	}
+
+	if profile.ShowProjects {
+		return profile, nil
+	}
+
+	if userId != "" {
+		isMember, err := s.profiles.IsMember(ctx, profile.Id, userId)
+		if err != nil {
+			return nil, err //nolint:wrapcheck
+		}
+
+		if isMember {
+			return profile, nil
+		}
+	}
+
+	return nil, fmt.Errorf("%w(profile: %s)", ErrRecordNotFound, slug)
+}
+
+func (s *Service) getMemberProfile(ctx context.Context, slug string, userId string) (*profiles.Profile, error) {
+	profile, err := s.getProfile(ctx, slug)
+	if err != nil {
+		return nil, err
+	}
+
+	isMember, err := s.profiles.IsMember(ctx, profile.Id, userId)
+	if err != nil {
+		return nil, err //nolint:wrapcheck
+	}
+
+	if !isMember {
+		return nil, fmt.Errorf("%w(profile: %s, user: %s)", ErrNotMember, profile.Id, userId)
+	}
+
+	return profile, nil
+}
+
+func (s *Service) getProfile(ctx context.Context, slug string) (*profiles.Profile, error) {
+	profile, err := s.profiles.GetBySlug(ctx, slug)
+	if err != nil {
+		return nil, err //nolint:wrapcheck
+	}
+
+	if profile == nil || profile.DeletedAt.Valid {
+		return nil, fmt.Errorf("%w(profile: %s)", ErrRecordNotFound, slug)
+	}
+
+	return profile, nil
+}
+
+func (s *Service) getBySlug(ctx context.Context, profileId string, slug string) (*Project, error) {
+	record, err := s.repo.GetProjectBySlug(ctx, GetProjectBySlugParams{ProfileId: profileId, Slug: slug})
+	if err != nil {
+		return nil, fmt.Errorf("%w(slug: %s): %w", ErrFailedToGetRecord, slug, err)
+	}
+
+	if record == nil {
+		return nil, fmt.Errorf("%w(slug: %s)", ErrRecordNotFound, slug)
+	}
+
+	return record, nil
+}
+
+func (s *Service) getById(ctx context.Context, id string) (*ProjectView, error) {
+	record, err := s.repo.GetProjectById(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("%w(id: %s): %w", ErrFailedToGetRecord, id, err)
+	}
+
+	if record == nil {
+		return nil, fmt.Errorf("%w(id: %s)", ErrRecordNotFound, id)
+	}
+
+	return s.present(ctx, record)
+}
+
+// recordChange records a change of the project, reading the state it left
+// within the transaction making it. before is nil for new projects.
+func (s *Service) recordChange(ctx context.Context, eventType string, before *ProjectView, projectId string) error {
+	after, err := s.getById(ctx, projectId)
+	if err != nil {
+		return err
+	}
+
+	return s.events.Record(ctx, AggregateProject, projectId, eventType, &ProjectEvent{ //nolint:wrapcheck
+		ProjectId: projectId,
+		ProfileId: after.ProfileId,
+		Slug:      after.Slug,
+		before:    before,
+		after:     after,
+	})
+}
+
+func (s *Service) setTags(ctx context.Context, projectId string, names []string) error {
+	_, err := s.tagger.SetTags(ctx, tags.KindProject, projectId, names)
+	if errors.Is(err, tags.ErrInvalidInput) {
+		return fmt.Errorf("%w: %w", ErrInvalidInput, err)
+	}
+
+	return err //nolint:wrapcheck
+}
+
+func (s *Service) present(ctx context.Context, record *Project) (*ProjectView, error) {
+	views, err := s.presentAll(ctx, []*Project{record})
+	if err != nil {
+		return nil, err
+	}
+
+	return views[0], nil
+}
+
+func (s *Service) presentAll(ctx context.Context, records []*Project) ([]*ProjectView, error) {
+	ids := make([]string, len(records))
+	for i, record := range records {
+		ids[i] = record.Id
+	}
+
+	tagsByProject, err := s.tagger.TagsOf(ctx, tags.KindProject, ids)
+	if err != nil {
+		return nil, err //nolint:wrapcheck
+	}
+
+	views := make([]*ProjectView, len(records))
+	for i, record := range records {
+		views[i] = &ProjectView{Project: record, Tags: tagsByProject[record.Id]}
+	}
+
+	return views, nil
+}
+
+// validate checks the fields shared by creation and updates, and returns them
+// normalized.
+func validate(name string, status string, repositoryUri string) (string, sql.NullString, error) {
+	noRepository := sql.NullString{} //nolint:exhaustruct
+
+	if strings.TrimSpace(name) == "" {
+		return "", noRepository, fmt.Errorf("%w: name is required", ErrInvalidInput)
+	}
+
+	if status == "" {
+		status = StatusActive
+	}
+
+	if !slices.Contains(Statuses, status) {
+		return "", noRepository, fmt.Errorf("%w: unknown status %q", ErrInvalidInput, status)
+	}
+
+	if repositoryUri == "" {
+		return status, noRepository, nil
+	}
+
+	parsed, err := url.Parse(repositoryUri)
+	if err != nil || (parsed.Scheme != "https" && parsed.Scheme != "http") || parsed.Host == "" {
+		return "", noRepository, fmt.Errorf("%w: repositoryUri must be an http(s) address", ErrInvalidInput)
+	}
+
+	return status, sql.NullString{String: repositoryUri, Valid: true}, nil
+}
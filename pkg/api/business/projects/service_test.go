@@ -0,0 +1,481 @@
+package projects_test
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/eser/acik.io/pkg/api/adapters/github"
+	"github.com/eser/acik.io/pkg/api/business/outbox/outboxtest"
+	"github.com/eser/acik.io/pkg/api/business/profiles"
+	"github.com/eser/acik.io/pkg/api/business/projects"
+	"github.com/eser/acik.io/pkg/api/business/tags"
+)
+
+const (
+	profileId = "01HPROFILE0000000000000000"
+	memberId  = "01HMEMBER00000000000000000"
+)
+
+// repository keeps projects in memory, following projects.sql.
+type repository struct {
+	projects.Repository
+
+	byId map[string]*projects.Project
+	mu   sync.Mutex
+}
+
+func newRepository() *repository {
+	return &repository{byId: map[string]*projects.Project{}} //nolint:exhaustruct
+}
+
+func (r *repository) Transact(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
+
+func (r *repository) GetProjectById(_ context.Context, id string) (*projects.Project, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	record, ok := r.byId[id]
+	if !ok || record.DeletedAt.Valid {
+		return nil, nil //nolint:nilnil
+	}
+
+	clone := *record
+
+	return &clone, nil
+}
+
+func (r *repository) GetProjectBySlug(
+	_ context.Context,
+	arg projects.GetProjectBySlugParams,
+) (*projects.Project, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, record := range r.byId {
+		if record.ProfileId == arg.ProfileId && record.Slug == arg.Slug && !record.DeletedAt.Valid {
+			clone := *record
+
+			return &clone, nil
+		}
+	}
+
+	return nil, nil //nolint:nilnil
+}
+
+func (r *repository) CreateProject(_ context.Context, arg projects.CreateProjectParams) (*projects.Project, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	record := &projects.Project{ //nolint:exhaustruct
+		Id:            arg.Id,
+		ProfileId:     arg.ProfileId,
+		Slug:          arg.Slug,
+		Name:          arg.Name,
+		Description:   arg.Description,
+		RepositoryUri: arg.RepositoryUri,
+		Status:        arg.Status,
+		CreatedAt:     time.Now(),
+	}
+	r.byId[record.Id] = record
+
+	clone := *record
+
+	return &clone, nil
+}
+
+func (r *repository) UpdateProject(_ context.Context, arg projects.UpdateProjectParams) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	record, ok := r.byId[arg.Id]
+	if !ok || record.DeletedAt.Valid {
+		return 0, nil
+	}
+
+	if arg.ExpectedVersion.Valid && !record.Version().Equal(arg.ExpectedVersion.Time) {
+		return 0, nil
+	}
+
+	record.Name = arg.Name
+	record.Description = arg.Description
+	record.RepositoryUri = arg.RepositoryUri
+	record.Status = arg.Status
+	record.UpdatedAt = sql.NullTime{Time: time.Now(), Valid: true}
+
+	return 1, nil
+}
+
+func (r *repository) ClearProjectMetadata(_ context.Context, id string) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	record, ok := r.byId[id]
+	if !ok {
+		return 0, nil
+	}
+
+	record.Stars = sql.NullInt32{}            //nolint:exhaustruct
+	record.Language = sql.NullString{}        //nolint:exhaustruct
+	record.LastCommitAt = sql.NullTime{}      //nolint:exhaustruct
+	record.MetadataFetchedAt = sql.NullTime{} //nolint:exhaustruct
+
+	return 1, nil
+}
+
+func (r *repository) SetProjectMetadata(_ context.Context, arg projects.SetProjectMetadataParams) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	record, ok := r.byId[arg.Id]
+	if !ok || record.DeletedAt.Valid {
+		return 0, nil
+	}
+
+	record.Stars = arg.Stars
+	record.Language = arg.Language
+	record.LastCommitAt = arg.LastCommitAt
+	record.MetadataFetchedAt = sql.NullTime{Time: time.Now(), Valid: true}
+
+	return 1, nil
+}
+
+func (r *repository) ListProjectIdsWithStaleMetadata(
+	_ context.Context,
+	arg projects.ListProjectIdsWithStaleMetadataParams,
+) ([]string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	ids := make([]string, 0)
+
+	for id, record := range r.byId {
+		if !record.RepositoryUri.Valid || record.DeletedAt.Valid {
+			continue
+		}
+
+		if !record.MetadataFetchedAt.Valid || record.MetadataFetchedAt.Time.Before(arg.FetchedBefore) {
+			ids = append(ids, id)
+		}
+	}
+
+	slices.Sort(ids)
+
+	return ids[:min(len(ids), int(arg.LimitCount))], nil
+}
+
+// age makes the metadata of a project look fetched the given time ago.
+func (r *repository) age(id string, by time.Duration) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.byId[id].MetadataFetchedAt = sql.NullTime{Time: time.Now().Add(-by), Valid: true}
+}
+
+// directory holds a single profile, shown to everyone, with a single member.
+type directory struct{}
+
+func (directory) GetBySlug(_ context.Context, slug string) (*profiles.Profile, error) {
+	if slug != "acik" {
+		return nil, nil //nolint:nilnil
+	}
+
+	return &profiles.Profile{Id: profileId, Slug: slug, ShowProjects: true}, nil //nolint:exhaustruct
+}
+
+func (directory) IsMember(_ context.Context, id string, userId string) (bool, error) {
+	return id == profileId && userId == memberId, nil
+}
+
+type tagger struct{}
+
+func (tagger) SetTags(context.Context, string, string, []string) ([]*tags.TagRef, error) {
+	return nil, nil
+}
+
+func (tagger) TagsOf(context.Context, string, []string) (map[string][]*tags.TagRef, error) {
+	return map[string][]*tags.TagRef{}, nil
+}
+
+// jobQueue records the jobs enqueued.
+type jobQueue struct {
+	jobs []any
+	mu   sync.Mutex
+}
+
+func (q *jobQueue) Enqueue(_ context.Context, _ string, payload any) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	q.jobs = append(q.jobs, payload)
+
+	return nil
+}
+
+type fixture struct {
+	service *projects.Service
+	repo    *repository
+	host    *github.FakeClient
+	jobs    *jobQueue
+}
+
+func newFixture() *fixture {
+	repo := newRepository()
+	host := github.NewFakeClient()
+	jobs := &jobQueue{} //nolint:exhaustruct
+
+	service := projects.NewService(
+		&projects.Config{MetadataTtl: time.Hour}, //nolint:exhaustruct
+		repo,
+		directory{},
+		tagger{},
+		host,
+		jobs,
+		outboxtest.Discard{},
+	)
+
+	return &fixture{service: service, repo: repo, host: host, jobs: jobs}
+}
+
+func (f *fixture) create(t *testing.T, slug string, repositoryUri string) *projects.ProjectView {
+	t.Helper()
+
+	project, err := f.service.Create(context.Background(), memberId, "acik", &projects.CreateProjectInput{
+		Slug:          slug,
+		Name:          slug,
+		Description:   "",
+		RepositoryUri: repositoryUri,
+		Status:        "",
+		Tags:          nil,
+	})
+	if err != nil {
+		t.Fatalf("creating project: %v", err)
+	}
+
+	return project
+}
+
+func (f *fixture) refresh(t *testing.T, id string) *projects.Project {
+	t.Helper()
+
+	err := f.service.RefreshMetadata(context.Background(), id)
+	if err != nil {
+		t.Fatalf("refreshing metadata: %v", err)
+	}
+
+	record, _ := f.repo.GetProjectById(context.Background(), id)
+
+	return record
+}
+
+var acikMetadata = &projects.RepositoryMetadata{ //nolint:gochecknoglobals
+	LastCommitAt: time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC),
+	Language:     "Go",
+	Stars:        42,
+}
+
+func TestCreateEnqueuesMetadataRefresh(t *testing.T) {
+	t.Parallel()
+
+	f := newFixture()
+	f.host.Set("eser/acik.io", acikMetadata)
+
+	project := f.create(t, "acik", "https://github.com/eser/acik.io")
+	f.create(t, "offline", "")
+
+	if len(f.jobs.jobs) != 1 {
+		t.Fatalf("got %d jobs, want 1 for the project with a repository", len(f.jobs.jobs))
+	}
+
+	job, ok := f.jobs.jobs[0].(*projects.RefreshMetadataJob)
+	if !ok || job.ProjectId != project.Id {
+		t.Fatalf("got job %+v, want a refresh of %s", f.jobs.jobs[0], project.Id)
+	}
+
+	record := f.refresh(t, job.ProjectId)
+
+	if record.Stars.Int32 != 42 || record.Language.String != "Go" ||
+		!record.LastCommitAt.Time.Equal(acikMetadata.LastCommitAt) || !record.MetadataFetchedAt.Valid {
+		t.Errorf("got metadata %+v, want the one of the repository", record)
+	}
+
+	if calls := f.host.Calls(); !slices.Equal(calls, []string{"eser/acik.io"}) {
+		t.Errorf("got calls %v, want eser/acik.io", calls)
+	}
+}
+
+func TestRefreshMetadataClearsMissingRepositories(t *testing.T) {
+	t.Parallel()
+
+	f := newFixture()
+	f.host.Set("eser/acik.io", acikMetadata)
+
+	project := f.create(t, "acik", "https://github.com/eser/acik.io")
+	f.refresh(t, project.Id)
+
+	f.host.Fail("eser/acik.io", fmt.Errorf("%w(repository: eser/acik.io)", projects.ErrRepositoryNotFound))
+
+	record := f.refresh(t, project.Id)
+	if record.Stars.Valid || record.Language.Valid || record.MetadataFetchedAt.Valid {
+		t.Errorf("got metadata %+v of a missing repository, want none", record)
+	}
+}
+
+func TestRefreshMetadataClearsOtherHosts(t *testing.T) {
+	t.Parallel()
+
+	f := newFixture()
+	project := f.create(t, "acik", "https://gitlab.com/eser/acik.io")
+
+	record := f.refresh(t, project.Id)
+	if record.MetadataFetchedAt.Valid {
+		t.Errorf("got metadata %+v of a repository on another host, want none", record)
+	}
+
+	if calls := f.host.Calls(); len(calls) != 0 {
+		t.Errorf("got calls %v, want none", calls)
+	}
+}
+
+func TestRefreshMetadataKeepsMetadataWhenRateLimited(t *testing.T) {
+	t.Parallel()
+
+	f := newFixture()
+	f.host.Set("eser/acik.io", acikMetadata)
+
+	project := f.create(t, "acik", "https://github.com/eser/acik.io")
+	f.refresh(t, project.Id)
+
+	f.host.Fail("eser/acik.io", fmt.Errorf("%w(status: %d)", github.ErrUnexpectedStatus, http.StatusForbidden))
+
+	err := f.service.RefreshMetadata(context.Background(), project.Id)
+	if !errors.Is(err, projects.ErrFailedToFetchMetadata) {
+		t.Fatalf("got %v, want %v", err, projects.ErrFailedToFetchMetadata)
+	}
+
+	record, _ := f.repo.GetProjectById(context.Background(), project.Id)
+	if record.Stars.Int32 != 42 || !record.MetadataFetchedAt.Valid {
+		t.Errorf("got metadata %+v, want the previous one kept", record)
+	}
+}
+
+func TestRefreshStaleMetadata(t *testing.T) {
+	t.Parallel()
+
+	f := newFixture()
+	f.host.Set("eser/acik.io", acikMetadata)
+	f.host.Set("eser/ajan", acikMetadata)
+	f.host.Set("eser/laroux", acikMetadata)
+
+	fresh := f.create(t, "acik", "https://github.com/eser/acik.io")
+	stale := f.create(t, "ajan", "https://github.com/eser/ajan")
+	f.create(t, "laroux", "https://github.com/eser/laroux")
+	f.create(t, "offline", "")
+
+	f.refresh(t, fresh.Id)
+	f.refresh(t, stale.Id)
+	f.repo.age(stale.Id, 2*time.Hour)
+
+	calls := len(f.host.Calls())
+
+	refreshed, err := f.service.RefreshStaleMetadata(context.Background())
+	if err != nil {
+		t.Fatalf("refreshing stale metadata: %v", err)
+	}
+
+	if refreshed != 2 {
+		t.Errorf("got %d refreshed, want the stale and the never fetched one", refreshed)
+	}
+
+	got := f.host.Calls()[calls:]
+	slices.Sort(got)
+
+	if !slices.Equal(got, []string{"eser/ajan", "eser/laroux"}) {
+		t.Errorf("got calls %v, want eser/ajan and eser/laroux", got)
+	}
+
+	// failures don't hold back the rest of the batch.
+	f.repo.age(fresh.Id, 2*time.Hour)
+	f.repo.age(stale.Id, 2*time.Hour)
+	f.host.Fail("eser/acik.io", fmt.Errorf("%w(status: %d)", github.ErrUnexpectedStatus, http.StatusTooManyRequests))
+
+	refreshed, err = f.service.RefreshStaleMetadata(context.Background())
+	if !errors.Is(err, projects.ErrFailedToFetchMetadata) || refreshed != 1 {
+		t.Errorf("got %d refreshed (%v), want 1 along with the failure", refreshed, err)
+	}
+}
+
+func TestUpdateChangingRepositoryClearsMetadata(t *testing.T) {
+	t.Parallel()
+
+	f := newFixture()
+	f.host.Set("eser/acik.io", acikMetadata)
+
+	project := f.create(t, "acik", "https://github.com/eser/acik.io")
+	f.refresh(t, project.Id)
+
+	updated, err := f.service.Update(context.Background(), memberId, "acik", "acik", &projects.UpdateProjectInput{
+		Name:          "acik",
+		Description:   "",
+		RepositoryUri: "https://github.com/eser/ajan",
+		Status:        projects.StatusActive,
+		IfMatch:       "",
+		Tags:          nil,
+	})
+	if err != nil {
+		t.Fatalf("updating project: %v", err)
+	}
+
+	if updated.MetadataFetchedAt.Valid || updated.Stars.Valid {
+		t.Errorf("got metadata %+v, want the one of the previous repository cleared", updated.Project)
+	}
+
+	if len(f.jobs.jobs) != 2 { //nolint:mnd
+		t.Errorf("got %d jobs, want a refresh of the new repository", len(f.jobs.jobs))
+	}
+}
+
+func TestUpdateIfMatch(t *testing.T) {
+	t.Parallel()
+
+	f := newFixture()
+	project := f.create(t, "acik", "")
+
+	update := func(ifMatch string) (*projects.ProjectView, error) {
+		return f.service.Update(context.Background(), memberId, "acik", "acik", &projects.UpdateProjectInput{ //nolint:wrapcheck
+			Name:          "acik",
+			Description:   "updated",
+			RepositoryUri: "",
+			Status:        projects.StatusActive,
+			IfMatch:       ifMatch,
+			Tags:          nil,
+		})
+	}
+
+	_, err := update(`"stale"`)
+	if !errors.Is(err, projects.ErrVersionMismatch) {
+		t.Fatalf("got %v for a stale ETag, want ErrVersionMismatch", err)
+	}
+
+	updated, err := update(`"other", ` + project.ETag())
+	if err != nil {
+		t.Fatalf("updating with the current ETag: %v", err)
+	}
+
+	if updated.ETag() == project.ETag() {
+		t.Errorf("got the same ETag %s after an update", updated.ETag())
+	}
+
+	_, err = update(project.ETag())
+	if !errors.Is(err, projects.ErrVersionMismatch) {
+		t.Errorf("got %v for the ETag before the update, want ErrVersionMismatch", err)
+	}
+}
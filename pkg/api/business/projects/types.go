@@ -0,0 +1,149 @@
+package projects
+
+import (
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/eser/acik.io/pkg/api/business/tags"
+	"github.com/oklog/ulid/v2"
+)
+
+const (
+	StatusActive   = "active"
+	StatusInactive = "inactive"
+	StatusArchived = "archived"
+
+	QueueRefreshMetadata = "projects.refresh-metadata"
+
+	AggregateProject = "project"
+
+	EventProjectCreated = "project.created"
+	EventProjectUpdated = "project.updated"
+	EventProjectDeleted = "project.deleted"
+)
+
+// Statuses lists the statuses a project can be in.
+var Statuses = []string{StatusActive, StatusInactive, StatusArchived} //nolint:gochecknoglobals
+
+type RecordID string
+
+type RecordIDGenerator func() RecordID
+
+func DefaultIDGenerator() RecordID {
+	return RecordID(ulid.Make().String())
+}
+
+// ProjectEvent is the payload of every project domain event.
+type ProjectEvent struct {
+	ProjectId string `json:"projectId"`
+	ProfileId string `json:"profileId"`
+	Slug      string `json:"slug"`
+
+	before *ProjectView
+	after  *ProjectView
+}
+
+// AuditProfileId attributes the changes of a project to its profile.
+func (e *ProjectEvent) AuditProfileId() string {
+	return e.ProfileId
+}
+
+// AuditStates logs the project along with its tags before and after the
+// change. before is nil for new projects and after for deleted ones.
+func (e *ProjectEvent) AuditStates() (any, any) {
+	return e.before, e.after
+}
+
+type CreateProjectInput struct {
+	Slug          string   `json:"slug"`
+	Name          string   `json:"name"`
+	Description   string   `json:"description"`
+	RepositoryUri string   `json:"repositoryUri"`
+	Status        string   `json:"status"`
+	Tags          []string `json:"tags"`
+}
+
+// UpdateProjectInput leaves the tags of the project as they are when Tags is
+// omitted. IfMatch, taken from the If-Match header, makes the update
+// conditional on the project still having one of the entity tags it lists.
+type UpdateProjectInput struct {
+	Name          string   `json:"name"`
+	Description   string   `json:"description"`
+	RepositoryUri string   `json:"repositoryUri"`
+	Status        string   `json:"status"`
+	IfMatch       string   `json:"-"`
+	Tags          []string `json:"tags"`
+}
+
+// Version is the time of the last change to the project.
+func (p *Project) Version() time.Time {
+	if p.UpdatedAt.Valid {
+		return p.UpdatedAt.Time
+	}
+
+	return p.CreatedAt
+}
+
+// ETag returns a strong entity tag of the project, changing with every
+// update. Refreshing the repository metadata leaves updated_at alone, so the
+// time of the last refresh is part of the tag.
+func (p *Project) ETag() string {
+	tag := p.Id + "-" + strconv.FormatInt(p.Version().UnixNano(), 36)
+
+	if p.MetadataFetchedAt.Valid {
+		tag += "-m" + strconv.FormatInt(p.MetadataFetchedAt.Time.UnixNano(), 36)
+	}
+
+	return `"` + tag + `"`
+}
+
+// ProjectView is a project as returned to clients, with its tags.
+type ProjectView struct {
+	*Project
+
+	Tags []*tags.TagRef `json:"tags"`
+}
+
+// RefreshMetadataJob is enqueued when the repository of a project is set or
+// changed.
+type RefreshMetadataJob struct {
+	ProjectId string `json:"projectId"`
+}
+
+// RepositoryRef identifies a repository on its host.
+type RepositoryRef struct {
+	Owner string
+	Name  string
+}
+
+// RepositoryMetadata is what the host of a repository tells about it.
+type RepositoryMetadata struct {
+	LastCommitAt time.Time
+	Language     string
+	Stars        int32
+}
+
+// ParseGithubRepository returns the GitHub repository an address points to.
+// It reports false for addresses of other hosts.
+func ParseGithubRepository(uri string) (*RepositoryRef, bool) {
+	parsed, err := url.Parse(uri)
+	if err != nil || (parsed.Scheme != "https" && parsed.Scheme != "http") {
+		return nil, false
+	}
+
+	if host := strings.ToLower(parsed.Hostname()); host != "github.com" && host != "www.github.com" {
+		return nil, false
+	}
+
+	segments := strings.Split(strings.Trim(parsed.Path, "/"), "/")
+	if len(segments) < 2 || segments[0] == "" || segments[1] == "" { //nolint:mnd
+		return nil, false
+	}
+
+	return &RepositoryRef{
+		Owner: segments[0],
+		Name:  strings.TrimSuffix(segments[1], ".git"),
+	}, true
+}
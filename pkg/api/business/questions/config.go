@@ -0,0 +1,11 @@
+package questions
+
+import "time"
+
+type Config struct {
+	FilterEnabled   bool          `conf:"FILTER_ENABLED" default:"true"`  // runs new questions through the content filter
+	BlockedWords    string        `conf:"BLOCKED_WORDS"`                  // comma-separated words and phrases held back on top of the built-in lists
+	MaxLinks        string        `conf:"MAX_LINKS" default:"2"`          // number of links a question may carry
+	DuplicateWindow time.Duration `conf:"DUPLICATE_WINDOW" default:"24h"` // how far back a question is compared with the earlier ones of its asker
+	MinReputation   string        `conf:"MIN_REPUTATION" default:"-5"`    // reputation under which every question of a user is held back for review
+}
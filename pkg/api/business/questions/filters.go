@@ -0,0 +1,272 @@
+package questions
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/eser/acik.io/pkg/api/business/slugs"
+)
+
+const (
+	FilterWordList   = "word_list"
+	FilterLinks      = "links"
+	FilterDuplicate  = "duplicate"
+	FilterReputation = "reputation"
+
+	// RejectedPenalty is how much a question hidden or deleted by a moderator
+	// weighs against the reputation of its asker.
+	RejectedPenalty = 3
+)
+
+var ErrInvalidFilterConfig = errors.New("invalid filter config")
+
+// BlockedWordsEnglish and BlockedWordsTurkish are held back whatever the
+// configuration. Entries match whole words, after the same normalization
+// slugs go through, so "Siktir" and "sıktir" are not told apart.
+var (
+	BlockedWordsEnglish = []string{ //nolint:gochecknoglobals
+		"asshole", "bitch", "bullshit", "casino", "cunt", "escort", "fuck", "fucker", "fucking", "motherfucker",
+		"porn", "porno", "shit", "viagra", "whore", "xxx",
+	}
+	BlockedWordsTurkish = []string{ //nolint:gochecknoglobals
+		"amk", "amina", "aminakoyim", "aq", "bahis", "ibne", "kahpe", "orospu", "orospu cocugu", "pezevenk",
+		"sikerim", "sikeyim", "siktir", "yarak", "yarrak", "yavsak",
+	}
+)
+
+var linkPattern = regexp.MustCompile(`(?i)\b(?:https?://|www\.)\S+`) //nolint:gochecknoglobals
+
+// Candidate is a question about to be stored.
+type Candidate struct {
+	UserId  string
+	Content string
+}
+
+// Verdict tells why a filter held a question back.
+type Verdict struct {
+	Filter string `json:"filter"`
+	Reason string `json:"reason"`
+}
+
+// Filter inspects a question before it is stored. It returns no verdict
+// when it finds nothing wrong.
+type Filter interface {
+	Name() string
+	Check(ctx context.Context, candidate *Candidate) (*Verdict, error)
+}
+
+// History is what the filters know of the earlier questions of a user.
+type History interface {
+	ListRecentQuestionContentsOfUser(ctx context.Context, arg ListRecentQuestionContentsOfUserParams) ([]string, error)
+	GetQuestionReputationOfUser(ctx context.Context, userId string) (*GetQuestionReputationOfUserRow, error)
+}
+
+// Pipeline runs a question through a series of filters.
+type Pipeline struct {
+	filters []Filter
+}
+
+func NewPipeline(filters ...Filter) *Pipeline {
+	return &Pipeline{filters: filters}
+}
+
+// NewPipelineFromConfig puts together the filters turned on by the
+// configuration: the word lists, the link limit, duplicate detection and
+// the reputation threshold.
+func NewPipelineFromConfig(config *Config, history History) (*Pipeline, error) {
+	if !config.FilterEnabled {
+		return NewPipeline(), nil
+	}
+
+	maxLinks, err := strconv.Atoi(strings.TrimSpace(config.MaxLinks))
+	if err != nil || maxLinks < 0 {
+		return nil, fmt.Errorf("%w: max links %q must be a number of zero or more", ErrInvalidFilterConfig, config.MaxLinks)
+	}
+
+	minReputation, err := strconv.Atoi(strings.TrimSpace(config.MinReputation))
+	if err != nil {
+		return nil, fmt.Errorf("%w: min reputation %q must be a number", ErrInvalidFilterConfig, config.MinReputation)
+	}
+
+	words := append(append([]string{}, BlockedWordsEnglish...), BlockedWordsTurkish...)
+	words = append(words, strings.Split(config.BlockedWords, ",")...)
+
+	filters := []Filter{
+		NewWordListFilter(words),
+		NewLinkFilter(maxLinks),
+		NewReputationFilter(history, minReputation),
+	}
+
+	if config.DuplicateWindow > 0 {
+		filters = append(filters, NewDuplicateFilter(history, config.DuplicateWindow))
+	}
+
+	return NewPipeline(filters...), nil
+}
+
+// Check runs all the filters rather than stopping at the first verdict, so
+// reviewers see everything that is wrong with a question. A nil pipeline
+// lets everything through.
+func (p *Pipeline) Check(ctx context.Context, candidate *Candidate) ([]*Verdict, error) {
+	if p == nil {
+		return nil, nil
+	}
+
+	var verdicts []*Verdict
+
+	for _, filter := range p.filters {
+		verdict, err := filter.Check(ctx, candidate)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", filter.Name(), err)
+		}
+
+		if verdict != nil {
+			verdicts = append(verdicts, verdict)
+		}
+	}
+
+	return verdicts, nil
+}
+
+// WordListFilter holds back questions containing blocked words or phrases.
+type WordListFilter struct {
+	phrases []string
+}
+
+func NewWordListFilter(words []string) *WordListFilter {
+	phrases := make([]string, 0, len(words))
+
+	for _, word := range words {
+		if phrase := slugs.Normalize(word); phrase != "" {
+			phrases = append(phrases, phrase)
+		}
+	}
+
+	return &WordListFilter{phrases: phrases}
+}
+
+func (f *WordListFilter) Name() string {
+	return FilterWordList
+}
+
+func (f *WordListFilter) Check(_ context.Context, candidate *Candidate) (*Verdict, error) {
+	// words are separated by dashes once normalized, padding both sides
+	// matches whole words only.
+	content := "-" + slugs.Normalize(candidate.Content) + "-"
+
+	for _, phrase := range f.phrases {
+		if strings.Contains(content, "-"+phrase+"-") {
+			return &Verdict{Filter: FilterWordList, Reason: fmt.Sprintf("contains the blocked word %q", phrase)}, nil
+		}
+	}
+
+	return nil, nil //nolint:nilnil
+}
+
+// LinkFilter holds back questions carrying more links than allowed.
+type LinkFilter struct {
+	maxLinks int
+}
+
+func NewLinkFilter(maxLinks int) *LinkFilter {
+	return &LinkFilter{maxLinks: maxLinks}
+}
+
+func (f *LinkFilter) Name() string {
+	return FilterLinks
+}
+
+func (f *LinkFilter) Check(_ context.Context, candidate *Candidate) (*Verdict, error) {
+	count := len(linkPattern.FindAllStringIndex(candidate.Content, -1))
+	if count <= f.maxLinks {
+		return nil, nil //nolint:nilnil
+	}
+
+	return &Verdict{Filter: FilterLinks, Reason: fmt.Sprintf("carries %d links, %d allowed", count, f.maxLinks)}, nil
+}
+
+// DuplicateFilter holds back questions a user already asked within the
+// window. Questions are compared after normalization, so case, punctuation
+// and spacing don't tell them apart.
+type DuplicateFilter struct {
+	history History
+	window  time.Duration
+	now     func() time.Time
+}
+
+func NewDuplicateFilter(history History, window time.Duration) *DuplicateFilter {
+	return &DuplicateFilter{history: history, window: window, now: time.Now}
+}
+
+func (f *DuplicateFilter) Name() string {
+	return FilterDuplicate
+}
+
+func (f *DuplicateFilter) Check(ctx context.Context, candidate *Candidate) (*Verdict, error) {
+	content := slugs.Normalize(candidate.Content)
+	if content == "" {
+		return nil, nil //nolint:nilnil
+	}
+
+	recent, err := f.history.ListRecentQuestionContentsOfUser(ctx, ListRecentQuestionContentsOfUserParams{
+		UserId: candidate.UserId,
+		Since:  f.now().Add(-f.window),
+	})
+	if err != nil {
+		return nil, err //nolint:wrapcheck
+	}
+
+	for _, earlier := range recent {
+		if slugs.Normalize(earlier) == content {
+			return &Verdict{Filter: FilterDuplicate, Reason: "repeats a question asked within " + f.window.String()}, nil
+		}
+	}
+
+	return nil, nil //nolint:nilnil
+}
+
+// ReputationFilter holds back every question of users whose earlier
+// questions were mostly rejected by moderators, see Reputation.
+type ReputationFilter struct {
+	history       History
+	minReputation int
+}
+
+func NewReputationFilter(history History, minReputation int) *ReputationFilter {
+	return &ReputationFilter{history: history, minReputation: minReputation}
+}
+
+func (f *ReputationFilter) Name() string {
+	return FilterReputation
+}
+
+func (f *ReputationFilter) Check(ctx context.Context, candidate *Candidate) (*Verdict, error) {
+	record, err := f.history.GetQuestionReputationOfUser(ctx, candidate.UserId)
+	if err != nil {
+		return nil, err //nolint:wrapcheck
+	}
+
+	score := Reputation(record)
+	if score >= f.minReputation {
+		return nil, nil //nolint:nilnil
+	}
+
+	return &Verdict{Filter: FilterReputation, Reason: fmt.Sprintf("asker reputation %d is under %d", score, f.minReputation)}, nil
+}
+
+// Reputation scores a user by their questions: visible questions and the
+// votes they got count for them, the ones moderators hid or deleted count
+// against them. Questions waiting for review count neither way. Users
+// without questions start at zero.
+func Reputation(record *GetQuestionReputationOfUserRow) int {
+	if record == nil {
+		return 0
+	}
+
+	return int(record.AcceptedCount) + int(record.VoteTotal) - RejectedPenalty*int(record.RejectedCount)
+}
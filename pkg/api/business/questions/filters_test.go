@@ -0,0 +1,296 @@
+package questions_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/eser/acik.io/pkg/api/business/questions"
+)
+
+const userId = "01HUSER0000000000000000000"
+
+var errUnavailable = errors.New("unavailable")
+
+type asked struct {
+	at      time.Time
+	content string
+}
+
+// history serves the earlier questions and the reputation of a single user.
+type history struct {
+	reputation *questions.GetQuestionReputationOfUserRow
+	err        error
+	asked      []asked
+}
+
+func (h *history) ListRecentQuestionContentsOfUser(
+	_ context.Context,
+	arg questions.ListRecentQuestionContentsOfUserParams,
+) ([]string, error) {
+	if h.err != nil {
+		return nil, h.err
+	}
+
+	contents := make([]string, 0, len(h.asked))
+
+	for _, question := range h.asked {
+		if arg.UserId == userId && !question.at.Before(arg.Since) {
+			contents = append(contents, question.content)
+		}
+	}
+
+	return contents, nil
+}
+
+func (h *history) GetQuestionReputationOfUser(_ context.Context, _ string) (*questions.GetQuestionReputationOfUserRow, error) {
+	return h.reputation, h.err
+}
+
+func check(t *testing.T, filter questions.Filter, content string) *questions.Verdict {
+	t.Helper()
+
+	verdict, err := filter.Check(context.Background(), &questions.Candidate{UserId: userId, Content: content})
+	if err != nil {
+		t.Fatalf("checking %q: %v", content, err)
+	}
+
+	if verdict != nil && verdict.Filter != filter.Name() {
+		t.Errorf("got a verdict of %q from %q", verdict.Filter, filter.Name())
+	}
+
+	return verdict
+}
+
+func TestWordListFilter(t *testing.T) {
+	t.Parallel()
+
+	filter := questions.NewWordListFilter(append([]string{"kripto para", " "}, questions.BlockedWordsTurkish...))
+
+	tests := []struct {
+		content string
+		want    bool
+	}{
+		{content: "Siktir git", want: true},
+		{content: "SIKTIR!", want: true},
+		{content: "Kripto-para önerir misiniz?", want: true},
+		{content: "Kripto paralar hakkında ne düşünüyorsunuz?", want: false},
+		{content: "Sınav takvimi ne zaman açıklanacak?", want: false},
+		{content: "Bahisçilerin durumu", want: false},
+	}
+
+	for _, test := range tests {
+		if got := check(t, filter, test.content) != nil; got != test.want {
+			t.Errorf("got held back %t for %q, want %t", got, test.content, test.want)
+		}
+	}
+}
+
+func TestLinkFilter(t *testing.T) {
+	t.Parallel()
+
+	filter := questions.NewLinkFilter(1)
+
+	if verdict := check(t, filter, "See https://acik.io for details"); verdict != nil {
+		t.Errorf("got %+v for a single link, want none", verdict)
+	}
+
+	if verdict := check(t, filter, "See https://acik.io and www.example.com"); verdict == nil {
+		t.Errorf("got no verdict for two links, want one")
+	}
+
+	if verdict := check(t, questions.NewLinkFilter(0), "HTTP://ACIK.IO"); verdict == nil {
+		t.Errorf("got no verdict for a link when none are allowed, want one")
+	}
+}
+
+func TestDuplicateFilter(t *testing.T) {
+	t.Parallel()
+
+	now := time.Now()
+	filter := questions.NewDuplicateFilter(&history{ //nolint:exhaustruct
+		asked: []asked{
+			{at: now.Add(-time.Hour), content: "What is the roadmap?"},
+			{at: now.Add(-48 * time.Hour), content: "Will there be a meetup?"},
+		},
+	}, 24*time.Hour)
+
+	if verdict := check(t, filter, "  what is the ROADMAP  "); verdict == nil {
+		t.Errorf("got no verdict for a repeated question, want one")
+	}
+
+	if verdict := check(t, filter, "Will there be a meetup?"); verdict != nil {
+		t.Errorf("got %+v for a question asked before the window, want none", verdict)
+	}
+
+	if verdict := check(t, filter, "?!"); verdict != nil {
+		t.Errorf("got %+v for a question without words, want none", verdict)
+	}
+}
+
+func TestReputationFilter(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		reputation *questions.GetQuestionReputationOfUserRow
+		name       string
+		want       bool
+	}{
+		{name: "new user", reputation: nil, want: false},
+		{
+			name:       "votes make up for rejections",
+			reputation: &questions.GetQuestionReputationOfUserRow{AcceptedCount: 2, RejectedCount: 3, VoteTotal: 2},
+			want:       false,
+		},
+		{
+			name:       "mostly rejected",
+			reputation: &questions.GetQuestionReputationOfUserRow{AcceptedCount: 1, RejectedCount: 3, VoteTotal: 0},
+			want:       true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			filter := questions.NewReputationFilter(&history{reputation: test.reputation}, -5) //nolint:exhaustruct
+
+			if got := check(t, filter, "Hello") != nil; got != test.want {
+				t.Errorf("got held back %t with reputation %d, want %t", got, questions.Reputation(test.reputation), test.want)
+			}
+		})
+	}
+}
+
+func TestReputation(t *testing.T) {
+	t.Parallel()
+
+	if got := questions.Reputation(nil); got != 0 {
+		t.Errorf("got %d without questions, want 0", got)
+	}
+
+	got := questions.Reputation(&questions.GetQuestionReputationOfUserRow{AcceptedCount: 4, RejectedCount: 2, VoteTotal: 5})
+	if want := 4 + 5 - 2*questions.RejectedPenalty; got != want {
+		t.Errorf("got %d, want %d", got, want)
+	}
+}
+
+func TestPipelineRunsEveryFilter(t *testing.T) {
+	t.Parallel()
+
+	pipeline := questions.NewPipeline(
+		questions.NewWordListFilter([]string{"casino"}),
+		questions.NewLinkFilter(0),
+		questions.NewReputationFilter(&history{}, -5), //nolint:exhaustruct
+	)
+
+	verdicts, err := pipeline.Check(context.Background(), &questions.Candidate{
+		UserId:  userId,
+		Content: "Best casino: https://example.com",
+	})
+	if err != nil {
+		t.Fatalf("checking: %v", err)
+	}
+
+	if len(verdicts) != 2 || verdicts[0].Filter != questions.FilterWordList || verdicts[1].Filter != questions.FilterLinks {
+		t.Errorf("got verdicts %+v, want the word list and the links ones", verdicts)
+	}
+
+	var nilPipeline *questions.Pipeline
+
+	verdicts, err = nilPipeline.Check(context.Background(), &questions.Candidate{UserId: userId, Content: "casino"})
+	if err != nil || verdicts != nil {
+		t.Errorf("got %+v (%v) from a nil pipeline, want nothing", verdicts, err)
+	}
+}
+
+func TestPipelineStopsOnErrors(t *testing.T) {
+	t.Parallel()
+
+	pipeline := questions.NewPipeline(questions.NewReputationFilter(&history{err: errUnavailable}, -5)) //nolint:exhaustruct
+
+	_, err := pipeline.Check(context.Background(), &questions.Candidate{UserId: userId, Content: "Hello"})
+	if !errors.Is(err, errUnavailable) {
+		t.Errorf("got %v, want %v", err, errUnavailable)
+	}
+}
+
+func TestNewPipelineFromConfig(t *testing.T) {
+	t.Parallel()
+
+	config := func() *questions.Config {
+		return &questions.Config{
+			FilterEnabled:   true,
+			BlockedWords:    "kripto para,",
+			MaxLinks:        "1",
+			DuplicateWindow: 24 * time.Hour,
+			MinReputation:   "-5",
+		}
+	}
+
+	source := &history{asked: []asked{{at: time.Now(), content: "Asked before"}}} //nolint:exhaustruct
+	candidate := &questions.Candidate{
+		UserId:  userId,
+		Content: "Asked before",
+	}
+
+	pipeline, err := questions.NewPipelineFromConfig(config(), source)
+	if err != nil {
+		t.Fatalf("creating pipeline: %v", err)
+	}
+
+	for content, want := range map[string]string{
+		"Kripto para?":              questions.FilterWordList,
+		"Orospu":                    questions.FilterWordList,
+		"https://a.io https://b.io": questions.FilterLinks,
+		"Asked before":              questions.FilterDuplicate,
+	} {
+		candidate.Content = content
+
+		verdicts, err := pipeline.Check(context.Background(), candidate)
+		if err != nil || len(verdicts) != 1 || verdicts[0].Filter != want {
+			t.Errorf("got verdicts %+v (%v) for %q, want one of %s", verdicts, err, content, want)
+		}
+	}
+
+	disabled := config()
+	disabled.FilterEnabled = false
+
+	pipeline, err = questions.NewPipelineFromConfig(disabled, source)
+	if err != nil {
+		t.Fatalf("creating disabled pipeline: %v", err)
+	}
+
+	if verdicts, _ := pipeline.Check(context.Background(), candidate); len(verdicts) != 0 {
+		t.Errorf("got verdicts %+v from a disabled pipeline, want none", verdicts)
+	}
+
+	withoutWindow := config()
+	withoutWindow.DuplicateWindow = 0
+
+	pipeline, err = questions.NewPipelineFromConfig(withoutWindow, source)
+	if err != nil {
+		t.Fatalf("creating pipeline: %v", err)
+	}
+
+	candidate.Content = "Asked before"
+
+	if verdicts, _ := pipeline.Check(context.Background(), candidate); len(verdicts) != 0 {
+		t.Errorf("got verdicts %+v without a duplicate window, want none", verdicts)
+	}
+
+	for _, invalid := range []func(*questions.Config){
+		func(c *questions.Config) { c.MaxLinks = "-1" },
+		func(c *questions.Config) { c.MaxLinks = "many" },
+		func(c *questions.Config) { c.MinReputation = "low" },
+	} {
+		broken := config()
+		invalid(broken)
+
+		_, err := questions.NewPipelineFromConfig(broken, source)
+		if !errors.Is(err, questions.ErrInvalidFilterConfig) {
+			t.Errorf("got %v for %+v, want %v", err, broken, questions.ErrInvalidFilterConfig)
+		}
+	}
+}
@@ -0,0 +1,305 @@
+package questions
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+var (
+	ErrFailedToCreateRecord  = errors.New("failed to create record")
+	ErrFailedToGetRecord     = errors.New("failed to get record")
+	ErrFailedToListRecords   = errors.New("failed to list records")
+	ErrFailedToUpdateRecord  = errors.New("failed to update record")
+	ErrFailedToUpdateRecords = errors.New("failed to update records")
+	ErrFailedToFilter        = errors.New("failed to filter content")
+	ErrInvalidInput          = errors.New("invalid input")
+	ErrRecordNotFound        = errors.New("record not found")
+	ErrAlreadyAnswered       = errors.New("question is already answered")
+)
+
+type Repository interface {
+	History
+
+	Transact(ctx context.Context, fn func(ctx context.Context) error) error
+	CreateQuestion(ctx context.Context, arg CreateQuestionParams) (*Question, error)
+	GetQuestionById(ctx context.Context, id string) (*Question, error)
+	AnswerQuestion(ctx context.Context, arg AnswerQuestionParams) (*Question, error)
+	ListTopUnansweredQuestions(ctx context.Context, limitCount int32) ([]*Question, error)
+	RefreshQuestionVoteScores(ctx context.Context) (int64, error)
+	UpsertQuestionVote(ctx context.Context, arg UpsertQuestionVoteParams) error
+	DeleteQuestionVote(ctx context.Context, arg DeleteQuestionVoteParams) (int64, error)
+}
+
+type Users interface {
+	EnsureActive(ctx context.Context, userId string) error
+}
+
+// Flagger queues content the filters held back for review, see
+// moderation.Service.
+type Flagger interface {
+	Flag(ctx context.Context, entityType string, entityId string, note string) error
+}
+
+// EventRecorder records domain events. It is called within the transaction
+// of the state change the event describes.
+type EventRecorder interface {
+	Record(ctx context.Context, aggregateType string, aggregateId string, eventType string, payload any) error
+}
+
+type Service struct {
+	repo    Repository
+	users   Users
+	filters *Pipeline
+	flagger Flagger
+	events  EventRecorder
+
+	idGenerator RecordIDGenerator
+}
+
+func NewService(repo Repository, users Users, filters *Pipeline, flagger Flagger, events EventRecorder) *Service {
+	return &Service{
+		repo:        repo,
+		users:       users,
+		filters:     filters,
+		flagger:     flagger,
+		events:      events,
+		idGenerator: DefaultIDGenerator,
+	}
+}
+
+// Create stores a question after running it through the filters. Questions
+// the filters hold back are stored hidden and queued for review, and are
+// only announced to others once let through.
+func (s *Service) Create(ctx context.Context, userId string, input *CreateInput) (*Question, error) {
+	content := strings.TrimSpace(input.Content)
+
+	switch {
+	case content == "":
+		return nil, fmt.Errorf("%w: content is required", ErrInvalidInput)
+	case len(content) > MaxContentLength:
+		return nil, fmt.Errorf("%w: content can't be longer than %d bytes", ErrInvalidInput, MaxContentLength)
+	}
+
+	err := s.users.EnsureActive(ctx, userId)
+	if err != nil {
+		return nil, err //nolint:wrapcheck
+	}
+
+	verdicts, err := s.filters.Check(ctx, &Candidate{UserId: userId, Content: content})
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrFailedToFilter, err)
+	}
+
+	var record *Question
+
+	err = s.repo.Transact(ctx, func(ctx context.Context) error {
+		var err error
+
+		record, err = s.repo.CreateQuestion(ctx, CreateQuestionParams{
+			Id:          string(s.idGenerator()),
+			UserId:      userId,
+			Content:     content,
+			IsAnonymous: input.IsAnonymous,
+			IsHidden:    len(verdicts) > 0,
+		})
+		if err != nil {
+			return fmt.Errorf("%w: %w", ErrFailedToCreateRecord, err)
+		}
+
+		if record.IsHidden {
+			return s.flagger.Flag(ctx, AggregateQuestion, record.Id, describeVerdicts(verdicts)) //nolint:wrapcheck
+		}
+
+		return s.events.Record(ctx, AggregateQuestion, record.Id, EventQuestionCreated, NewQuestionCreatedEvent(record)) //nolint:wrapcheck,lll
+	})
+	if err != nil {
+		return nil, err //nolint:wrapcheck
+	}
+
+	return record, nil
+}
+
+// Answer answers a visible question once, and lets its asker know through
+// EventQuestionAnswered. Authorization is left to the caller.
+func (s *Service) Answer(ctx context.Context, questionId string, input *AnswerInput) (*Question, error) {
+	content := strings.TrimSpace(input.Content)
+
+	err := validateAnswer(input.Kind, input.Uri, content)
+	if err != nil {
+		return nil, err
+	}
+
+	var record *Question
+
+	err = s.repo.Transact(ctx, func(ctx context.Context) error {
+		before, err := s.repo.GetQuestionById(ctx, questionId)
+		if err != nil {
+			return fmt.Errorf("%w(id: %s): %w", ErrFailedToGetRecord, questionId, err)
+		}
+
+		record, err = s.repo.AnswerQuestion(ctx, AnswerQuestionParams{
+			AnswerKind:    sql.NullString{String: input.Kind, Valid: true},
+			AnswerUri:     sql.NullString{String: input.Uri, Valid: input.Uri != ""},
+			AnswerContent: sql.NullString{String: content, Valid: content != ""},
+			Id:            questionId,
+		})
+		if err != nil {
+			return fmt.Errorf("%w(id: %s): %w", ErrFailedToUpdateRecord, questionId, err)
+		}
+
+		if record == nil {
+			return s.explainUnanswerable(ctx, questionId)
+		}
+
+		return s.events.Record(ctx, AggregateQuestion, record.Id, EventQuestionAnswered, &QuestionAnsweredEvent{ //nolint:wrapcheck
+			QuestionId: record.Id,
+			UserId:     record.UserId,
+			Content:    record.Content,
+			AnswerUri:  record.AnswerUri.String,
+			before:     before,
+			after:      record,
+		})
+	})
+	if err != nil {
+		return nil, err //nolint:wrapcheck
+	}
+
+	if record.IsAnonymous {
+		record.UserId = ""
+	}
+
+	return record, nil
+}
+
+// Vote casts the vote of the user on a visible question, replacing the one
+// they cast before. Scores count towards the ranking of the question as of
+// the next vote score refresh.
+func (s *Service) Vote(ctx context.Context, userId string, questionId string, input *VoteInput) error {
+	if input.Score != 1 && input.Score != -1 {
+		return fmt.Errorf("%w: score must be 1 or -1", ErrInvalidInput)
+	}
+
+	err := s.users.EnsureActive(ctx, userId)
+	if err != nil {
+		return err //nolint:wrapcheck
+	}
+
+	record, err := s.repo.GetQuestionById(ctx, questionId)
+	if err != nil {
+		return fmt.Errorf("%w(id: %s): %w", ErrFailedToGetRecord, questionId, err)
+	}
+
+	if record == nil || record.IsHidden {
+		return fmt.Errorf("%w(id: %s)", ErrRecordNotFound, questionId)
+	}
+
+	err = s.repo.UpsertQuestionVote(ctx, UpsertQuestionVoteParams{
+		Id:         string(s.idGenerator()),
+		QuestionId: questionId,
+		UserId:     userId,
+		Score:      input.Score,
+	})
+	if err != nil {
+		return fmt.Errorf("%w(id: %s): %w", ErrFailedToUpdateRecord, questionId, err)
+	}
+
+	return nil
+}
+
+// Unvote withdraws the vote of the user on a question, if they cast one.
+func (s *Service) Unvote(ctx context.Context, userId string, questionId string) error {
+	_, err := s.repo.DeleteQuestionVote(ctx, DeleteQuestionVoteParams{QuestionId: questionId, UserId: userId})
+	if err != nil {
+		return fmt.Errorf("%w(id: %s): %w", ErrFailedToUpdateRecord, questionId, err)
+	}
+
+	return nil
+}
+
+// ListTopUnanswered returns the visible questions still waiting for an
+// answer, highest voted first as of the last vote score refresh. The asker
+// is not disclosed for anonymous questions.
+func (s *Service) ListTopUnanswered(ctx context.Context, limit int32) ([]*Question, error) {
+	records, err := s.repo.ListTopUnansweredQuestions(ctx, limit)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrFailedToListRecords, err)
+	}
+
+	for _, record := range records {
+		if record.IsAnonymous {
+			record.UserId = ""
+		}
+	}
+
+	return records, nil
+}
+
+// RefreshVoteScores recalculates the vote scores of the questions from their
+// votes. It returns the number of questions whose score changed.
+func (s *Service) RefreshVoteScores(ctx context.Context) (int64, error) {
+	updated, err := s.repo.RefreshQuestionVoteScores(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("%w: %w", ErrFailedToUpdateRecords, err)
+	}
+
+	return updated, nil
+}
+
+// explainUnanswerable tells why a question could not be answered: it is
+// either gone, held back by the filters or answered already.
+func (s *Service) explainUnanswerable(ctx context.Context, questionId string) error {
+	record, err := s.repo.GetQuestionById(ctx, questionId)
+	if err != nil {
+		return fmt.Errorf("%w(id: %s): %w", ErrFailedToGetRecord, questionId, err)
+	}
+
+	if record == nil || record.IsHidden {
+		return fmt.Errorf("%w(id: %s)", ErrRecordNotFound, questionId)
+	}
+
+	return fmt.Errorf("%w(id: %s)", ErrAlreadyAnswered, questionId)
+}
+
+func validateAnswer(kind string, uri string, content string) error {
+	switch kind {
+	case AnswerKindText:
+		if content == "" {
+			return fmt.Errorf("%w: content is required for text answers", ErrInvalidInput)
+		}
+	case AnswerKindVideo:
+		if uri == "" {
+			return fmt.Errorf("%w: uri is required for video answers", ErrInvalidInput)
+		}
+	default:
+		return fmt.Errorf("%w: kind must be one of text or video", ErrInvalidInput)
+	}
+
+	if len(content) > MaxAnswerContentLength {
+		return fmt.Errorf("%w: content can't be longer than %d bytes", ErrInvalidInput, MaxAnswerContentLength)
+	}
+
+	if uri != "" {
+		parsed, err := url.Parse(uri)
+		if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+			return fmt.Errorf("%w: uri must be an http or https url", ErrInvalidInput)
+		}
+	}
+
+	return nil
+}
+
+// describeVerdicts tells reviewers why a question was held back, one
+// filter per line.
+func describeVerdicts(verdicts []*Verdict) string {
+	lines := make([]string, len(verdicts))
+
+	for i, verdict := range verdicts {
+		lines[i] = verdict.Filter + ": " + verdict.Reason
+	}
+
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,137 @@
+package questions_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/eser/acik.io/pkg/api/business/outbox/outboxtest"
+	"github.com/eser/acik.io/pkg/api/business/questions"
+)
+
+// repository keeps the questions and their votes in memory.
+type repository struct {
+	history
+
+	questions map[string]*questions.Question
+	votes     map[[2]string]int32
+}
+
+func (r *repository) Transact(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
+
+func (r *repository) CreateQuestion(context.Context, questions.CreateQuestionParams) (*questions.Question, error) {
+	return nil, errors.ErrUnsupported
+}
+
+func (r *repository) GetQuestionById(_ context.Context, id string) (*questions.Question, error) {
+	return r.questions[id], nil
+}
+
+func (r *repository) AnswerQuestion(context.Context, questions.AnswerQuestionParams) (*questions.Question, error) {
+	return nil, errors.ErrUnsupported
+}
+
+func (r *repository) ListTopUnansweredQuestions(context.Context, int32) ([]*questions.Question, error) {
+	return nil, errors.ErrUnsupported
+}
+
+func (r *repository) RefreshQuestionVoteScores(context.Context) (int64, error) {
+	return 0, errors.ErrUnsupported
+}
+
+func (r *repository) UpsertQuestionVote(_ context.Context, arg questions.UpsertQuestionVoteParams) error {
+	r.votes[[2]string{arg.QuestionId, arg.UserId}] = arg.Score
+
+	return nil
+}
+
+func (r *repository) DeleteQuestionVote(_ context.Context, arg questions.DeleteQuestionVoteParams) (int64, error) {
+	key := [2]string{arg.QuestionId, arg.UserId}
+	if _, ok := r.votes[key]; !ok {
+		return 0, nil
+	}
+
+	delete(r.votes, key)
+
+	return 1, nil
+}
+
+type activeUsers struct{}
+
+func (activeUsers) EnsureActive(context.Context, string) error {
+	return nil
+}
+
+func newVoteFixture() (*questions.Service, *repository) {
+	repo := &repository{ //nolint:exhaustruct
+		questions: map[string]*questions.Question{
+			"visible": {Id: "visible"},                //nolint:exhaustruct
+			"hidden":  {Id: "hidden", IsHidden: true}, //nolint:exhaustruct
+		},
+		votes: map[[2]string]int32{},
+	}
+
+	return questions.NewService(repo, activeUsers{}, questions.NewPipeline(), nil, outboxtest.Discard{}), repo
+}
+
+func TestVoteReplacesEarlierVote(t *testing.T) {
+	t.Parallel()
+
+	service, repo := newVoteFixture()
+	ctx := context.Background()
+
+	for _, score := range []int32{1, -1} {
+		err := service.Vote(ctx, "user", "visible", &questions.VoteInput{Score: score})
+		if err != nil {
+			t.Fatalf("voting %d: %v", score, err)
+		}
+	}
+
+	if got := repo.votes[[2]string{"visible", "user"}]; got != -1 || len(repo.votes) != 1 {
+		t.Errorf("got votes %v, want the single latest vote of -1", repo.votes)
+	}
+
+	err := service.Unvote(ctx, "user", "visible")
+	if err != nil {
+		t.Fatalf("withdrawing vote: %v", err)
+	}
+
+	if len(repo.votes) != 0 {
+		t.Errorf("got votes %v after withdrawing, want none", repo.votes)
+	}
+}
+
+func TestVoteRejects(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		questionId string
+		score      int32
+		want       error
+	}{
+		{name: "score above one", questionId: "visible", score: 2, want: questions.ErrInvalidInput},
+		{name: "zero score", questionId: "visible", score: 0, want: questions.ErrInvalidInput},
+		{name: "hidden question", questionId: "hidden", score: 1, want: questions.ErrRecordNotFound},
+		{name: "missing question", questionId: "missing", score: 1, want: questions.ErrRecordNotFound},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			service, repo := newVoteFixture()
+
+			err := service.Vote(context.Background(), "user", test.questionId, &questions.VoteInput{Score: test.score})
+			if !errors.Is(err, test.want) {
+				t.Errorf("got %v, want %v", err, test.want)
+			}
+
+			if len(repo.votes) != 0 {
+				t.Errorf("got votes %v, want none stored", repo.votes)
+			}
+		})
+	}
+}
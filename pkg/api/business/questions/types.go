@@ -0,0 +1,84 @@
+package questions
+
+import "github.com/oklog/ulid/v2"
+
+const (
+	AggregateQuestion = "question"
+
+	MaxContentLength       = 2000
+	MaxAnswerContentLength = 20000
+
+	AnswerKindText  = "text"
+	AnswerKindVideo = "video"
+
+	EventQuestionCreated  = "question.created"
+	EventQuestionAnswered = "question.answered"
+)
+
+type RecordID string
+
+type RecordIDGenerator func() RecordID
+
+func DefaultIDGenerator() RecordID {
+	return RecordID(ulid.Make().String())
+}
+
+type CreateInput struct {
+	Content     string `json:"content"`
+	IsAnonymous bool   `json:"isAnonymous"`
+}
+
+// VoteInput votes a question up with a score of 1 or down with -1.
+type VoteInput struct {
+	Score int32 `json:"score"`
+}
+
+// AnswerInput answers a question, either in writing or with a link to the
+// recording where it was answered.
+type AnswerInput struct {
+	Kind    string `json:"kind"`
+	Uri     string `json:"uri"`
+	Content string `json:"content"`
+}
+
+// QuestionCreatedEvent is the payload of EventQuestionCreated. The asker is
+// left out for anonymous questions.
+type QuestionCreatedEvent struct {
+	QuestionId string `json:"questionId"`
+	UserId     string `json:"userId,omitempty"`
+	Content    string `json:"content"`
+
+	after *Question
+}
+
+// AuditStates logs the question as asked.
+func (e *QuestionCreatedEvent) AuditStates() (any, any) {
+	return nil, e.after
+}
+
+// NewQuestionCreatedEvent announces a question, when asked or once let
+// through by moderators.
+func NewQuestionCreatedEvent(record *Question) *QuestionCreatedEvent {
+	payload := &QuestionCreatedEvent{QuestionId: record.Id, UserId: "", Content: record.Content, after: record}
+	if !record.IsAnonymous {
+		payload.UserId = record.UserId
+	}
+
+	return payload
+}
+
+// QuestionAnsweredEvent is the payload of EventQuestionAnswered.
+type QuestionAnsweredEvent struct {
+	QuestionId string `json:"questionId"`
+	UserId     string `json:"userId"`
+	Content    string `json:"content"`
+	AnswerUri  string `json:"answerUri,omitempty"`
+
+	before *Question
+	after  *Question
+}
+
+// AuditStates logs the question before and after it was answered.
+func (e *QuestionAnsweredEvent) AuditStates() (any, any) {
+	return e.before, e.after
+}
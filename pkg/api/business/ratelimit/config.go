@@ -0,0 +1,10 @@
+package ratelimit
+
+import "time"
+
+type Config struct {
+	Enabled  bool          `conf:"ENABLED" default:"true"`
+	Backend  string        `conf:"BACKEND" default:"memory"` // where buckets are kept: memory, or postgres to share them between instances
+	Policies string        `conf:"POLICIES"`                 // per policy overrides of the limits, as "policy=limit/window,policy=limit/window"
+	IdleTtl  time.Duration `conf:"IDLE_TTL" default:"1h"`    // age after which an untouched bucket is swept, at least the longest policy window
+}
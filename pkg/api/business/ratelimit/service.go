@@ -0,0 +1,81 @@
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+var (
+	ErrInvalidPolicy     = errors.New("invalid rate limit policy")
+	ErrUnknownPolicy     = errors.New("unknown rate limit policy")
+	ErrUnknownBackend    = errors.New("unknown rate limit backend")
+	ErrFailedToTakeToken = errors.New("failed to take token")
+	ErrFailedToSweep     = errors.New("failed to sweep buckets")
+)
+
+// Store keeps the buckets. Take must refill and take from a bucket
+// atomically, as instances sharing a store race for the same buckets.
+type Store interface {
+	Take(ctx context.Context, key string, policy *Policy) (*Decision, error)
+	Sweep(ctx context.Context, idleBefore time.Time) (int64, error)
+}
+
+type Limiter struct {
+	config   *Config
+	store    Store
+	policies map[string]*Policy
+}
+
+func NewLimiter(config *Config, store Store) (*Limiter, error) {
+	policies, err := ParsePolicies(config.Policies)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Limiter{config: config, store: store, policies: policies}, nil
+}
+
+// Enabled reports whether requests are limited at all.
+func (l *Limiter) Enabled() bool {
+	return l.config.Enabled
+}
+
+// Policy returns the policy of a name.
+func (l *Limiter) Policy(name string) (*Policy, error) {
+	policy, ok := l.policies[name]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownPolicy, name)
+	}
+
+	return policy, nil
+}
+
+// Allow takes a token from the bucket of the identity under a policy.
+// Identities have separate buckets per policy.
+func (l *Limiter) Allow(ctx context.Context, policyName string, identity string) (*Decision, error) {
+	policy, err := l.Policy(policyName)
+	if err != nil {
+		return nil, err
+	}
+
+	decision, err := l.store.Take(ctx, policy.Name+":"+identity, policy)
+	if err != nil {
+		return nil, fmt.Errorf("%w(policy: %s): %w", ErrFailedToTakeToken, policy.Name, err)
+	}
+
+	return decision, nil
+}
+
+// Sweep deletes the buckets left untouched for longer than the idle ttl.
+// They would be full by then, so nothing is lost. It returns the number of
+// buckets deleted.
+func (l *Limiter) Sweep(ctx context.Context) (int64, error) {
+	deleted, err := l.store.Sweep(ctx, time.Now().Add(-l.config.IdleTtl))
+	if err != nil {
+		return 0, fmt.Errorf("%w: %w", ErrFailedToSweep, err)
+	}
+
+	return deleted, nil
+}
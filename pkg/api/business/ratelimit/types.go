@@ -0,0 +1,152 @@
+package ratelimit
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	PolicyWrite     = "write"
+	PolicyQuestions = "questions"
+	PolicyReports   = "reports"
+	PolicyFollows   = "follows"
+	PolicyUploads   = "uploads"
+	PolicyVotes     = "votes"
+)
+
+// Policy lets Limit requests through per Window. Buckets hold up to Limit
+// tokens and refill evenly over the window, so bursts up to the limit are
+// allowed after a quiet period.
+type Policy struct {
+	Name   string
+	Limit  int
+	Window time.Duration
+}
+
+// DefaultPolicies returns the policies the routes are held to unless
+// overridden by the configuration.
+func DefaultPolicies() map[string]*Policy {
+	return map[string]*Policy{
+		PolicyWrite:     {Name: PolicyWrite, Limit: 120, Window: time.Minute},  //nolint:mnd
+		PolicyQuestions: {Name: PolicyQuestions, Limit: 10, Window: time.Hour}, //nolint:mnd
+		PolicyReports:   {Name: PolicyReports, Limit: 20, Window: time.Hour},   //nolint:mnd
+		PolicyFollows:   {Name: PolicyFollows, Limit: 60, Window: time.Hour},   //nolint:mnd
+		PolicyUploads:   {Name: PolicyUploads, Limit: 30, Window: time.Hour},   //nolint:mnd
+		PolicyVotes:     {Name: PolicyVotes, Limit: 100, Window: time.Hour},    //nolint:mnd
+	}
+}
+
+// Rate returns the number of tokens the bucket regains per second.
+func (p *Policy) Rate() float64 {
+	return float64(p.Limit) / p.Window.Seconds()
+}
+
+// Decide tells the outcome of a take that left the bucket with tokens.
+func (p *Policy) Decide(tokens float64, allowed bool) *Decision {
+	decision := &Decision{
+		Allowed:    allowed,
+		Limit:      p.Limit,
+		Remaining:  int(math.Max(0, math.Floor(tokens))),
+		Reset:      p.durationFor(float64(p.Limit) - tokens),
+		RetryAfter: 0,
+	}
+
+	if !allowed {
+		decision.RetryAfter = p.durationFor(1 - tokens)
+	}
+
+	return decision
+}
+
+// durationFor returns how long the bucket takes to regain tokens.
+func (p *Policy) durationFor(tokens float64) time.Duration {
+	if tokens <= 0 {
+		return 0
+	}
+
+	return time.Duration(tokens / p.Rate() * float64(time.Second))
+}
+
+// Decision is the outcome of asking for a token.
+type Decision struct {
+	Allowed    bool
+	Limit      int
+	Remaining  int
+	Reset      time.Duration // until the bucket is full again
+	RetryAfter time.Duration // until a token is available, when not allowed
+}
+
+// Bucket is the state of a token bucket, for the backends that keep it in
+// process.
+type Bucket struct {
+	Tokens    float64
+	UpdatedAt time.Time
+}
+
+// NewBucket returns a full bucket.
+func NewBucket(policy *Policy, now time.Time) *Bucket {
+	return &Bucket{Tokens: float64(policy.Limit), UpdatedAt: now}
+}
+
+// Take refills the bucket for the time passed and takes a token from it if
+// there is one.
+func (b *Bucket) Take(policy *Policy, now time.Time) *Decision {
+	elapsed := now.Sub(b.UpdatedAt).Seconds()
+	if elapsed > 0 {
+		b.Tokens = math.Min(float64(policy.Limit), b.Tokens+elapsed*policy.Rate())
+		b.UpdatedAt = now
+	}
+
+	allowed := b.Tokens >= 1
+	if allowed {
+		b.Tokens--
+	}
+
+	return policy.Decide(b.Tokens, allowed)
+}
+
+// ParsePolicies applies the "policy=limit/window" overrides to the default
+// policies. Unknown policy names are rejected to catch typos.
+func ParsePolicies(value string) (map[string]*Policy, error) {
+	policies := DefaultPolicies()
+
+	for entry := range strings.SplitSeq(value, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		name, spec, found := strings.Cut(entry, "=")
+		if !found {
+			return nil, fmt.Errorf("%w: %q is not in policy=limit/window form", ErrInvalidPolicy, entry)
+		}
+
+		policy, ok := policies[strings.TrimSpace(name)]
+		if !ok {
+			return nil, fmt.Errorf("%w: unknown policy %q", ErrInvalidPolicy, name)
+		}
+
+		limit, window, found := strings.Cut(spec, "/")
+		if !found {
+			return nil, fmt.Errorf("%w: %q is not in policy=limit/window form", ErrInvalidPolicy, entry)
+		}
+
+		n, err := strconv.Atoi(strings.TrimSpace(limit))
+		if err != nil || n < 1 {
+			return nil, fmt.Errorf("%w: %q must have a positive limit", ErrInvalidPolicy, entry)
+		}
+
+		d, err := time.ParseDuration(strings.TrimSpace(window))
+		if err != nil || d <= 0 {
+			return nil, fmt.Errorf("%w: %q must have a positive window such as 1m", ErrInvalidPolicy, entry)
+		}
+
+		policy.Limit = n
+		policy.Window = d
+	}
+
+	return policies, nil
+}
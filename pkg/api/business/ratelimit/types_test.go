@@ -0,0 +1,62 @@
+package ratelimit_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/eser/acik.io/pkg/api/business/ratelimit"
+)
+
+func TestBucketTake(t *testing.T) {
+	t.Parallel()
+
+	// a token per second, up to two.
+	policy := &ratelimit.Policy{Name: "test", Limit: 2, Window: 2 * time.Second}
+	start := time.Date(2026, time.January, 1, 0, 0, 0, 0, time.UTC)
+	bucket := ratelimit.NewBucket(policy, start)
+
+	tests := []struct {
+		name string
+		at   time.Duration
+		want ratelimit.Decision
+	}{
+		{name: "first of a burst", at: 0, want: ratelimit.Decision{Allowed: true, Limit: 2, Remaining: 1, Reset: time.Second, RetryAfter: 0}},
+		{name: "last of a burst", at: 0, want: ratelimit.Decision{Allowed: true, Limit: 2, Remaining: 0, Reset: 2 * time.Second, RetryAfter: 0}},
+		{name: "empty", at: 0, want: ratelimit.Decision{Allowed: false, Limit: 2, Remaining: 0, Reset: 2 * time.Second, RetryAfter: time.Second}},
+		{name: "half refilled", at: time.Second / 2, want: ratelimit.Decision{Allowed: false, Limit: 2, Remaining: 0, Reset: 3 * time.Second / 2, RetryAfter: time.Second / 2}},
+		{name: "refilled a token", at: time.Second, want: ratelimit.Decision{Allowed: true, Limit: 2, Remaining: 0, Reset: 2 * time.Second, RetryAfter: 0}},
+		{name: "capped at the limit", at: time.Hour, want: ratelimit.Decision{Allowed: true, Limit: 2, Remaining: 1, Reset: time.Second, RetryAfter: 0}},
+	}
+
+	// the cases run in order against the same bucket.
+	for _, test := range tests {
+		if got := bucket.Take(policy, start.Add(test.at)); *got != test.want {
+			t.Errorf("%s: got %+v, want %+v", test.name, *got, test.want)
+		}
+	}
+}
+
+func TestParsePolicies(t *testing.T) {
+	t.Parallel()
+
+	policies, err := ratelimit.ParsePolicies(" votes = 5/10m, ,uploads=1/1s")
+	if err != nil {
+		t.Fatalf("parsing: %v", err)
+	}
+
+	if votes := policies[ratelimit.PolicyVotes]; votes.Limit != 5 || votes.Window != 10*time.Minute {
+		t.Errorf("got votes %+v, want the override", votes)
+	}
+
+	if write := policies[ratelimit.PolicyWrite]; *write != *ratelimit.DefaultPolicies()[ratelimit.PolicyWrite] {
+		t.Errorf("got write %+v, want the default", write)
+	}
+
+	for _, value := range []string{"votes", "votes=5", "likes=5/1m", "votes=0/1m", "votes=5/forever", "votes=5/-1m"} {
+		_, err := ratelimit.ParsePolicies(value)
+		if !errors.Is(err, ratelimit.ErrInvalidPolicy) {
+			t.Errorf("got %v for %q, want %v", err, value, ratelimit.ErrInvalidPolicy)
+		}
+	}
+}
@@ -0,0 +1,13 @@
+package readcache
+
+import "time"
+
+type Config struct {
+	Enabled     bool          `conf:"ENABLED"      default:"true"`
+	Backend     string        `conf:"BACKEND"      default:"memory"` // where entries are kept: memory, invalidated by the changes of the same instance only, or redis through the default cache connection, shared between instances and invalidated by every change
+	Size        string        `conf:"SIZE"         default:"10000"`  // number of entries the memory backend keeps before evicting the least recently used
+	Instances   int           `conf:"INSTANCES"    default:"1"`      // number of http instances serving the api; the memory backend is refused with more than one
+	Ttl         time.Duration `conf:"TTL"          default:"1m"`     // lifetime of cached records
+	NegativeTtl time.Duration `conf:"NEGATIVE_TTL" default:"15s"`    // lifetime of cached misses, such as unknown slugs
+	HomeTtl     time.Duration `conf:"HOME_TTL"     default:"30s"`    // lifetime of the cached home page
+}
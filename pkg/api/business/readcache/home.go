@@ -0,0 +1,59 @@
+package readcache
+
+import (
+	"context"
+	"errors"
+
+	"github.com/eser/acik.io/pkg/api/business/home"
+)
+
+// HomePageSource assembles the home page, see home.Service.
+type HomePageSource interface {
+	GetPage(ctx context.Context) (*home.Page, []error)
+}
+
+// HomePage serves the home page through the cache. Pages with unavailable
+// sections are served but not kept, so a slow section shows up again as
+// soon as it recovers.
+type HomePage struct {
+	source HomePageSource
+	cache  *Cache
+}
+
+func NewHomePage(source HomePageSource, cache *Cache) *HomePage {
+	return &HomePage{source: source, cache: cache}
+}
+
+// partialPage carries a page with unavailable sections out of the load
+// without it being kept.
+type partialPage struct {
+	page     *home.Page
+	problems []error
+}
+
+func (p *partialPage) Error() string {
+	return errors.Join(p.problems...).Error()
+}
+
+func (h *HomePage) GetPage(ctx context.Context) (*home.Page, []error) {
+	page, err := Fetch(ctx, h.cache, NameHome, HomePageKey(), h.cache.homeTtl(), func(ctx context.Context) (*home.Page, error) {
+		page, problems := h.source.GetPage(ctx)
+		if len(problems) > 0 {
+			return nil, &partialPage{page: page, problems: problems}
+		}
+
+		return page, nil
+	})
+
+	var partial *partialPage
+
+	switch {
+	case errors.As(err, &partial):
+		return partial.page, partial.problems
+	case err != nil:
+		// the page is assembled on its own when the cache can't be used.
+		return h.source.GetPage(ctx)
+	default:
+		return page, nil
+	}
+}
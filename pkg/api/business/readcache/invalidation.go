@@ -0,0 +1,128 @@
+package readcache
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/eser/acik.io/pkg/api/business/moderation"
+	"github.com/eser/acik.io/pkg/api/business/outbox"
+	"github.com/eser/acik.io/pkg/api/business/profiles"
+)
+
+// ProfileSource tells the current slug of a profile. It must not read
+// through the cache.
+type ProfileSource interface {
+	GetProfileById(ctx context.Context, id string) (*profiles.Profile, error)
+}
+
+// Invalidator deletes the entries domain events make stale. Events are
+// consumed by a single process, so the worker only reaches the entries of the
+// http instances through a shared backend. The http instance flushes the
+// events of its own changes into its cache as well (see Recorder); that is
+// all a memory backend gets, which is why it is kept to a single instance,
+// and the entries made stale by the worker itself run out with their ttl.
+type Invalidator struct {
+	cache    *Cache
+	profiles ProfileSource
+}
+
+func NewInvalidator(cache *Cache, profiles ProfileSource) *Invalidator {
+	return &Invalidator{cache: cache, profiles: profiles}
+}
+
+// HandleDomainEvent invalidates the home page on every event of EventTypes,
+// as they all bear on one of its sections, and the profile an event is
+// about, by id and by its current and previous slugs.
+func (i *Invalidator) HandleDomainEvent(ctx context.Context, envelope *outbox.Envelope) error {
+	if !i.cache.enabled() {
+		return nil
+	}
+
+	keys := []string{HomePageKey()}
+
+	profileId, staleSlug, err := profileOf(envelope)
+	if err != nil {
+		return err
+	}
+
+	if profileId != "" {
+		keys = append(keys, ProfileByIdKey(profileId))
+
+		if staleSlug != "" {
+			keys = append(keys, ProfileBySlugKey(staleSlug))
+		}
+
+		profile, err := i.profiles.GetProfileById(ctx, profileId)
+		if err != nil {
+			return err //nolint:wrapcheck
+		}
+
+		if profile != nil {
+			keys = append(keys, ProfileBySlugKey(profile.Slug))
+		}
+	}
+
+	return i.cache.Invalidate(ctx, keys...)
+}
+
+// Flush invalidates the entries made stale by the pending events, once the
+// changes that recorded them are committed.
+func (i *Invalidator) Flush(ctx context.Context, pending *Pending) error {
+	envelopes := pending.take()
+	errs := make([]error, 0, len(envelopes))
+
+	for _, envelope := range envelopes {
+		errs = append(errs, i.HandleDomainEvent(ctx, envelope))
+	}
+
+	return errors.Join(errs...)
+}
+
+// profileOf returns the profile an event changed, if any, along with a slug
+// whose entry it made stale besides the current one: the previous slug of a
+// renamed profile, or the slug of a new profile, which may be cached as a
+// miss.
+func profileOf(envelope *outbox.Envelope) (string, string, error) {
+	switch envelope.EventType {
+	case profiles.EventProfileCreated:
+		var payload profiles.ProfileCreatedEvent
+
+		err := decode(envelope, &payload)
+		if err != nil {
+			return "", "", err
+		}
+
+		return payload.ProfileId, payload.Slug, nil
+	case profiles.EventProfileUpdated:
+		var payload profiles.ProfileUpdatedEvent
+
+		err := decode(envelope, &payload)
+		if err != nil {
+			return "", "", err
+		}
+
+		return payload.ProfileId, payload.PreviousSlug, nil
+	case moderation.EventContentModerated:
+		var payload moderation.ContentModeratedEvent
+
+		err := decode(envelope, &payload)
+		if err != nil || payload.EntityType != moderation.EntityProfile {
+			return "", "", err
+		}
+
+		return payload.EntityId, "", nil
+	default:
+		return "", "", nil
+	}
+}
+
+func decode(envelope *outbox.Envelope, payload any) error {
+	err := json.Unmarshal(envelope.Payload, payload)
+	if err != nil {
+		return fmt.Errorf("%w(event: %s): %w", ErrFailedToDecodePayload, envelope.Id, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,47 @@
+package readcache
+
+import (
+	"context"
+
+	"github.com/eser/acik.io/pkg/api/business/profiles"
+)
+
+// ProfileRepository reads profiles by id and by slug through the cache,
+// passing everything else to the repository it decorates. It is meant for
+// read paths only; changes must read the repository itself, so they don't
+// work on a cached profile.
+type ProfileRepository struct {
+	profiles.Repository
+
+	cache *Cache
+}
+
+func NewProfileRepository(repo profiles.Repository, cache *Cache) *ProfileRepository {
+	return &ProfileRepository{Repository: repo, cache: cache}
+}
+
+func (r *ProfileRepository) GetProfileById(ctx context.Context, id string) (*profiles.Profile, error) {
+	return Fetch(
+		ctx,
+		r.cache,
+		NameProfiles,
+		ProfileByIdKey(id),
+		r.cache.ttl(),
+		func(ctx context.Context) (*profiles.Profile, error) {
+			return r.Repository.GetProfileById(ctx, id) //nolint:wrapcheck
+		},
+	)
+}
+
+func (r *ProfileRepository) GetProfileBySlug(ctx context.Context, slug string) (*profiles.Profile, error) {
+	return Fetch(
+		ctx,
+		r.cache,
+		NameProfiles,
+		ProfileBySlugKey(slug),
+		r.cache.ttl(),
+		func(ctx context.Context) (*profiles.Profile, error) {
+			return r.Repository.GetProfileBySlug(ctx, slug) //nolint:wrapcheck
+		},
+	)
+}
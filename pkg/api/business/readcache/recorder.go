@@ -0,0 +1,99 @@
+package readcache
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/eser/acik.io/pkg/api/business/outbox"
+)
+
+type contextKey string
+
+const contextKeyPending contextKey = "readcache-pending"
+
+// EventRecorder records domain events, see outbox.Service.
+type EventRecorder interface {
+	Record(ctx context.Context, aggregateType string, aggregateId string, eventType string, payload any) error
+}
+
+// Recorder records domain events through the recorder it decorates, and
+// notes the ones of EventTypes in the pending changes the context carries.
+// The process making a change flushes them once it is committed, so its own
+// entries don't outlive the change until the event reaches the worker.
+type Recorder struct {
+	events EventRecorder
+}
+
+func NewRecorder(events EventRecorder) *Recorder {
+	return &Recorder{events: events}
+}
+
+func (r *Recorder) Record(
+	ctx context.Context,
+	aggregateType string,
+	aggregateId string,
+	eventType string,
+	payload any,
+) error {
+	err := r.events.Record(ctx, aggregateType, aggregateId, eventType, payload)
+	if err != nil {
+		return err //nolint:wrapcheck
+	}
+
+	pending, hasPending := ctx.Value(contextKeyPending).(*Pending)
+	if !hasPending || !slices.Contains(EventTypes, eventType) {
+		return nil
+	}
+
+	encoded, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("%w(event: %s): %w", ErrFailedToEncodeRecord, eventType, err)
+	}
+
+	pending.add(&outbox.Envelope{
+		OccurredAt:    time.Now(),
+		Id:            "",
+		AggregateType: aggregateType,
+		AggregateId:   aggregateId,
+		EventType:     eventType,
+		Payload:       encoded,
+		Attempt:       0,
+	})
+
+	return nil
+}
+
+// Pending collects the events recorded while serving a change, to be
+// flushed by Invalidator.Flush after it is committed.
+type Pending struct {
+	envelopes []*outbox.Envelope
+	mu        sync.Mutex
+}
+
+// WithPending returns a context collecting the events recorded within it.
+func WithPending(ctx context.Context) (context.Context, *Pending) {
+	pending := &Pending{} //nolint:exhaustruct
+
+	return context.WithValue(ctx, contextKeyPending, pending), pending
+}
+
+func (p *Pending) add(envelope *outbox.Envelope) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.envelopes = append(p.envelopes, envelope)
+}
+
+func (p *Pending) take() []*outbox.Envelope {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	envelopes := p.envelopes
+	p.envelopes = nil
+
+	return envelopes
+}
@@ -0,0 +1,199 @@
+package readcache
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"golang.org/x/sync/singleflight"
+)
+
+var (
+	ErrUnknownBackend        = errors.New("unknown cache backend")
+	ErrInvalidConfig         = errors.New("invalid cache config")
+	ErrFailedToInvalidate    = errors.New("failed to invalidate cache")
+	ErrFailedToEncodeRecord  = errors.New("failed to encode record")
+	ErrFailedToDecodeRecord  = errors.New("failed to decode cached record")
+	ErrFailedToDecodePayload = errors.New("failed to decode payload")
+)
+
+// negativeEntry is what a miss is stored as. It decodes to a nil record.
+var negativeEntry = []byte("null") //nolint:gochecknoglobals
+
+// Store keeps the encoded records.
+type Store interface {
+	Get(ctx context.Context, key string) ([]byte, bool, error)
+	Set(ctx context.Context, key string, value []byte, ttl time.Duration) error
+	Delete(ctx context.Context, keys ...string) error
+}
+
+// Metrics observes the lookups and the loads of each cache.
+type Metrics interface {
+	CountLookup(name string, outcome string)
+	ObserveLoad(name string, duration time.Duration)
+}
+
+// Cache reads records through a store, loading them from their source on a
+// miss. Concurrent misses of a key are collapsed into a single load. Records
+// are kept encoded, so callers get copies they are free to change. A nil or
+// disabled cache loads every record.
+//
+// Entries are invalidated by domain events, after the change that caused
+// them is committed. A load racing with an invalidation may still store the
+// previous state, which lasts until the ttl runs out.
+type Cache struct {
+	config  *Config
+	store   Store
+	metrics Metrics
+	flights singleflight.Group
+}
+
+func NewCache(config *Config, store Store, metrics Metrics) *Cache {
+	return &Cache{config: config, store: store, metrics: metrics, flights: singleflight.Group{}}
+}
+
+func (c *Cache) enabled() bool {
+	return c != nil && c.config.Enabled
+}
+
+func (c *Cache) ttl() time.Duration {
+	if c == nil {
+		return 0
+	}
+
+	return c.config.Ttl
+}
+
+func (c *Cache) homeTtl() time.Duration {
+	if c == nil {
+		return 0
+	}
+
+	return c.config.HomeTtl
+}
+
+// Invalidate deletes the entries of the keys.
+func (c *Cache) Invalidate(ctx context.Context, keys ...string) error {
+	if !c.enabled() || len(keys) == 0 {
+		return nil
+	}
+
+	err := c.store.Delete(ctx, keys...)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrFailedToInvalidate, err)
+	}
+
+	return nil
+}
+
+// Fetch returns the record under the key, loading it on a miss and keeping
+// it for ttl. Loads returning no record are kept for the negative ttl, as
+// unknown slugs are asked for as often as known ones. Failed loads are not
+// kept. The store failing is counted and treated as a miss, so the cache
+// never fails a read its source would serve.
+func Fetch[T any](
+	ctx context.Context,
+	cache *Cache,
+	name string,
+	key string,
+	ttl time.Duration,
+	load func(ctx context.Context) (*T, error),
+) (*T, error) {
+	if !cache.enabled() {
+		return load(ctx)
+	}
+
+	encoded, found, err := cache.store.Get(ctx, key)
+
+	switch {
+	case err != nil:
+		cache.metrics.CountLookup(name, OutcomeError)
+	case !found:
+		cache.metrics.CountLookup(name, OutcomeMiss)
+	case string(encoded) == string(negativeEntry):
+		cache.metrics.CountLookup(name, OutcomeNegativeHit)
+
+		return nil, nil
+	default:
+		record, err := decodeRecord[T](encoded)
+		if err == nil {
+			cache.metrics.CountLookup(name, OutcomeHit)
+
+			return record, nil
+		}
+
+		// entries that don't decode anymore are loaded again.
+		cache.metrics.CountLookup(name, OutcomeError)
+	}
+
+	// the load is shared by every caller waiting on the key, so it outlives
+	// the one that started it. Callers stop waiting when they are cancelled.
+	flight := cache.flights.DoChan(key, func() (any, error) {
+		return cache.load(context.WithoutCancel(ctx), name, key, ttl, func(ctx context.Context) (any, error) {
+			return load(ctx)
+		})
+	})
+
+	select {
+	case result := <-flight:
+		if result.Err != nil {
+			return nil, result.Err
+		}
+
+		encoded, _ = result.Val.([]byte)
+
+		return decodeRecord[T](encoded)
+	case <-ctx.Done():
+		return nil, ctx.Err() //nolint:wrapcheck
+	}
+}
+
+func (c *Cache) load(
+	ctx context.Context,
+	name string,
+	key string,
+	ttl time.Duration,
+	load func(ctx context.Context) (any, error),
+) ([]byte, error) {
+	started := time.Now()
+	record, err := load(ctx)
+	c.metrics.ObserveLoad(name, time.Since(started))
+
+	if err != nil {
+		return nil, err
+	}
+
+	encoded, err := json.Marshal(record)
+	if err != nil {
+		return nil, fmt.Errorf("%w(key: %s): %w", ErrFailedToEncodeRecord, key, err)
+	}
+
+	if string(encoded) == string(negativeEntry) {
+		ttl = c.config.NegativeTtl
+	}
+
+	// a zero ttl turns caching off, rather than keeping entries forever.
+	if ttl <= 0 {
+		return encoded, nil
+	}
+
+	err = c.store.Set(ctx, key, encoded, ttl)
+	if err != nil {
+		c.metrics.CountLookup(name, OutcomeError)
+	}
+
+	return encoded, nil
+}
+
+func decodeRecord[T any](encoded []byte) (*T, error) {
+	var record *T
+
+	err := json.Unmarshal(encoded, &record)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrFailedToDecodeRecord, err)
+	}
+
+	return record, nil
+}
@@ -0,0 +1,351 @@
+package readcache_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/eser/acik.io/pkg/api/adapters/readcachestore"
+	"github.com/eser/acik.io/pkg/api/business/outbox/outboxtest"
+	"github.com/eser/acik.io/pkg/api/business/profiles"
+	"github.com/eser/acik.io/pkg/api/business/readcache"
+)
+
+var errUnavailable = errors.New("unavailable")
+
+// metrics counts the lookup outcomes.
+type metrics struct {
+	outcomes map[string]int
+	mu       sync.Mutex
+}
+
+func (m *metrics) CountLookup(_ string, outcome string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.outcomes[outcome]++
+}
+
+func (m *metrics) ObserveLoad(string, time.Duration) {}
+
+func (m *metrics) count(outcome string) int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return m.outcomes[outcome]
+}
+
+func newCache() (*readcache.Cache, *metrics) {
+	counts := &metrics{outcomes: map[string]int{}} //nolint:exhaustruct
+
+	return readcache.NewCache(&readcache.Config{
+		Enabled:     true,
+		Backend:     readcachestore.BackendMemory,
+		Size:        "100",
+		Ttl:         time.Minute,
+		NegativeTtl: time.Minute,
+		HomeTtl:     time.Minute,
+	}, readcachestore.NewMemoryStore(100), counts), counts //nolint:mnd
+}
+
+type record struct {
+	Name string `json:"name"`
+}
+
+// loader serves the record it holds, counting the loads.
+type loader struct {
+	record *record
+	err    error
+	loads  int
+}
+
+func (l *loader) load(context.Context) (*record, error) {
+	l.loads++
+
+	if l.err != nil {
+		return nil, l.err
+	}
+
+	if l.record == nil {
+		return nil, nil
+	}
+
+	clone := *l.record
+
+	return &clone, nil
+}
+
+func fetch(t *testing.T, cache *readcache.Cache, source *loader) *record {
+	t.Helper()
+
+	result, err := readcache.Fetch(context.Background(), cache, "test", "key", time.Minute, source.load)
+	if err != nil {
+		t.Fatalf("fetching: %v", err)
+	}
+
+	return result
+}
+
+func TestFetchMissThenHit(t *testing.T) {
+	t.Parallel()
+
+	cache, counts := newCache()
+	source := &loader{record: &record{Name: "first"}} //nolint:exhaustruct
+
+	if got := fetch(t, cache, source); got == nil || got.Name != "first" {
+		t.Fatalf("got %+v on a miss, want the loaded record", got)
+	}
+
+	got := fetch(t, cache, source)
+	if got == nil || got.Name != "first" {
+		t.Fatalf("got %+v on a hit, want the cached record", got)
+	}
+
+	if source.loads != 1 || counts.count(readcache.OutcomeMiss) != 1 || counts.count(readcache.OutcomeHit) != 1 {
+		t.Errorf("got %d loads, %d misses and %d hits, want 1 of each",
+			source.loads, counts.count(readcache.OutcomeMiss), counts.count(readcache.OutcomeHit))
+	}
+
+	// callers get copies.
+	got.Name = "changed"
+
+	if again := fetch(t, cache, source); again.Name != "first" {
+		t.Errorf("got %q after changing a returned record, want the cached one", again.Name)
+	}
+}
+
+func TestFetchKeepsMisses(t *testing.T) {
+	t.Parallel()
+
+	cache, counts := newCache()
+	source := &loader{} //nolint:exhaustruct
+
+	for range 2 {
+		if got := fetch(t, cache, source); got != nil {
+			t.Fatalf("got %+v, want no record", got)
+		}
+	}
+
+	if source.loads != 1 || counts.count(readcache.OutcomeNegativeHit) != 1 {
+		t.Errorf("got %d loads and %d negative hits, want 1 of each", source.loads, counts.count(readcache.OutcomeNegativeHit))
+	}
+}
+
+func TestFetchDoesNotKeepFailures(t *testing.T) {
+	t.Parallel()
+
+	cache, _ := newCache()
+	source := &loader{err: errUnavailable} //nolint:exhaustruct
+
+	_, err := readcache.Fetch(context.Background(), cache, "test", "key", time.Minute, source.load)
+	if !errors.Is(err, errUnavailable) {
+		t.Fatalf("got %v, want %v", err, errUnavailable)
+	}
+
+	source.err = nil
+	source.record = &record{Name: "recovered"}
+
+	if got := fetch(t, cache, source); got == nil || got.Name != "recovered" {
+		t.Errorf("got %+v after a failed load, want the record loaded again", got)
+	}
+}
+
+func TestDisabledCacheLoadsEveryTime(t *testing.T) {
+	t.Parallel()
+
+	source := &loader{record: &record{Name: "first"}} //nolint:exhaustruct
+
+	fetch(t, nil, source)
+	fetch(t, nil, source)
+
+	if source.loads != 2 {
+		t.Errorf("got %d loads, want 2", source.loads)
+	}
+}
+
+// profileStore serves profiles from memory, counting the reads.
+type profileStore struct {
+	profiles.Repository
+
+	byId  map[string]*profiles.Profile
+	reads int
+	mu    sync.Mutex
+}
+
+func newProfileStore() *profileStore {
+	return &profileStore{byId: map[string]*profiles.Profile{}} //nolint:exhaustruct
+}
+
+func (s *profileStore) put(id string, slug string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.byId[id] = &profiles.Profile{Id: id, Slug: slug} //nolint:exhaustruct
+}
+
+func (s *profileStore) GetProfileById(_ context.Context, id string) (*profiles.Profile, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.reads++
+
+	return s.byId[id], nil
+}
+
+func (s *profileStore) GetProfileBySlug(_ context.Context, slug string) (*profiles.Profile, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.reads++
+
+	for _, profile := range s.byId {
+		if profile.Slug == slug {
+			return profile, nil
+		}
+	}
+
+	return nil, nil //nolint:nilnil
+}
+
+func slugOf(t *testing.T, repo *readcache.ProfileRepository, slug string) *profiles.Profile {
+	t.Helper()
+
+	profile, err := repo.GetProfileBySlug(context.Background(), slug)
+	if err != nil {
+		t.Fatalf("getting profile: %v", err)
+	}
+
+	return profile
+}
+
+func TestProfileCreatedClearsCachedMiss(t *testing.T) {
+	t.Parallel()
+
+	cache, _ := newCache()
+	store := newProfileStore()
+	repo := readcache.NewProfileRepository(store, cache)
+	invalidator := readcache.NewInvalidator(cache, store)
+
+	if profile := slugOf(t, repo, "eser"); profile != nil {
+		t.Fatalf("got %+v before the profile exists", profile)
+	}
+
+	store.put("p1", "eser")
+
+	if profile := slugOf(t, repo, "eser"); profile != nil {
+		t.Fatalf("got %+v, want the cached miss until invalidated", profile)
+	}
+
+	err := invalidator.HandleDomainEvent(context.Background(), outboxtest.Envelope(t, profiles.EventProfileCreated, &profiles.ProfileCreatedEvent{
+		ProfileId:       "p1",
+		Kind:            profiles.KindIndividual,
+		Slug:            "eser",
+		Title:           "Eser",
+		CreatedByUserId: "u1",
+	}))
+	if err != nil {
+		t.Fatalf("invalidating: %v", err)
+	}
+
+	if profile := slugOf(t, repo, "eser"); profile == nil || profile.Id != "p1" {
+		t.Errorf("got %+v after the profile was created, want it", profile)
+	}
+}
+
+func TestProfileUpdatedClearsEveryKey(t *testing.T) {
+	t.Parallel()
+
+	cache, _ := newCache()
+	store := newProfileStore()
+	store.put("p1", "old")
+
+	repo := readcache.NewProfileRepository(store, cache)
+	invalidator := readcache.NewInvalidator(cache, store)
+
+	slugOf(t, repo, "old")
+	slugOf(t, repo, "new")
+
+	_, err := repo.GetProfileById(context.Background(), "p1")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	store.put("p1", "new")
+
+	err = invalidator.HandleDomainEvent(context.Background(), outboxtest.Envelope(t, profiles.EventProfileUpdated, &profiles.ProfileUpdatedEvent{
+		ProfileId:    "p1",
+		PreviousSlug: "old",
+	}))
+	if err != nil {
+		t.Fatalf("invalidating: %v", err)
+	}
+
+	if profile := slugOf(t, repo, "old"); profile != nil {
+		t.Errorf("got %+v by the previous slug, want none", profile)
+	}
+
+	if profile := slugOf(t, repo, "new"); profile == nil {
+		t.Errorf("got no profile by the new slug, want it")
+	}
+
+	profile, err := repo.GetProfileById(context.Background(), "p1")
+	if err != nil || profile == nil || profile.Slug != "new" {
+		t.Errorf("got %+v (%v) by id, want the renamed profile", profile, err)
+	}
+}
+
+func TestFlushInvalidatesRecordedChanges(t *testing.T) {
+	t.Parallel()
+
+	cache, _ := newCache()
+	store := newProfileStore()
+	store.put("p1", "eser")
+
+	repo := readcache.NewProfileRepository(store, cache)
+	invalidator := readcache.NewInvalidator(cache, store)
+	recorder := readcache.NewRecorder(outboxtest.Discard{})
+
+	slugOf(t, repo, "eser")
+	store.put("p1", "renamed")
+
+	ctx, pending := readcache.WithPending(context.Background())
+
+	err := recorder.Record(ctx, "profile", "p1", profiles.EventProfileUpdated, &profiles.ProfileUpdatedEvent{
+		ProfileId:    "p1",
+		PreviousSlug: "eser",
+	})
+	if err != nil {
+		t.Fatalf("recording: %v", err)
+	}
+
+	if profile := slugOf(t, repo, "eser"); profile == nil {
+		t.Fatalf("got no profile, want the cached one until flushed")
+	}
+
+	err = invalidator.Flush(ctx, pending)
+	if err != nil {
+		t.Fatalf("flushing: %v", err)
+	}
+
+	if profile := slugOf(t, repo, "eser"); profile != nil {
+		t.Errorf("got %+v by the previous slug after flushing, want none", profile)
+	}
+
+	// events are only noted within a context collecting them.
+	err = recorder.Record(context.Background(), "profile", "p1", profiles.EventProfileUpdated, &profiles.ProfileUpdatedEvent{
+		ProfileId:    "p1",
+		PreviousSlug: "",
+	})
+	if err != nil {
+		t.Fatalf("recording: %v", err)
+	}
+
+	reads := store.reads
+
+	err = invalidator.Flush(ctx, pending)
+	if err != nil || store.reads != reads {
+		t.Errorf("flushing again read %d profiles (%v), want nothing pending", store.reads-reads, err)
+	}
+}
@@ -0,0 +1,54 @@
+package readcache
+
+import (
+	"github.com/eser/acik.io/pkg/api/business/events"
+	"github.com/eser/acik.io/pkg/api/business/moderation"
+	"github.com/eser/acik.io/pkg/api/business/profiles"
+	"github.com/eser/acik.io/pkg/api/business/questions"
+	"github.com/eser/acik.io/pkg/api/business/stories"
+)
+
+const (
+	// KeyPrefix is prepended to every key. It is bumped when the shape of
+	// cached records changes, so instances of different versions sharing a
+	// store don't read each other's entries.
+	KeyPrefix = "readcache:v1:"
+
+	NameProfiles = "profiles"
+	NameHome     = "home"
+
+	OutcomeHit         = "hit"
+	OutcomeNegativeHit = "negative_hit"
+	OutcomeMiss        = "miss"
+	OutcomeError       = "error"
+)
+
+// EventTypes lists the domain events that invalidate cached records.
+var EventTypes = []string{ //nolint:gochecknoglobals
+	profiles.EventProfileCreated,
+	profiles.EventProfileUpdated,
+	stories.EventStoryCreated,
+	stories.EventStoryUpdated,
+	stories.EventStoryStatusChanged,
+	stories.EventStoryPublished,
+	stories.EventStoryFeaturedChanged,
+	events.EventEventCreated,
+	events.EventEventUpdated,
+	events.EventEventPublished,
+	events.EventEventRescheduled,
+	questions.EventQuestionCreated,
+	questions.EventQuestionAnswered,
+	moderation.EventContentModerated,
+}
+
+func ProfileByIdKey(id string) string {
+	return KeyPrefix + "profile:id:" + id
+}
+
+func ProfileBySlugKey(slug string) string {
+	return KeyPrefix + "profile:slug:" + slug
+}
+
+func HomePageKey() string {
+	return KeyPrefix + "home:page"
+}
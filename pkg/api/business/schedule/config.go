@@ -0,0 +1,9 @@
+package schedule
+
+import "time"
+
+//nolint:lll
+type Config struct {
+	Tasks        string        `conf:"TASKS" default:"users.sweep-sessions=*/15 * * * *;stories.publish-due=* * * * *;questions.refresh-vote-scores=*/5 * * * *;events.materialize-recurring=0 3 * * *;digest.send-weekly=0 8 * * 1;projects.refresh-metadata=15 * * * *;ratelimit.sweep-buckets=*/10 * * * *;idempotency.sweep-keys=*/30 * * * *"` // cron expressions of the scheduled tasks, as "task=expression;task=expression"
+	PollInterval time.Duration `conf:"POLL_INTERVAL" default:"15s"`                                                                                                                                                                                                                                                                                 // how often due tasks are checked for and leadership is claimed
+}
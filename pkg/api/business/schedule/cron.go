@@ -0,0 +1,206 @@
+package schedule
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// maxLookahead bounds the search for the next matching time, so expressions
+// that can never match (e.g. "0 0 31 2 *") don't loop forever.
+const maxLookahead = 5 * 366 * 24 * time.Hour
+
+var ErrInvalidCron = errors.New("invalid cron expression")
+
+//nolint:gochecknoglobals
+var cronAliases = map[string]string{
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+type cronField struct {
+	min int
+	max int
+}
+
+//nolint:gochecknoglobals
+var cronFields = [5]cronField{
+	{min: 0, max: 59}, // minute
+	{min: 0, max: 23}, // hour
+	{min: 1, max: 31}, // day of month
+	{min: 1, max: 12}, // month
+	{min: 0, max: 6},  // day of week, sunday being 0 (or 7)
+}
+
+// Cron is a parsed five field cron expression: minute, hour, day of month,
+// month and day of week. Fields accept "*", numbers, ranges ("1-5"), lists
+// ("1,15") and steps ("*/15", "0-30/10"). As in crontab, when both the day
+// of month and the day of week are restricted, matching either one is enough.
+type Cron struct {
+	Expression string
+
+	minutes     uint64
+	hours       uint64
+	daysOfMonth uint64
+	months      uint64
+	daysOfWeek  uint64
+
+	daysOfMonthAny bool
+	daysOfWeekAny  bool
+}
+
+func ParseCron(expression string) (*Cron, error) {
+	normalized := strings.TrimSpace(expression)
+	if alias, ok := cronAliases[normalized]; ok {
+		normalized = alias
+	}
+
+	parts := strings.Fields(normalized)
+	if len(parts) != len(cronFields) {
+		return nil, fmt.Errorf("%w(%q): expected %d fields", ErrInvalidCron, expression, len(cronFields))
+	}
+
+	var sets [5]uint64
+
+	for i, part := range parts {
+		set, err := parseCronField(part, cronFields[i])
+		if err != nil {
+			return nil, fmt.Errorf("%w(%q): %w", ErrInvalidCron, expression, err)
+		}
+
+		sets[i] = set
+	}
+
+	// 7 is an alias of sunday.
+	if sets[4]&(1<<7) != 0 {
+		sets[4] = sets[4]&^(1<<7) | 1
+	}
+
+	return &Cron{
+		Expression:     expression,
+		minutes:        sets[0],
+		hours:          sets[1],
+		daysOfMonth:    sets[2],
+		months:         sets[3],
+		daysOfWeek:     sets[4],
+		daysOfMonthAny: strings.HasPrefix(parts[2], "*"),
+		daysOfWeekAny:  strings.HasPrefix(parts[4], "*"),
+	}, nil
+}
+
+func parseCronField(value string, field cronField) (uint64, error) {
+	var set uint64
+
+	maxValue := field.max
+	if field.max == 6 { //nolint:mnd
+		maxValue = 7 // accept 7 for sunday
+	}
+
+	for item := range strings.SplitSeq(value, ",") {
+		rangePart, stepPart, hasStep := strings.Cut(item, "/")
+
+		step := 1
+
+		if hasStep {
+			parsed, err := strconv.Atoi(stepPart)
+			if err != nil || parsed < 1 {
+				return 0, fmt.Errorf("invalid step %q", item) //nolint:err113
+			}
+
+			step = parsed
+		}
+
+		low, high := field.min, maxValue
+
+		if rangePart != "*" {
+			lowPart, highPart, isRange := strings.Cut(rangePart, "-")
+
+			parsedLow, err := strconv.Atoi(lowPart)
+			if err != nil {
+				return 0, fmt.Errorf("invalid value %q", item) //nolint:err113
+			}
+
+			low, high = parsedLow, parsedLow
+
+			if isRange {
+				high, err = strconv.Atoi(highPart)
+				if err != nil {
+					return 0, fmt.Errorf("invalid range %q", item) //nolint:err113
+				}
+			} else if hasStep {
+				high = maxValue
+			}
+		}
+
+		if low < field.min || high > maxValue || low > high {
+			return 0, fmt.Errorf("%q is out of range %d-%d", item, field.min, maxValue) //nolint:err113
+		}
+
+		for n := low; n <= high; n += step {
+			set |= 1 << n
+		}
+	}
+
+	return set, nil
+}
+
+// Next returns the first time strictly after the given one that matches the
+// expression, in the location of the given time. It returns the zero time
+// when nothing matches within a few years.
+func (c *Cron) Next(after time.Time) time.Time {
+	t := after.Truncate(time.Minute).Add(time.Minute)
+	limit := after.Add(maxLookahead)
+
+	for t.Before(limit) {
+		if c.months&(1<<int(t.Month())) == 0 {
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, t.Location())
+
+			continue
+		}
+
+		if !c.matchesDay(t) {
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, t.Location())
+
+			continue
+		}
+
+		if c.hours&(1<<t.Hour()) == 0 {
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, t.Location())
+
+			continue
+		}
+
+		if c.minutes&(1<<t.Minute()) == 0 {
+			t = t.Add(time.Minute)
+
+			continue
+		}
+
+		return t
+	}
+
+	return time.Time{}
+}
+
+func (c *Cron) matchesDay(t time.Time) bool {
+	dayOfMonth := c.daysOfMonth&(1<<t.Day()) != 0
+	dayOfWeek := c.daysOfWeek&(1<<int(t.Weekday())) != 0
+
+	switch {
+	case c.daysOfMonthAny && c.daysOfWeekAny:
+		return true
+	case c.daysOfMonthAny:
+		return dayOfWeek
+	case c.daysOfWeekAny:
+		return dayOfMonth
+	default:
+		return dayOfMonth || dayOfWeek
+	}
+}
@@ -0,0 +1,83 @@
+package schedule_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/eser/acik.io/pkg/api/business/schedule"
+)
+
+func TestCronNext(t *testing.T) {
+	t.Parallel()
+
+	at := func(month time.Month, day int, hour int, minute int) time.Time {
+		return time.Date(2026, month, day, hour, minute, 0, 0, time.UTC)
+	}
+
+	tests := []struct {
+		expression string
+		after      time.Time
+		want       time.Time
+	}{
+		{expression: "*/15 * * * *", after: at(1, 1, 10, 7), want: at(1, 1, 10, 15)},
+		{expression: "*/15 * * * *", after: at(1, 1, 10, 15), want: at(1, 1, 10, 30)},
+		{expression: "0 9 * * 1-5", after: at(1, 2, 10, 0), want: at(1, 5, 9, 0)},
+		{expression: "@monthly", after: at(1, 15, 0, 0), want: at(2, 1, 0, 0)},
+		{expression: "0 0 * * 7", after: at(1, 1, 0, 0), want: at(1, 4, 0, 0)},
+		{expression: "0 0 13 * 5", after: at(1, 8, 12, 0), want: at(1, 9, 0, 0)},
+		{expression: "0,30 8-9 1 1,7 *", after: at(1, 1, 9, 0), want: at(1, 1, 9, 30)},
+		{expression: "30 2 29 2 *", after: at(1, 1, 0, 0), want: time.Date(2028, 2, 29, 2, 30, 0, 0, time.UTC)},
+		{expression: "0 0 31 2 *", after: at(1, 1, 0, 0), want: time.Time{}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.expression, func(t *testing.T) {
+			t.Parallel()
+
+			cron, err := schedule.ParseCron(test.expression)
+			if err != nil {
+				t.Fatalf("parsing: %v", err)
+			}
+
+			if got := cron.Next(test.after); !got.Equal(test.want) {
+				t.Errorf("got %v after %v, want %v", got, test.after, test.want)
+			}
+		})
+	}
+}
+
+func TestParseCronRejects(t *testing.T) {
+	t.Parallel()
+
+	for _, expression := range []string{"", "* * * *", "60 * * * *", "* * 0 * *", "*/0 * * * *", "5-1 * * * *", "a * * * *", "@often"} {
+		t.Run(expression, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := schedule.ParseCron(expression)
+			if !errors.Is(err, schedule.ErrInvalidCron) {
+				t.Errorf("got %v, want %v", err, schedule.ErrInvalidCron)
+			}
+		})
+	}
+}
+
+func TestParseTasks(t *testing.T) {
+	t.Parallel()
+
+	tasks, err := schedule.ParseTasks(" digest = @daily ; ; cleanup=*/5 * * * *")
+	if err != nil {
+		t.Fatalf("parsing: %v", err)
+	}
+
+	if len(tasks) != 2 || tasks["digest"] == nil || tasks["cleanup"] == nil {
+		t.Fatalf("got %v, want the digest and cleanup tasks", tasks)
+	}
+
+	for _, value := range []string{"digest", "digest=@often"} {
+		_, err := schedule.ParseTasks(value)
+		if !errors.Is(err, schedule.ErrInvalidConfig) {
+			t.Errorf("got %v for %q, want %v", err, value, schedule.ErrInvalidConfig)
+		}
+	}
+}
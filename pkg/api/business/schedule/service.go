@@ -0,0 +1,328 @@
+package schedule
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+)
+
+var (
+	ErrFailedToListRecords   = errors.New("failed to list records")
+	ErrFailedToStartRun      = errors.New("failed to start run")
+	ErrFailedToFinishRun     = errors.New("failed to finish run")
+	ErrFailedToElectLeader   = errors.New("failed to elect leader")
+	ErrInvalidConfig         = errors.New("invalid schedule configuration")
+	ErrTaskAlreadyRegistered = errors.New("task is already registered")
+	ErrTaskFailed            = errors.New("task failed")
+	ErrTaskNotFound          = errors.New("task not found")
+	ErrTaskStillRunning      = errors.New("previous run of the task is still running")
+	ErrUnknownTaskInConfig   = errors.New("schedule configuration names an unknown task")
+	ErrRunAlreadyRecorded    = errors.New("run is already recorded")
+	ErrTaskPanicked          = errors.New("task panicked")
+)
+
+type Repository interface {
+	StartScheduleRun(ctx context.Context, arg StartScheduleRunParams) (*ScheduleRun, error)
+	FinishScheduleRun(ctx context.Context, arg FinishScheduleRunParams) error
+	ListLatestScheduleRuns(ctx context.Context) ([]*ScheduleRun, error)
+}
+
+// LeaderElector makes sure only a single instance fires the scheduled tasks.
+type LeaderElector interface {
+	// TryLead reports whether this instance leads, claiming the leadership if
+	// nobody holds it. It is called on every poll, so it also notices a lost
+	// leadership.
+	TryLead(ctx context.Context) (bool, error)
+	// Resign gives the leadership up, if this instance holds it.
+	Resign(ctx context.Context) error
+}
+
+type Service struct {
+	config      *Config
+	repo        Repository
+	leader      LeaderElector
+	idGenerator RecordIDGenerator
+	instance    string
+	tasks       []*Task
+
+	mu      sync.Mutex
+	running map[string]bool
+}
+
+func NewService(config *Config, repo Repository, leader LeaderElector, instance string) *Service {
+	return &Service{
+		config:      config,
+		repo:        repo,
+		leader:      leader,
+		idGenerator: DefaultIDGenerator,
+		instance:    instance,
+		tasks:       nil,
+		mu:          sync.Mutex{},
+		running:     map[string]bool{},
+	}
+}
+
+// Register adds a task. It is scheduled with the cron expression configured
+// for its name, tasks without one can still be run manually.
+func (s *Service) Register(name string, run TaskFunc) error {
+	if s.task(name) != nil {
+		return fmt.Errorf("%w: %s", ErrTaskAlreadyRegistered, name)
+	}
+
+	crons, err := ParseTasks(s.config.Tasks)
+	if err != nil {
+		return err
+	}
+
+	s.tasks = append(s.tasks, &Task{
+		Run:  run,
+		Cron: crons[name],
+		Name: name,
+	})
+
+	return nil
+}
+
+// Tasks returns the registered tasks along with their next and last runs.
+func (s *Service) Tasks(ctx context.Context) ([]*TaskStatus, error) {
+	runs, err := s.repo.ListLatestScheduleRuns(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrFailedToListRecords, err)
+	}
+
+	now := time.Now()
+	result := make([]*TaskStatus, 0, len(s.tasks))
+
+	for _, task := range s.tasks {
+		status := &TaskStatus{
+			NextRunAt:  nil,
+			LastRun:    nil,
+			Name:       task.Name,
+			Expression: "",
+		}
+
+		if task.IsScheduled() {
+			status.Expression = task.Cron.Expression
+
+			if next := task.Cron.Next(now); !next.IsZero() {
+				status.NextRunAt = &next
+			}
+		}
+
+		index := slices.IndexFunc(runs, func(run *ScheduleRun) bool { return run.Task == task.Name })
+		if index != -1 {
+			status.LastRun = runs[index]
+		}
+
+		result = append(result, status)
+	}
+
+	return result, nil
+}
+
+// RunNow runs a task right away on this instance, regardless of its schedule
+// and of the leadership. The run is recorded like a scheduled one.
+func (s *Service) RunNow(ctx context.Context, name string) (*ScheduleRun, error) {
+	task := s.task(name)
+	if task == nil {
+		return nil, fmt.Errorf("%w: %s", ErrTaskNotFound, name)
+	}
+
+	if !s.acquire(task) {
+		return nil, fmt.Errorf("%w: %s", ErrTaskStillRunning, name)
+	}
+
+	defer s.release(task)
+
+	return s.fire(ctx, task, time.Now(), TriggerManual)
+}
+
+// Run fires the scheduled tasks when they are due, until the context is
+// cancelled. Every instance keeps track of the schedule, but only the leader
+// fires the tasks; a tick is also recorded only once, so a leadership change
+// in the middle of a tick can't run a task twice. Ticks missed while no
+// instance was running are not caught up on.
+func (s *Service) Run(ctx context.Context, onError func(err error)) error {
+	err := s.validate()
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	next := make(map[*Task]time.Time, len(s.tasks))
+
+	for _, task := range s.tasks {
+		if task.IsScheduled() {
+			next[task] = task.Cron.Next(now)
+		}
+	}
+
+	var wg sync.WaitGroup
+
+	defer func() {
+		wg.Wait()
+
+		err := s.leader.Resign(context.WithoutCancel(ctx))
+		if err != nil {
+			onError(fmt.Errorf("%w: %w", ErrFailedToElectLeader, err))
+		}
+	}()
+
+	ticker := time.NewTicker(s.config.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case now = <-ticker.C:
+		}
+
+		isLeader, err := s.leader.TryLead(ctx)
+		if err != nil {
+			onError(fmt.Errorf("%w: %w", ErrFailedToElectLeader, err))
+
+			isLeader = false
+		}
+
+		for task, scheduledAt := range next {
+			if scheduledAt.IsZero() || scheduledAt.After(now) {
+				continue
+			}
+
+			next[task] = task.Cron.Next(now)
+
+			if !isLeader {
+				continue
+			}
+
+			if !s.acquire(task) {
+				onError(fmt.Errorf("%w: %s", ErrTaskStillRunning, task.Name))
+
+				continue
+			}
+
+			wg.Add(1)
+
+			go func() {
+				defer wg.Done()
+				defer s.release(task)
+
+				_, err := s.fire(ctx, task, scheduledAt, TriggerSchedule)
+				if err != nil && !errors.Is(err, ErrRunAlreadyRecorded) {
+					onError(err)
+				}
+			}()
+		}
+	}
+}
+
+// fire records the run of a task for the given tick, runs it and records its
+// outcome. The tick is claimed first, so it runs at most once.
+func (s *Service) fire(ctx context.Context, task *Task, scheduledAt time.Time, trigger string) (*ScheduleRun, error) {
+	run, err := s.repo.StartScheduleRun(ctx, StartScheduleRunParams{
+		Id:          string(s.idGenerator()),
+		Task:        task.Name,
+		Trigger:     trigger,
+		Instance:    s.instance,
+		ScheduledAt: scheduledAt,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%w(task: %s): %w", ErrFailedToStartRun, task.Name, err)
+	}
+
+	if run == nil {
+		return nil, fmt.Errorf("%w(task: %s, scheduledAt: %s)", ErrRunAlreadyRecorded, task.Name, scheduledAt)
+	}
+
+	taskErr := s.runTask(ctx, task)
+
+	run.Status = RunStatusSucceeded
+	run.Error = sql.NullString{String: "", Valid: false}
+
+	if taskErr != nil {
+		run.Status = RunStatusFailed
+		run.Error = sql.NullString{String: taskErr.Error(), Valid: true}
+	}
+
+	// the outcome is recorded even when the task was cut off by a shutdown.
+	err = s.repo.FinishScheduleRun(context.WithoutCancel(ctx), FinishScheduleRunParams{
+		Status: run.Status,
+		Error:  run.Error,
+		Id:     run.Id,
+	})
+	if err != nil {
+		return run, fmt.Errorf("%w(task: %s, run: %s): %w", ErrFailedToFinishRun, task.Name, run.Id, err)
+	}
+
+	run.FinishedAt = sql.NullTime{Time: time.Now(), Valid: true}
+
+	if taskErr != nil {
+		return run, fmt.Errorf("%w(task: %s, run: %s): %w", ErrTaskFailed, task.Name, run.Id, taskErr)
+	}
+
+	return run, nil
+}
+
+func (s *Service) runTask(ctx context.Context, task *Task) (err error) {
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			err = fmt.Errorf("%w: %v", ErrTaskPanicked, recovered)
+		}
+	}()
+
+	return task.Run(ctx)
+}
+
+// validate rejects configured cron expressions of tasks that don't exist, as
+// they are most likely typos that would silently never run.
+func (s *Service) validate() error {
+	crons, err := ParseTasks(s.config.Tasks)
+	if err != nil {
+		return err
+	}
+
+	for name := range crons {
+		if s.task(name) == nil {
+			return fmt.Errorf("%w: %s", ErrUnknownTaskInConfig, name)
+		}
+	}
+
+	return nil
+}
+
+func (s *Service) task(name string) *Task {
+	for _, task := range s.tasks {
+		if task.Name == name {
+			return task
+		}
+	}
+
+	return nil
+}
+
+// acquire marks the task as running on this instance, so a slow run doesn't
+// overlap with the next tick.
+func (s *Service) acquire(task *Task) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.running[task.Name] {
+		return false
+	}
+
+	s.running[task.Name] = true
+
+	return true
+}
+
+func (s *Service) release(task *Task) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.running, task.Name)
+}
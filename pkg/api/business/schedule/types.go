@@ -0,0 +1,77 @@
+package schedule
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/oklog/ulid/v2"
+)
+
+const (
+	TriggerSchedule = "schedule"
+	TriggerManual   = "manual"
+
+	RunStatusRunning   = "running"
+	RunStatusSucceeded = "succeeded"
+	RunStatusFailed    = "failed"
+)
+
+type RecordID string
+
+type RecordIDGenerator func() RecordID
+
+func DefaultIDGenerator() RecordID {
+	return RecordID(ulid.Make().String())
+}
+
+// TaskFunc is the body of a scheduled task.
+type TaskFunc func(ctx context.Context) error
+
+type Task struct {
+	Run  TaskFunc
+	Cron *Cron
+	Name string
+}
+
+// IsScheduled tells whether the task has a cron expression, tasks without
+// one only run when triggered manually.
+func (t *Task) IsScheduled() bool {
+	return t.Cron != nil
+}
+
+// TaskStatus describes a task for operators.
+type TaskStatus struct {
+	NextRunAt  *time.Time   `json:"nextRunAt"`
+	LastRun    *ScheduleRun `json:"lastRun"`
+	Name       string       `json:"name"`
+	Expression string       `json:"expression"`
+}
+
+// ParseTasks parses the "task=expression;task=expression" form of the task
+// configuration into cron expressions by task name.
+func ParseTasks(value string) (map[string]*Cron, error) {
+	result := map[string]*Cron{}
+
+	for entry := range strings.SplitSeq(value, ";") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		name, expression, found := strings.Cut(entry, "=")
+		if !found {
+			return nil, fmt.Errorf("%w: %q is not in task=expression form", ErrInvalidConfig, entry)
+		}
+
+		cron, err := ParseCron(expression)
+		if err != nil {
+			return nil, fmt.Errorf("%w(task: %s): %w", ErrInvalidConfig, name, err)
+		}
+
+		result[strings.TrimSpace(name)] = cron
+	}
+
+	return result, nil
+}
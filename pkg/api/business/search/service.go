@@ -0,0 +1,145 @@
+package search
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"html"
+	"slices"
+	"strings"
+	"unicode"
+)
+
+const (
+	MaxQueryTerms = 8
+
+	// highlightStart and highlightStop delimit matches in the snippets coming
+	// from the database. They are swapped for markup only after the snippet
+	// is escaped, so the indexed text can never inject HTML.
+	highlightStart = "⟦"
+	highlightStop  = "⟧"
+
+	headlineOptions = "StartSel=" + highlightStart + ", StopSel=" + highlightStop +
+		", MaxWords=35, MinWords=15, MaxFragments=2, FragmentDelimiter=\" … \""
+)
+
+var (
+	ErrFailedToSearch  = errors.New("failed to search")
+	ErrFailedToRebuild = errors.New("failed to rebuild search documents")
+	ErrInvalidInput    = errors.New("invalid input")
+	ErrUnknownType     = errors.New("unknown entity type")
+	highlightReplacer  = strings.NewReplacer(highlightStart, "<mark>", highlightStop, "</mark>") //nolint:gochecknoglobals
+)
+
+type Repository interface {
+	SearchDocuments(ctx context.Context, arg SearchDocumentsParams) ([]*SearchDocumentsRow, error)
+	RebuildSearchDocuments(ctx context.Context, entityType string) error
+}
+
+type Service struct {
+	repo Repository
+}
+
+func NewService(repo Repository) *Service {
+	return &Service{repo: repo}
+}
+
+// Search ranks the visible profiles, stories, events and questions matching
+// the query text. Every term is matched as a prefix, so partial input as
+// typed into a search box already finds results. Profiles and questions carry
+// no tags, so a query narrowed down to a tag only finds stories and events.
+func (s *Service) Search(ctx context.Context, query *Query) (*Page, error) {
+	queryText := BuildPrefixQuery(query.Text)
+	if queryText == "" {
+		return nil, fmt.Errorf("%w: query must contain at least one word", ErrInvalidInput)
+	}
+
+	types := query.Types
+	if len(types) == 0 {
+		types = EntityTypes
+	}
+
+	for _, entityType := range types {
+		if !slices.Contains(EntityTypes, entityType) {
+			return nil, fmt.Errorf("%w: %q", ErrUnknownType, entityType)
+		}
+	}
+
+	rows, err := s.repo.SearchDocuments(ctx, SearchDocumentsParams{
+		QueryText:       queryText,
+		HeadlineOptions: headlineOptions,
+		EntityTypes:     strings.Join(types, ","),
+		TagId:           sql.NullString{String: query.TagId, Valid: query.TagId != ""},
+		LimitCount:      query.Limit,
+		OffsetCount:     query.Offset,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%w(query: %s): %w", ErrFailedToSearch, queryText, err)
+	}
+
+	page := &Page{
+		Results: make([]*Result, len(rows)),
+		Total:   0,
+		Limit:   query.Limit,
+		Offset:  query.Offset,
+	}
+
+	for i, row := range rows {
+		page.Total = row.TotalCount
+		page.Results[i] = &Result{
+			EntityType: row.EntityType,
+			EntityId:   row.EntityId,
+			Slug:       row.Slug,
+			Title:      strings.TrimSpace(row.Title),
+			Snippet:    highlightReplacer.Replace(html.EscapeString(row.Snippet)),
+			Rank:       row.Rank,
+		}
+	}
+
+	return page, nil
+}
+
+// BuildPrefixQuery turns free text into a tsquery expression that requires
+// every word, each matched as a prefix. Anything but letters and digits is
+// dropped, so user input can not alter the query syntax.
+func BuildPrefixQuery(text string) string {
+	terms := strings.FieldsFunc(text, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+
+	if len(terms) > MaxQueryTerms {
+		terms = terms[:MaxQueryTerms]
+	}
+
+	for i, term := range terms {
+		terms[i] = term + ":*"
+	}
+
+	return strings.Join(terms, " & ")
+}
+
+// Rebuild recreates the search documents of the given entity types from
+// their source tables, or of every entity type when none is given. The
+// documents are normally kept in sync by triggers; a rebuild is only needed
+// after the indexed columns or the text search configuration change.
+func (s *Service) Rebuild(ctx context.Context, types ...string) error {
+	if len(types) == 0 {
+		types = EntityTypes
+	}
+
+	for _, entityType := range types {
+		if !slices.Contains(EntityTypes, entityType) {
+			return fmt.Errorf("%w: %s", ErrUnknownType, entityType)
+		}
+	}
+
+	for _, entityType := range types {
+		err := s.repo.RebuildSearchDocuments(ctx, entityType)
+		if err != nil {
+			return fmt.Errorf("%w(type: %s): %w", ErrFailedToRebuild, entityType, err)
+		}
+	}
+
+	return nil
+}
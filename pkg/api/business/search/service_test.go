@@ -0,0 +1,106 @@
+package search_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/eser/acik.io/pkg/api/business/search"
+)
+
+// repository records the last search and answers it with fixed rows.
+type repository struct {
+	arg  search.SearchDocumentsParams
+	rows []*search.SearchDocumentsRow
+}
+
+func (r *repository) SearchDocuments(_ context.Context, arg search.SearchDocumentsParams) ([]*search.SearchDocumentsRow, error) {
+	r.arg = arg
+
+	return r.rows, nil
+}
+
+func (r *repository) RebuildSearchDocuments(context.Context, string) error {
+	return nil
+}
+
+func TestBuildPrefixQuery(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		text string
+		want string
+	}{
+		{name: "words", text: "açık kaynak", want: "açık:* & kaynak:*"},
+		{name: "query syntax", text: "go & !rust | (c:*)", want: "go:* & rust:* & c:*"},
+		{name: "digits", text: "go1.22", want: "go1:* & 22:*"},
+		{name: "no words", text: " !&| ", want: ""},
+		{name: "too many words", text: "a b c d e f g h i j", want: "a:* & b:* & c:* & d:* & e:* & f:* & g:* & h:*"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := search.BuildPrefixQuery(test.text); got != test.want {
+				t.Errorf("got %q, want %q", got, test.want)
+			}
+		})
+	}
+}
+
+func TestSearchRejects(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name  string
+		query search.Query
+		want  error
+	}{
+		{name: "no words", query: search.Query{Text: "!!"}, want: search.ErrInvalidInput},                             //nolint:exhaustruct
+		{name: "unknown type", query: search.Query{Text: "go", Types: []string{"user"}}, want: search.ErrUnknownType}, //nolint:exhaustruct
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := search.NewService(&repository{}).Search(context.Background(), &test.query) //nolint:exhaustruct
+			if !errors.Is(err, test.want) {
+				t.Errorf("got %v, want %v", err, test.want)
+			}
+		})
+	}
+}
+
+func TestSearch(t *testing.T) {
+	t.Parallel()
+
+	repo := &repository{ //nolint:exhaustruct
+		rows: []*search.SearchDocumentsRow{
+			{EntityType: "story", EntityId: "1", Slug: "go", Title: " Go \n", Snippet: "<b>⟦Go⟧</b>", Rank: 1, TotalCount: 3},
+		},
+	}
+
+	page, err := search.NewService(repo).Search(context.Background(), &search.Query{Text: "go", Types: nil, TagId: "tag", Limit: 1, Offset: 2})
+	if err != nil {
+		t.Fatalf("searching: %v", err)
+	}
+
+	if repo.arg.EntityTypes != "profile,story,event,question" {
+		t.Errorf("got entity types %q, want every type by default", repo.arg.EntityTypes)
+	}
+
+	if !repo.arg.TagId.Valid || repo.arg.TagId.String != "tag" {
+		t.Errorf("got tag %+v, want the tag of the query", repo.arg.TagId)
+	}
+
+	if page.Total != 3 || page.Limit != 1 || page.Offset != 2 {
+		t.Errorf("got page %+v, want the total of the rows and the paging of the query", page)
+	}
+
+	if result := page.Results[0]; result.Title != "Go" || result.Snippet != "&lt;b&gt;<mark>Go</mark>&lt;/b&gt;" {
+		t.Errorf("got result %+v, want a trimmed title and an escaped snippet with its matches marked", result)
+	}
+}
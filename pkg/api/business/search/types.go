@@ -0,0 +1,47 @@
+package search
+
+const (
+	EntityTypeProfile  = "profile"
+	EntityTypeStory    = "story"
+	EntityTypeEvent    = "event"
+	EntityTypeQuestion = "question"
+
+	QueueReindex = "search.reindex"
+)
+
+var EntityTypes = []string{ //nolint:gochecknoglobals
+	EntityTypeProfile,
+	EntityTypeStory,
+	EntityTypeEvent,
+	EntityTypeQuestion,
+}
+
+type Query struct {
+	Text   string
+	Types  []string
+	TagId  string // when set, only the stories and events with the tag match
+	Limit  int32
+	Offset int32
+}
+
+type Result struct {
+	EntityType string  `json:"entityType"`
+	EntityId   string  `json:"entityId"`
+	Slug       string  `json:"slug"`
+	Title      string  `json:"title"`
+	Snippet    string  `json:"snippet"`
+	Rank       float32 `json:"rank"`
+}
+
+type Page struct {
+	Results []*Result `json:"results"`
+	Total   int64     `json:"total"`
+	Limit   int32     `json:"limit"`
+	Offset  int32     `json:"offset"`
+}
+
+// ReindexJob is enqueued to rebuild the search documents of an entity type,
+// or of every entity type when EntityType is empty.
+type ReindexJob struct {
+	EntityType string `json:"entityType"`
+}
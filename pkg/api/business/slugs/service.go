@@ -0,0 +1,158 @@
+package slugs
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+)
+
+var (
+	ErrFailedToGetRecord    = errors.New("failed to get record")
+	ErrFailedToUpdateRecord = errors.New("failed to update record")
+	ErrRecordNotFound       = errors.New("record not found")
+	ErrInvalidSlug          = errors.New("invalid slug")
+	ErrReservedSlug         = errors.New("slug is reserved")
+	ErrSlugTaken            = errors.New("slug is already taken")
+	ErrUnknownKind          = errors.New("unknown kind")
+)
+
+type Repository interface {
+	GetSlugRedirect(ctx context.Context, arg GetSlugRedirectParams) (string, error)
+	IsSlugTaken(ctx context.Context, arg IsSlugTakenParams) (bool, error)
+	RecordSlugHistory(ctx context.Context, arg RecordSlugHistoryParams) error
+	DeleteSlugHistory(ctx context.Context, arg DeleteSlugHistoryParams) error
+	RenameProfileSlug(ctx context.Context, arg RenameProfileSlugParams) (string, error)
+	RenameEventSlug(ctx context.Context, arg RenameEventSlugParams) (string, error)
+	RenameEventSeriesSlug(ctx context.Context, arg RenameEventSeriesSlugParams) (string, error)
+	RenameStorySlug(ctx context.Context, arg RenameStorySlugParams) (string, error)
+
+	Transact(ctx context.Context, fn func(ctx context.Context) error) error
+}
+
+// Service keeps slugs of profiles, events, event series and stories. Renaming
+// keeps the previous slug in the history, so links to it can be redirected
+// to the current one.
+type Service struct {
+	repo Repository
+}
+
+func NewService(repo Repository) *Service {
+	return &Service{repo: repo}
+}
+
+// Validate checks that a slug is in its normal form, within bounds and not
+// reserved.
+func Validate(slug string) error {
+	if len(slug) < MinLength || len(slug) > MaxLength {
+		return fmt.Errorf("%w(slug: %s): must be %d to %d characters long", ErrInvalidSlug, slug, MinLength, MaxLength)
+	}
+
+	if normalized := Normalize(slug); normalized != slug {
+		return fmt.Errorf(
+			"%w(slug: %s): only lowercase letters, digits and dashes are allowed, try %q",
+			ErrInvalidSlug,
+			slug,
+			normalized,
+		)
+	}
+
+	if IsReserved(slug) {
+		return fmt.Errorf("%w(slug: %s)", ErrReservedSlug, slug)
+	}
+
+	return nil
+}
+
+// Rename gives a record a new slug, and returns the previous one. The
+// previous slug keeps pointing at the record, while the new one is taken out
+// of the history in case another record had it before. Authorization is left
+// to the caller.
+func (s *Service) Rename(ctx context.Context, kind string, id string, slug string) (string, error) {
+	rename, err := s.renamerOf(kind)
+	if err != nil {
+		return "", err
+	}
+
+	err = Validate(slug)
+	if err != nil {
+		return "", err
+	}
+
+	var previous string
+
+	err = s.repo.Transact(ctx, func(ctx context.Context) error {
+		isTaken, err := s.repo.IsSlugTaken(ctx, IsSlugTakenParams{Kind: kind, Slug: slug})
+		if err != nil {
+			return fmt.Errorf("%w(kind: %s, slug: %s): %w", ErrFailedToGetRecord, kind, slug, err)
+		}
+
+		if isTaken {
+			return fmt.Errorf("%w(kind: %s, slug: %s)", ErrSlugTaken, kind, slug)
+		}
+
+		previous, err = rename(ctx, id, slug)
+		if errors.Is(err, sql.ErrNoRows) {
+			return fmt.Errorf("%w(kind: %s, id: %s)", ErrRecordNotFound, kind, id)
+		}
+
+		if err != nil {
+			return fmt.Errorf("%w(kind: %s, id: %s): %w", ErrFailedToUpdateRecord, kind, id, err)
+		}
+
+		err = s.repo.DeleteSlugHistory(ctx, DeleteSlugHistoryParams{Kind: kind, Slug: slug})
+		if err != nil {
+			return fmt.Errorf("%w(kind: %s, slug: %s): %w", ErrFailedToUpdateRecord, kind, slug, err)
+		}
+
+		err = s.repo.RecordSlugHistory(ctx, RecordSlugHistoryParams{Kind: kind, Slug: previous, EntityId: id})
+		if err != nil {
+			return fmt.Errorf("%w(kind: %s, slug: %s): %w", ErrFailedToUpdateRecord, kind, previous, err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return "", err //nolint:wrapcheck
+	}
+
+	return previous, nil
+}
+
+// Resolve returns the current slug of the record that once had the given
+// slug. Callers are expected to look up current slugs first.
+func (s *Service) Resolve(ctx context.Context, kind string, slug string) (string, error) {
+	current, err := s.repo.GetSlugRedirect(ctx, GetSlugRedirectParams{Kind: kind, Slug: slug})
+	if errors.Is(err, sql.ErrNoRows) || (err == nil && current == "") {
+		return "", fmt.Errorf("%w(kind: %s, slug: %s)", ErrRecordNotFound, kind, slug)
+	}
+
+	if err != nil {
+		return "", fmt.Errorf("%w(kind: %s, slug: %s): %w", ErrFailedToGetRecord, kind, slug, err)
+	}
+
+	return current, nil
+}
+
+func (s *Service) renamerOf(kind string) (func(ctx context.Context, id string, slug string) (string, error), error) {
+	switch kind {
+	case KindProfile:
+		return func(ctx context.Context, id string, slug string) (string, error) {
+			return s.repo.RenameProfileSlug(ctx, RenameProfileSlugParams{Slug: slug, Id: id})
+		}, nil
+	case KindEvent:
+		return func(ctx context.Context, id string, slug string) (string, error) {
+			return s.repo.RenameEventSlug(ctx, RenameEventSlugParams{Slug: slug, Id: id})
+		}, nil
+	case KindEventSeries:
+		return func(ctx context.Context, id string, slug string) (string, error) {
+			return s.repo.RenameEventSeriesSlug(ctx, RenameEventSeriesSlugParams{Slug: slug, Id: id})
+		}, nil
+	case KindStory:
+		return func(ctx context.Context, id string, slug string) (string, error) {
+			return s.repo.RenameStorySlug(ctx, RenameStorySlugParams{Slug: slug, Id: id})
+		}, nil
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrUnknownKind, kind)
+	}
+}
@@ -0,0 +1,183 @@
+package slugs_test
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/eser/acik.io/pkg/api/business/slugs"
+)
+
+// repository keeps the current profile slugs by id and the history by slug.
+type repository struct {
+	slugs.Repository
+
+	current map[string]string
+	history map[string]string
+}
+
+func (r *repository) Transact(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
+
+func (r *repository) GetSlugRedirect(_ context.Context, arg slugs.GetSlugRedirectParams) (string, error) {
+	id, ok := r.history[arg.Slug]
+	if !ok {
+		return "", sql.ErrNoRows
+	}
+
+	return r.current[id], nil
+}
+
+func (r *repository) IsSlugTaken(_ context.Context, arg slugs.IsSlugTakenParams) (bool, error) {
+	for _, slug := range r.current {
+		if slug == arg.Slug {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+func (r *repository) RecordSlugHistory(_ context.Context, arg slugs.RecordSlugHistoryParams) error {
+	r.history[arg.Slug] = arg.EntityId
+
+	return nil
+}
+
+func (r *repository) DeleteSlugHistory(_ context.Context, arg slugs.DeleteSlugHistoryParams) error {
+	delete(r.history, arg.Slug)
+
+	return nil
+}
+
+func (r *repository) RenameProfileSlug(_ context.Context, arg slugs.RenameProfileSlugParams) (string, error) {
+	previous, ok := r.current[arg.Id]
+	if !ok {
+		return "", sql.ErrNoRows
+	}
+
+	r.current[arg.Id] = arg.Slug
+
+	return previous, nil
+}
+
+func newRepository() *repository {
+	return &repository{ //nolint:exhaustruct
+		current: map[string]string{"1": "eser", "2": "acik"},
+		history: map[string]string{"ozsoy": "2"},
+	}
+}
+
+func TestNormalize(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		title string
+		want  string
+	}{
+		{title: "Açık Kaynak Günleri", want: "acik-kaynak-gunleri"},
+		{title: "IŞIK İstanbul", want: "isik-istanbul"},
+		{title: "  Café -- Straße!  ", want: "cafe-strasse"},
+		{title: "Go 1.22 & Rust", want: "go-1-22-rust"},
+		{title: "日本語", want: ""},
+	}
+
+	for _, test := range tests {
+		t.Run(test.title, func(t *testing.T) {
+			t.Parallel()
+
+			if got := slugs.Normalize(test.title); got != test.want {
+				t.Errorf("got %q, want %q", got, test.want)
+			}
+		})
+	}
+}
+
+func TestValidate(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		slug string
+		want error
+	}{
+		{slug: "acik-io", want: nil},
+		{slug: "a", want: slugs.ErrInvalidSlug},
+		{slug: strings.Repeat("a", slugs.MaxLength+1), want: slugs.ErrInvalidSlug},
+		{slug: "Acik", want: slugs.ErrInvalidSlug},
+		{slug: "acik--io", want: slugs.ErrInvalidSlug},
+		{slug: "admin", want: slugs.ErrReservedSlug},
+	}
+
+	for _, test := range tests {
+		t.Run(test.slug, func(t *testing.T) {
+			t.Parallel()
+
+			if err := slugs.Validate(test.slug); !errors.Is(err, test.want) {
+				t.Errorf("got %v, want %v", err, test.want)
+			}
+		})
+	}
+
+	if !slugs.IsReserved("feed.xml") {
+		t.Error("got feed.xml not reserved, want names of feeds reserved")
+	}
+}
+
+func TestRename(t *testing.T) {
+	t.Parallel()
+
+	repo := newRepository()
+	service := slugs.NewService(repo)
+	ctx := context.Background()
+
+	// ozsoy was acik's before, renaming eser to it takes it out of the history.
+	previous, err := service.Rename(ctx, slugs.KindProfile, "1", "ozsoy")
+	if err != nil || previous != "eser" {
+		t.Fatalf("got %q, %v, want the previous slug", previous, err)
+	}
+
+	if current, err := service.Resolve(ctx, slugs.KindProfile, "eser"); err != nil || current != "ozsoy" {
+		t.Errorf("got %q, %v resolving the previous slug, want ozsoy", current, err)
+	}
+
+	if _, err := service.Resolve(ctx, slugs.KindProfile, "ozsoy"); !errors.Is(err, slugs.ErrRecordNotFound) {
+		t.Errorf("got %v resolving the taken over slug, want %v", err, slugs.ErrRecordNotFound)
+	}
+}
+
+func TestRenameRejects(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		kind string
+		id   string
+		slug string
+		want error
+	}{
+		{name: "taken", kind: slugs.KindProfile, id: "1", slug: "acik", want: slugs.ErrSlugTaken},
+		{name: "missing record", kind: slugs.KindProfile, id: "3", slug: "new-slug", want: slugs.ErrRecordNotFound},
+		{name: "reserved", kind: slugs.KindProfile, id: "1", slug: "search", want: slugs.ErrReservedSlug},
+		{name: "unknown kind", kind: "tag", id: "1", slug: "new-slug", want: slugs.ErrUnknownKind},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			repo := newRepository()
+
+			_, err := slugs.NewService(repo).Rename(context.Background(), test.kind, test.id, test.slug)
+			if !errors.Is(err, test.want) {
+				t.Errorf("got %v, want %v", err, test.want)
+			}
+
+			if repo.current["1"] != "eser" || len(repo.history) != 1 {
+				t.Errorf("got slugs %v and history %v, want them unchanged", repo.current, repo.history)
+			}
+		})
+	}
+}
@@ -0,0 +1,75 @@
+package slugs
+
+import (
+	"slices"
+	"strings"
+	"unicode"
+)
+
+const (
+	KindProfile     = "profile"
+	KindEvent       = "event"
+	KindEventSeries = "event_series"
+	KindStory       = "story"
+
+	MinLength = 2
+	MaxLength = 64
+)
+
+// reserved are the slugs that would collide with routes or that could pass
+// for an official page. Slugs live in separate namespaces per kind, so this
+// list is kept conservative rather than per kind.
+var reserved = []string{ //nolint:gochecknoglobals
+	"about", "admin", "api", "auth", "digest", "events", "featured", "feed", "feeds",
+	"followers", "following", "help", "home", "img", "login", "logout", "me", "media", "moderation", "new",
+	"picture", "profiles", "projects", "questions", "reports", "search", "settings", "stories", "support", "tags", "webhooks",
+}
+
+// RenameInput asks for a new slug.
+type RenameInput struct {
+	Slug string `json:"slug"`
+}
+
+var transliterations = map[rune]string{ //nolint:gochecknoglobals
+	'ç': "c", 'ğ': "g", 'ı': "i", 'ö': "o", 'ş': "s", 'ü': "u",
+	'â': "a", 'î': "i", 'û': "u",
+	'á': "a", 'à': "a", 'ä': "a", 'ã': "a", 'å': "a",
+	'é': "e", 'è': "e", 'ê': "e", 'ë': "e",
+	'í': "i", 'ì': "i", 'ï': "i",
+	'ó': "o", 'ò': "o", 'ô': "o", 'õ': "o", 'ø': "o",
+	'ú': "u", 'ù': "u",
+	'ñ': "n", 'ß': "ss",
+}
+
+// Normalize turns a title into a slug. Turkish letters and common diacritics
+// are transliterated, and everything else that isn't a letter or a digit
+// separates words.
+func Normalize(title string) string {
+	var builder strings.Builder
+
+	for _, r := range strings.ToLowerSpecial(unicode.TurkishCase, title) {
+		if replacement, ok := transliterations[r]; ok {
+			builder.WriteString(replacement)
+
+			continue
+		}
+
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') {
+			builder.WriteRune(r)
+
+			continue
+		}
+
+		builder.WriteByte('-')
+	}
+
+	words := strings.FieldsFunc(builder.String(), func(r rune) bool { return r == '-' })
+
+	return strings.Join(words, "-")
+}
+
+// IsReserved reports whether a slug is kept from users. Anything starting
+// with "feed." is reserved too, as feeds are served next to slugs.
+func IsReserved(slug string) bool {
+	return slices.Contains(reserved, slug) || strings.HasPrefix(slug, "feed.")
+}
@@ -0,0 +1,5 @@
+package stories
+
+type Config struct {
+	SiteUrl string `conf:"SITE_URL" default:"https://acik.io"` // public address story links in feeds point to
+}
@@ -0,0 +1,102 @@
+package stories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"unicode"
+
+	"github.com/eser/acik.io/pkg/api/business/tags"
+)
+
+const (
+	SummaryMaxLength = 240
+	WordsPerMinute   = 200
+
+	ContentFormatMarkdown ContentFormat = "content"
+	ContentFormatHtml     ContentFormat = "html"
+	ContentFormatBoth     ContentFormat = "both"
+)
+
+var ErrUnknownContentFormat = errors.New("unknown content format")
+
+// ContentFormat selects which representations of a story's content are
+// returned to the client.
+type ContentFormat string
+
+type Heading struct {
+	Id    string `json:"id"`
+	Text  string `json:"text"`
+	Level int    `json:"level"`
+}
+
+type RenderedContent struct {
+	Html            string
+	PlainText       string
+	TableOfContents []*Heading
+}
+
+// ContentRenderer converts the raw content of a story into sanitized HTML.
+type ContentRenderer interface {
+	Render(ctx context.Context, source string) (*RenderedContent, error)
+}
+
+// StoryView is a story as it is presented to readers. Content shadows the raw
+// content of the embedded story so it can be left out when only HTML is asked
+// for.
+type StoryView struct {
+	*Story
+
+	Content            *string        `json:"content,omitempty"`
+	ContentHtml        *string        `json:"contentHtml,omitempty"`
+	TableOfContents    []*Heading     `json:"tableOfContents,omitempty"`
+	ReadingTimeMinutes int            `json:"readingTimeMinutes"`
+	Tags               []*tags.TagRef `json:"tags"`
+}
+
+func ParseContentFormat(value string) (ContentFormat, error) {
+	switch ContentFormat(value) {
+	case "", ContentFormatMarkdown:
+		return ContentFormatMarkdown, nil
+	case ContentFormatHtml:
+		return ContentFormatHtml, nil
+	case ContentFormatBoth:
+		return ContentFormatBoth, nil
+	default:
+		return "", fmt.Errorf("%w: %q", ErrUnknownContentFormat, value)
+	}
+}
+
+// GenerateSummary cuts the plain text down to at most maxLength runes,
+// preferring to break on a word boundary.
+func GenerateSummary(plainText string, maxLength int) string {
+	text := strings.Join(strings.Fields(plainText), " ")
+	runes := []rune(text)
+
+	if len(runes) <= maxLength {
+		return text
+	}
+
+	cut := runes[:maxLength]
+
+	if lastSpace := strings.LastIndexFunc(string(cut), unicode.IsSpace); lastSpace > 0 {
+		return strings.TrimRightFunc(string(cut)[:lastSpace], unicode.IsPunct) + "…"
+	}
+
+	return string(cut) + "…"
+}
+
+// EstimateReadingTime returns the reading time of the text in whole minutes,
+// never less than a minute.
+func EstimateReadingTime(plainText string) int {
+	words := len(strings.Fields(plainText))
+
+	minutes := int(math.Ceil(float64(words) / WordsPerMinute))
+	if minutes < 1 {
+		return 1
+	}
+
+	return minutes
+}
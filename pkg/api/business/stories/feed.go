@@ -0,0 +1,193 @@
+package stories
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	FeedSize = 20
+
+	FeedContentFull    FeedContent = "full"
+	FeedContentSummary FeedContent = "summary"
+)
+
+var ErrUnknownFeedContent = errors.New("unknown feed content")
+
+// FeedContent selects whether feed entries carry the full rendered story or
+// only its summary.
+type FeedContent string
+
+// FeedStamp is a cheap fingerprint of a feed. It changes whenever a story
+// enters, leaves or is edited within the feed, so it can answer conditional
+// requests without loading the stories themselves.
+type FeedStamp struct {
+	LastModifiedAt time.Time
+	StoryCount     int64
+}
+
+type Feed struct {
+	UpdatedAt   time.Time
+	Id          string
+	Title       string
+	Description string
+	Link        string
+	FeedUrl     string
+	AuthorName  string
+	Entries     []*FeedEntry
+}
+
+type FeedEntry struct {
+	PublishedAt     time.Time
+	UpdatedAt       time.Time
+	Id              string
+	Title           string
+	Link            string
+	Summary         string
+	ContentHtml     string
+	StoryPictureUri string
+}
+
+type FeedInput struct {
+	AuthorProfileId string
+	Title           string
+	Description     string
+	Link            string
+	FeedUrl         string
+	AuthorName      string
+	Content         FeedContent
+}
+
+func ParseFeedContent(value string) (FeedContent, error) {
+	switch FeedContent(value) {
+	case "", FeedContentFull:
+		return FeedContentFull, nil
+	case FeedContentSummary:
+		return FeedContentSummary, nil
+	default:
+		return "", fmt.Errorf("%w: %q", ErrUnknownFeedContent, value)
+	}
+}
+
+// ETag returns a weak entity tag for the given representation of the feed.
+func (f *FeedStamp) ETag(variant string) string {
+	return `W/"` + variant + "-" + strconv.FormatInt(f.StoryCount, 10) + "-" +
+		strconv.FormatInt(f.LastModifiedAt.UnixNano(), 36) + `"`
+}
+
+func (s *Service) StoryUrl(record *Story) string {
+	return strings.TrimRight(s.config.SiteUrl, "/") + "/stories/" + record.Slug
+}
+
+func (s *Service) ProfileUrl(profileSlug string) string {
+	return strings.TrimRight(s.config.SiteUrl, "/") + "/profiles/" + profileSlug
+}
+
+func (s *Service) SiteUrl() string {
+	return strings.TrimRight(s.config.SiteUrl, "/")
+}
+
+// GetFeedStamp fingerprints the published stories, optionally limited to a
+// single author profile.
+func (s *Service) GetFeedStamp(ctx context.Context, authorProfileId string) (*FeedStamp, error) {
+	if authorProfileId == "" {
+		row, err := s.repo.GetPublishedStoriesFeedStamp(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrFailedToGetRecord, err)
+		}
+
+		return &FeedStamp{LastModifiedAt: row.LastModifiedAt, StoryCount: row.StoryCount}, nil
+	}
+
+	row, err := s.repo.GetPublishedStoriesFeedStampByAuthorProfileId(
+		ctx,
+		sql.NullString{String: authorProfileId, Valid: true},
+	)
+	if err != nil {
+		return nil, fmt.Errorf("%w(author: %s): %w", ErrFailedToGetRecord, authorProfileId, err)
+	}
+
+	return &FeedStamp{LastModifiedAt: row.LastModifiedAt, StoryCount: row.StoryCount}, nil
+}
+
+// BuildFeed collects the latest published stories into a feed, independent of
+// the syndication format it will be encoded in.
+func (s *Service) BuildFeed(ctx context.Context, input *FeedInput) (*Feed, error) {
+	var (
+		records []*Story
+		err     error
+	)
+
+	if input.AuthorProfileId == "" {
+		records, err = s.ListPublished(ctx, "", FeedSize, 0)
+	} else {
+		records, err = s.ListPublishedByAuthor(ctx, input.AuthorProfileId, "", FeedSize, 0)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	feed := &Feed{
+		UpdatedAt:   time.Unix(0, 0).UTC(),
+		Id:          input.Link,
+		Title:       input.Title,
+		Description: input.Description,
+		Link:        input.Link,
+		FeedUrl:     input.FeedUrl,
+		AuthorName:  input.AuthorName,
+		Entries:     make([]*FeedEntry, 0, len(records)),
+	}
+
+	for _, record := range records {
+		entry, err := s.feedEntry(ctx, record, input.Content)
+		if err != nil {
+			return nil, err
+		}
+
+		if entry.UpdatedAt.After(feed.UpdatedAt) {
+			feed.UpdatedAt = entry.UpdatedAt
+		}
+
+		feed.Entries = append(feed.Entries, entry)
+	}
+
+	return feed, nil
+}
+
+func (s *Service) feedEntry(ctx context.Context, record *Story, content FeedContent) (*FeedEntry, error) {
+	entry := &FeedEntry{
+		PublishedAt:     record.PublishedAt.Time,
+		UpdatedAt:       record.PublishedAt.Time,
+		Id:              "urn:acik:story:" + record.Id,
+		Title:           record.Title,
+		Link:            s.StoryUrl(record),
+		Summary:         record.Summary,
+		ContentHtml:     "",
+		StoryPictureUri: record.StoryPictureUri.String,
+	}
+
+	if record.UpdatedAt.Valid && record.UpdatedAt.Time.After(entry.UpdatedAt) {
+		entry.UpdatedAt = record.UpdatedAt.Time
+	}
+
+	if entry.Summary == "" {
+		entry.Summary = record.Description
+	}
+
+	if content == FeedContentFull {
+		rendered, err := s.render(ctx, record.Content)
+		if err != nil {
+			return nil, err
+		}
+
+		entry.ContentHtml = rendered.Html
+	}
+
+	return entry, nil
+}
@@ -0,0 +1,742 @@
+package stories
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/eser/acik.io/pkg/api/business/slugs"
+	"github.com/eser/acik.io/pkg/api/business/tags"
+)
+
+var (
+	ErrFailedToGetRecord    = errors.New("failed to get record")
+	ErrFailedToListRecords  = errors.New("failed to list records")
+	ErrFailedToCreateRecord = errors.New("failed to create record")
+	ErrFailedToUpdateRecord = errors.New("failed to update record")
+	ErrFailedToSchedule     = errors.New("failed to schedule publishing")
+	ErrRecordNotFound       = errors.New("record not found")
+	ErrInvalidInput         = errors.New("invalid input")
+	ErrNotAuthor            = errors.New("user is not a member of the author profile")
+	ErrInvalidTransition    = errors.New("invalid status transition")
+	ErrStatusChanged        = errors.New("story status has changed concurrently")
+	ErrVersionMismatch      = errors.New("story has changed since the given version")
+	ErrFailedToRender       = errors.New("failed to render content")
+)
+
+type Repository interface {
+	Transact(ctx context.Context, fn func(ctx context.Context) error) error
+	GetStoryById(ctx context.Context, id string) (*Story, error)
+	GetStoryBySlug(ctx context.Context, slug string) (*Story, error)
+	ListPublishedStories(ctx context.Context, arg ListPublishedStoriesParams) ([]*Story, error)
+	ListPublishedStoriesByAuthorProfileId(ctx context.Context, arg ListPublishedStoriesByAuthorProfileIdParams) ([]*Story, error) //nolint:lll
+	GetPublishedStoriesFeedStamp(ctx context.Context) (*GetPublishedStoriesFeedStampRow, error)
+	GetPublishedStoriesFeedStampByAuthorProfileId(ctx context.Context, authorProfileId sql.NullString) (*GetPublishedStoriesFeedStampByAuthorProfileIdRow, error) //nolint:lll
+	ListFeaturedStories(ctx context.Context, arg ListFeaturedStoriesParams) ([]*Story, error)
+	ListDueScheduledStoryIds(ctx context.Context) ([]string, error)
+	CreateStory(ctx context.Context, arg CreateStoryParams) (*Story, error)
+	UpdateStory(ctx context.Context, arg UpdateStoryParams) (int64, error)
+	UpdateStoryStatus(ctx context.Context, arg UpdateStoryStatusParams) (int64, error)
+	SetStoryFeatured(ctx context.Context, arg SetStoryFeaturedParams) (int64, error)
+	SetStoryPictureUri(ctx context.Context, arg SetStoryPictureUriParams) (int64, error)
+}
+
+type MembershipChecker interface {
+	IsMember(ctx context.Context, profileId string, userId string) (bool, error)
+}
+
+type JobQueue interface {
+	Enqueue(ctx context.Context, queueName string, payload any) error
+}
+
+type Tagger interface {
+	SetTags(ctx context.Context, kind string, entityId string, names []string) ([]*tags.TagRef, error)
+	TagsOf(ctx context.Context, kind string, ids []string) (map[string][]*tags.TagRef, error)
+}
+
+type SlugRenamer interface {
+	Rename(ctx context.Context, kind string, id string, slug string) (string, error)
+}
+
+// EventRecorder records domain events. It is called within the transaction
+// of the state change the event describes.
+type EventRecorder interface {
+	Record(ctx context.Context, aggregateType string, aggregateId string, eventType string, payload any) error
+}
+
+type Service struct {
+	config   *Config
+	repo     Repository
+	members  MembershipChecker
+	jobs     JobQueue
+	renderer ContentRenderer
+	events   EventRecorder
+	tagger   Tagger
+	slugs    SlugRenamer
+
+	idGenerator RecordIDGenerator
+}
+
+func NewService(
+	config *Config,
+	repo Repository,
+	members MembershipChecker,
+	jobs JobQueue,
+	renderer ContentRenderer,
+	events EventRecorder,
+	tagger Tagger,
+	slugs SlugRenamer,
+) *Service {
+	return &Service{
+		config:      config,
+		repo:        repo,
+		members:     members,
+		jobs:        jobs,
+		renderer:    renderer,
+		events:      events,
+		tagger:      tagger,
+		slugs:       slugs,
+		idGenerator: DefaultIDGenerator,
+	}
+}
+
+func (s *Service) GetById(ctx context.Context, id string) (*Story, error) {
+	record, err := s.repo.GetStoryById(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("%w(id: %s): %w", ErrFailedToGetRecord, id, err)
+	}
+
+	if record == nil {
+		return nil, fmt.Errorf("%w(id: %s)", ErrRecordNotFound, id)
+	}
+
+	return record, nil
+}
+
+func (s *Service) GetBySlug(ctx context.Context, slug string) (*Story, error) {
+	record, err := s.repo.GetStoryBySlug(ctx, slug)
+	if err != nil {
+		return nil, fmt.Errorf("%w(slug: %s): %w", ErrFailedToGetRecord, slug, err)
+	}
+
+	if record == nil {
+		return nil, fmt.Errorf("%w(slug: %s)", ErrRecordNotFound, slug)
+	}
+
+	return record, nil
+}
+
+// GetVisibleBySlug returns a story if it is published, or if the given user
+// is one of its authors. userId may be empty for anonymous readers.
+func (s *Service) GetVisibleBySlug(ctx context.Context, slug string, userId string) (*Story, error) {
+	record, err := s.GetBySlug(ctx, slug)
+	if err != nil {
+		return nil, err
+	}
+
+	if record.IsPublishedAt(time.Now()) {
+		return record, nil
+	}
+
+	if userId != "" {
+		err = s.ensureAuthor(ctx, record.AuthorProfileId.String, userId)
+		if err == nil {
+			return record, nil
+		}
+
+		if !errors.Is(err, ErrNotAuthor) {
+			return nil, err
+		}
+	}
+
+	return nil, fmt.Errorf("%w(slug: %s)", ErrRecordNotFound, slug)
+}
+
+// GetEditableBySlug returns a story if the given user is one of its authors.
+func (s *Service) GetEditableBySlug(ctx context.Context, slug string, userId string) (*Story, error) {
+	record, err := s.GetBySlug(ctx, slug)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.ensureAuthor(ctx, record.AuthorProfileId.String, userId)
+	if err != nil {
+		return nil, err
+	}
+
+	return record, nil
+}
+
+// ListPublished lists the published stories, newest first. tagId narrows them
+// down to the stories with a tag when it isn't empty.
+func (s *Service) ListPublished(ctx context.Context, tagId string, limit int32, offset int32) ([]*Story, error) {
+	records, err := s.repo.ListPublishedStories(ctx, ListPublishedStoriesParams{
+		TagId:       tagFilter(tagId),
+		LimitCount:  limit,
+		OffsetCount: offset,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrFailedToListRecords, err)
+	}
+
+	return records, nil
+}
+
+func (s *Service) ListPublishedByAuthor(
+	ctx context.Context,
+	authorProfileId string,
+	tagId string,
+	limit int32,
+	offset int32,
+) ([]*Story, error) {
+	records, err := s.repo.ListPublishedStoriesByAuthorProfileId(ctx, ListPublishedStoriesByAuthorProfileIdParams{
+		AuthorProfileId: sql.NullString{String: authorProfileId, Valid: true},
+		TagId:           tagFilter(tagId),
+		LimitCount:      limit,
+		OffsetCount:     offset,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%w(author: %s): %w", ErrFailedToListRecords, authorProfileId, err)
+	}
+
+	return records, nil
+}
+
+// ListFeatured returns the published stories picked by moderators, in their
+// curated order.
+func (s *Service) ListFeatured(ctx context.Context, tagId string, limit int32) ([]*Story, error) {
+	records, err := s.repo.ListFeaturedStories(ctx, ListFeaturedStoriesParams{
+		TagId:      tagFilter(tagId),
+		LimitCount: limit,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrFailedToListRecords, err)
+	}
+
+	return records, nil
+}
+
+// SetFeatured features or unfeatures a story. Featured stories are listed
+// by ascending order. Authorization is left to the caller, as curation is a
+// moderator task rather than an authoring one.
+func (s *Service) SetFeatured(ctx context.Context, slug string, input *FeatureInput) (*Story, error) {
+	record, err := s.GetBySlug(ctx, slug)
+	if err != nil {
+		return nil, err
+	}
+
+	if input.IsFeatured && record.Status != StatusPublished {
+		return nil, fmt.Errorf("%w: only published stories can be featured", ErrInvalidInput)
+	}
+
+	order := input.Order
+	if !input.IsFeatured {
+		order = 0
+	}
+
+	err = s.repo.Transact(ctx, func(ctx context.Context) error {
+		_, err := s.repo.SetStoryFeatured(ctx, SetStoryFeaturedParams{
+			IsFeatured:    sql.NullBool{Bool: input.IsFeatured, Valid: true},
+			FeaturedOrder: order,
+			Id:            record.Id,
+		})
+		if err != nil {
+			return fmt.Errorf("%w(id: %s): %w", ErrFailedToUpdateRecord, record.Id, err)
+		}
+
+		return s.recordChange(ctx, EventStoryFeaturedChanged, record)
+	})
+	if err != nil {
+		return nil, err //nolint:wrapcheck
+	}
+
+	return s.GetById(ctx, record.Id)
+}
+
+// SetPicture replaces the picture of a story. Authorization is left to the
+// caller, see GetEditableBySlug.
+func (s *Service) SetPicture(ctx context.Context, record *Story, uri string) (*Story, error) {
+	err := s.repo.Transact(ctx, func(ctx context.Context) error {
+		_, err := s.repo.SetStoryPictureUri(ctx, SetStoryPictureUriParams{
+			StoryPictureUri: sql.NullString{String: uri, Valid: uri != ""},
+			Id:              record.Id,
+		})
+		if err != nil {
+			return fmt.Errorf("%w(id: %s): %w", ErrFailedToUpdateRecord, record.Id, err)
+		}
+
+		return s.recordChange(ctx, EventStoryUpdated, record)
+	})
+	if err != nil {
+		return nil, err //nolint:wrapcheck
+	}
+
+	return s.GetById(ctx, record.Id)
+}
+
+func (s *Service) Create(ctx context.Context, userId string, input *CreateStoryInput) (*Story, error) {
+	if input.AuthorProfileId == "" || input.Slug == "" || input.Title == "" {
+		return nil, fmt.Errorf("%w: authorProfileId, slug and title are required", ErrInvalidInput)
+	}
+
+	err := slugs.Validate(input.Slug)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidInput, err)
+	}
+
+	err = s.ensureAuthor(ctx, input.AuthorProfileId, userId)
+	if err != nil {
+		return nil, err
+	}
+
+	kind := input.Kind
+	if kind == "" {
+		kind = KindArticle
+	}
+
+	summary, err := s.summarize(ctx, input.Summary, input.Content)
+	if err != nil {
+		return nil, err
+	}
+
+	var record *Story
+
+	err = s.repo.Transact(ctx, func(ctx context.Context) error {
+		record, err = s.repo.CreateStory(ctx, CreateStoryParams{
+			Id:              string(s.idGenerator()),
+			Kind:            kind,
+			Status:          StatusDraft,
+			Slug:            input.Slug,
+			StoryPictureUri: sql.NullString{String: input.StoryPictureUri, Valid: input.StoryPictureUri != ""},
+			Title:           input.Title,
+			Description:     input.Description,
+			AuthorProfileId: sql.NullString{String: input.AuthorProfileId, Valid: true},
+			Content:         input.Content,
+			Summary:         summary,
+		})
+		if err != nil {
+			return fmt.Errorf("%w: %w", ErrFailedToCreateRecord, err)
+		}
+
+		err = s.setTags(ctx, record.Id, input.Tags)
+		if err != nil {
+			return err
+		}
+
+		return s.recordEvent(ctx, EventStoryCreated, nil, record, "")
+	})
+	if err != nil {
+		return nil, err //nolint:wrapcheck
+	}
+
+	return record, nil
+}
+
+func (s *Service) Update(ctx context.Context, userId string, slug string, input *UpdateStoryInput) (*Story, error) {
+	if input.Title == "" {
+		return nil, fmt.Errorf("%w: title is required", ErrInvalidInput)
+	}
+
+	record, err := s.GetBySlug(ctx, slug)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.ensureAuthor(ctx, record.AuthorProfileId.String, userId)
+	if err != nil {
+		return nil, err
+	}
+
+	expectedVersion, err := expectedVersionOf(record, input.IfMatch)
+	if err != nil {
+		return nil, err
+	}
+
+	summary, err := s.summarize(ctx, input.Summary, input.Content)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.repo.Transact(ctx, func(ctx context.Context) error {
+		affected, err := s.repo.UpdateStory(ctx, UpdateStoryParams{
+			StoryPictureUri: sql.NullString{String: input.StoryPictureUri, Valid: input.StoryPictureUri != ""},
+			Title:           input.Title,
+			Description:     input.Description,
+			Content:         input.Content,
+			Summary:         summary,
+			Id:              record.Id,
+			ExpectedVersion: expectedVersion,
+		})
+		if err != nil {
+			return fmt.Errorf("%w(id: %s): %w", ErrFailedToUpdateRecord, record.Id, err)
+		}
+
+		// changed between reading the story and updating it.
+		if affected == 0 && expectedVersion.Valid {
+			return fmt.Errorf("%w(id: %s)", ErrVersionMismatch, record.Id)
+		}
+
+		if input.Tags != nil {
+			err = s.setTags(ctx, record.Id, input.Tags)
+			if err != nil {
+				return err
+			}
+		}
+
+		return s.recordChange(ctx, EventStoryUpdated, record)
+	})
+	if err != nil {
+		return nil, err //nolint:wrapcheck
+	}
+
+	return s.GetById(ctx, record.Id)
+}
+
+// expectedVersionOf checks an If-Match precondition against the story, and
+// returns the version the update must still find to go through. "*" only
+// asks for the story to exist.
+func expectedVersionOf(record *Story, ifMatch string) (sql.NullTime, error) {
+	if ifMatch == "" {
+		return sql.NullTime{}, nil //nolint:exhaustruct
+	}
+
+	if strings.TrimSpace(ifMatch) == "*" {
+		return sql.NullTime{}, nil //nolint:exhaustruct
+	}
+
+	etag := record.ETag()
+
+	for candidate := range strings.SplitSeq(ifMatch, ",") {
+		// If-Match uses the strong comparison, weak tags never match.
+		if strings.TrimSpace(candidate) == etag {
+			return sql.NullTime{Time: record.Version(), Valid: true}, nil
+		}
+	}
+
+	return sql.NullTime{}, fmt.Errorf("%w(id: %s)", ErrVersionMismatch, record.Id) //nolint:exhaustruct
+}
+
+// Rename gives a story a new slug. Links to the previous slug keep working,
+// see slugs.Service.
+func (s *Service) Rename(ctx context.Context, userId string, slug string, input *slugs.RenameInput) (*Story, error) {
+	record, err := s.GetBySlug(ctx, slug)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.ensureAuthor(ctx, record.AuthorProfileId.String, userId)
+	if err != nil {
+		return nil, err
+	}
+
+	if input.Slug == record.Slug {
+		return record, nil
+	}
+
+	err = s.repo.Transact(ctx, func(ctx context.Context) error {
+		_, err := s.slugs.Rename(ctx, slugs.KindStory, record.Id, input.Slug)
+		if err != nil {
+			return err //nolint:wrapcheck
+		}
+
+		return s.recordChange(ctx, EventStoryUpdated, record)
+	})
+	if err != nil {
+		return nil, err //nolint:wrapcheck
+	}
+
+	return s.GetById(ctx, record.Id)
+}
+
+// Transition moves a story through its publishing workflow. Publishing with a
+// publish time in the future schedules the story and enqueues a job that
+// publishes it once the time comes.
+func (s *Service) Transition(ctx context.Context, userId string, slug string, input *TransitionInput) (*Story, error) {
+	record, err := s.GetBySlug(ctx, slug)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.ensureAuthor(ctx, record.AuthorProfileId.String, userId)
+	if err != nil {
+		return nil, err
+	}
+
+	if !CanTransition(record.Status, input.Status) {
+		return nil, fmt.Errorf("%w(from: %s, to: %s)", ErrInvalidTransition, record.Status, input.Status)
+	}
+
+	now := time.Now()
+	newStatus := input.Status
+	publishedAt := record.PublishedAt
+
+	switch newStatus {
+	case StatusPublished:
+		publishAt := now
+		if input.PublishAt != nil && input.PublishAt.After(now) {
+			publishAt = *input.PublishAt
+			newStatus = StatusScheduled
+		}
+
+		publishedAt = sql.NullTime{Time: publishAt, Valid: true}
+	case StatusDraft, StatusInReview:
+		publishedAt = sql.NullTime{} //nolint:exhaustruct
+	}
+
+	err = s.updateStatus(ctx, record, newStatus, publishedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.GetById(ctx, record.Id)
+}
+
+// PublishScheduled handles a PublishScheduledJob. Stories that are no longer
+// scheduled, or were rescheduled to a later time since the job was enqueued,
+// are skipped, so redelivered jobs are harmless; PublishDue enqueues them
+// again once they are due.
+func (s *Service) PublishScheduled(ctx context.Context, job *PublishScheduledJob) error {
+	record, err := s.GetById(ctx, job.StoryId)
+	if err != nil {
+		if errors.Is(err, ErrRecordNotFound) {
+			return nil
+		}
+
+		return err
+	}
+
+	if record.Status != StatusScheduled ||
+		(record.PublishedAt.Valid && record.PublishedAt.Time.After(time.Now())) {
+		return nil
+	}
+
+	err = s.updateStatus(ctx, record, StatusPublished, record.PublishedAt)
+	if err != nil && !errors.Is(err, ErrStatusChanged) {
+		return err
+	}
+
+	return nil
+}
+
+// PublishDue enqueues a PublishScheduledJob for every scheduled story whose
+// publish time has passed. It is run periodically, so stories are published
+// within a run of their publish time. A story that can't be enqueued doesn't
+// hold back the others; the failures are returned together, along with the
+// number of stories enqueued.
+func (s *Service) PublishDue(ctx context.Context) (int, error) {
+	ids, err := s.repo.ListDueScheduledStoryIds(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("%w: %w", ErrFailedToListRecords, err)
+	}
+
+	enqueued := 0
+	errs := make([]error, 0)
+
+	for _, id := range ids {
+		err := s.jobs.Enqueue(ctx, QueuePublishScheduled, &PublishScheduledJob{StoryId: id})
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%w(id: %s): %w", ErrFailedToSchedule, id, err))
+
+			continue
+		}
+
+		enqueued++
+	}
+
+	return enqueued, errors.Join(errs...)
+}
+
+// Present renders the story for readers in the requested content format,
+// along with its tags, table of contents and reading time.
+func (s *Service) Present(ctx context.Context, record *Story, format ContentFormat) (*StoryView, error) {
+	views, err := s.PresentAll(ctx, []*Story{record}, format)
+	if err != nil {
+		return nil, err
+	}
+
+	return views[0], nil
+}
+
+func (s *Service) PresentAll(ctx context.Context, records []*Story, format ContentFormat) ([]*StoryView, error) {
+	ids := make([]string, len(records))
+	for i, record := range records {
+		ids[i] = record.Id
+	}
+
+	tagsByStory, err := s.tagger.TagsOf(ctx, tags.KindStory, ids)
+	if err != nil {
+		return nil, err //nolint:wrapcheck
+	}
+
+	views := make([]*StoryView, len(records))
+
+	for i, record := range records {
+		view, err := s.present(ctx, record, format)
+		if err != nil {
+			return nil, err
+		}
+
+		view.Tags = tagsByStory[record.Id]
+		views[i] = view
+	}
+
+	return views, nil
+}
+
+func (s *Service) present(ctx context.Context, record *Story, format ContentFormat) (*StoryView, error) {
+	rendered, err := s.render(ctx, record.Content)
+	if err != nil {
+		return nil, err
+	}
+
+	view := &StoryView{
+		Story:              record,
+		Content:            nil,
+		ContentHtml:        nil,
+		TableOfContents:    rendered.TableOfContents,
+		ReadingTimeMinutes: EstimateReadingTime(rendered.PlainText),
+		Tags:               nil,
+	}
+
+	if format == ContentFormatMarkdown || format == ContentFormatBoth {
+		view.Content = &record.Content
+	}
+
+	if format == ContentFormatHtml || format == ContentFormatBoth {
+		view.ContentHtml = &rendered.Html
+	}
+
+	return view, nil
+}
+
+func (s *Service) setTags(ctx context.Context, storyId string, names []string) error {
+	_, err := s.tagger.SetTags(ctx, tags.KindStory, storyId, names)
+	if errors.Is(err, tags.ErrInvalidInput) {
+		return fmt.Errorf("%w: %w", ErrInvalidInput, err)
+	}
+
+	return err //nolint:wrapcheck
+}
+
+func (s *Service) render(ctx context.Context, content string) (*RenderedContent, error) {
+	rendered, err := s.renderer.Render(ctx, content)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrFailedToRender, err)
+	}
+
+	return rendered, nil
+}
+
+// summarize keeps the given summary, or derives one from the content when it
+// is left empty.
+func (s *Service) summarize(ctx context.Context, summary string, content string) (string, error) {
+	if strings.TrimSpace(summary) != "" {
+		return summary, nil
+	}
+
+	rendered, err := s.render(ctx, content)
+	if err != nil {
+		return "", err
+	}
+
+	return GenerateSummary(rendered.PlainText, SummaryMaxLength), nil
+}
+
+// updateStatus changes the status of the story if it is still the one the
+// caller has seen, and records the change along with it.
+func (s *Service) updateStatus(ctx context.Context, record *Story, newStatus string, publishedAt sql.NullTime) error {
+	return s.repo.Transact(ctx, func(ctx context.Context) error { //nolint:wrapcheck
+		affected, err := s.repo.UpdateStoryStatus(ctx, UpdateStoryStatusParams{
+			NewStatus:     newStatus,
+			PublishedAt:   publishedAt,
+			Id:            record.Id,
+			CurrentStatus: record.Status,
+		})
+		if err != nil {
+			return fmt.Errorf("%w(id: %s): %w", ErrFailedToUpdateRecord, record.Id, err)
+		}
+
+		if affected == 0 {
+			return fmt.Errorf("%w(id: %s, from: %s)", ErrStatusChanged, record.Id, record.Status)
+		}
+
+		changed, err := s.GetById(ctx, record.Id)
+		if err != nil {
+			return err
+		}
+
+		err = s.recordEvent(ctx, EventStoryStatusChanged, record, changed, record.Status)
+		if err != nil {
+			return err
+		}
+
+		if newStatus == StatusPublished {
+			return s.recordEvent(ctx, EventStoryPublished, record, changed, record.Status)
+		}
+
+		return nil
+	})
+}
+
+// recordChange records a change of the story, reading the state it left
+// within the transaction making it.
+func (s *Service) recordChange(ctx context.Context, eventType string, before *Story) error {
+	after, err := s.GetById(ctx, before.Id)
+	if err != nil {
+		return err
+	}
+
+	return s.recordEvent(ctx, eventType, before, after, "")
+}
+
+// recordEvent records an event of the story changing from before to after.
+// before is nil for new stories.
+func (s *Service) recordEvent(
+	ctx context.Context,
+	eventType string,
+	before *Story,
+	after *Story,
+	previousStatus string,
+) error {
+	payload := &StoryEvent{
+		PublishedAt:     nil,
+		StoryId:         after.Id,
+		Slug:            after.Slug,
+		AuthorProfileId: after.AuthorProfileId.String,
+		Status:          after.Status,
+		PreviousStatus:  previousStatus,
+		IsFeatured:      after.IsFeatured.Bool,
+		before:          before,
+		after:           after,
+	}
+
+	if after.PublishedAt.Valid {
+		payload.PublishedAt = &after.PublishedAt.Time
+	}
+
+	return s.events.Record(ctx, AggregateStory, after.Id, eventType, payload) //nolint:wrapcheck
+}
+
+func (s *Service) ensureAuthor(ctx context.Context, authorProfileId string, userId string) error {
+	if authorProfileId == "" {
+		return fmt.Errorf("%w(user: %s)", ErrNotAuthor, userId)
+	}
+
+	isMember, err := s.members.IsMember(ctx, authorProfileId, userId)
+	if err != nil {
+		return err //nolint:wrapcheck
+	}
+
+	if !isMember {
+		return fmt.Errorf("%w(profile: %s, user: %s)", ErrNotAuthor, authorProfileId, userId)
+	}
+
+	return nil
+}
+
+func tagFilter(tagId string) sql.NullString {
+	return sql.NullString{String: tagId, Valid: tagId != ""}
+}
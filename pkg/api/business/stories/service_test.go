@@ -0,0 +1,226 @@
+package stories_test
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/eser/acik.io/pkg/api/business/outbox/outboxtest"
+	"github.com/eser/acik.io/pkg/api/business/stories"
+)
+
+const (
+	authorProfileId = "01HPROFILE0000000000000000"
+	authorId        = "01HAUTHOR00000000000000000"
+)
+
+// repository holds a single story, following stories.sql for updates.
+type repository struct {
+	stories.Repository
+
+	story *stories.Story
+	// beforeUpdate runs as an update reaches the database.
+	beforeUpdate func()
+	mu           sync.Mutex
+}
+
+func (r *repository) Transact(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
+
+func (r *repository) GetStoryById(_ context.Context, id string) (*stories.Story, error) {
+	return r.get(func(story *stories.Story) bool { return story.Id == id })
+}
+
+func (r *repository) GetStoryBySlug(_ context.Context, slug string) (*stories.Story, error) {
+	return r.get(func(story *stories.Story) bool { return story.Slug == slug })
+}
+
+func (r *repository) get(match func(story *stories.Story) bool) (*stories.Story, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if !match(r.story) {
+		return nil, nil //nolint:nilnil
+	}
+
+	clone := *r.story
+
+	return &clone, nil
+}
+
+func (r *repository) UpdateStory(_ context.Context, arg stories.UpdateStoryParams) (int64, error) {
+	if r.beforeUpdate != nil {
+		r.beforeUpdate()
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.story.Id != arg.Id || (arg.ExpectedVersion.Valid && !r.story.Version().Equal(arg.ExpectedVersion.Time)) {
+		return 0, nil
+	}
+
+	r.story.Title = arg.Title
+	r.story.Content = arg.Content
+	r.story.Summary = arg.Summary
+	r.story.UpdatedAt = sql.NullTime{Time: r.story.Version().Add(time.Second), Valid: true}
+
+	return 1, nil
+}
+
+// touch changes the story behind the back of the service.
+func (r *repository) touch() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.story.UpdatedAt = sql.NullTime{Time: r.story.Version().Add(time.Minute), Valid: true}
+}
+
+type authors struct{}
+
+func (authors) IsMember(_ context.Context, profileId string, userId string) (bool, error) {
+	return profileId == authorProfileId && userId == authorId, nil
+}
+
+func newFixture() (*stories.Service, *repository) {
+	repo := &repository{ //nolint:exhaustruct
+		story: &stories.Story{ //nolint:exhaustruct
+			Id:              "01HSTORY000000000000000000",
+			Status:          stories.StatusDraft,
+			Slug:            "hello",
+			Title:           "Hello",
+			AuthorProfileId: sql.NullString{String: authorProfileId, Valid: true},
+			Content:         "Hello, world.",
+			Summary:         "Hello.",
+			CreatedAt:       time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC),
+		},
+	}
+
+	service := stories.NewService(&stories.Config{}, repo, authors{}, nil, nil, outboxtest.Discard{}, nil, nil) //nolint:exhaustruct
+
+	return service, repo
+}
+
+func update(service *stories.Service, ifMatch string) (*stories.Story, error) {
+	return service.Update(context.Background(), authorId, "hello", &stories.UpdateStoryInput{
+		StoryPictureUri: "",
+		Title:           "Hello again",
+		Description:     "",
+		Summary:         "Again.",
+		Content:         "Hello again, world.",
+		IfMatch:         ifMatch,
+		Tags:            nil,
+	})
+}
+
+func TestUpdateIfMatch(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		ifMatch func(current string) string
+		name    string
+		wantErr bool
+	}{
+		{name: "no precondition", ifMatch: func(string) string { return "" }, wantErr: false},
+		{name: "any version", ifMatch: func(string) string { return "*" }, wantErr: false},
+		{name: "current version", ifMatch: func(current string) string { return `"old", ` + current }, wantErr: false},
+		{name: "weak tag", ifMatch: func(current string) string { return "W/" + current }, wantErr: true},
+		{name: "stale version", ifMatch: func(string) string { return `"01HSTORY000000000000000000-0"` }, wantErr: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			service, repo := newFixture()
+			current := repo.story.ETag()
+
+			record, err := update(service, test.ifMatch(current))
+
+			if test.wantErr {
+				if !errors.Is(err, stories.ErrVersionMismatch) {
+					t.Fatalf("got %v, want %v", err, stories.ErrVersionMismatch)
+				}
+
+				if repo.story.Title != "Hello" {
+					t.Errorf("got title %q, want the story untouched", repo.story.Title)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("updating: %v", err)
+			}
+
+			if record.Title != "Hello again" || record.ETag() == current {
+				t.Errorf("got %q with ETag %s, want the updated story with a new ETag", record.Title, record.ETag())
+			}
+		})
+	}
+}
+
+func TestUpdateIfMatchLosesRace(t *testing.T) {
+	t.Parallel()
+
+	service, repo := newFixture()
+	current := repo.story.ETag()
+
+	// the check passes on the story as read, but another update lands
+	// before the write.
+	repo.beforeUpdate = repo.touch
+
+	_, err := update(service, current)
+	if !errors.Is(err, stories.ErrVersionMismatch) {
+		t.Errorf("got %v, want %v", err, stories.ErrVersionMismatch)
+	}
+}
+
+// dueRepository lists a fixed set of due stories.
+type dueRepository struct {
+	stories.Repository
+
+	ids []string
+}
+
+func (r *dueRepository) ListDueScheduledStoryIds(context.Context) ([]string, error) {
+	return r.ids, nil
+}
+
+// flakyQueue fails to enqueue the publish jobs of the given stories.
+type flakyQueue struct {
+	failing  map[string]bool
+	enqueued []string
+}
+
+func (q *flakyQueue) Enqueue(_ context.Context, _ string, payload any) error {
+	job, _ := payload.(*stories.PublishScheduledJob)
+	if q.failing[job.StoryId] {
+		return errors.New("broker unavailable")
+	}
+
+	q.enqueued = append(q.enqueued, job.StoryId)
+
+	return nil
+}
+
+func TestPublishDueContinuesPastFailures(t *testing.T) {
+	t.Parallel()
+
+	repo := &dueRepository{ids: []string{"first", "second", "third"}} //nolint:exhaustruct
+	jobs := &flakyQueue{failing: map[string]bool{"second": true}, enqueued: nil}
+	service := stories.NewService(&stories.Config{}, repo, authors{}, jobs, nil, outboxtest.Discard{}, nil, nil) //nolint:exhaustruct
+
+	enqueued, err := service.PublishDue(context.Background())
+	if !errors.Is(err, stories.ErrFailedToSchedule) {
+		t.Errorf("got error %v, want %v", err, stories.ErrFailedToSchedule)
+	}
+
+	if enqueued != 2 || len(jobs.enqueued) != 2 || jobs.enqueued[1] != "third" {
+		t.Errorf("got %d enqueued (%v), want first and third", enqueued, jobs.enqueued)
+	}
+}
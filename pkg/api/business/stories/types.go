@@ -0,0 +1,149 @@
+package stories
+
+import (
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/oklog/ulid/v2"
+)
+
+const (
+	KindArticle = "article"
+
+	StatusDraft     = "draft"
+	StatusInReview  = "in_review"
+	StatusScheduled = "scheduled"
+	StatusPublished = "published"
+	StatusArchived  = "archived"
+	// StatusHidden is set by moderators. Authors can't move stories out of it.
+	StatusHidden = "hidden"
+
+	QueuePublishScheduled = "stories.publish-scheduled"
+
+	AggregateStory = "story"
+
+	EventStoryCreated         = "story.created"
+	EventStoryUpdated         = "story.updated"
+	EventStoryStatusChanged   = "story.status-changed"
+	EventStoryPublished       = "story.published"
+	EventStoryFeaturedChanged = "story.featured-changed"
+)
+
+// transitions lists the statuses a story can move to from its current one.
+// scheduled is an intermediate state of published, entered when the
+// requested publish time lies in the future.
+var transitions = map[string][]string{ //nolint:gochecknoglobals
+	StatusDraft:     {StatusInReview},
+	StatusInReview:  {StatusDraft, StatusPublished},
+	StatusScheduled: {StatusInReview, StatusPublished},
+	StatusPublished: {StatusArchived},
+	StatusArchived:  {StatusDraft},
+}
+
+type RecordID string
+
+type RecordIDGenerator func() RecordID
+
+func DefaultIDGenerator() RecordID {
+	return RecordID(ulid.Make().String())
+}
+
+func CanTransition(from string, to string) bool {
+	return slices.Contains(transitions[from], to)
+}
+
+func (s *Story) IsPublishedAt(now time.Time) bool {
+	return s.Status == StatusPublished && s.PublishedAt.Valid && !s.PublishedAt.Time.After(now)
+}
+
+// Version is the time of the last change to the story.
+func (s *Story) Version() time.Time {
+	if s.UpdatedAt.Valid {
+		return s.UpdatedAt.Time
+	}
+
+	return s.CreatedAt
+}
+
+// ETag returns a strong entity tag of the story, changing with every update.
+// Featuring leaves updated_at alone, so the featured state is part of the tag.
+func (s *Story) ETag() string {
+	tag := s.Id + "-" + strconv.FormatInt(s.Version().UnixNano(), 36)
+
+	if s.IsFeatured.Bool {
+		tag += "-f" + strconv.FormatInt(int64(s.FeaturedOrder), 36)
+	}
+
+	return `"` + tag + `"`
+}
+
+type CreateStoryInput struct {
+	AuthorProfileId string   `json:"authorProfileId"`
+	Kind            string   `json:"kind"`
+	Slug            string   `json:"slug"`
+	StoryPictureUri string   `json:"storyPictureUri"`
+	Title           string   `json:"title"`
+	Description     string   `json:"description"`
+	Summary         string   `json:"summary"`
+	Content         string   `json:"content"`
+	Tags            []string `json:"tags"`
+}
+
+// UpdateStoryInput leaves the tags of the story as they are when Tags is
+// omitted. IfMatch, taken from the If-Match header, makes the update
+// conditional on the story still having one of the entity tags it lists.
+type UpdateStoryInput struct {
+	StoryPictureUri string   `json:"storyPictureUri"`
+	Title           string   `json:"title"`
+	Description     string   `json:"description"`
+	Summary         string   `json:"summary"`
+	Content         string   `json:"content"`
+	IfMatch         string   `json:"-"`
+	Tags            []string `json:"tags"`
+}
+
+type TransitionInput struct {
+	PublishAt *time.Time `json:"publishAt"`
+	Status    string     `json:"status"`
+}
+
+// PublishScheduledJob is enqueued by PublishDue for a scheduled story whose
+// publish time has passed.
+type PublishScheduledJob struct {
+	StoryId string `json:"storyId"`
+}
+
+type FeatureInput struct {
+	IsFeatured bool  `json:"isFeatured"`
+	Order      int32 `json:"order"`
+}
+
+// StoryEvent is the payload of every story domain event.
+type StoryEvent struct {
+	PublishedAt     *time.Time `json:"publishedAt,omitempty"`
+	StoryId         string     `json:"storyId"`
+	Slug            string     `json:"slug"`
+	AuthorProfileId string     `json:"authorProfileId"`
+	Status          string     `json:"status"`
+	PreviousStatus  string     `json:"previousStatus,omitempty"`
+	IsFeatured      bool       `json:"isFeatured"`
+
+	before *Story
+	after  *Story
+}
+
+// AuditProfileId attributes the changes of a story to its author profile.
+func (e *StoryEvent) AuditProfileId() string {
+	return e.AuthorProfileId
+}
+
+// AuditStates logs the full story before and after the change, so edits of
+// its title, summary or content are visible in the audit log.
+func (e *StoryEvent) AuditStates() (any, any) {
+	if e.before == nil {
+		return nil, e.after
+	}
+
+	return e.before, e.after
+}
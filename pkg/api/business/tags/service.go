@@ -0,0 +1,465 @@
+package tags
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+const (
+	MaxTags       = 10
+	MaxNameLength = 32
+)
+
+var (
+	ErrFailedToGetRecord    = errors.New("failed to get record")
+	ErrFailedToListRecords  = errors.New("failed to list records")
+	ErrFailedToCreateRecord = errors.New("failed to create record")
+	ErrFailedToUpdateRecord = errors.New("failed to update record")
+	ErrRecordNotFound       = errors.New("record not found")
+	ErrInvalidInput         = errors.New("invalid input")
+	ErrUnknownKind          = errors.New("unknown tag kind")
+)
+
+type Repository interface {
+	Transact(ctx context.Context, fn func(ctx context.Context) error) error
+	GetTagById(ctx context.Context, id string) (*Tag, error)
+	GetTagBySlug(ctx context.Context, slug string) (*Tag, error)
+	ListTagsBySlugs(ctx context.Context, slugs []string) ([]*Tag, error)
+	ListTagSynonyms(ctx context.Context, canonicalTagId sql.NullString) ([]*Tag, error)
+	UpsertTag(ctx context.Context, arg UpsertTagParams) (*Tag, error)
+	SetTagCanonical(ctx context.Context, arg SetTagCanonicalParams) (int64, error)
+	RepointTagSynonyms(ctx context.Context, arg RepointTagSynonymsParams) (int64, error)
+	MoveStoryTags(ctx context.Context, arg MoveStoryTagsParams) (int64, error)
+	MoveEventTags(ctx context.Context, arg MoveEventTagsParams) (int64, error)
+	MoveProjectTags(ctx context.Context, arg MoveProjectTagsParams) (int64, error)
+	ReplaceStoryTags(ctx context.Context, arg ReplaceStoryTagsParams) error
+	ReplaceEventTags(ctx context.Context, arg ReplaceEventTagsParams) error
+	ReplaceProjectTags(ctx context.Context, arg ReplaceProjectTagsParams) error
+	ListStoryTags(ctx context.Context, storyIds []string) ([]*ListStoryTagsRow, error)
+	ListEventTags(ctx context.Context, eventIds []string) ([]*ListEventTagsRow, error)
+	ListProjectTags(ctx context.Context, projectIds []string) ([]*ListProjectTagsRow, error)
+}
+
+// EventRecorder records domain events. It is called within the transaction
+// of the state change the event describes.
+type EventRecorder interface {
+	Record(ctx context.Context, aggregateType string, aggregateId string, eventType string, payload any) error
+}
+
+type Service struct {
+	repo   Repository
+	events EventRecorder
+
+	idGenerator RecordIDGenerator
+}
+
+func NewService(repo Repository, events EventRecorder) *Service {
+	return &Service{
+		repo:        repo,
+		events:      events,
+		idGenerator: DefaultIDGenerator,
+	}
+}
+
+// Resolve returns the canonical tag a slug stands for, following synonyms.
+func (s *Service) Resolve(ctx context.Context, slug string) (*Tag, error) {
+	record, err := s.getBySlug(ctx, NormalizeSlug(slug))
+	if err != nil {
+		return nil, err
+	}
+
+	return s.canonicalOf(ctx, record)
+}
+
+// Get returns the canonical tag a slug stands for along with its synonyms.
+func (s *Service) Get(ctx context.Context, slug string) (*TagDetail, error) {
+	record, err := s.Resolve(ctx, slug)
+	if err != nil {
+		return nil, err
+	}
+
+	synonyms, err := s.repo.ListTagSynonyms(ctx, sql.NullString{String: record.Id, Valid: true})
+	if err != nil {
+		return nil, fmt.Errorf("%w(id: %s): %w", ErrFailedToListRecords, record.Id, err)
+	}
+
+	detail := &TagDetail{Tag: record, Synonyms: make([]*TagRef, 0, len(synonyms))}
+	for _, synonym := range synonyms {
+		detail.Synonyms = append(detail.Synonyms, &TagRef{Slug: synonym.Slug, Name: synonym.Name})
+	}
+
+	return detail, nil
+}
+
+// Ensure returns the canonical tags for a list of names, creating the ones
+// that don't exist yet. Names that normalize to the same slug, or to synonyms
+// of the same tag, collapse into one.
+func (s *Service) Ensure(ctx context.Context, names []string) ([]*Tag, error) {
+	slugs, namesBySlug, err := normalizeNames(names)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(slugs) == 0 {
+		return make([]*Tag, 0), nil
+	}
+
+	existing, err := s.repo.ListTagsBySlugs(ctx, slugs)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrFailedToListRecords, err)
+	}
+
+	bySlug := make(map[string]*Tag, len(existing))
+	for _, record := range existing {
+		bySlug[record.Slug] = record
+	}
+
+	result := make([]*Tag, 0, len(slugs))
+	seen := make(map[string]bool, len(slugs))
+
+	for _, slug := range slugs {
+		record, ok := bySlug[slug]
+		if !ok {
+			record, err = s.repo.UpsertTag(ctx, UpsertTagParams{
+				Id:   string(s.idGenerator()),
+				Slug: slug,
+				Name: namesBySlug[slug],
+			})
+			if err != nil {
+				return nil, fmt.Errorf("%w(slug: %s): %w", ErrFailedToCreateRecord, slug, err)
+			}
+		}
+
+		record, err = s.canonicalOf(ctx, record)
+		if err != nil {
+			return nil, err
+		}
+
+		if !seen[record.Id] {
+			seen[record.Id] = true
+
+			result = append(result, record)
+		}
+	}
+
+	return result, nil
+}
+
+// SetTags replaces the tags of a story, event or project with the given names.
+func (s *Service) SetTags(ctx context.Context, kind string, entityId string, names []string) ([]*TagRef, error) {
+	var refs []*TagRef
+
+	err := s.repo.Transact(ctx, func(ctx context.Context) error {
+		records, err := s.Ensure(ctx, names)
+		if err != nil {
+			return err
+		}
+
+		tagIds := make([]string, 0, len(records))
+		refs = make([]*TagRef, 0, len(records))
+
+		for _, record := range records {
+			tagIds = append(tagIds, record.Id)
+			refs = append(refs, &TagRef{Slug: record.Slug, Name: record.Name})
+		}
+
+		return s.replace(ctx, kind, entityId, tagIds)
+	})
+	if err != nil {
+		return nil, err //nolint:wrapcheck
+	}
+
+	return refs, nil
+}
+
+// TagsOf returns the tags of a batch of stories, events or projects keyed by
+// their ids. Every requested id is present in the result.
+func (s *Service) TagsOf(ctx context.Context, kind string, ids []string) (map[string][]*TagRef, error) {
+	result := make(map[string][]*TagRef, len(ids))
+	for _, id := range ids {
+		result[id] = make([]*TagRef, 0)
+	}
+
+	if len(ids) == 0 {
+		return result, nil
+	}
+
+	add := func(entityId string, slug string, name string) {
+		result[entityId] = append(result[entityId], &TagRef{Slug: slug, Name: name})
+	}
+
+	switch kind {
+	case KindStory:
+		rows, err := s.repo.ListStoryTags(ctx, ids)
+		if err != nil {
+			return nil, fmt.Errorf("%w(kind: %s): %w", ErrFailedToListRecords, kind, err)
+		}
+
+		for _, row := range rows {
+			add(row.EntityId, row.Slug, row.Name)
+		}
+	case KindEvent:
+		rows, err := s.repo.ListEventTags(ctx, ids)
+		if err != nil {
+			return nil, fmt.Errorf("%w(kind: %s): %w", ErrFailedToListRecords, kind, err)
+		}
+
+		for _, row := range rows {
+			add(row.EntityId, row.Slug, row.Name)
+		}
+	case KindProject:
+		rows, err := s.repo.ListProjectTags(ctx, ids)
+		if err != nil {
+			return nil, fmt.Errorf("%w(kind: %s): %w", ErrFailedToListRecords, kind, err)
+		}
+
+		for _, row := range rows {
+			add(row.EntityId, row.Slug, row.Name)
+		}
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrUnknownKind, kind)
+	}
+
+	return result, nil
+}
+
+// Merge folds a tag into another one: everything tagged with it gets the
+// other tag instead, and it and its synonyms become synonyms of the other.
+func (s *Service) Merge(ctx context.Context, slug string, intoSlug string) (*TagDetail, error) {
+	from, err := s.Resolve(ctx, slug)
+	if err != nil {
+		return nil, err
+	}
+
+	into, err := s.Resolve(ctx, intoSlug)
+	if err != nil {
+		return nil, err
+	}
+
+	if from.Id == into.Id {
+		return nil, fmt.Errorf("%w: a tag can't be merged into itself", ErrInvalidInput)
+	}
+
+	return s.changeSynonyms(ctx, EventTagMerged, into, func(ctx context.Context) error {
+		return s.merge(ctx, from, into)
+	})
+}
+
+// AddSynonym makes a name a synonym of a tag. If the name already is a tag of
+// its own, that tag is merged into this one.
+func (s *Service) AddSynonym(ctx context.Context, slug string, name string) (*TagDetail, error) {
+	canonical, err := s.Resolve(ctx, slug)
+	if err != nil {
+		return nil, err
+	}
+
+	synonymSlugs, namesBySlug, err := normalizeNames([]string{name})
+	if err != nil {
+		return nil, err
+	}
+
+	if len(synonymSlugs) == 0 {
+		return nil, fmt.Errorf("%w: name is required", ErrInvalidInput)
+	}
+
+	synonymSlug := synonymSlugs[0]
+
+	return s.changeSynonyms(ctx, EventTagSynonymAdded, canonical, func(ctx context.Context) error {
+		existing, err := s.repo.GetTagBySlug(ctx, synonymSlug)
+		if err != nil {
+			return fmt.Errorf("%w(slug: %s): %w", ErrFailedToGetRecord, synonymSlug, err)
+		}
+
+		if existing == nil {
+			existing, err = s.repo.UpsertTag(ctx, UpsertTagParams{
+				Id:   string(s.idGenerator()),
+				Slug: synonymSlug,
+				Name: namesBySlug[synonymSlug],
+			})
+			if err != nil {
+				return fmt.Errorf("%w(slug: %s): %w", ErrFailedToCreateRecord, synonymSlug, err)
+			}
+		}
+
+		existing, err = s.canonicalOf(ctx, existing)
+		if err != nil {
+			return err
+		}
+
+		if existing.Id == canonical.Id {
+			return nil
+		}
+
+		return s.merge(ctx, existing, canonical)
+	})
+}
+
+// changeSynonyms runs a change to the synonyms of a canonical tag in a
+// transaction, and records it with the tag as it was before and after.
+func (s *Service) changeSynonyms(
+	ctx context.Context,
+	eventType string,
+	canonical *Tag,
+	change func(ctx context.Context) error,
+) (*TagDetail, error) {
+	var after *TagDetail
+
+	err := s.repo.Transact(ctx, func(ctx context.Context) error {
+		before, err := s.Get(ctx, canonical.Slug)
+		if err != nil {
+			return err
+		}
+
+		err = change(ctx)
+		if err != nil {
+			return err
+		}
+
+		after, err = s.Get(ctx, canonical.Slug)
+		if err != nil {
+			return err
+		}
+
+		return s.events.Record(ctx, AggregateTag, canonical.Id, eventType, &TagEvent{ //nolint:wrapcheck
+			TagId:  canonical.Id,
+			Slug:   canonical.Slug,
+			before: before,
+			after:  after,
+		})
+	})
+	if err != nil {
+		return nil, err //nolint:wrapcheck
+	}
+
+	return after, nil
+}
+
+func (s *Service) merge(ctx context.Context, from *Tag, into *Tag) error {
+	_, err := s.repo.MoveStoryTags(ctx, MoveStoryTagsParams{FromTagId: from.Id, ToTagId: into.Id})
+	if err != nil {
+		return fmt.Errorf("%w(id: %s): %w", ErrFailedToUpdateRecord, from.Id, err)
+	}
+
+	_, err = s.repo.MoveEventTags(ctx, MoveEventTagsParams{FromTagId: from.Id, ToTagId: into.Id})
+	if err != nil {
+		return fmt.Errorf("%w(id: %s): %w", ErrFailedToUpdateRecord, from.Id, err)
+	}
+
+	_, err = s.repo.MoveProjectTags(ctx, MoveProjectTagsParams{FromTagId: from.Id, ToTagId: into.Id})
+	if err != nil {
+		return fmt.Errorf("%w(id: %s): %w", ErrFailedToUpdateRecord, from.Id, err)
+	}
+
+	// synonyms always point at a canonical tag, never at another synonym.
+	_, err = s.repo.RepointTagSynonyms(ctx, RepointTagSynonymsParams{
+		ToTagId:   sql.NullString{String: into.Id, Valid: true},
+		FromTagId: sql.NullString{String: from.Id, Valid: true},
+	})
+	if err != nil {
+		return fmt.Errorf("%w(id: %s): %w", ErrFailedToUpdateRecord, from.Id, err)
+	}
+
+	_, err = s.repo.SetTagCanonical(ctx, SetTagCanonicalParams{
+		CanonicalTagId: sql.NullString{String: into.Id, Valid: true},
+		Id:             from.Id,
+	})
+	if err != nil {
+		return fmt.Errorf("%w(id: %s): %w", ErrFailedToUpdateRecord, from.Id, err)
+	}
+
+	return nil
+}
+
+func (s *Service) replace(ctx context.Context, kind string, entityId string, tagIds []string) error {
+	var err error
+
+	switch kind {
+	case KindStory:
+		err = s.repo.ReplaceStoryTags(ctx, ReplaceStoryTagsParams{StoryId: entityId, TagIds: tagIds})
+	case KindEvent:
+		err = s.repo.ReplaceEventTags(ctx, ReplaceEventTagsParams{EventId: entityId, TagIds: tagIds})
+	case KindProject:
+		err = s.repo.ReplaceProjectTags(ctx, ReplaceProjectTagsParams{ProjectId: entityId, TagIds: tagIds})
+	default:
+		return fmt.Errorf("%w: %s", ErrUnknownKind, kind)
+	}
+
+	if err != nil {
+		return fmt.Errorf("%w(%s: %s): %w", ErrFailedToUpdateRecord, kind, entityId, err)
+	}
+
+	return nil
+}
+
+func (s *Service) getBySlug(ctx context.Context, slug string) (*Tag, error) {
+	if slug == "" {
+		return nil, fmt.Errorf("%w(slug: %s)", ErrRecordNotFound, slug)
+	}
+
+	record, err := s.repo.GetTagBySlug(ctx, slug)
+	if err != nil {
+		return nil, fmt.Errorf("%w(slug: %s): %w", ErrFailedToGetRecord, slug, err)
+	}
+
+	if record == nil {
+		return nil, fmt.Errorf("%w(slug: %s)", ErrRecordNotFound, slug)
+	}
+
+	return record, nil
+}
+
+func (s *Service) canonicalOf(ctx context.Context, record *Tag) (*Tag, error) {
+	if !record.CanonicalTagId.Valid {
+		return record, nil
+	}
+
+	canonical, err := s.repo.GetTagById(ctx, record.CanonicalTagId.String)
+	if err != nil {
+		return nil, fmt.Errorf("%w(id: %s): %w", ErrFailedToGetRecord, record.CanonicalTagId.String, err)
+	}
+
+	if canonical == nil {
+		return nil, fmt.Errorf("%w(id: %s)", ErrRecordNotFound, record.CanonicalTagId.String)
+	}
+
+	return canonical, nil
+}
+
+// normalizeNames returns the distinct slugs of a list of names in order, along
+// with the first name given for each.
+func normalizeNames(names []string) ([]string, map[string]string, error) {
+	slugs := make([]string, 0, len(names))
+	namesBySlug := make(map[string]string, len(names))
+
+	for _, name := range names {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+
+		if len([]rune(name)) > MaxNameLength {
+			return nil, nil, fmt.Errorf("%w: tags can be at most %d characters", ErrInvalidInput, MaxNameLength)
+		}
+
+		slug := NormalizeSlug(name)
+		if slug == "" {
+			return nil, nil, fmt.Errorf("%w: tag %q has no letters or digits", ErrInvalidInput, name)
+		}
+
+		if _, ok := namesBySlug[slug]; ok {
+			continue
+		}
+
+		namesBySlug[slug] = name
+		slugs = append(slugs, slug)
+	}
+
+	if len(slugs) > MaxTags {
+		return nil, nil, fmt.Errorf("%w: at most %d tags are allowed", ErrInvalidInput, MaxTags)
+	}
+
+	return slugs, namesBySlug, nil
+}
@@ -0,0 +1,139 @@
+package tags_test
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/eser/acik.io/pkg/api/business/outbox/outboxtest"
+	"github.com/eser/acik.io/pkg/api/business/tags"
+)
+
+// repository keeps the tags in memory. Only the lookups and upserts Ensure
+// needs are implemented; anything else panics on the nil embedded interface.
+type repository struct {
+	tags.Repository
+
+	tags     map[string]*tags.Tag
+	upserted []string
+}
+
+func (r *repository) GetTagById(_ context.Context, id string) (*tags.Tag, error) {
+	for _, record := range r.tags {
+		if record.Id == id {
+			return record, nil
+		}
+	}
+
+	return nil, nil //nolint:nilnil
+}
+
+func (r *repository) ListTagsBySlugs(_ context.Context, slugs []string) ([]*tags.Tag, error) {
+	result := []*tags.Tag{}
+
+	for _, slug := range slugs {
+		if record, ok := r.tags[slug]; ok {
+			result = append(result, record)
+		}
+	}
+
+	return result, nil
+}
+
+func (r *repository) UpsertTag(_ context.Context, arg tags.UpsertTagParams) (*tags.Tag, error) {
+	record := &tags.Tag{Id: arg.Id, Slug: arg.Slug, Name: arg.Name} //nolint:exhaustruct
+	r.tags[arg.Slug] = record
+	r.upserted = append(r.upserted, arg.Name)
+
+	return record, nil
+}
+
+func newRepository() *repository {
+	return &repository{ //nolint:exhaustruct
+		tags: map[string]*tags.Tag{
+			"go":     {Id: "1", Slug: "go", Name: "Go"},                                                                   //nolint:exhaustruct
+			"golang": {Id: "2", Slug: "golang", Name: "Golang", CanonicalTagId: sql.NullString{String: "1", Valid: true}}, //nolint:exhaustruct
+		},
+	}
+}
+
+func TestNormalizeSlug(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		want string
+	}{
+		{name: "Açık Kaynak", want: "acik-kaynak"},
+		{name: "C", want: "c"},
+		{name: "C++", want: "c-plus-plus"},
+		{name: "C#", want: "c-sharp"},
+		{name: "  Node.js  ", want: "node-js"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := tags.NormalizeSlug(test.name); got != test.want {
+				t.Errorf("got %q, want %q", got, test.want)
+			}
+		})
+	}
+}
+
+func TestEnsure(t *testing.T) {
+	t.Parallel()
+
+	repo := newRepository()
+
+	records, err := tags.NewService(repo, outboxtest.Discard{}).Ensure(context.Background(), []string{"Go", " go ", "Golang", "", "Rust", "rust"})
+	if err != nil {
+		t.Fatalf("ensuring: %v", err)
+	}
+
+	got := make([]string, 0, len(records))
+	for _, record := range records {
+		got = append(got, record.Slug)
+	}
+
+	if strings.Join(got, ",") != "go,rust" {
+		t.Errorf("got %v, want synonyms and duplicates collapsed into go and rust", got)
+	}
+
+	if strings.Join(repo.upserted, ",") != "Rust" {
+		t.Errorf("got %v created, want only Rust under its first given name", repo.upserted)
+	}
+}
+
+func TestEnsureRejects(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name  string
+		names []string
+	}{
+		{name: "no letters", names: []string{"Go", "!!!"}},
+		{name: "too long", names: []string{strings.Repeat("ş", tags.MaxNameLength+1)}},
+		{name: "too many", names: strings.Split("a,b,c,d,e,f,g,h,i,j,k", ",")},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			repo := newRepository()
+
+			_, err := tags.NewService(repo, outboxtest.Discard{}).Ensure(context.Background(), test.names)
+			if !errors.Is(err, tags.ErrInvalidInput) {
+				t.Errorf("got %v, want %v", err, tags.ErrInvalidInput)
+			}
+
+			if len(repo.upserted) != 0 {
+				t.Errorf("got %v created, want none", repo.upserted)
+			}
+		})
+	}
+}
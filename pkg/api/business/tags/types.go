@@ -0,0 +1,72 @@
+package tags
+
+import (
+	"strings"
+
+	"github.com/eser/acik.io/pkg/api/business/slugs"
+	"github.com/oklog/ulid/v2"
+)
+
+const (
+	KindStory   = "story"
+	KindEvent   = "event"
+	KindProject = "project"
+
+	AggregateTag = "tag"
+
+	EventTagMerged       = "tag.merged"
+	EventTagSynonymAdded = "tag.synonym-added"
+)
+
+type RecordID string
+
+type RecordIDGenerator func() RecordID
+
+func DefaultIDGenerator() RecordID {
+	return RecordID(ulid.Make().String())
+}
+
+// TagRef is a tag as attached to stories, events and projects.
+type TagRef struct {
+	Slug string `json:"slug"`
+	Name string `json:"name"`
+}
+
+// TagDetail is a canonical tag along with the synonyms that point to it.
+type TagDetail struct {
+	*Tag
+
+	Synonyms []*TagRef `json:"synonyms"`
+}
+
+// TagEvent is the payload of the tag domain events, recorded on the canonical
+// tag that gained synonyms.
+type TagEvent struct {
+	TagId string `json:"tagId"`
+	Slug  string `json:"slug"`
+
+	before *TagDetail
+	after  *TagDetail
+}
+
+// AuditStates logs the tag and its synonyms before and after the change.
+func (e *TagEvent) AuditStates() (any, any) {
+	return e.before, e.after
+}
+
+type MergeTagInput struct {
+	Into string `json:"into"`
+}
+
+type AddSynonymInput struct {
+	Name string `json:"name"`
+}
+
+var spelledOut = strings.NewReplacer("+", " plus ", "#", " sharp ") //nolint:gochecknoglobals
+
+// NormalizeSlug turns a tag name into its slug, the same way other slugs are
+// normalized, except that "+" and "#" are spelled out so "C++" and "C#" stay
+// apart from "C".
+func NormalizeSlug(name string) string {
+	return slugs.Normalize(spelledOut.Replace(name))
+}
@@ -0,0 +1,150 @@
+package users
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+)
+
+var (
+	ErrFailedToGetRecord     = errors.New("failed to get record")
+	ErrFailedToDeleteRecords = errors.New("failed to delete records")
+	ErrFailedToUpdateRecord  = errors.New("failed to update record")
+	ErrRecordNotFound        = errors.New("record not found")
+	ErrSessionNotValid       = errors.New("session is not valid")
+	ErrUserSuspended         = errors.New("user is suspended")
+)
+
+type Repository interface {
+	Transact(ctx context.Context, fn func(ctx context.Context) error) error
+	GetUserById(ctx context.Context, id string) (*User, error)
+	GetSessionById(ctx context.Context, id string) (*Session, error)
+	DeleteStaleSessions(ctx context.Context, pendingBefore time.Time) (int64, error)
+	IsUserSuspended(ctx context.Context, id string) (bool, error)
+	SuspendUser(ctx context.Context, arg SuspendUserParams) (int64, error)
+	LiftUserSuspension(ctx context.Context, id string) (int64, error)
+	DeleteSessionsOfUser(ctx context.Context, loggedInUserId string) (int64, error)
+}
+
+type Service struct {
+	repo Repository
+}
+
+func NewService(repo Repository) *Service {
+	return &Service{repo: repo}
+}
+
+func (s *Service) GetById(ctx context.Context, id string) (*User, error) {
+	record, err := s.repo.GetUserById(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("%w(id: %s): %w", ErrFailedToGetRecord, id, err)
+	}
+
+	if record == nil {
+		return nil, fmt.Errorf("%w(id: %s)", ErrRecordNotFound, id)
+	}
+
+	return record, nil
+}
+
+// ResolveSession returns the session and its logged in user, as long as the
+// session is logged in and has not expired yet.
+func (s *Service) ResolveSession(ctx context.Context, sessionId string) (*Session, *User, error) {
+	session, err := s.repo.GetSessionById(ctx, sessionId)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w(session: %s): %w", ErrFailedToGetRecord, sessionId, err)
+	}
+
+	if session == nil || session.Status != SessionStatusLoggedIn || !session.LoggedInUserId.Valid {
+		return nil, nil, fmt.Errorf("%w(session: %s)", ErrSessionNotValid, sessionId)
+	}
+
+	if session.ExpiresAt.Valid && !session.ExpiresAt.Time.After(time.Now()) {
+		return nil, nil, fmt.Errorf("%w(session: %s): expired", ErrSessionNotValid, sessionId)
+	}
+
+	user, err := s.GetById(ctx, session.LoggedInUserId.String)
+	if err != nil {
+		if errors.Is(err, ErrRecordNotFound) {
+			return nil, nil, fmt.Errorf("%w(session: %s): %w", ErrSessionNotValid, sessionId, err)
+		}
+
+		return nil, nil, err
+	}
+
+	// sessions are revoked on suspension; this covers ones created since.
+	if user.IsSuspended() {
+		return nil, nil, fmt.Errorf("%w(session: %s): %w", ErrSessionNotValid, sessionId, ErrUserSuspended)
+	}
+
+	return session, user, nil
+}
+
+// EnsureActive returns ErrUserSuspended if the user is suspended. Services
+// call it before letting a user write.
+func (s *Service) EnsureActive(ctx context.Context, userId string) error {
+	isSuspended, err := s.repo.IsUserSuspended(ctx, userId)
+	if err != nil {
+		return fmt.Errorf("%w(id: %s): %w", ErrFailedToGetRecord, userId, err)
+	}
+
+	if isSuspended {
+		return fmt.Errorf("%w(id: %s)", ErrUserSuspended, userId)
+	}
+
+	return nil
+}
+
+// Suspend keeps a user from logging in and from writing, and logs them out
+// of all their sessions.
+func (s *Service) Suspend(ctx context.Context, userId string, reason string) error {
+	return s.repo.Transact(ctx, func(ctx context.Context) error { //nolint:wrapcheck
+		affected, err := s.repo.SuspendUser(ctx, SuspendUserParams{
+			SuspendedReason: sql.NullString{String: reason, Valid: reason != ""},
+			Id:              userId,
+		})
+		if err != nil {
+			return fmt.Errorf("%w(id: %s): %w", ErrFailedToUpdateRecord, userId, err)
+		}
+
+		if affected == 0 {
+			return fmt.Errorf("%w(id: %s)", ErrRecordNotFound, userId)
+		}
+
+		_, err = s.repo.DeleteSessionsOfUser(ctx, userId)
+		if err != nil {
+			return fmt.Errorf("%w(user: %s): %w", ErrFailedToDeleteRecords, userId, err)
+		}
+
+		return nil
+	})
+}
+
+// LiftSuspension lets a suspended user log in again. It returns
+// ErrRecordNotFound for users that aren't suspended.
+func (s *Service) LiftSuspension(ctx context.Context, userId string) error {
+	affected, err := s.repo.LiftUserSuspension(ctx, userId)
+	if err != nil {
+		return fmt.Errorf("%w(id: %s): %w", ErrFailedToUpdateRecord, userId, err)
+	}
+
+	if affected == 0 {
+		return fmt.Errorf("%w(id: %s)", ErrRecordNotFound, userId)
+	}
+
+	return nil
+}
+
+// SweepSessions deletes the sessions that have expired, along with the ones
+// that never got past the login flow within pendingTtl. It returns the number
+// of deleted sessions.
+func (s *Service) SweepSessions(ctx context.Context, pendingTtl time.Duration) (int64, error) {
+	deleted, err := s.repo.DeleteStaleSessions(ctx, time.Now().Add(-pendingTtl))
+	if err != nil {
+		return 0, fmt.Errorf("%w: %w", ErrFailedToDeleteRecords, err)
+	}
+
+	return deleted, nil
+}
@@ -0,0 +1,27 @@
+package users
+
+const (
+	KindRegular   = "regular"
+	KindModerator = "moderator"
+	KindAdmin     = "admin"
+
+	SessionStatusLoggedIn = "logged_in"
+
+	QueueSweepSessions = "users.sweep-sessions"
+)
+
+// SweepSessionsJob is enqueued to remove the expired sessions and the login
+// attempts that were never completed.
+type SweepSessionsJob struct{}
+
+func (u *User) IsModerator() bool {
+	return u.Kind == KindModerator || u.Kind == KindAdmin
+}
+
+func (u *User) IsAdmin() bool {
+	return u.Kind == KindAdmin
+}
+
+func (u *User) IsSuspended() bool {
+	return u.SuspendedAt.Valid
+}
@@ -0,0 +1,12 @@
+package webhooks
+
+import "time"
+
+type Config struct {
+	DispatchInterval     time.Duration `conf:"DISPATCH_INTERVAL" default:"2s"`         // how often the dispatcher polls for due deliveries
+	RequestTimeout       time.Duration `conf:"REQUEST_TIMEOUT" default:"10s"`          // upper bound for a single delivery request
+	RetryBaseDelay       time.Duration `conf:"RETRY_BASE_DELAY" default:"30s"`         // delay before the first retry of a delivery, doubled on every further attempt
+	RetryMaxDelay        time.Duration `conf:"RETRY_MAX_DELAY" default:"6h"`           // upper bound for the delay between retries
+	DisableAfter         time.Duration `conf:"DISABLE_AFTER" default:"72h"`            // how long an endpoint may keep failing before it is disabled
+	AllowPrivateNetworks bool          `conf:"ALLOW_PRIVATE_NETWORKS" default:"false"` // permits plain http and private addresses, for local receivers only
+}
@@ -0,0 +1,875 @@
+package webhooks
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"net"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/eser/acik.io/pkg/api/business/events"
+	"github.com/eser/acik.io/pkg/api/business/notifications"
+	"github.com/eser/acik.io/pkg/api/business/outbox"
+	"github.com/eser/acik.io/pkg/api/business/questions"
+	"github.com/eser/acik.io/pkg/api/business/stories"
+)
+
+const (
+	DispatchBatchSize        = 20
+	MaxAttempts              = 10
+	MinFailuresBeforeDisable = 5
+	MaxResponseBodyLength    = 2048
+	MaxDescriptionLength     = 200
+	MaxDeliveriesPageSize    = 100
+
+	// DeliveryLease is how long a claimed delivery is hidden from other
+	// dispatchers. A dispatcher that dies mid-delivery leaves it to be
+	// retried once the lease runs out.
+	DeliveryLease = 2 * time.Minute
+)
+
+var (
+	ErrFailedToCreateRecord   = errors.New("failed to create record")
+	ErrFailedToGetRecord      = errors.New("failed to get record")
+	ErrFailedToListRecords    = errors.New("failed to list records")
+	ErrFailedToUpdateRecord   = errors.New("failed to update record")
+	ErrFailedToDeleteRecord   = errors.New("failed to delete record")
+	ErrFailedToDecodePayload  = errors.New("failed to decode payload")
+	ErrFailedToClaim          = errors.New("failed to claim deliveries")
+	ErrRecordNotFound         = errors.New("record not found")
+	ErrInvalidUrl             = errors.New("invalid webhook url")
+	ErrNoEventTypes           = errors.New("at least one event type is required")
+	ErrUnknownEventType       = errors.New("unknown event type")
+	ErrDescriptionTooLong     = errors.New("description is too long")
+	ErrUnexpectedResponseCode = errors.New("unexpected response status code")
+)
+
+type Repository interface {
+	Transact(ctx context.Context, fn func(ctx context.Context) error) error
+	CreateWebhookEndpoint(ctx context.Context, arg CreateWebhookEndpointParams) (*WebhookEndpoint, error)
+	GetWebhookEndpointById(ctx context.Context, arg GetWebhookEndpointByIdParams) (*WebhookEndpoint, error)
+	ListWebhookEndpointsByProfileId(ctx context.Context, profileId string) ([]*WebhookEndpoint, error)
+	UpdateWebhookEndpoint(ctx context.Context, arg UpdateWebhookEndpointParams) (*WebhookEndpoint, error)
+	RotateWebhookEndpointSecret(ctx context.Context, arg RotateWebhookEndpointSecretParams) (*WebhookEndpoint, error)
+	DeleteWebhookEndpoint(ctx context.Context, arg DeleteWebhookEndpointParams) (int64, error)
+	ListActiveWebhookEndpointsForProfileEvent(
+		ctx context.Context,
+		arg ListActiveWebhookEndpointsForProfileEventParams,
+	) ([]*WebhookEndpoint, error)
+	ListActiveWebhookEndpointsForQuestionEvent(
+		ctx context.Context,
+		arg ListActiveWebhookEndpointsForQuestionEventParams,
+	) ([]*WebhookEndpoint, error)
+	RecordWebhookEndpointSuccess(ctx context.Context, id string) error
+	RecordWebhookEndpointFailure(ctx context.Context, id string) (*WebhookEndpoint, error)
+	DisableWebhookEndpoint(ctx context.Context, arg DisableWebhookEndpointParams) error
+	InsertWebhookDelivery(ctx context.Context, arg InsertWebhookDeliveryParams) error
+	ClaimWebhookDeliveries(ctx context.Context, arg ClaimWebhookDeliveriesParams) ([]*WebhookDelivery, error)
+	GetWebhookDeliveryById(ctx context.Context, arg GetWebhookDeliveryByIdParams) (*WebhookDelivery, error)
+	ListWebhookDeliveriesByEndpointId(
+		ctx context.Context,
+		arg ListWebhookDeliveriesByEndpointIdParams,
+	) ([]*WebhookDelivery, error)
+	MarkWebhookDeliveryAttempt(ctx context.Context, arg MarkWebhookDeliveryAttemptParams) error
+	GetWebhookEndpointForDelivery(ctx context.Context, id string) (*WebhookEndpoint, error)
+}
+
+// Sender sends signed delivery requests to the receivers. A non-nil result
+// is returned whenever a response was received, whatever its status.
+type Sender interface {
+	Send(ctx context.Context, request *OutgoingRequest) (*SendResult, error)
+}
+
+// EventOrganizers resolves the profiles organizing an event, whose endpoints
+// receive the event's domain events.
+type EventOrganizers interface {
+	ListOrganizerProfileIds(ctx context.Context, eventId string) ([]string, error)
+}
+
+// EventRecorder records domain events. It is called within the transaction
+// of the state change the event describes.
+type EventRecorder interface {
+	Record(ctx context.Context, aggregateType string, aggregateId string, eventType string, payload any) error
+}
+
+type Service struct {
+	config     *Config
+	repo       Repository
+	sender     Sender
+	organizers EventOrganizers
+	events     EventRecorder
+
+	idGenerator RecordIDGenerator
+}
+
+func NewService(
+	config *Config,
+	repo Repository,
+	sender Sender,
+	organizers EventOrganizers,
+	events EventRecorder,
+) *Service {
+	return &Service{
+		config:      config,
+		repo:        repo,
+		sender:      sender,
+		organizers:  organizers,
+		events:      events,
+		idGenerator: DefaultIDGenerator,
+	}
+}
+
+func (s *Service) List(ctx context.Context, profileId string) ([]*Endpoint, error) {
+	records, err := s.repo.ListWebhookEndpointsByProfileId(ctx, profileId)
+	if err != nil {
+		return nil, fmt.Errorf("%w(profile: %s): %w", ErrFailedToListRecords, profileId, err)
+	}
+
+	endpoints := make([]*Endpoint, len(records))
+	for i, record := range records {
+		endpoints[i] = newEndpoint(record, false)
+	}
+
+	return endpoints, nil
+}
+
+func (s *Service) Get(ctx context.Context, profileId string, id string) (*Endpoint, error) {
+	record, err := s.getRecord(ctx, profileId, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return newEndpoint(record, false), nil
+}
+
+// Create registers a new endpoint with a freshly generated secret. The
+// returned endpoint is the only place the secret is disclosed, until it is
+// rotated.
+func (s *Service) Create(ctx context.Context, profileId string, input *EndpointInput) (*Endpoint, error) {
+	eventTypes, err := s.validate(input)
+	if err != nil {
+		return nil, err
+	}
+
+	var record *WebhookEndpoint
+
+	err = s.repo.Transact(ctx, func(ctx context.Context) error {
+		record, err = s.repo.CreateWebhookEndpoint(ctx, CreateWebhookEndpointParams{
+			Id:          string(s.idGenerator()),
+			ProfileId:   profileId,
+			Url:         input.Url,
+			Secret:      GenerateSecret(),
+			EventTypes:  strings.Join(eventTypes, ","),
+			Description: input.Description,
+		})
+		if err != nil {
+			return fmt.Errorf("%w(profile: %s): %w", ErrFailedToCreateRecord, profileId, err)
+		}
+
+		return s.recordChange(ctx, EventEndpointCreated, nil, record)
+	})
+	if err != nil {
+		return nil, err //nolint:wrapcheck
+	}
+
+	return newEndpoint(record, true), nil
+}
+
+// Update replaces the settings of an endpoint. Re-activating an endpoint
+// that was disabled for failing clears its failure streak.
+func (s *Service) Update(
+	ctx context.Context,
+	profileId string,
+	id string,
+	input *EndpointInput,
+) (*Endpoint, error) {
+	eventTypes, err := s.validate(input)
+	if err != nil {
+		return nil, err
+	}
+
+	var record *WebhookEndpoint
+
+	err = s.repo.Transact(ctx, func(ctx context.Context) error {
+		current, err := s.getRecord(ctx, profileId, id)
+		if err != nil {
+			return err
+		}
+
+		isActive := current.IsActive
+		if input.IsActive != nil {
+			isActive = *input.IsActive
+		}
+
+		record, err = s.repo.UpdateWebhookEndpoint(ctx, UpdateWebhookEndpointParams{
+			Url:         input.Url,
+			EventTypes:  strings.Join(eventTypes, ","),
+			Description: input.Description,
+			IsActive:    isActive,
+			Id:          id,
+			ProfileId:   profileId,
+		})
+		if err != nil {
+			return fmt.Errorf("%w(id: %s): %w", ErrFailedToUpdateRecord, id, err)
+		}
+
+		if record == nil {
+			return fmt.Errorf("%w(id: %s)", ErrRecordNotFound, id)
+		}
+
+		return s.recordChange(ctx, EventEndpointUpdated, current, record)
+	})
+	if err != nil {
+		return nil, err //nolint:wrapcheck
+	}
+
+	return newEndpoint(record, false), nil
+}
+
+// RotateSecret replaces the secret of an endpoint and discloses the new one.
+// Deliveries signed from then on, retries included, use the new secret.
+func (s *Service) RotateSecret(ctx context.Context, profileId string, id string) (*Endpoint, error) {
+	var record *WebhookEndpoint
+
+	err := s.repo.Transact(ctx, func(ctx context.Context) error {
+		current, err := s.getRecord(ctx, profileId, id)
+		if err != nil {
+			return err
+		}
+
+		record, err = s.repo.RotateWebhookEndpointSecret(ctx, RotateWebhookEndpointSecretParams{
+			Secret:    GenerateSecret(),
+			Id:        id,
+			ProfileId: profileId,
+		})
+		if err != nil {
+			return fmt.Errorf("%w(id: %s): %w", ErrFailedToUpdateRecord, id, err)
+		}
+
+		if record == nil {
+			return fmt.Errorf("%w(id: %s)", ErrRecordNotFound, id)
+		}
+
+		return s.recordChange(ctx, EventSecretRotated, current, record)
+	})
+	if err != nil {
+		return nil, err //nolint:wrapcheck
+	}
+
+	return newEndpoint(record, true), nil
+}
+
+func (s *Service) Delete(ctx context.Context, profileId string, id string) error {
+	return s.repo.Transact(ctx, func(ctx context.Context) error { //nolint:wrapcheck
+		current, err := s.getRecord(ctx, profileId, id)
+		if err != nil {
+			return err
+		}
+
+		affected, err := s.repo.DeleteWebhookEndpoint(ctx, DeleteWebhookEndpointParams{Id: id, ProfileId: profileId})
+		if err != nil {
+			return fmt.Errorf("%w(id: %s): %w", ErrFailedToDeleteRecord, id, err)
+		}
+
+		if affected == 0 {
+			return fmt.Errorf("%w(id: %s)", ErrRecordNotFound, id)
+		}
+
+		return s.recordChange(ctx, EventEndpointDeleted, current, nil)
+	})
+}
+
+// recordChange records a change of an endpoint from before to after, either
+// of which is nil when the endpoint is created or deleted.
+func (s *Service) recordChange(ctx context.Context, eventType string, before *WebhookEndpoint, after *WebhookEndpoint) error {
+	payload := &EndpointEvent{EndpointId: "", ProfileId: "", before: nil, after: nil}
+
+	for _, record := range []*WebhookEndpoint{before, after} {
+		if record != nil {
+			payload.EndpointId = record.Id
+			payload.ProfileId = record.ProfileId
+		}
+	}
+
+	if before != nil {
+		payload.before = newEndpoint(before, false)
+	}
+
+	if after != nil {
+		payload.after = newEndpoint(after, false)
+	}
+
+	return s.events.Record(ctx, AggregateEndpoint, payload.EndpointId, eventType, payload) //nolint:wrapcheck
+}
+
+// ListDeliveries returns the delivery log of an endpoint, the latest first.
+func (s *Service) ListDeliveries(
+	ctx context.Context,
+	profileId string,
+	id string,
+	limit int32,
+	offset int32,
+) ([]*WebhookDelivery, error) {
+	_, err := s.getRecord(ctx, profileId, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if limit <= 0 || limit > MaxDeliveriesPageSize {
+		limit = MaxDeliveriesPageSize
+	}
+
+	records, err := s.repo.ListWebhookDeliveriesByEndpointId(ctx, ListWebhookDeliveriesByEndpointIdParams{
+		EndpointId:  id,
+		LimitCount:  limit,
+		OffsetCount: max(offset, 0),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%w(endpoint: %s): %w", ErrFailedToListRecords, id, err)
+	}
+
+	return records, nil
+}
+
+// Replay queues a delivery to be sent again with the same body, so receivers
+// see the same message id and can tell it apart from a new event.
+func (s *Service) Replay(
+	ctx context.Context,
+	profileId string,
+	id string,
+	deliveryId string,
+) (*WebhookDelivery, error) {
+	_, err := s.getRecord(ctx, profileId, id)
+	if err != nil {
+		return nil, err
+	}
+
+	original, err := s.getDelivery(ctx, id, deliveryId)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.insertDelivery(ctx, InsertWebhookDeliveryParams{
+		Id:         string(s.idGenerator()),
+		EndpointId: id,
+		EventId:    original.EventId,
+		EventType:  original.EventType,
+		Payload:    original.Payload,
+		ReplayOf:   sql.NullString{String: original.Id, Valid: true},
+	})
+}
+
+// Ping sends a webhook.ping message to an endpoint right away and returns
+// the logged delivery. Pings are not retried and do not count towards the
+// failure streak of the endpoint.
+func (s *Service) Ping(ctx context.Context, profileId string, id string) (*WebhookDelivery, error) {
+	endpoint, err := s.getRecord(ctx, profileId, id)
+	if err != nil {
+		return nil, err
+	}
+
+	messageId := string(s.idGenerator())
+
+	data, err := json.Marshal(&PingEvent{EndpointId: endpoint.Id})
+	if err != nil {
+		return nil, fmt.Errorf("%w(endpoint: %s): %w", ErrFailedToCreateRecord, id, err)
+	}
+
+	payload, err := json.Marshal(&Message{
+		OccurredAt: time.Now(),
+		Id:         messageId,
+		Type:       EventPing,
+		Data:       data,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%w(endpoint: %s): %w", ErrFailedToCreateRecord, id, err)
+	}
+
+	delivery, err := s.insertDelivery(ctx, InsertWebhookDeliveryParams{
+		Id:         messageId,
+		EndpointId: id,
+		EventId:    messageId,
+		EventType:  EventPing,
+		Payload:    payload,
+		ReplayOf:   sql.NullString{}, //nolint:exhaustruct
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.deliver(ctx, endpoint, delivery, false)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.getDelivery(ctx, id, delivery.Id)
+}
+
+// HandleDomainEvent logs a delivery of the event for every active endpoint
+// subscribed to it. Story events go to the endpoints of the author profile,
+// event events to the endpoints of the organizing profiles, and question
+// events to the endpoints of the individual profile of the asker.
+//
+// The outbox delivers events at least once; deliveries are unique per
+// endpoint and event, so handling an event again is harmless.
+func (s *Service) HandleDomainEvent(ctx context.Context, envelope *outbox.Envelope) error {
+	endpoints, err := s.subscribersOf(ctx, envelope)
+	if err != nil {
+		return err
+	}
+
+	if len(endpoints) == 0 {
+		return nil
+	}
+
+	return s.enqueue(ctx, endpoints, &Message{
+		OccurredAt: envelope.OccurredAt,
+		Id:         envelope.Id,
+		Type:       envelope.EventType,
+		Data:       envelope.Payload,
+	})
+}
+
+// Publish logs a delivery of a message for the active endpoints of a profile
+// subscribed to its type. It serves the features delivering messages of their
+// own rather than domain events, like notifications. Deliveries are unique
+// per endpoint and message id, so publishing a message again is harmless.
+func (s *Service) Publish(ctx context.Context, profileId string, message *Message) error {
+	endpoints, err := s.repo.ListActiveWebhookEndpointsForProfileEvent(
+		ctx,
+		ListActiveWebhookEndpointsForProfileEventParams{ProfileId: profileId, EventType: message.Type},
+	)
+	if err != nil {
+		return fmt.Errorf("%w(profile: %s): %w", ErrFailedToListRecords, profileId, err)
+	}
+
+	if len(endpoints) == 0 {
+		return nil
+	}
+
+	return s.enqueue(ctx, endpoints, message)
+}
+
+// DispatchBatch sends the deliveries that are due and returns how many were
+// attempted. Deliveries are leased rather than locked for the duration of the
+// requests, so a slow receiver holds no database connection.
+func (s *Service) DispatchBatch(ctx context.Context) (int, error) {
+	deliveries, err := s.repo.ClaimWebhookDeliveries(ctx, ClaimWebhookDeliveriesParams{
+		LeaseUntil: time.Now().Add(DeliveryLease),
+		LimitCount: DispatchBatchSize,
+	})
+	if err != nil {
+		return 0, fmt.Errorf("%w: %w", ErrFailedToClaim, err)
+	}
+
+	var (
+		wg   sync.WaitGroup
+		mu   sync.Mutex
+		errs []error
+	)
+
+	for _, delivery := range deliveries {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			err := s.dispatch(ctx, delivery)
+			if err != nil {
+				mu.Lock()
+				errs = append(errs, err)
+				mu.Unlock()
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	return len(deliveries), errors.Join(errs...)
+}
+
+// Dispatch sends due deliveries until the context is cancelled. A full batch
+// is followed by the next one right away; otherwise the dispatcher waits for
+// the configured interval. Errors are passed to onError and do not stop it.
+func (s *Service) Dispatch(ctx context.Context, onError func(err error)) {
+	for {
+		attempted, err := s.DispatchBatch(ctx)
+		if err != nil && ctx.Err() == nil {
+			onError(err)
+		}
+
+		if attempted == DispatchBatchSize {
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(s.config.DispatchInterval):
+		}
+	}
+}
+
+// Backoff returns the delay before the given retry of a delivery: the base
+// delay doubled for every previous attempt, capped, with up to 20% jitter.
+func (s *Service) Backoff(attempt int32) time.Duration {
+	delay := s.config.RetryBaseDelay
+
+	for i := int32(1); i < attempt && delay < s.config.RetryMaxDelay; i++ {
+		delay *= 2
+	}
+
+	delay = min(delay, s.config.RetryMaxDelay)
+
+	jitter := time.Duration(rand.Int64N(int64(delay)/5 + 1)) //nolint:gosec,mnd
+
+	return delay - jitter
+}
+
+func (s *Service) subscribersOf(ctx context.Context, envelope *outbox.Envelope) ([]*WebhookEndpoint, error) {
+	var profileIds []string
+
+	switch envelope.EventType {
+	case stories.EventStoryPublished:
+		var payload stories.StoryEvent
+
+		err := json.Unmarshal(envelope.Payload, &payload)
+		if err != nil {
+			return nil, fmt.Errorf("%w(event: %s): %w", ErrFailedToDecodePayload, envelope.Id, err)
+		}
+
+		profileIds = []string{payload.AuthorProfileId}
+	case events.EventEventPublished, events.EventRsvpChanged:
+		organizers, err := s.organizers.ListOrganizerProfileIds(ctx, envelope.AggregateId)
+		if err != nil {
+			return nil, err //nolint:wrapcheck
+		}
+
+		profileIds = organizers
+	case questions.EventQuestionCreated:
+		// questions belong to no profile but the individual one of the asker,
+		// which is looked up rather than read from the payload leaving out
+		// anonymous askers.
+		endpoints, err := s.repo.ListActiveWebhookEndpointsForQuestionEvent(
+			ctx,
+			ListActiveWebhookEndpointsForQuestionEventParams{QuestionId: envelope.AggregateId, EventType: envelope.EventType},
+		)
+		if err != nil {
+			return nil, fmt.Errorf("%w(event: %s): %w", ErrFailedToListRecords, envelope.Id, err)
+		}
+
+		return endpoints, nil
+	case notifications.EventNotificationCreated:
+		// notifications reach endpoints through the webhook channel of their
+		// recipients, which honors their preferences.
+		return nil, nil
+	default:
+		return nil, nil
+	}
+
+	var endpoints []*WebhookEndpoint
+
+	for _, profileId := range profileIds {
+		records, err := s.repo.ListActiveWebhookEndpointsForProfileEvent(
+			ctx,
+			ListActiveWebhookEndpointsForProfileEventParams{ProfileId: profileId, EventType: envelope.EventType},
+		)
+		if err != nil {
+			return nil, fmt.Errorf("%w(profile: %s): %w", ErrFailedToListRecords, profileId, err)
+		}
+
+		endpoints = append(endpoints, records...)
+	}
+
+	return endpoints, nil
+}
+
+func (s *Service) enqueue(ctx context.Context, endpoints []*WebhookEndpoint, message *Message) error {
+	payload, err := json.Marshal(message)
+	if err != nil {
+		return fmt.Errorf("%w(message: %s): %w", ErrFailedToCreateRecord, message.Id, err)
+	}
+
+	return s.repo.Transact(ctx, func(ctx context.Context) error { //nolint:wrapcheck
+		for _, endpoint := range endpoints {
+			err := s.repo.InsertWebhookDelivery(ctx, InsertWebhookDeliveryParams{
+				Id:         string(s.idGenerator()),
+				EndpointId: endpoint.Id,
+				EventId:    message.Id,
+				EventType:  message.Type,
+				Payload:    payload,
+				ReplayOf:   sql.NullString{}, //nolint:exhaustruct
+			})
+			if err != nil {
+				return fmt.Errorf("%w(endpoint: %s, message: %s): %w", ErrFailedToCreateRecord, endpoint.Id, message.Id, err)
+			}
+		}
+
+		return nil
+	})
+}
+
+func (s *Service) dispatch(ctx context.Context, delivery *WebhookDelivery) error {
+	endpoint, err := s.repo.GetWebhookEndpointForDelivery(ctx, delivery.EndpointId)
+	if err != nil {
+		return fmt.Errorf("%w(endpoint: %s): %w", ErrFailedToGetRecord, delivery.EndpointId, err)
+	}
+
+	// the endpoint was removed or disabled after the delivery was claimed;
+	// the delivery is picked up again should it be re-enabled.
+	if endpoint == nil || !endpoint.IsActive || endpoint.DeletedAt.Valid {
+		return nil
+	}
+
+	return s.deliver(ctx, endpoint, delivery, true)
+}
+
+// deliver sends a delivery once and logs the outcome. A failed delivery is
+// scheduled for a retry with backoff unless retries are off or exhausted.
+func (s *Service) deliver(
+	ctx context.Context,
+	endpoint *WebhookEndpoint,
+	delivery *WebhookDelivery,
+	retries bool,
+) error {
+	timestamp := time.Now().Unix()
+
+	result, sendErr := s.sender.Send(ctx, &OutgoingRequest{
+		Url: endpoint.Url,
+		Headers: map[string]string{
+			"Content-Type":  "application/json",
+			"User-Agent":    UserAgent,
+			HeaderEvent:     delivery.EventType,
+			HeaderDelivery:  delivery.Id,
+			HeaderTimestamp: strconv.FormatInt(timestamp, 10),
+			HeaderSignature: Sign(endpoint.Secret, timestamp, delivery.Payload),
+		},
+		Body: delivery.Payload,
+	})
+
+	attempt := delivery.Attempts + 1
+
+	params := MarkWebhookDeliveryAttemptParams{ //nolint:exhaustruct
+		Status:        DeliveryStatusSucceeded,
+		NextAttemptAt: time.Now(),
+		Id:            delivery.Id,
+	}
+
+	if result != nil {
+		params.ResponseStatus = sql.NullInt32{Int32: int32(result.StatusCode), Valid: true} //nolint:gosec
+		params.ResponseBody = sql.NullString{String: sanitizeResponseBody(result.Body), Valid: true}
+		params.DurationMs = sql.NullInt32{Int32: int32(result.Duration.Milliseconds()), Valid: true} //nolint:gosec
+	}
+
+	if sendErr == nil && !result.IsSuccess() {
+		sendErr = fmt.Errorf("%w: %d", ErrUnexpectedResponseCode, result.StatusCode)
+	}
+
+	if sendErr != nil {
+		params.Status = DeliveryStatusFailed
+		params.Error = sql.NullString{String: sendErr.Error(), Valid: true}
+
+		if retries && attempt < MaxAttempts {
+			params.Status = DeliveryStatusPending
+			params.NextAttemptAt = time.Now().Add(s.Backoff(attempt))
+		}
+	}
+
+	err := s.repo.MarkWebhookDeliveryAttempt(ctx, params)
+	if err != nil {
+		return fmt.Errorf("%w(delivery: %s): %w", ErrFailedToUpdateRecord, delivery.Id, err)
+	}
+
+	if !retries {
+		return nil
+	}
+
+	if sendErr == nil {
+		err = s.repo.RecordWebhookEndpointSuccess(ctx, endpoint.Id)
+		if err != nil {
+			return fmt.Errorf("%w(endpoint: %s): %w", ErrFailedToUpdateRecord, endpoint.Id, err)
+		}
+
+		return nil
+	}
+
+	return s.recordFailure(ctx, endpoint.Id)
+}
+
+// recordFailure extends the failure streak of an endpoint and disables it
+// once it has kept failing for longer than the configured window. Requiring
+// a minimum number of failures keeps a single retry that happens to fall
+// outside the window from disabling an endpoint.
+func (s *Service) recordFailure(ctx context.Context, endpointId string) error {
+	endpoint, err := s.repo.RecordWebhookEndpointFailure(ctx, endpointId)
+	if err != nil {
+		return fmt.Errorf("%w(endpoint: %s): %w", ErrFailedToUpdateRecord, endpointId, err)
+	}
+
+	if endpoint == nil ||
+		endpoint.ConsecutiveFailures < MinFailuresBeforeDisable ||
+		!endpoint.FailingSince.Valid ||
+		time.Since(endpoint.FailingSince.Time) < s.config.DisableAfter {
+		return nil
+	}
+
+	err = s.repo.DisableWebhookEndpoint(ctx, DisableWebhookEndpointParams{
+		DisabledReason: sql.NullString{
+			String: fmt.Sprintf(
+				"failing since %s (%d consecutive failures)",
+				endpoint.FailingSince.Time.UTC().Format(time.RFC3339),
+				endpoint.ConsecutiveFailures,
+			),
+			Valid: true,
+		},
+		Id: endpointId,
+	})
+	if err != nil {
+		return fmt.Errorf("%w(endpoint: %s): %w", ErrFailedToUpdateRecord, endpointId, err)
+	}
+
+	return nil
+}
+
+func (s *Service) getRecord(ctx context.Context, profileId string, id string) (*WebhookEndpoint, error) {
+	record, err := s.repo.GetWebhookEndpointById(ctx, GetWebhookEndpointByIdParams{Id: id, ProfileId: profileId})
+	if err != nil {
+		return nil, fmt.Errorf("%w(id: %s): %w", ErrFailedToGetRecord, id, err)
+	}
+
+	if record == nil {
+		return nil, fmt.Errorf("%w(id: %s)", ErrRecordNotFound, id)
+	}
+
+	return record, nil
+}
+
+func (s *Service) getDelivery(ctx context.Context, endpointId string, id string) (*WebhookDelivery, error) {
+	record, err := s.repo.GetWebhookDeliveryById(ctx, GetWebhookDeliveryByIdParams{Id: id, EndpointId: endpointId})
+	if err != nil {
+		return nil, fmt.Errorf("%w(delivery: %s): %w", ErrFailedToGetRecord, id, err)
+	}
+
+	if record == nil {
+		return nil, fmt.Errorf("%w(delivery: %s)", ErrRecordNotFound, id)
+	}
+
+	return record, nil
+}
+
+func (s *Service) insertDelivery(ctx context.Context, arg InsertWebhookDeliveryParams) (*WebhookDelivery, error) {
+	err := s.repo.InsertWebhookDelivery(ctx, arg)
+	if err != nil {
+		return nil, fmt.Errorf("%w(endpoint: %s): %w", ErrFailedToCreateRecord, arg.EndpointId, err)
+	}
+
+	return s.getDelivery(ctx, arg.EndpointId, arg.Id)
+}
+
+// validate checks the input of an endpoint and returns its event types,
+// deduplicated and sorted.
+func (s *Service) validate(input *EndpointInput) ([]string, error) {
+	err := s.validateUrl(input.Url)
+	if err != nil {
+		return nil, err
+	}
+
+	if utf8.RuneCountInString(input.Description) > MaxDescriptionLength {
+		return nil, fmt.Errorf("%w(max: %d)", ErrDescriptionTooLong, MaxDescriptionLength)
+	}
+
+	if len(input.EventTypes) == 0 {
+		return nil, ErrNoEventTypes
+	}
+
+	eventTypes := make([]string, 0, len(input.EventTypes))
+
+	for _, eventType := range input.EventTypes {
+		if !slices.Contains(EventTypes, eventType) {
+			return nil, fmt.Errorf("%w(type: %s)", ErrUnknownEventType, eventType)
+		}
+
+		eventTypes = append(eventTypes, eventType)
+	}
+
+	slices.Sort(eventTypes)
+
+	return slices.Compact(eventTypes), nil
+}
+
+// validateUrl rejects endpoints that are not plain https urls. Addresses are
+// checked again when connecting, as a host name may resolve to anything.
+func (s *Service) validateUrl(raw string) error {
+	parsed, err := url.Parse(raw)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidUrl, err)
+	}
+
+	if parsed.Host == "" || parsed.User != nil || parsed.Fragment != "" {
+		return fmt.Errorf("%w(url: %s)", ErrInvalidUrl, raw)
+	}
+
+	if parsed.Scheme != "https" && (parsed.Scheme != "http" || !s.config.AllowPrivateNetworks) {
+		return fmt.Errorf("%w(url: %s): https is required", ErrInvalidUrl, raw)
+	}
+
+	if s.config.AllowPrivateNetworks {
+		return nil
+	}
+
+	if ip := net.ParseIP(parsed.Hostname()); ip != nil && isPrivateAddress(ip) {
+		return fmt.Errorf("%w(url: %s): private addresses are not allowed", ErrInvalidUrl, raw)
+	}
+
+	if strings.EqualFold(parsed.Hostname(), "localhost") {
+		return fmt.Errorf("%w(url: %s): private addresses are not allowed", ErrInvalidUrl, raw)
+	}
+
+	return nil
+}
+
+// isPrivateAddress reports whether an address is not publicly routable.
+func isPrivateAddress(ip net.IP) bool {
+	return ip.IsLoopback() ||
+		ip.IsPrivate() ||
+		ip.IsLinkLocalUnicast() ||
+		ip.IsLinkLocalMulticast() ||
+		ip.IsInterfaceLocalMulticast() ||
+		ip.IsMulticast() ||
+		ip.IsUnspecified()
+}
+
+func newEndpoint(record *WebhookEndpoint, revealSecret bool) *Endpoint {
+	endpoint := &Endpoint{
+		WebhookEndpoint: record,
+		Secret:          "",
+		EventTypes:      strings.Split(record.EventTypes, ","),
+	}
+
+	if revealSecret {
+		endpoint.Secret = record.Secret
+	}
+
+	return endpoint
+}
+
+// sanitizeResponseBody makes a response body safe to store: truncated, valid
+// UTF-8 and free of NUL bytes, which PostgreSQL text columns reject.
+func sanitizeResponseBody(body string) string {
+	if len(body) > MaxResponseBodyLength {
+		body = body[:MaxResponseBodyLength]
+	}
+
+	body = strings.ToValidUTF8(body, "�")
+
+	return strings.ReplaceAll(body, "\x00", "")
+}
@@ -0,0 +1,511 @@
+package webhooks_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	adapterwebhooks "github.com/eser/acik.io/pkg/api/adapters/webhooks"
+	"github.com/eser/acik.io/pkg/api/business/outbox"
+	"github.com/eser/acik.io/pkg/api/business/outbox/outboxtest"
+	"github.com/eser/acik.io/pkg/api/business/questions"
+	"github.com/eser/acik.io/pkg/api/business/stories"
+	"github.com/eser/acik.io/pkg/api/business/webhooks"
+	"github.com/eser/acik.io/pkg/api/business/webhooks/webhookstest"
+)
+
+const (
+	testProfileId = "01HPROFILE0000000000000000"
+	testTolerance = 5 * time.Minute
+)
+
+type receivedRequest struct {
+	header http.Header
+	body   []byte
+}
+
+// receiver is a webhook receiver answering with the given status codes in
+// turn, the last one repeating.
+type receiver struct {
+	server   *httptest.Server
+	statuses []int
+	requests []*receivedRequest
+	mu       sync.Mutex
+}
+
+func newReceiver(t *testing.T, statuses ...int) *receiver {
+	t.Helper()
+
+	r := &receiver{statuses: statuses} //nolint:exhaustruct
+
+	r.server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		body, _ := io.ReadAll(req.Body)
+
+		r.mu.Lock()
+		r.requests = append(r.requests, &receivedRequest{header: req.Header.Clone(), body: body})
+		status := r.statuses[min(len(r.requests), len(r.statuses))-1]
+		r.mu.Unlock()
+
+		w.WriteHeader(status)
+	}))
+
+	t.Cleanup(r.server.Close)
+
+	return r
+}
+
+func (r *receiver) received() []*receivedRequest {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return append([]*receivedRequest{}, r.requests...)
+}
+
+type fixture struct {
+	service  *webhooks.Service
+	repo     *webhookstest.Repository
+	receiver *receiver
+	endpoint *webhooks.Endpoint
+}
+
+func newFixture(t *testing.T, config *webhooks.Config, statuses ...int) *fixture {
+	t.Helper()
+
+	config.AllowPrivateNetworks = true
+	config.RequestTimeout = 5 * time.Second
+
+	repo := webhookstest.NewRepository()
+	service := webhooks.NewService(config, repo, adapterwebhooks.NewSender(config), nil, outboxtest.Discard{})
+	receiver := newReceiver(t, statuses...)
+
+	endpoint, err := service.Create(context.Background(), testProfileId, &webhooks.EndpointInput{
+		IsActive:    nil,
+		Url:         receiver.server.URL,
+		Description: "test receiver",
+		EventTypes:  []string{stories.EventStoryPublished},
+	})
+	if err != nil {
+		t.Fatalf("creating endpoint: %v", err)
+	}
+
+	return &fixture{service: service, repo: repo, receiver: receiver, endpoint: endpoint}
+}
+
+func (f *fixture) publishStory(t *testing.T, eventId string) {
+	t.Helper()
+
+	payload, err := json.Marshal(&stories.StoryEvent{ //nolint:exhaustruct
+		StoryId:         "01HSTORY00000000000000000",
+		Slug:            "hello",
+		AuthorProfileId: testProfileId,
+		Status:          "published",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = f.service.HandleDomainEvent(context.Background(), &outbox.Envelope{
+		OccurredAt:    time.Now(),
+		Id:            eventId,
+		AggregateType: "story",
+		AggregateId:   "01HSTORY00000000000000000",
+		EventType:     stories.EventStoryPublished,
+		Payload:       payload,
+		Attempt:       0,
+	})
+	if err != nil {
+		t.Fatalf("handling event: %v", err)
+	}
+}
+
+func (f *fixture) dispatch(t *testing.T) int {
+	t.Helper()
+
+	attempted, err := f.service.DispatchBatch(context.Background())
+	if err != nil {
+		t.Fatalf("dispatching: %v", err)
+	}
+
+	return attempted
+}
+
+func (f *fixture) onlyDelivery(t *testing.T) *webhooks.WebhookDelivery {
+	t.Helper()
+
+	deliveries := f.repo.Deliveries(f.endpoint.Id)
+	if len(deliveries) != 1 {
+		t.Fatalf("got %d deliveries, want 1", len(deliveries))
+	}
+
+	return deliveries[0]
+}
+
+func defaultConfig() *webhooks.Config {
+	return &webhooks.Config{ //nolint:exhaustruct
+		RetryBaseDelay: time.Hour,
+		RetryMaxDelay:  6 * time.Hour,
+		DisableAfter:   72 * time.Hour,
+	}
+}
+
+func TestDeliveryIsSigned(t *testing.T) {
+	t.Parallel()
+
+	f := newFixture(t, defaultConfig(), http.StatusOK)
+	f.publishStory(t, "01HEVENT0000000000000000001")
+
+	if attempted := f.dispatch(t); attempted != 1 {
+		t.Fatalf("attempted %d deliveries, want 1", attempted)
+	}
+
+	requests := f.receiver.received()
+	if len(requests) != 1 {
+		t.Fatalf("received %d requests, want 1", len(requests))
+	}
+
+	request := requests[0]
+	signature := request.header.Get(webhooks.HeaderSignature)
+	timestamp := request.header.Get(webhooks.HeaderTimestamp)
+
+	err := webhooks.Verify(f.endpoint.Secret, signature, timestamp, request.body, testTolerance)
+	if err != nil {
+		t.Errorf("verifying with the endpoint secret: %v", err)
+	}
+
+	err = webhooks.Verify(webhooks.GenerateSecret(), signature, timestamp, request.body, testTolerance)
+	if !errors.Is(err, webhooks.ErrSignatureMismatch) {
+		t.Errorf("verifying with another secret: got %v, want %v", err, webhooks.ErrSignatureMismatch)
+	}
+
+	err = webhooks.Verify(f.endpoint.Secret, signature, timestamp, append(request.body, ' '), testTolerance)
+	if !errors.Is(err, webhooks.ErrSignatureMismatch) {
+		t.Errorf("verifying a tampered body: got %v, want %v", err, webhooks.ErrSignatureMismatch)
+	}
+
+	var message webhooks.Message
+
+	err = json.Unmarshal(request.body, &message)
+	if err != nil {
+		t.Fatalf("decoding message: %v", err)
+	}
+
+	if message.Id != "01HEVENT0000000000000000001" || message.Type != stories.EventStoryPublished {
+		t.Errorf("got message %s of type %s", message.Id, message.Type)
+	}
+
+	delivery := f.onlyDelivery(t)
+
+	if request.header.Get(webhooks.HeaderDelivery) != delivery.Id {
+		t.Errorf("got delivery header %q, want %q", request.header.Get(webhooks.HeaderDelivery), delivery.Id)
+	}
+
+	if delivery.Status != webhooks.DeliveryStatusSucceeded || delivery.Attempts != 1 {
+		t.Errorf("got delivery %s after %d attempts", delivery.Status, delivery.Attempts)
+	}
+}
+
+func TestVerifyRejectsStaleTimestamps(t *testing.T) {
+	t.Parallel()
+
+	secret := webhooks.GenerateSecret()
+	body := []byte(`{"id":"1"}`)
+	timestamp := time.Now().Add(-time.Hour).Unix()
+
+	err := webhooks.Verify(
+		secret,
+		webhooks.Sign(secret, timestamp, body),
+		"not a number",
+		body,
+		testTolerance,
+	)
+	if !errors.Is(err, webhooks.ErrTimestampTooOld) {
+		t.Errorf("malformed timestamp: got %v, want %v", err, webhooks.ErrTimestampTooOld)
+	}
+
+	err = webhooks.Verify(secret, webhooks.Sign(secret, timestamp, body), strconv.FormatInt(timestamp, 10), body, testTolerance)
+	if !errors.Is(err, webhooks.ErrTimestampTooOld) {
+		t.Errorf("stale timestamp: got %v, want %v", err, webhooks.ErrTimestampTooOld)
+	}
+}
+
+func TestDomainEventIsDeliveredOnce(t *testing.T) {
+	t.Parallel()
+
+	f := newFixture(t, defaultConfig(), http.StatusOK)
+	f.publishStory(t, "01HEVENT0000000000000000001")
+	f.publishStory(t, "01HEVENT0000000000000000001")
+
+	f.onlyDelivery(t)
+}
+
+func TestQuestionCreatedReachesTheAskerOnly(t *testing.T) {
+	t.Parallel()
+
+	const (
+		askerProfileId = "01HASKER000000000000000000"
+		questionId     = "01HQUESTION000000000000000"
+	)
+
+	f := newFixture(t, defaultConfig(), http.StatusOK)
+	f.repo.SetQuestionAsker(questionId, askerProfileId)
+
+	subscribe := func(profileId string) *webhooks.Endpoint {
+		endpoint, err := f.service.Create(context.Background(), profileId, &webhooks.EndpointInput{
+			IsActive:    nil,
+			Url:         f.receiver.server.URL,
+			Description: "",
+			EventTypes:  []string{questions.EventQuestionCreated},
+		})
+		if err != nil {
+			t.Fatalf("creating endpoint: %v", err)
+		}
+
+		return endpoint
+	}
+
+	asker := subscribe(askerProfileId)
+	other := subscribe(testProfileId)
+
+	payload, err := json.Marshal(&questions.QuestionCreatedEvent{QuestionId: questionId, UserId: "", Content: "Why?"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = f.service.HandleDomainEvent(context.Background(), &outbox.Envelope{
+		OccurredAt:    time.Now(),
+		Id:            "01HEVENT0000000000000000001",
+		AggregateType: questions.AggregateQuestion,
+		AggregateId:   questionId,
+		EventType:     questions.EventQuestionCreated,
+		Payload:       payload,
+		Attempt:       0,
+	})
+	if err != nil {
+		t.Fatalf("handling event: %v", err)
+	}
+
+	if got := len(f.repo.Deliveries(asker.Id)); got != 1 {
+		t.Errorf("got %d deliveries for the asker, want 1", got)
+	}
+
+	if got := len(f.repo.Deliveries(other.Id)); got != 0 {
+		t.Errorf("got %d deliveries for another profile, want none", got)
+	}
+}
+
+func TestFailedDeliveryIsRetriedWithBackoff(t *testing.T) {
+	t.Parallel()
+
+	config := defaultConfig()
+	f := newFixture(t, config, http.StatusInternalServerError, http.StatusServiceUnavailable, http.StatusNoContent)
+	f.publishStory(t, "01HEVENT0000000000000000001")
+
+	f.dispatch(t)
+
+	delivery := f.onlyDelivery(t)
+	if delivery.Status != webhooks.DeliveryStatusPending || delivery.Attempts != 1 {
+		t.Fatalf("got delivery %s after %d attempts, want a pending retry", delivery.Status, delivery.Attempts)
+	}
+
+	if wait := time.Until(delivery.NextAttemptAt); wait < config.RetryBaseDelay*4/5-time.Minute || wait > config.RetryBaseDelay {
+		t.Errorf("got the retry in %s, want about %s", wait, config.RetryBaseDelay)
+	}
+
+	if attempted := f.dispatch(t); attempted != 0 {
+		t.Errorf("attempted %d deliveries before the backoff passed, want 0", attempted)
+	}
+
+	if endpoint := f.repo.Endpoint(f.endpoint.Id); endpoint.ConsecutiveFailures != 1 {
+		t.Errorf("got %d consecutive failures, want 1", endpoint.ConsecutiveFailures)
+	}
+
+	f.repo.MakeDue()
+	f.dispatch(t)
+	f.repo.MakeDue()
+	f.dispatch(t)
+
+	delivery = f.onlyDelivery(t)
+	if delivery.Status != webhooks.DeliveryStatusSucceeded || delivery.Attempts != 3 {
+		t.Errorf("got delivery %s after %d attempts, want succeeded after 3", delivery.Status, delivery.Attempts)
+	}
+
+	requests := f.receiver.received()
+	if len(requests) != 3 {
+		t.Fatalf("received %d requests, want 3", len(requests))
+	}
+
+	for _, request := range requests {
+		if request.header.Get(webhooks.HeaderDelivery) != delivery.Id {
+			t.Errorf("got delivery header %q on a retry, want %q", request.header.Get(webhooks.HeaderDelivery), delivery.Id)
+		}
+	}
+
+	if endpoint := f.repo.Endpoint(f.endpoint.Id); endpoint.ConsecutiveFailures != 0 || endpoint.FailingSince.Valid {
+		t.Errorf("got %d consecutive failures after a success, want 0", endpoint.ConsecutiveFailures)
+	}
+}
+
+func TestRetriesStopAfterMaxAttempts(t *testing.T) {
+	t.Parallel()
+
+	f := newFixture(t, defaultConfig(), http.StatusInternalServerError)
+	f.publishStory(t, "01HEVENT0000000000000000001")
+
+	for range webhooks.MaxAttempts + 2 {
+		f.repo.MakeDue()
+		f.dispatch(t)
+	}
+
+	delivery := f.onlyDelivery(t)
+	if delivery.Status != webhooks.DeliveryStatusFailed || delivery.Attempts != webhooks.MaxAttempts {
+		t.Errorf("got delivery %s after %d attempts, want failed after %d", delivery.Status, delivery.Attempts, webhooks.MaxAttempts)
+	}
+
+	if !delivery.ResponseStatus.Valid || delivery.ResponseStatus.Int32 != http.StatusInternalServerError {
+		t.Errorf("got response status %v, want %d", delivery.ResponseStatus, http.StatusInternalServerError)
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	t.Parallel()
+
+	service := webhooks.NewService(defaultConfig(), webhookstest.NewRepository(), nil, nil, outboxtest.Discard{})
+
+	tests := []struct {
+		attempt int32
+		want    time.Duration
+	}{
+		{attempt: 1, want: time.Hour},
+		{attempt: 2, want: 2 * time.Hour},
+		{attempt: 3, want: 4 * time.Hour},
+		{attempt: 4, want: 6 * time.Hour},
+		{attempt: 9, want: 6 * time.Hour},
+	}
+
+	for _, test := range tests {
+		for range 20 {
+			got := service.Backoff(test.attempt)
+			if got > test.want || got < test.want*4/5 {
+				t.Errorf("Backoff(%d) = %s, want %s less up to 20%% jitter", test.attempt, got, test.want)
+			}
+		}
+	}
+}
+
+func TestFailingEndpointIsDisabled(t *testing.T) {
+	t.Parallel()
+
+	config := defaultConfig()
+	config.DisableAfter = 0
+
+	f := newFixture(t, config, http.StatusBadGateway)
+	f.publishStory(t, "01HEVENT0000000000000000001")
+
+	for i := range webhooks.MinFailuresBeforeDisable {
+		if endpoint := f.repo.Endpoint(f.endpoint.Id); !endpoint.IsActive {
+			t.Fatalf("endpoint disabled after %d failures, want at least %d", i, webhooks.MinFailuresBeforeDisable)
+		}
+
+		f.repo.MakeDue()
+		f.dispatch(t)
+	}
+
+	endpoint := f.repo.Endpoint(f.endpoint.Id)
+	if endpoint.IsActive || !endpoint.DisabledAt.Valid || !endpoint.DisabledReason.Valid {
+		t.Fatalf("endpoint still active after %d failures", endpoint.ConsecutiveFailures)
+	}
+
+	f.repo.MakeDue()
+
+	if attempted := f.dispatch(t); attempted != 0 {
+		t.Errorf("attempted %d deliveries to a disabled endpoint, want 0", attempted)
+	}
+
+	// re-enabling the endpoint clears its failure streak.
+	isActive := true
+
+	updated, err := f.service.Update(context.Background(), testProfileId, f.endpoint.Id, &webhooks.EndpointInput{
+		IsActive:    &isActive,
+		Url:         f.endpoint.Url,
+		Description: f.endpoint.Description,
+		EventTypes:  f.endpoint.EventTypes,
+	})
+	if err != nil {
+		t.Fatalf("re-enabling endpoint: %v", err)
+	}
+
+	if !updated.IsActive || updated.ConsecutiveFailures != 0 || updated.FailingSince.Valid {
+		t.Errorf("got re-enabled endpoint with %d consecutive failures", updated.ConsecutiveFailures)
+	}
+}
+
+func TestEndpointFailingWithinWindowStaysActive(t *testing.T) {
+	t.Parallel()
+
+	f := newFixture(t, defaultConfig(), http.StatusBadGateway)
+	f.publishStory(t, "01HEVENT0000000000000000001")
+
+	for range webhooks.MinFailuresBeforeDisable + 1 {
+		f.repo.MakeDue()
+		f.dispatch(t)
+	}
+
+	if endpoint := f.repo.Endpoint(f.endpoint.Id); !endpoint.IsActive {
+		t.Errorf("endpoint disabled after failing for %s, want active until %s", time.Since(endpoint.FailingSince.Time), 72*time.Hour)
+	}
+}
+
+func TestReplaySendsTheSameMessage(t *testing.T) {
+	t.Parallel()
+
+	f := newFixture(t, defaultConfig(), http.StatusOK)
+	f.publishStory(t, "01HEVENT0000000000000000001")
+	f.dispatch(t)
+
+	original := f.onlyDelivery(t)
+
+	replay, err := f.service.Replay(context.Background(), testProfileId, f.endpoint.Id, original.Id)
+	if err != nil {
+		t.Fatalf("replaying: %v", err)
+	}
+
+	if replay.Id == original.Id || !replay.ReplayOf.Valid || replay.ReplayOf.String != original.Id {
+		t.Errorf("got replay %s of %v, want a new delivery of %s", replay.Id, replay.ReplayOf, original.Id)
+	}
+
+	if replay.Status != webhooks.DeliveryStatusPending {
+		t.Errorf("got replay %s, want pending", replay.Status)
+	}
+
+	f.dispatch(t)
+
+	requests := f.receiver.received()
+	if len(requests) != 2 {
+		t.Fatalf("received %d requests, want 2", len(requests))
+	}
+
+	if string(requests[1].body) != string(requests[0].body) {
+		t.Errorf("got replayed body %s, want %s", requests[1].body, requests[0].body)
+	}
+
+	if requests[1].header.Get(webhooks.HeaderDelivery) != replay.Id {
+		t.Errorf("got delivery header %q, want %q", requests[1].header.Get(webhooks.HeaderDelivery), replay.Id)
+	}
+
+	_, err = f.service.Replay(context.Background(), testProfileId, f.endpoint.Id, "01HMISSING000000000000000")
+	if !errors.Is(err, webhooks.ErrRecordNotFound) {
+		t.Errorf("replaying a missing delivery: got %v, want %v", err, webhooks.ErrRecordNotFound)
+	}
+
+	_, err = f.service.Replay(context.Background(), "01HOTHERPROFILE00000000000", f.endpoint.Id, original.Id)
+	if !errors.Is(err, webhooks.ErrRecordNotFound) {
+		t.Errorf("replaying from another profile: got %v, want %v", err, webhooks.ErrRecordNotFound)
+	}
+}
@@ -0,0 +1,122 @@
+package webhooks
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/eser/acik.io/pkg/api/business/events"
+	"github.com/eser/acik.io/pkg/api/business/notifications"
+	"github.com/eser/acik.io/pkg/api/business/questions"
+	"github.com/eser/acik.io/pkg/api/business/stories"
+	"github.com/oklog/ulid/v2"
+)
+
+const (
+	DeliveryStatusPending   = "pending"
+	DeliveryStatusSucceeded = "succeeded"
+	DeliveryStatusFailed    = "failed"
+
+	// EventPing is delivered on request, to check an endpoint is reachable
+	// and verifies signatures.
+	EventPing = "webhook.ping"
+
+	AggregateEndpoint = "webhook"
+
+	EventEndpointCreated = "webhook.created"
+	EventEndpointUpdated = "webhook.updated"
+	EventEndpointDeleted = "webhook.deleted"
+	EventSecretRotated   = "webhook.secret-rotated"
+
+	HeaderEvent     = "X-Acik-Event"
+	HeaderDelivery  = "X-Acik-Delivery"
+	HeaderTimestamp = "X-Acik-Timestamp"
+	HeaderSignature = "X-Acik-Signature"
+
+	UserAgent = "acik.io-webhooks/1.0"
+)
+
+// EventTypes lists the domain events endpoints can subscribe to.
+var EventTypes = []string{ //nolint:gochecknoglobals
+	stories.EventStoryPublished,
+	events.EventEventPublished,
+	events.EventRsvpChanged,
+	questions.EventQuestionCreated,
+	notifications.EventNotificationCreated,
+}
+
+type RecordID string
+
+type RecordIDGenerator func() RecordID
+
+func DefaultIDGenerator() RecordID {
+	return RecordID(ulid.Make().String())
+}
+
+// Endpoint is a webhook endpoint as shown to the admins of its profile. The
+// secret is only disclosed right after it is generated.
+type Endpoint struct {
+	*WebhookEndpoint
+
+	Secret     string   `json:"secret,omitempty"`
+	EventTypes []string `json:"eventTypes"`
+}
+
+type EndpointInput struct {
+	IsActive    *bool    `json:"isActive"`
+	Url         string   `json:"url"`
+	Description string   `json:"description"`
+	EventTypes  []string `json:"eventTypes"`
+}
+
+// Message is the body of every delivery. Its id stays the same across the
+// retries and the replays of a delivery, so receivers can drop duplicates.
+type Message struct {
+	OccurredAt time.Time       `json:"occurredAt"`
+	Id         string          `json:"id"`
+	Type       string          `json:"type"`
+	Data       json.RawMessage `json:"data"`
+}
+
+// PingEvent is the data of EventPing messages.
+type PingEvent struct {
+	EndpointId string `json:"endpointId"`
+}
+
+// EndpointEvent is the payload of every endpoint domain event. Its states
+// never disclose the secret.
+type EndpointEvent struct {
+	EndpointId string `json:"endpointId"`
+	ProfileId  string `json:"profileId"`
+
+	before *Endpoint
+	after  *Endpoint
+}
+
+// AuditProfileId attributes the changes of an endpoint to its profile.
+func (e *EndpointEvent) AuditProfileId() string {
+	return e.ProfileId
+}
+
+// AuditStates logs the endpoint before and after the change. before is nil
+// for new endpoints and after for deleted ones.
+func (e *EndpointEvent) AuditStates() (any, any) {
+	return e.before, e.after
+}
+
+// OutgoingRequest is a signed delivery request, ready to be sent.
+type OutgoingRequest struct {
+	Headers map[string]string
+	Url     string
+	Body    []byte
+}
+
+// SendResult is the response of a receiver.
+type SendResult struct {
+	Body       string
+	StatusCode int
+	Duration   time.Duration
+}
+
+func (r *SendResult) IsSuccess() bool {
+	return r.StatusCode >= 200 && r.StatusCode < 300
+}
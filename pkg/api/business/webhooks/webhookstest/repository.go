@@ -0,0 +1,463 @@
+// Package webhookstest provides an in-memory webhooks.Repository for tests.
+package webhookstest
+
+import (
+	"context"
+	"database/sql"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/eser/acik.io/pkg/api/business/webhooks"
+)
+
+// Repository keeps endpoints and deliveries in memory, following the
+// semantics of the queries in webhooks.sql. Transactions are not isolated;
+// the function is run as is.
+type Repository struct {
+	endpoints  map[string]*webhooks.WebhookEndpoint
+	askers     map[string]string
+	deliveries []*webhooks.WebhookDelivery
+	mu         sync.Mutex
+}
+
+var _ webhooks.Repository = (*Repository)(nil)
+
+func NewRepository() *Repository {
+	return &Repository{ //nolint:exhaustruct
+		endpoints: make(map[string]*webhooks.WebhookEndpoint),
+		askers:    make(map[string]string),
+	}
+}
+
+// SetQuestionAsker records the individual profile of the user who asked a
+// question.
+func (r *Repository) SetQuestionAsker(questionId string, profileId string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.askers[questionId] = profileId
+}
+
+// Endpoint returns a copy of an endpoint, deleted or not.
+func (r *Repository) Endpoint(id string) *webhooks.WebhookEndpoint {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	endpoint, ok := r.endpoints[id]
+	if !ok {
+		return nil
+	}
+
+	clone := *endpoint
+
+	return &clone
+}
+
+// Deliveries returns copies of the deliveries of an endpoint, in the order
+// they were logged.
+func (r *Repository) Deliveries(endpointId string) []*webhooks.WebhookDelivery {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	result := []*webhooks.WebhookDelivery{}
+
+	for _, delivery := range r.deliveries {
+		if delivery.EndpointId == endpointId {
+			clone := *delivery
+			result = append(result, &clone)
+		}
+	}
+
+	return result
+}
+
+// MakeDue moves the next attempt of every pending delivery to now, as if
+// their backoff had passed.
+func (r *Repository) MakeDue() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, delivery := range r.deliveries {
+		if delivery.Status == webhooks.DeliveryStatusPending {
+			delivery.NextAttemptAt = time.Now()
+		}
+	}
+}
+
+func (r *Repository) Transact(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
+
+func (r *Repository) CreateWebhookEndpoint(
+	_ context.Context,
+	arg webhooks.CreateWebhookEndpointParams,
+) (*webhooks.WebhookEndpoint, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	endpoint := &webhooks.WebhookEndpoint{ //nolint:exhaustruct
+		Id:          arg.Id,
+		ProfileId:   arg.ProfileId,
+		Url:         arg.Url,
+		Secret:      arg.Secret,
+		EventTypes:  arg.EventTypes,
+		Description: arg.Description,
+		IsActive:    true,
+		CreatedAt:   time.Now(),
+	}
+
+	r.endpoints[arg.Id] = endpoint
+	clone := *endpoint
+
+	return &clone, nil
+}
+
+func (r *Repository) GetWebhookEndpointById(
+	_ context.Context,
+	arg webhooks.GetWebhookEndpointByIdParams,
+) (*webhooks.WebhookEndpoint, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	endpoint := r.ownedEndpoint(arg.Id, arg.ProfileId)
+	if endpoint == nil {
+		return nil, nil //nolint:nilnil
+	}
+
+	clone := *endpoint
+
+	return &clone, nil
+}
+
+func (r *Repository) ListWebhookEndpointsByProfileId(
+	_ context.Context,
+	profileId string,
+) ([]*webhooks.WebhookEndpoint, error) {
+	return r.filterEndpoints(func(endpoint *webhooks.WebhookEndpoint) bool {
+		return endpoint.ProfileId == profileId && !endpoint.DeletedAt.Valid
+	}), nil
+}
+
+func (r *Repository) UpdateWebhookEndpoint(
+	_ context.Context,
+	arg webhooks.UpdateWebhookEndpointParams,
+) (*webhooks.WebhookEndpoint, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	endpoint := r.ownedEndpoint(arg.Id, arg.ProfileId)
+	if endpoint == nil {
+		return nil, nil //nolint:nilnil
+	}
+
+	switch {
+	case arg.IsActive && !endpoint.IsActive:
+		endpoint.ConsecutiveFailures = 0
+		endpoint.FailingSince = sql.NullTime{}     //nolint:exhaustruct
+		endpoint.DisabledAt = sql.NullTime{}       //nolint:exhaustruct
+		endpoint.DisabledReason = sql.NullString{} //nolint:exhaustruct
+	case !arg.IsActive && endpoint.IsActive:
+		endpoint.DisabledAt = sql.NullTime{Time: time.Now(), Valid: true}
+		endpoint.DisabledReason = sql.NullString{String: "disabled by the profile", Valid: true}
+	}
+
+	endpoint.Url = arg.Url
+	endpoint.EventTypes = arg.EventTypes
+	endpoint.Description = arg.Description
+	endpoint.IsActive = arg.IsActive
+	endpoint.UpdatedAt = sql.NullTime{Time: time.Now(), Valid: true}
+
+	clone := *endpoint
+
+	return &clone, nil
+}
+
+func (r *Repository) RotateWebhookEndpointSecret(
+	_ context.Context,
+	arg webhooks.RotateWebhookEndpointSecretParams,
+) (*webhooks.WebhookEndpoint, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	endpoint := r.ownedEndpoint(arg.Id, arg.ProfileId)
+	if endpoint == nil {
+		return nil, nil //nolint:nilnil
+	}
+
+	endpoint.Secret = arg.Secret
+	endpoint.UpdatedAt = sql.NullTime{Time: time.Now(), Valid: true}
+
+	clone := *endpoint
+
+	return &clone, nil
+}
+
+func (r *Repository) DeleteWebhookEndpoint(
+	_ context.Context,
+	arg webhooks.DeleteWebhookEndpointParams,
+) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	endpoint := r.ownedEndpoint(arg.Id, arg.ProfileId)
+	if endpoint == nil {
+		return 0, nil
+	}
+
+	endpoint.DeletedAt = sql.NullTime{Time: time.Now(), Valid: true}
+
+	return 1, nil
+}
+
+func (r *Repository) ListActiveWebhookEndpointsForProfileEvent(
+	_ context.Context,
+	arg webhooks.ListActiveWebhookEndpointsForProfileEventParams,
+) ([]*webhooks.WebhookEndpoint, error) {
+	return r.filterEndpoints(func(endpoint *webhooks.WebhookEndpoint) bool {
+		return endpoint.ProfileId == arg.ProfileId && isSubscribed(endpoint, arg.EventType)
+	}), nil
+}
+
+func (r *Repository) ListActiveWebhookEndpointsForQuestionEvent(
+	_ context.Context,
+	arg webhooks.ListActiveWebhookEndpointsForQuestionEventParams,
+) ([]*webhooks.WebhookEndpoint, error) {
+	r.mu.Lock()
+	profileId, ok := r.askers[arg.QuestionId]
+	r.mu.Unlock()
+
+	if !ok {
+		return []*webhooks.WebhookEndpoint{}, nil
+	}
+
+	return r.filterEndpoints(func(endpoint *webhooks.WebhookEndpoint) bool {
+		return endpoint.ProfileId == profileId && isSubscribed(endpoint, arg.EventType)
+	}), nil
+}
+
+func (r *Repository) RecordWebhookEndpointSuccess(_ context.Context, id string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if endpoint, ok := r.endpoints[id]; ok {
+		endpoint.ConsecutiveFailures = 0
+		endpoint.FailingSince = sql.NullTime{} //nolint:exhaustruct
+	}
+
+	return nil
+}
+
+func (r *Repository) RecordWebhookEndpointFailure(
+	_ context.Context,
+	id string,
+) (*webhooks.WebhookEndpoint, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	endpoint, ok := r.endpoints[id]
+	if !ok {
+		return nil, nil //nolint:nilnil
+	}
+
+	endpoint.ConsecutiveFailures++
+
+	if !endpoint.FailingSince.Valid {
+		endpoint.FailingSince = sql.NullTime{Time: time.Now(), Valid: true}
+	}
+
+	clone := *endpoint
+
+	return &clone, nil
+}
+
+func (r *Repository) DisableWebhookEndpoint(_ context.Context, arg webhooks.DisableWebhookEndpointParams) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	endpoint, ok := r.endpoints[arg.Id]
+	if !ok || !endpoint.IsActive {
+		return nil
+	}
+
+	endpoint.IsActive = false
+	endpoint.DisabledAt = sql.NullTime{Time: time.Now(), Valid: true}
+	endpoint.DisabledReason = arg.DisabledReason
+
+	return nil
+}
+
+func (r *Repository) InsertWebhookDelivery(_ context.Context, arg webhooks.InsertWebhookDeliveryParams) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if !arg.ReplayOf.Valid {
+		for _, delivery := range r.deliveries {
+			if delivery.EndpointId == arg.EndpointId && delivery.EventId == arg.EventId && !delivery.ReplayOf.Valid {
+				return nil
+			}
+		}
+	}
+
+	now := time.Now()
+
+	r.deliveries = append(r.deliveries, &webhooks.WebhookDelivery{ //nolint:exhaustruct
+		Id:            arg.Id,
+		EndpointId:    arg.EndpointId,
+		EventId:       arg.EventId,
+		EventType:     arg.EventType,
+		Payload:       arg.Payload,
+		ReplayOf:      arg.ReplayOf,
+		Status:        webhooks.DeliveryStatusPending,
+		NextAttemptAt: now,
+		CreatedAt:     now,
+	})
+
+	return nil
+}
+
+func (r *Repository) ClaimWebhookDeliveries(
+	_ context.Context,
+	arg webhooks.ClaimWebhookDeliveriesParams,
+) ([]*webhooks.WebhookDelivery, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := time.Now()
+	due := []*webhooks.WebhookDelivery{}
+
+	for _, delivery := range r.deliveries {
+		endpoint, ok := r.endpoints[delivery.EndpointId]
+
+		if delivery.Status != webhooks.DeliveryStatusPending ||
+			delivery.NextAttemptAt.After(now) ||
+			!ok || !endpoint.IsActive || endpoint.DeletedAt.Valid {
+			continue
+		}
+
+		due = append(due, delivery)
+	}
+
+	slices.SortStableFunc(due, func(a, b *webhooks.WebhookDelivery) int {
+		return a.NextAttemptAt.Compare(b.NextAttemptAt)
+	})
+
+	claimed := make([]*webhooks.WebhookDelivery, 0, min(len(due), int(arg.LimitCount)))
+
+	for _, delivery := range due[:min(len(due), int(arg.LimitCount))] {
+		delivery.NextAttemptAt = arg.LeaseUntil
+		clone := *delivery
+		claimed = append(claimed, &clone)
+	}
+
+	return claimed, nil
+}
+
+func (r *Repository) GetWebhookDeliveryById(
+	_ context.Context,
+	arg webhooks.GetWebhookDeliveryByIdParams,
+) (*webhooks.WebhookDelivery, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, delivery := range r.deliveries {
+		if delivery.Id == arg.Id && delivery.EndpointId == arg.EndpointId {
+			clone := *delivery
+
+			return &clone, nil
+		}
+	}
+
+	return nil, nil //nolint:nilnil
+}
+
+func (r *Repository) ListWebhookDeliveriesByEndpointId(
+	_ context.Context,
+	arg webhooks.ListWebhookDeliveriesByEndpointIdParams,
+) ([]*webhooks.WebhookDelivery, error) {
+	deliveries := r.Deliveries(arg.EndpointId)
+	slices.Reverse(deliveries)
+
+	start := min(int(arg.OffsetCount), len(deliveries))
+	end := min(start+int(arg.LimitCount), len(deliveries))
+
+	return deliveries[start:end], nil
+}
+
+func (r *Repository) MarkWebhookDeliveryAttempt(_ context.Context, arg webhooks.MarkWebhookDeliveryAttemptParams) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, delivery := range r.deliveries {
+		if delivery.Id != arg.Id {
+			continue
+		}
+
+		delivery.Status = arg.Status
+		delivery.Attempts++
+		delivery.NextAttemptAt = arg.NextAttemptAt
+		delivery.ResponseStatus = arg.ResponseStatus
+		delivery.ResponseBody = arg.ResponseBody
+		delivery.Error = arg.Error
+		delivery.DurationMs = arg.DurationMs
+
+		if arg.Status == webhooks.DeliveryStatusSucceeded {
+			delivery.DeliveredAt = sql.NullTime{Time: time.Now(), Valid: true}
+		}
+	}
+
+	return nil
+}
+
+func (r *Repository) GetWebhookEndpointForDelivery(
+	_ context.Context,
+	id string,
+) (*webhooks.WebhookEndpoint, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	endpoint, ok := r.endpoints[id]
+	if !ok {
+		return nil, nil //nolint:nilnil
+	}
+
+	clone := *endpoint
+
+	return &clone, nil
+}
+
+func (r *Repository) ownedEndpoint(id string, profileId string) *webhooks.WebhookEndpoint {
+	endpoint, ok := r.endpoints[id]
+	if !ok || endpoint.ProfileId != profileId || endpoint.DeletedAt.Valid {
+		return nil
+	}
+
+	return endpoint
+}
+
+func (r *Repository) filterEndpoints(keep func(endpoint *webhooks.WebhookEndpoint) bool) []*webhooks.WebhookEndpoint {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	result := []*webhooks.WebhookEndpoint{}
+
+	for _, endpoint := range r.endpoints {
+		if keep(endpoint) {
+			clone := *endpoint
+			result = append(result, &clone)
+		}
+	}
+
+	slices.SortFunc(result, func(a, b *webhooks.WebhookEndpoint) int {
+		return strings.Compare(a.Id, b.Id)
+	})
+
+	return result
+}
+
+func isSubscribed(endpoint *webhooks.WebhookEndpoint, eventType string) bool {
+	return endpoint.IsActive &&
+		!endpoint.DeletedAt.Valid &&
+		slices.Contains(strings.Split(endpoint.EventTypes, ","), eventType)
+}